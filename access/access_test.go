@@ -0,0 +1,166 @@
+package access_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/access"
+)
+
+var _ = Describe("Access structures", func() {
+	trials := 20
+
+	Context("weighted sharing", func() {
+		It("should assign consecutive indices to each party", func() {
+			indices, err := WeightedIndices([]int{2, 0, 3})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(indices[0]).To(HaveLen(2))
+			Expect(indices[1]).To(HaveLen(0))
+			Expect(indices[2]).To(HaveLen(3))
+
+			expected := uint16(1)
+			for _, inds := range indices {
+				for _, ind := range inds {
+					e := secp256k1.NewFnFromU16(expected)
+					Expect(ind.Eq(&e)).To(BeTrue())
+					expected++
+				}
+			}
+		})
+
+		It("should allow any set of parties with enough weight to reconstruct", func() {
+			for i := 0; i < trials; i++ {
+				weights := make([]int, shamirutil.RandRange(1, 8))
+				total := 0
+				for j := range weights {
+					weights[j] = rand.Intn(4)
+					total += weights[j]
+				}
+				if total == 0 {
+					continue
+				}
+				k := shamirutil.RandRange(1, total)
+				secret := secp256k1.RandomFn()
+
+				partyShares, err := ShareWeighted(weights, secret, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(partyShares).To(HaveLen(len(weights)))
+
+				// Add random parties until there is enough weight.
+				var subset []shamir.Shares
+				weight := 0
+				for _, j := range rand.Perm(len(weights)) {
+					if weight >= k {
+						break
+					}
+					subset = append(subset, partyShares[j])
+					weight += weights[j]
+				}
+
+				recon, err := OpenWeighted(subset, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an error when there is not enough weight", func() {
+			partyShares, err := ShareWeighted([]int{1, 2, 3}, secp256k1.RandomFn(), 4)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = OpenWeighted(partyShares[1:2], 4)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return errors for invalid parameters", func() {
+			_, err := ShareWeighted([]int{1, -1}, secp256k1.RandomFn(), 1)
+			Expect(err).To(HaveOccurred())
+			_, err = ShareWeighted([]int{1, 2}, secp256k1.RandomFn(), 4)
+			Expect(err).To(HaveOccurred())
+			_, err = ShareWeighted([]int{1, 2}, secp256k1.RandomFn(), 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("hierarchical sharing", func() {
+		// One executive is required along with at least four parties in
+		// total.
+		h := Hierarchy{Thresholds: []int{1, 4}}
+		levels := []int{1, 0, 1, 1, 0, 1, 1}
+
+		It("should determine which sets are qualified", func() {
+			Expect(h.Qualified([]int{0, 1, 1, 1})).To(BeTrue())
+			Expect(h.Qualified([]int{0, 0, 1, 1})).To(BeTrue())
+			Expect(h.Qualified([]int{1, 1, 1, 1, 1})).To(BeFalse())
+			Expect(h.Qualified([]int{0, 0, 0})).To(BeFalse())
+			Expect(h.Qualified([]int{0, 2, 1, 1})).To(BeFalse())
+		})
+
+		It("should allow qualified sets to reconstruct", func() {
+			for i := 0; i < trials; i++ {
+				secret := secp256k1.RandomFn()
+				shares, err := ShareHierarchical(h, levels, secret)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Random subsets until a qualified one is found.
+				var subset []HierarchicalShare
+				for {
+					subset = subset[:0]
+					subsetLevels := []int{}
+					for _, j := range rand.Perm(len(shares))[:shamirutil.RandRange(4, len(shares))] {
+						subset = append(subset, shares[j])
+						subsetLevels = append(subsetLevels, shares[j].Level)
+					}
+					if h.Qualified(subsetLevels) {
+						break
+					}
+				}
+
+				recon, err := OpenHierarchical(h, subset)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should reconstruct with three levels", func() {
+			h3 := Hierarchy{Thresholds: []int{1, 3, 5}}
+			levels3 := []int{0, 0, 1, 1, 1, 2, 2, 2}
+			for i := 0; i < trials; i++ {
+				secret := secp256k1.RandomFn()
+				shares, err := ShareHierarchical(h3, levels3, secret)
+				Expect(err).ToNot(HaveOccurred())
+
+				subset := []HierarchicalShare{shares[0], shares[2], shares[3], shares[5], shares[7]}
+				recon, err := OpenHierarchical(h3, subset)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should reject unqualified sets", func() {
+			shares, err := ShareHierarchical(h, levels, secp256k1.RandomFn())
+			Expect(err).ToNot(HaveOccurred())
+
+			var juniors []HierarchicalShare
+			for _, share := range shares {
+				if share.Level == 1 {
+					juniors = append(juniors, share)
+				}
+			}
+			_, err = OpenHierarchical(h, juniors)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return errors for invalid parameters", func() {
+			_, err := ShareHierarchical(Hierarchy{Thresholds: []int{2, 2}}, levels, secp256k1.RandomFn())
+			Expect(err).To(HaveOccurred())
+			_, err = ShareHierarchical(h, []int{0, 3, 1, 1}, secp256k1.RandomFn())
+			Expect(err).To(HaveOccurred())
+			_, err = ShareHierarchical(h, []int{1, 1, 1, 1}, secp256k1.RandomFn())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,202 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// A Hierarchy describes a conjunctive hierarchical access structure, as
+// defined by Tassa. Parties are assigned to levels 0, 1, ..., where level 0 is
+// the most senior. The thresholds are strictly increasing, and a set of
+// parties is qualified when, for every level l, it contains at least
+// Thresholds[l] parties from levels 0 through l. For example, thresholds
+// {1, 4} require at least one party from level 0 and at least four parties in
+// total, so one executive together with three engineers is qualified, but
+// four engineers are not.
+type Hierarchy struct {
+	Thresholds []int
+}
+
+// K returns the overall threshold of the hierarchy, which is the degree of the
+// sharing polynomial plus one.
+func (h Hierarchy) K() int {
+	return h.Thresholds[len(h.Thresholds)-1]
+}
+
+// Validate returns an error if the thresholds are not positive and strictly
+// increasing.
+func (h Hierarchy) Validate() error {
+	if len(h.Thresholds) == 0 {
+		return errors.New("hierarchy must have at least one level")
+	}
+	prev := 0
+	for l, k := range h.Thresholds {
+		if k <= prev {
+			return fmt.Errorf("thresholds must be positive and strictly increasing: level %v has threshold %v", l, k)
+		}
+		prev = k
+	}
+	return nil
+}
+
+// Qualified returns true if a set of parties with the given levels is
+// qualified in the hierarchy.
+func (h Hierarchy) Qualified(levels []int) bool {
+	counts := make([]int, len(h.Thresholds))
+	for _, l := range levels {
+		if l < 0 || l >= len(counts) {
+			return false
+		}
+		counts[l]++
+	}
+	total := 0
+	for l, k := range h.Thresholds {
+		total += counts[l]
+		if total < k {
+			return false
+		}
+	}
+	return true
+}
+
+// The order of the derivative of the sharing polynomial that is given to
+// parties in the given level.
+func (h Hierarchy) order(level int) int {
+	if level == 0 {
+		return 0
+	}
+	return h.Thresholds[level-1]
+}
+
+// A HierarchicalShare is a share in a hierarchical sharing. The value is the
+// derivative of the sharing polynomial of order equal to the threshold of the
+// level above, evaluated at the index.
+type HierarchicalShare struct {
+	Share shamir.Share
+	Level int
+}
+
+// ShareHierarchical creates a sharing of the given secret for parties with the
+// given levels in the hierarchy. The returned shares are in the same order as
+// the levels. Indices are assigned to parties in order of seniority, which
+// ensures that the reconstruction for qualified sets is well defined with
+// overwhelming probability.
+func ShareHierarchical(h Hierarchy, levels []int, secret secp256k1.Fn) ([]HierarchicalShare, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	if len(levels) > 0xffff {
+		return nil, fmt.Errorf("too many parties: expected at most %v, got %v", 0xffff, len(levels))
+	}
+	for i, l := range levels {
+		if l < 0 || l >= len(h.Thresholds) {
+			return nil, fmt.Errorf("invalid level for party %v: expected 0 <= l < %v, got l = %v", i, len(h.Thresholds), l)
+		}
+	}
+	if !h.Qualified(levels) {
+		return nil, errors.New("the set of all parties is not qualified")
+	}
+
+	// Order the parties by level so that more senior parties get smaller
+	// indices.
+	order := make([]int, len(levels))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return levels[order[i]] < levels[order[j]] })
+
+	k := h.K()
+	coeffs := make([]secp256k1.Fn, k)
+	defer shamir.WipeFns(coeffs)
+	coeffs[0] = secret
+	for i := 1; i < k; i++ {
+		var err error
+		if coeffs[i], err = shamir.RandomFn(); err != nil {
+			return nil, err
+		}
+	}
+
+	shares := make([]HierarchicalShare, len(levels))
+	for pos, i := range order {
+		var index secp256k1.Fn
+		index.SetU16(uint16(pos + 1))
+		shares[i] = HierarchicalShare{
+			Share: shamir.NewShare(index, evalDerivative(coeffs, h.order(levels[i]), &index)),
+			Level: levels[i],
+		}
+	}
+	return shares, nil
+}
+
+// OpenHierarchical reconstructs the secret from the given hierarchical
+// shares. An error is returned if the shares do not come from a qualified set
+// of parties, or if the interpolation problem does not have a unique solution.
+func OpenHierarchical(h Hierarchy, shares []HierarchicalShare) (secp256k1.Fn, error) {
+	if err := h.Validate(); err != nil {
+		return secp256k1.Fn{}, err
+	}
+	levels := make([]int, len(shares))
+	for i := range shares {
+		levels[i] = shares[i].Level
+	}
+	if !h.Qualified(levels) {
+		return secp256k1.Fn{}, errors.New("shares are not from a qualified set of parties")
+	}
+
+	// Each share gives a linear equation in the coefficients of the sharing
+	// polynomial, whose entries are the derivatives of the monomials.
+	k := h.K()
+	rows := make([][]secp256k1.Fn, len(shares))
+	for i := range shares {
+		rows[i] = make([]secp256k1.Fn, k+1)
+		d := h.order(shares[i].Level)
+		var pow secp256k1.Fn
+		pow.SetU16(1)
+		for j := d; j < k; j++ {
+			rows[i][j] = fallingFactorial(j, d)
+			rows[i][j].Mul(&rows[i][j], &pow)
+			pow.Mul(&pow, &shares[i].Share.Index)
+		}
+		rows[i][k] = shares[i].Share.Value
+	}
+	defer func() {
+		for i := range rows {
+			shamir.WipeFns(rows[i])
+		}
+	}()
+
+	coeffs, ok := solve(rows, k)
+	if !ok {
+		return secp256k1.Fn{}, errors.New("birkhoff interpolation does not have a unique solution")
+	}
+	defer shamir.WipeFns(coeffs)
+	return coeffs[0], nil
+}
+
+// Evaluates the derivative of order d of the polynomial with the given
+// coefficients at x.
+func evalDerivative(coeffs []secp256k1.Fn, d int, x *secp256k1.Fn) secp256k1.Fn {
+	var res, term secp256k1.Fn
+	for j := len(coeffs) - 1; j >= d; j-- {
+		term = fallingFactorial(j, d)
+		term.Mul(&term, &coeffs[j])
+		res.Mul(&res, x)
+		res.Add(&res, &term)
+	}
+	return res
+}
+
+// Returns j(j-1)...(j-d+1) as a field element.
+func fallingFactorial(j, d int) secp256k1.Fn {
+	var res, tmp secp256k1.Fn
+	res.SetU16(1)
+	for i := 0; i < d; i++ {
+		tmp.SetU16(uint16(j - i))
+		res.Mul(&res, &tmp)
+	}
+	return res
+}
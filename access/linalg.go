@@ -0,0 +1,63 @@
+package access
+
+import (
+	"github.com/renproject/secp256k1"
+)
+
+// Solves the linear system whose augmented matrix is given by rows, where
+// each row has length cols+1 and the last entry is the right hand side. The
+// matrix is reduced in place. If the system has a unique solution it is
+// returned along with true, otherwise false is returned. Rows in excess of the
+// number of columns are permitted, but must be consistent with the others.
+func solve(rows [][]secp256k1.Fn, cols int) ([]secp256k1.Fn, bool) {
+	var inv, tmp secp256k1.Fn
+	pivotRow := 0
+	for col := 0; col < cols; col++ {
+		// Find a row with a non-zero entry in this column.
+		pivot := -1
+		for r := pivotRow; r < len(rows); r++ {
+			if !rows[r][col].IsZero() {
+				pivot = r
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, false
+		}
+		rows[pivotRow], rows[pivot] = rows[pivot], rows[pivotRow]
+
+		// Normalise the pivot row.
+		inv.Inverse(&rows[pivotRow][col])
+		for c := col; c <= cols; c++ {
+			rows[pivotRow][c].Mul(&rows[pivotRow][c], &inv)
+		}
+
+		// Eliminate the column from all other rows.
+		for r := range rows {
+			if r == pivotRow || rows[r][col].IsZero() {
+				continue
+			}
+			factor := rows[r][col]
+			factor.Negate(&factor)
+			for c := col; c <= cols; c++ {
+				tmp.Mul(&factor, &rows[pivotRow][c])
+				rows[r][c].Add(&rows[r][c], &tmp)
+			}
+		}
+		pivotRow++
+	}
+
+	// Any remaining rows must now be entirely zero, otherwise the system is
+	// inconsistent.
+	for r := pivotRow; r < len(rows); r++ {
+		if !rows[r][cols].IsZero() {
+			return nil, false
+		}
+	}
+
+	solution := make([]secp256k1.Fn, cols)
+	for i := range solution {
+		solution[i] = rows[i][cols]
+	}
+	return solution, true
+}
@@ -0,0 +1,357 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// A Policy is a monotone boolean formula over named parties, built from AND,
+// OR and threshold gates. A policy is written using the functions and(...),
+// or(...) and thresh(t, ...), whose arguments are either party names or
+// other gates. For example,
+//
+//	or(thresh(2, alice, bob, carol), and(alice, dave))
+//
+// is satisfied by any two of alice, bob and carol, or by alice and dave
+// together. A party may appear more than once in a policy, in which case it
+// receives one share for each occurrence.
+//
+// Sharing for a policy uses the standard construction of a linear secret
+// sharing scheme from a threshold formula: the secret is shared at the root
+// gate using Shamir sharing with the gate's threshold, and each share is
+// recursively shared at the corresponding child gate. AND and OR gates are
+// threshold gates with thresholds equal to the number of children and one
+// respectively. This is equivalent to the monotone span program for the
+// formula.
+type Policy struct {
+	root   *policyNode
+	leaves int
+}
+
+type policyNode struct {
+	// Party is only set for leaves, and leaf is the position of the leaf in a
+	// left to right traversal of the formula.
+	party string
+	leaf  int
+
+	threshold int
+	children  []*policyNode
+}
+
+// A PolicyShare is the share for a single occurrence of a party in a policy.
+// Leaf identifies the occurrence, and is the position of the party name in a
+// left to right reading of the policy.
+type PolicyShare struct {
+	Party string
+	Leaf  int
+	Value secp256k1.Fn
+}
+
+// ParsePolicy parses a policy from its string representation. Gate names are
+// case insensitive, and party names may contain ASCII letters, digits and the
+// characters '_', '-', '.' and '@'.
+func ParsePolicy(str string) (*Policy, error) {
+	p := &policyParser{input: str}
+	p.next()
+	policy := &Policy{}
+	root, err := p.parseExpr(policy)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != "" {
+		return nil, fmt.Errorf("unexpected %q at position %v", p.tok, p.tokPos)
+	}
+	policy.root = root
+	return policy, nil
+}
+
+// String returns the canonical string representation of the policy, which
+// can be parsed again with ParsePolicy.
+func (p *Policy) String() string {
+	var b strings.Builder
+	p.root.writeTo(&b)
+	return b.String()
+}
+
+func (node *policyNode) writeTo(b *strings.Builder) {
+	if node.children == nil {
+		b.WriteString(node.party)
+		return
+	}
+	switch node.threshold {
+	case len(node.children):
+		b.WriteString("and(")
+	case 1:
+		b.WriteString("or(")
+	default:
+		fmt.Fprintf(b, "thresh(%v, ", node.threshold)
+	}
+	for i, child := range node.children {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		child.writeTo(b)
+	}
+	b.WriteString(")")
+}
+
+// Parties returns the sorted list of distinct parties in the policy.
+func (p *Policy) Parties() []string {
+	seen := map[string]bool{}
+	p.root.walkLeaves(func(leaf *policyNode) { seen[leaf.party] = true })
+	parties := make([]string, 0, len(seen))
+	for party := range seen {
+		parties = append(parties, party)
+	}
+	sort.Strings(parties)
+	return parties
+}
+
+// Satisfied returns true if the given set of parties satisfies the policy.
+func (p *Policy) Satisfied(parties []string) bool {
+	set := map[string]bool{}
+	for _, party := range parties {
+		set[party] = true
+	}
+	return p.root.satisfied(set)
+}
+
+func (node *policyNode) satisfied(set map[string]bool) bool {
+	if node.children == nil {
+		return set[node.party]
+	}
+	count := 0
+	for _, child := range node.children {
+		if child.satisfied(set) {
+			count++
+			if count == node.threshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (node *policyNode) walkLeaves(f func(leaf *policyNode)) {
+	if node.children == nil {
+		f(node)
+		return
+	}
+	for _, child := range node.children {
+		child.walkLeaves(f)
+	}
+}
+
+// ShareForPolicy creates a sharing of the given secret for the policy. One
+// share is returned for every occurrence of a party in the policy, ordered by
+// leaf.
+func ShareForPolicy(p *Policy, secret secp256k1.Fn) ([]PolicyShare, error) {
+	shares := make([]PolicyShare, p.leaves)
+	if err := p.root.share(secret, shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (node *policyNode) share(value secp256k1.Fn, dst []PolicyShare) error {
+	if node.children == nil {
+		dst[node.leaf] = PolicyShare{Party: node.party, Leaf: node.leaf, Value: value}
+		return nil
+	}
+
+	childShares := make(shamir.Shares, len(node.children))
+	defer childShares.Zero()
+	if err := shamir.ShareSecret(&childShares, childIndices(len(node.children)), value, node.threshold); err != nil {
+		return err
+	}
+	for i, child := range node.children {
+		if err := child.share(childShares[i].Value, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenPolicy reconstructs the secret from the given shares. An error is
+// returned if the parties that the shares belong to do not satisfy the
+// policy. Shares for leaves that do not exist in the policy, or whose party
+// does not match the leaf, are ignored.
+func OpenPolicy(p *Policy, shares []PolicyShare) (secp256k1.Fn, error) {
+	byLeaf := map[int]secp256k1.Fn{}
+	leafParty := make([]string, p.leaves)
+	p.root.walkLeaves(func(leaf *policyNode) { leafParty[leaf.leaf] = leaf.party })
+	for _, share := range shares {
+		if share.Leaf < 0 || share.Leaf >= p.leaves || leafParty[share.Leaf] != share.Party {
+			continue
+		}
+		byLeaf[share.Leaf] = share.Value
+	}
+
+	secret, ok := p.root.open(byLeaf)
+	if !ok {
+		return secp256k1.Fn{}, errors.New("shares do not satisfy the policy")
+	}
+	return secret, nil
+}
+
+func (node *policyNode) open(byLeaf map[int]secp256k1.Fn) (secp256k1.Fn, bool) {
+	if node.children == nil {
+		value, ok := byLeaf[node.leaf]
+		return value, ok
+	}
+
+	indices := childIndices(len(node.children))
+	shares := make(shamir.Shares, 0, node.threshold)
+	defer shares.Zero()
+	for i, child := range node.children {
+		value, ok := child.open(byLeaf)
+		if !ok {
+			continue
+		}
+		shares = append(shares, shamir.NewShare(indices[i], value))
+		if len(shares) == node.threshold {
+			return shamir.Open(shares), true
+		}
+	}
+	return secp256k1.Fn{}, false
+}
+
+// The Shamir indices used for the children of a gate, which are 1, 2, ....
+func childIndices(n int) []secp256k1.Fn {
+	indices := make([]secp256k1.Fn, n)
+	for i := range indices {
+		indices[i].SetU16(uint16(i + 1))
+	}
+	return indices
+}
+
+type policyParser struct {
+	input  string
+	pos    int
+	tok    string
+	tokPos int
+}
+
+func isPartyRune(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("_-.@", r)
+}
+
+// Advances to the next token. The token is empty at the end of the input.
+func (p *policyParser) next() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	p.tokPos = p.pos
+	if p.pos == len(p.input) {
+		p.tok = ""
+		return
+	}
+	if strings.ContainsRune("(),", rune(p.input[p.pos])) {
+		p.tok = p.input[p.pos : p.pos+1]
+		p.pos++
+		return
+	}
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := rune(p.input[p.pos])
+		if !isPartyRune(r) {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		// Consume the invalid character so that it is reported.
+		p.pos++
+	}
+	p.tok = p.input[start:p.pos]
+}
+
+func (p *policyParser) expect(tok string) error {
+	if p.tok != tok {
+		if p.tok == "" {
+			return fmt.Errorf("expected %q but reached the end of the policy", tok)
+		}
+		return fmt.Errorf("expected %q at position %v, got %q", tok, p.tokPos, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+func (p *policyParser) parseExpr(policy *Policy) (*policyNode, error) {
+	name, pos := p.tok, p.tokPos
+	if name == "" {
+		return nil, errors.New("unexpected end of the policy")
+	}
+	for _, r := range name {
+		if !isPartyRune(r) {
+			return nil, fmt.Errorf("unexpected %q at position %v", name, pos)
+		}
+	}
+	p.next()
+
+	// A name that is not followed by an opening parenthesis is a party.
+	if p.tok != "(" {
+		node := &policyNode{party: name, leaf: policy.leaves}
+		policy.leaves++
+		return node, nil
+	}
+	p.next()
+
+	threshold := 0
+	switch strings.ToLower(name) {
+	case "and", "or":
+	case "thresh", "threshold":
+		t, err := strconv.Atoi(p.tok)
+		if err != nil {
+			return nil, fmt.Errorf("expected a threshold at position %v, got %q", p.tokPos, p.tok)
+		}
+		threshold = t
+		p.next()
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown gate %q at position %v", name, pos)
+	}
+
+	node := &policyNode{}
+	for {
+		child, err := p.parseExpr(policy)
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+		if p.tok != "," {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(name) {
+	case "and":
+		node.threshold = len(node.children)
+	case "or":
+		node.threshold = 1
+	default:
+		node.threshold = threshold
+	}
+	if node.threshold < 1 || node.threshold > len(node.children) {
+		return nil, fmt.Errorf(
+			"invalid threshold for gate at position %v: expected 1 <= t <= %v, got t = %v",
+			pos, len(node.children), node.threshold,
+		)
+	}
+	if len(node.children) > 0xffff {
+		return nil, fmt.Errorf("too many children for gate at position %v", pos)
+	}
+	return node, nil
+}
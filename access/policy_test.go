@@ -0,0 +1,118 @@
+package access_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/access"
+)
+
+var _ = Describe("Policies", func() {
+	trials := 20
+
+	Context("parsing", func() {
+		It("should parse and print policies", func() {
+			policy, err := ParsePolicy("OR( thresh(2, alice, bob, carol),and(alice,dave) )")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.String()).To(Equal("or(thresh(2, alice, bob, carol), and(alice, dave))"))
+			Expect(policy.Parties()).To(Equal([]string{"alice", "bob", "carol", "dave"}))
+
+			reparsed, err := ParsePolicy(policy.String())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reparsed.String()).To(Equal(policy.String()))
+		})
+
+		It("should parse a single party", func() {
+			policy, err := ParsePolicy("alice@example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Parties()).To(Equal([]string{"alice@example.com"}))
+		})
+
+		It("should reject invalid policies", func() {
+			for _, str := range []string{
+				"",
+				"and(",
+				"and()",
+				"and(alice,)",
+				"and(alice bob)",
+				"xor(alice, bob)",
+				"thresh(alice, bob)",
+				"thresh(3, alice, bob)",
+				"thresh(0, alice, bob)",
+				"alice)",
+				"and(alice, bob) carol",
+				"al!ce",
+			} {
+				_, err := ParsePolicy(str)
+				Expect(err).To(HaveOccurred(), str)
+			}
+		})
+	})
+
+	Context("evaluation", func() {
+		policy, err := ParsePolicy("or(and(exec1, exec2), and(or(exec1, exec2), thresh(3, eng1, eng2, eng3, eng4)))")
+		Expect(err).ToNot(HaveOccurred())
+
+		It("should determine which sets satisfy the policy", func() {
+			Expect(policy.Satisfied([]string{"exec1", "exec2"})).To(BeTrue())
+			Expect(policy.Satisfied([]string{"exec2", "eng1", "eng3", "eng4"})).To(BeTrue())
+			Expect(policy.Satisfied([]string{"exec1", "eng1", "eng2"})).To(BeFalse())
+			Expect(policy.Satisfied([]string{"eng1", "eng2", "eng3", "eng4"})).To(BeFalse())
+		})
+
+		It("should allow satisfying sets to reconstruct the secret", func() {
+			parties := policy.Parties()
+			for i := 0; i < trials; i++ {
+				secret := secp256k1.RandomFn()
+				shares, err := ShareForPolicy(policy, secret)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(shares).To(HaveLen(8))
+
+				// Pick random sets of parties and check that reconstruction
+				// succeeds exactly when the policy is satisfied.
+				perm := rand.Perm(len(parties))
+				subset := []string{}
+				for _, j := range perm[:rand.Intn(len(parties)+1)] {
+					subset = append(subset, parties[j])
+				}
+				inSubset := map[string]bool{}
+				for _, party := range subset {
+					inSubset[party] = true
+				}
+				var held []PolicyShare
+				for _, share := range shares {
+					if inSubset[share.Party] {
+						held = append(held, share)
+					}
+				}
+
+				recon, err := OpenPolicy(policy, held)
+				if policy.Satisfied(subset) {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recon.Eq(&secret)).To(BeTrue())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			}
+		})
+
+		It("should ignore shares that do not match the policy", func() {
+			secret := secp256k1.RandomFn()
+			shares, err := ShareForPolicy(policy, secret)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Relabel the executive shares so that they belong to the wrong
+			// party.
+			for i := range shares {
+				if shares[i].Party == "exec1" || shares[i].Party == "exec2" {
+					shares[i].Party = "mallory"
+				}
+			}
+			_, err = OpenPolicy(policy, shares)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
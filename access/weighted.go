@@ -0,0 +1,89 @@
+// Package access implements secret sharing for access structures that are
+// more general than a single threshold over equally trusted parties. Weighted
+// sharing gives each party a number of shares proportional to its weight,
+// and hierarchical sharing (Tassa's scheme based on Birkhoff interpolation)
+// requires a minimum number of parties from the more senior levels of a
+// hierarchy.
+package access
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// WeightedIndices returns the share indices for each party in a weighted
+// sharing with the given weights. Party i holds weights[i] shares at
+// consecutive indices, with the first party starting at index 1. That is, the
+// indices for party i are offset+1, ..., offset+weights[i], where offset is
+// the sum of the weights of the parties before it.
+func WeightedIndices(weights []int) ([][]secp256k1.Fn, error) {
+	total := 0
+	for i, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("invalid weight for party %v: expected w >= 0, got w = %v", i, w)
+		}
+		total += w
+	}
+	if total > 0xffff {
+		return nil, fmt.Errorf("total weight too large: expected at most %v, got %v", 0xffff, total)
+	}
+
+	indices := make([][]secp256k1.Fn, len(weights))
+	next := 1
+	for i, w := range weights {
+		indices[i] = make([]secp256k1.Fn, w)
+		for j := range indices[i] {
+			indices[i][j].SetU16(uint16(next))
+			next++
+		}
+	}
+	return indices, nil
+}
+
+// ShareWeighted creates a sharing of the given secret in which party i
+// receives weights[i] shares, and any set of parties whose weights sum to at
+// least k can reconstruct the secret. The returned slice contains the shares
+// for each party, at the indices given by WeightedIndices.
+func ShareWeighted(weights []int, secret secp256k1.Fn, k int) ([]shamir.Shares, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("%w: expected k >= 1, got k = %v", shamir.ErrInvalidThreshold, k)
+	}
+	indices, err := WeightedIndices(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	var flat []secp256k1.Fn
+	for _, inds := range indices {
+		flat = append(flat, inds...)
+	}
+
+	shares := make(shamir.Shares, len(flat))
+	if err := shamir.ShareSecret(&shares, flat, secret, k); err != nil {
+		return nil, err
+	}
+
+	partyShares := make([]shamir.Shares, len(weights))
+	offset := 0
+	for i, w := range weights {
+		partyShares[i] = shares[offset : offset+w : offset+w]
+		offset += w
+	}
+	return partyShares, nil
+}
+
+// OpenWeighted reconstructs the secret from the shares held by a set of
+// parties in a weighted sharing with threshold k. An error is returned if the
+// combined weight of the parties is less than k.
+func OpenWeighted(partyShares []shamir.Shares, k int) (secp256k1.Fn, error) {
+	var shares shamir.Shares
+	for _, s := range partyShares {
+		shares = append(shares, s...)
+	}
+	if len(shares) < k {
+		return secp256k1.Fn{}, fmt.Errorf("insufficient weight: expected at least %v, got %v", k, len(shares))
+	}
+	return shamir.Open(shares[:k]), nil
+}
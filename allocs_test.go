@@ -0,0 +1,95 @@
+//go:build !race
+// +build !race
+
+// The race detector adds allocations of its own, and makes sync.Pool drop
+// pooled items at random, so allocation counts are only checked without it.
+
+package shamir_test
+
+import (
+	"testing"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Allocations", func() {
+	n, k := 20, 7
+	runs := 10
+
+	indices := RandomIndices(n)
+	h := PedersenH()
+	secret := secp256k1.RandomFn()
+
+	It("should not allocate when sharing with scratch space", func() {
+		var s Scratch
+		shares := make(Shares, n)
+		Expect(ShareSecretWithScratch(&shares, indices, secret, k, &s)).To(Succeed())
+		allocs := testing.AllocsPerRun(runs, func() {
+			_ = ShareSecretWithScratch(&shares, indices, secret, k, &s)
+		})
+		Expect(allocs).To(BeZero())
+		recon := Open(shares)
+		Expect(recon.Eq(&secret)).To(BeTrue())
+	})
+
+	It("should not allocate when verifiably sharing with scratch space", func() {
+		var s Scratch
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecretWithScratch(&vshares, &c, indices, h, secret, k, &s)).To(Succeed())
+		// The only allocations are made by the scalar multiplications of h.
+		allocs := testing.AllocsPerRun(runs, func() {
+			_ = VShareSecretWithScratch(&vshares, &c, indices, h, secret, k, &s)
+		})
+		Expect(allocs).To(BeNumerically("<=", k))
+		for i := range vshares {
+			Expect(IsValid(h, &c, &vshares[i])).To(BeTrue())
+		}
+	})
+
+	It("should not allocate when opening with scratch space", func() {
+		var s Scratch
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+		shares := vshares.Shares()
+
+		allocs := testing.AllocsPerRun(runs, func() {
+			_ = OpenWithScratch(shares, &s)
+		})
+		Expect(allocs).To(BeZero())
+		allocs = testing.AllocsPerRun(runs, func() {
+			_ = OpenVSharesWithScratch(vshares, &s)
+		})
+		Expect(allocs).To(BeZero())
+
+		recon := OpenWithScratch(shares, &s)
+		Expect(recon.Eq(&secret)).To(BeTrue())
+		recon = OpenVSharesWithScratch(vshares, &s)
+		Expect(recon.Eq(&secret)).To(BeTrue())
+	})
+
+	It("should reuse pooled scratch space in the other functions", func() {
+		shares := make(Shares, n)
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+
+		// The pool may occasionally be emptied by the garbage collector, so
+		// allow for some allocations on average.
+		Expect(testing.AllocsPerRun(runs, func() {
+			_ = ShareSecret(&shares, indices, secret, k)
+		})).To(BeNumerically("<", 1))
+		Expect(testing.AllocsPerRun(runs, func() {
+			_ = VShareSecret(&vshares, &c, indices, h, secret, k)
+		})).To(BeNumerically("<", k+1))
+		Expect(testing.AllocsPerRun(runs, func() {
+			_ = Open(shares)
+		})).To(BeNumerically("<", 1))
+	})
+})
@@ -0,0 +1,178 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/fnbatch"
+)
+
+// AddInPlace adds each of the other shares to the share in the caller at the
+// same position, as for Share.Add. An error is returned, and the caller is not
+// modified, if the two slices have different lengths or if the shares at any
+// position have different indices.
+func (shares Shares) AddInPlace(other Shares) error {
+	if err := checkAligned(len(shares), len(other), func(i int) bool {
+		return shares[i].IndexEq(&other[i].Index)
+	}); err != nil {
+		return err
+	}
+	for i := range shares {
+		shares[i].Value.Add(&shares[i].Value, &other[i].Value)
+	}
+	return nil
+}
+
+// AddConstantInPlace adds the constant to each of the shares, as for
+// Share.AddConstant.
+func (shares Shares) AddConstantInPlace(c *secp256k1.Fn) {
+	for i := range shares {
+		shares[i].Value.Add(&shares[i].Value, c)
+	}
+}
+
+// ScaleInPlace multiplies each of the shares by the scale, as for Share.Scale.
+func (shares Shares) ScaleInPlace(scale *secp256k1.Fn) {
+	for i := range shares {
+		shares[i].Value.Mul(&shares[i].Value, scale)
+	}
+}
+
+// AddInPlace adds each of the other verifiable shares to the verifiable share
+// in the caller at the same position, as for VerifiableShare.Add. An error is
+// returned, and the caller is not modified, if the two slices have different
+// lengths or if the shares at any position have different indices.
+func (vshares VerifiableShares) AddInPlace(other VerifiableShares) error {
+	if err := checkAligned(len(vshares), len(other), func(i int) bool {
+		return vshares[i].Share.IndexEq(&other[i].Share.Index)
+	}); err != nil {
+		return err
+	}
+	for i := range vshares {
+		vshares[i].Share.Value.Add(&vshares[i].Share.Value, &other[i].Share.Value)
+		vshares[i].Decommitment.Add(&vshares[i].Decommitment, &other[i].Decommitment)
+	}
+	return nil
+}
+
+// AddConstantInPlace adds the constant to each of the verifiable shares, as
+// for VerifiableShare.AddConstant.
+func (vshares VerifiableShares) AddConstantInPlace(c *secp256k1.Fn) {
+	for i := range vshares {
+		vshares[i].Share.Value.Add(&vshares[i].Share.Value, c)
+	}
+}
+
+// ScaleInPlace multiplies each of the verifiable shares by the scale, as for
+// VerifiableShare.Scale.
+func (vshares VerifiableShares) ScaleInPlace(scale *secp256k1.Fn) {
+	for i := range vshares {
+		vshares[i].Share.Value.Mul(&vshares[i].Share.Value, scale)
+		vshares[i].Decommitment.Mul(&vshares[i].Decommitment, scale)
+	}
+}
+
+// AlignedShares holds the shares of several sharings that have the same
+// indices in the same order. The alignment is checked once when the shares
+// are added, so that linear combinations of the sharings need no further
+// checks of the indices, unlike repeated calls to AddInPlace, which check
+// every index of every pair of sharings that they add.
+type AlignedShares struct {
+	indices []secp256k1.Fn
+	// The values of the shares by position, so that values[i][j] is the
+	// value of the i-th share of the j-th sharing.
+	values [][]secp256k1.Fn
+}
+
+// NewAlignedShares returns the aligned shares of the given sharings. An error
+// is returned if no sharings are given, or if any of them has a different
+// length or a different index at any position than the first.
+func NewAlignedShares(sharings ...Shares) (AlignedShares, error) {
+	if len(sharings) == 0 {
+		return AlignedShares{}, fmt.Errorf("no sharings")
+	}
+	first := sharings[0]
+	for j := 1; j < len(sharings); j++ {
+		other := sharings[j]
+		if err := checkAligned(len(first), len(other), func(i int) bool {
+			return first[i].IndexEq(&other[i].Index)
+		}); err != nil {
+			return AlignedShares{}, fmt.Errorf("sharing %v: %w", j, err)
+		}
+	}
+
+	n, m := len(first), len(sharings)
+	indices := make([]secp256k1.Fn, n)
+	values := make([][]secp256k1.Fn, n)
+	backing := make([]secp256k1.Fn, n*m)
+	for i := range first {
+		indices[i] = first[i].Index
+		values[i] = backing[i*m : (i+1)*m : (i+1)*m]
+		for j := range sharings {
+			values[i][j] = sharings[j][i].Value
+		}
+	}
+	return AlignedShares{indices: indices, values: values}, nil
+}
+
+// NumSharings returns the number of sharings.
+func (a *AlignedShares) NumSharings() int {
+	if len(a.values) == 0 {
+		return 0
+	}
+	return len(a.values[0])
+}
+
+// NumShares returns the number of shares in each sharing.
+func (a *AlignedShares) NumShares() int { return len(a.indices) }
+
+// WeightedSum stores in dst the shares of the linear combination of the
+// sharings with the given weights, so that the i-th share of dst is the sum
+// of the i-th shares of the sharings multiplied by their weights. This is the
+// same as scaling and adding the sharings with ScaleInPlace and AddInPlace,
+// but the indices are not checked again. An error is returned if the number
+// of weights is not the number of sharings.
+//
+// Panics: This function will panic if the destination shares slice has a
+// capacity less than the number of shares.
+func (a *AlignedShares) WeightedSum(dst *Shares, weights []secp256k1.Fn) error {
+	if len(weights) != a.NumSharings() {
+		return fmt.Errorf("expected %v weights, got %v", a.NumSharings(), len(weights))
+	}
+	*dst = (*dst)[:len(a.indices)]
+	for i := range a.indices {
+		(*dst)[i].Index = a.indices[i]
+		(*dst)[i].Value = fnbatch.InnerProduct(weights, a.values[i])
+	}
+	return nil
+}
+
+// Sharing returns a copy of the shares of the j-th sharing.
+func (a *AlignedShares) Sharing(j int) Shares {
+	shares := make(Shares, len(a.indices))
+	for i := range a.indices {
+		shares[i] = NewShare(a.indices[i], a.values[i][j])
+	}
+	return shares
+}
+
+// Zero sets the values of all of the shares to zero.
+func (a *AlignedShares) Zero() {
+	for i := range a.values {
+		WipeFns(a.values[i])
+	}
+}
+
+// Checks that two slices of shares have the same length n and that, for every
+// position i, indexEq(i) is true.
+func checkAligned(n, m int, indexEq func(i int) bool) error {
+	if n != m {
+		return fmt.Errorf("different numbers of shares: %v and %v", n, m)
+	}
+	for i := 0; i < n; i++ {
+		if !indexEq(i) {
+			return fmt.Errorf("shares at position %v have different indices", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package shamir_test
+
+import (
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Slice arithmetic", func() {
+	trials := 20
+	n, k := 10, 4
+	h := secp256k1.RandomPoint()
+
+	share := func(indices []secp256k1.Fn, secret secp256k1.Fn) Shares {
+		shares := make(Shares, len(indices))
+		Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+		return shares
+	}
+
+	vshare := func(indices []secp256k1.Fn, secret secp256k1.Fn) (VerifiableShares, Commitment) {
+		vshares := make(VerifiableShares, len(indices))
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+		return vshares, c
+	}
+
+	allValid := func(c Commitment, vshares VerifiableShares) bool {
+		for i := range vshares {
+			if !IsValid(h, &c, &vshares[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	Context("for shares", func() {
+		It("should add shares of two secrets", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				a, b := secp256k1.RandomFn(), secp256k1.RandomFn()
+				shares, other := share(indices, a), share(indices, b)
+
+				Expect(shares.AddInPlace(other)).To(Succeed())
+				var sum secp256k1.Fn
+				sum.Add(&a, &b)
+				opened := Open(shares[:k])
+				Expect(opened.Eq(&sum)).To(BeTrue())
+			}
+		})
+
+		It("should add constants and scale", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				secret, c, scale := secp256k1.RandomFn(), secp256k1.RandomFn(), secp256k1.RandomFn()
+				shares := share(indices, secret)
+
+				shares.AddConstantInPlace(&c)
+				shares.ScaleInPlace(&scale)
+				var expected secp256k1.Fn
+				expected.Add(&secret, &c)
+				expected.Mul(&expected, &scale)
+				opened := Open(shares[:k])
+				Expect(opened.Eq(&expected)).To(BeTrue())
+			}
+		})
+
+		It("should return an error without modifying the shares when they are not aligned", func() {
+			indices := RandomIndices(n)
+			shares := share(indices, secp256k1.RandomFn())
+			original := append(Shares{}, shares...)
+
+			Expect(shares.AddInPlace(share(indices[:n-1], secp256k1.RandomFn()))).ToNot(Succeed())
+
+			other := share(indices, secp256k1.RandomFn())
+			other[n-1].Index = secp256k1.RandomFn()
+			Expect(shares.AddInPlace(other)).ToNot(Succeed())
+
+			for i := range shares {
+				Expect(shares[i].Eq(&original[i])).To(BeTrue())
+			}
+		})
+	})
+
+	Context("for verifiable shares", func() {
+		It("should add verifiable shares of two secrets", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				a, b := secp256k1.RandomFn(), secp256k1.RandomFn()
+				vshares, ca := vshare(indices, a)
+				other, cb := vshare(indices, b)
+
+				Expect(vshares.AddInPlace(other)).To(Succeed())
+				c := NewCommitmentWithCapacity(k)
+				c.Add(ca, cb)
+				Expect(allValid(c, vshares)).To(BeTrue())
+
+				var sum secp256k1.Fn
+				sum.Add(&a, &b)
+				opened := Open(vshares.Shares()[:k])
+				Expect(opened.Eq(&sum)).To(BeTrue())
+			}
+		})
+
+		It("should add constants and scale", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				secret, constant, scale := secp256k1.RandomFn(), secp256k1.RandomFn(), secp256k1.RandomFn()
+				vshares, c := vshare(indices, secret)
+
+				vshares.AddConstantInPlace(&constant)
+				c.AddConstant(c, &constant)
+				Expect(allValid(c, vshares)).To(BeTrue())
+
+				vshares.ScaleInPlace(&scale)
+				c.Scale(c, &scale)
+				Expect(allValid(c, vshares)).To(BeTrue())
+
+				var expected secp256k1.Fn
+				expected.Add(&secret, &constant)
+				expected.Mul(&expected, &scale)
+				opened := Open(vshares.Shares()[:k])
+				Expect(opened.Eq(&expected)).To(BeTrue())
+			}
+		})
+
+		It("should return an error without modifying the shares when they are not aligned", func() {
+			indices := RandomIndices(n)
+			vshares, _ := vshare(indices, secp256k1.RandomFn())
+			original := append(VerifiableShares{}, vshares...)
+
+			other, _ := vshare(indices, secp256k1.RandomFn())
+			other[0], other[1] = other[1], other[0]
+			Expect(vshares.AddInPlace(other)).ToNot(Succeed())
+			Expect(vshares.AddInPlace(other[:n-1])).ToNot(Succeed())
+
+			for i := range vshares {
+				Expect(vshares[i].Eq(&original[i])).To(BeTrue())
+			}
+		})
+	})
+
+	Context("for aligned shares", func() {
+		It("should compute weighted sums of the sharings", func() {
+			m := 5
+			indices := RandomIndices(n)
+			for i := 0; i < trials; i++ {
+				sharings := make([]Shares, m)
+				weights := make([]secp256k1.Fn, m)
+				var expected, term secp256k1.Fn
+				for j := range sharings {
+					secret := secp256k1.RandomFn()
+					sharings[j] = share(indices, secret)
+					weights[j] = secp256k1.RandomFn()
+					term.Mul(&weights[j], &secret)
+					expected.Add(&expected, &term)
+				}
+				aligned, err := NewAlignedShares(sharings...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(aligned.NumSharings()).To(Equal(m))
+				Expect(aligned.NumShares()).To(Equal(n))
+
+				sum := make(Shares, n)
+				Expect(aligned.WeightedSum(&sum, weights)).To(Succeed())
+				for l := range sum {
+					Expect(sum[l].Index.Eq(&indices[l])).To(BeTrue())
+				}
+				opened := Open(sum[n-k:])
+				Expect(opened.Eq(&expected)).To(BeTrue())
+
+				for j := range sharings {
+					copied := aligned.Sharing(j)
+					for l := range copied {
+						Expect(copied[l].Eq(&sharings[j][l])).To(BeTrue())
+					}
+				}
+			}
+		})
+
+		It("should return an error when the sharings are not aligned", func() {
+			indices := RandomIndices(n)
+			a, b := share(indices, secp256k1.RandomFn()), share(indices, secp256k1.RandomFn())
+			b[2], b[3] = b[3], b[2]
+			_, err := NewAlignedShares(a, b)
+			Expect(err).To(HaveOccurred())
+			_, err = NewAlignedShares(a, a[:n-1])
+			Expect(err).To(HaveOccurred())
+			_, err = NewAlignedShares()
+			Expect(err).To(HaveOccurred())
+
+			aligned, err := NewAlignedShares(a, a)
+			Expect(err).ToNot(HaveOccurred())
+			sum := make(Shares, n)
+			Expect(aligned.WeightedSum(&sum, []secp256k1.Fn{secp256k1.RandomFn()})).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,251 @@
+package shamir
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// Authenticated shares
+//
+// Plain shares can be modified without detection: Open silently returns a
+// different secret. Verifiable shares detect this, but need a commitment and
+// a Pedersen parameter and cost a multi-exponentiation to check. Authenticated
+// shares sit between the two: each share carries an HMAC-SHA256 tag keyed by
+// a verification key that the dealer chooses, and a share is checked by
+// recomputing its tag. The tags can only be checked, or forged, by someone
+// who knows the key, so the key must not be given to the holders of the
+// shares. Instead the dealer escrows it alongside the secret, for example in
+// the metadata of a backup, and gives it to whoever runs the reconstruction.
+// Unlike verifiable sharing, a holder of a share can not check it on receipt,
+// and nothing stops the dealer from dealing inconsistent shares.
+
+// AuthKeySize is the number of bytes in an AuthKey.
+const AuthKeySize = 32
+
+// AuthTagSize is the number of bytes in the tag of an AuthenticatedShare.
+const AuthTagSize = sha256.Size
+
+// AuthShareSize is the number of bytes in an authenticated share.
+const AuthShareSize = ShareSize + AuthTagSize
+
+// The tag that separates the tags of authenticated shares from other uses of
+// the verification key.
+const authShareTag = "renproject/shamir/authenticated share"
+
+// ErrShareAuth is wrapped by the errors returned by OpenAuthenticated when
+// the tag of a share is not valid.
+var ErrShareAuth = errors.New("share authentication failed")
+
+// An AuthKey is the verification key of a sharing of authenticated shares.
+type AuthKey [AuthKeySize]byte
+
+// NewAuthKey returns a random verification key, read from the source set
+// with SetRandomSource. An error is returned if reading from the source fails.
+func NewAuthKey() (AuthKey, error) {
+	var key AuthKey
+	if err := sharing.ReadRandom(RandomSource(), key[:]); err != nil {
+		return AuthKey{}, err
+	}
+	return key, nil
+}
+
+// An AuthenticatedShare is a Share together with a tag that authenticates its
+// index and value under the verification key of the sharing.
+type AuthenticatedShare struct {
+	Share Share
+	Tag   [AuthTagSize]byte
+}
+
+// AuthenticatedShares is a slice of authenticated shares.
+type AuthenticatedShares []AuthenticatedShare
+
+// ShareSecretAuthenticated is the same as ShareSecret, but also tags each of
+// the shares with the given verification key.
+func ShareSecretAuthenticated(dst *AuthenticatedShares, indices []secp256k1.Fn, secret secp256k1.Fn, k int, key *AuthKey, opts ...ShareOption) error {
+	shares := make(Shares, len(indices))
+	defer shares.Zero()
+	if err := ShareSecret(&shares, indices, secret, k, opts...); err != nil {
+		return err
+	}
+	*dst = (*dst)[:len(indices)]
+	mac := newAuthMAC(key)
+	for i := range shares {
+		(*dst)[i].Share = shares[i]
+		authTag(&(*dst)[i].Tag, mac, &shares[i])
+	}
+	return nil
+}
+
+// Verify returns true if the tag of the share is valid for the given
+// verification key.
+func (as *AuthenticatedShare) Verify(key *AuthKey) bool {
+	return as.verify(newAuthMAC(key))
+}
+
+func (as *AuthenticatedShare) verify(mac hash.Hash) bool {
+	var tag [AuthTagSize]byte
+	authTag(&tag, mac, &as.Share)
+	return hmac.Equal(tag[:], as.Tag[:])
+}
+
+// OpenAuthenticated checks the tags of the given shares with the verification
+// key, and then computes the secret as for Open. An error wrapping
+// ErrShareAuth, which names the positions of every share whose tag is not
+// valid, is returned if any tag is not valid, and an error wrapping
+// ErrDuplicateIndex if two shares have the same index. As for Open, the
+// result is only the secret if there are at least k shares.
+func OpenAuthenticated(shares AuthenticatedShares, key *AuthKey) (secp256k1.Fn, error) {
+	mac := newAuthMAC(key)
+	var invalid []int
+	for i := range shares {
+		if !shares[i].verify(mac) {
+			invalid = append(invalid, i)
+		}
+	}
+	if len(invalid) > 0 {
+		return secp256k1.Fn{}, fmt.Errorf("%w: shares %v", ErrShareAuth, invalid)
+	}
+	plain := shares.Shares()
+	defer plain.Zero()
+	indices := make([]secp256k1.Fn, len(plain))
+	for i := range plain {
+		indices[i] = plain[i].Index
+	}
+	if err := validateIndices(indices, ShareOptions{RejectDuplicates: true}); err != nil {
+		return secp256k1.Fn{}, err
+	}
+	return Open(plain), nil
+}
+
+// Shares returns the underlying shares of the authenticated shares, without
+// their tags.
+func (shares AuthenticatedShares) Shares() Shares {
+	plain := make(Shares, len(shares))
+	for i := range shares {
+		plain[i] = shares[i].Share
+	}
+	return plain
+}
+
+// Eq returns true if the two authenticated shares are equal, and false
+// otherwise.
+func (as *AuthenticatedShare) Eq(other *AuthenticatedShare) bool {
+	return as.Share.Eq(&other.Share) && as.Tag == other.Tag
+}
+
+// Zero sets the share and the tag to zero.
+func (as *AuthenticatedShare) Zero() {
+	as.Share.Zero()
+	as.Tag = [AuthTagSize]byte{}
+}
+
+// Zero sets every share and tag in the slice to zero.
+func (shares AuthenticatedShares) Zero() {
+	for i := range shares {
+		shares[i].Zero()
+	}
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (as AuthenticatedShare) SizeHint() int { return as.Share.SizeHint() + AuthTagSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (as AuthenticatedShare) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := as.Share.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if len(buf) < AuthTagSize || rem < AuthTagSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(buf, as.Tag[:])
+	return buf[AuthTagSize:], rem - AuthTagSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (as *AuthenticatedShare) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := as.Share.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if len(buf) < AuthTagSize || rem < AuthTagSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(as.Tag[:], buf)
+	return buf[AuthTagSize:], rem - AuthTagSize, nil
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (shares AuthenticatedShares) SizeHint() int {
+	return surge.SizeHintU32 + AuthShareSize*len(shares)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (shares AuthenticatedShares) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(shares)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	for i := range shares {
+		buf, rem, err = shares[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (shares *AuthenticatedShares) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, AuthShareSize, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	if *shares == nil {
+		*shares = make(AuthenticatedShares, 0, l)
+	}
+
+	*shares = (*shares)[:0]
+	for i := uint32(0); i < l; i++ {
+		*shares = append(*shares, AuthenticatedShare{})
+		buf, rem, err = (*shares)[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Returns the MAC for authenticated shares keyed by the given key.
+func newAuthMAC(key *AuthKey) hash.Hash {
+	return hmac.New(sha256.New, key[:])
+}
+
+// Computes the tag of the share, which is the MAC of
+//
+//	tag || version || index || value
+//
+// encoded as for the digests in digest.go.
+func authTag(dst *[AuthTagSize]byte, mac hash.Hash, s *Share) {
+	mac.Reset()
+	writeU32(mac, uint32(len(authShareTag)))
+	mac.Write([]byte(authShareTag))
+	mac.Write([]byte{DigestVersion})
+	var bs [secp256k1.FnSizeMarshalled]byte
+	s.Index.PutB32(bs[:])
+	mac.Write(bs[:])
+	s.Value.PutB32(bs[:])
+	mac.Write(bs[:])
+	mac.Sum(dst[:0])
+}
@@ -0,0 +1,87 @@
+package shamir_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Authenticated shares", func() {
+	n, k := 10, 4
+
+	mustAuthKey := func() AuthKey {
+		key, err := NewAuthKey()
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	deal := func(key *AuthKey) (AuthenticatedShares, secp256k1.Fn) {
+		secret := secp256k1.RandomFn()
+		shares := make(AuthenticatedShares, n)
+		Expect(ShareSecretAuthenticated(&shares, RandomIndices(n), secret, k, key)).To(Succeed())
+		return shares, secret
+	}
+
+	It("should open to the secret with the verification key", func() {
+		key := mustAuthKey()
+		shares, secret := deal(&key)
+		for i := range shares {
+			Expect(shares[i].Verify(&key)).To(BeTrue())
+		}
+		opened, err := OpenAuthenticated(shares[:k], &key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opened.Eq(&secret)).To(BeTrue())
+		plain := Open(shares.Shares()[n-k:])
+		Expect(plain.Eq(&secret)).To(BeTrue())
+	})
+
+	It("should detect modified shares", func() {
+		key := mustAuthKey()
+		shares, _ := deal(&key)
+		shares[1].Share.Value = secp256k1.RandomFn()
+		shares[3].Share.Index = secp256k1.RandomFn()
+		shares[2].Tag[0] ^= 1
+		Expect(shares[1].Verify(&key)).To(BeFalse())
+
+		_, err := OpenAuthenticated(shares, &key)
+		Expect(errors.Is(err, ErrShareAuth)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("[1 2 3]"))
+	})
+
+	It("should not verify shares with a different key", func() {
+		key, other := mustAuthKey(), mustAuthKey()
+		shares, _ := deal(&key)
+		Expect(shares[0].Verify(&other)).To(BeFalse())
+		_, err := OpenAuthenticated(shares, &other)
+		Expect(errors.Is(err, ErrShareAuth)).To(BeTrue())
+	})
+
+	It("should reject duplicate shares", func() {
+		key := mustAuthKey()
+		shares, _ := deal(&key)
+		shares[1] = shares[0]
+		_, err := OpenAuthenticated(shares, &key)
+		Expect(errors.Is(err, ErrDuplicateIndex)).To(BeTrue())
+	})
+
+	It("should marshal and unmarshal", func() {
+		key := mustAuthKey()
+		shares, _ := deal(&key)
+		buf, err := surge.ToBinary(shares)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf).To(HaveLen(shares.SizeHint()))
+		var decoded AuthenticatedShares
+		Expect(surge.FromBinary(&decoded, buf)).To(Succeed())
+		Expect(decoded).To(HaveLen(n))
+		for i := range shares {
+			Expect(decoded[i].Eq(&shares[i])).To(BeTrue())
+		}
+		Expect(surge.FromBinary(&decoded, buf[:len(buf)-1])).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,134 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// A fixedBaseTable holds the multiples d*16^w*P of a point P for every digit d
+// and window w, so that a scalar multiplication of P needs only one addition
+// per window and no doublings.
+type fixedBaseTable [256 / msmWindow]pointTable
+
+func newFixedBaseTable(p *secp256k1.Point) *fixedBaseTable {
+	table := new(fixedBaseTable)
+	base := *p
+	for w := range table {
+		table[w] = newPointTable(&base)
+		for j := 0; j < msmWindow; j++ {
+			base.Add(&base, &base)
+		}
+	}
+	return table
+}
+
+// Computes scalar*P. This is not constant time with respect to the scalar.
+func (table *fixedBaseTable) mul(dst *secp256k1.Point, scalar *secp256k1.Fn) {
+	var bs [32]byte
+	scalar.PutB32(bs[:])
+	acc := secp256k1.NewPointInfinity()
+	for w := range table {
+		if digit := window(&bs, w); digit != 0 {
+			acc.Add(&acc, &table[w][digit])
+		}
+	}
+	*dst = acc
+}
+
+// VShareSecretBatch verifiably shares each of the given secrets among the
+// parties with the given indices with reconstruction threshold k, and returns
+// one dealing for each secret, in the same order. The result is the same as
+// calling Deal for each secret, but the work that depends only on the indices
+// and h is done once for the whole batch: the powers of the indices are
+// tabulated, and the commitments are computed using precomputed multiples of G
+// and h. This makes the batch about twice as fast as the naive loop when there
+// are many secrets.
+//
+// Unlike VShareSecret, the scalar multiplications used for the commitments are
+// not constant time with respect to the coefficients of the sharing
+// polynomials. VShareSecret should be used instead where timing side channels
+// are a concern.
+//
+// Panics: This function will panic if any of the given indices is the zero
+// element.
+func VShareSecretBatch(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secrets []secp256k1.Fn,
+	k int,
+) ([]Dealing, error) {
+	for _, index := range indices {
+		if index.IsZero() {
+			panic("cannot create share for index zero")
+		}
+	}
+	if k > len(indices) {
+		return nil, &ErrKTooLarge{K: k, N: len(indices)}
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("%w: expected k >= 1, got k = %v", ErrInvalidThreshold, k)
+	}
+
+	// Tabulate the powers of every index, so that evaluating a polynomial is
+	// a dot product with its coefficients.
+	pows := make([][]secp256k1.Fn, len(indices))
+	for i := range indices {
+		pows[i] = make([]secp256k1.Fn, k)
+		powers(pows[i], &indices[i])
+	}
+
+	one := secp256k1.NewFnFromU16(1)
+	var g secp256k1.Point
+	g.BaseExp(&one)
+	gTable, hTable := newFixedBaseTable(&g), newFixedBaseTable(&h)
+
+	coeffs := make([]secp256k1.Fn, k)
+	decomCoeffs := make([]secp256k1.Fn, k)
+	defer WipeFns(coeffs)
+	defer WipeFns(decomCoeffs)
+
+	dealings := make([]Dealing, len(secrets))
+	var hPow secp256k1.Point
+	for s := range secrets {
+		if err := setRandomCoeffs(coeffs, secrets[s], k); err != nil {
+			return nil, err
+		}
+		decom, err := randomFn(RandomSource())
+		if err != nil {
+			return nil, err
+		}
+		if err := setRandomCoeffs(decomCoeffs, decom, k); err != nil {
+			return nil, err
+		}
+
+		c := make(Commitment, k)
+		for i := range c {
+			gTable.mul(&c[i], &coeffs[i])
+			hTable.mul(&hPow, &decomCoeffs[i])
+			c[i].Add(&c[i], &hPow)
+		}
+
+		vshares := make(VerifiableShares, len(indices))
+		for i := range vshares {
+			vshares[i].Share.Index = indices[i]
+			dot(&vshares[i].Share.Value, coeffs, pows[i])
+			dot(&vshares[i].Decommitment, decomCoeffs, pows[i])
+		}
+
+		dealings[s] = Dealing{Commitment: c, Shares: vshares}
+	}
+
+	return dealings, nil
+}
+
+// Sets dst to the dot product of the two given slices, which must have the
+// same length.
+func dot(dst *secp256k1.Fn, xs, ys []secp256k1.Fn) {
+	var term secp256k1.Fn
+	dst.SetU16(0)
+	for i := range xs {
+		term.Mul(&xs[i], &ys[i])
+		dst.Add(dst, &term)
+	}
+}
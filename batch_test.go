@@ -0,0 +1,92 @@
+package shamir_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Batch verifiable sharing", func() {
+	trials := 5
+	n := 20
+	h := PedersenH()
+
+	It("should produce a valid dealing for each secret", func() {
+		for i := 0; i < trials; i++ {
+			indices := RandomIndices(n)
+			k := RandRange(1, n)
+			secrets := make([]secp256k1.Fn, RandRange(0, 10))
+			for j := range secrets {
+				secrets[j] = secp256k1.RandomFn()
+			}
+
+			dealings, err := VShareSecretBatch(indices, h, secrets, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dealings).To(HaveLen(len(secrets)))
+			for j := range dealings {
+				Expect(dealings[j].Validate(h)).To(Succeed())
+				Expect(dealings[j].Commitment.Len()).To(Equal(k))
+				for l := range indices {
+					Expect(dealings[j].Shares[l].Share.Index.Eq(&indices[l])).To(BeTrue())
+				}
+				recon := Open(dealings[j].Shares.Shares()[:k])
+				Expect(recon.Eq(&secrets[j])).To(BeTrue())
+			}
+		}
+	})
+
+	It("should return an error for an invalid threshold", func() {
+		secrets := []secp256k1.Fn{secp256k1.RandomFn()}
+		_, err := VShareSecretBatch(RandomIndices(n), h, secrets, n+1)
+		var kErr *ErrKTooLarge
+		Expect(errors.As(err, &kErr)).To(BeTrue())
+		Expect(kErr.K).To(Equal(n + 1))
+		_, err = VShareSecretBatch(RandomIndices(n), h, secrets, 0)
+		Expect(errors.Is(err, ErrInvalidThreshold)).To(BeTrue())
+	})
+
+	It("should panic if an index is zero", func() {
+		indices := RandomIndices(n)
+		indices[0].Clear()
+		secrets := []secp256k1.Fn{secp256k1.RandomFn()}
+		Expect(func() { _, _ = VShareSecretBatch(indices, h, secrets, n) }).To(Panic())
+	})
+})
+
+func BenchmarkVShareSecretNaive(b *testing.B) {
+	n, k, m := 100, 33, 20
+	h := secp256k1.RandomPoint()
+	indices := RandomIndices(n)
+	secrets := make([]secp256k1.Fn, m)
+	for i := range secrets {
+		secrets[i] = secp256k1.RandomFn()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range secrets {
+			_, _ = Deal(indices, h, secrets[j], k)
+		}
+	}
+}
+
+func BenchmarkVShareSecretBatch(b *testing.B) {
+	n, k, m := 100, 33, 20
+	h := secp256k1.RandomPoint()
+	indices := RandomIndices(n)
+	secrets := make([]secp256k1.Fn, m)
+	for i := range secrets {
+		secrets[i] = secp256k1.RandomFn()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = VShareSecretBatch(indices, h, secrets, k)
+	}
+}
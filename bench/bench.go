@@ -0,0 +1,254 @@
+// Package bench benchmarks the sharing, opening and verification operations
+// of this module over a matrix of parameters and groups, and reports the
+// results as JSON. It is intended for sizing hardware for a deployment, and
+// for tracking performance across releases by comparing the reports of two
+// versions on the same machine.
+//
+// The benchmarks can be run from a program with
+//
+//	report, err := bench.RunAll(bench.Options{Output: os.Stdout})
+//
+// and every benchmark can be restricted to a subset of the groups, parameters
+// and operations with the corresponding options.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// A Curve identifies the group that a benchmark is run over.
+type Curve string
+
+// The groups that are supported.
+const (
+	CurveSecp256k1    Curve = "secp256k1"
+	CurveP256         Curve = "p256"
+	CurveBN254        Curve = "bn254"
+	CurveRistretto255 Curve = "ristretto255"
+)
+
+// Curves returns all of the supported groups.
+func Curves() []Curve {
+	return []Curve{CurveSecp256k1, CurveP256, CurveBN254, CurveRistretto255}
+}
+
+// Params are the number of shares N and the reconstruction threshold K of a
+// sharing.
+type Params struct {
+	N int `json:"n"`
+	K int `json:"k"`
+}
+
+// DefaultParams are the parameters that are benchmarked when none are given:
+// small, medium and large sharings, each with a threshold of just over a
+// third of the shares.
+var DefaultParams = []Params{{N: 10, K: 4}, {N: 100, K: 34}, {N: 1000, K: 334}}
+
+// DefaultDuration is the time that each benchmark is run for when no duration
+// is given.
+const DefaultDuration = time.Second
+
+// Options configures RunAll. The zero value runs every benchmark over every
+// group with the default parameters, and does not write the report.
+type Options struct {
+	// Curves are the groups to benchmark. All groups are benchmarked if it
+	// is empty.
+	Curves []Curve
+	// Params are the parameters to benchmark. DefaultParams are used if it
+	// is empty.
+	Params []Params
+	// Benchmarks are the names of the operations to benchmark, as listed by
+	// Benchmarks. All operations are benchmarked if it is empty; an
+	// operation that is not supported for a group is skipped for that group.
+	Benchmarks []string
+	// Duration is the minimum time for which each benchmark is run.
+	// DefaultDuration is used if it is zero.
+	Duration time.Duration
+	// Output, if not nil, is where the report is written as JSON.
+	Output io.Writer
+}
+
+// A Report holds the results of RunAll together with a description of the
+// environment that they were measured in.
+type Report struct {
+	GoVersion string   `json:"goVersion"`
+	GOOS      string   `json:"goos"`
+	GOARCH    string   `json:"goarch"`
+	NumCPU    int      `json:"numCPU"`
+	Results   []Result `json:"results"`
+}
+
+// A Result is the measurement of one operation over one group with one set of
+// parameters.
+type Result struct {
+	Benchmark   string `json:"benchmark"`
+	Curve       Curve  `json:"curve"`
+	Params      Params `json:"params"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"nsPerOp"`
+	AllocsPerOp int64  `json:"allocsPerOp"`
+	BytesPerOp  int64  `json:"bytesPerOp"`
+}
+
+// Benchmarks returns the names of the operations that can be benchmarked over
+// the given group, or nil if the group is not supported. The operations are:
+//
+//	share           Shamir sharing of a secret into N shares
+//	open            opening the secret from K shares
+//	vshare          Pedersen verifiable sharing of a secret into N shares
+//	verify          verifying one verifiable share against its commitment
+//	verifier        as for verify, using a shamir.Verifier (secp256k1 only)
+//	index-verifier  as for verify, using a shamir.IndexVerifier (secp256k1 only)
+//	interpolate     interpolating a polynomial through N points (secp256k1 only)
+func Benchmarks(curve Curve) []string {
+	benchmarks, ok := curveBenchmarks[curve]
+	if !ok {
+		return nil
+	}
+	names := make([]string, len(benchmarks))
+	for i := range benchmarks {
+		names[i] = benchmarks[i].name
+	}
+	return names
+}
+
+// RunAll runs the benchmarks that are selected by the options, in the order
+// of the groups, then the parameters, then the operations, and returns the
+// report. If the options have an output, the report is also written to it as
+// JSON. An error is returned if a group or operation is not known, or if the
+// parameters are not valid, before any benchmark is run.
+func RunAll(opts Options) (Report, error) {
+	curves := opts.Curves
+	if len(curves) == 0 {
+		curves = Curves()
+	}
+	params := opts.Params
+	if len(params) == 0 {
+		params = DefaultParams
+	}
+	duration := opts.Duration
+	if duration == 0 {
+		duration = DefaultDuration
+	}
+	if err := checkOptions(curves, params, opts.Benchmarks); err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Results:   []Result{},
+	}
+	for _, curve := range curves {
+		for _, p := range params {
+			for _, b := range curveBenchmarks[curve] {
+				if len(opts.Benchmarks) > 0 && !contains(opts.Benchmarks, b.name) {
+					continue
+				}
+				result := measure(b.setup(p.N, p.K), duration)
+				result.Benchmark, result.Curve, result.Params = b.name, curve, p
+				report.Results = append(report.Results, result)
+			}
+		}
+	}
+
+	if opts.Output != nil {
+		enc := json.NewEncoder(opts.Output)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// A benchmark is a named operation. The setup function prepares the inputs
+// for the given parameters, and returns a function that performs the
+// operation once.
+type benchmark struct {
+	name  string
+	setup func(n, k int) func()
+}
+
+func checkOptions(curves []Curve, params []Params, names []string) error {
+	for _, curve := range curves {
+		if _, ok := curveBenchmarks[curve]; !ok {
+			return fmt.Errorf("unknown curve %q", curve)
+		}
+	}
+	for _, p := range params {
+		if p.K < 1 || p.K > p.N || p.N > 1<<16-1 {
+			return fmt.Errorf("invalid parameters: n = %v, k = %v", p.N, p.K)
+		}
+	}
+	for _, name := range names {
+		known := false
+		for _, curve := range Curves() {
+			known = known || contains(Benchmarks(curve), name)
+		}
+		if !known {
+			return fmt.Errorf("unknown benchmark %q", name)
+		}
+	}
+	return nil
+}
+
+// Runs the operation repeatedly for at least the given duration, increasing
+// the number of iterations in the same way as the testing package, and
+// returns the measurements of the final run.
+func measure(op func(), duration time.Duration) Result {
+	op()
+	iterations := 1
+	for {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := 0; i < iterations; i++ {
+			op()
+		}
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		if elapsed >= duration || iterations >= 1e9 {
+			n := int64(iterations)
+			return Result{
+				Iterations:  iterations,
+				NsPerOp:     elapsed.Nanoseconds() / n,
+				AllocsPerOp: int64(after.Mallocs-before.Mallocs) / n,
+				BytesPerOp:  int64(after.TotalAlloc-before.TotalAlloc) / n,
+			}
+		}
+
+		// Aim for 20% more than the duration, but grow by at most 100 times
+		// and at least by one iteration.
+		next := int64(iterations) * 100
+		if ns := elapsed.Nanoseconds(); ns > 0 {
+			if predicted := int64(1.2 * float64(iterations) * float64(duration.Nanoseconds()) / float64(ns)); predicted < next {
+				next = predicted
+			}
+		}
+		if next <= int64(iterations) {
+			next = int64(iterations) + 1
+		}
+		if next > 1e9 {
+			next = 1e9
+		}
+		iterations = int(next)
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package bench_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/bench"
+)
+
+var _ = Describe("Benchmarks", func() {
+	params := []Params{{N: 5, K: 2}, {N: 8, K: 8}}
+
+	It("should run every benchmark for every curve and parameters", func() {
+		report, err := RunAll(Options{Params: params, Duration: time.Millisecond})
+		Expect(err).ToNot(HaveOccurred())
+
+		i := 0
+		for _, curve := range Curves() {
+			for _, p := range params {
+				for _, name := range Benchmarks(curve) {
+					result := report.Results[i]
+					Expect(result.Curve).To(Equal(curve))
+					Expect(result.Params).To(Equal(p))
+					Expect(result.Benchmark).To(Equal(name))
+					Expect(result.Iterations).To(BeNumerically(">", 0))
+					Expect(result.NsPerOp).To(BeNumerically(">", 0))
+					i++
+				}
+			}
+		}
+		Expect(report.Results).To(HaveLen(i))
+	})
+
+	It("should only run the selected benchmarks", func() {
+		report, err := RunAll(Options{
+			Curves:     []Curve{CurveP256, CurveSecp256k1},
+			Params:     params[:1],
+			Benchmarks: []string{"verify", "verifier"},
+			Duration:   time.Millisecond,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Results).To(HaveLen(3))
+		Expect(report.Results[0].Curve).To(Equal(CurveP256))
+		Expect(report.Results[0].Benchmark).To(Equal("verify"))
+		Expect(report.Results[2].Benchmark).To(Equal("verifier"))
+	})
+
+	It("should write the report as JSON", func() {
+		var buf bytes.Buffer
+		report, err := RunAll(Options{
+			Curves:     []Curve{CurveRistretto255},
+			Params:     params[:1],
+			Benchmarks: []string{"open"},
+			Duration:   time.Millisecond,
+			Output:     &buf,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var decoded Report
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(report))
+		Expect(decoded.GoVersion).ToNot(BeEmpty())
+	})
+
+	It("should return an error for invalid options", func() {
+		_, err := RunAll(Options{Curves: []Curve{"ed448"}})
+		Expect(err).To(HaveOccurred())
+		_, err = RunAll(Options{Params: []Params{{N: 3, K: 4}}})
+		Expect(err).To(HaveOccurred())
+		_, err = RunAll(Options{Params: []Params{{N: 3, K: 0}}})
+		Expect(err).To(HaveOccurred())
+		_, err = RunAll(Options{Benchmarks: []string{"unknown"}})
+		Expect(err).To(HaveOccurred())
+		Expect(Benchmarks("ed448")).To(BeNil())
+	})
+})
@@ -0,0 +1,195 @@
+package bench
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/bn254"
+	"github.com/renproject/shamir/p256"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/ristretto255"
+	"github.com/renproject/shamir/shamirutil"
+)
+
+// The benchmarks for each group. The groups other than secp256k1 have the
+// same API as the root package for sharing, opening and verification, but not
+// for its other functionality.
+var curveBenchmarks = map[Curve][]benchmark{
+	CurveSecp256k1:    secp256k1Benchmarks,
+	CurveP256:         p256Benchmarks,
+	CurveBN254:        bn254Benchmarks,
+	CurveRistretto255: ristretto255Benchmarks,
+}
+
+var secp256k1Benchmarks = []benchmark{
+	{"share", func(n, k int) func() {
+		indices := shamirutil.SequentialIndices(n)
+		shares := make(shamir.Shares, n)
+		secret := secp256k1.RandomFn()
+		return func() { _ = shamir.ShareSecret(&shares, indices, secret, k) }
+	}},
+	{"open", func(n, k int) func() {
+		indices := shamirutil.SequentialIndices(n)
+		shares := make(shamir.Shares, n)
+		_ = shamir.ShareSecret(&shares, indices, secp256k1.RandomFn(), k)
+		shamirutil.Shuffle(shares)
+		return func() { _ = shamir.Open(shares[:k]) }
+	}},
+	{"vshare", func(n, k int) func() {
+		h := shamir.PedersenH()
+		indices := shamirutil.SequentialIndices(n)
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		secret := secp256k1.RandomFn()
+		return func() { _ = shamir.VShareSecret(&vshares, &c, indices, h, secret, k) }
+	}},
+	{"verify", func(n, k int) func() {
+		h := shamir.PedersenH()
+		d, _ := shamir.Deal(shamirutil.SequentialIndices(n), h, secp256k1.RandomFn(), k)
+		share := d.Shares[rand.Intn(n)]
+		return func() { shamir.IsValid(h, &d.Commitment, &share) }
+	}},
+	{"verifier", func(n, k int) func() {
+		h := shamir.PedersenH()
+		d, _ := shamir.Deal(shamirutil.SequentialIndices(n), h, secp256k1.RandomFn(), k)
+		verifier := shamir.NewVerifier(h, d.Commitment)
+		share := d.Shares[rand.Intn(n)]
+		return func() { verifier.Verify(&share) }
+	}},
+	{"index-verifier", func(n, k int) func() {
+		h := shamir.PedersenH()
+		set := shamir.NewSequentialIndexSet(n)
+		d, _ := shamir.Deal(set.Indices(), h, secp256k1.RandomFn(), k)
+		verifier := shamir.NewIndexVerifier(h, set, k)
+		share := d.Shares[rand.Intn(n)]
+		return func() { verifier.Verify(&d.Commitment, &share) }
+	}},
+	{"interpolate", func(n, k int) func() {
+		indices := shamirutil.SequentialIndices(n)
+		values := shamirutil.RandomIndices(n)
+		p := poly.NewWithCapacity(n)
+		interp := poly.NewInterpolator(indices)
+		return func() { interp.Interpolate(values, &p) }
+	}},
+}
+
+var p256Benchmarks = []benchmark{
+	{"share", func(n, k int) func() {
+		indices := p256Indices(n)
+		shares := make(p256.Shares, n)
+		secret := p256.RandomScalar()
+		return func() { _ = p256.ShareSecret(&shares, indices, secret, k) }
+	}},
+	{"open", func(n, k int) func() {
+		shares := make(p256.Shares, n)
+		_ = p256.ShareSecret(&shares, p256Indices(n), p256.RandomScalar(), k)
+		rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+		return func() { _ = p256.Open(shares[:k]) }
+	}},
+	{"vshare", func(n, k int) func() {
+		h := p256.PedersenH()
+		indices := p256Indices(n)
+		vshares := make(p256.VerifiableShares, n)
+		c := p256.NewCommitmentWithCapacity(k)
+		secret := p256.RandomScalar()
+		return func() { _ = p256.VShareSecret(&vshares, &c, indices, h, secret, k) }
+	}},
+	{"verify", func(n, k int) func() {
+		h := p256.PedersenH()
+		vshares := make(p256.VerifiableShares, n)
+		c := p256.NewCommitmentWithCapacity(k)
+		_ = p256.VShareSecret(&vshares, &c, p256Indices(n), h, p256.RandomScalar(), k)
+		share := vshares[rand.Intn(n)]
+		return func() { p256.IsValid(h, c, &share) }
+	}},
+}
+
+var bn254Benchmarks = []benchmark{
+	{"share", func(n, k int) func() {
+		indices := bn254Indices(n)
+		shares := make(bn254.Shares, n)
+		secret := bn254.RandomScalar()
+		return func() { _ = bn254.ShareSecret(&shares, indices, secret, k) }
+	}},
+	{"open", func(n, k int) func() {
+		shares := make(bn254.Shares, n)
+		_ = bn254.ShareSecret(&shares, bn254Indices(n), bn254.RandomScalar(), k)
+		rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+		return func() { _ = bn254.Open(shares[:k]) }
+	}},
+	{"vshare", func(n, k int) func() {
+		h := bn254.PedersenH()
+		indices := bn254Indices(n)
+		vshares := make(bn254.VerifiableShares, n)
+		c := bn254.NewCommitmentWithCapacity(k)
+		secret := bn254.RandomScalar()
+		return func() { _ = bn254.VShareSecret(&vshares, &c, indices, h, secret, k) }
+	}},
+	{"verify", func(n, k int) func() {
+		h := bn254.PedersenH()
+		vshares := make(bn254.VerifiableShares, n)
+		c := bn254.NewCommitmentWithCapacity(k)
+		_ = bn254.VShareSecret(&vshares, &c, bn254Indices(n), h, bn254.RandomScalar(), k)
+		share := vshares[rand.Intn(n)]
+		return func() { bn254.IsValid(h, c, &share) }
+	}},
+}
+
+var ristretto255Benchmarks = []benchmark{
+	{"share", func(n, k int) func() {
+		indices := ristretto255Indices(n)
+		shares := make(ristretto255.Shares, n)
+		secret := ristretto255.RandomScalar()
+		return func() { _ = ristretto255.ShareSecret(&shares, indices, secret, k) }
+	}},
+	{"open", func(n, k int) func() {
+		shares := make(ristretto255.Shares, n)
+		_ = ristretto255.ShareSecret(&shares, ristretto255Indices(n), ristretto255.RandomScalar(), k)
+		rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+		return func() { _ = ristretto255.Open(shares[:k]) }
+	}},
+	{"vshare", func(n, k int) func() {
+		h := ristretto255.PedersenH()
+		indices := ristretto255Indices(n)
+		vshares := make(ristretto255.VerifiableShares, n)
+		c := ristretto255.NewCommitmentWithCapacity(k)
+		secret := ristretto255.RandomScalar()
+		return func() { _ = ristretto255.VShareSecret(&vshares, &c, indices, h, secret, k) }
+	}},
+	{"verify", func(n, k int) func() {
+		h := ristretto255.PedersenH()
+		vshares := make(ristretto255.VerifiableShares, n)
+		c := ristretto255.NewCommitmentWithCapacity(k)
+		_ = ristretto255.VShareSecret(&vshares, &c, ristretto255Indices(n), h, ristretto255.RandomScalar(), k)
+		share := vshares[rand.Intn(n)]
+		return func() { ristretto255.IsValid(h, c, &share) }
+	}},
+}
+
+// Every group uses the indices 1 to n, so that the results for different
+// groups are comparable.
+
+func p256Indices(n int) []p256.Scalar {
+	indices := make([]p256.Scalar, n)
+	for i := range indices {
+		indices[i] = p256.NewScalarFromU16(uint16(i) + 1)
+	}
+	return indices
+}
+
+func bn254Indices(n int) []bn254.Scalar {
+	indices := make([]bn254.Scalar, n)
+	for i := range indices {
+		indices[i] = bn254.NewScalarFromU16(uint16(i) + 1)
+	}
+	return indices
+}
+
+func ristretto255Indices(n int) []ristretto255.Scalar {
+	indices := make([]ristretto255.Scalar, n)
+	for i := range indices {
+		indices[i] = ristretto255.NewScalarFromU16(uint16(i) + 1)
+	}
+	return indices
+}
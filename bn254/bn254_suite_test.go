@@ -0,0 +1,13 @@
+package bn254_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBN254(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BN254 Suite")
+}
@@ -0,0 +1,350 @@
+package bn254_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"reflect"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/bn254"
+)
+
+var _ = Describe("BN254 backend", func() {
+	trials := 20
+	n := 10
+
+	randomIndices := func(n int) []Scalar {
+		indices := make([]Scalar, n)
+		for i := range indices {
+			indices[i] = RandomScalar()
+		}
+		return indices
+	}
+
+	Context("group operations", func() {
+		It("should treat the zero value point as the identity", func() {
+			var zero Point
+			p := RandomPoint()
+			identity := NewIdentity()
+			Expect(zero.IsIdentity()).To(BeTrue())
+			Expect(zero.Eq(&identity)).To(BeTrue())
+			Expect(zero.Eq(&p)).To(BeFalse())
+
+			var sum Point
+			sum.Add(&p, &zero)
+			Expect(sum.Eq(&p)).To(BeTrue())
+		})
+
+		It("should satisfy the distributive law for scalar multiplication", func() {
+			for i := 0; i < trials; i++ {
+				a, b := RandomScalar(), RandomScalar()
+				var sum Scalar
+				sum.Add(&a, &b)
+
+				var ga, gb, gsum, expected Point
+				ga.BaseExp(&a)
+				gb.BaseExp(&b)
+				gsum.BaseExp(&sum)
+				expected.Add(&ga, &gb)
+				Expect(gsum.Eq(&expected)).To(BeTrue())
+
+				var diff Point
+				diff.Sub(&gsum, &gb)
+				Expect(diff.Eq(&ga)).To(BeTrue())
+			}
+		})
+
+		It("should compute inverses", func() {
+			for i := 0; i < trials; i++ {
+				a := RandomScalar()
+				var inv, prod Scalar
+				inv.Inverse(&a)
+				prod.Mul(&a, &inv)
+				one := NewScalarFromU16(1)
+				Expect(prod.Eq(&one)).To(BeTrue())
+			}
+		})
+
+		It("should reject invalid encodings", func() {
+			bs := make([]byte, PointSize)
+			for i := range bs {
+				bs[i] = 0xff
+			}
+			var s Scalar
+			Expect(s.SetBytes(bs[:ScalarSize])).ToNot(Succeed())
+			var p Point
+			Expect(p.SetBytes(bs)).ToNot(Succeed())
+
+			// The point (1, 1) is not on the curve.
+			bs = make([]byte, PointSize)
+			bs[31], bs[63] = 1, 1
+			Expect(p.SetBytes(bs)).ToNot(Succeed())
+		})
+
+		It("should match known multiples of the generator", func() {
+			g := NewGenerator()
+			two := NewScalarFromU16(2)
+			var g2 Point
+			g2.BaseExp(&two)
+			bs := make([]byte, PointSize)
+			g2.PutBytes(bs)
+			Expect(hex.EncodeToString(bs)).To(Equal(
+				"030644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd3" +
+					"15ed738c0e0a7c92e7845f96b2ae9c0a68a6a449e3538fc7ff3ebf7a5a18a2c4",
+			))
+
+			var sum Point
+			sum.Add(&g, &g)
+			Expect(sum.Eq(&g2)).To(BeTrue())
+
+			// (r - 1) * G = -G, where r is the order of G1.
+			var minusOne Scalar
+			one := NewScalarFromU16(1)
+			minusOne.Negate(&one)
+			var p, negG Point
+			p.BaseExp(&minusOne)
+			negG.Negate(&g)
+			Expect(p.Eq(&negG)).To(BeTrue())
+			p.Add(&p, &g)
+			Expect(p.IsIdentity()).To(BeTrue())
+
+			for i := 0; i < trials; i++ {
+				p := RandomPoint()
+				p.PutBytes(bs)
+				var decoded Point
+				Expect(decoded.SetBytes(bs)).To(Succeed())
+				Expect(decoded.Eq(&p)).To(BeTrue())
+			}
+		})
+
+		It("should agree with gnark-crypto for scalar multiplication", func() {
+			toInt := func(s *Scalar) *big.Int {
+				bs := make([]byte, ScalarSize)
+				s.PutBytes(bs)
+				return new(big.Int).SetBytes(bs)
+			}
+			encode := func(p *gnark.G1Affine) []byte {
+				x, y := p.X.Bytes(), p.Y.Bytes()
+				return append(x[:], y[:]...)
+			}
+
+			// Include the edge cases zero, one and the negation of one.
+			one := NewScalarFromU16(1)
+			var minusOne Scalar
+			minusOne.Negate(&one)
+			scalars := []Scalar{{}, one, minusOne}
+			for i := 0; i < trials; i++ {
+				scalars = append(scalars, RandomScalar())
+			}
+
+			_, _, g, _ := gnark.Generators()
+			bs := make([]byte, PointSize)
+			for _, s := range scalars {
+				s := s
+				var p Point
+				p.BaseExp(&s)
+				var expected gnark.G1Affine
+				expected.ScalarMultiplication(&g, toInt(&s))
+				p.PutBytes(bs)
+				Expect(bs).To(Equal(encode(&expected)))
+
+				// Scale a point other than the generator, and add the results.
+				var q Point
+				Expect(q.SetBytes(bs)).To(Succeed())
+				q.Scale(&q, &s)
+				var scaled gnark.G1Affine
+				scaled.ScalarMultiplication(&expected, toInt(&s))
+				q.PutBytes(bs)
+				Expect(bs).To(Equal(encode(&scaled)))
+
+				q.Add(&q, &p)
+				scaled.Add(&scaled, &expected)
+				q.PutBytes(bs)
+				Expect(bs).To(Equal(encode(&scaled)))
+			}
+		})
+
+		It("should report the order of the scalar field", func() {
+			// The order minus one is the negation of one.
+			m := FieldOrder()
+			m.Sub(m, big.NewInt(1))
+			bs := make([]byte, ScalarSize)
+			copy(bs[ScalarSize-len(m.Bytes()):], m.Bytes())
+			var x Scalar
+			Expect(x.SetBytes(bs)).To(Succeed())
+			one := NewScalarFromU16(1)
+			x.Add(&x, &one)
+			Expect(x.IsZero()).To(BeTrue())
+			Expect(CommitmentPointSize).To(Equal(PointSize))
+			Expect(CurveName()).ToNot(BeEmpty())
+		})
+	})
+
+	Context("sharing", func() {
+		It("should reconstruct the secret from any k shares", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+
+				rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+				recon := Open(shares[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an ErrInvalidThreshold when k is not between 1 and n", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for _, k := range []int{-1, 0, n + 1} {
+				err := ShareSecret(&shares, indices, RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+				err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+			}
+
+			err := ShareSecret(&shares, indices, RandomScalar(), n+1)
+			var kErr *shamir.ErrKTooLarge
+			Expect(errors.As(err, &kErr)).To(BeTrue())
+			Expect(*kErr).To(Equal(shamir.ErrKTooLarge{K: n + 1, N: n}))
+		})
+
+		It("should return an error when the source of randomness fails", func() {
+			shamir.SetRandomSource(bytes.NewReader(nil))
+			defer shamir.SetRandomSource(nil)
+
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			err := ShareSecret(&shares, indices, RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+
+			// With k = 1 only the decommitment is random.
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 1)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		})
+	})
+
+	Context("verifiable sharing", func() {
+		h := PedersenH()
+
+		It("should produce valid shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+				for j := range vshares {
+					Expect(IsValid(h, c, &vshares[j])).To(BeTrue())
+				}
+
+				recon := Open(vshares.Shares()[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should detect perturbed shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				// When k = 1 the index does not affect validity, so k is at
+				// least 2.
+				Expect(VShareSecret(&vshares, &c, indices, h, RandomScalar(), shamirutil.RandRange(2, n))).To(Succeed())
+				j := rand.Intn(n)
+				switch rand.Intn(3) {
+				case 0:
+					vshares[j].Share.Index = RandomScalar()
+				case 1:
+					vshares[j].Share.Value = RandomScalar()
+				default:
+					vshares[j].Decommitment = RandomScalar()
+				}
+				Expect(IsValid(h, c, &vshares[j])).To(BeFalse())
+			}
+		})
+
+		It("should be homomorphic under addition and scaling", func() {
+			indices := randomIndices(n)
+			vshares1 := make(VerifiableShares, n)
+			vshares2 := make(VerifiableShares, n)
+			c1 := NewCommitmentWithCapacity(n)
+			c2 := NewCommitmentWithCapacity(n)
+			sum := NewCommitmentWithCapacity(n)
+			scaled := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				Expect(VShareSecret(&vshares1, &c1, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				Expect(VShareSecret(&vshares2, &c2, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				scale := RandomScalar()
+				sum.Add(c1, c2)
+				scaled.Scale(c1, &scale)
+				for j := range indices {
+					var s VerifiableShare
+					s.Add(&vshares1[j], &vshares2[j])
+					Expect(IsValid(h, sum, &s)).To(BeTrue())
+					s.Scale(&vshares1[j], &scale)
+					Expect(IsValid(h, scaled, &s)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should derive a fixed Pedersen generator", func() {
+			h1, h2 := PedersenH(), PedersenH()
+			Expect(h1.Eq(&h2)).To(BeTrue())
+			Expect(h1.IsIdentity()).To(BeFalse())
+
+			h3 := PedersenHFromSeed([]byte(PedersenDomain))
+			Expect(h3.Eq(&h1)).To(BeTrue())
+			h4 := PedersenHFromSeed([]byte("another domain"))
+			Expect(h4.Eq(&h1)).To(BeFalse())
+			Expect(h4.IsIdentity()).To(BeFalse())
+		})
+	})
+
+	Context("surge marshalling", func() {
+		types := []reflect.Type{
+			reflect.TypeOf(Scalar{}),
+			reflect.TypeOf(Point{}),
+			reflect.TypeOf(Share{}),
+			reflect.TypeOf(Shares{}),
+			reflect.TypeOf(VerifiableShare{}),
+			reflect.TypeOf(VerifiableShares{}),
+			reflect.TypeOf(Commitment{}),
+		}
+
+		for _, t := range types {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
@@ -0,0 +1,584 @@
+// Package bn254 implements Shamir secret sharing and Pedersen verifiable
+// secret sharing over the BN254 (also known as alt_bn128 or BN256) pairing
+// friendly curve. Secrets and shares are elements of the BN254 scalar field,
+// which is the native field of Groth16 and PLONK circuits over BN254, so that
+// shares can be consumed inside such circuits without any non native field
+// arithmetic. Commitments are points in the G1 group, encoded in the same
+// format as the Ethereum precompiles (EIP-196).
+//
+// Field arithmetic uses the fr and fp packages of gnark-crypto, whose
+// assembly implementations on amd64 run in constant time. Scalar
+// multiplication uses complete projective formulas with a fixed window and
+// constant time table lookups, and affine conversion inverts by
+// exponentiation, so the sequence of operations does not depend on the
+// scalar. On other architectures gnark-crypto falls back to generic code
+// whose final reductions branch, and this backend should not be used there
+// where timing side channels on the dealer are a concern.
+//
+// The API mirrors the secp256k1 based API of the top level shamir package.
+package bn254
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	mrand "math/rand"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// ScalarSize is the number of bytes in the encoding of a Scalar.
+const ScalarSize = 32
+
+// PointSize is the number of bytes in the encoding of a Point. Points are
+// encoded as the big endian affine coordinates x and y, with the identity
+// encoded as 64 zero bytes, as in EIP-196.
+const PointSize = 64
+
+// CommitmentPointSize is the number of bytes in the encoding of each point of
+// a Commitment.
+const CommitmentPointSize = PointSize
+
+// CurveName returns the name of the group, "BN254".
+func CurveName() string {
+	return "BN254"
+}
+
+// FieldOrder returns the order of the scalar field, which is the order of the
+// group. The caller may modify the returned integer.
+func FieldOrder() *big.Int {
+	return fr.Modulus()
+}
+
+var (
+	// The moduli of the scalar and base fields as little endian 64 bit limbs,
+	// for checking that encodings are canonical.
+	orderLimbs = limbsFromBytes(fr.Modulus().Bytes())
+	fieldLimbs = limbsFromBytes(fp.Modulus().Bytes())
+
+	// The exponents that compute inverses by Fermat's little theorem. They are
+	// public, so exponentiation only branches on public data.
+	orderInvExp = new(big.Int).Sub(fr.Modulus(), big.NewInt(2))
+	fieldInvExp = new(big.Int).Sub(fp.Modulus(), big.NewInt(2))
+
+	// 2^128 as a scalar, in Montgomery form.
+	scalarShift = *(&fr.Element{0, 0, 1, 0}).ToMont()
+
+	// Three times the curve coefficient b = 3, as used by the complete
+	// addition formulas.
+	curveB3 = fp.NewElement(9)
+)
+
+// A Scalar is an element of the BN254 scalar field, that is, an integer
+// modulo the order of G1. The zero value is the zero scalar. Like
+// secp256k1.Fn, operations store their result in the receiver, and all
+// operations are safe for aliasing. IsZero and Eq, whose results are not
+// secret, and the check that an encoding is canonical are the only operations
+// that are not constant time.
+type Scalar struct {
+	// The scalar in the Montgomery form used by gnark-crypto.
+	e fr.Element
+}
+
+// NewScalarFromU16 returns a scalar equal to the given value.
+func NewScalarFromU16(v uint16) Scalar {
+	var s Scalar
+	s.SetU16(v)
+	return s
+}
+
+// RandomScalar returns a uniformly random scalar. It panics if the system
+// source of randomness fails.
+func RandomScalar() Scalar {
+	s, err := randomScalar(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Returns a uniformly random scalar using bytes from the given source.
+func randomScalar(r io.Reader) (Scalar, error) {
+	// Reducing 384 bits modulo the 254 bit order gives a negligible bias.
+	var bs [48]byte
+	if err := sharing.ReadRandom(r, bs[:]); err != nil {
+		return Scalar{}, err
+	}
+
+	// Every 128 bit chunk is less than the order, so the chunks can be
+	// combined with field arithmetic, most significant first.
+	var s Scalar
+	for i := 0; i < len(bs); i += 16 {
+		chunk := fr.Element{binary.BigEndian.Uint64(bs[i+8 : i+16]), binary.BigEndian.Uint64(bs[i : i+8])}
+		chunk.ToMont()
+		s.e.Mul(&s.e, &scalarShift)
+		s.e.Add(&s.e, &chunk)
+		chunk.SetZero()
+	}
+	for i := range bs {
+		bs[i] = 0
+	}
+	return s, nil
+}
+
+// SetU16 sets the scalar to the given value.
+func (s *Scalar) SetU16(v uint16) {
+	s.e.SetUint64(uint64(v))
+}
+
+// Clear sets the scalar to zero.
+func (s *Scalar) Clear() {
+	s.e.SetZero()
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (s *Scalar) Add(a, b *Scalar) {
+	s.e.Add(&a.e, &b.e)
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (s *Scalar) Sub(a, b *Scalar) {
+	s.e.Sub(&a.e, &b.e)
+}
+
+// Mul computes a * b and stores the result in the receiver.
+func (s *Scalar) Mul(a, b *Scalar) {
+	s.e.Mul(&a.e, &b.e)
+}
+
+// Negate computes -a and stores the result in the receiver.
+func (s *Scalar) Negate(a *Scalar) {
+	// fr.Element.Neg branches on zero, so subtract from zero instead.
+	var zero fr.Element
+	s.e.Sub(&zero, &a.e)
+}
+
+// Inverse computes the multiplicative inverse of a and stores the result in
+// the receiver. The inverse of zero is zero.
+func (s *Scalar) Inverse(a *Scalar) {
+	s.e.Exp(a.e, orderInvExp)
+}
+
+// BatchInvert replaces every element of the slice with its inverse, using a
+// single inversion, as for shamir.BatchInvert. The elements must be non-zero;
+// if any of them is zero, every element is set to zero.
+func BatchInvert(xs []Scalar) {
+	if len(xs) == 0 {
+		return
+	}
+	prefix := make([]Scalar, len(xs))
+	prefix[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		prefix[i].Mul(&prefix[i-1], &xs[i])
+	}
+	var inv, tmp Scalar
+	inv.Inverse(&prefix[len(xs)-1])
+	for i := len(xs) - 1; i > 0; i-- {
+		tmp.Mul(&inv, &prefix[i-1])
+		inv.Mul(&inv, &xs[i])
+		xs[i] = tmp
+	}
+	xs[0] = inv
+}
+
+// IsZero returns true if the scalar is zero.
+func (s *Scalar) IsZero() bool {
+	return s.e.IsZero()
+}
+
+// Eq returns true if the two scalars are equal.
+func (s *Scalar) Eq(other *Scalar) bool {
+	return s.e.Equal(&other.e)
+}
+
+// PutBytes writes the 32 byte big endian encoding of the scalar into the
+// destination slice, which must have length at least ScalarSize.
+func (s *Scalar) PutBytes(dst []byte) {
+	bs := s.e.Bytes()
+	copy(dst[:ScalarSize], bs[:])
+}
+
+// SetBytes sets the scalar from its 32 byte big endian encoding. An error is
+// returned if the encoded integer is not less than the order.
+func (s *Scalar) SetBytes(bs []byte) error {
+	if len(bs) != ScalarSize {
+		return fmt.Errorf("invalid scalar length: expected %v bytes, got %v", ScalarSize, len(bs))
+	}
+	x := limbsFromBytes(bs)
+	if !lessThan(&x, &orderLimbs) {
+		return errors.New("scalar is not less than the order")
+	}
+	s.e = fr.Element(x)
+	s.e.ToMont()
+	return nil
+}
+
+// Generate implements the quick.Generator interface.
+func (s Scalar) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomScalar())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (s Scalar) SizeHint() int { return ScalarSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (s Scalar) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	s.PutBytes(buf)
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (s *Scalar) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := s.SetBytes(buf[:ScalarSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// Reads 32 big endian bytes as little endian 64 bit limbs.
+func limbsFromBytes(bs []byte) [4]uint64 {
+	return [4]uint64{
+		binary.BigEndian.Uint64(bs[24:32]),
+		binary.BigEndian.Uint64(bs[16:24]),
+		binary.BigEndian.Uint64(bs[8:16]),
+		binary.BigEndian.Uint64(bs[0:8]),
+	}
+}
+
+// Returns true if x < m, without branching on x.
+func lessThan(x, m *[4]uint64) bool {
+	var borrow uint64
+	_, borrow = bits.Sub64(x[0], m[0], 0)
+	_, borrow = bits.Sub64(x[1], m[1], borrow)
+	_, borrow = bits.Sub64(x[2], m[2], borrow)
+	_, borrow = bits.Sub64(x[3], m[3], borrow)
+	return borrow == 1
+}
+
+// A Point is a point in the G1 group of BN254, which is the group of points on
+// the curve y^2 = x^3 + 3 over the base field. The zero value is the point at
+// infinity, which is the identity element. Operations store their result in
+// the receiver, and are safe for aliasing.
+type Point struct {
+	// The affine coordinates in the Montgomery form used by gnark-crypto. The
+	// point at infinity is represented by (0, 0), which is not on the curve.
+	x, y fp.Element
+}
+
+// A point in projective coordinates (X : Y : Z), representing the affine
+// point (X/Z, Y/Z). The point at infinity is (0 : 1 : 0). The complete
+// addition formulas handle every pair of points, including the identity and
+// equal points, without any special cases.
+type projective struct {
+	x, y, z fp.Element
+}
+
+func (p *Point) projective() projective {
+	one := fp.One()
+	var zero fp.Element
+
+	// The identity is mapped to (0 : 1 : 0), and every other point to
+	// (x : y : 1), without branching.
+	mask := isZeroMask(&p.x) & isZeroMask(&p.y)
+	q := projective{x: p.x}
+	selectFp(&q.y, &one, &p.y, mask)
+	selectFp(&q.z, &zero, &one, mask)
+	return q
+}
+
+func (p *Point) setProjective(q *projective) {
+	// Inverting by exponentiation takes the same time for every Z, and maps
+	// the Z = 0 of the identity to zero, which gives its (0, 0)
+	// representation.
+	var zInv fp.Element
+	zInv.Exp(q.z, fieldInvExp)
+	p.x.Mul(&q.x, &zInv)
+	p.y.Mul(&q.y, &zInv)
+}
+
+// Complete addition for curves with a = 0, using algorithm 7 of "Complete
+// addition formulas for prime order elliptic curves" by Renes, Costello and
+// Batina. The receiver may alias either input.
+func (q *projective) add(a, b *projective) {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fp.Element
+	t0.Mul(&a.x, &b.x)
+	t1.Mul(&a.y, &b.y)
+	t2.Mul(&a.z, &b.z)
+	t3.Add(&a.x, &a.y)
+	t4.Add(&b.x, &b.y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.y, &a.z)
+	x3.Add(&b.y, &b.z)
+	t4.Mul(&t4, &x3)
+	x3.Add(&t1, &t2)
+	t4.Sub(&t4, &x3)
+	x3.Add(&a.x, &a.z)
+	y3.Add(&b.x, &b.z)
+	x3.Mul(&x3, &y3)
+	y3.Add(&t0, &t2)
+	y3.Sub(&x3, &y3)
+	x3.Double(&t0)
+	t0.Add(&x3, &t0)
+	t2.Mul(&t2, &curveB3)
+	z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	y3.Mul(&y3, &curveB3)
+	x3.Mul(&t4, &y3)
+	t2.Mul(&t3, &t1)
+	x3.Sub(&t2, &x3)
+	y3.Mul(&y3, &t0)
+	t1.Mul(&t1, &z3)
+	y3.Add(&t1, &y3)
+	t0.Mul(&t0, &t3)
+	z3.Mul(&z3, &t4)
+	z3.Add(&z3, &t0)
+	q.x, q.y, q.z = x3, y3, z3
+}
+
+// Sets the receiver to table[i], reading every entry of the table so that the
+// memory access pattern does not depend on i.
+func (q *projective) lookup(table *[16]projective, i byte) {
+	*q = projective{}
+	for j := range table {
+		mask := -uint64(subtle.ConstantTimeByteEq(byte(j), i))
+		for k := range q.x {
+			q.x[k] |= table[j].x[k] & mask
+			q.y[k] |= table[j].y[k] & mask
+			q.z[k] |= table[j].z[k] & mask
+		}
+	}
+}
+
+// Returns all ones if the element is zero, and zero otherwise.
+func isZeroMask(e *fp.Element) uint64 {
+	v := e[0] | e[1] | e[2] | e[3]
+	return ((v | -v) >> 63) - 1
+}
+
+// Sets dst to a if the mask is all ones, and to b if it is zero.
+func selectFp(dst, a, b *fp.Element, mask uint64) {
+	for i := range dst {
+		dst[i] = a[i]&mask | b[i]&^mask
+	}
+}
+
+// Sets the point to the one with the given big endian x coordinate and the y
+// coordinate with the given parity. False is returned, and the point is left
+// unchanged, if there is no such point.
+func (p *Point) setX(xBytes []byte, odd bool) bool {
+	xLimbs := limbsFromBytes(xBytes)
+	if !lessThan(&xLimbs, &fieldLimbs) {
+		return false
+	}
+	x := fp.Element(xLimbs)
+	x.ToMont()
+	rhs := curveRHS(&x)
+	var y fp.Element
+	if y.Sqrt(&rhs) == nil {
+		return false
+	}
+	if (y.Bytes()[31]&1 == 1) != odd {
+		var zero fp.Element
+		y.Sub(&zero, &y)
+	}
+	p.x, p.y = x, y
+	return true
+}
+
+// Returns x^3 + 3.
+func curveRHS(x *fp.Element) fp.Element {
+	b := fp.NewElement(3)
+	var rhs fp.Element
+	rhs.Square(x)
+	rhs.Mul(&rhs, x)
+	rhs.Add(&rhs, &b)
+	return rhs
+}
+
+// NewGenerator returns the standard generator (1, 2) of G1.
+func NewGenerator() Point {
+	return Point{x: fp.NewElement(1), y: fp.NewElement(2)}
+}
+
+// NewIdentity returns the identity element of the group.
+func NewIdentity() Point {
+	return Point{}
+}
+
+// RandomPoint returns a uniformly random point in G1. It panics if the system
+// source of randomness fails.
+func RandomPoint() Point {
+	var p Point
+	s := RandomScalar()
+	p.BaseExp(&s)
+	return p
+}
+
+// BaseExp computes the scalar multiple of the generator and stores the result
+// in the receiver.
+func (p *Point) BaseExp(s *Scalar) {
+	g := NewGenerator()
+	p.Scale(&g, s)
+}
+
+// Scale computes the scalar multiple of a and stores the result in the
+// receiver. It runs in constant time with respect to the scalar.
+func (p *Point) Scale(a *Point, s *Scalar) {
+	// Precompute the multiples 0*a to 15*a, and then process the scalar in
+	// four bit windows, most significant first.
+	var table [16]projective
+	table[0] = projective{y: fp.One()}
+	table[1] = a.projective()
+	for i := 2; i < len(table); i++ {
+		table[i].add(&table[i-1], &table[1])
+	}
+
+	bs := s.e.Bytes()
+	acc := table[0]
+	var t projective
+	for i := 0; i < 2*len(bs); i++ {
+		for j := 0; j < 4; j++ {
+			acc.add(&acc, &acc)
+		}
+		w := bs[i/2] >> (4 * uint(1-i%2)) & 0xf
+		t.lookup(&table, w)
+		acc.add(&acc, &t)
+	}
+	for i := range bs {
+		bs[i] = 0
+	}
+	p.setProjective(&acc)
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (p *Point) Add(a, b *Point) {
+	pa, pb := a.projective(), b.projective()
+	pa.add(&pa, &pb)
+	p.setProjective(&pa)
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (p *Point) Sub(a, b *Point) {
+	var neg Point
+	neg.Negate(b)
+	p.Add(a, &neg)
+}
+
+// Negate computes -a and stores the result in the receiver.
+func (p *Point) Negate(a *Point) {
+	// The negation of the (0, 0) identity is itself.
+	var zero fp.Element
+	p.x = a.x
+	p.y.Sub(&zero, &a.y)
+}
+
+// Eq returns true if the two points are equal.
+func (p *Point) Eq(other *Point) bool {
+	return *p == *other
+}
+
+// IsIdentity returns true if the point is the identity element.
+func (p *Point) IsIdentity() bool {
+	return *p == Point{}
+}
+
+// PutBytes writes the 64 byte encoding of the point into the destination
+// slice, which must have length at least PointSize.
+func (p *Point) PutBytes(dst []byte) {
+	x, y := p.x.Bytes(), p.y.Bytes()
+	copy(dst[:32], x[:])
+	copy(dst[32:PointSize], y[:])
+}
+
+// SetBytes sets the point from its 64 byte encoding. An error is returned if
+// the coordinates are not reduced or the point is not on the curve.
+func (p *Point) SetBytes(bs []byte) error {
+	if len(bs) != PointSize {
+		return fmt.Errorf("invalid point length: expected %v bytes, got %v", PointSize, len(bs))
+	}
+	xLimbs, yLimbs := limbsFromBytes(bs[:32]), limbsFromBytes(bs[32:])
+	if !lessThan(&xLimbs, &fieldLimbs) || !lessThan(&yLimbs, &fieldLimbs) {
+		return errors.New("invalid point encoding: coordinate not reduced")
+	}
+	q := Point{x: fp.Element(xLimbs), y: fp.Element(yLimbs)}
+	if q.IsIdentity() {
+		*p = q
+		return nil
+	}
+	q.x.ToMont()
+	q.y.ToMont()
+	var y2 fp.Element
+	y2.Square(&q.y)
+	if rhs := curveRHS(&q.x); !y2.Equal(&rhs) {
+		return errors.New("invalid point encoding: point not on curve")
+	}
+	*p = q
+	return nil
+}
+
+// Generate implements the quick.Generator interface.
+func (p Point) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomPoint())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (p Point) SizeHint() int { return PointSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (p Point) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	p.PutBytes(buf)
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (p *Point) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := p.SetBytes(buf[:PointSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// PedersenHFromSeed derives a Pedersen generator from the given domain
+// separation tag by try and increment: for a one byte counter starting at
+// zero, the candidate x coordinate is the SHA-256 hash of the domain followed
+// by the counter, and the first candidate that is less than the field modulus
+// and on the curve is used together with its even y coordinate. Since G1 has
+// cofactor one, the result is in G1, and nobody knows its discrete logarithm
+// with respect to the generator.
+func PedersenHFromSeed(domain []byte) Point {
+	input := make([]byte, len(domain)+1)
+	copy(input, domain)
+	for ctr := 0; ctr < 256; ctr++ {
+		input[len(domain)] = byte(ctr)
+		hash := sha256.Sum256(input)
+		var h Point
+		if h.setX(hash[:], false) {
+			return h
+		}
+	}
+	panic("could not derive pedersen generator")
+}
@@ -0,0 +1,70 @@
+package shamir
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Canonicalize puts the shares into a canonical form, so that two parties that
+// hold the same set of shares, possibly in different orders and with repeats,
+// end up with identical slices, and hence identical encodings. The shares are
+// sorted in ascending order of index, and any share that is equal to another
+// is removed. Shares that have the same index but are otherwise different are
+// all kept, ordered by value and then by decommitment, since dropping either
+// one would lose information; use a VShareMap to reject them instead.
+func (vshares *VerifiableShares) Canonicalize() {
+	s := *vshares
+	sort.Slice(s, func(i, j int) bool {
+		return compareVShares(&s[i], &s[j]) < 0
+	})
+	n := 0
+	for i := range s {
+		if n > 0 && s[n-1].Eq(&s[i]) {
+			continue
+		}
+		s[n] = s[i]
+		n++
+	}
+	for i := n; i < len(s); i++ {
+		s[i].Zero()
+	}
+	*vshares = s[:n]
+}
+
+// CanonicalDigest returns a digest of the set of shares that does not depend
+// on their order or on repeated shares. It is the SHA-256 hash of
+//
+//	tag || version || len(shares) || shares[0] || ... || shares[len(shares)-1]
+//
+// for the canonical form of the shares as given by Canonicalize, where tag is
+// the string "renproject/shamir/verifiable shares" prefixed by its length, and
+// each share is encoded as for Dealing.TranscriptDigest. The shares
+// themselves are not modified.
+func (vshares VerifiableShares) CanonicalDigest() [32]byte {
+	canonical := make(VerifiableShares, len(vshares))
+	copy(canonical, vshares)
+	defer canonical.Zero()
+	canonical.Canonicalize()
+
+	h := newDigest(vsharesDigestTag)
+	writeVShares(h, canonical)
+	return sum(h)
+}
+
+func compareVShares(a, b *VerifiableShare) int {
+	var x, y [secp256k1.FnSizeMarshalled]byte
+	for _, pair := range [...][2]*secp256k1.Fn{
+		{&a.Share.Index, &b.Share.Index},
+		{&a.Share.Value, &b.Share.Value},
+		{&a.Decommitment, &b.Decommitment},
+	} {
+		pair[0].PutB32(x[:])
+		pair[1].PutB32(y[:])
+		if c := bytes.Compare(x[:], y[:]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package shamir_test
+
+import (
+	"bytes"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Canonical verifiable shares", func() {
+	trials := 20
+
+	deal := func(n, k int) VerifiableShares {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, RandomIndices(n), PedersenH(), secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares
+	}
+
+	// Returns a shuffled copy of the shares with some of them repeated.
+	scramble := func(vshares VerifiableShares) VerifiableShares {
+		scrambled := make(VerifiableShares, len(vshares))
+		copy(scrambled, vshares)
+		for i := 0; i < RandRange(0, len(vshares)); i++ {
+			scrambled = append(scrambled, vshares[rand.Intn(len(vshares))])
+		}
+		rand.Shuffle(len(scrambled), func(i, j int) {
+			scrambled[i], scrambled[j] = scrambled[j], scrambled[i]
+		})
+		return scrambled
+	}
+
+	It("should sort the shares by index and remove repeats", func() {
+		for i := 0; i < trials; i++ {
+			vshares := deal(RandRange(3, 20), 3)
+			canonical := scramble(vshares)
+			canonical.Canonicalize()
+
+			Expect(len(canonical)).To(Equal(len(vshares)))
+			for j := 1; j < len(canonical); j++ {
+				prev := KeyOf(&canonical[j-1].Share.Index)
+				cur := KeyOf(&canonical[j].Share.Index)
+				Expect(bytes.Compare(prev[:], cur[:])).To(Equal(-1))
+			}
+		}
+	})
+
+	It("should give identical encodings and digests for the same set", func() {
+		for i := 0; i < trials; i++ {
+			vshares := deal(RandRange(3, 20), 3)
+			a, b := scramble(vshares), scramble(vshares)
+			Expect(a.CanonicalDigest()).To(Equal(b.CanonicalDigest()))
+
+			a.Canonicalize()
+			b.Canonicalize()
+			aBytes, err := surge.ToBinary(a)
+			Expect(err).ToNot(HaveOccurred())
+			bBytes, err := surge.ToBinary(b)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(aBytes).To(Equal(bBytes))
+		}
+	})
+
+	It("should not modify the shares when computing the digest", func() {
+		vshares := scramble(deal(10, 3))
+		original := make(VerifiableShares, len(vshares))
+		copy(original, vshares)
+		_ = vshares.CanonicalDigest()
+		Expect(vshares).To(Equal(original))
+	})
+
+	It("should give different digests for different sets", func() {
+		vshares := deal(10, 3)
+		Expect(vshares.CanonicalDigest()).ToNot(Equal(vshares[:9].CanonicalDigest()))
+
+		modified := make(VerifiableShares, len(vshares))
+		copy(modified, vshares)
+		modified[4].Decommitment = secp256k1.RandomFn()
+		Expect(vshares.CanonicalDigest()).ToNot(Equal(modified.CanonicalDigest()))
+	})
+
+	It("should keep shares with the same index but different values", func() {
+		vshares := deal(5, 3)
+		conflicting := vshares[2]
+		conflicting.Share.Value = secp256k1.RandomFn()
+		a := append(VerifiableShares{conflicting}, vshares...)
+		b := append(vshares, conflicting)
+
+		a.Canonicalize()
+		b.Canonicalize()
+		Expect(len(a)).To(Equal(6))
+		Expect(a).To(Equal(b))
+	})
+
+	It("should handle empty sets", func() {
+		var vshares VerifiableShares
+		vshares.Canonicalize()
+		Expect(vshares).To(BeEmpty())
+		Expect(vshares.CanonicalDigest()).To(Equal(VerifiableShares{}.CanonicalDigest()))
+	})
+})
@@ -0,0 +1,436 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// CBOR encoding
+//
+// Share, VerifiableShare, Commitment, SharingMetadata and TaggedShare can be
+// encoded in CBOR (RFC 8949) using the core deterministic encoding, so that
+// they can be embedded in COSE and CWT based messages, and so that equal
+// values always have identical encodings. Each value is wrapped in a CBOR tag
+// that identifies its type, and the fields of structured values are held in a
+// map with small integer labels, in the style of COSE headers:
+//
+//	Share           CBORTagShare(1: index, 2: value)
+//	VerifiableShare CBORTagVerifiableShare(1: index, 2: value, 3: decommitment)
+//	Commitment      CBORTagCommitment([point, ...])
+//	SharingMetadata CBORTagSharingMetadata(1: n, 2: k, 3: curve, 4: h digest)
+//	TaggedShare     CBORTagTaggedShare(1: index, 2: value, 3: decommitment, 4: tag)
+//
+// Scalars are 32 byte big endian byte strings, points are 33 byte compressed
+// byte strings as for the surge encoding, n, k and curve are unsigned
+// integers, and the digest and tag are byte strings. Every label is required.
+//
+// Decoding is strict: anything other than the deterministic encoding of a
+// valid value is rejected, including integers that are not in their shortest
+// form, indefinite lengths, labels that are unknown, missing or out of order,
+// scalars that are not reduced and trailing bytes.
+
+// The CBOR tag numbers of the types that can be encoded. They are in the first
+// come first served range of the IANA registry, and spell "sh" followed by the
+// envelope type of the value.
+const (
+	CBORTagShare           = cborTagBase | wireTypeShare
+	CBORTagVerifiableShare = cborTagBase | wireTypeVerifiableShare
+	CBORTagCommitment      = cborTagBase | wireTypeCommitment
+	CBORTagSharingMetadata = cborTagBase | wireTypeSharingMetadata
+	CBORTagTaggedShare     = cborTagBase | wireTypeTaggedShare
+)
+
+const cborTagBase = 0x73680000
+
+// The CBOR major types that are used by the encoding.
+const (
+	cborMajorUint  = 0
+	cborMajorBytes = 2
+	cborMajorArray = 4
+	cborMajorMap   = 5
+	cborMajorTag   = 6
+)
+
+// MarshalCBOR returns the deterministic CBOR encoding of the share.
+func (s Share) MarshalCBOR() ([]byte, error) {
+	buf := appendCBORHead(nil, cborMajorTag, CBORTagShare)
+	buf = appendCBORHead(buf, cborMajorMap, 2)
+	buf = appendCBORFn(buf, 1, &s.Index)
+	return appendCBORFn(buf, 2, &s.Value), nil
+}
+
+// UnmarshalCBOR decodes the deterministic CBOR encoding of a share.
+func (s *Share) UnmarshalCBOR(buf []byte) error {
+	d := cborDecoder{buf}
+	var share Share
+	if err := d.readStart(CBORTagShare, 2); err != nil {
+		return err
+	}
+	if err := d.readFn(1, &share.Index); err != nil {
+		return err
+	}
+	if err := d.readFn(2, &share.Value); err != nil {
+		return err
+	}
+	if err := d.readEnd(); err != nil {
+		return err
+	}
+	*s = share
+	return nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the share.
+func (vs VerifiableShare) MarshalCBOR() ([]byte, error) {
+	buf := appendCBORHead(nil, cborMajorTag, CBORTagVerifiableShare)
+	buf = appendCBORHead(buf, cborMajorMap, 3)
+	return appendCBORVShare(buf, &vs), nil
+}
+
+// UnmarshalCBOR decodes the deterministic CBOR encoding of a verifiable
+// share.
+func (vs *VerifiableShare) UnmarshalCBOR(buf []byte) error {
+	d := cborDecoder{buf}
+	var vshare VerifiableShare
+	if err := d.readStart(CBORTagVerifiableShare, 3); err != nil {
+		return err
+	}
+	if err := d.readVShare(&vshare); err != nil {
+		return err
+	}
+	if err := d.readEnd(); err != nil {
+		return err
+	}
+	*vs = vshare
+	return nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the commitment.
+func (c Commitment) MarshalCBOR() ([]byte, error) {
+	buf := appendCBORHead(nil, cborMajorTag, CBORTagCommitment)
+	buf = appendCBORHead(buf, cborMajorArray, uint64(len(c)))
+	var bs [secp256k1.PointSizeMarshalled]byte
+	for i := range c {
+		c[i].PutBytes(bs[:])
+		buf = appendCBORHead(buf, cborMajorBytes, uint64(len(bs)))
+		buf = append(buf, bs[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalCBOR decodes the deterministic CBOR encoding of a commitment.
+func (c *Commitment) UnmarshalCBOR(buf []byte) error {
+	d := cborDecoder{buf}
+	if err := d.readTag(CBORTagCommitment); err != nil {
+		return err
+	}
+	l, err := d.readHead(cborMajorArray)
+	if err != nil {
+		return err
+	}
+	// Every point takes at least one byte, which bounds the allocation.
+	if l > uint64(len(d.buf)) {
+		return surge.ErrUnexpectedEndOfBuffer
+	}
+	com := make(Commitment, l)
+	for i := range com {
+		bs, err := d.readBytes()
+		if err != nil {
+			return err
+		}
+		if len(bs) != secp256k1.PointSizeMarshalled {
+			return fmt.Errorf("invalid point length %v", len(bs))
+		}
+		if err := com[i].SetBytes(bs); err != nil {
+			return err
+		}
+	}
+	if err := d.readEnd(); err != nil {
+		return err
+	}
+	*c = com
+	return nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the metadata.
+func (m SharingMetadata) MarshalCBOR() ([]byte, error) {
+	buf := appendCBORHead(nil, cborMajorTag, CBORTagSharingMetadata)
+	buf = appendCBORHead(buf, cborMajorMap, 4)
+	buf = appendCBORHead(buf, cborMajorUint, 1)
+	buf = appendCBORHead(buf, cborMajorUint, uint64(m.N))
+	buf = appendCBORHead(buf, cborMajorUint, 2)
+	buf = appendCBORHead(buf, cborMajorUint, uint64(m.K))
+	buf = appendCBORHead(buf, cborMajorUint, 3)
+	buf = appendCBORHead(buf, cborMajorUint, uint64(m.Curve))
+	buf = appendCBORHead(buf, cborMajorUint, 4)
+	buf = appendCBORHead(buf, cborMajorBytes, uint64(len(m.HDigest)))
+	return append(buf, m.HDigest[:]...), nil
+}
+
+// UnmarshalCBOR decodes the deterministic CBOR encoding of sharing metadata.
+func (m *SharingMetadata) UnmarshalCBOR(buf []byte) error {
+	d := cborDecoder{buf}
+	if err := d.readStart(CBORTagSharingMetadata, 4); err != nil {
+		return err
+	}
+	var meta SharingMetadata
+	n, err := d.readUint(1, 1<<32-1)
+	if err != nil {
+		return err
+	}
+	k, err := d.readUint(2, 1<<32-1)
+	if err != nil {
+		return err
+	}
+	curve, err := d.readUint(3, 1<<8-1)
+	if err != nil {
+		return err
+	}
+	meta.N, meta.K, meta.Curve = uint32(n), uint32(k), CurveID(curve)
+	if err := d.readLabel(4); err != nil {
+		return err
+	}
+	bs, err := d.readBytes()
+	if err != nil {
+		return err
+	}
+	if len(bs) != len(meta.HDigest) {
+		return fmt.Errorf("invalid digest length %v", len(bs))
+	}
+	copy(meta.HDigest[:], bs)
+	if err := d.readEnd(); err != nil {
+		return err
+	}
+	*m = meta
+	return nil
+}
+
+// MarshalCBOR returns the deterministic CBOR encoding of the tagged share.
+func (ts TaggedShare) MarshalCBOR() ([]byte, error) {
+	if len(ts.Tag) > MaxShareTagLen {
+		return nil, fmt.Errorf("tag too long: expected at most %v bytes, got %v", MaxShareTagLen, len(ts.Tag))
+	}
+	buf := appendCBORHead(nil, cborMajorTag, CBORTagTaggedShare)
+	buf = appendCBORHead(buf, cborMajorMap, 4)
+	buf = appendCBORVShare(buf, &ts.Tagged)
+	buf = appendCBORHead(buf, cborMajorUint, 4)
+	buf = appendCBORHead(buf, cborMajorBytes, uint64(len(ts.Tag)))
+	return append(buf, ts.Tag...), nil
+}
+
+// UnmarshalCBOR decodes the deterministic CBOR encoding of a tagged share.
+func (ts *TaggedShare) UnmarshalCBOR(buf []byte) error {
+	d := cborDecoder{buf}
+	var tagged TaggedShare
+	if err := d.readStart(CBORTagTaggedShare, 4); err != nil {
+		return err
+	}
+	if err := d.readVShare(&tagged.Tagged); err != nil {
+		return err
+	}
+	if err := d.readLabel(4); err != nil {
+		return err
+	}
+	tag, err := d.readBytes()
+	if err != nil {
+		return err
+	}
+	if len(tag) > MaxShareTagLen {
+		return fmt.Errorf("tag too long: expected at most %v bytes, got %v", MaxShareTagLen, len(tag))
+	}
+	tagged.Tag = append([]byte{}, tag...)
+	if err := d.readEnd(); err != nil {
+		return err
+	}
+	*ts = tagged
+	return nil
+}
+
+// Appends the head of a data item with the given major type and argument,
+// using the shortest form of the argument.
+func appendCBORHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= 0xFF:
+		return append(buf, major|24, byte(arg))
+	case arg <= 0xFFFF:
+		return append(buf, major|25, byte(arg>>8), byte(arg))
+	case arg <= 0xFFFFFFFF:
+		return append(buf, major|26, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	default:
+		return append(
+			buf, major|27,
+			byte(arg>>56), byte(arg>>48), byte(arg>>40), byte(arg>>32),
+			byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg),
+		)
+	}
+}
+
+// Appends the map entry with the given label and scalar value.
+func appendCBORFn(buf []byte, label uint64, x *secp256k1.Fn) []byte {
+	buf = appendCBORHead(buf, cborMajorUint, label)
+	buf = appendCBORHead(buf, cborMajorBytes, uint64(secp256k1.FnSizeMarshalled))
+	var bs [secp256k1.FnSizeMarshalled]byte
+	x.PutB32(bs[:])
+	return append(buf, bs[:]...)
+}
+
+// Appends the map entries for the index, value and decommitment of the share,
+// which are shared by VerifiableShare and TaggedShare.
+func appendCBORVShare(buf []byte, vs *VerifiableShare) []byte {
+	buf = appendCBORFn(buf, 1, &vs.Share.Index)
+	buf = appendCBORFn(buf, 2, &vs.Share.Value)
+	return appendCBORFn(buf, 3, &vs.Decommitment)
+}
+
+// A cborDecoder reads data items from the front of a buffer, and rejects any
+// encoding that is not deterministic.
+type cborDecoder struct {
+	buf []byte
+}
+
+// Reads the head of a data item, which must have the given major type, and
+// returns its argument.
+func (d *cborDecoder) readHead(major byte) (uint64, error) {
+	if len(d.buf) == 0 {
+		return 0, surge.ErrUnexpectedEndOfBuffer
+	}
+	if d.buf[0]>>5 != major {
+		return 0, fmt.Errorf("unexpected cbor major type %v, expected %v", d.buf[0]>>5, major)
+	}
+	info := d.buf[0] & 0x1F
+	if info < 24 {
+		d.buf = d.buf[1:]
+		return uint64(info), nil
+	}
+	if info > 27 {
+		return 0, fmt.Errorf("unsupported cbor additional information %v", info)
+	}
+	size := 1 << (info - 24)
+	if len(d.buf) < 1+size {
+		return 0, surge.ErrUnexpectedEndOfBuffer
+	}
+	var arg uint64
+	for _, b := range d.buf[1 : 1+size] {
+		arg = arg<<8 | uint64(b)
+	}
+	// The argument must not fit in a shorter form.
+	if (size == 1 && arg < 24) || (size > 1 && arg < 1<<(4*size)) {
+		return 0, fmt.Errorf("cbor argument %v is not in its shortest form", arg)
+	}
+	d.buf = d.buf[1+size:]
+	return arg, nil
+}
+
+// Reads a tag, which must have the given number.
+func (d *cborDecoder) readTag(tag uint64) error {
+	n, err := d.readHead(cborMajorTag)
+	if err != nil {
+		return err
+	}
+	if n != tag {
+		return fmt.Errorf("unexpected cbor tag %v, expected %v", n, tag)
+	}
+	return nil
+}
+
+// Reads a tag with the given number followed by the head of a map with the
+// given number of entries.
+func (d *cborDecoder) readStart(tag, entries uint64) error {
+	if err := d.readTag(tag); err != nil {
+		return err
+	}
+	n, err := d.readHead(cborMajorMap)
+	if err != nil {
+		return err
+	}
+	if n != entries {
+		return fmt.Errorf("unexpected number of map entries %v, expected %v", n, entries)
+	}
+	return nil
+}
+
+// Reads a map label, which must be the given label. Since every label is
+// required and the labels are read in ascending order, this also ensures that
+// the map keys are in the deterministic order and are not repeated.
+func (d *cborDecoder) readLabel(label uint64) error {
+	l, err := d.readHead(cborMajorUint)
+	if err != nil {
+		return err
+	}
+	if l != label {
+		return fmt.Errorf("unexpected map label %v, expected %v", l, label)
+	}
+	return nil
+}
+
+// Reads a byte string. The returned slice aliases the buffer.
+func (d *cborDecoder) readBytes() ([]byte, error) {
+	l, err := d.readHead(cborMajorBytes)
+	if err != nil {
+		return nil, err
+	}
+	if l > uint64(len(d.buf)) {
+		return nil, surge.ErrUnexpectedEndOfBuffer
+	}
+	bs := d.buf[:l]
+	d.buf = d.buf[l:]
+	return bs, nil
+}
+
+// Reads the map entry with the given label and an unsigned integer value that
+// is at most max.
+func (d *cborDecoder) readUint(label, max uint64) (uint64, error) {
+	if err := d.readLabel(label); err != nil {
+		return 0, err
+	}
+	n, err := d.readHead(cborMajorUint)
+	if err != nil {
+		return 0, err
+	}
+	if n > max {
+		return 0, fmt.Errorf("value %v of label %v is too large", n, label)
+	}
+	return n, nil
+}
+
+// Reads the map entry with the given label and a scalar value, which must be
+// reduced.
+func (d *cborDecoder) readFn(label uint64, x *secp256k1.Fn) error {
+	if err := d.readLabel(label); err != nil {
+		return err
+	}
+	bs, err := d.readBytes()
+	if err != nil {
+		return err
+	}
+	if len(bs) != secp256k1.FnSizeMarshalled {
+		return fmt.Errorf("invalid scalar length %v", len(bs))
+	}
+	if x.SetB32(bs) {
+		return fmt.Errorf("scalar of label %v is not reduced", label)
+	}
+	return nil
+}
+
+// Reads the map entries for the index, value and decommitment of a share.
+func (d *cborDecoder) readVShare(vs *VerifiableShare) error {
+	if err := d.readFn(1, &vs.Share.Index); err != nil {
+		return err
+	}
+	if err := d.readFn(2, &vs.Share.Value); err != nil {
+		return err
+	}
+	return d.readFn(3, &vs.Decommitment)
+}
+
+// Checks that the whole buffer has been read.
+func (d *cborDecoder) readEnd() error {
+	if len(d.buf) != 0 {
+		return fmt.Errorf("%v unexpected trailing bytes", len(d.buf))
+	}
+	return nil
+}
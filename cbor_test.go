@@ -0,0 +1,137 @@
+package shamir_test
+
+import (
+	"bytes"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("CBOR encoding", func() {
+	trials := 20
+
+	It("should decode what was encoded", func() {
+		for i := 0; i < trials; i++ {
+			share := NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			vshare := NewVerifiableShare(share, secp256k1.RandomFn())
+			c := RandomCommitment(RandRange(0, 10))
+			h := secp256k1.RandomPoint()
+			meta := NewSharingMetadata(RandRange(1, 100), RandRange(1, 100), &h)
+			ts := NewTaggedShare(&vshare, []byte("epoch 1"))
+
+			bs, err := share.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			var decodedShare Share
+			Expect(decodedShare.UnmarshalCBOR(bs)).To(Succeed())
+			Expect(decodedShare.Eq(&share)).To(BeTrue())
+
+			bs, err = vshare.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			var decodedVShare VerifiableShare
+			Expect(decodedVShare.UnmarshalCBOR(bs)).To(Succeed())
+			Expect(decodedVShare.Eq(&vshare)).To(BeTrue())
+
+			bs, err = c.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			var decodedC Commitment
+			Expect(decodedC.UnmarshalCBOR(bs)).To(Succeed())
+			Expect(decodedC.Eq(c)).To(BeTrue())
+
+			bs, err = meta.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			var decodedMeta SharingMetadata
+			Expect(decodedMeta.UnmarshalCBOR(bs)).To(Succeed())
+			Expect(decodedMeta).To(Equal(meta))
+
+			bs, err = ts.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			var decodedTS TaggedShare
+			Expect(decodedTS.UnmarshalCBOR(bs)).To(Succeed())
+			Expect(decodedTS.Tagged.Eq(&ts.Tagged)).To(BeTrue())
+			Expect(decodedTS.Tag).To(Equal(ts.Tag))
+		}
+	})
+
+	It("should use the deterministic encoding", func() {
+		var index, value secp256k1.Fn
+		index.SetU16(1)
+		value.SetU16(2)
+		bs, err := NewShare(index, value).MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+
+		expected := []byte{0xDA, 0x73, 0x68, 0x00, 0x01, 0xA2}
+		expected = append(expected, 0x01, 0x58, 0x20)
+		expected = append(expected, make([]byte, 31)...)
+		expected = append(expected, 0x01)
+		expected = append(expected, 0x02, 0x58, 0x20)
+		expected = append(expected, make([]byte, 31)...)
+		expected = append(expected, 0x02)
+		Expect(bs).To(Equal(expected))
+
+		meta := SharingMetadata{N: 10, K: 300, Curve: CurveSecp256k1}
+		bs, err = meta.MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bs[:14]).To(Equal([]byte{
+			0xDA, 0x73, 0x68, 0x00, 0x04, 0xA4,
+			0x01, 0x0A,
+			0x02, 0x19, 0x01, 0x2C,
+			0x03, 0x01,
+		}))
+	})
+
+	It("should reject encodings that are not deterministic or not valid", func() {
+		share := NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+		bs, err := share.MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+		var decoded Share
+
+		// Truncated and trailing bytes.
+		Expect(decoded.UnmarshalCBOR(bs[:len(bs)-1])).ToNot(Succeed())
+		Expect(decoded.UnmarshalCBOR(append(bs, 0))).ToNot(Succeed())
+
+		// The wrong tag.
+		wrongTag := append([]byte{}, bs...)
+		wrongTag[4] = 0x02
+		Expect(decoded.UnmarshalCBOR(wrongTag)).ToNot(Succeed())
+
+		// A label that is not in its shortest form.
+		longLabel := append([]byte{}, bs[:6]...)
+		longLabel = append(longLabel, 0x18, 0x01)
+		longLabel = append(longLabel, bs[7:]...)
+		Expect(decoded.UnmarshalCBOR(longLabel)).ToNot(Succeed())
+
+		// Labels out of order.
+		entry := len(bs[6:]) / 2
+		swapped := append([]byte{}, bs[:6]...)
+		swapped = append(swapped, bs[6+entry:]...)
+		swapped = append(swapped, bs[6:6+entry]...)
+		Expect(decoded.UnmarshalCBOR(swapped)).ToNot(Succeed())
+
+		// A scalar that is not reduced.
+		unreduced := append([]byte{}, bs...)
+		copy(unreduced[9:41], bytes.Repeat([]byte{0xFF}, 32))
+		Expect(decoded.UnmarshalCBOR(unreduced)).ToNot(Succeed())
+
+		// The original encoding still decodes.
+		Expect(decoded.UnmarshalCBOR(bs)).To(Succeed())
+		Expect(decoded.Eq(&share)).To(BeTrue())
+	})
+
+	It("should reject commitments with invalid points", func() {
+		c := RandomCommitment(3)
+		bs, err := c.MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+
+		invalid := append([]byte{}, bs...)
+		// There is no point with an x coordinate of zero. The first point
+		// follows the tag, the array head and the byte string head.
+		copy(invalid[8:41], make([]byte, 33))
+		invalid[8] = 0x02
+		var decoded Commitment
+		Expect(decoded.UnmarshalCBOR(invalid)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// A report is the result of auditing a transcript.
+type report struct {
+	Format string  `json:"format"`
+	OK     bool    `json:"ok"`
+	Checks []check `json:"checks"`
+}
+
+// A check is the result of a single check of a transcript. The index is set
+// for the checks of individual shares.
+type check struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Index string `json:"index,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func (r *report) add(name string, index *secp256k1.Fn, err error) {
+	c := check{Name: name, OK: err == nil}
+	if index != nil {
+		var bs [secp256k1.FnSizeMarshalled]byte
+		index.PutB32(bs[:])
+		c.Index = hex.EncodeToString(bs[:])
+	}
+	if err != nil {
+		c.Error = err.Error()
+		r.OK = false
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// Performs every check that applies to the transcript. The report is OK if all
+// of them pass.
+func audit(t *transcript) report {
+	r := report{Format: t.format, OK: true, Checks: []check{}}
+
+	if t.metadata != nil {
+		r.add("metadata", nil, t.metadata.CheckCommitment(&t.h, t.commitment))
+	}
+	if len(t.commitment) > 0 || len(t.shares) > 0 {
+		auditVSS(&r, t)
+	}
+	if t.pvss != nil {
+		k := t.k
+		if k == 0 {
+			k = t.pvss.dealing.Commitment.Len()
+		}
+		r.add("pvss", nil, t.pvss.dealing.Verify(t.pvss.indices, t.pvss.pubKeys, k, t.pvss.domain))
+	}
+	return r
+}
+
+func auditVSS(r *report, t *transcript) {
+	k := t.k
+	if k == 0 {
+		k = t.commitment.Len()
+	}
+	var err error
+	switch {
+	case t.commitment.Len() == 0:
+		err = fmt.Errorf("empty commitment")
+	case t.commitment.Len() != k:
+		err = fmt.Errorf("commitment has %v points, expected k = %v", t.commitment.Len(), k)
+	}
+	r.add("commitment", nil, err)
+
+	indices := make([]secp256k1.Fn, len(t.shares))
+	for i := range t.shares {
+		indices[i] = t.shares[i].Share.Index
+	}
+	r.add("indices", nil, shamir.ValidateIndices(indices))
+
+	verifier := shamir.NewVerifier(t.h, t.commitment)
+	for i := range t.shares {
+		err = nil
+		if !verifier.Verify(&t.shares[i]) {
+			err = fmt.Errorf("share %v is not valid", i)
+		}
+		r.add("share", &t.shares[i].Share.Index, err)
+	}
+
+	if k > 0 {
+		r.add("degree", nil, shamir.VerifyDegree(t.shares, k))
+	}
+}
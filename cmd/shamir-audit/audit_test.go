@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/pvss"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transcript audit", func() {
+	n, k := 5, 3
+	domain := []byte("audit test")
+
+	dealVSS := func() shamir.Dealing {
+		indices := shamirutil.RandomIndices(n)
+		d, err := shamir.Deal(indices, shamir.PedersenH(), secp256k1.RandomFn(), k)
+		Expect(err).ToNot(HaveOccurred())
+		return d
+	}
+
+	dealPVSS := func() (pvss.Dealing, []secp256k1.Fn, []secp256k1.Point) {
+		indices := shamirutil.RandomIndices(n)
+		pubKeys := make([]secp256k1.Point, n)
+		for i := range pubKeys {
+			priv := secp256k1.RandomFn()
+			pubKeys[i].BaseExp(&priv)
+		}
+		d, _, err := pvss.Deal(indices, pubKeys, k, domain)
+		Expect(err).ToNot(HaveOccurred())
+		return d, indices, pubKeys
+	}
+
+	fnHex := func(x secp256k1.Fn) string {
+		var bs [32]byte
+		x.PutB32(bs[:])
+		return hex.EncodeToString(bs[:])
+	}
+
+	pointHex := func(p secp256k1.Point) string {
+		var bs [33]byte
+		p.PutBytes(bs[:])
+		return hex.EncodeToString(bs[:])
+	}
+
+	toJSON := func(d *shamir.Dealing) []byte {
+		jt := jsonTranscript{K: k}
+		for _, p := range d.Commitment {
+			jt.Commitment = append(jt.Commitment, pointHex(p))
+		}
+		for _, vs := range d.Shares {
+			jt.Shares = append(jt.Shares, jsonVShare{
+				Index:        fnHex(vs.Share.Index),
+				Value:        fnHex(vs.Share.Value),
+				Decommitment: fnHex(vs.Decommitment),
+			})
+		}
+		bs, err := json.Marshal(jt)
+		Expect(err).ToNot(HaveOccurred())
+		return bs
+	}
+
+	toCBOR := func(d *shamir.Dealing, pd *pvss.Dealing, indices []secp256k1.Fn, pubKeys []secp256k1.Point) []byte {
+		appendBytes := func(buf, bs []byte) []byte {
+			return append(append(buf, 0x58, byte(len(bs))), bs...)
+		}
+		c, err := d.Commitment.MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+		buf := []byte{0xA4, 0x02, byte(k), 0x03}
+		buf = append(buf, c...)
+		buf = append(buf, 0x04, 0x80|byte(len(d.Shares)))
+		for _, vs := range d.Shares {
+			bs, err := vs.MarshalCBOR()
+			Expect(err).ToNot(HaveOccurred())
+			buf = append(buf, bs...)
+		}
+
+		buf = append(buf, 0x06, 0xA7, 0x01)
+		buf = appendBytes(buf, domain)
+		buf = append(buf, 0x02, 0x80|byte(n))
+		for i := range indices {
+			var bs [32]byte
+			indices[i].PutB32(bs[:])
+			buf = appendBytes(buf, bs[:])
+		}
+		buf = append(buf, 0x03, 0x80|byte(n))
+		for i := range pubKeys {
+			var bs [33]byte
+			pubKeys[i].PutBytes(bs[:])
+			buf = appendBytes(buf, bs[:])
+		}
+		c, err = pd.Commitment.MarshalCBOR()
+		Expect(err).ToNot(HaveOccurred())
+		buf = append(append(buf, 0x04), c...)
+		buf = append(buf, 0x05, 0x80|byte(n))
+		for i := range pd.Encrypted {
+			var bs [33]byte
+			pd.Encrypted[i].PutBytes(bs[:])
+			buf = appendBytes(buf, bs[:])
+		}
+		var bs [32]byte
+		pd.Proof.Challenge.PutB32(bs[:])
+		buf = appendBytes(append(buf, 0x06), bs[:])
+		buf = append(buf, 0x07, 0x80|byte(n))
+		for i := range pd.Proof.Responses {
+			pd.Proof.Responses[i].PutB32(bs[:])
+			buf = appendBytes(buf, bs[:])
+		}
+		return buf
+	}
+
+	failed := func(r report) []string {
+		names := []string{}
+		for _, c := range r.Checks {
+			if !c.OK {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+
+	It("should pass a valid JSON transcript", func() {
+		d := dealVSS()
+		t, err := decodeTranscript(toJSON(&d), "auto")
+		Expect(err).ToNot(HaveOccurred())
+		r := audit(t)
+		Expect(r.Format).To(Equal("json"))
+		Expect(r.OK).To(BeTrue())
+		// The commitment, indices and degree checks, and one for each share.
+		Expect(r.Checks).To(HaveLen(3 + n))
+	})
+
+	It("should pass a valid CBOR transcript", func() {
+		d := dealVSS()
+		pd, indices, pubKeys := dealPVSS()
+		t, err := decodeTranscript(toCBOR(&d, &pd, indices, pubKeys), "auto")
+		Expect(err).ToNot(HaveOccurred())
+		r := audit(t)
+		Expect(r.Format).To(Equal("cbor"))
+		Expect(r.OK).To(BeTrue())
+		Expect(r.Checks).To(HaveLen(4 + n))
+	})
+
+	It("should report invalid shares", func() {
+		d := dealVSS()
+		d.Shares[1].Share.Value = secp256k1.RandomFn()
+		t, err := decodeTranscript(toJSON(&d), "json")
+		Expect(err).ToNot(HaveOccurred())
+		r := audit(t)
+		Expect(r.OK).To(BeFalse())
+		Expect(failed(r)).To(Equal([]string{"share", "degree"}))
+		for _, c := range r.Checks {
+			if c.Name == "share" && !c.OK {
+				Expect(c.Index).To(Equal(fnHex(d.Shares[1].Share.Index)))
+			}
+		}
+	})
+
+	It("should report a commitment of the wrong length and duplicate indices", func() {
+		d := dealVSS()
+		d.Shares[2] = d.Shares[0]
+		t, err := decodeTranscript(toJSON(&d), "json")
+		Expect(err).ToNot(HaveOccurred())
+		t.k = k + 1
+		// The shares are still valid for the commitment.
+		Expect(failed(audit(t))).To(Equal([]string{"commitment", "indices", "degree"}))
+	})
+
+	It("should report an invalid pvss dealing", func() {
+		d := dealVSS()
+		pd, indices, pubKeys := dealPVSS()
+		pd.Encrypted[0], pd.Encrypted[1] = pd.Encrypted[1], pd.Encrypted[0]
+		t, err := decodeTranscript(toCBOR(&d, &pd, indices, pubKeys), "cbor")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed(audit(t))).To(Equal([]string{"pvss"}))
+	})
+
+	It("should reject malformed transcripts", func() {
+		d := dealVSS()
+		pd, indices, pubKeys := dealPVSS()
+		bs := toCBOR(&d, &pd, indices, pubKeys)
+
+		_, err := decodeTranscript(bs[:len(bs)-1], "auto")
+		Expect(err).To(HaveOccurred())
+		_, err = decodeTranscript(append(bs, 0), "auto")
+		Expect(err).To(HaveOccurred())
+		_, err = decodeTranscript(bs, "json")
+		Expect(err).To(HaveOccurred())
+		_, err = decodeTranscript([]byte(`{"k": 3, "shares": [{"index": "01"}]}`), "auto")
+		Expect(err).To(HaveOccurred())
+		_, err = decodeTranscript([]byte(`{"unknown": 1}`), "auto")
+		Expect(err).To(HaveOccurred())
+		_, err = decodeTranscript(toJSON(&d), "xml")
+		Expect(err).To(HaveOccurred())
+	})
+})
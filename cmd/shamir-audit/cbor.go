@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// The CBOR major types that are used by transcripts.
+const (
+	cborMajorUint  = 0
+	cborMajorBytes = 2
+	cborMajorArray = 4
+	cborMajorMap   = 5
+	cborMajorTag   = 6
+)
+
+// The maximum nesting depth of a transcript.
+const cborMaxDepth = 8
+
+// A cborItem is a parsed CBOR data item. Only the types that are used by
+// transcripts are supported. The raw encoding of the item is kept, so that
+// the items that are values of the shamir package can be decoded by their
+// UnmarshalCBOR methods, which do the strict checks.
+type cborItem struct {
+	raw   []byte
+	major byte
+	arg   uint64
+	bytes []byte
+	elems []cborItem
+	keys  []uint64
+}
+
+// Parses a single data item, which must take up the whole buffer.
+func parseCBOR(buf []byte) (*cborItem, error) {
+	item, rest, err := parseCBORItem(buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%v unexpected trailing bytes", len(rest))
+	}
+	return &item, nil
+}
+
+func parseCBORItem(buf []byte, depth int) (cborItem, []byte, error) {
+	if depth > cborMaxDepth {
+		return cborItem{}, nil, fmt.Errorf("cbor nested too deeply")
+	}
+	if len(buf) == 0 {
+		return cborItem{}, nil, fmt.Errorf("unexpected end of cbor")
+	}
+	item := cborItem{major: buf[0] >> 5}
+	info := buf[0] & 0x1F
+	rest := buf[1:]
+	switch {
+	case info < 24:
+		item.arg = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(rest) < size {
+			return cborItem{}, nil, fmt.Errorf("unexpected end of cbor")
+		}
+		for _, b := range rest[:size] {
+			item.arg = item.arg<<8 | uint64(b)
+		}
+		rest = rest[size:]
+	default:
+		return cborItem{}, nil, fmt.Errorf("unsupported cbor additional information %v", info)
+	}
+
+	switch item.major {
+	case cborMajorUint:
+	case cborMajorBytes:
+		if item.arg > uint64(len(rest)) {
+			return cborItem{}, nil, fmt.Errorf("unexpected end of cbor")
+		}
+		item.bytes, rest = rest[:item.arg], rest[item.arg:]
+	case cborMajorArray, cborMajorMap, cborMajorTag:
+		n := item.arg
+		if item.major == cborMajorTag {
+			n = 1
+		}
+		// Every item takes at least one byte, which bounds the allocation.
+		if n > uint64(len(rest)) {
+			return cborItem{}, nil, fmt.Errorf("unexpected end of cbor")
+		}
+		item.elems = make([]cborItem, 0, n)
+		for i := uint64(0); i < n; i++ {
+			if item.major == cborMajorMap {
+				var key cborItem
+				var err error
+				if key, rest, err = parseCBORItem(rest, depth+1); err != nil {
+					return cborItem{}, nil, err
+				}
+				if key.major != cborMajorUint {
+					return cborItem{}, nil, fmt.Errorf("cbor map label is not an unsigned integer")
+				}
+				item.keys = append(item.keys, key.arg)
+			}
+			var elem cborItem
+			var err error
+			if elem, rest, err = parseCBORItem(rest, depth+1); err != nil {
+				return cborItem{}, nil, err
+			}
+			item.elems = append(item.elems, elem)
+		}
+	default:
+		return cborItem{}, nil, fmt.Errorf("unsupported cbor major type %v", item.major)
+	}
+	item.raw = buf[:len(buf)-len(rest)]
+	return item, rest, nil
+}
+
+// Returns the entries of a map, whose labels must be distinct and in the range
+// min to max.
+func (item *cborItem) asMap(min, max uint64) (map[uint64]*cborItem, error) {
+	if item.major != cborMajorMap {
+		return nil, fmt.Errorf("expected a cbor map")
+	}
+	entries := make(map[uint64]*cborItem, len(item.keys))
+	for i, key := range item.keys {
+		if key < min || key > max {
+			return nil, fmt.Errorf("unknown label %v", key)
+		}
+		if _, ok := entries[key]; ok {
+			return nil, fmt.Errorf("repeated label %v", key)
+		}
+		entries[key] = &item.elems[i]
+	}
+	return entries, nil
+}
+
+func (item *cborItem) asUint() (uint64, error) {
+	if item.major != cborMajorUint {
+		return 0, fmt.Errorf("expected an unsigned integer")
+	}
+	return item.arg, nil
+}
+
+func (item *cborItem) asBytes() ([]byte, error) {
+	if item.major != cborMajorBytes {
+		return nil, fmt.Errorf("expected a byte string")
+	}
+	return item.bytes, nil
+}
+
+func (item *cborItem) asFn() (secp256k1.Fn, error) {
+	bs, err := item.asBytes()
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	return fnFromBytes(bs)
+}
+
+func (item *cborItem) asPoint() (secp256k1.Point, error) {
+	bs, err := item.asBytes()
+	if err != nil {
+		return secp256k1.Point{}, err
+	}
+	return pointFromBytes(bs)
+}
+
+func (item *cborItem) asFns() ([]secp256k1.Fn, error) {
+	if item.major != cborMajorArray {
+		return nil, fmt.Errorf("expected an array")
+	}
+	xs := make([]secp256k1.Fn, len(item.elems))
+	for i := range item.elems {
+		var err error
+		if xs[i], err = item.elems[i].asFn(); err != nil {
+			return nil, err
+		}
+	}
+	return xs, nil
+}
+
+func (item *cborItem) asPoints() ([]secp256k1.Point, error) {
+	if item.major != cborMajorArray {
+		return nil, fmt.Errorf("expected an array")
+	}
+	ps := make([]secp256k1.Point, len(item.elems))
+	for i := range item.elems {
+		var err error
+		if ps[i], err = item.elems[i].asPoint(); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
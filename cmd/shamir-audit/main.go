@@ -0,0 +1,101 @@
+// Command shamir-audit checks a transcript of a dealing, and prints a report
+// of every check in JSON.
+//
+// Usage:
+//
+//	shamir-audit [-format auto|json|cbor] [transcript]
+//
+// The transcript is read from the given file, or from standard input if no
+// file is given. It can hold a Pedersen verifiable sharing, given by its
+// commitment and any number of its shares, a publicly verifiable sharing from
+// the pvss package, or both. The checks that are performed are:
+//
+//   - metadata: the sharing metadata, if given, is valid and describes the
+//     commitment and the Pedersen parameter;
+//   - commitment: the commitment has k points;
+//   - indices: the indices of the shares are non-zero and distinct;
+//   - share: each share is valid with regard to the commitment, with one check
+//     for each share;
+//   - degree: the shares lie on polynomials of degree less than k, which does
+//     not depend on the commitment;
+//   - pvss: the publicly verifiable dealing is valid for its recipients.
+//
+// The command exits with status 0 if every check passes, 1 if any check
+// fails, and 2 if the transcript can not be read.
+//
+// In a JSON transcript, scalars and points are hex encoded as in the
+// testvectors package, and the domain of the pvss dealing is a string. Every
+// field is optional, except that shares need a commitment; h defaults to
+// shamir.PedersenH() and k defaults to the length of the commitment.
+//
+//	{
+//	  "h": "<point>",
+//	  "k": 3,
+//	  "metadata": {"n": 5, "k": 3, "curve": 1, "hDigest": "<32 bytes>"},
+//	  "commitment": ["<point>", ...],
+//	  "shares": [{"index": "<scalar>", "value": "<scalar>", "decommitment": "<scalar>"}, ...],
+//	  "pvss": {
+//	    "domain": "<string>",
+//	    "indices": ["<scalar>", ...],
+//	    "publicKeys": ["<point>", ...],
+//	    "commitment": ["<point>", ...],
+//	    "encrypted": ["<point>", ...],
+//	    "challenge": "<scalar>",
+//	    "responses": ["<scalar>", ...]
+//	  }
+//	}
+//
+// A CBOR transcript is a map with the same fields under integer labels, in
+// which the commitment, shares and metadata use the tagged CBOR encodings of
+// the shamir package, and scalars, points and the domain are byte strings:
+//
+//	1: h, 2: k, 3: commitment, 4: [share, ...], 5: metadata,
+//	6: {1: domain, 2: indices, 3: public keys, 4: commitment,
+//	    5: encrypted shares, 6: challenge, 7: responses}
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "auto", "format of the transcript: auto, json or cbor")
+	flag.Parse()
+	if flag.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: shamir-audit [-format auto|json|cbor] [transcript]")
+		os.Exit(2)
+	}
+
+	var buf []byte
+	var err error
+	if flag.NArg() == 1 {
+		buf, err = ioutil.ReadFile(flag.Arg(0))
+	} else {
+		buf, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot read transcript: %v\n", err)
+		os.Exit(2)
+	}
+
+	t, err := decodeTranscript(buf, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot decode transcript: %v\n", err)
+		os.Exit(2)
+	}
+	report := audit(t)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write report: %v\n", err)
+		os.Exit(2)
+	}
+	if !report.OK {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/pvss"
+)
+
+// A transcript is a decoded dealing.
+type transcript struct {
+	format     string
+	h          secp256k1.Point
+	k          int
+	metadata   *shamir.SharingMetadata
+	commitment shamir.Commitment
+	shares     shamir.VerifiableShares
+	pvss       *pvssTranscript
+}
+
+// A pvssTranscript is a publicly verifiable dealing together with the indices
+// and public keys of its recipients.
+type pvssTranscript struct {
+	domain  []byte
+	indices []secp256k1.Fn
+	pubKeys []secp256k1.Point
+	dealing pvss.Dealing
+}
+
+// Decodes a transcript in the given format. In the auto format, the transcript
+// is JSON if its first non-space byte is an opening brace, and CBOR otherwise.
+func decodeTranscript(buf []byte, format string) (*transcript, error) {
+	if format == "auto" {
+		format = "cbor"
+		if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = "json"
+		}
+	}
+
+	var t *transcript
+	var err error
+	switch format {
+	case "json":
+		t, err = decodeJSONTranscript(buf)
+	case "cbor":
+		t, err = decodeCBORTranscript(buf)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.format = format
+	if len(t.shares) > 0 && len(t.commitment) == 0 {
+		return nil, fmt.Errorf("shares without a commitment")
+	}
+	return t, nil
+}
+
+type jsonTranscript struct {
+	H          string        `json:"h"`
+	K          int           `json:"k"`
+	Metadata   *jsonMetadata `json:"metadata"`
+	Commitment []string      `json:"commitment"`
+	Shares     []jsonVShare  `json:"shares"`
+	PVSS       *jsonPVSS     `json:"pvss"`
+}
+
+type jsonMetadata struct {
+	N       uint32 `json:"n"`
+	K       uint32 `json:"k"`
+	Curve   uint8  `json:"curve"`
+	HDigest string `json:"hDigest"`
+}
+
+type jsonVShare struct {
+	Index        string `json:"index"`
+	Value        string `json:"value"`
+	Decommitment string `json:"decommitment"`
+}
+
+type jsonPVSS struct {
+	Domain     string   `json:"domain"`
+	Indices    []string `json:"indices"`
+	PublicKeys []string `json:"publicKeys"`
+	Commitment []string `json:"commitment"`
+	Encrypted  []string `json:"encrypted"`
+	Challenge  string   `json:"challenge"`
+	Responses  []string `json:"responses"`
+}
+
+func decodeJSONTranscript(buf []byte) (*transcript, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+	var jt jsonTranscript
+	if err := dec.Decode(&jt); err != nil {
+		return nil, err
+	}
+
+	t := &transcript{h: shamir.PedersenH(), k: jt.K}
+	var err error
+	if jt.H != "" {
+		if t.h, err = decodePoint(jt.H); err != nil {
+			return nil, fmt.Errorf("h: %v", err)
+		}
+	}
+	if jt.Metadata != nil {
+		m := shamir.SharingMetadata{N: jt.Metadata.N, K: jt.Metadata.K, Curve: shamir.CurveID(jt.Metadata.Curve)}
+		digest, err := hex.DecodeString(jt.Metadata.HDigest)
+		if err != nil || len(digest) != len(m.HDigest) {
+			return nil, fmt.Errorf("metadata: invalid h digest")
+		}
+		copy(m.HDigest[:], digest)
+		t.metadata = &m
+	}
+	if t.commitment, err = decodePoints(jt.Commitment); err != nil {
+		return nil, fmt.Errorf("commitment: %v", err)
+	}
+	t.shares = make(shamir.VerifiableShares, len(jt.Shares))
+	for i, s := range jt.Shares {
+		vs := &t.shares[i]
+		for _, field := range [...]struct {
+			str string
+			dst *secp256k1.Fn
+		}{{s.Index, &vs.Share.Index}, {s.Value, &vs.Share.Value}, {s.Decommitment, &vs.Decommitment}} {
+			if *field.dst, err = decodeFn(field.str); err != nil {
+				return nil, fmt.Errorf("share %v: %v", i, err)
+			}
+		}
+	}
+
+	if jt.PVSS != nil {
+		p := &pvssTranscript{domain: []byte(jt.PVSS.Domain)}
+		if p.indices, err = decodeFns(jt.PVSS.Indices); err != nil {
+			return nil, fmt.Errorf("pvss indices: %v", err)
+		}
+		if p.pubKeys, err = decodePoints(jt.PVSS.PublicKeys); err != nil {
+			return nil, fmt.Errorf("pvss public keys: %v", err)
+		}
+		if p.dealing.Commitment, err = decodePoints(jt.PVSS.Commitment); err != nil {
+			return nil, fmt.Errorf("pvss commitment: %v", err)
+		}
+		if p.dealing.Encrypted, err = decodePoints(jt.PVSS.Encrypted); err != nil {
+			return nil, fmt.Errorf("pvss encrypted shares: %v", err)
+		}
+		if p.dealing.Proof.Challenge, err = decodeFn(jt.PVSS.Challenge); err != nil {
+			return nil, fmt.Errorf("pvss challenge: %v", err)
+		}
+		if p.dealing.Proof.Responses, err = decodeFns(jt.PVSS.Responses); err != nil {
+			return nil, fmt.Errorf("pvss responses: %v", err)
+		}
+		t.pvss = p
+	}
+	return t, nil
+}
+
+func decodeCBORTranscript(buf []byte) (*transcript, error) {
+	root, err := parseCBOR(buf)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := root.asMap(1, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &transcript{h: shamir.PedersenH()}
+	if item, ok := entries[1]; ok {
+		if t.h, err = item.asPoint(); err != nil {
+			return nil, fmt.Errorf("h: %v", err)
+		}
+	}
+	if item, ok := entries[2]; ok {
+		k, err := item.asUint()
+		if err != nil || k > 1<<16 {
+			return nil, fmt.Errorf("k: invalid threshold")
+		}
+		t.k = int(k)
+	}
+	if item, ok := entries[3]; ok {
+		if err := t.commitment.UnmarshalCBOR(item.raw); err != nil {
+			return nil, fmt.Errorf("commitment: %v", err)
+		}
+	}
+	if item, ok := entries[4]; ok {
+		if item.major != cborMajorArray {
+			return nil, fmt.Errorf("shares: expected an array")
+		}
+		t.shares = make(shamir.VerifiableShares, len(item.elems))
+		for i := range item.elems {
+			if err := t.shares[i].UnmarshalCBOR(item.elems[i].raw); err != nil {
+				return nil, fmt.Errorf("share %v: %v", i, err)
+			}
+		}
+	}
+	if item, ok := entries[5]; ok {
+		t.metadata = new(shamir.SharingMetadata)
+		if err := t.metadata.UnmarshalCBOR(item.raw); err != nil {
+			return nil, fmt.Errorf("metadata: %v", err)
+		}
+	}
+	if item, ok := entries[6]; ok {
+		if t.pvss, err = decodeCBORPVSS(item); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func decodeCBORPVSS(item *cborItem) (*pvssTranscript, error) {
+	entries, err := item.asMap(1, 7)
+	if err != nil {
+		return nil, fmt.Errorf("pvss: %v", err)
+	}
+	for label := uint64(1); label <= 7; label++ {
+		if _, ok := entries[label]; !ok {
+			return nil, fmt.Errorf("pvss: missing label %v", label)
+		}
+	}
+
+	p := &pvssTranscript{}
+	if p.domain, err = entries[1].asBytes(); err != nil {
+		return nil, fmt.Errorf("pvss domain: %v", err)
+	}
+	if p.indices, err = entries[2].asFns(); err != nil {
+		return nil, fmt.Errorf("pvss indices: %v", err)
+	}
+	if p.pubKeys, err = entries[3].asPoints(); err != nil {
+		return nil, fmt.Errorf("pvss public keys: %v", err)
+	}
+	if err := p.dealing.Commitment.UnmarshalCBOR(entries[4].raw); err != nil {
+		return nil, fmt.Errorf("pvss commitment: %v", err)
+	}
+	if p.dealing.Encrypted, err = entries[5].asPoints(); err != nil {
+		return nil, fmt.Errorf("pvss encrypted shares: %v", err)
+	}
+	if p.dealing.Proof.Challenge, err = entries[6].asFn(); err != nil {
+		return nil, fmt.Errorf("pvss challenge: %v", err)
+	}
+	if p.dealing.Proof.Responses, err = entries[7].asFns(); err != nil {
+		return nil, fmt.Errorf("pvss responses: %v", err)
+	}
+	return p, nil
+}
+
+func decodeFn(str string) (secp256k1.Fn, error) {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	return fnFromBytes(bs)
+}
+
+func decodeFns(strs []string) ([]secp256k1.Fn, error) {
+	xs := make([]secp256k1.Fn, len(strs))
+	for i := range strs {
+		var err error
+		if xs[i], err = decodeFn(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return xs, nil
+}
+
+func decodePoint(str string) (secp256k1.Point, error) {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return secp256k1.Point{}, err
+	}
+	return pointFromBytes(bs)
+}
+
+func decodePoints(strs []string) ([]secp256k1.Point, error) {
+	ps := make([]secp256k1.Point, len(strs))
+	for i := range strs {
+		var err error
+		if ps[i], err = decodePoint(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+func fnFromBytes(bs []byte) (secp256k1.Fn, error) {
+	var x secp256k1.Fn
+	if len(bs) != secp256k1.FnSizeMarshalled {
+		return x, fmt.Errorf("expected %v bytes for a scalar, got %v", secp256k1.FnSizeMarshalled, len(bs))
+	}
+	if x.SetB32(bs) {
+		return x, fmt.Errorf("scalar is not less than the group order")
+	}
+	return x, nil
+}
+
+func pointFromBytes(bs []byte) (secp256k1.Point, error) {
+	var p secp256k1.Point
+	if len(bs) != secp256k1.PointSizeMarshalled {
+		return p, fmt.Errorf("expected %v bytes for a point, got %v", secp256k1.PointSizeMarshalled, len(bs))
+	}
+	return p, p.SetBytes(bs)
+}
@@ -0,0 +1,125 @@
+// Package commitcache caches the precomputed verifiers of commitments, so that
+// nodes that verify many shares from a few dealers do not repeat the setup of
+// a shamir.Verifier every time they receive an identical commitment.
+//
+// Verifiers are keyed by the digest of the commitment, as given by
+// shamir.Commitment.Hash, together with the digest of the Pedersen parameter
+// h, and the least recently used verifier is evicted once the cache is full.
+// Since the digest is collision resistant, a verifier is only ever reused for
+// a commitment and h that are equal to the ones it was built for.
+package commitcache
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// A key identifies a commitment and Pedersen parameter.
+type key struct {
+	c, h [32]byte
+}
+
+type entry struct {
+	key      key
+	verifier *shamir.Verifier
+}
+
+// A Cache is a bounded cache of verifiers. It is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	// The entries, from most to least recently used.
+	order   *list.List
+	entries map[key]*list.Element
+
+	hits, misses uint64
+}
+
+// New returns an empty cache that holds at most the given number of
+// verifiers. Each verifier holds a table of points for each point of the
+// commitment, so the capacity should take the threshold into account.
+//
+// Panics: This function will panic if the capacity is less than 1.
+func New(capacity int) *Cache {
+	if capacity < 1 {
+		panic(fmt.Sprintf("invalid capacity: expected capacity >= 1, got %v", capacity))
+	}
+	return &Cache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[key]*list.Element, capacity),
+	}
+}
+
+// Verifier returns a verifier for the given commitment and Pedersen parameter
+// h, constructing it and adding it to the cache if it is not already cached.
+// The returned verifier must not be modified, since it may be shared with
+// other callers; Verifier.Verify does not modify it and is safe to call
+// concurrently.
+func (cache *Cache) Verifier(h secp256k1.Point, c shamir.Commitment) *shamir.Verifier {
+	k := key{c: c.Hash(), h: shamir.PedersenHDigest(&h)}
+
+	cache.mu.Lock()
+	if elem, ok := cache.entries[k]; ok {
+		cache.order.MoveToFront(elem)
+		cache.hits++
+		cache.mu.Unlock()
+		return elem.Value.(*entry).verifier
+	}
+	cache.misses++
+	cache.mu.Unlock()
+
+	// The setup is done without holding the lock so that other lookups are
+	// not blocked by it. Concurrent misses for the same key may both build a
+	// verifier, in which case the first one to be inserted is kept.
+	v := shamir.NewVerifier(h, c)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if elem, ok := cache.entries[k]; ok {
+		cache.order.MoveToFront(elem)
+		return elem.Value.(*entry).verifier
+	}
+	cache.entries[k] = cache.order.PushFront(&entry{key: k, verifier: &v})
+	if cache.order.Len() > cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*entry).key)
+	}
+	return &v
+}
+
+// IsValid returns true when the given verifiable share is valid with regard to
+// the given commitment, and false otherwise, using a cached verifier for the
+// commitment. It gives the same result as shamir.IsValid, except that it
+// returns false rather than panicking when the commitment is empty.
+func (cache *Cache) IsValid(h secp256k1.Point, c *shamir.Commitment, vshare *shamir.VerifiableShare) bool {
+	return cache.Verifier(h, *c).Verify(vshare)
+}
+
+// Len returns the number of verifiers in the cache.
+func (cache *Cache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.order.Len()
+}
+
+// Stats returns the number of lookups that found a cached verifier, and the
+// number that had to construct one.
+func (cache *Cache) Stats() (hits, misses uint64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.hits, cache.misses
+}
+
+// Purge removes all verifiers from the cache.
+func (cache *Cache) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.order.Init()
+	cache.entries = make(map[key]*list.Element, cache.capacity)
+}
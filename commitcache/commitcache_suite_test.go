@@ -0,0 +1,13 @@
+package commitcache_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCommitcache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Commitcache Suite")
+}
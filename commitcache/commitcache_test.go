@@ -0,0 +1,104 @@
+package commitcache_test
+
+import (
+	"sync"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/commitcache"
+)
+
+var _ = Describe("Commitment cache", func() {
+	n, k := 10, 4
+	h := shamir.PedersenH()
+
+	deal := func() (shamir.VerifiableShares, shamir.Commitment) {
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		Expect(shamir.VShareSecret(&vshares, &c, shamirutil.RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares, c
+	}
+
+	It("should agree with IsValid", func() {
+		cache := New(4)
+		vshares, c := deal()
+		for i := range vshares {
+			Expect(cache.IsValid(h, &c, &vshares[i])).To(BeTrue())
+			shamirutil.PerturbValue(&vshares[i])
+			Expect(cache.IsValid(h, &c, &vshares[i])).To(BeFalse())
+		}
+	})
+
+	It("should reuse the verifier for an identical commitment", func() {
+		cache := New(4)
+		_, c := deal()
+		v := cache.Verifier(h, c)
+
+		// An identical commitment that was, for example, unmarshalled from
+		// another message.
+		copied := append(shamir.Commitment{}, c...)
+		Expect(cache.Verifier(h, copied)).To(BeIdenticalTo(v))
+		hits, misses := cache.Stats()
+		Expect(hits).To(Equal(uint64(1)))
+		Expect(misses).To(Equal(uint64(1)))
+
+		other := shamir.PedersenHFromSeed([]byte("other"))
+		Expect(cache.Verifier(other, c)).ToNot(BeIdenticalTo(v))
+		Expect(cache.Len()).To(Equal(2))
+	})
+
+	It("should evict the least recently used verifier", func() {
+		cache := New(2)
+		_, c1 := deal()
+		_, c2 := deal()
+		_, c3 := deal()
+		v1 := cache.Verifier(h, c1)
+		cache.Verifier(h, c2)
+		Expect(cache.Verifier(h, c1)).To(BeIdenticalTo(v1))
+
+		cache.Verifier(h, c3)
+		Expect(cache.Len()).To(Equal(2))
+		Expect(cache.Verifier(h, c1)).To(BeIdenticalTo(v1))
+		_, misses := cache.Stats()
+		cache.Verifier(h, c2)
+		_, missesAfter := cache.Stats()
+		Expect(missesAfter).To(Equal(misses + 1))
+
+		cache.Purge()
+		Expect(cache.Len()).To(Equal(0))
+	})
+
+	It("should be safe for concurrent use", func() {
+		cache := New(2)
+		var sharings [3]struct {
+			vshares shamir.VerifiableShares
+			c       shamir.Commitment
+		}
+		for i := range sharings {
+			sharings[i].vshares, sharings[i].c = deal()
+		}
+
+		var wg sync.WaitGroup
+		valid := make([]bool, 8*n)
+		for i := range valid {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s := &sharings[i%len(sharings)]
+				valid[i] = cache.IsValid(h, &s.c, &s.vshares[i%n])
+			}(i)
+		}
+		wg.Wait()
+		for i := range valid {
+			Expect(valid[i]).To(BeTrue())
+		}
+	})
+
+	It("should panic for an invalid capacity", func() {
+		Expect(func() { New(0) }).To(Panic())
+	})
+})
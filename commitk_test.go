@@ -0,0 +1,84 @@
+package shamir_test
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Fixed size commitments", func() {
+	n, k := 10, 3
+	h := PedersenH()
+	indices := RandomIndices(n)
+
+	deal := func() (VerifiableShares, Commitment3) {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+		var c3 Commitment3
+		Expect(c3.SetCommitment(c)).To(Succeed())
+		return vshares, c3
+	}
+
+	It("should verify the same shares as the commitment", func() {
+		vshares, c := deal()
+		com := c.Commitment()
+		for i := range vshares {
+			Expect(c.IsValid(h, &vshares[i])).To(BeTrue())
+			eval := c.Evaluate(&vshares[i].Share.Index)
+			expected := com.Evaluate(&vshares[i].Share.Index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+
+		vshares[0].Share.Value = secp256k1.RandomFn()
+		Expect(c.IsValid(h, &vshares[0])).To(BeFalse())
+	})
+
+	It("should add commitments as the commitment does", func() {
+		vshares1, c1 := deal()
+		vshares2, c2 := deal()
+		var sum Commitment3
+		sum.Add(&c1, &c2)
+
+		var vshare VerifiableShare
+		vshare.Add(&vshares1[0], &vshares2[0])
+		Expect(sum.IsValid(h, &vshare)).To(BeTrue())
+	})
+
+	It("should be encoded as the points without a length prefix", func() {
+		_, c := deal()
+		com := c.Commitment()
+		buf, err := surge.ToBinary(c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf).To(HaveLen(k * secp256k1.PointSizeMarshalled))
+		full, err := surge.ToBinary(com)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf).To(Equal(full[surge.SizeHintU32:]))
+
+		var decoded Commitment3
+		Expect(surge.FromBinary(&decoded, buf)).To(Succeed())
+		Expect(decoded.Eq(&c)).To(BeTrue())
+
+		_, _, err = decoded.Unmarshal(buf[:len(buf)-1], len(buf))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should refer to the same points as the returned commitment", func() {
+		_, c := deal()
+		com := c.Commitment()
+		com[0] = secp256k1.RandomPoint()
+		Expect(c[0].Eq(&com[0])).To(BeTrue())
+	})
+
+	It("should only be set from a commitment of the same length", func() {
+		var c2 Commitment2
+		var c4 Commitment4
+		c := make(Commitment, k)
+		Expect(c2.SetCommitment(c)).ToNot(Succeed())
+		Expect(c4.SetCommitment(c)).ToNot(Succeed())
+	})
+})
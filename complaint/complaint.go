@@ -0,0 +1,334 @@
+// Package complaint defines the messages of the complaint round of a
+// verifiable secret sharing or DKG, together with the checks that every party
+// runs on them, so that network layers only need to transport well defined
+// objects.
+//
+// The package assumes that each party has a key pair (x, X = x*G), and that a
+// dealer sends each share over a channel keyed by the Diffie-Hellman key
+// K = x_dealer*X_recipient = x_recipient*X_dealer, for example by encrypting
+// it with a key derived from K. The flow is then:
+//   - A recipient that receives a share that is not valid with regard to the
+//     dealer's commitment broadcasts a Complaint. The evidence contains the
+//     share that it received and the channel key K, with a DLEQ proof that K
+//     was computed correctly, so that anyone can decrypt the message that the
+//     dealer sent and check that it contains the share in the evidence.
+//   - The accused dealer answers with a DefenseReveal, which publishes the
+//     correct share with a DLEQ proof of knowledge of the dealer's private
+//     key, so that only the dealer can answer.
+//
+// A complaint that verifies shows that the share in the evidence is invalid,
+// and a defense that verifies shows that the dealer has published a valid
+// share for the accuser, which the accuser should use instead. A dealer that
+// does not answer a verified complaint with a verified defense should be
+// disqualified, and an accuser whose complaint does not verify can be
+// penalised.
+//
+// Since the format of the messages between dealers and recipients is not
+// defined by this package, checking that the evidence share is the one that
+// the dealer sent, by decrypting the dealer's message with the channel key, is
+// left to the caller.
+package complaint
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/dleq"
+)
+
+// The tags that separate the proofs in complaints from those in defenses, and
+// both from other uses of DLEQ proofs with the same domain.
+const (
+	complaintTag = "renproject/shamir/complaint"
+	defenseTag   = "renproject/shamir/defense"
+)
+
+// Evidence is the information that supports a complaint: the share that the
+// accuser received, and the key of the channel over which it was received,
+// with a proof that the key is correct.
+type Evidence struct {
+	Share shamir.VerifiableShare
+	Key   secp256k1.Point
+	Proof dleq.Proof
+}
+
+// A Complaint is an accusation, by the party with index Accuser, that the
+// dealer with index Accused sent it an invalid share.
+type Complaint struct {
+	Accuser, Accused secp256k1.Fn
+	Evidence         Evidence
+}
+
+// A DefenseReveal is the answer of an accused dealer to a complaint. It
+// publishes the share for the accuser, with a proof that it was created by the
+// dealer.
+type DefenseReveal struct {
+	Share shamir.VerifiableShare
+	Proof dleq.Proof
+}
+
+// NewComplaint creates a complaint by the party with the given index and
+// private key against the dealer with the given index and public key, for the
+// given share that was received from that dealer. The domain separation tag
+// binds the complaint to the context in which it is raised, such as a protocol
+// name and session identifier. An error is returned if the nonce of the proof
+// can not be generated.
+func NewComplaint(
+	accuser, accused *secp256k1.Fn,
+	share *shamir.VerifiableShare,
+	priv *secp256k1.Fn,
+	accusedKey *secp256k1.Point,
+	domain []byte,
+) (Complaint, error) {
+	// (G, X_accuser, X_accused, K).
+	g := generator()
+	st := dleq.NewStatement(priv, &g, accusedKey)
+	c := Complaint{
+		Accuser:  *accuser,
+		Accused:  *accused,
+		Evidence: Evidence{Share: *share, Key: st.H2},
+	}
+	proof, err := dleq.Prove(&st, priv, c.bind(domain))
+	if err != nil {
+		return Complaint{}, err
+	}
+	c.Evidence.Proof = proof
+	return c, nil
+}
+
+// Verify checks that the complaint is justified with regard to the accused
+// dealer's commitment and Pedersen parameter h, and the public keys of the
+// accuser and accused, and returns an error describing the first problem that
+// is found if it is not. A justified complaint has a correct channel key and
+// evidence share for the accuser's index that is not valid.
+func (c *Complaint) Verify(
+	h secp256k1.Point,
+	commitment *shamir.Commitment,
+	accuserKey, accusedKey *secp256k1.Point,
+	domain []byte,
+) error {
+	if c.Accuser.Eq(&c.Accused) {
+		return fmt.Errorf("accuser and accused have the same index")
+	}
+	if !c.Evidence.Share.Share.IndexEq(&c.Accuser) {
+		return fmt.Errorf("evidence share does not have the index of the accuser")
+	}
+	g := generator()
+	st := dleq.Statement{G1: g, H1: *accuserKey, G2: *accusedKey, H2: c.Evidence.Key}
+	if !dleq.Verify(&st, &c.Evidence.Proof, c.bind(domain)) {
+		return fmt.Errorf("invalid channel key proof")
+	}
+	if shamir.IsValid(h, commitment, &c.Evidence.Share) {
+		return fmt.Errorf("evidence share is valid")
+	}
+	return nil
+}
+
+// NewDefenseReveal creates the answer to the given complaint by the accused
+// dealer with the given private key, which publishes the given share. This
+// should be the dealer's share for the accuser. An error is returned if the
+// nonce of the proof can not be generated.
+func NewDefenseReveal(
+	c *Complaint,
+	share *shamir.VerifiableShare,
+	priv *secp256k1.Fn,
+	accuserKey *secp256k1.Point,
+	domain []byte,
+) (DefenseReveal, error) {
+	// (G, X_accused, X_accuser, K).
+	g := generator()
+	st := dleq.NewStatement(priv, &g, accuserKey)
+	d := DefenseReveal{Share: *share}
+	proof, err := dleq.Prove(&st, priv, d.bind(c, domain))
+	if err != nil {
+		return DefenseReveal{}, err
+	}
+	d.Proof = proof
+	return d, nil
+}
+
+// Verify checks that the defense answers the given complaint, in that it was
+// created by the accused dealer and reveals a share for the accuser that is
+// valid with regard to the dealer's commitment and Pedersen parameter h. An
+// error describing the first problem that is found is returned if it does
+// not. The complaint should have been verified first.
+func (d *DefenseReveal) Verify(
+	h secp256k1.Point,
+	commitment *shamir.Commitment,
+	c *Complaint,
+	accuserKey, accusedKey *secp256k1.Point,
+	domain []byte,
+) error {
+	if !d.Share.Share.IndexEq(&c.Accuser) {
+		return fmt.Errorf("revealed share does not have the index of the accuser")
+	}
+	g := generator()
+	st := dleq.Statement{G1: g, H1: *accusedKey, G2: *accuserKey, H2: c.Evidence.Key}
+	if !dleq.Verify(&st, &d.Proof, d.bind(c, domain)) {
+		return fmt.Errorf("invalid defense proof")
+	}
+	if !shamir.IsValid(h, commitment, &d.Share) {
+		return fmt.Errorf("revealed share is not valid")
+	}
+	return nil
+}
+
+// Returns the domain for the proof of the complaint, which binds the proof to
+// the indices and evidence share, so that they can not be changed by anyone
+// other than the accuser.
+func (c *Complaint) bind(domain []byte) []byte {
+	return bindDomain(complaintTag, domain, &c.Accuser, &c.Accused, &c.Evidence.Share)
+}
+
+// Returns the domain for the proof of the defense, which binds the proof to the
+// complaint that it answers and to the revealed share.
+func (d *DefenseReveal) bind(c *Complaint, domain []byte) []byte {
+	return bindDomain(defenseTag, domain, &c.Accuser, &c.Accused, &d.Share)
+}
+
+func bindDomain(
+	tag string,
+	domain []byte,
+	accuser, accused *secp256k1.Fn,
+	share *shamir.VerifiableShare,
+) []byte {
+	h := sha256.New()
+	h.Write([]byte(tag))
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(domain)))
+	h.Write(l[:])
+	h.Write(domain)
+
+	var bs [secp256k1.FnSizeMarshalled]byte
+	for _, x := range [...]*secp256k1.Fn{
+		accuser, accused,
+		&share.Share.Index, &share.Share.Value, &share.Decommitment,
+	} {
+		x.PutB32(bs[:])
+		h.Write(bs[:])
+	}
+	return h.Sum(nil)
+}
+
+func generator() secp256k1.Point {
+	var g secp256k1.Point
+	one := secp256k1.NewFnFromU16(1)
+	g.BaseExp(&one)
+	return g
+}
+
+// Generate implements the quick.Generator interface.
+func (e Evidence) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Evidence{
+		Share: shamir.VerifiableShare{}.Generate(rand, size).Interface().(shamir.VerifiableShare),
+		Key:   secp256k1.RandomPoint(),
+		Proof: dleq.Proof{}.Generate(rand, size).Interface().(dleq.Proof),
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (e Evidence) SizeHint() int {
+	return e.Share.SizeHint() + e.Key.SizeHint() + e.Proof.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (e Evidence) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := e.Share.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = e.Key.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return e.Proof.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (e *Evidence) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := e.Share.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = e.Key.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return e.Proof.Unmarshal(buf, rem)
+}
+
+// Generate implements the quick.Generator interface.
+func (c Complaint) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Complaint{
+		Accuser:  secp256k1.RandomFn(),
+		Accused:  secp256k1.RandomFn(),
+		Evidence: Evidence{}.Generate(rand, size).Interface().(Evidence),
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (c Complaint) SizeHint() int {
+	return c.Accuser.SizeHint() + c.Accused.SizeHint() + c.Evidence.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (c Complaint) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := c.Accuser.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = c.Accused.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return c.Evidence.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (c *Complaint) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := c.Accuser.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = c.Accused.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return c.Evidence.Unmarshal(buf, rem)
+}
+
+// Generate implements the quick.Generator interface.
+func (d DefenseReveal) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(DefenseReveal{
+		Share: shamir.VerifiableShare{}.Generate(rand, size).Interface().(shamir.VerifiableShare),
+		Proof: dleq.Proof{}.Generate(rand, size).Interface().(dleq.Proof),
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (d DefenseReveal) SizeHint() int {
+	return d.Share.SizeHint() + d.Proof.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (d DefenseReveal) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Share.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return d.Proof.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (d *DefenseReveal) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Share.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return d.Proof.Unmarshal(buf, rem)
+}
@@ -0,0 +1,161 @@
+package complaint_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/complaint"
+)
+
+var _ = Describe("Complaints", func() {
+	trials := 10
+	n, k := 5, 3
+	h := shamir.PedersenH()
+	domain := []byte("complaint test")
+
+	type party struct {
+		index secp256k1.Fn
+		priv  secp256k1.Fn
+		pub   secp256k1.Point
+	}
+	newParty := func() party {
+		p := party{index: secp256k1.RandomFn(), priv: secp256k1.RandomFn()}
+		p.pub.BaseExp(&p.priv)
+		return p
+	}
+
+	// Deals a sharing to the index of the accuser and other
+	// random indices, and returns the dealing and the accuser's correct share.
+	setup := func(accuser party) (shamir.Dealing, shamir.VerifiableShare) {
+		indices := append([]secp256k1.Fn{accuser.index}, shamirutil.RandomIndices(n-1)...)
+		d, err := shamir.Deal(indices, h, secp256k1.RandomFn(), k)
+		Expect(err).ToNot(HaveOccurred())
+		return d, d.Shares[0]
+	}
+
+	newComplaint := func(accuser, accused *secp256k1.Fn, share *shamir.VerifiableShare, priv *secp256k1.Fn, accusedKey *secp256k1.Point) Complaint {
+		c, err := NewComplaint(accuser, accused, share, priv, accusedKey, domain)
+		Expect(err).ToNot(HaveOccurred())
+		return c
+	}
+
+	newDefenseReveal := func(c *Complaint, share *shamir.VerifiableShare, priv *secp256k1.Fn, accuserKey *secp256k1.Point) DefenseReveal {
+		def, err := NewDefenseReveal(c, share, priv, accuserKey, domain)
+		Expect(err).ToNot(HaveOccurred())
+		return def
+	}
+
+	corrupt := func(vshare shamir.VerifiableShare) shamir.VerifiableShare {
+		vshare.Share.Value = secp256k1.RandomFn()
+		return vshare
+	}
+
+	It("should verify complaints about invalid shares and defenses that reveal valid shares", func() {
+		for i := 0; i < trials; i++ {
+			dealer, accuser := newParty(), newParty()
+			d, share := setup(accuser)
+			bad := corrupt(share)
+
+			c := newComplaint(&accuser.index, &dealer.index, &bad, &accuser.priv, &dealer.pub)
+			Expect(c.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).To(Succeed())
+
+			def := newDefenseReveal(&c, &share, &dealer.priv, &accuser.pub)
+			Expect(def.Verify(h, &d.Commitment, &c, &accuser.pub, &dealer.pub, domain)).To(Succeed())
+
+			// Both parties compute the same channel key.
+			var key secp256k1.Point
+			key.Scale(&accuser.pub, &dealer.priv)
+			Expect(key.Eq(&c.Evidence.Key)).To(BeTrue())
+		}
+	})
+
+	It("should reject complaints about valid shares", func() {
+		dealer, accuser := newParty(), newParty()
+		d, share := setup(accuser)
+		c := newComplaint(&accuser.index, &dealer.index, &share, &accuser.priv, &dealer.pub)
+		Expect(c.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+	})
+
+	It("should reject complaints with incorrect or altered evidence", func() {
+		for i := 0; i < trials; i++ {
+			dealer, accuser, other := newParty(), newParty(), newParty()
+			d, share := setup(accuser)
+			bad := corrupt(share)
+			c := newComplaint(&accuser.index, &dealer.index, &bad, &accuser.priv, &dealer.pub)
+
+			// Wrong keys or domain.
+			Expect(c.Verify(h, &d.Commitment, &other.pub, &dealer.pub, domain)).ToNot(Succeed())
+			Expect(c.Verify(h, &d.Commitment, &accuser.pub, &other.pub, domain)).ToNot(Succeed())
+			Expect(c.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, []byte("other"))).ToNot(Succeed())
+
+			// Altered channel key, share or indices.
+			altered := c
+			altered.Evidence.Key = secp256k1.RandomPoint()
+			Expect(altered.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+			altered = c
+			altered.Evidence.Share = corrupt(share)
+			Expect(altered.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+			altered = c
+			altered.Accused = other.index
+			Expect(altered.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+			altered = c
+			altered.Accused = c.Accuser
+			Expect(altered.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+
+			// Evidence for a different index.
+			misplaced := bad
+			misplaced.Share.Index = other.index
+			c = newComplaint(&accuser.index, &dealer.index, &misplaced, &accuser.priv, &dealer.pub)
+			Expect(c.Verify(h, &d.Commitment, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+		}
+	})
+
+	It("should reject defenses that are invalid or not from the accused", func() {
+		for i := 0; i < trials; i++ {
+			dealer, accuser, other := newParty(), newParty(), newParty()
+			d, share := setup(accuser)
+			bad := corrupt(share)
+			c := newComplaint(&accuser.index, &dealer.index, &bad, &accuser.priv, &dealer.pub)
+
+			def := newDefenseReveal(&c, &bad, &dealer.priv, &accuser.pub)
+			Expect(def.Verify(h, &d.Commitment, &c, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+
+			def = newDefenseReveal(&c, &share, &other.priv, &accuser.pub)
+			Expect(def.Verify(h, &d.Commitment, &c, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+
+			def = newDefenseReveal(&c, &share, &dealer.priv, &accuser.pub)
+			altered := def
+			altered.Share = d.Shares[1]
+			Expect(altered.Verify(h, &d.Commitment, &c, &accuser.pub, &dealer.pub, domain)).ToNot(Succeed())
+			Expect(def.Verify(h, &d.Commitment, &c, &accuser.pub, &dealer.pub, []byte("other"))).ToNot(Succeed())
+		}
+	})
+
+	Context("surge marshalling", func() {
+		for _, t := range []reflect.Type{
+			reflect.TypeOf(Evidence{}),
+			reflect.TypeOf(Complaint{}),
+			reflect.TypeOf(DefenseReveal{}),
+		} {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
@@ -0,0 +1,167 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Consistency checking", func() {
+	trials := 20
+	n := 15
+
+	share := func(k int) Shares {
+		shares := make(Shares, n)
+		Expect(ShareSecret(&shares, RandomIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+		return shares
+	}
+
+	It("should return no indices for consistent shares", func() {
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n)
+			inconsistent, err := InconsistentIndices(share(k), k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inconsistent).To(BeNil())
+		}
+	})
+
+	It("should return the indices of the inconsistent shares", func() {
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n-2)
+			shares := share(k)
+			numBad := RandRange(1, (n-k)/2)
+			bad := rand.Perm(n)[:numBad]
+			for _, j := range bad {
+				shares[j].Value = secp256k1.RandomFn()
+			}
+
+			inconsistent, err := InconsistentIndices(shares, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inconsistent).To(HaveLen(numBad))
+			for _, j := range bad {
+				Expect(inconsistent).To(ContainElement(shares[j].Index))
+			}
+			Expect(SharesAreConsistent(shares, k)).To(BeFalse())
+		}
+	})
+
+	It("should return an error when an inconsistency cannot be located", func() {
+		// With k = n - 1 no errors can be corrected, but a single inconsistent
+		// share must still be detected.
+		k := n - 1
+		shares := share(k)
+		shares[rand.Intn(n)].Value = secp256k1.RandomFn()
+
+		_, err := InconsistentIndices(shares, k)
+		Expect(err).To(Equal(ErrTooManyInconsistent))
+	})
+
+	It("should return an error when there are too many inconsistent shares", func() {
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n-2)
+			shares := share(k)
+			for j := range shares {
+				shares[j].Value = secp256k1.RandomFn()
+			}
+			_, err := InconsistentIndices(shares, k)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should return an error for invalid input", func() {
+		shares := share(3)
+		_, err := InconsistentIndices(shares, 0)
+		Expect(err).To(HaveOccurred())
+		shares[1].Index = shares[0].Index
+		_, err = InconsistentIndices(shares, 3)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should check verifiable shares", func() {
+		h := PedersenH()
+		k := RandRange(1, n-2)
+		d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), k)
+		Expect(err).ToNot(HaveOccurred())
+		inconsistent, err := InconsistentVShareIndices(d.Shares, k)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inconsistent).To(BeNil())
+
+		PerturbValue(&d.Shares[0])
+		inconsistent, err = InconsistentVShareIndices(d.Shares, k)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inconsistent).To(ConsistOf(d.Shares[0].Share.Index))
+	})
+
+	Context("when opening robustly", func() {
+		shareSecret := func(k int) (Shares, secp256k1.Fn) {
+			secret := secp256k1.RandomFn()
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, RandomIndices(n), secret, k)).To(Succeed())
+			return shares, secret
+		}
+
+		It("should open consistent shares without a report", func() {
+			for i := 0; i < trials; i++ {
+				k := RandRange(1, n)
+				shares, secret := shareSecret(k)
+				opened, report, err := OpenRobust(shares, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report).To(BeNil())
+				Expect(opened.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should correct and report inconsistent shares", func() {
+			for i := 0; i < trials; i++ {
+				k := RandRange(1, n-2)
+				shares, secret := shareSecret(k)
+				original := append(Shares{}, shares...)
+				numBad := RandRange(1, (n-k)/2)
+				bad := rand.Perm(n)[:numBad]
+				for _, j := range bad {
+					shares[j].Value = secp256k1.RandomFn()
+				}
+
+				opened, report, err := OpenRobust(shares, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(opened.Eq(&secret)).To(BeTrue())
+				Expect(report.Decoded).To(BeTrue())
+				Expect(report.Threshold).To(Equal(k))
+				Expect(report.Positions).To(ConsistOf(bad))
+				for j, pos := range report.Positions {
+					Expect(report.Indices[j].Eq(&shares[pos].Index)).To(BeTrue())
+					Expect(report.Received[j].Eq(&shares[pos].Value)).To(BeTrue())
+					Expect(report.Expected[j].Eq(&original[pos].Value)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should return an undecoded report when there are too many inconsistent shares", func() {
+			k := n - 1
+			shares, _ := shareSecret(k)
+			shares[rand.Intn(n)].Value = secp256k1.RandomFn()
+
+			_, report, err := OpenRobust(shares, k)
+			Expect(err).To(Equal(ErrTooManyInconsistent))
+			Expect(report.Decoded).To(BeFalse())
+			Expect(report.Positions).To(BeEmpty())
+		})
+
+		It("should open verifiable shares", func() {
+			k := RandRange(1, n-2)
+			secret := secp256k1.RandomFn()
+			d, err := Deal(RandomIndices(n), PedersenH(), secret, k)
+			Expect(err).ToNot(HaveOccurred())
+			PerturbValue(&d.Shares[0])
+			opened, report, err := OpenRobustVShares(d.Shares, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened.Eq(&secret)).To(BeTrue())
+			Expect(report.Positions).To(Equal([]int{0}))
+		})
+	})
+})
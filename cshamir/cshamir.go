@@ -0,0 +1,112 @@
+// Command cshamir builds a C library that exports the sharing functions of
+// this module, so that programs written in other languages can use the same
+// implementation and encodings instead of their own. It is built with
+//
+//	go build -buildmode=c-shared -o libcshamir.so ./cshamir
+//
+// or with -buildmode=c-archive for a static library, either of which also
+// writes the header libcshamir.h. The header in this directory is generated
+// in the same way by go generate.
+//
+// The functions are those of the mobile package, with the same curves and the
+// same encodings of secrets, shares and commitments. Every output is written
+// to a buffer that is given by the caller together with its length, so no
+// memory is allocated by the library that the caller has to free. The sizes
+// of the outputs only depend on the parameters:
+//
+//	shamir_split             n * SHAMIR_SHARE_SIZE
+//	shamir_combine           SHAMIR_SCALAR_SIZE
+//	shamir_vsplit            n * SHAMIR_VSHARE_SIZE and k * SHAMIR_POINT_SIZE
+//
+// Each function returns SHAMIR_OK on success, SHAMIR_ERR_INVALID if an input
+// can not be decoded or the parameters are not valid, and SHAMIR_ERR_BUFFER
+// if an output buffer has the wrong length; shamir_vss_verify instead returns
+// SHAMIR_VALID or SHAMIR_NOT_VALID if the share can be decoded. Output
+// buffers are not written to unless the function succeeds.
+package main
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+
+#define SHAMIR_CURVE_SECP256K1 1
+#define SHAMIR_CURVE_P256 2
+
+#define SHAMIR_SCALAR_SIZE 32
+#define SHAMIR_POINT_SIZE 33
+#define SHAMIR_SHARE_SIZE 64
+#define SHAMIR_VSHARE_SIZE 96
+
+#define SHAMIR_OK 0
+#define SHAMIR_VALID 1
+#define SHAMIR_NOT_VALID 0
+#define SHAMIR_ERR_INVALID -1
+#define SHAMIR_ERR_BUFFER -2
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+//go:generate go build -buildmode=c-archive -o libcshamir.a .
+//go:generate rm libcshamir.a
+
+func main() {}
+
+// shamir_split writes n shares of the secret, any k of which recover it.
+//
+//export shamir_split
+func shamir_split(curve C.int, secret *C.uint8_t, secretLen C.size_t, n, k C.int, out *C.uint8_t, outLen C.size_t) C.int {
+	return C.int(split(int(curve), goBytes(secret, secretLen), int(n), int(k), cBytes(out, outLen)))
+}
+
+// shamir_combine writes the secret that is recovered from the shares.
+//
+//export shamir_combine
+func shamir_combine(curve C.int, shares *C.uint8_t, sharesLen C.size_t, out *C.uint8_t, outLen C.size_t) C.int {
+	return C.int(combine(int(curve), goBytes(shares, sharesLen), cBytes(out, outLen)))
+}
+
+// shamir_vsplit writes n verifiable shares of the secret, any k of which
+// recover it, and the commitment that they can be verified against.
+//
+//export shamir_vsplit
+func shamir_vsplit(
+	curve C.int,
+	secret *C.uint8_t, secretLen C.size_t,
+	n, k C.int,
+	shares *C.uint8_t, sharesLen C.size_t,
+	commitment *C.uint8_t, commitmentLen C.size_t,
+) C.int {
+	return C.int(vsplit(
+		int(curve),
+		goBytes(secret, secretLen),
+		int(n), int(k),
+		cBytes(shares, sharesLen),
+		cBytes(commitment, commitmentLen),
+	))
+}
+
+// shamir_vss_verify checks the verifiable share against the commitment.
+//
+//export shamir_vss_verify
+func shamir_vss_verify(curve C.int, vshare *C.uint8_t, vshareLen C.size_t, commitment *C.uint8_t, commitmentLen C.size_t) C.int {
+	return C.int(verify(int(curve), goBytes(vshare, vshareLen), goBytes(commitment, commitmentLen)))
+}
+
+// Copies the C buffer into memory owned by Go.
+func goBytes(p *C.uint8_t, n C.size_t) []byte {
+	if p == nil || n == 0 {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(p), C.int(n))
+}
+
+// Returns a slice that refers to the C buffer, without copying it.
+func cBytes(p *C.uint8_t, n C.size_t) []byte {
+	if p == nil || n == 0 {
+		return nil
+	}
+	return (*[1 << 30]byte)(unsafe.Pointer(p))[:n:n]
+}
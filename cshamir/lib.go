@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/renproject/shamir/mobile"
+)
+
+// The return codes of the exported functions, which are the same as those
+// defined in the header.
+const (
+	codeOK       = 0
+	codeValid    = 1
+	codeNotValid = 0
+	codeInvalid  = -1
+	codeBuffer   = -2
+)
+
+// The exported functions convert their arguments and call these, so that
+// they can be tested without cgo. Secrets and shares given by the caller have
+// already been copied, and are wiped once they have been used.
+
+func split(curve int, secret []byte, n, k int, out []byte) int {
+	defer wipe(secret)
+	if !validParams(n, k) {
+		return codeInvalid
+	}
+	if len(out) != n*mobile.ShareSize {
+		return codeBuffer
+	}
+	shares, err := mobile.Split(curve, secret, n, k)
+	if err != nil {
+		return codeInvalid
+	}
+	defer wipe(shares)
+	copy(out, shares)
+	return codeOK
+}
+
+func combine(curve int, shares []byte, out []byte) int {
+	defer wipe(shares)
+	if len(out) != mobile.ScalarSize {
+		return codeBuffer
+	}
+	secret, err := mobile.Combine(curve, shares)
+	if err != nil {
+		return codeInvalid
+	}
+	defer wipe(secret)
+	copy(out, secret)
+	return codeOK
+}
+
+func vsplit(curve int, secret []byte, n, k int, shares, commitment []byte) int {
+	defer wipe(secret)
+	if !validParams(n, k) {
+		return codeInvalid
+	}
+	if len(shares) != n*mobile.VShareSize || len(commitment) != k*mobile.PointSize {
+		return codeBuffer
+	}
+	sharing, err := mobile.VSplit(curve, secret, n, k)
+	if err != nil {
+		return codeInvalid
+	}
+	defer wipe(sharing.Shares)
+	copy(shares, sharing.Shares)
+	copy(commitment, sharing.Commitment)
+	return codeOK
+}
+
+func verify(curve int, vshare, commitment []byte) int {
+	defer wipe(vshare)
+	ok, err := mobile.Verify(curve, vshare, commitment)
+	if err != nil {
+		return codeInvalid
+	}
+	if !ok {
+		return codeNotValid
+	}
+	return codeValid
+}
+
+// The lengths of the output buffers can only be checked for valid parameters.
+func validParams(n, k int) bool {
+	return k >= 1 && k <= n && n <= mobile.MaxShares
+}
+
+func wipe(bs []byte) {
+	for i := range bs {
+		bs[i] = 0
+	}
+}
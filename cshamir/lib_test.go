@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/mobile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("C library", func() {
+	n, k := 5, 3
+
+	randomSecret := func() []byte {
+		secret := make([]byte, mobile.ScalarSize)
+		s := secp256k1.RandomFn()
+		s.PutB32(secret)
+		return secret
+	}
+
+	It("should recover the secret from k shares", func() {
+		secret := randomSecret()
+		shares := make([]byte, n*mobile.ShareSize)
+		Expect(split(mobile.Secp256k1, append([]byte{}, secret...), n, k, shares)).To(Equal(codeOK))
+
+		out := make([]byte, mobile.ScalarSize)
+		Expect(combine(mobile.Secp256k1, shares[mobile.ShareSize:], out)).To(Equal(codeOK))
+		Expect(out).To(Equal(secret))
+	})
+
+	It("should create verifiable shares that verify", func() {
+		secret := randomSecret()
+		shares := make([]byte, n*mobile.VShareSize)
+		commitment := make([]byte, k*mobile.PointSize)
+		Expect(vsplit(mobile.P256, secret, n, k, shares, commitment)).To(Equal(codeOK))
+
+		for i := 0; i < n; i++ {
+			vshare := append([]byte{}, shares[i*mobile.VShareSize:(i+1)*mobile.VShareSize]...)
+			Expect(verify(mobile.P256, vshare, commitment)).To(Equal(codeValid))
+		}
+		vshare := append([]byte{}, shares[:mobile.VShareSize]...)
+		vshare[mobile.VShareSize-1] ^= 1
+		Expect(verify(mobile.P256, vshare, commitment)).To(Equal(codeNotValid))
+	})
+
+	It("should wipe the secret and shares that it is given", func() {
+		secret := randomSecret()
+		shares := make([]byte, n*mobile.ShareSize)
+		Expect(split(mobile.Secp256k1, secret, n, k, shares)).To(Equal(codeOK))
+		Expect(secret).To(Equal(make([]byte, mobile.ScalarSize)))
+
+		given := append([]byte{}, shares...)
+		Expect(combine(mobile.Secp256k1, given, make([]byte, mobile.ScalarSize))).To(Equal(codeOK))
+		Expect(given).To(Equal(make([]byte, len(shares))))
+	})
+
+	It("should distinguish invalid inputs from buffers of the wrong length", func() {
+		Expect(split(mobile.Secp256k1, randomSecret(), n, k, make([]byte, n*mobile.ShareSize-1))).To(Equal(codeBuffer))
+		Expect(split(mobile.Secp256k1, randomSecret(), k, n, make([]byte, k*mobile.ShareSize))).To(Equal(codeInvalid))
+		Expect(split(3, randomSecret(), n, k, make([]byte, n*mobile.ShareSize))).To(Equal(codeInvalid))
+		Expect(combine(mobile.Secp256k1, nil, make([]byte, mobile.ScalarSize))).To(Equal(codeInvalid))
+		Expect(combine(mobile.Secp256k1, make([]byte, mobile.ShareSize), nil)).To(Equal(codeBuffer))
+		Expect(vsplit(mobile.Secp256k1, randomSecret(), n, k, make([]byte, n*mobile.VShareSize), nil)).To(Equal(codeBuffer))
+		Expect(vsplit(mobile.Secp256k1, randomSecret(), n, 0, nil, nil)).To(Equal(codeInvalid))
+		Expect(verify(mobile.Secp256k1, make([]byte, mobile.ShareSize), make([]byte, mobile.PointSize))).To(Equal(codeInvalid))
+	})
+})
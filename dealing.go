@@ -0,0 +1,155 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+)
+
+// A Dealing bundles the commitment to a verifiable sharing with shares of that
+// sharing. The dealer's view of a dealing contains the shares of all parties,
+// while the view of a recipient contains only the share for that recipient,
+// which can be obtained from the dealer's view using ForIndex.
+type Dealing struct {
+	Commitment Commitment
+	Shares     VerifiableShares
+}
+
+// Deal creates a verifiable sharing of the given secret for the given indices
+// with reconstruction threshold k, and returns the dealer's view of it.
+func Deal(indices []secp256k1.Fn, h secp256k1.Point, secret secp256k1.Fn, k int) (Dealing, error) {
+	d := Dealing{
+		Commitment: NewCommitmentWithCapacity(k),
+		Shares:     make(VerifiableShares, len(indices)),
+	}
+	if err := VShareSecret(&d.Shares, &d.Commitment, indices, h, secret, k); err != nil {
+		return Dealing{}, err
+	}
+	return d, nil
+}
+
+// Generate implements the quick.Generator interface.
+func (d Dealing) Generate(rand *rand.Rand, size int) reflect.Value {
+	shares := make(VerifiableShares, rand.Intn(size))
+	for i := range shares {
+		shares[i] = NewVerifiableShare(
+			NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+			secp256k1.RandomFn(),
+		)
+	}
+	com := Commitment{}.Generate(rand, size).Interface().(Commitment)
+	return reflect.ValueOf(Dealing{Commitment: com, Shares: shares})
+}
+
+// Eq returns true if the two dealings have equal commitments and shares, and
+// false otherwise.
+func (d *Dealing) Eq(other *Dealing) bool {
+	if !d.Commitment.Eq(other.Commitment) || len(d.Shares) != len(other.Shares) {
+		return false
+	}
+	for i := range d.Shares {
+		if !d.Shares[i].Eq(&other.Shares[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Share returns the share in the dealing with the given index, and false if
+// there is no such share.
+func (d *Dealing) Share(index *secp256k1.Fn) (VerifiableShare, bool) {
+	for i := range d.Shares {
+		if d.Shares[i].Share.IndexEq(index) {
+			return d.Shares[i], true
+		}
+	}
+	return VerifiableShare{}, false
+}
+
+// ForIndex returns the view of the dealing for the recipient with the given
+// index, which contains the commitment and the single share for that index.
+// False is returned if there is no share for the index.
+func (d *Dealing) ForIndex(index *secp256k1.Fn) (Dealing, bool) {
+	share, ok := d.Share(index)
+	if !ok {
+		return Dealing{}, false
+	}
+	var com Commitment
+	com.Set(d.Commitment)
+	return Dealing{Commitment: com, Shares: VerifiableShares{share}}, true
+}
+
+// Validate checks that the dealing is well formed and that every share in it
+// is valid with regard to the commitment, and returns an error describing the
+// first problem that is found.
+func (d *Dealing) Validate(h secp256k1.Point) error {
+	if len(d.Commitment) == 0 {
+		return errors.New("empty commitment")
+	}
+	if len(d.Shares) == 0 {
+		return errors.New("no shares")
+	}
+	for i := range d.Shares {
+		for j := 0; j < i; j++ {
+			if d.Shares[i].Share.IndexEq(&d.Shares[j].Share.Index) {
+				return fmt.Errorf("shares %v and %v have the same index", j, i)
+			}
+		}
+		if !IsValid(h, &d.Commitment, &d.Shares[i]) {
+			return fmt.Errorf("share %v is not valid", i)
+		}
+	}
+	return nil
+}
+
+// Add stores in the caller the dealing that represents the addition of the
+// two given dealings, as for Commitment.Add and VerifiableShare.Add. The
+// shares of the two dealings are matched by index, and an error is returned if
+// the two dealings do not contain shares for the same indices in the same
+// order.
+func (d *Dealing) Add(a, b *Dealing) error {
+	if err := checkAligned(len(a.Shares), len(b.Shares), func(i int) bool {
+		return a.Shares[i].Share.IndexEq(&b.Shares[i].Share.Index)
+	}); err != nil {
+		return err
+	}
+
+	l := len(a.Commitment)
+	if len(b.Commitment) > l {
+		l = len(b.Commitment)
+	}
+	com := NewCommitmentWithCapacity(l)
+	com.Add(a.Commitment, b.Commitment)
+	shares := make(VerifiableShares, len(a.Shares))
+	for i := range shares {
+		shares[i].Add(&a.Shares[i], &b.Shares[i])
+	}
+	d.Commitment, d.Shares = com, shares
+	return nil
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (d Dealing) SizeHint() int {
+	return d.Commitment.SizeHint() + d.Shares.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (d Dealing) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Commitment.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return d.Shares.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (d *Dealing) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Commitment.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return d.Shares.Unmarshal(buf, rem)
+}
@@ -0,0 +1,176 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Dealings", func() {
+	trials := 20
+	n := 10
+	h := PedersenH()
+
+	It("should produce valid dealings", func() {
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n)
+			secret := secp256k1.RandomFn()
+			d, err := Deal(RandomIndices(n), h, secret, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.Validate(h)).To(Succeed())
+			Expect(d.Commitment.Len()).To(Equal(k))
+
+			recon := Open(d.Shares.Shares())
+			Expect(recon.Eq(&secret)).To(BeTrue())
+		}
+	})
+
+	It("should return an error when k is larger than n", func() {
+		_, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), n+1)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should produce valid recipient views", func() {
+		d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+		Expect(err).ToNot(HaveOccurred())
+		for i := range d.Shares {
+			view, ok := d.ForIndex(&d.Shares[i].Share.Index)
+			Expect(ok).To(BeTrue())
+			Expect(view.Shares).To(HaveLen(1))
+			Expect(view.Shares[0].Eq(&d.Shares[i])).To(BeTrue())
+			Expect(view.Commitment.Eq(d.Commitment)).To(BeTrue())
+			Expect(view.Validate(h)).To(Succeed())
+		}
+
+		index := secp256k1.RandomFn()
+		_, ok := d.ForIndex(&index)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should detect invalid dealings", func() {
+		for i := 0; i < trials; i++ {
+			d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+			Expect(err).ToNot(HaveOccurred())
+			j := rand.Intn(n)
+			switch rand.Intn(3) {
+			case 0:
+				d.Shares[j].Share.Value = secp256k1.RandomFn()
+			case 1:
+				d.Shares[j].Decommitment = secp256k1.RandomFn()
+			default:
+				d.Shares[j].Share.Index = d.Shares[(j+1)%n].Share.Index
+			}
+			Expect(d.Validate(h)).ToNot(Succeed())
+		}
+
+		var empty Dealing
+		Expect(empty.Validate(h)).ToNot(Succeed())
+	})
+
+	It("should be homomorphic under addition", func() {
+		indices := RandomIndices(n)
+		for i := 0; i < trials; i++ {
+			secret1, secret2 := secp256k1.RandomFn(), secp256k1.RandomFn()
+			d1, err := Deal(indices, h, secret1, RandRange(1, n))
+			Expect(err).ToNot(HaveOccurred())
+			d2, err := Deal(indices, h, secret2, RandRange(1, n))
+			Expect(err).ToNot(HaveOccurred())
+
+			var sum Dealing
+			Expect(sum.Add(&d1, &d2)).To(Succeed())
+			Expect(sum.Validate(h)).To(Succeed())
+			var expected secp256k1.Fn
+			expected.Add(&secret1, &secret2)
+			recon := Open(sum.Shares.Shares())
+			Expect(recon.Eq(&expected)).To(BeTrue())
+
+			// Adding into one of the inputs should give the same result.
+			Expect(d1.Add(&d1, &d2)).To(Succeed())
+			Expect(d1.Eq(&sum)).To(BeTrue())
+		}
+	})
+
+	It("should not add dealings with different indices", func() {
+		d1, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+		Expect(err).ToNot(HaveOccurred())
+		d2, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+		Expect(err).ToNot(HaveOccurred())
+		var sum Dealing
+		Expect(sum.Add(&d1, &d2)).ToNot(Succeed())
+
+		d3, err := Deal(RandomIndices(n-1), h, secp256k1.RandomFn(), RandRange(1, n-1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sum.Add(&d1, &d3)).ToNot(Succeed())
+	})
+
+	It("should marshal and unmarshal both views", func() {
+		d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+		Expect(err).ToNot(HaveOccurred())
+		view, _ := d.ForIndex(&d.Shares[0].Share.Index)
+		for _, dealing := range []Dealing{d, view} {
+			bs, err := surge.ToBinary(dealing)
+			Expect(err).ToNot(HaveOccurred())
+			var decoded Dealing
+			Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+			Expect(decoded.Eq(&dealing)).To(BeTrue())
+			Expect(decoded.Validate(h)).To(Succeed())
+		}
+	})
+
+	Context("when the dealer is malicious", func() {
+		It("should detect exactly the inconsistent share", func() {
+			for i := 0; i < trials; i++ {
+				k := RandRange(1, n)
+				position := rand.Intn(n)
+				d, err := DealInconsistentShare(RandomIndices(n), h, secp256k1.RandomFn(), k, position)
+				Expect(err).ToNot(HaveOccurred())
+				for j := range d.Shares {
+					Expect(IsValid(h, &d.Commitment, &d.Shares[j])).To(Equal(j != position))
+				}
+			}
+		})
+
+		It("should produce valid shares with a commitment of the wrong length", func() {
+			k := RandRange(2, n-1)
+			d, err := DealWrongDegree(RandomIndices(n), h, secp256k1.RandomFn(), k+1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.Validate(h)).To(Succeed())
+			Expect(d.Commitment.Len()).ToNot(Equal(k))
+		})
+
+		It("should produce the same biased dealing for the same seed", func() {
+			indices := RandomIndices(n)
+			secret := secp256k1.RandomFn()
+			k := RandRange(2, n)
+			a, err := DealBiased(indices, h, secret, k, 1)
+			Expect(err).ToNot(HaveOccurred())
+			b, err := DealBiased(indices, h, secret, k, 1)
+			Expect(err).ToNot(HaveOccurred())
+			c, err := DealBiased(indices, h, secret, k, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(a.Validate(h)).To(Succeed())
+			Expect(a.Eq(&b)).To(BeTrue())
+			Expect(a.Eq(&c)).To(BeFalse())
+		})
+
+		It("should give every holder the secret in a degenerate dealing", func() {
+			secret := secp256k1.RandomFn()
+			k := RandRange(2, n)
+			d, err := DealDegenerate(RandomIndices(n), h, secret, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.Validate(h)).To(Succeed())
+			for j := range d.Shares {
+				Expect(d.Shares[j].Share.Value.Eq(&secret)).To(BeTrue())
+			}
+			for j := 1; j < k; j++ {
+				Expect(d.Commitment[j].IsInfinity()).To(BeTrue())
+			}
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// VerifyDegree checks that the values and decommitments of the given
+// verifiable shares lie on polynomials of degree less than k, and returns an
+// error if they do not. Unlike checking the shares against a commitment, this
+// needs nothing but the shares, so it can be used to resolve complaints in a
+// DKG once the shares have been revealed.
+//
+// The check is probabilistic: the shares are combined using a random codeword
+// of the dual of the Reed-Solomon code with the given indices and dimension k,
+// which gives zero for every sharing of degree less than k, and gives zero for
+// any other sharing with probability at most 1/q, where q is the order of the
+// secp256k1 group. It takes time quadratic in the number of shares. If there
+// are at most k shares, any values lie on a polynomial of degree less than k,
+// and so nil is returned. An error is also returned if k is less than 1 or
+// if the shares do not have distinct indices.
+func VerifyDegree(vshares VerifiableShares, k int) error {
+	if k < 1 {
+		return fmt.Errorf("%w: expected k >= 1, got k = %v", ErrInvalidThreshold, k)
+	}
+	n := len(vshares)
+	weights := make([]secp256k1.Fn, n)
+	var diff secp256k1.Fn
+	for i := range vshares {
+		weights[i].SetU16(1)
+		for j := range vshares {
+			if i == j {
+				continue
+			}
+			diff.Negate(&vshares[j].Share.Index)
+			diff.Add(&diff, &vshares[i].Share.Index)
+			if diff.IsZero() {
+				return fmt.Errorf("shares %v and %v have the same index", j, i)
+			}
+			weights[i].Mul(&weights[i], &diff)
+		}
+	}
+	if n <= k {
+		return nil
+	}
+
+	// The dual code consists of the vectors (w_i m(x_i)), where w_i is the
+	// inverse of the product of (x_i - x_j) over j != i, and m is any
+	// polynomial of degree less than n - k. A random m, and a random
+	// combination of the values and decommitments, gives a single check for
+	// both.
+	r := RandomSource()
+	m := make([]secp256k1.Fn, n-k)
+	for i := range m {
+		var err error
+		if m[i], err = randomFn(r); err != nil {
+			return err
+		}
+	}
+	mix, err := randomFn(r)
+	if err != nil {
+		return err
+	}
+
+	var sum, term, eval secp256k1.Fn
+	for i := range vshares {
+		polyEval(&eval, &vshares[i].Share.Index, m)
+		weights[i].Inverse(&weights[i])
+		term.Mul(&mix, &vshares[i].Decommitment)
+		term.Add(&term, &vshares[i].Share.Value)
+		term.Mul(&term, &weights[i])
+		term.Mul(&term, &eval)
+		sum.Add(&sum, &term)
+	}
+	if !sum.IsZero() {
+		return fmt.Errorf("shares do not lie on a polynomial of degree less than %v", k)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Degree checks", func() {
+	trials := 20
+	h := PedersenH()
+
+	deal := func(n, k int) (VerifiableShares, Commitment) {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares, c
+	}
+
+	Context("commitment degree bounds", func() {
+		It("should be the threshold of the sharing, even when padded", func() {
+			for i := 0; i < trials; i++ {
+				k := RandRange(1, 10)
+				_, c := deal(k, k)
+				Expect(c.DegreeBound()).To(Equal(k))
+				c.PadToLen(k + RandRange(1, 5))
+				Expect(c.DegreeBound()).To(Equal(k))
+				Expect(c.Truncate(c.DegreeBound())).To(BeTrue())
+			}
+		})
+
+		It("should be zero for empty commitments and commitments to zero", func() {
+			Expect(Commitment{}.DegreeBound()).To(Equal(0))
+			c := Commitment{}
+			c.PadToLen(3)
+			Expect(c.DegreeBound()).To(Equal(0))
+		})
+	})
+
+	Context("verifying the degree of shares", func() {
+		It("should accept shares of a sharing with the given threshold or less", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				vshares, _ := deal(n, k)
+				Expect(VerifyDegree(vshares, k)).To(Succeed())
+				Expect(VerifyDegree(vshares, RandRange(k, n+1))).To(Succeed())
+			}
+		})
+
+		It("should reject shares of a sharing with a larger threshold", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(3, 20)
+				k := RandRange(2, n)
+				vshares, _ := deal(n, k)
+				Expect(VerifyDegree(vshares, k-1)).ToNot(Succeed())
+			}
+		})
+
+		It("should reject a single modified share", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(2, 20)
+				k := RandRange(1, n-1)
+				vshares, _ := deal(n, k)
+				j := rand.Intn(n)
+				if rand.Intn(2) == 0 {
+					PerturbValue(&vshares[j])
+				} else {
+					PerturbDecommitment(&vshares[j])
+				}
+				Expect(VerifyDegree(vshares, k)).ToNot(Succeed())
+			}
+		})
+
+		It("should return an error for invalid thresholds or duplicate indices", func() {
+			vshares, _ := deal(5, 3)
+			Expect(VerifyDegree(vshares, 0)).ToNot(Succeed())
+			vshares[1].Share.Index = vshares[0].Share.Index
+			Expect(VerifyDegree(vshares, 3)).ToNot(Succeed())
+		})
+	})
+})
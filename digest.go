@@ -0,0 +1,109 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/renproject/secp256k1"
+)
+
+// DigestVersion is the version of the canonical encoding that is hashed by
+// Commitment.Hash and Dealing.TranscriptDigest. It is included in every digest
+// so that a future change to the encoding can not produce digests that
+// collide with the current ones.
+const DigestVersion = 1
+
+// The tags that separate the different kinds of digest.
+const (
+	commitmentDigestTag = "renproject/shamir/commitment"
+	transcriptDigestTag = "renproject/shamir/dealing transcript"
+	vsharesDigestTag    = "renproject/shamir/verifiable shares"
+)
+
+// Hash returns a canonical digest of the commitment. The digest is the
+// SHA-256 hash of
+//
+//	tag || version || len(c) || c[0] || ... || c[len(c)-1]
+//
+// where tag is the string "renproject/shamir/commitment" prefixed by its
+// length as a 4 byte big endian integer, version is DigestVersion as a single
+// byte, len(c) is a 4 byte big endian integer, and each point is in its 33
+// byte compressed form as given by secp256k1.Point.PutBytes.
+func (c Commitment) Hash() [32]byte {
+	h := newDigest(commitmentDigestTag)
+	writeCommitment(h, c)
+	return sum(h)
+}
+
+// TranscriptDigest returns a canonical digest of the dealing, suitable for
+// signing by the dealer so that recipients can later prove what they were
+// sent, for example when raising a complaint in a DKG. The digest is the
+// SHA-256 hash of
+//
+//	tag || version || len(domain) || domain || len(c) || c[0] || ... ||
+//	    len(shares) || shares[0] || ...
+//
+// where tag is the string "renproject/shamir/dealing transcript" prefixed by
+// its length, the commitment points are encoded as for Commitment.Hash, each
+// share is the 32 byte big endian encodings of its index, value and
+// decommitment in that order, and all lengths are 4 byte big endian integers.
+// The domain should identify the protocol and session in which the dealing is
+// used.
+//
+// The digest covers the shares in the dealing, so the digest of the dealer's
+// view is different from the digest of a recipient's view. A dealer that
+// wants to sign what a given recipient receives should sign the digest of the
+// view returned by ForIndex.
+func (d *Dealing) TranscriptDigest(domain []byte) [32]byte {
+	h := newDigest(transcriptDigestTag)
+	writeU32(h, uint32(len(domain)))
+	h.Write(domain)
+	writeCommitment(h, d.Commitment)
+	writeVShares(h, d.Shares)
+	return sum(h)
+}
+
+func newDigest(tag string) hash.Hash {
+	h := sha256.New()
+	writeU32(h, uint32(len(tag)))
+	h.Write([]byte(tag))
+	h.Write([]byte{DigestVersion})
+	return h
+}
+
+func writeCommitment(h hash.Hash, c Commitment) {
+	writeU32(h, uint32(len(c)))
+	var bs [secp256k1.PointSizeMarshalled]byte
+	for i := range c {
+		c[i].PutBytes(bs[:])
+		h.Write(bs[:])
+	}
+}
+
+func writeVShares(h hash.Hash, vshares VerifiableShares) {
+	writeU32(h, uint32(len(vshares)))
+	var bs [secp256k1.FnSizeMarshalled]byte
+	for i := range vshares {
+		for _, x := range [...]*secp256k1.Fn{
+			&vshares[i].Share.Index,
+			&vshares[i].Share.Value,
+			&vshares[i].Decommitment,
+		} {
+			x.PutB32(bs[:])
+			h.Write(bs[:])
+		}
+	}
+}
+
+func writeU32(h hash.Hash, x uint32) {
+	var bs [4]byte
+	binary.BigEndian.PutUint32(bs[:], x)
+	h.Write(bs[:])
+}
+
+func sum(h hash.Hash) [32]byte {
+	var digest [32]byte
+	h.Sum(digest[:0])
+	return digest
+}
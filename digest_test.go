@@ -0,0 +1,105 @@
+package shamir_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Digests", func() {
+	trials := 20
+	n := 10
+	h := PedersenH()
+
+	Context("commitments", func() {
+		It("should match the canonical encoding", func() {
+			one := secp256k1.NewFnFromU16(1)
+			var g secp256k1.Point
+			g.BaseExp(&one)
+			c := Commitment{g}
+
+			gBytes, _ := hex.DecodeString("0079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+			tag := "renproject/shamir/commitment"
+			var preimage []byte
+			preimage = append(preimage, 0, 0, 0, byte(len(tag)))
+			preimage = append(preimage, tag...)
+			preimage = append(preimage, DigestVersion)
+			preimage = append(preimage, 0, 0, 0, 1)
+			preimage = append(preimage, gBytes...)
+			Expect(c.Hash()).To(Equal(sha256.Sum256(preimage)))
+		})
+
+		It("should be equal for equal commitments", func() {
+			for i := 0; i < trials; i++ {
+				_, c := randomDealing(n, h)
+				var other Commitment
+				other.Set(c)
+				Expect(other.Hash()).To(Equal(c.Hash()))
+			}
+		})
+
+		It("should differ for different commitments", func() {
+			for i := 0; i < trials; i++ {
+				_, c := randomDealing(n, h)
+				var other Commitment
+				other.Set(c)
+				other[RandRange(0, other.Len()-1)] = secp256k1.RandomPoint()
+				Expect(other.Hash()).ToNot(Equal(c.Hash()))
+				Expect(c[:c.Len()-1].Hash()).ToNot(Equal(c.Hash()))
+			}
+		})
+	})
+
+	Context("dealing transcripts", func() {
+		domain := []byte("session")
+
+		It("should be deterministic", func() {
+			for i := 0; i < trials; i++ {
+				d, _ := randomDealing(n, h)
+				bs, err := surge.ToBinary(d)
+				Expect(err).ToNot(HaveOccurred())
+				var other Dealing
+				Expect(surge.FromBinary(&other, bs)).To(Succeed())
+				Expect(other.TranscriptDigest(domain)).To(Equal(d.TranscriptDigest(domain)))
+			}
+		})
+
+		It("should depend on the domain, commitment and shares", func() {
+			for i := 0; i < trials; i++ {
+				d, _ := randomDealing(n, h)
+				digest := d.TranscriptDigest(domain)
+				Expect(d.TranscriptDigest([]byte("other session"))).ToNot(Equal(digest))
+				Expect(d.TranscriptDigest(nil)).ToNot(Equal(digest))
+
+				view, _ := d.ForIndex(&d.Shares[0].Share.Index)
+				Expect(view.TranscriptDigest(domain)).ToNot(Equal(digest))
+
+				j := RandRange(0, n-1)
+				perturbed := d
+				perturbed.Shares = append(VerifiableShares{}, d.Shares...)
+				perturbed.Shares[j].Decommitment = secp256k1.RandomFn()
+				Expect(perturbed.TranscriptDigest(domain)).ToNot(Equal(digest))
+
+				perturbed.Shares = d.Shares
+				perturbed.Commitment = append(Commitment{}, d.Commitment...)
+				perturbed.Commitment[0] = secp256k1.RandomPoint()
+				Expect(perturbed.TranscriptDigest(domain)).ToNot(Equal(digest))
+			}
+		})
+	})
+})
+
+func randomDealing(n int, h secp256k1.Point) (Dealing, Commitment) {
+	d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(2, n))
+	if err != nil {
+		panic(err)
+	}
+	return d, d.Commitment
+}
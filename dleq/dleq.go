@@ -0,0 +1,178 @@
+// Package dleq implements non-interactive Chaum-Pedersen proofs of equality of
+// discrete logarithms over secp256k1. A proof for the statement (G1, H1, G2,
+// H2) shows that the prover knows x such that H1 = x*G1 and H2 = x*G2, without
+// revealing x.
+//
+// This is the building block for publicly verifiable secret sharing and for
+// distributed key generation without a complaint round. For example, a dealer
+// can prove that a share encrypted to a party's public key is the same share
+// that the Pedersen commitment evaluates to at that party's index.
+//
+// Proofs are made non-interactive using the Fiat-Shamir transform, with a
+// transcript from the transcript package. The challenge is computed from a
+// domain separation tag and all of the points in the statement and the
+// commitment, so a proof created for one domain will not verify for another.
+package dleq
+
+import (
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/transcript"
+)
+
+// ProofSize is the number of bytes in a marshalled Proof.
+const ProofSize = 2 * secp256k1.FnSizeMarshalled
+
+// StatementSize is the number of bytes in a marshalled Statement.
+const StatementSize = 4 * secp256k1.PointSizeMarshalled
+
+// The name of the protocol for the transcripts of the proofs.
+const protocol = "renproject/shamir/dleq v1"
+
+// A Statement is the claim that log_G1(H1) = log_G2(H2).
+type Statement struct {
+	G1, H1, G2, H2 secp256k1.Point
+}
+
+// NewStatement computes the statement for the given discrete logarithm and
+// bases. That is, it returns the statement (g1, x*g1, g2, x*g2).
+func NewStatement(x *secp256k1.Fn, g1, g2 *secp256k1.Point) Statement {
+	st := Statement{G1: *g1, G2: *g2}
+	st.H1.ScaleExt(g1, x)
+	st.H2.ScaleExt(g2, x)
+	return st
+}
+
+// Generate implements the quick.Generator interface.
+func (st Statement) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Statement{
+		G1: secp256k1.RandomPoint(),
+		H1: secp256k1.RandomPoint(),
+		G2: secp256k1.RandomPoint(),
+		H2: secp256k1.RandomPoint(),
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (st Statement) SizeHint() int { return StatementSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (st Statement) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	for _, p := range [...]*secp256k1.Point{&st.G1, &st.H1, &st.G2, &st.H2} {
+		buf, rem, err = p.Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (st *Statement) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	for _, p := range [...]*secp256k1.Point{&st.G1, &st.H1, &st.G2, &st.H2} {
+		buf, rem, err = p.Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// A Proof is a non-interactive proof of a Statement. It consists of the
+// Fiat-Shamir challenge and the response of the prover.
+type Proof struct {
+	Challenge, Response secp256k1.Fn
+}
+
+// Generate implements the quick.Generator interface.
+func (proof Proof) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Proof{Challenge: secp256k1.RandomFn(), Response: secp256k1.RandomFn()})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (proof Proof) SizeHint() int { return ProofSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (proof Proof) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := proof.Challenge.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return proof.Response.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (proof *Proof) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := proof.Challenge.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return proof.Response.Unmarshal(buf, rem)
+}
+
+// Prove creates a proof of the given statement, using the witness x, which
+// must satisfy H1 = x*G1 and H2 = x*G2. The domain separation tag binds the
+// proof to the context in which it is used, for example a protocol name and
+// session identifier. If x is not a valid witness, the proof will not verify.
+// The nonce is read from the source set with shamir.SetRandomSource, and an
+// error is returned if reading from it fails.
+func Prove(st *Statement, x *secp256k1.Fn, domain []byte) (Proof, error) {
+	w, err := shamir.RandomFn()
+	if err != nil {
+		return Proof{}, err
+	}
+	defer w.Clear()
+
+	var a1, a2 secp256k1.Point
+	a1.ScaleExt(&st.G1, &w)
+	a2.ScaleExt(&st.G2, &w)
+
+	var proof Proof
+	proof.Challenge = challenge(st, &a1, &a2, domain)
+
+	// r = w - c*x.
+	proof.Response.Mul(&proof.Challenge, x)
+	proof.Response.Negate(&proof.Response)
+	proof.Response.Add(&proof.Response, &w)
+	return proof, nil
+}
+
+// Verify returns true if the proof is a valid proof of the statement for the
+// given domain separation tag, and false otherwise. Statements in which either
+// base is the point at infinity are never valid.
+func Verify(st *Statement, proof *Proof, domain []byte) bool {
+	if st.G1.IsInfinity() || st.G2.IsInfinity() {
+		return false
+	}
+
+	// a = r*G + c*H.
+	var a1, a2, tmp secp256k1.Point
+	a1.ScaleExt(&st.G1, &proof.Response)
+	tmp.ScaleExt(&st.H1, &proof.Challenge)
+	a1.Add(&a1, &tmp)
+	a2.ScaleExt(&st.G2, &proof.Response)
+	tmp.ScaleExt(&st.H2, &proof.Challenge)
+	a2.Add(&a2, &tmp)
+
+	c := challenge(st, &a1, &a2, domain)
+	return c.Eq(&proof.Challenge)
+}
+
+// Computes the Fiat-Shamir challenge from a transcript of the domain, the
+// statement and the prover's commitment.
+func challenge(st *Statement, a1, a2 *secp256k1.Point, domain []byte) secp256k1.Fn {
+	t := transcript.New(protocol)
+	t.AppendMessage("domain", domain)
+	t.AppendPoint("G1", &st.G1)
+	t.AppendPoint("H1", &st.H1)
+	t.AppendPoint("G2", &st.G2)
+	t.AppendPoint("H2", &st.H2)
+	t.AppendPoint("A1", a1)
+	t.AppendPoint("A2", a2)
+	return t.ChallengeScalar("challenge")
+}
@@ -0,0 +1,13 @@
+package dleq_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDLEQ(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DLEQ Suite")
+}
@@ -0,0 +1,120 @@
+package dleq_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/dleq"
+)
+
+var _ = Describe("DLEQ proofs", func() {
+	trials := 20
+	domain := []byte("test domain")
+
+	prove := func(st *Statement, x *secp256k1.Fn, domain []byte) Proof {
+		proof, err := Prove(st, x, domain)
+		Expect(err).ToNot(HaveOccurred())
+		return proof
+	}
+
+	randomStatement := func() (Statement, secp256k1.Fn) {
+		x := secp256k1.RandomFn()
+		g1, g2 := secp256k1.RandomPoint(), secp256k1.RandomPoint()
+		return NewStatement(&x, &g1, &g2), x
+	}
+
+	It("should verify honestly generated proofs", func() {
+		for i := 0; i < trials; i++ {
+			st, x := randomStatement()
+			proof := prove(&st, &x, domain)
+			Expect(Verify(&st, &proof, domain)).To(BeTrue())
+		}
+	})
+
+	It("should not verify proofs for false statements", func() {
+		for i := 0; i < trials; i++ {
+			st, x := randomStatement()
+			st.H2 = secp256k1.RandomPoint()
+			proof := prove(&st, &x, domain)
+			Expect(Verify(&st, &proof, domain)).To(BeFalse())
+		}
+	})
+
+	It("should not verify proofs for a different domain", func() {
+		for i := 0; i < trials; i++ {
+			st, x := randomStatement()
+			proof := prove(&st, &x, domain)
+			Expect(Verify(&st, &proof, []byte("other domain"))).To(BeFalse())
+		}
+	})
+
+	It("should not verify perturbed proofs or statements", func() {
+		for i := 0; i < trials; i++ {
+			st, x := randomStatement()
+			proof := prove(&st, &x, domain)
+
+			perturbed := proof
+			perturbed.Response = secp256k1.RandomFn()
+			Expect(Verify(&st, &perturbed, domain)).To(BeFalse())
+			perturbed = proof
+			perturbed.Challenge = secp256k1.RandomFn()
+			Expect(Verify(&st, &perturbed, domain)).To(BeFalse())
+
+			other := st
+			other.G1 = secp256k1.RandomPoint()
+			Expect(Verify(&other, &proof, domain)).To(BeFalse())
+		}
+	})
+
+	It("should reject statements with a base at infinity", func() {
+		x := secp256k1.RandomFn()
+		g1, g2 := secp256k1.NewPointInfinity(), secp256k1.RandomPoint()
+		st := NewStatement(&x, &g1, &g2)
+		proof := prove(&st, &x, domain)
+		Expect(Verify(&st, &proof, domain)).To(BeFalse())
+	})
+
+	It("should prove that a share matches a commitment evaluation", func() {
+		// The public share value*G is what a Feldman commitment evaluates to
+		// at the index of the share. A dealer can prove that the share
+		// encrypted to a public key as value*pk has the same value.
+		n, k := 10, 4
+		indices := shamirutil.RandomIndices(n)
+		shares := make(shamir.Shares, n)
+		Expect(shamir.ShareSecret(&shares, indices, secp256k1.RandomFn(), k)).To(Succeed())
+
+		var g secp256k1.Point
+		one := secp256k1.NewFnFromU16(1)
+		g.BaseExp(&one)
+		pk := secp256k1.RandomPoint()
+		for i := range shares {
+			st := NewStatement(&shares[i].Value, &g, &pk)
+			proof := prove(&st, &shares[i].Value, domain)
+			Expect(Verify(&st, &proof, domain)).To(BeTrue())
+		}
+	})
+
+	Context("surge marshalling", func() {
+		for _, t := range []reflect.Type{reflect.TypeOf(Proof{}), reflect.TypeOf(Statement{})} {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
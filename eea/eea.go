@@ -1,6 +1,8 @@
 package eea
 
 import (
+	"sync"
+
 	"github.com/renproject/shamir/poly"
 )
 
@@ -8,11 +10,21 @@ import (
 // It holds the internal state of the algorithm, and allows it to be stepped,
 // and hence allows this state to be inspected at points in the algorithm
 // before the canonical termination condition.
+//
+// A Stepper has no setup that is independent of its inputs: all of its state
+// is modified by Init and Step, and Rem, S and T return references to that
+// state. It is therefore not safe for concurrent use, and copies of a Stepper
+// share memory with the original. Each goroutine should construct its own
+// Stepper, or share a SyncStepper.
 type Stepper struct {
 	rPrev, rNext poly.Poly
 	sPrev, sNext poly.Poly
 	tPrev, tNext poly.Poly
 	q, r         poly.Poly
+
+	// The quotients of the steps since the last call to Init. The
+	// polynomials are reused by later runs when they have enough capacity.
+	qs []poly.Poly
 }
 
 // NewStepperWithCapacity constructs a new EEA algorithm object with the given
@@ -26,10 +38,10 @@ func NewStepperWithCapacity(c int) Stepper {
 	q, r := poly.NewWithCapacity(c), poly.NewWithCapacity(c)
 
 	return Stepper{
-		rPrev, rNext,
-		sPrev, sNext,
-		tPrev, tNext,
-		q, r,
+		rPrev: rPrev, rNext: rNext,
+		sPrev: sPrev, sNext: sNext,
+		tPrev: tPrev, tNext: tNext,
+		q: q, r: r,
 	}
 }
 
@@ -48,6 +60,40 @@ func (eea *Stepper) T() *poly.Poly {
 	return &eea.tNext
 }
 
+// Quotients returns copies of the quotients of the steps that have been
+// performed since the last call to Init, in order, so that the i-th
+// quotient is the quotient of the division performed by the i-th step.
+func (eea *Stepper) Quotients() []poly.Poly {
+	qs := make([]poly.Poly, len(eea.qs))
+	for i := range eea.qs {
+		qs[i] = poly.NewFromSlice(eea.qs[i])
+	}
+	return qs
+}
+
+// Bezout returns copies of the current s and t terms of the EEA, which are
+// the coefficients for which s a + t b is equal to the current remainder,
+// where a and b are the polynomials given to Init. Unlike S and T, the
+// returned polynomials are not changed by later steps.
+func (eea *Stepper) Bezout() (s, t poly.Poly) {
+	return poly.NewFromSlice(eea.sNext), poly.NewFromSlice(eea.tNext)
+}
+
+// RunUntil steps the EEA until pred returns true for the degree of the
+// current remainder, as given by Rem().Degree(), and returns true. The
+// predicate is also called before the first step, so no steps are performed
+// if it already holds. If the canonical termination condition is reached
+// before the predicate holds, RunUntil returns false.
+func (eea *Stepper) RunUntil(pred func(remDegree int) bool) bool {
+	for !pred(eea.rNext.Degree()) {
+		if eea.rNext.IsZero() {
+			return false
+		}
+		eea.Step()
+	}
+	return true
+}
+
 // Init performs the initialisation of the state for the EEA for the given
 // input polynomials. No steps in the algorithm are performed.
 func (eea *Stepper) Init(a, b poly.Poly) {
@@ -62,12 +108,15 @@ func (eea *Stepper) Init(a, b poly.Poly) {
 	eea.tPrev.Zero()
 	eea.tNext.Zero()
 	eea.tNext.Coefficient(0).SetU16(1)
+
+	eea.qs = eea.qs[:0]
 }
 
 // Step carries out one step of the EEA. It returns a boolean that is true when
 // the state has reached the canonical termination condition (r_{k+1} = 0).
 func (eea *Stepper) Step() bool {
 	poly.Divide(eea.rPrev, eea.rNext, &eea.q, &eea.r)
+	eea.recordQuotient()
 
 	eea.rPrev.Set(eea.rNext)
 	eea.rNext.Set(eea.r)
@@ -86,3 +135,40 @@ func (eea *Stepper) Step() bool {
 
 	return eea.rNext.IsZero()
 }
+
+// Appends a copy of the current quotient to the recorded quotients, reusing
+// the polynomial left by a previous run if it is large enough.
+func (eea *Stepper) recordQuotient() {
+	n := len(eea.qs)
+	if n < cap(eea.qs) && cap(eea.qs[:n+1][n]) >= len(eea.q) {
+		eea.qs = eea.qs[:n+1]
+	} else {
+		eea.qs = append(eea.qs, poly.NewWithCapacity(len(eea.q)))
+	}
+	eea.qs[n].Set(eea.q)
+}
+
+// SyncStepper is a Stepper that is guarded by a mutex, so that it can be
+// shared by multiple goroutines. Since the polynomials returned by Rem, S and T
+// are references to the state of the stepper, the stepper is only accessible
+// through Do, so that a sequence of steps and the inspection of their results
+// happens atomically.
+type SyncStepper struct {
+	mu  sync.Mutex
+	eea Stepper
+}
+
+// NewSyncStepperWithCapacity constructs a new guarded stepper with the given
+// capacity, as for NewStepperWithCapacity.
+func NewSyncStepperWithCapacity(c int) *SyncStepper {
+	return &SyncStepper{eea: NewStepperWithCapacity(c)}
+}
+
+// Do calls f with the stepper while holding the lock. The stepper, and the
+// polynomials returned by its methods, must not be retained by f after it
+// returns.
+func (s *SyncStepper) Do(f func(eea *Stepper)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(&s.eea)
+}
@@ -2,6 +2,7 @@ package eea_test
 
 import (
 	"math/rand"
+	"sync"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -50,4 +51,116 @@ var _ = Describe("Extended Euclidean Algorithm", func() {
 			}
 		})
 	})
+
+	Context("when inspecting the quotients and Bezout coefficients", func() {
+		maxDegree := 20
+		a := poly.NewWithCapacity(maxDegree + 1)
+		b := poly.NewWithCapacity(maxDegree + 1)
+		temp1 := poly.NewWithCapacity(2 * (maxDegree + 1))
+		temp2 := poly.NewWithCapacity(2 * (maxDegree + 1))
+		eea := NewStepperWithCapacity(maxDegree + 1)
+
+		Specify("the quotients should reproduce the remainder sequence", func() {
+			for i := 0; i < 100; i++ {
+				polyutil.SetRandomPolynomial(&a, rand.Intn(maxDegree+1))
+				polyutil.SetRandomPolynomial(&b, rand.Intn(maxDegree)+1)
+				eea.Init(a, b)
+				steps := 0
+				for !eea.Step() {
+					steps++
+				}
+				qs := eea.Quotients()
+				Expect(qs).To(HaveLen(steps + 1))
+
+				// r_{i+1} = r_{i-1} - q_i r_i, ending with the zero remainder.
+				rPrev := poly.NewWithCapacity(2 * (maxDegree + 1))
+				rNext := poly.NewWithCapacity(2 * (maxDegree + 1))
+				rPrev.Set(a)
+				rNext.Set(b)
+				for _, q := range qs {
+					temp1.Mul(q, rNext)
+					temp1.Sub(rPrev, temp1)
+					rPrev.Set(rNext)
+					rNext.Set(temp1)
+				}
+				Expect(rNext.IsZero()).To(BeTrue())
+
+				// A new run starts a new sequence of quotients.
+				eea.Init(a, b)
+				Expect(eea.Quotients()).To(BeEmpty())
+			}
+		})
+
+		Specify("the Bezout coefficients should be unchanged by later steps", func() {
+			polyutil.SetRandomPolynomial(&a, maxDegree)
+			polyutil.SetRandomPolynomial(&b, maxDegree-1)
+			eea.Init(a, b)
+			eea.Step()
+			s, t := eea.Bezout()
+			rem := poly.NewFromSlice(*eea.Rem())
+			eea.Step()
+			Expect(s.Eq(*eea.S())).To(BeFalse())
+
+			temp1.Mul(a, s)
+			temp2.Mul(b, t)
+			temp1.Add(temp1, temp2)
+			Expect(temp1.Eq(rem)).To(BeTrue())
+		})
+
+		Specify("RunUntil should stop when the predicate holds", func() {
+			for i := 0; i < 100; i++ {
+				polyutil.SetRandomPolynomial(&a, maxDegree)
+				polyutil.SetRandomPolynomial(&b, maxDegree-1)
+				threshold := rand.Intn(maxDegree-1) + 1
+				eea.Init(a, b)
+				Expect(eea.RunUntil(func(d int) bool { return d < threshold })).To(BeTrue())
+				Expect(eea.Rem().Degree()).To(BeNumerically("<", threshold))
+
+				// For random polynomials the remainder degree drops by one in
+				// each step with overwhelming probability.
+				Expect(eea.Quotients()).To(HaveLen(maxDegree - threshold))
+			}
+
+			eea.Init(a, b)
+			Expect(eea.RunUntil(func(int) bool { return false })).To(BeFalse())
+			Expect(eea.Rem().IsZero()).To(BeTrue())
+		})
+	})
+
+	Context("when sharing a guarded stepper between goroutines", func() {
+		Specify("each run should satisfy the invariant relation at termination", func() {
+			goroutines := 8
+			trials := 20
+			maxDegree := 20
+			eea := NewSyncStepperWithCapacity(maxDegree + 1)
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					a := poly.NewWithCapacity(maxDegree + 1)
+					b := poly.NewWithCapacity(maxDegree + 1)
+					temp1 := poly.NewWithCapacity(2 * (maxDegree + 1))
+					temp2 := poly.NewWithCapacity(2 * (maxDegree + 1))
+					rem := poly.NewWithCapacity(2 * (maxDegree + 1))
+					for i := 0; i < trials; i++ {
+						polyutil.SetRandomPolynomial(&a, rand.Intn(maxDegree+1))
+						polyutil.SetRandomPolynomial(&b, rand.Intn(maxDegree)+1)
+						eea.Do(func(eea *Stepper) {
+							eea.Init(a, b)
+							for !eea.Step() {
+							}
+							temp1.Mul(a, *eea.S())
+							temp2.Mul(b, *eea.T())
+							rem.Add(temp1, temp2)
+							Expect(rem.Eq(*eea.Rem())).To(BeTrue())
+						})
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	})
 })
@@ -0,0 +1,125 @@
+package eea
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/poly"
+)
+
+// GCD returns the greatest common divisor of the two polynomials. The result
+// is monic, that is, its leading coefficient is one, except when both
+// polynomials are zero, in which case the zero polynomial is returned.
+func GCD(a, b poly.Poly) poly.Poly {
+	if b.IsZero() {
+		return monic(a)
+	}
+	eea := NewStepperWithCapacity(capFor(a, b))
+	eea.Init(a, b)
+	for !eea.Step() {
+	}
+	// When the algorithm terminates, the last non zero remainder is the
+	// previous one.
+	return monic(eea.rPrev)
+}
+
+// ModInverse returns the inverse of the polynomial a modulo the polynomial m,
+// that is, the polynomial x of degree less than deg(m) such that a x = 1 mod m.
+// The returned boolean is false if the inverse does not exist, which is the
+// case exactly when a and m have a non constant common divisor, or when a is
+// divisible by m.
+//
+// Panics: This function will panic if m has degree 0.
+func ModInverse(a, m poly.Poly) (poly.Poly, bool) {
+	if m.Degree() == 0 {
+		panic("modulus must have positive degree")
+	}
+
+	c := capFor(a, m)
+	q, r := poly.NewWithCapacity(c), poly.NewWithCapacity(c)
+	poly.Divide(a, m, &q, &r)
+	if r.IsZero() {
+		return nil, false
+	}
+
+	// At every step, rNext = s m + t a. When the algorithm terminates, the
+	// previous remainder is the greatest common divisor, and so if it is a
+	// constant g, then t/g is the inverse.
+	eea := NewStepperWithCapacity(c)
+	eea.Init(m, r)
+	for !eea.Step() {
+	}
+	if eea.rPrev.Degree() != 0 {
+		return nil, false
+	}
+	var gInv secp256k1.Fn
+	gInv.Inverse(eea.rPrev.Coefficient(0))
+	inv := poly.NewWithCapacity(c)
+	inv.ScalarMul(eea.tPrev, gInv)
+	return inv, true
+}
+
+// Resultant returns the resultant of the two polynomials, which is zero if
+// and only if they have a common root in the algebraic closure of the field,
+// or one of them is zero. If a has leading coefficient c and roots x_i, then
+// the resultant is c^deg(b) times the product of the b(x_i). The resultant of
+// two non zero constant polynomials is one.
+func Resultant(a, b poly.Poly) secp256k1.Fn {
+	var res secp256k1.Fn
+	if a.IsZero() || b.IsZero() {
+		res.Clear()
+		return res
+	}
+
+	c := capFor(a, b)
+	x, y := poly.NewWithCapacity(c), poly.NewWithCapacity(c)
+	q, r := poly.NewWithCapacity(c), poly.NewWithCapacity(c)
+	x.Set(a)
+	y.Set(b)
+
+	// This uses the identity res(x, y) = (-1)^(deg(x) deg(y)) lc(y)^(deg(x) -
+	// deg(r)) res(y, r), where r = x mod y, and res(x, y) = lc(y)^deg(x) when
+	// y is constant.
+	res.SetU16(1)
+	for y.Degree() > 0 {
+		poly.Divide(x, y, &q, &r)
+		if r.IsZero() {
+			res.Clear()
+			return res
+		}
+		if x.Degree()%2 == 1 && y.Degree()%2 == 1 {
+			res.Negate(&res)
+		}
+		mulPow(&res, y.Coefficient(y.Degree()), x.Degree()-r.Degree())
+		x, y, r = y, r, x
+	}
+	mulPow(&res, y.Coefficient(0), x.Degree())
+	return res
+}
+
+// Returns a copy of the given polynomial scaled so that its leading
+// coefficient is one, or the zero polynomial if it is zero.
+func monic(a poly.Poly) poly.Poly {
+	p := poly.NewWithCapacity(len(a))
+	if a.IsZero() {
+		return p
+	}
+	var lcInv secp256k1.Fn
+	lcInv.Inverse(a.Coefficient(a.Degree()))
+	p.ScalarMul(a, lcInv)
+	return p
+}
+
+// Sets dst to dst x^e.
+func mulPow(dst, x *secp256k1.Fn, e int) {
+	for i := 0; i < e; i++ {
+		dst.Mul(dst, x)
+	}
+}
+
+// Returns a capacity that is large enough for all of the intermediate
+// polynomials when running the EEA on the given polynomials.
+func capFor(a, b poly.Poly) int {
+	if len(a) > len(b) {
+		return len(a) + 1
+	}
+	return len(b) + 1
+}
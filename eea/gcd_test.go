@@ -0,0 +1,199 @@
+package eea_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/eea"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/poly/polyutil"
+)
+
+var _ = Describe("GCD, modular inverse and resultant", func() {
+	trials := 200
+	maxDegree := 10
+
+	randomPoly := func(degree int) poly.Poly {
+		p := poly.NewWithCapacity(maxDegree + 1)
+		polyutil.SetRandomPolynomial(&p, degree)
+		return p
+	}
+
+	mul := func(a, b poly.Poly) poly.Poly {
+		p := poly.NewWithCapacity(a.Degree() + b.Degree() + 1)
+		p.Mul(a, b)
+		return p
+	}
+
+	isMonic := func(p poly.Poly) bool {
+		return p.Coefficient(p.Degree()).IsOne()
+	}
+
+	Context("GCD", func() {
+		It("should return the monic common factor of two polynomials", func() {
+			for i := 0; i < trials; i++ {
+				g := randomPoly(rand.Intn(maxDegree) + 1)
+				a := mul(g, randomPoly(rand.Intn(maxDegree+1)))
+				b := mul(g, randomPoly(rand.Intn(maxDegree+1)))
+
+				gcd := GCD(a, b)
+				Expect(isMonic(gcd)).To(BeTrue())
+
+				// The random cofactors are coprime with overwhelming
+				// probability, so the gcd is g up to a scalar.
+				var lcInv secp256k1.Fn
+				lcInv.Inverse(g.Coefficient(g.Degree()))
+				g.ScalarMul(g, lcInv)
+				Expect(gcd.Eq(g)).To(BeTrue())
+			}
+		})
+
+		It("should return one for coprime polynomials", func() {
+			one := poly.NewFromSlice([]secp256k1.Fn{secp256k1.NewFnFromU16(1)})
+			for i := 0; i < trials; i++ {
+				a := randomPoly(rand.Intn(maxDegree) + 1)
+				b := randomPoly(rand.Intn(maxDegree) + 1)
+				gcd := GCD(a, b)
+				Expect(gcd.Eq(one)).To(BeTrue())
+			}
+		})
+
+		It("should return the monic polynomial when the other is zero", func() {
+			zero := poly.NewWithCapacity(1)
+			for i := 0; i < trials; i++ {
+				a := randomPoly(rand.Intn(maxDegree + 1))
+				gcd := GCD(a, zero)
+				Expect(isMonic(gcd)).To(BeTrue())
+				Expect(gcd.Degree()).To(Equal(a.Degree()))
+				Expect(gcd.Eq(GCD(zero, a))).To(BeTrue())
+			}
+			gcd := GCD(zero, zero)
+			Expect(gcd.IsZero()).To(BeTrue())
+		})
+
+		It("should not modify the arguments", func() {
+			a := randomPoly(maxDegree)
+			b := randomPoly(maxDegree - 1)
+			aCopy, bCopy := poly.NewFromSlice(a), poly.NewFromSlice(b)
+			_ = GCD(a, b)
+			Expect(a.Eq(aCopy)).To(BeTrue())
+			Expect(b.Eq(bCopy)).To(BeTrue())
+		})
+	})
+
+	Context("ModInverse", func() {
+		It("should return the inverse when it exists", func() {
+			for i := 0; i < trials; i++ {
+				m := randomPoly(rand.Intn(maxDegree) + 1)
+				a := randomPoly(rand.Intn(maxDegree + 1))
+
+				inv, ok := ModInverse(a, m)
+				Expect(ok).To(BeTrue())
+				Expect(inv.Degree() < m.Degree()).To(BeTrue())
+
+				prod := mul(a, inv)
+				q := poly.NewWithCapacity(prod.Degree() + 1)
+				r := poly.NewWithCapacity(prod.Degree() + 1)
+				poly.Divide(prod, m, &q, &r)
+				Expect(r.Degree()).To(Equal(0))
+				Expect(r.Coefficient(0).IsOne()).To(BeTrue())
+			}
+		})
+
+		It("should fail when the polynomials have a common factor", func() {
+			for i := 0; i < trials; i++ {
+				g := randomPoly(rand.Intn(maxDegree) + 1)
+				m := mul(g, randomPoly(rand.Intn(maxDegree)+1))
+				a := mul(g, randomPoly(rand.Intn(maxDegree+1)))
+
+				_, ok := ModInverse(a, m)
+				Expect(ok).To(BeFalse())
+			}
+		})
+
+		It("should fail when the polynomial is a multiple of the modulus", func() {
+			m := randomPoly(maxDegree)
+			_, ok := ModInverse(mul(m, randomPoly(2)), m)
+			Expect(ok).To(BeFalse())
+			_, ok = ModInverse(poly.NewWithCapacity(1), m)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should panic when the modulus is constant", func() {
+			a := randomPoly(maxDegree)
+			Expect(func() { ModInverse(a, randomPoly(0)) }).To(Panic())
+		})
+	})
+
+	Context("Resultant", func() {
+		It("should be the product of the evaluations at the roots", func() {
+			for i := 0; i < trials; i++ {
+				// Construct a monic polynomial with known roots.
+				numRoots := rand.Intn(maxDegree) + 1
+				a := poly.NewWithCapacity(maxDegree + 1)
+				a.Coefficient(0).SetU16(1)
+				roots := make([]secp256k1.Fn, numRoots)
+				for j := range roots {
+					roots[j] = secp256k1.RandomFn()
+					linear := poly.NewFromSlice([]secp256k1.Fn{roots[j], secp256k1.NewFnFromU16(1)})
+					linear.Coefficient(0).Negate(linear.Coefficient(0))
+					a.Mul(a, linear)
+				}
+				b := randomPoly(rand.Intn(maxDegree + 1))
+
+				expected := secp256k1.NewFnFromU16(1)
+				for j := range roots {
+					eval := b.Evaluate(roots[j])
+					expected.Mul(&expected, &eval)
+				}
+				res := Resultant(a, b)
+				Expect(res.Eq(&expected)).To(BeTrue())
+
+				// Swapping the arguments changes the sign by
+				// (-1)^(deg(a) deg(b)).
+				swapped := Resultant(b, a)
+				if a.Degree()%2 == 1 && b.Degree()%2 == 1 {
+					swapped.Negate(&swapped)
+				}
+				Expect(swapped.Eq(&expected)).To(BeTrue())
+			}
+		})
+
+		It("should be zero when the polynomials have a common factor", func() {
+			for i := 0; i < trials; i++ {
+				g := randomPoly(rand.Intn(maxDegree) + 1)
+				a := mul(g, randomPoly(rand.Intn(maxDegree+1)))
+				b := mul(g, randomPoly(rand.Intn(maxDegree+1)))
+				res := Resultant(a, b)
+				Expect(res.IsZero()).To(BeTrue())
+			}
+		})
+
+		It("should be zero when either polynomial is zero", func() {
+			zero := poly.NewWithCapacity(1)
+			a := randomPoly(maxDegree)
+			res := Resultant(a, zero)
+			Expect(res.IsZero()).To(BeTrue())
+			res = Resultant(zero, a)
+			Expect(res.IsZero()).To(BeTrue())
+		})
+
+		It("should be a power of the constant when one polynomial is constant", func() {
+			for i := 0; i < trials; i++ {
+				a := randomPoly(rand.Intn(maxDegree + 1))
+				c := randomPoly(0)
+				expected := secp256k1.NewFnFromU16(1)
+				for j := 0; j < a.Degree(); j++ {
+					expected.Mul(&expected, c.Coefficient(0))
+				}
+				res := Resultant(a, c)
+				Expect(res.Eq(&expected)).To(BeTrue())
+				res = Resultant(c, a)
+				Expect(res.Eq(&expected)).To(BeTrue())
+			}
+		})
+	})
+})
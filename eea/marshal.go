@@ -5,6 +5,7 @@ import (
 	"reflect"
 
 	"github.com/renproject/shamir/poly"
+	"github.com/renproject/surge"
 )
 
 // Generate implements the quick.Generator interface.
@@ -18,11 +19,16 @@ func (eea Stepper) Generate(rand *rand.Rand, size int) reflect.Value {
 	tNext := poly.Poly{}.Generate(rand, size).Interface().(poly.Poly)
 	q := poly.Poly{}.Generate(rand, size).Interface().(poly.Poly)
 	r := poly.Poly{}.Generate(rand, size).Interface().(poly.Poly)
+	var qs []poly.Poly
+	for i := rand.Intn(size/8 + 1); i > 0; i-- {
+		qs = append(qs, poly.Poly{}.Generate(rand, size).Interface().(poly.Poly))
+	}
 	stepper := Stepper{
 		rPrev: rPrev, rNext: rNext,
 		sPrev: sPrev, sNext: sNext,
 		tPrev: tPrev, tNext: tNext,
 		q: q, r: r,
+		qs: qs,
 	}
 	return reflect.ValueOf(stepper)
 }
@@ -36,7 +42,8 @@ func (eea Stepper) SizeHint() int {
 		eea.tNext.SizeHint() +
 		eea.tPrev.SizeHint() +
 		eea.q.SizeHint() +
-		eea.r.SizeHint()
+		eea.r.SizeHint() +
+		surge.SizeHint(eea.qs)
 }
 
 // Marshal implements the surge.Marshaler interface.
@@ -69,7 +76,11 @@ func (eea Stepper) Marshal(buf []byte, rem int) ([]byte, int, error) {
 	if err != nil {
 		return buf, rem, err
 	}
-	return eea.r.Marshal(buf, rem)
+	buf, rem, err = eea.r.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.Marshal(eea.qs, buf, rem)
 }
 
 // Unmarshal implements the surge.Unmarshaler interface.
@@ -102,5 +113,25 @@ func (eea *Stepper) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
 	if err != nil {
 		return buf, rem, err
 	}
-	return eea.r.Unmarshal(buf, rem)
+	buf, rem, err = eea.r.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	buf, rem, err = surge.UnmarshalLen(&l, 2*surge.SizeHintU32, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if eea.qs == nil && l > 0 {
+		eea.qs = make([]poly.Poly, 0, l)
+	}
+	eea.qs = eea.qs[:0]
+	for i := uint32(0); i < l; i++ {
+		eea.qs = append(eea.qs, poly.Poly{})
+		buf, rem, err = eea.qs[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
 }
@@ -0,0 +1,160 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/surge"
+)
+
+// A WireVersion identifies a version of the enveloped wire format.
+type WireVersion uint8
+
+// WireV1 is the first version of the enveloped wire format. An envelope
+// consists of the version byte, a byte identifying the type of the payload,
+// and the surge encoding of the payload.
+const WireV1 WireVersion = 1
+
+// The type tags of the payloads that can be enveloped.
+const (
+	wireTypeShare           = 1
+	wireTypeVerifiableShare = 2
+	wireTypeCommitment      = 3
+	wireTypeSharingMetadata = 4
+	wireTypeTaggedShare     = 5
+)
+
+// The length of the envelope header that precedes the payload.
+const envelopeHeaderSize = 2
+
+// ErrUnsupportedWireVersion is returned when decoding an envelope with a
+// version that this package does not understand, and when there is no version
+// in common during negotiation.
+var ErrUnsupportedWireVersion = errors.New("unsupported wire version")
+
+// SupportedWireVersions returns the versions of the enveloped wire format that
+// this package can decode, in ascending order.
+func SupportedWireVersions() []WireVersion {
+	return []WireVersion{WireV1}
+}
+
+// NegotiateWireVersion returns the highest version of the enveloped wire
+// format that is supported both by this package and by a peer that supports
+// the given versions. ErrUnsupportedWireVersion is returned if there is no
+// such version.
+func NegotiateWireVersion(peer []WireVersion) (WireVersion, error) {
+	supported := SupportedWireVersions()
+	for i := len(supported) - 1; i >= 0; i-- {
+		for _, v := range peer {
+			if v == supported[i] {
+				return v, nil
+			}
+		}
+	}
+	return 0, ErrUnsupportedWireVersion
+}
+
+// EncodeV1 encodes the given value, which must be a Share, VerifiableShare,
+// Commitment, SharingMetadata or TaggedShare (or a pointer to one), in version
+// 1 of the enveloped wire format.
+func EncodeV1(v interface{}) ([]byte, error) {
+	var tag byte
+	var payload surge.Marshaler
+	switch v := v.(type) {
+	case Share:
+		tag, payload = wireTypeShare, v
+	case *Share:
+		tag, payload = wireTypeShare, *v
+	case VerifiableShare:
+		tag, payload = wireTypeVerifiableShare, v
+	case *VerifiableShare:
+		tag, payload = wireTypeVerifiableShare, *v
+	case Commitment:
+		tag, payload = wireTypeCommitment, v
+	case *Commitment:
+		tag, payload = wireTypeCommitment, *v
+	case SharingMetadata:
+		tag, payload = wireTypeSharingMetadata, v
+	case *SharingMetadata:
+		tag, payload = wireTypeSharingMetadata, *v
+	case TaggedShare:
+		tag, payload = wireTypeTaggedShare, v
+	case *TaggedShare:
+		tag, payload = wireTypeTaggedShare, *v
+	default:
+		return nil, fmt.Errorf("cannot encode value of type %T", v)
+	}
+
+	buf := make([]byte, envelopeHeaderSize+payload.SizeHint())
+	buf[0], buf[1] = byte(WireV1), tag
+	if _, _, err := payload.Marshal(buf[envelopeHeaderSize:], surge.MaxBytes); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// DecodeAny decodes an envelope in any supported version of the wire format.
+// The returned value is a Share, VerifiableShare, Commitment, SharingMetadata
+// or TaggedShare, depending on what was encoded. An error is returned if the
+// envelope has an unsupported version or unknown type, or if there are bytes
+// left over after the payload.
+//
+// Encodings without an envelope are not accepted; use DecodeLegacy for those.
+func DecodeAny(buf []byte) (interface{}, error) {
+	if len(buf) < envelopeHeaderSize {
+		return nil, surge.ErrUnexpectedEndOfBuffer
+	}
+	if WireVersion(buf[0]) != WireV1 {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedWireVersion, buf[0])
+	}
+
+	payload := buf[envelopeHeaderSize:]
+	switch buf[1] {
+	case wireTypeShare:
+		var share Share
+		if err := DecodeLegacy(payload, &share); err != nil {
+			return nil, err
+		}
+		return share, nil
+	case wireTypeVerifiableShare:
+		var vshare VerifiableShare
+		if err := DecodeLegacy(payload, &vshare); err != nil {
+			return nil, err
+		}
+		return vshare, nil
+	case wireTypeCommitment:
+		var c Commitment
+		if err := DecodeLegacy(payload, &c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	case wireTypeSharingMetadata:
+		var m SharingMetadata
+		if err := DecodeLegacy(payload, &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case wireTypeTaggedShare:
+		var ts TaggedShare
+		if err := DecodeLegacy(payload, &ts); err != nil {
+			return nil, err
+		}
+		return ts, nil
+	default:
+		return nil, fmt.Errorf("unknown payload type %v", buf[1])
+	}
+}
+
+// DecodeLegacy decodes the raw surge encoding of a value without an envelope,
+// as produced by surge.ToBinary, into the given destination. An error is
+// returned if there are bytes left over after the value.
+func DecodeLegacy(buf []byte, dst surge.Unmarshaler) error {
+	rest, _, err := dst.Unmarshal(buf, surge.MaxBytes)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return fmt.Errorf("%v unexpected trailing bytes", len(rest))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package shamir_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Versioned wire format", func() {
+	trials := 20
+
+	It("should decode what was encoded", func() {
+		for i := 0; i < trials; i++ {
+			share := NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			vshare := NewVerifiableShare(share, secp256k1.RandomFn())
+			c := RandomCommitment(RandRange(1, 10))
+
+			for _, v := range []interface{}{share, &share, vshare, &vshare, c, &c} {
+				bs, err := EncodeV1(v)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bs[0]).To(Equal(byte(WireV1)))
+
+				decoded, err := DecodeAny(bs)
+				Expect(err).ToNot(HaveOccurred())
+				switch decoded := decoded.(type) {
+				case Share:
+					Expect(decoded.Eq(&share)).To(BeTrue())
+				case VerifiableShare:
+					Expect(decoded.Eq(&vshare)).To(BeTrue())
+				case Commitment:
+					Expect(decoded.Eq(c)).To(BeTrue())
+				default:
+					Fail("unexpected decoded type")
+				}
+			}
+		}
+	})
+
+	It("should wrap the legacy encoding", func() {
+		vshare := NewVerifiableShare(
+			NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+			secp256k1.RandomFn(),
+		)
+		legacy, err := surge.ToBinary(vshare)
+		Expect(err).ToNot(HaveOccurred())
+		bs, err := EncodeV1(vshare)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bs[2:]).To(Equal(legacy))
+
+		var decoded VerifiableShare
+		Expect(DecodeLegacy(legacy, &decoded)).To(Succeed())
+		Expect(decoded.Eq(&vshare)).To(BeTrue())
+		Expect(DecodeLegacy(append(legacy, 0), &decoded)).ToNot(Succeed())
+	})
+
+	It("should return an error for invalid envelopes", func() {
+		c := RandomCommitment(RandRange(1, 10))
+		bs, err := EncodeV1(c)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = DecodeAny(bs[:1])
+		Expect(err).To(HaveOccurred())
+		_, err = DecodeAny(bs[:len(bs)-1])
+		Expect(err).To(HaveOccurred())
+		_, err = DecodeAny(append(bs, 0))
+		Expect(err).To(HaveOccurred())
+
+		unknownVersion := append([]byte{}, bs...)
+		unknownVersion[0] = 2
+		_, err = DecodeAny(unknownVersion)
+		Expect(errors.Is(err, ErrUnsupportedWireVersion)).To(BeTrue())
+
+		unknownType := append([]byte{}, bs...)
+		unknownType[1] = 0xFF
+		_, err = DecodeAny(unknownType)
+		Expect(err).To(HaveOccurred())
+
+		_, err = EncodeV1(Shares{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should negotiate the highest common version", func() {
+		v, err := NegotiateWireVersion([]WireVersion{WireV1, 7})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v).To(Equal(WireV1))
+		Expect(SupportedWireVersions()).To(ContainElement(WireV1))
+
+		_, err = NegotiateWireVersion([]WireVersion{7})
+		Expect(err).To(Equal(ErrUnsupportedWireVersion))
+		_, err = NegotiateWireVersion(nil)
+		Expect(err).To(Equal(ErrUnsupportedWireVersion))
+	})
+})
@@ -0,0 +1,129 @@
+// Package fnbatch implements arithmetic on slices of elements of the scalar
+// field of secp256k1, for the inner loops of sharing, opening and
+// interpolation.
+//
+// The arithmetic of secp256k1.Fn is done by the C library, and each operation
+// is a separate cgo call whose overhead is comparable to the cost of the
+// operation itself: an addition costs about as much as a multiplication. On
+// amd64 and arm64, the functions of this package instead operate on the four
+// 64 bit limbs of the elements directly, using the 64 bit multiply and add
+// with carry instructions that math/bits compiles to, so that a whole slice is
+// processed without leaving Go. On other architectures they fall back to the
+// methods of secp256k1.Fn, and give the same results.
+//
+// The limb arithmetic does not branch on the values of the elements, so it is
+// suitable for secret values.
+package fnbatch
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Initialising an element with its methods makes it escape to the heap, so the
+// constant one is copied from here instead.
+var one = secp256k1.NewFnFromU16(1)
+
+// Mul stores the product of a[i] and b[i] in dst[i] for every i. The slices
+// may alias.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func Mul(dst, a, b []secp256k1.Fn) {
+	checkLen(len(dst), len(a), len(b))
+	for i := range dst {
+		mul(&dst[i], &a[i], &b[i])
+	}
+}
+
+// Add stores the sum of a[i] and b[i] in dst[i] for every i. The slices may
+// alias.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func Add(dst, a, b []secp256k1.Fn) {
+	checkLen(len(dst), len(a), len(b))
+	for i := range dst {
+		add(&dst[i], &a[i], &b[i])
+	}
+}
+
+// SubScalar stores a[i] - s in dst[i] for every i. The slices may alias.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func SubScalar(dst, a []secp256k1.Fn, s *secp256k1.Fn) {
+	checkLen(len(dst), len(a), len(a))
+	t := *s
+	for i := range dst {
+		sub(&dst[i], &a[i], &t)
+	}
+}
+
+// Scale stores s*a[i] in dst[i] for every i. The slices may alias.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func Scale(dst, a []secp256k1.Fn, s *secp256k1.Fn) {
+	checkLen(len(dst), len(a), len(a))
+	t := *s
+	for i := range dst {
+		mul(&dst[i], &a[i], &t)
+	}
+}
+
+// AddScaled stores a[i] + s*b[i] in dst[i] for every i. The slices may alias.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func AddScaled(dst, a, b []secp256k1.Fn, s *secp256k1.Fn) {
+	checkLen(len(dst), len(a), len(b))
+	t := *s
+	var scaled secp256k1.Fn
+	for i := range dst {
+		mul(&scaled, &b[i], &t)
+		add(&dst[i], &a[i], &scaled)
+	}
+}
+
+// Product returns the product of the elements of the slice, which is one if
+// the slice is empty.
+func Product(xs []secp256k1.Fn) secp256k1.Fn {
+	res := one
+	for i := range xs {
+		mul(&res, &res, &xs[i])
+	}
+	return res
+}
+
+// InnerProduct returns the sum of a[i]*b[i] over every i.
+//
+// Panics: This function will panic if the slices do not have the same length.
+func InnerProduct(a, b []secp256k1.Fn) secp256k1.Fn {
+	checkLen(len(a), len(a), len(b))
+	var res, term secp256k1.Fn
+	for i := range a {
+		mul(&term, &a[i], &b[i])
+		add(&res, &res, &term)
+	}
+	return res
+}
+
+// Eval returns the evaluation at x of the polynomial with the given
+// coefficients, where the coefficient at index 0 is the constant term, using
+// Horner's method. The polynomial with no coefficients is zero.
+func Eval(coeffs []secp256k1.Fn, x *secp256k1.Fn) secp256k1.Fn {
+	var res secp256k1.Fn
+	if len(coeffs) == 0 {
+		return res
+	}
+	t := *x
+	res = coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		mul(&res, &res, &t)
+		add(&res, &res, &coeffs[i])
+	}
+	return res
+}
+
+func checkLen(dst, a, b int) {
+	if a != dst || b != dst {
+		panic(fmt.Sprintf("slice lengths do not match: %v, %v and %v", dst, a, b))
+	}
+}
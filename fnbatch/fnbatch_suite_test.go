@@ -0,0 +1,13 @@
+package fnbatch_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFnbatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fnbatch Suite")
+}
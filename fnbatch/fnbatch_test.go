@@ -0,0 +1,168 @@
+package fnbatch_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/fnbatch"
+)
+
+var _ = Describe("Batch field operations", func() {
+	trials := 100
+	n := 20
+
+	// Random elements, with a bias towards the edge cases zero, one and minus
+	// one, and the elements that are close to them.
+	randomFns := func(n int) []secp256k1.Fn {
+		xs := make([]secp256k1.Fn, n)
+		for i := range xs {
+			switch rand.Intn(4) {
+			case 0:
+				xs[i].SetU16(uint16(rand.Intn(3)))
+			case 1:
+				xs[i].SetU16(uint16(rand.Intn(3)))
+				xs[i].Negate(&xs[i])
+			default:
+				xs[i] = secp256k1.RandomFn()
+			}
+		}
+		return xs
+	}
+
+	eqAll := func(a, b []secp256k1.Fn) bool {
+		for i := range a {
+			if !a[i].Eq(&b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	It("should agree with the element wise operations", func() {
+		for t := 0; t < trials; t++ {
+			a, b, s := randomFns(n), randomFns(n), randomFns(1)[0]
+			dst := make([]secp256k1.Fn, n)
+			expected := make([]secp256k1.Fn, n)
+			var tmp secp256k1.Fn
+
+			Mul(dst, a, b)
+			for i := range expected {
+				expected[i].Mul(&a[i], &b[i])
+			}
+			Expect(eqAll(dst, expected)).To(BeTrue())
+
+			Add(dst, a, b)
+			for i := range expected {
+				expected[i].Add(&a[i], &b[i])
+			}
+			Expect(eqAll(dst, expected)).To(BeTrue())
+
+			SubScalar(dst, a, &s)
+			for i := range expected {
+				tmp.Negate(&s)
+				expected[i].Add(&a[i], &tmp)
+			}
+			Expect(eqAll(dst, expected)).To(BeTrue())
+
+			Scale(dst, a, &s)
+			for i := range expected {
+				expected[i].Mul(&a[i], &s)
+			}
+			Expect(eqAll(dst, expected)).To(BeTrue())
+
+			AddScaled(dst, a, b, &s)
+			for i := range expected {
+				tmp.Mul(&b[i], &s)
+				expected[i].Add(&a[i], &tmp)
+			}
+			Expect(eqAll(dst, expected)).To(BeTrue())
+		}
+	})
+
+	It("should agree with the reductions computed element by element", func() {
+		for t := 0; t < trials; t++ {
+			a, b, x := randomFns(n), randomFns(n), randomFns(1)[0]
+			var product, inner, eval, tmp secp256k1.Fn
+			product.SetU16(1)
+			for i := range a {
+				product.Mul(&product, &a[i])
+				tmp.Mul(&a[i], &b[i])
+				inner.Add(&inner, &tmp)
+			}
+			for i := len(a) - 1; i >= 0; i-- {
+				eval.Mul(&eval, &x)
+				eval.Add(&eval, &a[i])
+			}
+
+			res := Product(a)
+			Expect(res.Eq(&product)).To(BeTrue())
+			res = InnerProduct(a, b)
+			Expect(res.Eq(&inner)).To(BeTrue())
+			res = Eval(a, &x)
+			Expect(res.Eq(&eval)).To(BeTrue())
+		}
+
+		one := secp256k1.NewFnFromU16(1)
+		res := Product(nil)
+		Expect(res.IsOne()).To(BeTrue())
+		res = Eval(nil, &one)
+		Expect(res.IsZero()).To(BeTrue())
+	})
+
+	It("should allow the destination to alias the arguments", func() {
+		a, b := randomFns(n), randomFns(n)
+		expected := make([]secp256k1.Fn, n)
+		for i := range expected {
+			expected[i].Mul(&a[i], &a[i])
+			expected[i].Mul(&expected[i], &b[i])
+		}
+		Mul(a, a, a)
+		Mul(a, a, b)
+		Expect(eqAll(a, expected)).To(BeTrue())
+	})
+
+	It("should panic if the lengths do not match", func() {
+		a, b := randomFns(n), randomFns(n-1)
+		s := secp256k1.RandomFn()
+		Expect(func() { Mul(a, a, b) }).To(Panic())
+		Expect(func() { Add(b, a, a) }).To(Panic())
+		Expect(func() { SubScalar(b, a, &s) }).To(Panic())
+		Expect(func() { Scale(b, a, &s) }).To(Panic())
+		Expect(func() { AddScaled(a, a, b, &s) }).To(Panic())
+		Expect(func() { InnerProduct(a, b) }).To(Panic())
+	})
+})
+
+func BenchmarkMul(b *testing.B) {
+	n := 100
+	xs := make([]secp256k1.Fn, n)
+	ys := make([]secp256k1.Fn, n)
+	for i := range xs {
+		xs[i], ys[i] = secp256k1.RandomFn(), secp256k1.RandomFn()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Mul(xs, xs, ys)
+	}
+}
+
+func BenchmarkMulElementWise(b *testing.B) {
+	n := 100
+	xs := make([]secp256k1.Fn, n)
+	ys := make([]secp256k1.Fn, n)
+	for i := range xs {
+		xs[i], ys[i] = secp256k1.RandomFn(), secp256k1.RandomFn()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range xs {
+			xs[j].Mul(&xs[j], &ys[j])
+		}
+	}
+}
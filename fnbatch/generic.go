@@ -0,0 +1,18 @@
+//go:build !amd64 && !arm64
+// +build !amd64,!arm64
+
+package fnbatch
+
+import (
+	"github.com/renproject/secp256k1"
+)
+
+func add(z, x, y *secp256k1.Fn) { z.Add(x, y) }
+
+func sub(z, x, y *secp256k1.Fn) {
+	var neg secp256k1.Fn
+	neg.Negate(y)
+	z.Add(x, &neg)
+}
+
+func mul(z, x, y *secp256k1.Fn) { z.Mul(x, y) }
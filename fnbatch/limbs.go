@@ -0,0 +1,200 @@
+//go:build amd64 || arm64
+// +build amd64 arm64
+
+package fnbatch
+
+import (
+	"math/bits"
+	"unsafe"
+
+	"github.com/renproject/secp256k1"
+)
+
+// On these architectures the C library represents an element as four 64 bit
+// limbs, least significant first, and this fails to compile if the size of an
+// element is not that of the limbs.
+var _ = [1]struct{}{}[secp256k1.FnSize-32]
+
+// The limbs of the order n of the group, and of 2^256 - n.
+const (
+	n0 = 0xBFD25E8CD0364141
+	n1 = 0xBAAEDCE6AF48A03B
+	n2 = 0xFFFFFFFFFFFFFFFE
+	n3 = 0xFFFFFFFFFFFFFFFF
+
+	nc0 = 0x402DA1732FC9BEBF
+	nc1 = 0x4551231950B75FC4
+)
+
+func limbs(x *secp256k1.Fn) *[4]uint64 {
+	return (*[4]uint64)(unsafe.Pointer(x))
+}
+
+func add(z, x, y *secp256k1.Fn) {
+	a, b := limbs(x), limbs(y)
+	var s0, s1, s2, s3, c uint64
+	s0, c = bits.Add64(a[0], b[0], 0)
+	s1, c = bits.Add64(a[1], b[1], c)
+	s2, c = bits.Add64(a[2], b[2], c)
+	s3, c = bits.Add64(a[3], b[3], c)
+	reduceOnce(limbs(z), s0, s1, s2, s3, c)
+}
+
+func sub(z, x, y *secp256k1.Fn) {
+	a, b := limbs(x), limbs(y)
+	var d0, d1, d2, d3, borrow uint64
+	d0, borrow = bits.Sub64(a[0], b[0], 0)
+	d1, borrow = bits.Sub64(a[1], b[1], borrow)
+	d2, borrow = bits.Sub64(a[2], b[2], borrow)
+	d3, borrow = bits.Sub64(a[3], b[3], borrow)
+
+	// Add n back if the subtraction wrapped around.
+	mask := -borrow
+	var c uint64
+	r := limbs(z)
+	r[0], c = bits.Add64(d0, n0&mask, 0)
+	r[1], c = bits.Add64(d1, n1&mask, c)
+	r[2], c = bits.Add64(d2, n2&mask, c)
+	r[3], _ = bits.Add64(d3, n3&mask, c)
+}
+
+func mul(z, x, y *secp256k1.Fn) {
+	a, b := limbs(x), limbs(y)
+
+	// The product is accumulated column by column in the 192 bit
+	// accumulator (c0, c1, c2).
+	var l0, l1, l2, l3, l4, l5, l6, l7 uint64
+	var c0, c1, c2 uint64
+	c0, c1, c2 = muladd(c0, c1, c2, a[0], b[0])
+	l0, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[0], b[1])
+	c0, c1, c2 = muladd(c0, c1, c2, a[1], b[0])
+	l1, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[0], b[2])
+	c0, c1, c2 = muladd(c0, c1, c2, a[1], b[1])
+	c0, c1, c2 = muladd(c0, c1, c2, a[2], b[0])
+	l2, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[0], b[3])
+	c0, c1, c2 = muladd(c0, c1, c2, a[1], b[2])
+	c0, c1, c2 = muladd(c0, c1, c2, a[2], b[1])
+	c0, c1, c2 = muladd(c0, c1, c2, a[3], b[0])
+	l3, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[1], b[3])
+	c0, c1, c2 = muladd(c0, c1, c2, a[2], b[2])
+	c0, c1, c2 = muladd(c0, c1, c2, a[3], b[1])
+	l4, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[2], b[3])
+	c0, c1, c2 = muladd(c0, c1, c2, a[3], b[2])
+	l5, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, a[3], b[3])
+	l6, l7 = c0, c1
+	_ = c2
+
+	reduce512(limbs(z), l0, l1, l2, l3, l4, l5, l6, l7)
+}
+
+// Reduces the 512 bit product modulo n, using 2^256 = 2^256 - n (mod n) to
+// fold the high limbs into the low limbs, as in the C library. For a product
+// of two reduced elements, the first fold leaves at most 385 bits, the second
+// at most 258 bits, and the third a value less than 2n.
+func reduce512(z *[4]uint64, l0, l1, l2, l3, l4, l5, l6, l7 uint64) {
+	var c0, c1, c2 uint64
+
+	// m = l[0..3] + l[4..7] * (2^256 - n).
+	var m0, m1, m2, m3, m4, m5, m6 uint64
+	c0 = l0
+	c0, c1, c2 = muladd(c0, c1, c2, l4, nc0)
+	m0, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, l1)
+	c0, c1, c2 = muladd(c0, c1, c2, l5, nc0)
+	c0, c1, c2 = muladd(c0, c1, c2, l4, nc1)
+	m1, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, l2)
+	c0, c1, c2 = muladd(c0, c1, c2, l6, nc0)
+	c0, c1, c2 = muladd(c0, c1, c2, l5, nc1)
+	c0, c1, c2 = sumadd(c0, c1, c2, l4)
+	m2, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, l3)
+	c0, c1, c2 = muladd(c0, c1, c2, l7, nc0)
+	c0, c1, c2 = muladd(c0, c1, c2, l6, nc1)
+	c0, c1, c2 = sumadd(c0, c1, c2, l5)
+	m3, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = muladd(c0, c1, c2, l7, nc1)
+	c0, c1, c2 = sumadd(c0, c1, c2, l6)
+	m4, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, l7)
+	m5, m6 = c0, c1
+	_ = c2
+
+	// p = m[0..3] + m[4..6] * (2^256 - n).
+	var p0, p1, p2, p3, p4 uint64
+	c0, c1, c2 = m0, 0, 0
+	c0, c1, c2 = muladd(c0, c1, c2, m4, nc0)
+	p0, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, m1)
+	c0, c1, c2 = muladd(c0, c1, c2, m5, nc0)
+	c0, c1, c2 = muladd(c0, c1, c2, m4, nc1)
+	p1, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, m2)
+	c0, c1, c2 = muladd(c0, c1, c2, m6, nc0)
+	c0, c1, c2 = muladd(c0, c1, c2, m5, nc1)
+	c0, c1, c2 = sumadd(c0, c1, c2, m4)
+	p2, c0, c1, c2 = c0, c1, c2, 0
+	c0, c1, c2 = sumadd(c0, c1, c2, m3)
+	c0, c1, c2 = muladd(c0, c1, c2, m6, nc1)
+	c0, c1, c2 = sumadd(c0, c1, c2, m5)
+	p3, c0, c1, c2 = c0, c1, c2, 0
+	p4 = c0 + m6
+	_, _ = c1, c2
+
+	// r = p[0..3] + p4 * (2^256 - n), with a carry of at most one.
+	var r0, r1, r2, r3, hi, lo, c uint64
+	hi, lo = bits.Mul64(p4, nc0)
+	r0, c = bits.Add64(p0, lo, 0)
+	hi += c
+	hi2, lo2 := bits.Mul64(p4, nc1)
+	lo2, c = bits.Add64(lo2, hi, 0)
+	hi2 += c
+	r1, c = bits.Add64(p1, lo2, 0)
+	hi2 += c
+	lo, c = bits.Add64(p4, hi2, 0)
+	r2, c2 = bits.Add64(p2, lo, 0)
+	c += c2
+	r3, c = bits.Add64(p3, c, 0)
+	reduceOnce(z, r0, r1, r2, r3, c)
+}
+
+// Returns the accumulator (c0, c1, c2) plus a*b.
+func muladd(c0, c1, c2, a, b uint64) (uint64, uint64, uint64) {
+	hi, lo := bits.Mul64(a, b)
+	var c uint64
+	c0, c = bits.Add64(c0, lo, 0)
+	hi += c
+	c1, c = bits.Add64(c1, hi, 0)
+	return c0, c1, c2 + c
+}
+
+// Returns the accumulator (c0, c1, c2) plus a.
+func sumadd(c0, c1, c2, a uint64) (uint64, uint64, uint64) {
+	var c uint64
+	c0, c = bits.Add64(c0, a, 0)
+	c1, c = bits.Add64(c1, 0, c)
+	return c0, c1, c2 + c
+}
+
+// Stores s + carry*2^256, which must be less than 2n, reduced modulo n in z.
+func reduceOnce(z *[4]uint64, s0, s1, s2, s3, carry uint64) {
+	var d0, d1, d2, d3, borrow uint64
+	d0, borrow = bits.Sub64(s0, n0, 0)
+	d1, borrow = bits.Sub64(s1, n1, borrow)
+	d2, borrow = bits.Sub64(s2, n2, borrow)
+	d3, borrow = bits.Sub64(s3, n3, borrow)
+
+	// Keep the difference if s + carry*2^256 is at least n, which is when
+	// there was a carry or the subtraction did not borrow.
+	keep := -(carry | (borrow ^ 1))
+	z[0] = d0&keep | s0&^keep
+	z[1] = d1&keep | s1&^keep
+	z[2] = d2&keep | s2&^keep
+	z[3] = d3&keep | s3&^keep
+}
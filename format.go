@@ -0,0 +1,95 @@
+package shamir
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+)
+
+// The placeholder that is printed instead of secret values.
+const redacted = "<redacted>"
+
+// String implements the Stringer interface. Only the index of the share is
+// printed; the value is redacted so that shares can be safely logged.
+func (s Share) String() string {
+	return fmt.Sprintf("Share{Index: %v, Value: %v}", formatFn(&s.Index), redacted)
+}
+
+// Format implements the fmt.Formatter interface. Every verb, including %+v and
+// %#v, prints the same redacted output as String, so that shares can not leak
+// through error wrapping or structured logs. Use DebugString to print the
+// value.
+func (s Share) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+// DebugString returns the share including its value, which is intended for
+// debugging only. The result contains the secret value of the share and must
+// not be logged.
+func (s Share) DebugString() string {
+	return fmt.Sprintf("Share{Index: %v, Value: %v}", formatFn(&s.Index), formatFn(&s.Value))
+}
+
+// String implements the Stringer interface. Only the index of the share is
+// printed; the value and decommitment are redacted so that shares can be
+// safely logged.
+func (vs VerifiableShare) String() string {
+	return fmt.Sprintf(
+		"VerifiableShare{Index: %v, Value: %v, Decommitment: %v}",
+		formatFn(&vs.Share.Index), redacted, redacted,
+	)
+}
+
+// Format implements the fmt.Formatter interface. As for Share, every verb
+// prints the same redacted output as String.
+func (vs VerifiableShare) Format(f fmt.State, verb rune) {
+	io.WriteString(f, vs.String())
+}
+
+// DebugString returns the share including its value and decommitment, which
+// is intended for debugging only. The result contains the secret values of
+// the share and must not be logged.
+func (vs VerifiableShare) DebugString() string {
+	return fmt.Sprintf(
+		"VerifiableShare{Index: %v, Value: %v, Decommitment: %v}",
+		formatFn(&vs.Share.Index), formatFn(&vs.Share.Value), formatFn(&vs.Decommitment),
+	)
+}
+
+// String implements the Stringer interface. The commitment is summarised by
+// its length and a digest of its points, which is enough to tell commitments
+// apart in logs.
+func (c Commitment) String() string {
+	return fmt.Sprintf("Commitment{Len: %v, Digest: %v}", len(c), c.shortDigest())
+}
+
+// Format implements the fmt.Formatter interface. The verb %+v prints every
+// point in the commitment. All other verbs print the same summary as String.
+func (c Commitment) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('+') {
+		io.WriteString(f, "Commitment{")
+		var bs [secp256k1.PointSizeMarshalled]byte
+		for i := range c {
+			if i > 0 {
+				io.WriteString(f, ", ")
+			}
+			c[i].PutBytes(bs[:])
+			io.WriteString(f, hex.EncodeToString(bs[:]))
+		}
+		io.WriteString(f, "}")
+		return
+	}
+	io.WriteString(f, c.String())
+}
+
+// Returns a short hex digest of the commitment, for display purposes only.
+func (c Commitment) shortDigest() string {
+	digest := c.Hash()
+	return hex.EncodeToString(digest[:8])
+}
+
+func formatFn(x *secp256k1.Fn) string {
+	return "0x" + x.Int().Text(16)
+}
@@ -0,0 +1,83 @@
+package shamir_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Formatting", func() {
+	index := secp256k1.NewFnFromU16(42)
+	value := secp256k1.RandomFn()
+	decom := secp256k1.RandomFn()
+	share := NewShare(index, value)
+	vshare := NewVerifiableShare(share, decom)
+	hexOf := func(x secp256k1.Fn) string { return x.Int().Text(16) }
+
+	Context("shares", func() {
+		It("should print the index but not the value", func() {
+			for _, str := range []string{
+				share.String(),
+				fmt.Sprint(share),
+				fmt.Sprintf("%v", &share),
+				fmt.Sprintf("%s", share),
+				fmt.Sprintf("%+v", share),
+				fmt.Sprintf("%#v", share),
+				fmt.Sprint(Shares{share}),
+				fmt.Errorf("bad share: %+v", share).Error(),
+			} {
+				Expect(str).To(ContainSubstring("0x2a"))
+				Expect(str).ToNot(ContainSubstring(hexOf(value)))
+			}
+		})
+
+		It("should print the value only when asked explicitly", func() {
+			str := share.DebugString()
+			Expect(str).To(ContainSubstring("0x2a"))
+			Expect(str).To(ContainSubstring(hexOf(value)))
+		})
+	})
+
+	Context("verifiable shares", func() {
+		It("should print the index but not the value or decommitment", func() {
+			for _, str := range []string{
+				vshare.String(),
+				fmt.Sprint(vshare),
+				fmt.Sprintf("%+v", vshare),
+				fmt.Sprintf("%#v", &vshare),
+				fmt.Sprint(VerifiableShares{vshare}),
+			} {
+				Expect(str).To(ContainSubstring("0x2a"))
+				Expect(str).ToNot(ContainSubstring(hexOf(value)))
+				Expect(str).ToNot(ContainSubstring(hexOf(decom)))
+			}
+		})
+
+		It("should print the value and decommitment only when asked explicitly", func() {
+			str := vshare.DebugString()
+			Expect(str).To(ContainSubstring(hexOf(value)))
+			Expect(str).To(ContainSubstring(hexOf(decom)))
+		})
+	})
+
+	Context("commitments", func() {
+		It("should print a digest that distinguishes commitments", func() {
+			c1, c2 := RandomCommitment(3), RandomCommitment(3)
+			Expect(c1.String()).To(ContainSubstring("Len: 3"))
+			Expect(c1.String()).To(Equal(fmt.Sprint(c1)))
+			Expect(c1.String()).ToNot(Equal(c2.String()))
+		})
+
+		It("should print every point in full dump mode", func() {
+			c := RandomCommitment(3)
+			str := fmt.Sprintf("%+v", c)
+			Expect(strings.Count(str, ",")).To(Equal(2))
+		})
+	})
+})
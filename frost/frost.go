@@ -0,0 +1,409 @@
+// Package frost implements the building blocks of FROST threshold Schnorr
+// signatures, as specified in RFC 9591, for the FROST(secp256k1, SHA-256)
+// ciphersuite, on top of the sharing layer of the shamir package.
+//
+// The signing key is shared with a Feldman commitment, which is a
+// shamir.Commitment to the coefficients of the sharing polynomial multiplied by
+// the base point, either by a trusted dealer using ShareKey, or by a
+// distributed key generation. The group public key is then the first point of
+// the commitment, and the public key share of each participant is the
+// commitment evaluated at its identifier, which is the index of its share.
+//
+// Signing proceeds in two rounds. In the first round, each participant calls
+// Commit to create nonces and the corresponding SigningCommitment, which it
+// sends to the coordinator. In the second round, the coordinator sends the
+// message and the list of commitments to the participants, who each call Sign
+// to compute a signature share. The coordinator verifies each signature share
+// with VerifySignatureShare, and combines them with Aggregate. The resulting
+// signature can be verified with Verify.
+package frost
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// ElementSize is the number of bytes in the SEC1 compressed encoding of a
+// point, which is the serialization used by the ciphersuite.
+const ElementSize = 33
+
+// A SigningCommitment is the commitment to the pair of nonces of a
+// participant for a single signing operation.
+type SigningCommitment struct {
+	Identifier secp256k1.Fn
+	Hiding     secp256k1.Point
+	Binding    secp256k1.Point
+}
+
+// Nonces is the pair of secret nonces of a participant for a single signing
+// operation. Nonces must never be used for more than one signature.
+type Nonces struct {
+	Hiding, Binding secp256k1.Fn
+}
+
+// Zero sets the nonces to zero. It should be called once the signature share
+// has been computed.
+func (nonces *Nonces) Zero() {
+	nonces.Hiding.Clear()
+	nonces.Binding.Clear()
+}
+
+// A Signature is a Schnorr signature (R, z), which is valid for a message and
+// public key PK when z*G = R + c*PK, where c is the challenge.
+type Signature struct {
+	R secp256k1.Point
+	Z secp256k1.Fn
+}
+
+// ShareKey shares the given signing key among the participants with the given
+// identifiers with threshold k, and stores the shares and the Feldman
+// commitment in the given destinations. This is the trusted dealer key
+// generation of RFC 9591.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.ShareSecret, or if the destination commitment has a capacity less
+// than k.
+func ShareKey(dst *shamir.Shares, c *shamir.Commitment, identifiers []secp256k1.Fn, key secp256k1.Fn, k int) error {
+	coeffs := make([]secp256k1.Fn, k)
+	defer shamir.WipeFns(coeffs)
+	if err := shamir.ShareAndGetCoeffs(dst, coeffs, identifiers, key, k); err != nil {
+		return err
+	}
+	*c = (*c)[:k]
+	for i := range coeffs {
+		(*c)[i].BaseExp(&coeffs[i])
+	}
+	return nil
+}
+
+// GroupPublicKey returns the group public key for a Feldman commitment to the
+// sharing of the signing key, which is the commitment to the constant term.
+//
+// Panics: This function will panic if the commitment is empty.
+func GroupPublicKey(c shamir.Commitment) secp256k1.Point {
+	return c[0]
+}
+
+// PublicKeyShare returns the public key share of the participant with the
+// given identifier, for a Feldman commitment to the sharing of the signing
+// key.
+//
+// Panics: This function will panic if the commitment is empty.
+func PublicKeyShare(c shamir.Commitment, identifier *secp256k1.Fn) secp256k1.Point {
+	return c.Evaluate(identifier)
+}
+
+// Commit creates fresh nonces for the given share of the signing key, and the
+// corresponding commitment, for round one of signing. The nonces are derived
+// from fresh randomness, read from the source set with
+// shamir.SetRandomSource, and the share value, as in RFC 9591, so that a weak
+// source of randomness does not immediately leak the key.
+func Commit(share *shamir.Share) (Nonces, SigningCommitment, error) {
+	var nonces Nonces
+	var err error
+	if nonces.Hiding, err = nonceGenerate(&share.Value); err != nil {
+		return Nonces{}, SigningCommitment{}, err
+	}
+	if nonces.Binding, err = nonceGenerate(&share.Value); err != nil {
+		return Nonces{}, SigningCommitment{}, err
+	}
+	com := SigningCommitment{Identifier: share.Index}
+	com.Hiding.BaseExp(&nonces.Hiding)
+	com.Binding.BaseExp(&nonces.Binding)
+	return nonces, com, nil
+}
+
+func nonceGenerate(secret *secp256k1.Fn) (secp256k1.Fn, error) {
+	var randomBytes, secretBytes [32]byte
+	if _, err := io.ReadFull(shamir.RandomSource(), randomBytes[:]); err != nil {
+		return secp256k1.Fn{}, fmt.Errorf("could not generate random bytes: %w", err)
+	}
+	secret.PutB32(secretBytes[:])
+	nonce := h3(randomBytes[:], secretBytes[:])
+	for i := range secretBytes {
+		secretBytes[i] = 0
+	}
+	return nonce, nil
+}
+
+// BindingFactors computes the binding factor of each participant in the given
+// list of commitments, for signing the message under the group public key. The
+// i-th binding factor corresponds to the i-th commitment. The list of
+// commitments must be sorted in ascending order of identifier, with no
+// duplicates, and every point must not be the point at infinity.
+func BindingFactors(pk *secp256k1.Point, commitments []SigningCommitment, msg []byte) ([]secp256k1.Fn, error) {
+	encodedCommitments, err := encodeCommitmentList(commitments)
+	if err != nil {
+		return nil, err
+	}
+	pkEnc, err := serializeElement(pk)
+	if err != nil {
+		return nil, err
+	}
+	msgHash := h4(msg)
+	commitmentHash := h5(encodedCommitments)
+	prefix := append(append(pkEnc, msgHash[:]...), commitmentHash[:]...)
+
+	factors := make([]secp256k1.Fn, len(commitments))
+	var identifier [32]byte
+	for i := range commitments {
+		commitments[i].Identifier.PutB32(identifier[:])
+		factors[i] = h1(prefix, identifier[:])
+	}
+	return factors, nil
+}
+
+// ComputeGroupCommitment computes the group commitment R, which is the sum of
+// the hiding commitments and the binding commitments multiplied by the
+// corresponding binding factors.
+func ComputeGroupCommitment(commitments []SigningCommitment, bindingFactors []secp256k1.Fn) secp256k1.Point {
+	r := secp256k1.NewPointInfinity()
+	var tmp secp256k1.Point
+	for i := range commitments {
+		tmp.ScaleExt(&commitments[i].Binding, &bindingFactors[i])
+		tmp.Add(&tmp, &commitments[i].Hiding)
+		r.Add(&r, &tmp)
+	}
+	return r
+}
+
+// Challenge computes the Schnorr challenge for the given group commitment,
+// group public key and message. An error is returned if either point is the
+// point at infinity.
+func Challenge(r, pk *secp256k1.Point, msg []byte) (secp256k1.Fn, error) {
+	rEnc, err := serializeElement(r)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	pkEnc, err := serializeElement(pk)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	return h2(rEnc, pkEnc, msg), nil
+}
+
+// The values that are derived from the public inputs of round two.
+type signingPackage struct {
+	bindingFactors []secp256k1.Fn
+	r              secp256k1.Point
+	challenge      secp256k1.Fn
+	identifiers    []secp256k1.Fn
+}
+
+func newSigningPackage(pk *secp256k1.Point, commitments []SigningCommitment, msg []byte) (signingPackage, error) {
+	var pkg signingPackage
+	var err error
+	if pkg.bindingFactors, err = BindingFactors(pk, commitments, msg); err != nil {
+		return signingPackage{}, err
+	}
+	pkg.r = ComputeGroupCommitment(commitments, pkg.bindingFactors)
+	if pkg.challenge, err = Challenge(&pkg.r, pk, msg); err != nil {
+		return signingPackage{}, err
+	}
+	pkg.identifiers = make([]secp256k1.Fn, len(commitments))
+	for i := range commitments {
+		pkg.identifiers[i] = commitments[i].Identifier
+	}
+	return pkg, nil
+}
+
+// Returns the position of the commitment with the given identifier.
+func (pkg *signingPackage) position(identifier *secp256k1.Fn) (int, error) {
+	for i := range pkg.identifiers {
+		if pkg.identifiers[i].Eq(identifier) {
+			return i, nil
+		}
+	}
+	return 0, errors.New("participant is not in the list of commitments")
+}
+
+// Sign computes the signature share of the participant with the given share of
+// the signing key and nonces, for round two of signing. The list of commitments
+// must contain the commitment of the participant for the given nonces.
+func Sign(
+	share *shamir.Share,
+	nonces *Nonces,
+	pk *secp256k1.Point,
+	commitments []SigningCommitment,
+	msg []byte,
+) (secp256k1.Fn, error) {
+	pkg, err := newSigningPackage(pk, commitments, msg)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	i, err := pkg.position(&share.Index)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	lambda, err := shamir.LagrangeCoefficient(&share.Index, pkg.identifiers)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+
+	// z = d + e*rho + lambda*s*c.
+	var z, tmp secp256k1.Fn
+	z.Mul(&lambda, &share.Value)
+	z.Mul(&z, &pkg.challenge)
+	tmp.Mul(&nonces.Binding, &pkg.bindingFactors[i])
+	z.Add(&z, &tmp)
+	z.Add(&z, &nonces.Hiding)
+	tmp.Clear()
+	return z, nil
+}
+
+// VerifySignatureShare returns true if the given signature share of the
+// participant with the given identifier is valid, and false otherwise. The
+// public key share of the participant is the evaluation of the Feldman
+// commitment c at its identifier. An error is returned if the inputs are
+// malformed.
+func VerifySignatureShare(
+	c shamir.Commitment,
+	commitments []SigningCommitment,
+	msg []byte,
+	identifier *secp256k1.Fn,
+	z *secp256k1.Fn,
+) (bool, error) {
+	if len(c) == 0 {
+		return false, errors.New("empty commitment")
+	}
+	pk := GroupPublicKey(c)
+	pkg, err := newSigningPackage(&pk, commitments, msg)
+	if err != nil {
+		return false, err
+	}
+	i, err := pkg.position(identifier)
+	if err != nil {
+		return false, err
+	}
+	lambda, err := shamir.LagrangeCoefficient(identifier, pkg.identifiers)
+	if err != nil {
+		return false, err
+	}
+
+	// z*G = D + rho*E + (c*lambda)*PK_i.
+	var lhs, rhs, tmp secp256k1.Point
+	lhs.BaseExp(z)
+	rhs.ScaleExt(&commitments[i].Binding, &pkg.bindingFactors[i])
+	rhs.Add(&rhs, &commitments[i].Hiding)
+	pkShare := PublicKeyShare(c, identifier)
+	var e secp256k1.Fn
+	e.Mul(&pkg.challenge, &lambda)
+	tmp.ScaleExt(&pkShare, &e)
+	rhs.Add(&rhs, &tmp)
+	return lhs.Eq(&rhs), nil
+}
+
+// Aggregate combines the signature shares of all participants in the list of
+// commitments into a signature. The i-th signature share corresponds to the
+// i-th commitment. The signature shares should be verified with
+// VerifySignatureShare first, since a single invalid share gives an invalid
+// signature.
+func Aggregate(
+	pk *secp256k1.Point,
+	commitments []SigningCommitment,
+	msg []byte,
+	sigShares []secp256k1.Fn,
+) (Signature, error) {
+	if len(sigShares) != len(commitments) {
+		return Signature{}, fmt.Errorf("expected %v signature shares, got %v", len(commitments), len(sigShares))
+	}
+	pkg, err := newSigningPackage(pk, commitments, msg)
+	if err != nil {
+		return Signature{}, err
+	}
+	sig := Signature{R: pkg.r}
+	for i := range sigShares {
+		sig.Z.Add(&sig.Z, &sigShares[i])
+	}
+	return sig, nil
+}
+
+// Verify returns true if the signature is valid for the message under the
+// given public key, and false otherwise.
+func Verify(pk *secp256k1.Point, msg []byte, sig *Signature) bool {
+	c, err := Challenge(&sig.R, pk, msg)
+	if err != nil {
+		return false
+	}
+	var lhs, rhs secp256k1.Point
+	lhs.BaseExp(&sig.Z)
+	rhs.ScaleExt(pk, &c)
+	rhs.Add(&rhs, &sig.R)
+	return lhs.Eq(&rhs)
+}
+
+// Returns the SEC1 compressed encoding of the point. The point at infinity
+// can not be serialized.
+func serializeElement(p *secp256k1.Point) ([]byte, error) {
+	if p.IsInfinity() {
+		return nil, errors.New("cannot serialize the point at infinity")
+	}
+	bs := make([]byte, ElementSize)
+	p.PutBytes(bs)
+	// The secp256k1 package uses 0 and 1 for the parity of y instead of the
+	// SEC1 prefixes 2 and 3.
+	bs[0] += 2
+	return bs, nil
+}
+
+func encodeCommitmentList(commitments []SigningCommitment) ([]byte, error) {
+	if len(commitments) == 0 {
+		return nil, errors.New("empty list of commitments")
+	}
+	enc := make([]byte, 0, len(commitments)*(32+2*ElementSize))
+	var prev, identifier [32]byte
+	for i := range commitments {
+		if commitments[i].Identifier.IsZero() {
+			return nil, fmt.Errorf("commitment %v has identifier zero", i)
+		}
+		commitments[i].Identifier.PutB32(identifier[:])
+		if i > 0 && !lessBytes(prev[:], identifier[:]) {
+			return nil, errors.New("commitments are not sorted by identifier")
+		}
+		prev = identifier
+
+		hiding, err := serializeElement(&commitments[i].Hiding)
+		if err != nil {
+			return nil, fmt.Errorf("commitment %v: %v", i, err)
+		}
+		binding, err := serializeElement(&commitments[i].Binding)
+		if err != nil {
+			return nil, fmt.Errorf("commitment %v: %v", i, err)
+		}
+		enc = append(enc, identifier[:]...)
+		enc = append(enc, hiding...)
+		enc = append(enc, binding...)
+	}
+	return enc, nil
+}
+
+// Returns true if a is less than b when interpreted as big endian integers of
+// the same length.
+func lessBytes(a, b []byte) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// SortCommitments sorts the list of commitments in ascending order of
+// identifier, as required by BindingFactors.
+func SortCommitments(commitments []SigningCommitment) {
+	keys := make([][32]byte, len(commitments))
+	for i := range commitments {
+		commitments[i].Identifier.PutB32(keys[i][:])
+	}
+	// Insertion sort, since the number of signers is small.
+	for i := 1; i < len(commitments); i++ {
+		for j := i; j > 0 && lessBytes(keys[j][:], keys[j-1][:]); j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+			commitments[j], commitments[j-1] = commitments[j-1], commitments[j]
+		}
+	}
+}
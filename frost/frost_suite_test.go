@@ -0,0 +1,13 @@
+package frost_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFROST(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FROST Suite")
+}
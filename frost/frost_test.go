@@ -0,0 +1,173 @@
+package frost_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/frost"
+)
+
+var _ = Describe("FROST", func() {
+	trials := 10
+	n := 10
+	msg := []byte("message")
+
+	// Shares a random signing key with a Feldman commitment.
+	dealKey := func(k int) (shamir.Shares, shamir.Commitment, secp256k1.Fn) {
+		shares := make(shamir.Shares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		key := secp256k1.RandomFn()
+		Expect(ShareKey(&shares, &c, shamirutil.SequentialIndices(n), key, k)).To(Succeed())
+		return shares, c, key
+	}
+
+	// Runs round one for a random subset of t signers.
+	roundOne := func(shares shamir.Shares, t int) (shamir.Shares, []Nonces, []SigningCommitment) {
+		signers := make(shamir.Shares, t)
+		for i, j := range rand.Perm(len(shares))[:t] {
+			signers[i] = shares[j]
+		}
+		// Signers are ordered by identifier, to match the sorted list of
+		// commitments.
+		commitments := make([]SigningCommitment, t)
+		nonces := make([]Nonces, t)
+		for i := range signers {
+			var err error
+			nonces[i], commitments[i], err = Commit(&signers[i])
+			Expect(err).ToNot(HaveOccurred())
+		}
+		sorted := append([]SigningCommitment{}, commitments...)
+		SortCommitments(sorted)
+		sortedSigners := make(shamir.Shares, t)
+		sortedNonces := make([]Nonces, t)
+		for i := range sorted {
+			for j := range commitments {
+				if commitments[j].Identifier.Eq(&sorted[i].Identifier) {
+					sortedSigners[i], sortedNonces[i] = signers[j], nonces[j]
+				}
+			}
+		}
+		return sortedSigners, sortedNonces, sorted
+	}
+
+	It("should derive the group public key from the commitment", func() {
+		_, c, key := dealKey(shamirutil.RandRange(1, n))
+		var expected secp256k1.Point
+		expected.BaseExp(&key)
+		pk := GroupPublicKey(c)
+		Expect(pk.Eq(&expected)).To(BeTrue())
+	})
+
+	It("should produce valid signatures for any set of at least k signers", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(1, n)
+			shares, c, _ := dealKey(k)
+			pk := GroupPublicKey(c)
+			signers, nonces, commitments := roundOne(shares, shamirutil.RandRange(k, n))
+
+			sigShares := make([]secp256k1.Fn, len(signers))
+			for j := range signers {
+				var err error
+				sigShares[j], err = Sign(&signers[j], &nonces[j], &pk, commitments, msg)
+				Expect(err).ToNot(HaveOccurred())
+				nonces[j].Zero()
+
+				ok, err := VerifySignatureShare(c, commitments, msg, &signers[j].Index, &sigShares[j])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ok).To(BeTrue())
+			}
+
+			sig, err := Aggregate(&pk, commitments, msg, sigShares)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(Verify(&pk, msg, &sig)).To(BeTrue())
+			Expect(Verify(&pk, []byte("other message"), &sig)).To(BeFalse())
+		}
+	})
+
+	It("should detect invalid signature shares", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(1, n)
+			shares, c, _ := dealKey(k)
+			pk := GroupPublicKey(c)
+			signers, nonces, commitments := roundOne(shares, k)
+
+			j := rand.Intn(k)
+			z, err := Sign(&signers[j], &nonces[j], &pk, commitments, msg)
+			Expect(err).ToNot(HaveOccurred())
+			z = secp256k1.RandomFn()
+			ok, err := VerifySignatureShare(c, commitments, msg, &signers[j].Index, &z)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		}
+	})
+
+	It("should not produce valid signatures with fewer than k signers", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(2, n)
+			shares, c, _ := dealKey(k)
+			pk := GroupPublicKey(c)
+			signers, nonces, commitments := roundOne(shares, k-1)
+
+			sigShares := make([]secp256k1.Fn, len(signers))
+			for j := range signers {
+				var err error
+				sigShares[j], err = Sign(&signers[j], &nonces[j], &pk, commitments, msg)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			sig, err := Aggregate(&pk, commitments, msg, sigShares)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(Verify(&pk, msg, &sig)).To(BeFalse())
+		}
+	})
+
+	It("should compute binding factors that depend on the message and commitments", func() {
+		shares, c, _ := dealKey(3)
+		pk := GroupPublicKey(c)
+		_, _, commitments := roundOne(shares, 3)
+
+		factors, err := BindingFactors(&pk, commitments, msg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(factors).To(HaveLen(3))
+		Expect(factors[0].Eq(&factors[1])).To(BeFalse())
+
+		other, err := BindingFactors(&pk, commitments, []byte("other message"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(other[0].Eq(&factors[0])).To(BeFalse())
+
+		commitments[1].Binding = secp256k1.RandomPoint()
+		other, err = BindingFactors(&pk, commitments, msg)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(other[0].Eq(&factors[0])).To(BeFalse())
+	})
+
+	It("should reject malformed lists of commitments", func() {
+		shares, c, _ := dealKey(3)
+		pk := GroupPublicKey(c)
+		_, _, commitments := roundOne(shares, 3)
+
+		_, err := BindingFactors(&pk, nil, msg)
+		Expect(err).To(HaveOccurred())
+
+		unsorted := []SigningCommitment{commitments[1], commitments[0], commitments[2]}
+		_, err = BindingFactors(&pk, unsorted, msg)
+		Expect(err).To(HaveOccurred())
+
+		duplicated := []SigningCommitment{commitments[0], commitments[0]}
+		_, err = BindingFactors(&pk, duplicated, msg)
+		Expect(err).To(HaveOccurred())
+
+		infinity := append([]SigningCommitment{}, commitments...)
+		infinity[0].Hiding = secp256k1.NewPointInfinity()
+		_, err = BindingFactors(&pk, infinity, msg)
+		Expect(err).To(HaveOccurred())
+
+		var nonces Nonces
+		_, err = Sign(&shares[0], &nonces, &pk, commitments[:0], msg)
+		Expect(err).To(HaveOccurred())
+	})
+})
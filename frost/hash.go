@@ -0,0 +1,73 @@
+package frost
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/renproject/secp256k1"
+)
+
+// ContextString is the context string of the FROST(secp256k1, SHA-256)
+// ciphersuite, which prefixes the domain separation tags of all of its hash
+// functions.
+const ContextString = "FROST-secp256k1-SHA256-v1"
+
+var order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// The hash functions H1, H2 and H3 of the ciphersuite, which hash to a scalar
+// using hash_to_field from RFC 9380 with expand_message_xmd and SHA-256.
+func h1(msg ...[]byte) secp256k1.Fn { return hashToScalar(ContextString+"rho", msg...) }
+func h2(msg ...[]byte) secp256k1.Fn { return hashToScalar(ContextString+"chal", msg...) }
+func h3(msg ...[]byte) secp256k1.Fn { return hashToScalar(ContextString+"nonce", msg...) }
+
+// The hash functions H4 and H5 of the ciphersuite, which are SHA-256 with a
+// domain separation prefix.
+func h4(msg []byte) [32]byte { return sha256.Sum256(append([]byte(ContextString+"msg"), msg...)) }
+func h5(msg []byte) [32]byte { return sha256.Sum256(append([]byte(ContextString+"com"), msg...)) }
+
+// Hashes the concatenation of the given messages to a scalar, by expanding to
+// 48 bytes and reducing modulo the order.
+func hashToScalar(dst string, msg ...[]byte) secp256k1.Fn {
+	uniform := expandMessageXMD(dst, msg, 48)
+	x := new(big.Int).SetBytes(uniform)
+	x.Mod(x, order)
+
+	var bs [32]byte
+	xBytes := x.Bytes()
+	copy(bs[32-len(xBytes):], xBytes)
+	var s secp256k1.Fn
+	s.SetB32(bs[:])
+	return s
+}
+
+// Implements expand_message_xmd from RFC 9380 with SHA-256, for the
+// concatenation of the given messages. The output length must be at most 255
+// times the hash output size, which is always the case in this package.
+func expandMessageXMD(dst string, msg [][]byte, l int) []byte {
+	dstPrime := append([]byte(dst), byte(len(dst)))
+	ell := (l + sha256.Size - 1) / sha256.Size
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	for _, m := range msg {
+		h.Write(m)
+	}
+	h.Write([]byte{byte(l >> 8), byte(l), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	bi := make([]byte, sha256.Size)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:l]
+}
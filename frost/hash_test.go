@@ -0,0 +1,28 @@
+package frost
+
+import (
+	"encoding/hex"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hashing", func() {
+	// Test vectors from RFC 9380, appendix K.1.
+	It("should match the expand_message_xmd test vectors", func() {
+		dst := "QUUX-V01-CS02-with-expander-SHA256-128"
+		Expect(hex.EncodeToString(expandMessageXMD(dst, [][]byte{}, 0x20))).To(Equal(
+			"68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		))
+		Expect(hex.EncodeToString(expandMessageXMD(dst, [][]byte{[]byte("abc")}, 0x20))).To(Equal(
+			"d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+		))
+	})
+
+	It("should hash the concatenation of the messages", func() {
+		dst := "test"
+		whole := expandMessageXMD(dst, [][]byte{[]byte("abcdef")}, 48)
+		parts := expandMessageXMD(dst, [][]byte{[]byte("ab"), []byte("cd"), []byte("ef")}, 48)
+		Expect(parts).To(Equal(whole))
+	})
+})
@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package shamir_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge"
+)
+
+func FuzzOpen(f *testing.F) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 8; i++ {
+		seed := make([]byte, 2+64*32)
+		r.Read(seed)
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := shamirutil.CheckOpen(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzUnmarshalVerifiableShares(f *testing.F) {
+	for _, n := range []int{0, 1, 5} {
+		vshares := make(shamir.VerifiableShares, n)
+		for i := range vshares {
+			vshares[i] = shamir.NewVerifiableShare(
+				shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+				secp256k1.RandomFn(),
+			)
+		}
+		seed, err := surge.ToBinary(vshares)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(seed)
+	}
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := shamirutil.CheckUnmarshalVerifiableShares(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,607 @@
+//go:build ignore
+// +build ignore
+
+// This program generates shamir.go and vss.go in each of the packages for the
+// groups other than secp256k1. The sharing logic is the same for every group,
+// and only relies on the Scalar and Point types and the helpers defined in the
+// group.go file of each package. It is run by go generate.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"text/template"
+)
+
+// The packages for which the sharing logic is generated.
+var packages = []string{"bn254", "p256", "ristretto255"}
+
+var shamirTmpl = template.Must(template.New("shamir").Parse(`// Code generated by gen_backends.go; DO NOT EDIT.
+
+package {{.}}
+
+import (
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// ShareSize is the number of bytes in a share.
+const ShareSize = 2 * ScalarSize
+
+// Shares represents a slice of Shamir shares.
+type Shares []Share
+
+// Share represents a single share in a Shamir secret sharing scheme.
+type Share struct {
+	Index, Value Scalar
+}
+
+// NewShare constructs a new Shamir share from an index and a value.
+func NewShare(index, value Scalar) Share {
+	return Share{Index: index, Value: value}
+}
+
+// Eq returns true if the two shares are equal, and false otherwise.
+func (s *Share) Eq(other *Share) bool {
+	return s.Index.Eq(&other.Index) && s.Value.Eq(&other.Value)
+}
+
+// IndexEq returns true if the index of the share is equal to the given index,
+// and false otherwise.
+func (s *Share) IndexEq(other *Scalar) bool {
+	return s.Index.Eq(other)
+}
+
+// Add computes the addition of the two input shares and stores the result in
+// the caller. Addition is defined by adding the values but leaving the index
+// unchanged.
+//
+// Panics: Addition only makes sense when the two input shares have the same
+// index. If they do not, this function will panic.
+func (s *Share) Add(a, b *Share) {
+	if !a.Index.Eq(&b.Index) {
+		panic("cannot add shares with different indices")
+	}
+	s.Index = a.Index
+	s.Value.Add(&a.Value, &b.Value)
+}
+
+// AddConstant computes the addition of the input share and the given constant
+// and stores the result in the caller.
+func (s *Share) AddConstant(other *Share, c *Scalar) {
+	s.Index = other.Index
+	s.Value.Add(&other.Value, c)
+}
+
+// Scale multiplies the input share by a constant and then stores it in the
+// caller.
+func (s *Share) Scale(other *Share, scale *Scalar) {
+	s.Index = other.Index
+	s.Value.Mul(&other.Value, scale)
+}
+
+// Generate implements the quick.Generator interface.
+func (s Share) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(NewShare(RandomScalar(), RandomScalar()))
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (s Share) SizeHint() int { return ShareSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (s Share) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := s.Index.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return s.Value.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (s *Share) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := s.Index.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return s.Value.Unmarshal(buf, rem)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (shares Shares) SizeHint() int { return surge.SizeHintU32 + ShareSize*len(shares) }
+
+// Marshal implements the surge.Marshaler interface.
+func (shares Shares) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(shares)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range shares {
+		buf, rem, err = shares[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (shares *Shares) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, ShareSize, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if *shares == nil {
+		*shares = make(Shares, 0, l)
+	}
+	*shares = (*shares)[:0]
+	for i := uint32(0); i < l; i++ {
+		*shares = append(*shares, Share{})
+		buf, rem, err = (*shares)[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// ShareSecret creates Shamir shares for the given secret at the given
+// threshold, and stores them in the given destination slice. There will be
+// one share for each of the given indices. If k is larger than the number of
+// indices, or is less than one, an error wrapping shamir.ErrInvalidThreshold
+// is returned. The coefficients of the sharing polynomial are read from the
+// source set with shamir.SetRandomSource, and an error is returned if reading
+// from it fails.
+//
+// Panics: This function will panic if the destination shares slice has a
+// capacity less than n (the number of indices), or any of the given indices is
+// zero.
+func ShareSecret(dst *Shares, indices []Scalar, secret Scalar, k int) error {
+	if err := sharing.CheckThreshold(k, len(indices)); err != nil {
+		return err
+	}
+	coeffs := make([]Scalar, k)
+	defer wipe(coeffs)
+	return ShareAndGetCoeffs(dst, coeffs, indices, secret, k)
+}
+
+// ShareAndGetCoeffs is the same as ShareSecret, but uses the provided slice to
+// store the generated coefficients of the sharing polynomial, where index 0
+// is the constant term.
+//
+// Panics: This function will panic under the same conditions as ShareSecret,
+// or if the coefficients slice has length less than k.
+func ShareAndGetCoeffs(dst *Shares, coeffs, indices []Scalar, secret Scalar, k int) error {
+	for i := range indices {
+		if indices[i].IsZero() {
+			panic("cannot create share for index zero")
+		}
+	}
+	if err := sharing.CheckThreshold(k, len(indices)); err != nil {
+		return err
+	}
+	if err := setRandomCoeffs(coeffs, secret, k); err != nil {
+		return err
+	}
+
+	*dst = (*dst)[:len(indices)]
+	for i := range indices {
+		(*dst)[i].Index = indices[i]
+		polyEval(&(*dst)[i].Value, &indices[i], coeffs[:k])
+	}
+	return nil
+}
+
+// Sets the first k coefficients to the secret followed by random scalars read
+// from the source set with shamir.SetRandomSource.
+func setRandomCoeffs(coeffs []Scalar, secret Scalar, k int) error {
+	r := sharing.RandomSource()
+	coeffs = coeffs[:k]
+	coeffs[0] = secret
+	for i := 1; i < k; i++ {
+		var err error
+		if coeffs[i], err = randomScalar(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func polyEval(y, x *Scalar, coeffs []Scalar) {
+	*y = coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, &coeffs[i])
+	}
+}
+
+// Open computes the secret corresponding to the given shares by Lagrange
+// interpolation at zero. It is assumed that all shares have different indices,
+// that there are at least k of them, and that they have not been maliciously
+// modified.
+func Open(shares Shares) Scalar {
+	nums := make([]Scalar, len(shares))
+	denoms := make([]Scalar, len(shares))
+	var res, tmp Scalar
+	for i := range shares {
+		nums[i].SetU16(1)
+		denoms[i].SetU16(1)
+		for j := range shares {
+			if shares[i].Index.Eq(&shares[j].Index) {
+				continue
+			}
+			tmp.Sub(&shares[j].Index, &shares[i].Index)
+			denoms[i].Mul(&denoms[i], &tmp)
+			nums[i].Mul(&nums[i], &shares[j].Index)
+		}
+	}
+	BatchInvert(denoms)
+	for i := range shares {
+		tmp.Mul(&nums[i], &denoms[i])
+		tmp.Mul(&tmp, &shares[i].Value)
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+func wipe(xs []Scalar) {
+	for i := range xs {
+		xs[i].Clear()
+	}
+}
+`))
+
+var vssTmpl = template.Must(template.New("vss").Parse(`// Code generated by gen_backends.go; DO NOT EDIT.
+
+package {{.}}
+
+import (
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// VShareSize is the size of a verifiable share in bytes.
+const VShareSize = ShareSize + ScalarSize
+
+// VerifiableShares is a alias for a slice of VerifiableShare(s).
+type VerifiableShares []VerifiableShare
+
+// A VerifiableShare is a Share but with additional information that allows it
+// to be verified as correct for a given commitment to a sharing.
+type VerifiableShare struct {
+	Share        Share
+	Decommitment Scalar
+}
+
+// NewVerifiableShare constructs a new VerifiableShare from the given Share and
+// decommitment value.
+func NewVerifiableShare(share Share, r Scalar) VerifiableShare {
+	return VerifiableShare{share, r}
+}
+
+// Eq returns true if the two verifiable shares are equal, and false otherwise.
+func (vs *VerifiableShare) Eq(other *VerifiableShare) bool {
+	return vs.Share.Eq(&other.Share) && vs.Decommitment.Eq(&other.Decommitment)
+}
+
+// Add computes the addition of the two input shares and stores the result in
+// the caller.
+func (vs *VerifiableShare) Add(a, b *VerifiableShare) {
+	vs.Share.Add(&a.Share, &b.Share)
+	vs.Decommitment.Add(&a.Decommitment, &b.Decommitment)
+}
+
+// AddConstant computes the addition of the input share and the constant and
+// stores the result in the caller. The decommitment is unchanged.
+func (vs *VerifiableShare) AddConstant(other *VerifiableShare, c *Scalar) {
+	vs.Decommitment = other.Decommitment
+	vs.Share.AddConstant(&other.Share, c)
+}
+
+// Scale computes the scaling of the input share by given scale factor and
+// stores the result in the caller.
+func (vs *VerifiableShare) Scale(other *VerifiableShare, scale *Scalar) {
+	vs.Share.Scale(&other.Share, scale)
+	vs.Decommitment.Mul(&other.Decommitment, scale)
+}
+
+// Shares returns the underlying (unverified) shares.
+func (vshares VerifiableShares) Shares() Shares {
+	shares := make(Shares, len(vshares))
+	for i := range vshares {
+		shares[i] = vshares[i].Share
+	}
+	return shares
+}
+
+// Generate implements the quick.Generator interface.
+func (vs VerifiableShare) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(NewVerifiableShare(NewShare(RandomScalar(), RandomScalar()), RandomScalar()))
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (vs VerifiableShare) SizeHint() int { return VShareSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (vs VerifiableShare) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := vs.Share.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return vs.Decommitment.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (vs *VerifiableShare) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := vs.Share.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return vs.Decommitment.Unmarshal(buf, rem)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (vshares VerifiableShares) SizeHint() int {
+	return surge.SizeHintU32 + VShareSize*len(vshares)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (vshares VerifiableShares) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(vshares)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range vshares {
+		buf, rem, err = vshares[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (vshares *VerifiableShares) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, VShareSize, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if *vshares == nil {
+		*vshares = make(VerifiableShares, 0, l)
+	}
+	*vshares = (*vshares)[:0]
+	for i := uint32(0); i < l; i++ {
+		*vshares = append(*vshares, VerifiableShare{})
+		buf, rem, err = (*vshares)[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// A Commitment is used to verify that a sharing has been performed correctly.
+type Commitment []Point
+
+// NewCommitmentWithCapacity creates a new Commitment with the given capacity.
+func NewCommitmentWithCapacity(k int) Commitment {
+	return make(Commitment, 0, k)
+}
+
+// Generate implements the quick.Generator interface.
+func (c Commitment) Generate(rand *rand.Rand, size int) reflect.Value {
+	com := make(Commitment, rand.Intn(size))
+	for i := range com {
+		com[i] = RandomPoint()
+	}
+	return reflect.ValueOf(com)
+}
+
+// Eq returns true if the two commitments are equal, and false otherwise.
+func (c Commitment) Eq(other Commitment) bool {
+	if len(c) != len(other) {
+		return false
+	}
+	for i := range c {
+		if !c[i].Eq(&other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of points in the commitment. This is equal to the
+// reconstruction threshold of the associated verifiable sharing.
+func (c Commitment) Len() int {
+	return len(c)
+}
+
+// Add stores in the caller the commitment that represents the addition of the
+// two given commitments.
+//
+// Panics: If the destination commitment does not have capacity at least as big
+// as the greater of the lengths of the two inputs, then this function will
+// panic.
+func (c *Commitment) Add(a, b Commitment) {
+	var smaller, larger Commitment
+	if len(a) > len(b) {
+		smaller, larger = b, a
+	} else {
+		smaller, larger = a, b
+	}
+	*c = (*c)[:len(larger)]
+	for i := range smaller {
+		(*c)[i].Add(&smaller[i], &larger[i])
+	}
+	copy((*c)[len(smaller):], larger[len(smaller):])
+}
+
+// Scale stores in the caller the commitment that represents the scaling of
+// the given commitment by the given scalar.
+//
+// Panics: If the destination commitment does not have capacity at least as big
+// as the input commitment, then this function will panic.
+func (c *Commitment) Scale(other Commitment, scale *Scalar) {
+	*c = (*c)[:len(other)]
+	for i := range *c {
+		(*c)[i].Scale(&other[i], scale)
+	}
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (c Commitment) SizeHint() int { return surge.SizeHintU32 + PointSize*len(c) }
+
+// Marshal implements the surge.Marshaler interface.
+func (c Commitment) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(c)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range c {
+		buf, rem, err = c[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (c *Commitment) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, PointSize, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if *c == nil {
+		*c = make(Commitment, 0, l)
+	}
+	*c = (*c)[:0]
+	for i := uint32(0); i < l; i++ {
+		*c = append(*c, Point{})
+		buf, rem, err = (*c)[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Evaluates the sharing polynomial at the given index "in the exponent".
+func (c Commitment) evaluate(eval *Point, index *Scalar) {
+	*eval = c[len(c)-1]
+	for i := len(c) - 2; i >= 0; i-- {
+		eval.Scale(eval, index)
+		eval.Add(eval, &c[i])
+	}
+}
+
+// PedersenDomain is the domain separation tag from which PedersenH derives
+// the second Pedersen generator.
+const PedersenDomain = "renproject/shamir/{{.}} pedersen h"
+
+// PedersenH returns the second Pedersen generator for this backend. It is
+// equal to PedersenHFromSeed applied to PedersenDomain.
+func PedersenH() Point {
+	return PedersenHFromSeed([]byte(PedersenDomain))
+}
+
+
+// IsValid returns true when the given verifiable share is valid with regard to
+// the given commitment, and false otherwise.
+func IsValid(h Point, c Commitment, vshare *VerifiableShare) bool {
+	if len(c) == 0 {
+		return false
+	}
+	var gPow, hPow, eval Point
+	gPow.BaseExp(&vshare.Share.Value)
+	hPow.Scale(&h, &vshare.Decommitment)
+	gPow.Add(&gPow, &hPow)
+
+	c.evaluate(&eval, &vshare.Share.Index)
+	return gPow.Eq(&eval)
+}
+
+// VShareSecret creates verifiable Shamir shares for the given secret at the
+// given threshold, and stores the shares and the commitment in the given
+// destinations. The same errors as for ShareSecret are returned.
+//
+// Panics: This function will panic if the destination shares slice has a
+// capacity less than n (the number of indices), or if the destination
+// commitment has a capacity less than k.
+func VShareSecret(
+	vshares *VerifiableShares,
+	c *Commitment,
+	indices []Scalar,
+	h Point,
+	secret Scalar,
+	k int,
+) error {
+	n := len(indices)
+	if err := sharing.CheckThreshold(k, n); err != nil {
+		return err
+	}
+	shares := make(Shares, n)
+	coeffs := make([]Scalar, k)
+	defer wipe(coeffs)
+	if err := ShareAndGetCoeffs(&shares, coeffs, indices, secret, k); err != nil {
+		return err
+	}
+
+	*c = (*c)[:k]
+	for i := range coeffs {
+		(*c)[i].BaseExp(&coeffs[i])
+	}
+
+	blind, err := randomScalar(sharing.RandomSource())
+	if err != nil {
+		return err
+	}
+	if err := setRandomCoeffs(coeffs, blind, k); err != nil {
+		return err
+	}
+	*vshares = (*vshares)[:n]
+	for i := range indices {
+		(*vshares)[i].Share = shares[i]
+		polyEval(&(*vshares)[i].Decommitment, &indices[i], coeffs)
+	}
+
+	var hPow Point
+	for i := range coeffs {
+		hPow.Scale(&h, &coeffs[i])
+		(*c)[i].Add(&(*c)[i], &hPow)
+	}
+	return nil
+}
+`))
+
+func main() {
+	for _, pkg := range packages {
+		generate(shamirTmpl, pkg, "shamir.go")
+		generate(vssTmpl, pkg, "vss.go")
+	}
+}
+
+func generate(tmpl *template.Template, pkg, name string) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pkg); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(pkg, name), src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build ignore
+// +build ignore
+
+// This program generates commitk.go, which defines the fixed size commitment
+// types. It is run by go generate.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+// The thresholds for which a fixed size commitment type is generated.
+var thresholds = []int{2, 3, 4}
+
+var tmpl = template.Must(template.New("commitk").Parse(`// Code generated by gen_commitk.go; DO NOT EDIT.
+
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+{{range .}}
+// Commitment{{.}} is a commitment for a sharing with reconstruction threshold
+// {{.}}, held in an array rather than a slice. It does not need to be allocated
+// separately from the value that contains it, and its encoding has a fixed
+// size of {{.}} points with no length prefix.
+type Commitment{{.}} [{{.}}]secp256k1.Point
+
+// Commitment returns the commitment as a Commitment. The returned slice
+// refers to the same points, so changes to either are seen by the other.
+func (c *Commitment{{.}}) Commitment() Commitment {
+	return c[:]
+}
+
+// SetCommitment sets the commitment to the given one, which must have {{.}}
+// points.
+func (c *Commitment{{.}}) SetCommitment(other Commitment) error {
+	if len(other) != len(c) {
+		return fmt.Errorf("invalid commitment length: expected %v, got %v", len(c), len(other))
+	}
+	copy(c[:], other)
+	return nil
+}
+
+// Eq returns true if the two commitments are equal, and false otherwise.
+func (c *Commitment{{.}}) Eq(other *Commitment{{.}}) bool {
+	for i := range c {
+		if !c[i].Eq(&other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add stores in the caller the commitment for the sum of the sharings of the
+// two given commitments, as for Commitment.Add.
+func (c *Commitment{{.}}) Add(a, b *Commitment{{.}}) {
+	for i := range c {
+		c[i].Add(&a[i], &b[i])
+	}
+}
+
+// Evaluate returns the commitment evaluated at the given index "in the
+// exponent", as for Commitment.Evaluate.
+func (c *Commitment{{.}}) Evaluate(index *secp256k1.Fn) secp256k1.Point {
+	var eval secp256k1.Point
+	com := c.Commitment()
+	com.evaluate(&eval, index)
+	return eval
+}
+
+// IsValid returns true when the given verifiable share is valid with regard to
+// the commitment, as for the function IsValid.
+func (c *Commitment{{.}}) IsValid(h secp256k1.Point, vshare *VerifiableShare) bool {
+	com := c.Commitment()
+	return IsValid(h, &com, vshare)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (c Commitment{{.}}) SizeHint() int {
+	return {{.}} * secp256k1.PointSizeMarshalled
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (c Commitment{{.}}) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	for i := range c {
+		buf, rem, err = c[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (c *Commitment{{.}}) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < c.SizeHint() {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	var err error
+	for i := range c {
+		buf, rem, err = c[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+{{end}}`))
+
+func main() {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, thresholds); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("commitk.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -3,8 +3,13 @@ module github.com/renproject/shamir
 go 1.14
 
 require (
+	filippo.io/nistec v0.0.3
+	github.com/consensys/gnark-crypto v0.5.3
+	github.com/gtank/ristretto255 v0.1.2
 	github.com/onsi/ginkgo v1.16.5
 	github.com/onsi/gomega v1.19.0
 	github.com/renproject/secp256k1 v0.0.0-20220707021023-f849b5f8a3c6
 	github.com/renproject/surge v1.2.7
+	golang.org/x/crypto v0.0.0-20220622213112-05595931fe9d
+	google.golang.org/protobuf v1.26.0
 )
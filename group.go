@@ -0,0 +1,301 @@
+package shamir
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// A GroupElement is an element of a CommitmentGroup. Like secp256k1.Point,
+// operations store their result in the receiver and must be safe for aliasing.
+// Implementations may panic if an argument is not an element of the same
+// group.
+type GroupElement interface {
+	surge.MarshalUnmarshaler
+
+	// BaseExp sets the receiver to the generator of the group raised to the
+	// given scalar.
+	BaseExp(s *secp256k1.Fn)
+
+	// Scale sets the receiver to a raised to the given scalar.
+	Scale(a GroupElement, s *secp256k1.Fn)
+
+	// Add sets the receiver to the group operation applied to a and b.
+	Add(a, b GroupElement)
+
+	// Set sets the receiver to be equal to a.
+	Set(a GroupElement)
+
+	// Eq returns true if the two elements are equal.
+	Eq(other GroupElement) bool
+}
+
+// A CommitmentGroup is a group in which Pedersen commitments to a sharing can
+// be computed. Since the exponents are elements of the secp256k1 scalar field,
+// the group must have prime order equal to the order of that field. This
+// allows the commitments to live in a different group to the one implied by
+// the share field, for example in a pairing group of the right order, or in a
+// different representation of the secp256k1 curve, while the shares
+// themselves are unchanged.
+type CommitmentGroup interface {
+	// NewElement returns a new element of the group, which is the identity.
+	NewElement() GroupElement
+}
+
+// Secp256k1Group is the CommitmentGroup of points on the secp256k1 curve,
+// which is the group used by Commitment.
+var Secp256k1Group CommitmentGroup = secp256k1Group{}
+
+type secp256k1Group struct{}
+
+func (secp256k1Group) NewElement() GroupElement {
+	return &Secp256k1Element{Point: secp256k1.NewPointInfinity()}
+}
+
+// A Secp256k1Element is an element of Secp256k1Group.
+type Secp256k1Element struct {
+	Point secp256k1.Point
+}
+
+// BaseExp implements the GroupElement interface.
+func (e *Secp256k1Element) BaseExp(s *secp256k1.Fn) {
+	e.Point.BaseExp(s)
+}
+
+// Scale implements the GroupElement interface.
+func (e *Secp256k1Element) Scale(a GroupElement, s *secp256k1.Fn) {
+	e.Point.ScaleExt(&a.(*Secp256k1Element).Point, s)
+}
+
+// Add implements the GroupElement interface.
+func (e *Secp256k1Element) Add(a, b GroupElement) {
+	e.Point.Add(&a.(*Secp256k1Element).Point, &b.(*Secp256k1Element).Point)
+}
+
+// Set implements the GroupElement interface.
+func (e *Secp256k1Element) Set(a GroupElement) {
+	e.Point = a.(*Secp256k1Element).Point
+}
+
+// Eq implements the GroupElement interface.
+func (e *Secp256k1Element) Eq(other GroupElement) bool {
+	o, ok := other.(*Secp256k1Element)
+	return ok && e.Point.Eq(&o.Point)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (e *Secp256k1Element) SizeHint() int { return e.Point.SizeHint() }
+
+// Marshal implements the surge.Marshaler interface.
+func (e *Secp256k1Element) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	return e.Point.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (e *Secp256k1Element) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	return e.Point.Unmarshal(buf, rem)
+}
+
+// A GroupCommitment is a commitment to a sharing in an arbitrary
+// CommitmentGroup. It plays the same role as Commitment, which is the special
+// case of a GroupCommitment in Secp256k1Group.
+type GroupCommitment struct {
+	group CommitmentGroup
+	elems []GroupElement
+}
+
+// NewGroupCommitment creates a new empty commitment in the given group.
+func NewGroupCommitment(group CommitmentGroup) GroupCommitment {
+	return GroupCommitment{group: group}
+}
+
+// Group returns the group that the commitment lives in.
+func (c *GroupCommitment) Group() CommitmentGroup {
+	return c.group
+}
+
+// Len returns the number of group elements in the commitment. This is equal to
+// the reconstruction threshold of the associated verifiable sharing.
+func (c *GroupCommitment) Len() int {
+	return len(c.elems)
+}
+
+// At returns the group element at the given position in the commitment, which
+// is the commitment to the coefficient of the corresponding degree.
+func (c *GroupCommitment) At(i int) GroupElement {
+	return c.elems[i]
+}
+
+// Eq returns true if the two commitments are equal, and false otherwise.
+func (c *GroupCommitment) Eq(other *GroupCommitment) bool {
+	if len(c.elems) != len(other.elems) {
+		return false
+	}
+	for i := range c.elems {
+		if !c.elems[i].Eq(other.elems[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sets the length of the commitment, allocating new elements as necessary.
+func (c *GroupCommitment) resize(l int) {
+	for len(c.elems) < l {
+		c.elems = append(c.elems, c.group.NewElement())
+	}
+	c.elems = c.elems[:l]
+}
+
+// Add stores in the caller the commitment that represents the addition of the
+// two given commitments, as for Commitment.Add. The caller must be in the
+// same group as the inputs.
+func (c *GroupCommitment) Add(a, b *GroupCommitment) {
+	smaller, larger := a, b
+	if len(a.elems) > len(b.elems) {
+		smaller, larger = b, a
+	}
+	// Copy the input elements before resizing the caller, which might be
+	// aliased with one of the inputs.
+	tail := make([]GroupElement, len(larger.elems)-len(smaller.elems))
+	for i := range tail {
+		tail[i] = c.group.NewElement()
+		tail[i].Set(larger.elems[len(smaller.elems)+i])
+	}
+	c.resize(len(larger.elems))
+	for i := range smaller.elems {
+		c.elems[i].Add(smaller.elems[i], larger.elems[i])
+	}
+	copy(c.elems[len(smaller.elems):], tail)
+}
+
+// Scale stores in the caller the commitment that represents the scaling of the
+// given commitment by the given scalar, as for Commitment.Scale. The caller
+// must be in the same group as the input.
+func (c *GroupCommitment) Scale(other *GroupCommitment, scale *secp256k1.Fn) {
+	c.resize(len(other.elems))
+	for i := range c.elems {
+		c.elems[i].Scale(other.elems[i], scale)
+	}
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (c GroupCommitment) SizeHint() int {
+	size := surge.SizeHintU32
+	for i := range c.elems {
+		size += c.elems[i].SizeHint()
+	}
+	return size
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (c GroupCommitment) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(c.elems)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range c.elems {
+		buf, rem, err = c.elems[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface. The commitment must
+// have been created with NewGroupCommitment, so that the group of the encoded
+// elements is known.
+func (c *GroupCommitment) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if c.group == nil {
+		return buf, rem, errors.New("cannot unmarshal group commitment without a group")
+	}
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, c.group.NewElement().SizeHint(), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	c.resize(int(l))
+	for i := range c.elems {
+		buf, rem, err = c.elems[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// IsValidInGroup returns true when the given verifiable share is valid with
+// regard to the given commitment and second Pedersen generator h, and false
+// otherwise. The element h must be in the group of the commitment.
+func IsValidInGroup(h GroupElement, c *GroupCommitment, vshare *VerifiableShare) bool {
+	if len(c.elems) == 0 {
+		return false
+	}
+	gPow, hPow, eval := c.group.NewElement(), c.group.NewElement(), c.group.NewElement()
+	gPow.BaseExp(&vshare.Share.Value)
+	hPow.Scale(h, &vshare.Decommitment)
+	gPow.Add(gPow, hPow)
+
+	eval.Set(c.elems[len(c.elems)-1])
+	for i := len(c.elems) - 2; i >= 0; i-- {
+		eval.Scale(eval, &vshare.Share.Index)
+		eval.Add(eval, c.elems[i])
+	}
+	return gPow.Eq(eval)
+}
+
+// VShareSecretInGroup is the same as VShareSecret, except that the commitment
+// is computed in the group of the given commitment, which must have been
+// created with NewGroupCommitment. The element h is the second Pedersen
+// generator, and must be in the same group.
+//
+// Panics: This function will panic if the destination shares slice has a
+// capacity less than n (the number of indices).
+func VShareSecretInGroup(
+	vshares *VerifiableShares,
+	c *GroupCommitment,
+	indices []secp256k1.Fn,
+	h GroupElement,
+	secret secp256k1.Fn,
+	k int,
+) error {
+	if err := checkIndices(indices, k); err != nil {
+		return err
+	}
+	n := len(indices)
+	shares := make(Shares, n)
+	coeffs := make([]secp256k1.Fn, k)
+	defer shares.Zero()
+	defer WipeFns(coeffs)
+	err := ShareAndGetCoeffs(&shares, coeffs, indices, secret, k)
+	if err != nil {
+		return err
+	}
+
+	c.resize(k)
+	for i := range coeffs {
+		c.elems[i].BaseExp(&coeffs[i])
+	}
+
+	decom, err := randomFn(RandomSource())
+	if err != nil {
+		return err
+	}
+	if err := setRandomCoeffs(coeffs, decom, k); err != nil {
+		return err
+	}
+	*vshares = (*vshares)[:n]
+	for i := range indices {
+		(*vshares)[i].Share = shares[i]
+		polyEval(&(*vshares)[i].Decommitment, &indices[i], coeffs)
+	}
+
+	hPow := c.group.NewElement()
+	for i := range coeffs {
+		hPow.Scale(h, &coeffs[i])
+		c.elems[i].Add(c.elems[i], hPow)
+	}
+	return nil
+}
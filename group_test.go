@@ -0,0 +1,175 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+// The additive group of the scalar field, which has the right order to be a
+// commitment group but in which commitments are not hiding. It is only used
+// to check that sharing does not depend on the representation of the group.
+type logGroup struct{}
+
+type logElement struct{ log secp256k1.Fn }
+
+func (logGroup) NewElement() GroupElement { return &logElement{} }
+
+func (e *logElement) BaseExp(s *secp256k1.Fn) { e.log = *s }
+
+func (e *logElement) Scale(a GroupElement, s *secp256k1.Fn) {
+	e.log.Mul(&a.(*logElement).log, s)
+}
+
+func (e *logElement) Add(a, b GroupElement) {
+	e.log.Add(&a.(*logElement).log, &b.(*logElement).log)
+}
+
+func (e *logElement) Set(a GroupElement) { e.log = a.(*logElement).log }
+
+func (e *logElement) Eq(other GroupElement) bool {
+	o, ok := other.(*logElement)
+	return ok && e.log.Eq(&o.log)
+}
+
+func (e *logElement) SizeHint() int { return e.log.SizeHint() }
+
+func (e *logElement) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	return e.log.Marshal(buf, rem)
+}
+
+func (e *logElement) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	return e.log.Unmarshal(buf, rem)
+}
+
+var _ = Describe("Commitment groups", func() {
+	trials := 20
+	n := 10
+
+	groups := map[string]CommitmentGroup{
+		"secp256k1":    Secp256k1Group,
+		"discrete log": logGroup{},
+	}
+
+	randomH := func(group CommitmentGroup) GroupElement {
+		h := group.NewElement()
+		r := secp256k1.RandomFn()
+		h.BaseExp(&r)
+		return h
+	}
+
+	for name, group := range groups {
+		name, group := name, group
+
+		Context(name, func() {
+			It("should produce valid shares", func() {
+				indices := RandomIndices(n)
+				vshares := make(VerifiableShares, n)
+				c := NewGroupCommitment(group)
+				h := randomH(group)
+				for i := 0; i < trials; i++ {
+					k := RandRange(1, n)
+					secret := secp256k1.RandomFn()
+					Expect(VShareSecretInGroup(&vshares, &c, indices, h, secret, k)).To(Succeed())
+					Expect(c.Len()).To(Equal(k))
+					for j := range vshares {
+						Expect(IsValidInGroup(h, &c, &vshares[j])).To(BeTrue())
+					}
+					recon := Open(vshares.Shares()[:k])
+					Expect(recon.Eq(&secret)).To(BeTrue())
+				}
+			})
+
+			It("should detect perturbed shares", func() {
+				indices := RandomIndices(n)
+				vshares := make(VerifiableShares, n)
+				c := NewGroupCommitment(group)
+				h := randomH(group)
+				for i := 0; i < trials; i++ {
+					Expect(VShareSecretInGroup(&vshares, &c, indices, h, secp256k1.RandomFn(), RandRange(2, n))).To(Succeed())
+					j := rand.Intn(n)
+					switch rand.Intn(3) {
+					case 0:
+						vshares[j].Share.Index = secp256k1.RandomFn()
+					case 1:
+						vshares[j].Share.Value = secp256k1.RandomFn()
+					default:
+						vshares[j].Decommitment = secp256k1.RandomFn()
+					}
+					Expect(IsValidInGroup(h, &c, &vshares[j])).To(BeFalse())
+				}
+			})
+
+			It("should be homomorphic under addition and scaling", func() {
+				indices := RandomIndices(n)
+				vshares1 := make(VerifiableShares, n)
+				vshares2 := make(VerifiableShares, n)
+				c1, c2 := NewGroupCommitment(group), NewGroupCommitment(group)
+				sum, scaled := NewGroupCommitment(group), NewGroupCommitment(group)
+				h := randomH(group)
+				for i := 0; i < trials; i++ {
+					Expect(VShareSecretInGroup(&vshares1, &c1, indices, h, secp256k1.RandomFn(), RandRange(1, n))).To(Succeed())
+					Expect(VShareSecretInGroup(&vshares2, &c2, indices, h, secp256k1.RandomFn(), RandRange(1, n))).To(Succeed())
+					scale := secp256k1.RandomFn()
+					sum.Add(&c1, &c2)
+					scaled.Scale(&c1, &scale)
+					for j := range indices {
+						var s VerifiableShare
+						s.Add(&vshares1[j], &vshares2[j])
+						Expect(IsValidInGroup(h, &sum, &s)).To(BeTrue())
+						s.Scale(&vshares1[j], &scale)
+						Expect(IsValidInGroup(h, &scaled, &s)).To(BeTrue())
+					}
+
+					// Adding into one of the inputs should give the same result.
+					c1.Add(&c1, &c2)
+					Expect(c1.Eq(&sum)).To(BeTrue())
+				}
+			})
+
+			It("should marshal and unmarshal commitments", func() {
+				vshares := make(VerifiableShares, n)
+				c := NewGroupCommitment(group)
+				for i := 0; i < trials; i++ {
+					Expect(VShareSecretInGroup(&vshares, &c, RandomIndices(n), randomH(group), secp256k1.RandomFn(), RandRange(1, n))).To(Succeed())
+					bs, err := surge.ToBinary(c)
+					Expect(err).ToNot(HaveOccurred())
+					decoded := NewGroupCommitment(group)
+					Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+					Expect(decoded.Eq(&c)).To(BeTrue())
+				}
+			})
+		})
+	}
+
+	It("should agree with Commitment for the secp256k1 group", func() {
+		indices := RandomIndices(n)
+		vshares := make(VerifiableShares, n)
+		c := NewGroupCommitment(Secp256k1Group)
+		h := secp256k1.RandomPoint()
+		hElem := &Secp256k1Element{Point: h}
+		Expect(VShareSecretInGroup(&vshares, &c, indices, hElem, secp256k1.RandomFn(), RandRange(1, n))).To(Succeed())
+
+		com := NewCommitmentWithCapacity(c.Len())
+		for i := 0; i < c.Len(); i++ {
+			com.Append(c.At(i).(*Secp256k1Element).Point)
+		}
+		for j := range vshares {
+			Expect(IsValid(h, &com, &vshares[j])).To(BeTrue())
+		}
+	})
+
+	It("should fail to unmarshal without a group", func() {
+		c := NewGroupCommitment(Secp256k1Group)
+		bs, err := surge.ToBinary(c)
+		Expect(err).ToNot(HaveOccurred())
+		var decoded GroupCommitment
+		Expect(surge.FromBinary(&decoded, bs)).ToNot(Succeed())
+	})
+})
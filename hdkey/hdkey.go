@@ -0,0 +1,324 @@
+// Package hdkey shares keys that are derived from a BIP-32 extended private
+// key, which is the common custody workflow of deriving a child key for an
+// account or address and then splitting it between custodians. SLIP-10
+// derivation for secp256k1 is the same as BIP-32, so SLIP-10 keys are also
+// supported.
+//
+// The derived key can be shared with a Pedersen commitment, using
+// ShareDerived, or with a Feldman commitment, using ShareDerivedFeldman. A
+// Feldman commitment reveals the public key: it is the first point of the
+// commitment, as returned by PublicKey, and the public key share of each
+// custodian is the commitment evaluated at the index of its share, as
+// returned by PublicKeyShare.
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// HardenedOffset is added to a child number to select hardened derivation.
+const HardenedOffset uint32 = 0x80000000
+
+// The versions of serialized extended private keys for mainnet and testnet.
+const (
+	versionMainnetPrivate uint32 = 0x0488ADE4
+	versionTestnetPrivate uint32 = 0x04358394
+)
+
+// The length of a serialized extended key, without the checksum.
+const serializedKeyLen = 78
+
+// ErrInvalidChild is returned when a derivation produces an invalid key. This
+// happens with probability less than 2^-127, and BIP-32 specifies that the
+// next child number should be used instead.
+var ErrInvalidChild = errors.New("derived key is invalid")
+
+// An ExtendedKey is a BIP-32 extended private key: a private key together
+// with a chain code, from which child keys can be derived.
+type ExtendedKey struct {
+	Key       secp256k1.Fn
+	ChainCode [32]byte
+}
+
+// Zero sets the key and chain code to zero. It should be called once the
+// extended key is no longer needed.
+func (xk *ExtendedKey) Zero() {
+	xk.Key.Clear()
+	xk.ChainCode = [32]byte{}
+}
+
+// NewMasterKey derives the master extended key for the given seed, which
+// should be between 16 and 64 bytes long.
+func NewMasterKey(seed []byte) (ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return ExtendedKey{}, fmt.Errorf("seed must be between 16 and 64 bytes, got %v bytes", len(seed))
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	return fromHMAC(mac.Sum(nil), nil)
+}
+
+// ParseExtendedKey parses the base58check serialization of an extended
+// private key, such as a string starting with "xprv" or "tprv". Only the key
+// and chain code are kept; the depth, parent fingerprint and child number are
+// checked to be well formed but are otherwise ignored, so the derivation path
+// given to Derive is relative to the parsed key.
+func ParseExtendedKey(s string) (ExtendedKey, error) {
+	bs, err := decodeBase58Check(s)
+	if err != nil {
+		return ExtendedKey{}, err
+	}
+	defer wipeBytes(bs)
+	if len(bs) != serializedKeyLen {
+		return ExtendedKey{}, fmt.Errorf("expected %v bytes, got %v bytes", serializedKeyLen, len(bs))
+	}
+	version := binary.BigEndian.Uint32(bs[0:4])
+	if version != versionMainnetPrivate && version != versionTestnetPrivate {
+		return ExtendedKey{}, fmt.Errorf("unsupported extended key version %#08x", version)
+	}
+	depth, fingerprint, child := bs[4], bs[5:9], bs[9:13]
+	if depth == 0 && (binary.BigEndian.Uint32(fingerprint) != 0 || binary.BigEndian.Uint32(child) != 0) {
+		return ExtendedKey{}, fmt.Errorf("master key has a parent fingerprint or child number")
+	}
+	if bs[45] != 0 {
+		return ExtendedKey{}, fmt.Errorf("expected a private key")
+	}
+
+	var xk ExtendedKey
+	copy(xk.ChainCode[:], bs[13:45])
+	if xk.Key.SetB32(bs[46:]) || xk.Key.IsZero() {
+		xk.Zero()
+		return ExtendedKey{}, fmt.Errorf("private key is not in range")
+	}
+	return xk, nil
+}
+
+// ParsePath parses a derivation path such as "m/44'/0'/0'/0/7" into child
+// numbers. Hardened children are marked with a trailing ', h or H. The leading
+// "m" is optional.
+func ParsePath(path string) ([]uint32, error) {
+	if path == "m" || path == "" {
+		return []uint32{}, nil
+	}
+	parts := strings.Split(path, "/")
+	if parts[0] == "m" {
+		parts = parts[1:]
+	}
+	children := make([]uint32, len(parts))
+	for i, part := range parts {
+		hardened := false
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			hardened = true
+			part = part[:len(part)-1]
+		}
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || n >= uint64(HardenedOffset) {
+			return nil, fmt.Errorf("invalid path component %q", parts[i])
+		}
+		children[i] = uint32(n)
+		if hardened {
+			children[i] += HardenedOffset
+		}
+	}
+	return children, nil
+}
+
+// Child derives the child extended key with the given child number, which is
+// hardened if it is at least HardenedOffset. ErrInvalidChild is returned in
+// the negligibly unlikely case that the child key is invalid.
+func (xk *ExtendedKey) Child(i uint32) (ExtendedKey, error) {
+	data := make([]byte, 37)
+	defer wipeBytes(data)
+	if i >= HardenedOffset {
+		xk.Key.PutB32(data[1:33])
+	} else {
+		pk := CompressPublicKey(publicKey(&xk.Key))
+		copy(data, pk[:])
+	}
+	binary.BigEndian.PutUint32(data[33:], i)
+
+	mac := hmac.New(sha512.New, xk.ChainCode[:])
+	mac.Write(data)
+	return fromHMAC(mac.Sum(nil), &xk.Key)
+}
+
+// Derive derives the descendant extended key for the given path of child
+// numbers, as returned by ParsePath. The intermediate keys are zeroed.
+func (xk *ExtendedKey) Derive(path []uint32) (ExtendedKey, error) {
+	cur := *xk
+	for _, i := range path {
+		child, err := cur.Child(i)
+		cur.Zero()
+		if err != nil {
+			return ExtendedKey{}, err
+		}
+		cur = child
+	}
+	return cur, nil
+}
+
+// ShareDerived derives the key for the given path from the extended key, and
+// creates verifiable shares of it with a Pedersen commitment, as for
+// shamir.VShareSecret. The derived key is zeroed before returning.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func ShareDerived(
+	vshares *shamir.VerifiableShares,
+	c *shamir.Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	xk *ExtendedKey,
+	path []uint32,
+	k int,
+) error {
+	child, err := xk.Derive(path)
+	if err != nil {
+		return err
+	}
+	defer child.Zero()
+	return shamir.VShareSecret(vshares, c, indices, h, child.Key, k)
+}
+
+// ShareDerivedFeldman derives the key for the given path from the extended
+// key, and creates shares of it with a Feldman commitment, that is, the
+// coefficients of the sharing polynomial multiplied by the base point. The
+// shares can be checked with shamir.IsValidFeldman, and the public key and
+// public key shares can be computed from the commitment with PublicKey and
+// PublicKeyShare. The derived key and coefficients are zeroed before
+// returning.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.ShareSecret, or if the destination commitment has a capacity less
+// than k.
+func ShareDerivedFeldman(
+	shares *shamir.Shares,
+	c *shamir.Commitment,
+	indices []secp256k1.Fn,
+	xk *ExtendedKey,
+	path []uint32,
+	k int,
+) error {
+	child, err := xk.Derive(path)
+	if err != nil {
+		return err
+	}
+	defer child.Zero()
+	coeffs := make([]secp256k1.Fn, k)
+	defer shamir.WipeFns(coeffs)
+	if err := shamir.ShareAndGetCoeffs(shares, coeffs, indices, child.Key, k); err != nil {
+		return err
+	}
+	*c = (*c)[:k]
+	for i := range coeffs {
+		(*c)[i].BaseExp(&coeffs[i])
+	}
+	return nil
+}
+
+// PublicKey returns the public key of the shared key for a Feldman commitment
+// created by ShareDerivedFeldman, which is the first point of the commitment.
+//
+// Panics: This function will panic if the commitment is empty.
+func PublicKey(c shamir.Commitment) secp256k1.Point {
+	return c[0]
+}
+
+// PublicKeyShare returns the public key share of the holder of the share with
+// the given index, for a Feldman commitment created by ShareDerivedFeldman.
+//
+// Panics: This function will panic if the commitment is empty.
+func PublicKeyShare(c shamir.Commitment, index *secp256k1.Fn) secp256k1.Point {
+	return c.Evaluate(index)
+}
+
+// CompressPublicKey returns the 33 byte SEC1 compressed encoding of the given
+// point, which is how BIP-32 serializes public keys.
+//
+// Panics: This function will panic if the point is the point at infinity.
+func CompressPublicKey(p secp256k1.Point) [33]byte {
+	if p.IsInfinity() {
+		panic("cannot compress the point at infinity")
+	}
+	// PutBytes writes the parity of y as 0 or 1, followed by x, and SEC1 uses
+	// 2 or 3 for the prefix.
+	var bs [33]byte
+	p.PutBytes(bs[:])
+	bs[0] += 2
+	return bs
+}
+
+func publicKey(key *secp256k1.Fn) secp256k1.Point {
+	var p secp256k1.Point
+	p.BaseExp(key)
+	return p
+}
+
+// Constructs an extended key from the output of the HMAC, whose left half is
+// added to the parent key, if there is one, and whose right half is the chain
+// code.
+func fromHMAC(sum []byte, parent *secp256k1.Fn) (ExtendedKey, error) {
+	defer wipeBytes(sum)
+	var xk ExtendedKey
+	if xk.Key.SetB32(sum[:32]) {
+		return ExtendedKey{}, ErrInvalidChild
+	}
+	if parent != nil {
+		xk.Key.Add(&xk.Key, parent)
+	}
+	if xk.Key.IsZero() {
+		return ExtendedKey{}, ErrInvalidChild
+	}
+	copy(xk.ChainCode[:], sum[32:])
+	return xk, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Decodes a base58 string with a 4 byte double SHA-256 checksum, and returns
+// the data without the checksum.
+func decodeBase58Check(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	// Each leading '1' encodes a leading zero byte.
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	bs := append(make([]byte, zeros), n.Bytes()...)
+	if len(bs) < 4 {
+		return nil, fmt.Errorf("base58check string too short")
+	}
+	data, checksum := bs[:len(bs)-4], bs[len(bs)-4:]
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	if !hmac.Equal(second[:4], checksum) {
+		return nil, fmt.Errorf("invalid base58check checksum")
+	}
+	return data, nil
+}
+
+func wipeBytes(bs []byte) {
+	for i := range bs {
+		bs[i] = 0
+	}
+}
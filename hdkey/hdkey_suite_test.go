@@ -0,0 +1,13 @@
+package hdkey_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestHDKey(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HD Key Suite")
+}
@@ -0,0 +1,140 @@
+package hdkey_test
+
+import (
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/hdkey"
+)
+
+func mustDecodeHex(s string) []byte {
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return bs
+}
+
+var _ = Describe("HD key sharing", func() {
+	// Test vector 1 of BIP-32.
+	seed := mustDecodeHex("000102030405060708090a0b0c0d0e0f")
+	masterXprv := "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+	vectors := []struct {
+		path, key, chainCode string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0H", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0H/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0H/1/2H", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0H/1/2H/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0H/1/2H/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	}
+
+	Context("deriving keys", func() {
+		It("should match the BIP-32 test vectors", func() {
+			master, err := NewMasterKey(seed)
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := ParseExtendedKey(masterXprv)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(master))
+
+			for _, v := range vectors {
+				path, err := ParsePath(v.path)
+				Expect(err).ToNot(HaveOccurred())
+				child, err := master.Derive(path)
+				Expect(err).ToNot(HaveOccurred())
+
+				var key [32]byte
+				child.Key.PutB32(key[:])
+				Expect(hex.EncodeToString(key[:])).To(Equal(v.key), v.path)
+				Expect(hex.EncodeToString(child.ChainCode[:])).To(Equal(v.chainCode), v.path)
+			}
+		})
+
+		It("should parse paths", func() {
+			path, err := ParsePath("m/44'/0h/1H/0/7")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal([]uint32{44 + HardenedOffset, HardenedOffset, 1 + HardenedOffset, 0, 7}))
+
+			path, err = ParsePath("1/2")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal([]uint32{1, 2}))
+
+			for _, bad := range []string{"m/", "m/x", "m/1//2", "m/2147483648", "m/-1", "m/1''"} {
+				_, err := ParsePath(bad)
+				Expect(err).To(HaveOccurred(), bad)
+			}
+		})
+
+		It("should reject malformed extended keys and seeds", func() {
+			// Changing a character breaks the checksum.
+			_, err := ParseExtendedKey(masterXprv[:20] + "A" + masterXprv[21:])
+			Expect(err).To(HaveOccurred())
+			_, err = ParseExtendedKey(masterXprv[:len(masterXprv)-1])
+			Expect(err).To(HaveOccurred())
+			_, err = ParseExtendedKey("xprv0")
+			Expect(err).To(HaveOccurred())
+
+			_, err = NewMasterKey(seed[:15])
+			Expect(err).To(HaveOccurred())
+			_, err = NewMasterKey(make([]byte, 65))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("sharing derived keys", func() {
+		n, k := 10, 4
+		path := []uint32{44 + HardenedOffset, HardenedOffset, 0, 5}
+
+		derived := func() (ExtendedKey, secp256k1.Fn) {
+			master, err := NewMasterKey(seed)
+			Expect(err).ToNot(HaveOccurred())
+			child, err := master.Derive(path)
+			Expect(err).ToNot(HaveOccurred())
+			return master, child.Key
+		}
+
+		It("should share the derived key with a Pedersen commitment", func() {
+			master, key := derived()
+			h := shamir.PedersenH()
+			vshares := make(shamir.VerifiableShares, n)
+			c := shamir.NewCommitmentWithCapacity(k)
+			Expect(ShareDerived(&vshares, &c, shamirutil.RandomIndices(n), h, &master, path, k)).To(Succeed())
+
+			for i := range vshares {
+				Expect(shamir.IsValid(h, &c, &vshares[i])).To(BeTrue())
+			}
+			opened := shamir.Open(vshares.Shares()[:k])
+			Expect(opened.Eq(&key)).To(BeTrue())
+		})
+
+		It("should share the derived key with a Feldman commitment to its public key", func() {
+			master, key := derived()
+			shares := make(shamir.Shares, n)
+			c := shamir.NewCommitmentWithCapacity(k)
+			Expect(ShareDerivedFeldman(&shares, &c, shamirutil.RandomIndices(n), &master, path, k)).To(Succeed())
+
+			var pk secp256k1.Point
+			pk.BaseExp(&key)
+			groupKey := PublicKey(c)
+			Expect(groupKey.Eq(&pk)).To(BeTrue())
+			for i := range shares {
+				Expect(shamir.IsValidFeldman(&c, &shares[i])).To(BeTrue())
+				var expected secp256k1.Point
+				expected.BaseExp(&shares[i].Value)
+				pkShare := PublicKeyShare(c, &shares[i].Index)
+				Expect(pkShare.Eq(&expected)).To(BeTrue())
+			}
+			opened := shamir.Open(shares[:k])
+			Expect(opened.Eq(&key)).To(BeTrue())
+
+			compressed := CompressPublicKey(pk)
+			Expect(compressed[0]).To(Or(Equal(byte(2)), Equal(byte(3))))
+		})
+	})
+})
@@ -0,0 +1,205 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// An IndexSet is a fixed set of distinct, non-zero share indices, such as the
+// indices of the parties in a committee. It records whether the indices follow
+// the common convention that party i has index i+1, in which case the
+// denominators of the Lagrange coefficients are small integers whose inverses
+// are precomputed, and the position of an index is read off its value. For
+// other indices, the generic methods are used.
+//
+// An IndexSet is not modified after construction, and so is safe for
+// concurrent use.
+type IndexSet struct {
+	indices    []secp256k1.Fn
+	sequential bool
+	// For sequential sets, invs[d] is the inverse of d for 0 < d < n.
+	invs []secp256k1.Fn
+	// For other sets, the position of each index.
+	positions map[[32]byte]int
+}
+
+// NewIndexSet constructs the set of the given indices, in the given order. An
+// error wrapping ErrZeroIndex or ErrDuplicateIndex is returned if any of the
+// indices is zero or any two of them are equal. The indices are copied, and so
+// are safe to modify after this function returns.
+func NewIndexSet(indices []secp256k1.Fn) (IndexSet, error) {
+	s := IndexSet{indices: make([]secp256k1.Fn, len(indices))}
+	copy(s.indices, indices)
+	if isSequential(s.indices) {
+		s.sequential = true
+		s.invs = integerInverses(len(s.indices))
+		return s, nil
+	}
+
+	s.positions = make(map[[32]byte]int, len(indices))
+	var key [32]byte
+	for i := range s.indices {
+		if s.indices[i].IsZero() {
+			return IndexSet{}, fmt.Errorf("%w: index %v", ErrZeroIndex, i)
+		}
+		s.indices[i].PutB32(key[:])
+		if j, ok := s.positions[key]; ok {
+			return IndexSet{}, fmt.Errorf("%w: indices %v and %v are equal", ErrDuplicateIndex, j, i)
+		}
+		s.positions[key] = i
+	}
+	return s, nil
+}
+
+// NewSequentialIndexSet constructs the set of the indices 1, 2, ..., n.
+func NewSequentialIndexSet(n int) IndexSet {
+	indices := make([]secp256k1.Fn, n)
+	var one secp256k1.Fn
+	one.SetU16(1)
+	for i := range indices {
+		if i == 0 {
+			indices[i] = one
+		} else {
+			indices[i].Add(&indices[i-1], &one)
+		}
+	}
+	return IndexSet{indices: indices, sequential: true, invs: integerInverses(n)}
+}
+
+// Len returns the number of indices in the set.
+func (s *IndexSet) Len() int { return len(s.indices) }
+
+// IsSequential returns true if the indices of the set are 1, 2, ..., n in
+// that order, and false otherwise.
+func (s *IndexSet) IsSequential() bool { return s.sequential }
+
+// Indices returns a copy of the indices of the set.
+func (s *IndexSet) Indices() []secp256k1.Fn {
+	indices := make([]secp256k1.Fn, len(s.indices))
+	copy(indices, s.indices)
+	return indices
+}
+
+// Index returns the index at the given position.
+//
+// Panics: This function will panic if the position is out of range.
+func (s *IndexSet) Index(position int) secp256k1.Fn { return s.indices[position] }
+
+// Position returns the position of the given index in the set, and false if
+// it is not in the set.
+func (s *IndexSet) Position(index *secp256k1.Fn) (int, bool) {
+	var key [32]byte
+	index.PutB32(key[:])
+	if s.sequential {
+		for _, b := range key[:28] {
+			if b != 0 {
+				return 0, false
+			}
+		}
+		v := binary.BigEndian.Uint32(key[28:])
+		if v == 0 || uint64(v) > uint64(len(s.indices)) {
+			return 0, false
+		}
+		return int(v) - 1, true
+	}
+	i, ok := s.positions[key]
+	return i, ok
+}
+
+// LagrangeCoefficients computes the Lagrange coefficients for interpolation at
+// zero over the indices at the given positions of the set, so that the i-th
+// coefficient is that of the index at positions[i]. For sequential sets, no
+// inversions are needed, and the coefficients for the whole set in order take
+// linear time. An error is returned if a position is out of range or
+// repeated.
+func (s *IndexSet) LagrangeCoefficients(positions []int) ([]secp256k1.Fn, error) {
+	seen := make([]bool, len(s.indices))
+	inOrder := len(positions) == len(s.indices)
+	for i, p := range positions {
+		if p < 0 || p >= len(s.indices) {
+			return nil, fmt.Errorf("position %v is out of range", p)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("position %v is repeated", p)
+		}
+		seen[p] = true
+		inOrder = inOrder && p == i
+	}
+
+	if !s.sequential {
+		subset := make([]secp256k1.Fn, len(positions))
+		for i, p := range positions {
+			subset[i] = s.indices[p]
+		}
+		return lagrangeAtZero(subset)
+	}
+	if inOrder {
+		return lagrangeSequential(len(positions)), nil
+	}
+
+	// The i-th coefficient is the product of x_j / (x_j - x_i) over j != i,
+	// where x_j - x_i = p_j - p_i is a small integer.
+	coeffs := make([]secp256k1.Fn, len(positions))
+	var inv secp256k1.Fn
+	for i, p := range positions {
+		coeffs[i].SetU16(1)
+		for _, q := range positions {
+			if q == p {
+				continue
+			}
+			if q > p {
+				inv = s.invs[q-p]
+			} else {
+				inv.Negate(&s.invs[p-q])
+			}
+			coeffs[i].Mul(&coeffs[i], &s.indices[q])
+			coeffs[i].Mul(&coeffs[i], &inv)
+		}
+	}
+	return coeffs, nil
+}
+
+// Open reconstructs the secret from the given shares, whose indices must be
+// in the set, as for Open. An error is returned if the index of a share is
+// not in the set, or if two shares have the same index.
+func (s *IndexSet) Open(shares Shares) (secp256k1.Fn, error) {
+	positions := make([]int, len(shares))
+	for i := range shares {
+		p, ok := s.Position(&shares[i].Index)
+		if !ok {
+			return secp256k1.Fn{}, fmt.Errorf("index of share %v is not in the set", i)
+		}
+		positions[i] = p
+	}
+	coeffs, err := s.LagrangeCoefficients(positions)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	defer WipeFns(coeffs)
+
+	var secret, term secp256k1.Fn
+	for i := range shares {
+		term.Mul(&coeffs[i], &shares[i].Value)
+		secret.Add(&secret, &term)
+	}
+	term.Clear()
+	return secret, nil
+}
+
+// Returns the table whose d-th entry is the inverse of d for 0 < d < n.
+func integerInverses(n int) []secp256k1.Fn {
+	invs := make([]secp256k1.Fn, n)
+	if n < 2 {
+		return invs
+	}
+	var one secp256k1.Fn
+	one.SetU16(1)
+	invs[1] = one
+	for d := 2; d < n; d++ {
+		invs[d].Add(&invs[d-1], &one)
+	}
+	BatchInvert(invs[1:])
+	return invs
+}
@@ -0,0 +1,129 @@
+package shamir_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Index sets", func() {
+	trials := 20
+	n := 10
+
+	for _, sequential := range []bool{true, false} {
+		sequential := sequential
+		description := "when the indices are random"
+		if sequential {
+			description = "when the indices are sequential"
+		}
+		newSet := func() IndexSet {
+			if sequential {
+				return NewSequentialIndexSet(n)
+			}
+			set, err := NewIndexSet(RandomIndices(n))
+			Expect(err).ToNot(HaveOccurred())
+			return set
+		}
+
+		Context(description, func() {
+			It("should detect whether the indices are sequential", func() {
+				set := newSet()
+				Expect(set.IsSequential()).To(Equal(sequential))
+				other, err := NewIndexSet(set.Indices())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(other.IsSequential()).To(Equal(sequential))
+			})
+
+			It("should find the position of every index", func() {
+				set := newSet()
+				for i := 0; i < set.Len(); i++ {
+					index := set.Index(i)
+					p, ok := set.Position(&index)
+					Expect(ok).To(BeTrue())
+					Expect(p).To(Equal(i))
+				}
+				zero := secp256k1.Fn{}
+				outside := secp256k1.NewFnFromU16(uint16(n + 1))
+				random := secp256k1.RandomFn()
+				for _, index := range []secp256k1.Fn{zero, outside, random} {
+					_, ok := set.Position(&index)
+					Expect(ok).To(BeFalse())
+				}
+			})
+
+			It("should compute the same coefficients as LagrangeCoefficients", func() {
+				set := newSet()
+				for i := 0; i < trials; i++ {
+					positions := rand.Perm(n)[:RandRange(1, n)]
+					if i == 0 {
+						// The whole set in order.
+						positions = make([]int, n)
+						for j := range positions {
+							positions[j] = j
+						}
+					}
+					subset := make([]secp256k1.Fn, len(positions))
+					for j, p := range positions {
+						subset[j] = set.Index(p)
+					}
+					expected, err := LagrangeCoefficients(subset)
+					Expect(err).ToNot(HaveOccurred())
+					coeffs, err := set.LagrangeCoefficients(positions)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(coeffs).To(Equal(expected))
+				}
+			})
+
+			It("should open any qualified subset of shares", func() {
+				set := newSet()
+				shares := make(Shares, n)
+				for i := 0; i < trials; i++ {
+					k := RandRange(1, n)
+					secret := secp256k1.RandomFn()
+					Expect(ShareSecret(&shares, set.Indices(), secret, k)).To(Succeed())
+					subset := make(Shares, RandRange(k, n))
+					for j, p := range rand.Perm(n)[:len(subset)] {
+						subset[j] = shares[p]
+					}
+					opened, err := set.Open(subset)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(opened.Eq(&secret)).To(BeTrue())
+				}
+			})
+
+			It("should return errors for invalid positions and shares", func() {
+				set := newSet()
+				_, err := set.LagrangeCoefficients([]int{0, 0})
+				Expect(err).To(HaveOccurred())
+				_, err = set.LagrangeCoefficients([]int{n})
+				Expect(err).To(HaveOccurred())
+				_, err = set.LagrangeCoefficients([]int{-1})
+				Expect(err).To(HaveOccurred())
+
+				_, err = set.Open(Shares{NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())})
+				Expect(err).To(HaveOccurred())
+				share := NewShare(set.Index(0), secp256k1.RandomFn())
+				_, err = set.Open(Shares{share, share})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	}
+
+	It("should reject zero and duplicate indices", func() {
+		indices := RandomIndices(n)
+		indices[3] = secp256k1.Fn{}
+		_, err := NewIndexSet(indices)
+		Expect(errors.Is(err, ErrZeroIndex)).To(BeTrue())
+
+		indices = RandomIndices(n)
+		indices[3] = indices[7]
+		_, err = NewIndexSet(indices)
+		Expect(errors.Is(err, ErrDuplicateIndex)).To(BeTrue())
+	})
+})
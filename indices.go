@@ -0,0 +1,156 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/internal/sharing"
+)
+
+// ErrZeroIndex is returned when an index is zero. A share with index zero
+// would be the secret itself.
+var ErrZeroIndex = errors.New("index is zero")
+
+// ErrDuplicateIndex is returned when two indices are equal. Shares with equal
+// indices can not be used together to reconstruct a secret.
+var ErrDuplicateIndex = errors.New("duplicate index")
+
+// ErrInvalidThreshold is wrapped by the errors returned when a reconstruction
+// threshold is not positive, or is greater than the number of shares. An
+// ErrKTooLarge is also an ErrInvalidThreshold for errors.Is. The p256, bn254
+// and ristretto255 packages return the same errors.
+var ErrInvalidThreshold = sharing.ErrInvalidThreshold
+
+// ErrKTooLarge is returned by the sharing functions when the reconstruction
+// threshold K is greater than the number N of indices.
+type ErrKTooLarge = sharing.ErrKTooLarge
+
+// ValidateIndices returns an error if any of the given indices is zero or if
+// any two of them are equal, and nil otherwise. The returned error wraps
+// ErrZeroIndex or ErrDuplicateIndex.
+func ValidateIndices(indices []secp256k1.Fn) error {
+	return validateIndices(indices, ShareOptions{RejectZero: true, RejectDuplicates: true})
+}
+
+// ShareOptions configures the checks that ShareSecret and VShareSecret
+// perform on the indices, the source of randomness that they use, and the
+// optional outputs of verifiable sharing. The zero
+// value gives the default behaviour, which is to panic if an index is zero, to
+// allow duplicate indices, and to use the source set with SetRandomSource.
+type ShareOptions struct {
+	// RejectZero makes sharing return an error wrapping ErrZeroIndex, rather
+	// than panic, when an index is zero.
+	RejectZero bool
+	// RejectDuplicates makes sharing return an error wrapping
+	// ErrDuplicateIndex when two indices are equal.
+	RejectDuplicates bool
+	// Random, if not nil, is used instead of the source set with
+	// SetRandomSource.
+	Random io.Reader
+	// BlindingCommitment, if not nil, is where verifiable sharing stores the
+	// commitment to the blinding polynomial on its own, as for
+	// WithBlindingCommitment. Plain sharing ignores it.
+	BlindingCommitment *Commitment
+	// CheckCoefficients makes sharing sample the random coefficients again
+	// if any of them is zero or two of them are equal, as for
+	// WithCoefficientCheck.
+	CheckCoefficients bool
+	// EntropyCheck, if not nil, is given the random bytes that coefficients
+	// are sampled from, as for WithEntropyCheck.
+	EntropyCheck func(sample []byte) error
+}
+
+// A ShareOption modifies the ShareOptions used for sharing.
+type ShareOption func(*ShareOptions)
+
+// WithRejectDuplicates makes sharing return an error when two of the indices
+// are equal.
+func WithRejectDuplicates() ShareOption {
+	return func(opts *ShareOptions) { opts.RejectDuplicates = true }
+}
+
+// WithAllowZeroIndexOff turns off the panic for a zero index, so that sharing
+// returns an error when one of the indices is zero instead.
+func WithAllowZeroIndexOff() ShareOption {
+	return func(opts *ShareOptions) { opts.RejectZero = true }
+}
+
+// WithRandomSource makes sharing read its random values from the given source
+// instead of the one set with SetRandomSource.
+func WithRandomSource(r io.Reader) ShareOption {
+	return func(opts *ShareOptions) { opts.Random = r }
+}
+
+// WithCoefficientCheck makes sharing check that the random coefficients that
+// it samples are non-zero and distinct, and sample them again if they are
+// not. For a working source of randomness this never happens, so the check
+// only guards against a source that is broken or has little entropy; if the
+// coefficients still fail the check after a few attempts, sharing returns an
+// error wrapping ErrWeakRandomness. Checking for distinct coefficients takes
+// time quadratic in the threshold.
+func WithCoefficientCheck() ShareOption {
+	return func(opts *ShareOptions) { opts.CheckCoefficients = true }
+}
+
+// WithEntropyCheck makes sharing pass the random bytes from which each batch
+// of coefficients is sampled to the given function before they are used, for
+// example to run a statistical health test on the source. If it returns an
+// error, sharing returns an error wrapping both ErrWeakRandomness and the
+// error. The sample is wiped after the call, so the function must not keep
+// it.
+func WithEntropyCheck(check func(sample []byte) error) ShareOption {
+	return func(opts *ShareOptions) { opts.EntropyCheck = check }
+}
+
+// WithBlindingCommitment makes verifiable sharing also store in dst the
+// commitment to the blinding polynomial g alone, whose i-th point is g_i*h,
+// for protocols that need it separately from the Pedersen commitment. This
+// saves the dealer from computing it again, which it could only do with
+// VShareSecretAndGetCoeffs. The option is ignored by plain sharing.
+//
+// Panics: Verifiable sharing will panic if the capacity of dst is less than
+// k.
+func WithBlindingCommitment(dst *Commitment) ShareOption {
+	return func(opts *ShareOptions) { opts.BlindingCommitment = dst }
+}
+
+// Returns the source of randomness given by the options.
+func (opts *ShareOptions) random() io.Reader {
+	if opts.Random != nil {
+		return opts.Random
+	}
+	return RandomSource()
+}
+
+func newShareOptions(opts []ShareOption) ShareOptions {
+	// Applying options makes the struct escape, so avoid an allocation in the
+	// common case where there are none.
+	if len(opts) == 0 {
+		return ShareOptions{}
+	}
+	var options ShareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// Checks the indices according to the given options. Checking for duplicates
+// takes time quadratic in the number of indices.
+func validateIndices(indices []secp256k1.Fn, opts ShareOptions) error {
+	for i := range indices {
+		if opts.RejectZero && indices[i].IsZero() {
+			return fmt.Errorf("%w: index %v", ErrZeroIndex, i)
+		}
+		if opts.RejectDuplicates {
+			for j := 0; j < i; j++ {
+				if indices[i].Eq(&indices[j]) {
+					return fmt.Errorf("%w: indices %v and %v are equal", ErrDuplicateIndex, j, i)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package shamir_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Index validation", func() {
+	n := 10
+	k := 5
+	h := PedersenH()
+
+	It("should accept distinct non zero indices", func() {
+		Expect(ValidateIndices(RandomIndices(n))).To(Succeed())
+		Expect(ValidateIndices(SequentialIndices(n))).To(Succeed())
+		Expect(ValidateIndices(nil)).To(Succeed())
+	})
+
+	It("should reject zero and duplicate indices", func() {
+		indices := RandomIndices(n)
+		indices[RandRange(0, n-1)].Clear()
+		Expect(errors.Is(ValidateIndices(indices), ErrZeroIndex)).To(BeTrue())
+
+		indices = RandomIndices(n)
+		indices[n-1] = indices[0]
+		Expect(errors.Is(ValidateIndices(indices), ErrDuplicateIndex)).To(BeTrue())
+	})
+
+	Context("when sharing", func() {
+		secret := secp256k1.RandomFn()
+
+		It("should keep the default behaviour without options", func() {
+			indices := RandomIndices(n)
+			indices[n-1] = indices[0]
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+
+			indices[0].Clear()
+			Expect(func() { _ = ShareSecret(&shares, indices, secret, k) }).To(Panic())
+		})
+
+		It("should reject duplicates when asked to", func() {
+			indices := RandomIndices(n)
+			indices[n-1] = indices[0]
+			shares := make(Shares, n)
+			err := ShareSecret(&shares, indices, secret, k, WithRejectDuplicates())
+			Expect(errors.Is(err, ErrDuplicateIndex)).To(BeTrue())
+
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			err = VShareSecret(&vshares, &c, indices, h, secret, k, WithRejectDuplicates())
+			Expect(errors.Is(err, ErrDuplicateIndex)).To(BeTrue())
+		})
+
+		It("should return an error for a zero index when asked to", func() {
+			indices := RandomIndices(n)
+			indices[RandRange(0, n-1)].Clear()
+			shares := make(Shares, n)
+			err := ShareSecret(&shares, indices, secret, k, WithAllowZeroIndexOff())
+			Expect(errors.Is(err, ErrZeroIndex)).To(BeTrue())
+
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			err = VShareSecret(&vshares, &c, indices, h, secret, k, WithAllowZeroIndexOff(), WithRejectDuplicates())
+			Expect(errors.Is(err, ErrZeroIndex)).To(BeTrue())
+		})
+
+		It("should share valid indices with all options", func() {
+			indices := RandomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			Expect(VShareSecret(&vshares, &c, indices, h, secret, k, WithAllowZeroIndexOff(), WithRejectDuplicates())).To(Succeed())
+			for i := range vshares {
+				Expect(IsValid(h, &c, &vshares[i])).To(BeTrue())
+			}
+		})
+	})
+})
@@ -0,0 +1,80 @@
+// Package sharing holds the errors and the source of randomness that are
+// shared by the shamir package and the packages for the other groups. The shamir package imports those
+// packages, so they can not import it, and both import this package instead.
+// The shamir package exports everything here under the same names.
+package sharing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// ErrInvalidThreshold is wrapped by the errors returned when a reconstruction
+// threshold is not positive, or is greater than the number of shares.
+var ErrInvalidThreshold = errors.New("invalid threshold")
+
+// ErrKTooLarge is returned by the sharing functions when the reconstruction
+// threshold K is greater than the number N of indices.
+type ErrKTooLarge struct {
+	K, N int
+}
+
+func (err *ErrKTooLarge) Error() string {
+	return fmt.Sprintf("reconstruction threshold too large: expected k <= %v, got k = %v", err.N, err.K)
+}
+
+// Is returns true if the target is ErrInvalidThreshold.
+func (err *ErrKTooLarge) Is(target error) bool {
+	return target == ErrInvalidThreshold
+}
+
+// CheckThreshold returns an error wrapping ErrInvalidThreshold if the
+// threshold k is not positive, and an ErrKTooLarge if it is greater than the
+// number n of indices.
+func CheckThreshold(k, n int) error {
+	if k < 1 {
+		return fmt.Errorf("%w: expected k >= 1, got k = %v", ErrInvalidThreshold, k)
+	}
+	if k > n {
+		return &ErrKTooLarge{K: k, N: n}
+	}
+	return nil
+}
+
+// The value stored in an atomic.Value must always have the same concrete type,
+// so the reader is wrapped.
+type randomReader struct {
+	r io.Reader
+}
+
+var randomSourceValue atomic.Value
+
+func init() {
+	randomSourceValue.Store(randomReader{r: rand.Reader})
+}
+
+// SetRandomSource sets the source of randomness for sharing. Passing nil
+// restores the default, which is crypto/rand.Reader.
+func SetRandomSource(r io.Reader) {
+	if r == nil {
+		r = rand.Reader
+	}
+	randomSourceValue.Store(randomReader{r: r})
+}
+
+// RandomSource returns the source of randomness that was set with
+// SetRandomSource, or crypto/rand.Reader if none was set.
+func RandomSource() io.Reader {
+	return randomSourceValue.Load().(randomReader).r
+}
+
+// ReadRandom fills the given slice with bytes from the given source.
+func ReadRandom(r io.Reader, bs []byte) error {
+	if _, err := io.ReadFull(r, bs); err != nil {
+		return fmt.Errorf("could not generate random bytes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package interop
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"golang.org/x/crypto/sha3"
+)
+
+// The signatures of the functions of a verification contract for which this
+// package builds calldata. The commitment is a uint256[2][] of points and
+// each share is a uint256[3] of its index, value and decommitment, in the
+// encodings of CommitmentEVM and VShareEVM; h is the Pedersen parameter.
+const (
+	// VerifySharesSignature checks the shares against a commitment that is
+	// given in the calldata.
+	VerifySharesSignature = "verifyShares(uint256[2],uint256[2][],uint256[3][])"
+	// StoreCommitmentSignature stores the points of a commitment, starting at
+	// the given position, under the hash of the commitment. A commitment that
+	// is too large for one transaction is stored by several calls.
+	StoreCommitmentSignature = "storeCommitment(bytes32,uint256,uint256[2][])"
+	// VerifyStoredSharesSignature checks the shares against a commitment that
+	// was stored under the given hash.
+	VerifyStoredSharesSignature = "verifyStoredShares(bytes32,uint256[2],uint256[3][])"
+)
+
+// ErrGasLimit is returned when a single share or point can not be checked or
+// stored within the gas limit of a transaction.
+var ErrGasLimit = errors.New("gas limit too low")
+
+// A GasSchedule estimates the gas used by the calls for which this package
+// builds calldata. The cost of the calldata is exact, but the cost of
+// execution depends on the contract and is only an estimate.
+type GasSchedule struct {
+	// Tx is the base cost of a transaction.
+	Tx uint64
+	// ZeroByte and NonZeroByte are the costs of a byte of calldata.
+	ZeroByte, NonZeroByte uint64
+	// Term is the cost of multiplying one point of the commitment by a power
+	// of the index of a share when checking it, so checking n shares against
+	// a commitment with k points costs n k Term.
+	Term uint64
+	// StoreWord is the cost of storing a 32 byte word in a new storage slot,
+	// so storing k points costs 2 k StoreWord.
+	StoreWord uint64
+}
+
+// DefaultGasSchedule uses the transaction and calldata costs of EIP-2028, the
+// cost of a cold SSTORE to a new slot, and the cost of a scalar
+// multiplication done with the ecrecover precompile.
+var DefaultGasSchedule = GasSchedule{
+	Tx:          21000,
+	ZeroByte:    4,
+	NonZeroByte: 16,
+	Term:        3500,
+	StoreWord:   22100,
+}
+
+// CalldataGas returns the cost of the given calldata.
+func (sched GasSchedule) CalldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += sched.ZeroByte
+		} else {
+			gas += sched.NonZeroByte
+		}
+	}
+	return gas
+}
+
+// Selector returns the function selector of the given signature, which is
+// the first 4 bytes of its Keccak-256 hash.
+func Selector(signature string) [4]byte {
+	var sel [4]byte
+	copy(sel[:], keccak256([]byte(signature)))
+	return sel
+}
+
+// CommitmentHash returns the Keccak-256 hash of the commitment in the encoding
+// of CommitmentEVM, which is keccak256(abi.encodePacked(commitment)) in
+// Solidity. It identifies a stored commitment.
+func CommitmentHash(c shamir.Commitment) [32]byte {
+	var hash [32]byte
+	copy(hash[:], keccak256(CommitmentEVM(c)))
+	return hash
+}
+
+// VerifySharesCalldata returns the calldata of a call to verifyShares that
+// checks all of the given shares against the commitment.
+func VerifySharesCalldata(h secp256k1.Point, c shamir.Commitment, vshares shamir.VerifiableShares) []byte {
+	prefix := verifySharesPrefix(h, c)
+	return appendShares(prefix, vshares)
+}
+
+// BatchVerifySharesCalldata splits the shares into batches such that the
+// estimated gas of each call to verifyShares is at most gasLimit, and returns
+// the calldata of each call. Every call repeats the commitment, so for large
+// commitments it can be cheaper to store the commitment with
+// StoreCommitmentCalldata and check the shares with
+// BatchVerifyStoredSharesCalldata. An error wrapping ErrGasLimit is returned
+// if a call that checks a single share would exceed the gas limit.
+func BatchVerifySharesCalldata(h secp256k1.Point, c shamir.Commitment, vshares shamir.VerifiableShares, gasLimit uint64, sched GasSchedule) ([][]byte, error) {
+	return batchShares(verifySharesPrefix(h, c), c.Len(), vshares, gasLimit, sched)
+}
+
+// StoreCommitmentCalldata splits the commitment into chunks of consecutive
+// points such that the estimated gas of each call to storeCommitment is at
+// most gasLimit, and returns the calldata of each call. Each call stores its
+// chunk under the CommitmentHash of the whole commitment, at the position of
+// its first point, so the contract can check the hash once all points have
+// been stored. An error wrapping ErrGasLimit is returned if a call that
+// stores a single point would exceed the gas limit.
+func StoreCommitmentCalldata(c shamir.Commitment, gasLimit uint64, sched GasSchedule) ([][]byte, error) {
+	hash := CommitmentHash(c)
+	points := CommitmentEVM(c)
+
+	var calls [][]byte
+	for start := 0; start < c.Len(); {
+		prefix := make([]byte, 4, 4+4*32)
+		sel := Selector(StoreCommitmentSignature)
+		copy(prefix, sel[:])
+		prefix = append(prefix, hash[:]...)
+		prefix = appendWord(prefix, uint64(start))
+		prefix = appendWord(prefix, 3*32)
+		gas := sched.Tx + sched.CalldataGas(prefix)
+
+		end := start
+		for end < c.Len() {
+			point := points[EVMPointSize*end : EVMPointSize*(end+1)]
+			next := gas + sched.CalldataGas(point) + 2*sched.StoreWord
+			if next+wordGas(uint64(end-start+1), sched) > gasLimit {
+				break
+			}
+			gas = next
+			end++
+		}
+		if end == start {
+			return nil, fmt.Errorf("%w: can not store point %v within %v gas", ErrGasLimit, start, gasLimit)
+		}
+
+		call := appendWord(prefix, uint64(end-start))
+		call = append(call, points[EVMPointSize*start:EVMPointSize*end]...)
+		calls = append(calls, call)
+		start = end
+	}
+	return calls, nil
+}
+
+// VerifyStoredSharesCalldata returns the calldata of a call to
+// verifyStoredShares that checks all of the given shares against the
+// commitment stored under the given hash.
+func VerifyStoredSharesCalldata(hash [32]byte, h secp256k1.Point, vshares shamir.VerifiableShares) []byte {
+	return appendShares(verifyStoredSharesPrefix(hash, h), vshares)
+}
+
+// BatchVerifyStoredSharesCalldata is the same as BatchVerifySharesCalldata,
+// but checks the shares against the commitment with k points that is stored
+// under the given hash.
+func BatchVerifyStoredSharesCalldata(hash [32]byte, h secp256k1.Point, k int, vshares shamir.VerifiableShares, gasLimit uint64, sched GasSchedule) ([][]byte, error) {
+	return batchShares(verifyStoredSharesPrefix(hash, h), k, vshares, gasLimit, sched)
+}
+
+// DealingRoot returns the root of a Merkle tree whose leaves are the given
+// commitments, so that a set of dealings can be anchored on chain with a
+// single word. Each leaf is keccak256(bytes.concat(CommitmentHash(c))), and
+// each interior node is the Keccak-256 hash of its two children in increasing
+// order, so that DealingProof gives proofs that can be checked with
+// MerkleProof.verify of OpenZeppelin. A node without a sibling is carried up
+// to the next level unchanged. Leaves are hashed twice so that they can not be
+// confused with interior nodes, since a commitment with a single point has the
+// same size as two hashes. The root of no dealings is zero.
+func DealingRoot(dealings []shamir.Commitment) [32]byte {
+	if len(dealings) == 0 {
+		return [32]byte{}
+	}
+	level := dealingLeaves(dealings)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// DealingProof returns the proof that the dealing at the given position is a
+// leaf of the tree of DealingRoot, which is the list of siblings from the leaf
+// to the root.
+//
+// Panics: This function will panic if the position is out of range.
+func DealingProof(dealings []shamir.Commitment, pos int) [][32]byte {
+	_ = dealings[pos]
+	var proof [][32]byte
+	level := dealingLeaves(dealings)
+	for len(level) > 1 {
+		if sibling := pos ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		level = nextLevel(level)
+		pos /= 2
+	}
+	return proof
+}
+
+// VerifyDealingProof returns true if the proof shows that the commitment is a
+// leaf of the tree with the given root, and false otherwise.
+func VerifyDealingProof(root [32]byte, c shamir.Commitment, proof [][32]byte) bool {
+	node := dealingLeaf(c)
+	for i := range proof {
+		node = hashPair(&node, &proof[i])
+	}
+	return node == root
+}
+
+func verifySharesPrefix(h secp256k1.Point, c shamir.Commitment) []byte {
+	// The head is h inline and the offsets of the two arrays, followed by the
+	// commitment; the length and elements of the shares are appended later.
+	const head = 4 * 32
+	prefix := make([]byte, 4, 4+head+32+EVMPointSize*c.Len())
+	sel := Selector(VerifySharesSignature)
+	copy(prefix, sel[:])
+	prefix = append(prefix, PointEVM(&h)...)
+	prefix = appendWord(prefix, head)
+	prefix = appendWord(prefix, uint64(head+32+EVMPointSize*c.Len()))
+	prefix = appendWord(prefix, uint64(c.Len()))
+	return append(prefix, CommitmentEVM(c)...)
+}
+
+func verifyStoredSharesPrefix(hash [32]byte, h secp256k1.Point) []byte {
+	const head = 4 * 32
+	prefix := make([]byte, 4, 4+head)
+	sel := Selector(VerifyStoredSharesSignature)
+	copy(prefix, sel[:])
+	prefix = append(prefix, hash[:]...)
+	prefix = append(prefix, PointEVM(&h)...)
+	return appendWord(prefix, head)
+}
+
+// Appends the length and elements of the shares array to a copy of the
+// prefix.
+func appendShares(prefix []byte, vshares shamir.VerifiableShares) []byte {
+	call := make([]byte, len(prefix), len(prefix)+32+EVMVShareSize*len(vshares))
+	copy(call, prefix)
+	call = appendWord(call, uint64(len(vshares)))
+	for i := range vshares {
+		call = append(call, VShareEVM(&vshares[i])...)
+	}
+	return call
+}
+
+func batchShares(prefix []byte, k int, vshares shamir.VerifiableShares, gasLimit uint64, sched GasSchedule) ([][]byte, error) {
+	base := sched.Tx + sched.CalldataGas(prefix)
+	var calls [][]byte
+	for start := 0; start < len(vshares); {
+		gas := base
+		end := start
+		for end < len(vshares) {
+			next := gas + sched.CalldataGas(VShareEVM(&vshares[end])) + uint64(k)*sched.Term
+			if next+wordGas(uint64(end-start+1), sched) > gasLimit {
+				break
+			}
+			gas = next
+			end++
+		}
+		if end == start {
+			return nil, fmt.Errorf("%w: can not check share %v within %v gas", ErrGasLimit, start, gasLimit)
+		}
+		calls = append(calls, appendShares(prefix, vshares[start:end]))
+		start = end
+	}
+	return calls, nil
+}
+
+func dealingLeaves(dealings []shamir.Commitment) [][32]byte {
+	leaves := make([][32]byte, len(dealings))
+	for i := range dealings {
+		leaves[i] = dealingLeaf(dealings[i])
+	}
+	return leaves
+}
+
+func dealingLeaf(c shamir.Commitment) [32]byte {
+	var leaf [32]byte
+	hash := CommitmentHash(c)
+	copy(leaf[:], keccak256(hash[:]))
+	return leaf
+}
+
+func nextLevel(level [][32]byte) [][32]byte {
+	next := make([][32]byte, 0, (len(level)+1)/2)
+	for i := 0; i+1 < len(level); i += 2 {
+		next = append(next, hashPair(&level[i], &level[i+1]))
+	}
+	if len(level)%2 == 1 {
+		next = append(next, level[len(level)-1])
+	}
+	return next
+}
+
+// Hashes the two nodes in increasing order, as in OpenZeppelin.
+func hashPair(a, b *[32]byte) [32]byte {
+	var node [32]byte
+	if string(a[:]) > string(b[:]) {
+		a, b = b, a
+	}
+	copy(node[:], keccak256(a[:], b[:]))
+	return node
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func appendWord(dst []byte, x uint64) []byte {
+	var word [32]byte
+	putWord(word[:], x)
+	return append(dst, word[:]...)
+}
+
+// Returns the calldata gas of x as a 32 byte word.
+func wordGas(x uint64, sched GasSchedule) uint64 {
+	var word [32]byte
+	putWord(word[:], x)
+	return sched.CalldataGas(word[:])
+}
@@ -0,0 +1,165 @@
+package interop_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/interop"
+)
+
+var _ = Describe("EVM calldata", func() {
+	// Reads the 32 byte word at the given offset of the arguments.
+	word := func(call []byte, offset int) uint64 {
+		args := call[4:]
+		Expect(args[offset : offset+24]).To(Equal(make([]byte, 24)))
+		return binary.BigEndian.Uint64(args[offset+24 : offset+32])
+	}
+
+	randomVShares := func(n int) shamir.VerifiableShares {
+		vshares := make(shamir.VerifiableShares, n)
+		for i := range vshares {
+			vshares[i] = shamir.NewVerifiableShare(
+				shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+				secp256k1.RandomFn(),
+			)
+		}
+		return vshares
+	}
+
+	Context("selectors", func() {
+		It("should match the published selector of the ERC-20 transfer function", func() {
+			sel := Selector("transfer(address,uint256)")
+			Expect(hex.EncodeToString(sel[:])).To(Equal("a9059cbb"))
+		})
+	})
+
+	Context("checking shares", func() {
+		It("should ABI encode the point, commitment and shares", func() {
+			h := secp256k1.RandomPoint()
+			c := shamirutil.RandomCommitment(3)
+			vshares := randomVShares(2)
+			call := VerifySharesCalldata(h, c, vshares)
+
+			sel := Selector(VerifySharesSignature)
+			Expect(call[:4]).To(Equal(sel[:]))
+			Expect(call[4:68]).To(Equal(PointEVM(&h)))
+			cOffset, sOffset := int(word(call, 64)), int(word(call, 96))
+			Expect(word(call, cOffset)).To(Equal(uint64(3)))
+			Expect(call[4+cOffset+32 : 4+sOffset]).To(Equal(CommitmentEVM(c)))
+			Expect(word(call, sOffset)).To(Equal(uint64(2)))
+			Expect(call[4+sOffset+32:]).To(Equal(append(VShareEVM(&vshares[0]), VShareEVM(&vshares[1])...)))
+		})
+
+		It("should split the shares into calls within the gas limit", func() {
+			h := secp256k1.RandomPoint()
+			k := 10
+			c := shamirutil.RandomCommitment(k)
+			vshares := randomVShares(50)
+			sched := DefaultGasSchedule
+			gasLimit := uint64(500000)
+
+			calls, err := BatchVerifySharesCalldata(h, c, vshares, gasLimit, sched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(calls)).To(BeNumerically(">", 1))
+
+			start := 0
+			for _, call := range calls {
+				sOffset := int(word(call, 96))
+				n := int(word(call, sOffset))
+				Expect(call).To(Equal(VerifySharesCalldata(h, c, vshares[start:start+n])))
+				gas := sched.Tx + sched.CalldataGas(call) + uint64(n*k)*sched.Term
+				Expect(gas).To(BeNumerically("<=", gasLimit))
+				start += n
+			}
+			Expect(start).To(Equal(len(vshares)))
+		})
+
+		It("should return an error if a single share does not fit", func() {
+			h := secp256k1.RandomPoint()
+			c := shamirutil.RandomCommitment(100)
+			_, err := BatchVerifySharesCalldata(h, c, randomVShares(1), 300000, DefaultGasSchedule)
+			Expect(errors.Is(err, ErrGasLimit)).To(BeTrue())
+		})
+	})
+
+	Context("stored commitments", func() {
+		It("should store the commitment in chunks within the gas limit", func() {
+			c := shamirutil.RandomCommitment(20)
+			hash := CommitmentHash(c)
+			sched := DefaultGasSchedule
+			gasLimit := uint64(300000)
+
+			calls, err := StoreCommitmentCalldata(c, gasLimit, sched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(calls)).To(BeNumerically(">", 1))
+
+			sel := Selector(StoreCommitmentSignature)
+			var points []byte
+			for _, call := range calls {
+				Expect(call[:4]).To(Equal(sel[:]))
+				Expect(call[4:36]).To(Equal(hash[:]))
+				Expect(word(call, 32)).To(Equal(uint64(len(points) / EVMPointSize)))
+				offset := int(word(call, 64))
+				n := int(word(call, offset))
+				chunk := call[4+offset+32:]
+				Expect(len(chunk)).To(Equal(n * EVMPointSize))
+				points = append(points, chunk...)
+
+				gas := sched.Tx + sched.CalldataGas(call) + uint64(2*n)*sched.StoreWord
+				Expect(gas).To(BeNumerically("<=", gasLimit))
+			}
+			Expect(points).To(Equal(CommitmentEVM(c)))
+
+			_, err = StoreCommitmentCalldata(c, 50000, sched)
+			Expect(errors.Is(err, ErrGasLimit)).To(BeTrue())
+		})
+
+		It("should check shares against the stored commitment", func() {
+			c := shamirutil.RandomCommitment(5)
+			hash := CommitmentHash(c)
+			h := secp256k1.RandomPoint()
+			vshares := randomVShares(30)
+
+			calls, err := BatchVerifyStoredSharesCalldata(hash, h, c.Len(), vshares, 200000, DefaultGasSchedule)
+			Expect(err).ToNot(HaveOccurred())
+			start := 0
+			for _, call := range calls {
+				Expect(call[4:36]).To(Equal(hash[:]))
+				Expect(call[36:100]).To(Equal(PointEVM(&h)))
+				n := int(word(call, int(word(call, 96))))
+				Expect(call).To(Equal(VerifyStoredSharesCalldata(hash, h, vshares[start:start+n])))
+				start += n
+			}
+			Expect(start).To(Equal(len(vshares)))
+		})
+	})
+
+	Context("dealing roots", func() {
+		It("should be zero for no dealings", func() {
+			Expect(DealingRoot(nil)).To(Equal([32]byte{}))
+		})
+
+		It("should give proofs for every dealing", func() {
+			for n := 1; n <= 9; n++ {
+				dealings := make([]shamir.Commitment, n)
+				for i := range dealings {
+					dealings[i] = shamirutil.RandomCommitment(1 + i%3)
+				}
+				root := DealingRoot(dealings)
+				for i := range dealings {
+					proof := DealingProof(dealings, i)
+					Expect(VerifyDealingProof(root, dealings[i], proof)).To(BeTrue())
+					other := shamirutil.RandomCommitment(2)
+					Expect(VerifyDealingProof(root, other, proof)).To(BeFalse())
+				}
+			}
+		})
+	})
+})
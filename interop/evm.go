@@ -0,0 +1,181 @@
+package interop
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// Sizes in bytes of the EVM encodings. Every word of these encodings is a 32
+// byte big endian integer, as for the uint256 type of the EVM.
+const (
+	// EVMPointSize is the size of a point, which is its x coordinate followed
+	// by its y coordinate.
+	EVMPointSize = 64
+	// EVMShareSize is the size of a share, which is its index followed by its
+	// value.
+	EVMShareSize = 64
+	// EVMVShareSize is the size of a verifiable share, which is its index,
+	// value and decommitment.
+	EVMVShareSize = 96
+)
+
+// PutPointEVM writes the 64 byte uncompressed encoding of the point to dst,
+// which is the big endian x coordinate followed by the big endian y
+// coordinate, as used by the ecrecover precompile and most EVM elliptic curve
+// libraries. The point at infinity is encoded as 64 zero bytes; this is not
+// ambiguous, since (0, 0) is not on the curve.
+//
+// Panics: This function will panic if dst is shorter than 64 bytes.
+func PutPointEVM(dst []byte, p *secp256k1.Point) {
+	_ = dst[EVMPointSize-1]
+	x, y, err := p.XY()
+	if err != nil {
+		// The only error is for the point at infinity.
+		for i := range dst[:EVMPointSize] {
+			dst[i] = 0
+		}
+		return
+	}
+	x.PutB32(dst[:32])
+	y.PutB32(dst[32:EVMPointSize])
+}
+
+// PointEVM returns the 64 byte uncompressed encoding of the point, as
+// described for PutPointEVM.
+func PointEVM(p *secp256k1.Point) []byte {
+	bs := make([]byte, EVMPointSize)
+	PutPointEVM(bs, p)
+	return bs
+}
+
+// ParsePointEVM parses a point in the 64 byte uncompressed encoding. An error
+// is returned if either coordinate is not less than the order of the base
+// field, or if the point is not on the curve.
+func ParsePointEVM(bs []byte) (secp256k1.Point, error) {
+	if len(bs) != EVMPointSize {
+		return secp256k1.Point{}, fmt.Errorf("expected %v bytes for a point, got %v", EVMPointSize, len(bs))
+	}
+	if isZero(bs) {
+		return secp256k1.NewPointInfinity(), nil
+	}
+	var x, y secp256k1.Fp
+	if x.SetB32(bs[:32]) || y.SetB32(bs[32:]) {
+		return secp256k1.Point{}, errors.New("coordinate is not less than the order of the base field")
+	}
+	return secp256k1.NewPointFromXY(&x, &y)
+}
+
+// CommitmentEVM returns the points of the commitment in the 64 byte
+// uncompressed encoding, one after the other. This is the same as
+// abi.encodePacked of the commitment as a uint256[2][], and is the most
+// compact encoding for a contract that reads the commitment from calldata
+// with its own offsets.
+func CommitmentEVM(c shamir.Commitment) []byte {
+	bs := make([]byte, EVMPointSize*c.Len())
+	for i := range c {
+		PutPointEVM(bs[EVMPointSize*i:], &c[i])
+	}
+	return bs
+}
+
+// ParseCommitmentEVM parses a commitment encoded by CommitmentEVM.
+func ParseCommitmentEVM(bs []byte) (shamir.Commitment, error) {
+	if len(bs)%EVMPointSize != 0 {
+		return nil, fmt.Errorf("expected a multiple of %v bytes for a commitment, got %v", EVMPointSize, len(bs))
+	}
+	c := shamir.NewCommitmentWithCapacity(len(bs) / EVMPointSize)
+	for i := 0; i < len(bs); i += EVMPointSize {
+		p, err := ParsePointEVM(bs[i : i+EVMPointSize])
+		if err != nil {
+			return nil, fmt.Errorf("point %v: %v", i/EVMPointSize, err)
+		}
+		c.Append(p)
+	}
+	return c, nil
+}
+
+// CommitmentABI returns the commitment in the encoding of abi.encode for a
+// single uint256[2][] argument, which is the 32 byte offset of the array (that
+// is, 32), its length, and then the points as in CommitmentEVM. This is the
+// encoding that a contract receives when the commitment is its only dynamic
+// argument, and that abi.decode expects.
+func CommitmentABI(c shamir.Commitment) []byte {
+	bs := make([]byte, 64+EVMPointSize*c.Len())
+	putWord(bs[:32], 32)
+	putWord(bs[32:64], uint64(c.Len()))
+	copy(bs[64:], CommitmentEVM(c))
+	return bs
+}
+
+// ShareEVM returns the 64 byte encoding of the share, which is the big endian
+// index followed by the big endian value.
+func ShareEVM(s *shamir.Share) []byte {
+	bs := make([]byte, EVMShareSize)
+	PutScalar(bs[:32], &s.Index, BigEndian)
+	PutScalar(bs[32:], &s.Value, BigEndian)
+	return bs
+}
+
+// ParseShareEVM parses a share encoded by ShareEVM.
+func ParseShareEVM(bs []byte) (shamir.Share, error) {
+	if len(bs) != EVMShareSize {
+		return shamir.Share{}, fmt.Errorf("expected %v bytes for a share, got %v", EVMShareSize, len(bs))
+	}
+	index, err := ParseScalar(bs[:32], BigEndian)
+	if err != nil {
+		return shamir.Share{}, fmt.Errorf("index: %v", err)
+	}
+	value, err := ParseScalar(bs[32:], BigEndian)
+	if err != nil {
+		return shamir.Share{}, fmt.Errorf("value: %v", err)
+	}
+	return shamir.NewShare(index, value), nil
+}
+
+// VShareEVM returns the 96 byte encoding of the verifiable share, which is
+// the encoding of the share as for ShareEVM followed by the big endian
+// decommitment. A contract can check it against a commitment encoded by
+// CommitmentEVM given the encoding of the Pedersen parameter h.
+func VShareEVM(vs *shamir.VerifiableShare) []byte {
+	bs := make([]byte, EVMVShareSize)
+	copy(bs, ShareEVM(&vs.Share))
+	PutScalar(bs[EVMShareSize:], &vs.Decommitment, BigEndian)
+	return bs
+}
+
+// ParseVShareEVM parses a verifiable share encoded by VShareEVM.
+func ParseVShareEVM(bs []byte) (shamir.VerifiableShare, error) {
+	if len(bs) != EVMVShareSize {
+		return shamir.VerifiableShare{}, fmt.Errorf("expected %v bytes for a verifiable share, got %v", EVMVShareSize, len(bs))
+	}
+	share, err := ParseShareEVM(bs[:EVMShareSize])
+	if err != nil {
+		return shamir.VerifiableShare{}, err
+	}
+	decommitment, err := ParseScalar(bs[EVMShareSize:], BigEndian)
+	if err != nil {
+		return shamir.VerifiableShare{}, fmt.Errorf("decommitment: %v", err)
+	}
+	return shamir.NewVerifiableShare(share, decommitment), nil
+}
+
+// Writes x as a 32 byte big endian word.
+func putWord(dst []byte, x uint64) {
+	for i := range dst[:24] {
+		dst[i] = 0
+	}
+	binary.BigEndian.PutUint64(dst[24:32], x)
+}
+
+func isZero(bs []byte) bool {
+	for _, b := range bs {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package interop_test
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/interop"
+)
+
+var _ = Describe("EVM encodings", func() {
+	trials := 50
+
+	// Coordinates of the generator, as given in the secp256k1 specification.
+	const (
+		gx = "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+		gy = "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	)
+
+	Context("points", func() {
+		It("should match the published coordinates of the generator", func() {
+			one := secp256k1.NewFnFromU16(1)
+			var g secp256k1.Point
+			g.BaseExp(&one)
+			Expect(hex.EncodeToString(PointEVM(&g))).To(Equal(gx + gy))
+		})
+
+		It("should parse what was encoded", func() {
+			for i := 0; i < trials; i++ {
+				p := secp256k1.RandomPoint()
+				q, err := ParsePointEVM(PointEVM(&p))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(q.Eq(&p)).To(BeTrue())
+			}
+			inf := secp256k1.NewPointInfinity()
+			Expect(PointEVM(&inf)).To(Equal(make([]byte, EVMPointSize)))
+			p, err := ParsePointEVM(PointEVM(&inf))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p.IsInfinity()).To(BeTrue())
+		})
+
+		It("should return an error for invalid encodings", func() {
+			p := secp256k1.RandomPoint()
+			bs := PointEVM(&p)
+			bs[63] ^= 1
+			_, err := ParsePointEVM(bs)
+			Expect(err).To(HaveOccurred())
+
+			bs = PointEVM(&p)
+			copy(bs[:32], bytes.Repeat([]byte{0xff}, 32))
+			_, err = ParsePointEVM(bs)
+			Expect(err).To(HaveOccurred())
+
+			_, err = ParsePointEVM(PointEVM(&p)[:63])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("commitments", func() {
+		It("should encode the points one after the other", func() {
+			for i := 0; i < trials; i++ {
+				c := shamirutil.RandomCommitment(5)
+				bs := CommitmentEVM(c)
+				Expect(len(bs)).To(Equal(5 * EVMPointSize))
+				for j := range c {
+					Expect(bs[j*EVMPointSize : (j+1)*EVMPointSize]).To(Equal(PointEVM(&c[j])))
+				}
+
+				parsed, err := ParseCommitmentEVM(bs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed.Eq(c)).To(BeTrue())
+			}
+		})
+
+		It("should prefix the ABI encoding with the offset and length", func() {
+			c := shamirutil.RandomCommitment(3)
+			bs := CommitmentABI(c)
+			Expect(len(bs)).To(Equal(64 + 3*EVMPointSize))
+			Expect(bs[:31]).To(Equal(make([]byte, 31)))
+			Expect(bs[31]).To(Equal(byte(0x20)))
+			Expect(bs[32:63]).To(Equal(make([]byte, 31)))
+			Expect(bs[63]).To(Equal(byte(3)))
+			Expect(bs[64:]).To(Equal(CommitmentEVM(c)))
+		})
+
+		It("should return an error for invalid encodings", func() {
+			c := shamirutil.RandomCommitment(2)
+			_, err := ParseCommitmentEVM(CommitmentEVM(c)[1:])
+			Expect(err).To(HaveOccurred())
+			bs := CommitmentEVM(c)
+			bs[len(bs)-1] ^= 1
+			_, err = ParseCommitmentEVM(bs)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("shares", func() {
+		It("should encode the scalars as big endian words", func() {
+			s := shamir.NewShare(secp256k1.NewFnFromU16(1), secp256k1.NewFnFromU16(0x1234))
+			bs := ShareEVM(&s)
+			Expect(len(bs)).To(Equal(EVMShareSize))
+			Expect(bs[31]).To(Equal(byte(1)))
+			Expect(bs[62:]).To(Equal([]byte{0x12, 0x34}))
+		})
+
+		It("should parse what was encoded", func() {
+			for i := 0; i < trials; i++ {
+				vs := shamir.NewVerifiableShare(
+					shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+					secp256k1.RandomFn(),
+				)
+				bs := VShareEVM(&vs)
+				Expect(bs[:EVMShareSize]).To(Equal(ShareEVM(&vs.Share)))
+
+				parsed, err := ParseVShareEVM(bs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed.Eq(&vs)).To(BeTrue())
+				share, err := ParseShareEVM(bs[:EVMShareSize])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(share.Eq(&vs.Share)).To(BeTrue())
+			}
+		})
+
+		It("should return an error for invalid encodings", func() {
+			bs := bytes.Repeat([]byte{0xff}, EVMVShareSize)
+			_, err := ParseVShareEVM(bs)
+			Expect(err).To(HaveOccurred())
+			_, err = ParseShareEVM(bs[:EVMShareSize])
+			Expect(err).To(HaveOccurred())
+			_, err = ParseVShareEVM(bs[:EVMShareSize])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
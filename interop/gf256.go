@@ -0,0 +1,49 @@
+package interop
+
+// Arithmetic in GF(2^8) with the reduction polynomial x^8 + x^4 + x^3 + x + 1
+// (0x11b), which is the field used by the AES S-box and by HashiCorp Vault's
+// Shamir implementation. Multiplication is branch free so that the running
+// time does not depend on the secret bytes.
+
+func gfAdd(a, b byte) byte {
+	return a ^ b
+}
+
+func gfMul(a, b byte) byte {
+	var res byte
+	for i := 0; i < 8; i++ {
+		// mask is 0xff if the lowest bit of b is set, and 0x00 otherwise.
+		mask := -(b & 1)
+		res ^= a & mask
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return res
+}
+
+// Computes the inverse of a as a^254. The inverse of 0 is defined to be 0.
+func gfInv(a byte) byte {
+	res := byte(1)
+	for e := 254; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = gfMul(res, a)
+		}
+		a = gfMul(a, a)
+	}
+	return res
+}
+
+func gfDiv(a, b byte) byte {
+	return gfMul(a, gfInv(b))
+}
+
+// Evaluates the polynomial with the given coefficients at x, where the
+// coefficient at index 0 is the constant term.
+func gfEval(coeffs []byte, x byte) byte {
+	res := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		res = gfAdd(gfMul(res, x), coeffs[i])
+	}
+	return res
+}
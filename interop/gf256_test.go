@@ -0,0 +1,19 @@
+package interop
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GF(256) arithmetic", func() {
+	It("should match the multiplication examples from FIPS-197", func() {
+		Expect(gfMul(0x57, 0x83)).To(Equal(byte(0xc1)))
+		Expect(gfMul(0x57, 0x13)).To(Equal(byte(0xfe)))
+	})
+
+	It("should compute multiplicative inverses", func() {
+		for a := 1; a < 256; a++ {
+			Expect(gfMul(byte(a), gfInv(byte(a)))).To(Equal(byte(1)))
+		}
+	})
+})
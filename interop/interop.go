@@ -0,0 +1,259 @@
+// Package interop implements byte oriented Shamir secret sharing over GF(256),
+// and the share formats used by existing tooling that is based on it, so that
+// secrets can be migrated between those tools and this package.
+//
+// The only GF(256) format that is supported is the byte-wise format of
+// HashiCorp Vault, and of the libraries that are compatible with it: each
+// share is its bytes followed by a single byte x coordinate. The shares of
+// ssss (the Linux secret sharing tool) are not supported and can not be
+// converted, since ssss shares the whole secret as a single element of
+// GF(2^m) rather than byte by byte, so its shares are neither parsed nor
+// produced by this package.
+//
+// In a GF(256) sharing, each byte of the secret is shared independently using
+// a polynomial over GF(256), with all bytes of a share being evaluated at the
+// same x coordinate. Such shares can not be converted one by one into shares
+// over the secp256k1 scalar field, since the two fields are unrelated.
+// Migration is instead done by reconstructing the secret from a qualified set
+// of shares and immediately sharing it again in the other field. This means
+// that the secret is briefly held in memory by the party running the
+// migration.
+//
+// The package also converts shares and commitments to and from the encodings
+// of Coinbase kryptology, whose sharings over secp256k1 are in the same field
+// and with the same commitments as this package. These can be converted one
+// by one, so parties using either implementation can take part in the same
+// sharing, as long as the shares are indexed by small integers.
+//
+// Finally, shares and commitments can be encoded for smart contracts on the
+// EVM, with points in the 64 byte uncompressed encoding and scalars as 32 byte
+// big endian words, so that dealings can be checked on chain. The calldata of
+// the calls that check shares against a commitment is built from these
+// encodings, split across transactions to stay within a gas limit, and
+// dealings can be anchored on chain by the root of a Merkle tree of their
+// commitments.
+package interop
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// GF256Share is a share of a byte string in a GF(256) sharing. All bytes of Y
+// are evaluations at the same non-zero x coordinate X.
+type GF256Share struct {
+	X byte
+	Y []byte
+}
+
+// SplitGF256 shares the given secret into n shares over GF(256), any k of
+// which can reconstruct the secret. The x coordinates are distinct, non-zero
+// and chosen at random, matching the behaviour of HashiCorp Vault. The
+// coefficients and x coordinates are read from shamir.RandomSource, and an
+// error is returned if reading from it fails.
+func SplitGF256(secret []byte, n, k int) ([]GF256Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("cannot split an empty secret")
+	}
+	if n < 1 || n > 255 {
+		return nil, fmt.Errorf("invalid number of shares: expected 1 <= n <= 255, got n = %v", n)
+	}
+	if k < 1 || k > n {
+		return nil, fmt.Errorf("%w: expected 1 <= k <= %v, got k = %v", shamir.ErrInvalidThreshold, n, k)
+	}
+
+	xs, err := randomXCoordinates(shamir.RandomSource(), n)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]GF256Share, n)
+	for i := range shares {
+		shares[i] = GF256Share{X: xs[i], Y: make([]byte, len(secret))}
+	}
+
+	r := shamir.RandomSource()
+	coeffs := make([]byte, k)
+	defer wipeBytes(coeffs)
+	for j, b := range secret {
+		coeffs[0] = b
+		if _, err := io.ReadFull(r, coeffs[1:]); err != nil {
+			return nil, fmt.Errorf("could not generate random bytes: %w", err)
+		}
+		for i := range shares {
+			shares[i].Y[j] = gfEval(coeffs, shares[i].X)
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineGF256 reconstructs the secret from the given GF(256) shares. The
+// shares must have distinct non-zero x coordinates and equal lengths. As with
+// shamir.Open, the result will only be the original secret if there are at
+// least k unmodified shares.
+func CombineGF256(shares []GF256Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares given")
+	}
+	l := len(shares[0].Y)
+	for i := range shares {
+		if shares[i].X == 0 {
+			return nil, fmt.Errorf("share %v has x coordinate zero", i)
+		}
+		if len(shares[i].Y) != l {
+			return nil, fmt.Errorf("share %v has length %v, expected %v", i, len(shares[i].Y), l)
+		}
+		for j := 0; j < i; j++ {
+			if shares[i].X == shares[j].X {
+				return nil, fmt.Errorf("shares %v and %v have the same x coordinate %v", j, i, shares[i].X)
+			}
+		}
+	}
+
+	// Lagrange coefficients for evaluation at zero. Since addition and
+	// subtraction are the same operation in characteristic 2, the basis
+	// polynomial for x_i at zero is the product of x_j / (x_i + x_j).
+	lagrange := make([]byte, len(shares))
+	for i := range shares {
+		lagrange[i] = 1
+		for j := range shares {
+			if i == j {
+				continue
+			}
+			lagrange[i] = gfMul(lagrange[i], gfDiv(shares[j].X, gfAdd(shares[i].X, shares[j].X)))
+		}
+	}
+
+	secret := make([]byte, l)
+	for b := range secret {
+		for i := range shares {
+			secret[b] = gfAdd(secret[b], gfMul(lagrange[i], shares[i].Y[b]))
+		}
+	}
+	return secret, nil
+}
+
+// VaultBytes returns the share in the binary format used by HashiCorp Vault
+// unseal keys, which is the y values followed by a single byte for the x
+// coordinate.
+func (s GF256Share) VaultBytes() []byte {
+	return append(append(make([]byte, 0, len(s.Y)+1), s.Y...), s.X)
+}
+
+// ParseVaultShare parses a share in the binary format used by HashiCorp
+// Vault. Vault displays unseal keys encoded in base64 or hex; use
+// ParseVaultKey to parse those directly.
+func ParseVaultShare(bs []byte) (GF256Share, error) {
+	if len(bs) < 2 {
+		return GF256Share{}, fmt.Errorf("share too short: expected at least 2 bytes, got %v", len(bs))
+	}
+	x := bs[len(bs)-1]
+	if x == 0 {
+		return GF256Share{}, errors.New("share has x coordinate zero")
+	}
+	return GF256Share{X: x, Y: append([]byte{}, bs[:len(bs)-1]...)}, nil
+}
+
+// ParseVaultKey parses a Vault unseal key as displayed by the Vault CLI, in
+// either base64 or hex encoding.
+func ParseVaultKey(key string) (GF256Share, error) {
+	key = strings.TrimSpace(key)
+	if bs, err := hex.DecodeString(key); err == nil {
+		return ParseVaultShare(bs)
+	}
+	bs, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return GF256Share{}, errors.New("invalid vault key: expected base64 or hex encoding")
+	}
+	return ParseVaultShare(bs)
+}
+
+// VaultKey returns the share encoded in base64, as displayed by the Vault CLI.
+func (s GF256Share) VaultKey() string {
+	return base64.StdEncoding.EncodeToString(s.VaultBytes())
+}
+
+// MigrateFromGF256 reconstructs the secret from the given GF(256) shares and
+// shares it again over the secp256k1 scalar field, storing the result in dst.
+// The secret is interpreted as a big endian integer, and so must be at most
+// 32 bytes long and less than the order of the field.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.ShareSecret.
+func MigrateFromGF256(dst *shamir.Shares, shares []GF256Share, indices []secp256k1.Fn, k int) error {
+	secret, err := CombineGF256(shares)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(secret)
+
+	if len(secret) > secp256k1.FnSizeMarshalled {
+		return fmt.Errorf("secret too large: expected at most %v bytes, got %v", secp256k1.FnSizeMarshalled, len(secret))
+	}
+	var padded [32]byte
+	defer wipeBytes(padded[:])
+	copy(padded[32-len(secret):], secret)
+
+	var s secp256k1.Fn
+	defer s.Clear()
+	if s.SetB32(padded[:]) {
+		return errors.New("secret is not less than the order of the secp256k1 scalar field")
+	}
+	return shamir.ShareSecret(dst, indices, s, k)
+}
+
+// MigrateToGF256 reconstructs the secret from the given shares and shares it
+// again over GF(256) as n shares with threshold k. The secret is encoded as a
+// big endian integer of secretLen bytes; an error is returned if it does not
+// fit.
+func MigrateToGF256(shares shamir.Shares, secretLen, n, k int) ([]GF256Share, error) {
+	if secretLen < 1 || secretLen > secp256k1.FnSizeMarshalled {
+		return nil, fmt.Errorf("invalid secret length: expected 1 <= l <= %v, got %v", secp256k1.FnSizeMarshalled, secretLen)
+	}
+
+	s := shamir.Open(shares)
+	defer s.Clear()
+	var bs [32]byte
+	defer wipeBytes(bs[:])
+	s.PutB32(bs[:])
+
+	for _, b := range bs[:32-secretLen] {
+		if b != 0 {
+			return nil, fmt.Errorf("secret does not fit into %v bytes", secretLen)
+		}
+	}
+	return SplitGF256(bs[32-secretLen:], n, k)
+}
+
+// Returns n distinct random non-zero bytes using bytes from the given source.
+func randomXCoordinates(src io.Reader, n int) ([]byte, error) {
+	var perm [255]byte
+	for i := range perm {
+		perm[i] = byte(i + 1)
+	}
+	var r [2]byte
+	for i := len(perm) - 1; i > 0; i-- {
+		if _, err := io.ReadFull(src, r[:]); err != nil {
+			return nil, fmt.Errorf("could not generate random bytes: %w", err)
+		}
+		// The modulo bias is negligible for the purpose of choosing x
+		// coordinates, which are public.
+		j := int(uint16(r[0])<<8|uint16(r[1])) % (i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return append([]byte{}, perm[:n]...), nil
+}
+
+func wipeBytes(bs []byte) {
+	for i := range bs {
+		bs[i] = 0
+	}
+}
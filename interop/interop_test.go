@@ -0,0 +1,161 @@
+package interop_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/interop"
+)
+
+var _ = Describe("GF(256) interoperability", func() {
+	trials := 50
+
+	randomSecret := func(l int) []byte {
+		secret := make([]byte, l)
+		rand.Read(secret)
+		return secret
+	}
+
+	Context("sharing over GF(256)", func() {
+		It("should reconstruct the secret from any k shares", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, 20)
+				k := shamirutil.RandRange(1, n)
+				secret := randomSecret(shamirutil.RandRange(1, 64))
+
+				shares, err := SplitGF256(secret, n, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(shares).To(HaveLen(n))
+
+				rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+				recon, err := CombineGF256(shares[:shamirutil.RandRange(k, n)])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(recon).To(Equal(secret))
+			}
+		})
+
+		It("should use distinct non-zero x coordinates", func() {
+			shares, err := SplitGF256(randomSecret(16), 255, 3)
+			Expect(err).ToNot(HaveOccurred())
+			seen := map[byte]bool{}
+			for _, share := range shares {
+				Expect(share.X).ToNot(BeZero())
+				Expect(seen[share.X]).To(BeFalse())
+				seen[share.X] = true
+			}
+		})
+
+		It("should return errors for invalid parameters", func() {
+			_, err := SplitGF256(nil, 3, 2)
+			Expect(err).To(HaveOccurred())
+			_, err = SplitGF256(randomSecret(4), 256, 2)
+			Expect(err).To(HaveOccurred())
+			_, err = SplitGF256(randomSecret(4), 3, 4)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should read its randomness from the shamir source", func() {
+			secret := randomSecret(16)
+			seed := make([]byte, 1024)
+			rand.Read(seed)
+			defer shamir.SetRandomSource(nil)
+
+			shamir.SetRandomSource(bytes.NewReader(seed))
+			shares1, err := SplitGF256(secret, 5, 3)
+			Expect(err).ToNot(HaveOccurred())
+			shamir.SetRandomSource(bytes.NewReader(seed))
+			shares2, err := SplitGF256(secret, 5, 3)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shares2).To(Equal(shares1))
+
+			// Choosing the x coordinates reads 508 bytes, so the source
+			// fails either while choosing them or while sampling the
+			// coefficients.
+			for _, l := range []int{100, 520} {
+				shamir.SetRandomSource(bytes.NewReader(seed[:l]))
+				_, err = SplitGF256(secret, 5, 3)
+				Expect(errors.Is(err, io.EOF)).To(BeTrue())
+			}
+		})
+
+		It("should reject duplicate x coordinates and mismatched lengths", func() {
+			shares, err := SplitGF256(randomSecret(8), 3, 2)
+			Expect(err).ToNot(HaveOccurred())
+
+			dup := []GF256Share{shares[0], shares[0]}
+			_, err = CombineGF256(dup)
+			Expect(err).To(HaveOccurred())
+
+			short := []GF256Share{shares[0], {X: shares[1].X, Y: shares[1].Y[:4]}}
+			_, err = CombineGF256(short)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("share formats", func() {
+		It("should round trip the Vault formats", func() {
+			shares, err := SplitGF256(randomSecret(32), 5, 3)
+			Expect(err).ToNot(HaveOccurred())
+			for _, share := range shares {
+				bs := share.VaultBytes()
+				Expect(bs).To(HaveLen(33))
+				Expect(bs[32]).To(Equal(share.X))
+
+				parsed, err := ParseVaultKey(share.VaultKey())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed).To(Equal(share))
+			}
+		})
+	})
+
+	Context("migration", func() {
+		It("should migrate a secret from GF(256) shares to field shares and back", func() {
+			n, k := 10, 4
+			indices := shamirutil.SequentialIndices(n)
+			for i := 0; i < trials; i++ {
+				secret := randomSecret(shamirutil.RandRange(1, 31))
+				gfShares, err := SplitGF256(secret, 5, 3)
+				Expect(err).ToNot(HaveOccurred())
+
+				shares := make(shamir.Shares, n)
+				Expect(MigrateFromGF256(&shares, gfShares[:3], indices, k)).To(Succeed())
+				Expect(shamirutil.SharesAreConsistent(shares, k)).To(BeTrue())
+
+				back, err := MigrateToGF256(shares[:k], len(secret), 5, 3)
+				Expect(err).ToNot(HaveOccurred())
+				recon, err := CombineGF256(back[:3])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bytes.Equal(recon, secret)).To(BeTrue())
+			}
+		})
+
+		It("should reject secrets that do not fit", func() {
+			indices := shamirutil.SequentialIndices(5)
+			shares := make(shamir.Shares, 5)
+
+			gfShares, err := SplitGF256(randomSecret(33), 3, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(MigrateFromGF256(&shares, gfShares, indices, 2)).ToNot(Succeed())
+
+			gfShares, err = SplitGF256(bytes.Repeat([]byte{0xff}, 32), 3, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(MigrateFromGF256(&shares, gfShares, indices, 2)).ToNot(Succeed())
+
+			secret := secp256k1.RandomFn()
+			for secret.Int().BitLen() <= 8 {
+				secret = secp256k1.RandomFn()
+			}
+			Expect(shamir.ShareSecret(&shares, indices, secret, 2)).To(Succeed())
+			_, err = MigrateToGF256(shares, 1, 3, 2)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
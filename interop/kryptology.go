@@ -0,0 +1,246 @@
+package interop
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// ByteOrder is the order of the bytes in the encoding of a scalar. This
+// package, like the SEC 1 standard, uses big endian; some implementations
+// encode the scalars of some curves in little endian.
+type ByteOrder int
+
+const (
+	// BigEndian puts the most significant byte first.
+	BigEndian ByteOrder = iota
+	// LittleEndian puts the least significant byte first.
+	LittleEndian
+)
+
+// SEC1PointSize is the size in bytes of a point that is not the point at
+// infinity in the SEC 1 compressed encoding.
+const SEC1PointSize = 33
+
+// PutScalar writes the 32 byte encoding of the scalar in the given byte order
+// to dst.
+//
+// Panics: This function will panic if dst is shorter than 32 bytes.
+func PutScalar(dst []byte, x *secp256k1.Fn, order ByteOrder) {
+	x.PutB32(dst[:32])
+	if order == LittleEndian {
+		reverseBytes(dst[:32])
+	}
+}
+
+// ParseScalar parses a 32 byte scalar in the given byte order. An error is
+// returned if the scalar is not less than the order of the secp256k1 group,
+// rather than reducing it, so that every scalar has a unique encoding.
+func ParseScalar(bs []byte, order ByteOrder) (secp256k1.Fn, error) {
+	var x secp256k1.Fn
+	if len(bs) != 32 {
+		return x, fmt.Errorf("expected 32 bytes for a scalar, got %v", len(bs))
+	}
+	var buf [32]byte
+	defer wipeBytes(buf[:])
+	copy(buf[:], bs)
+	if order == LittleEndian {
+		reverseBytes(buf[:])
+	}
+	if x.SetB32(buf[:]) {
+		return x, errors.New("scalar is not less than the order of the secp256k1 group")
+	}
+	return x, nil
+}
+
+// PointSEC1 returns the SEC 1 compressed encoding of the point, which is 2 or
+// 3 according to the parity of the y coordinate followed by the 32 byte big
+// endian x coordinate. The point at infinity is encoded as the single byte 0,
+// as in SEC 1. This differs from the encoding of the Marshal methods of the
+// secp256k1 package only in the first byte.
+func PointSEC1(p *secp256k1.Point) []byte {
+	if p.IsInfinity() {
+		return []byte{0}
+	}
+	bs := make([]byte, SEC1PointSize)
+	p.PutBytes(bs)
+	bs[0] += 2
+	return bs
+}
+
+// ParsePointSEC1 parses a point in the SEC 1 compressed encoding. Since some
+// implementations encode the point at infinity with a fixed size, 33 zero
+// bytes are accepted as well as the single byte 0.
+func ParsePointSEC1(bs []byte) (secp256k1.Point, error) {
+	if isInfinitySEC1(bs) {
+		return secp256k1.NewPointInfinity(), nil
+	}
+	var p secp256k1.Point
+	if len(bs) != SEC1PointSize {
+		return p, fmt.Errorf("expected %v bytes for a point, got %v", SEC1PointSize, len(bs))
+	}
+	if bs[0] != 2 && bs[0] != 3 {
+		return p, fmt.Errorf("invalid prefix %#x for a compressed point", bs[0])
+	}
+	var buf [SEC1PointSize]byte
+	copy(buf[:], bs)
+	buf[0] -= 2
+	if err := p.SetBytes(buf[:]); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// KryptologyShare is a share in the format used by the sharing package of
+// Coinbase kryptology, ShamirShare. Shares in kryptology are indexed by a small
+// non-zero integer Id, and the value is the encoding of a scalar, which is big
+// endian for secp256k1 (K256).
+type KryptologyShare struct {
+	Id    uint32
+	Value []byte
+}
+
+// Bytes returns the share in the binary format of kryptology, which is the 4
+// byte big endian Id followed by the value.
+func (s KryptologyShare) Bytes() []byte {
+	bs := make([]byte, 4, 4+len(s.Value))
+	binary.BigEndian.PutUint32(bs, s.Id)
+	return append(bs, s.Value...)
+}
+
+// ParseKryptologyShare parses a share in the binary format of kryptology.
+func ParseKryptologyShare(bs []byte) (KryptologyShare, error) {
+	if len(bs) < 4 {
+		return KryptologyShare{}, fmt.Errorf("share too short: expected at least 4 bytes, got %v", len(bs))
+	}
+	id := binary.BigEndian.Uint32(bs)
+	if id == 0 {
+		return KryptologyShare{}, errors.New("share has id zero")
+	}
+	return KryptologyShare{Id: id, Value: append([]byte{}, bs[4:]...)}, nil
+}
+
+// ToKryptologyShare converts the share to the format of kryptology. An error
+// is returned if the index of the share is not an integer in the range 1 to
+// 2^32 - 1, since kryptology can not represent other indices.
+func ToKryptologyShare(s *shamir.Share) (KryptologyShare, error) {
+	id, err := kryptologyID(&s.Index)
+	if err != nil {
+		return KryptologyShare{}, err
+	}
+	value := make([]byte, 32)
+	PutScalar(value, &s.Value, BigEndian)
+	return KryptologyShare{Id: id, Value: value}, nil
+}
+
+// FromKryptologyShare converts a share in the format of kryptology to a share
+// whose index is the Id of the share.
+func FromKryptologyShare(ks KryptologyShare) (shamir.Share, error) {
+	if ks.Id == 0 {
+		return shamir.Share{}, errors.New("share has id zero")
+	}
+	value, err := ParseScalar(ks.Value, BigEndian)
+	if err != nil {
+		return shamir.Share{}, err
+	}
+	var bs [32]byte
+	binary.BigEndian.PutUint32(bs[28:], ks.Id)
+	var index secp256k1.Fn
+	index.SetB32(bs[:])
+	return shamir.NewShare(index, value), nil
+}
+
+// ToKryptologyPedersen converts the verifiable share to the pair of secret and
+// blinding shares that kryptology uses for Pedersen verifiable sharing. The
+// commitments of the two schemes are the same, c_i G + d_i H for the i-th
+// coefficients c_i and d_i of the sharing and blinding polynomials, so the
+// shares can be checked against a commitment converted with
+// ToKryptologyCommitment.
+func ToKryptologyPedersen(vs *shamir.VerifiableShare) (secret, blinding KryptologyShare, err error) {
+	if secret, err = ToKryptologyShare(&vs.Share); err != nil {
+		return KryptologyShare{}, KryptologyShare{}, err
+	}
+	blinding = KryptologyShare{Id: secret.Id, Value: make([]byte, 32)}
+	PutScalar(blinding.Value, &vs.Decommitment, BigEndian)
+	return secret, blinding, nil
+}
+
+// FromKryptologyPedersen converts a pair of secret and blinding shares from a
+// kryptology Pedersen verifiable sharing to a verifiable share. An error is
+// returned if the two shares have different Ids.
+func FromKryptologyPedersen(secret, blinding KryptologyShare) (shamir.VerifiableShare, error) {
+	if secret.Id != blinding.Id {
+		return shamir.VerifiableShare{}, fmt.Errorf("secret share has id %v but blinding share has id %v", secret.Id, blinding.Id)
+	}
+	share, err := FromKryptologyShare(secret)
+	if err != nil {
+		return shamir.VerifiableShare{}, err
+	}
+	decommitment, err := ParseScalar(blinding.Value, BigEndian)
+	if err != nil {
+		return shamir.VerifiableShare{}, err
+	}
+	return shamir.NewVerifiableShare(share, decommitment), nil
+}
+
+// ToKryptologyCommitment returns the points of the commitment in the SEC 1
+// compressed encoding, in the order of the Commitments of a kryptology
+// PedersenVerifier or FeldmanVerifier.
+func ToKryptologyCommitment(c shamir.Commitment) [][]byte {
+	points := make([][]byte, c.Len())
+	for i := range points {
+		points[i] = PointSEC1(&c[i])
+	}
+	return points
+}
+
+// FromKryptologyCommitment parses the SEC 1 compressed points of a kryptology
+// verifier into a commitment.
+func FromKryptologyCommitment(points [][]byte) (shamir.Commitment, error) {
+	c := shamir.NewCommitmentWithCapacity(len(points))
+	for i := range points {
+		p, err := ParsePointSEC1(points[i])
+		if err != nil {
+			return nil, fmt.Errorf("point %v: %v", i, err)
+		}
+		c.Append(p)
+	}
+	return c, nil
+}
+
+// Returns the index as a kryptology share Id.
+func kryptologyID(index *secp256k1.Fn) (uint32, error) {
+	var bs [32]byte
+	index.PutB32(bs[:])
+	for _, b := range bs[:28] {
+		if b != 0 {
+			return 0, errors.New("index does not fit into a kryptology share id")
+		}
+	}
+	id := binary.BigEndian.Uint32(bs[28:])
+	if id == 0 {
+		return 0, errors.New("index zero can not be a kryptology share id")
+	}
+	return id, nil
+}
+
+func isInfinitySEC1(bs []byte) bool {
+	if len(bs) != 1 && len(bs) != SEC1PointSize {
+		return false
+	}
+	for _, b := range bs {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func reverseBytes(bs []byte) {
+	for i, j := 0, len(bs)-1; i < j; i, j = i+1, j-1 {
+		bs[i], bs[j] = bs[j], bs[i]
+	}
+}
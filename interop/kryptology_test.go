@@ -0,0 +1,204 @@
+package interop_test
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/interop"
+)
+
+var _ = Describe("Kryptology interoperability", func() {
+	trials := 50
+
+	mustHex := func(str string) []byte {
+		bs, err := hex.DecodeString(str)
+		Expect(err).ToNot(HaveOccurred())
+		return bs
+	}
+
+	// SEC 1 compressed encodings of the generator and its double, as given
+	// in the secp256k1 specification and widely published elsewhere.
+	const (
+		g  = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+		g2 = "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"
+	)
+
+	sequentialIndices := func(n int) []secp256k1.Fn {
+		indices := make([]secp256k1.Fn, n)
+		for i := range indices {
+			indices[i].SetU16(uint16(i + 1))
+		}
+		return indices
+	}
+
+	Context("scalars", func() {
+		It("should encode in the given byte order", func() {
+			one := secp256k1.NewFnFromU16(1)
+			bs := make([]byte, 32)
+
+			PutScalar(bs, &one, BigEndian)
+			Expect(bs[31]).To(Equal(byte(1)))
+			Expect(bs[:31]).To(Equal(make([]byte, 31)))
+
+			PutScalar(bs, &one, LittleEndian)
+			Expect(bs[0]).To(Equal(byte(1)))
+			Expect(bs[1:]).To(Equal(make([]byte, 31)))
+		})
+
+		It("should parse what was encoded in either byte order", func() {
+			bs := make([]byte, 32)
+			for i := 0; i < trials; i++ {
+				x := secp256k1.RandomFn()
+				for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+					PutScalar(bs, &x, order)
+					y, err := ParseScalar(bs, order)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(y.Eq(&x)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should return an error for scalars that are too large or of the wrong size", func() {
+			_, err := ParseScalar(bytes.Repeat([]byte{0xff}, 32), BigEndian)
+			Expect(err).To(HaveOccurred())
+			_, err = ParseScalar(make([]byte, 31), BigEndian)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("points", func() {
+		It("should match the published SEC 1 encodings", func() {
+			one, two := secp256k1.NewFnFromU16(1), secp256k1.NewFnFromU16(2)
+			var p secp256k1.Point
+			p.BaseExp(&one)
+			Expect(hex.EncodeToString(PointSEC1(&p))).To(Equal(g))
+			p.BaseExp(&two)
+			Expect(hex.EncodeToString(PointSEC1(&p))).To(Equal(g2))
+
+			q, err := ParsePointSEC1(mustHex(g2))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(q.Eq(&p)).To(BeTrue())
+		})
+
+		It("should parse what was encoded", func() {
+			for i := 0; i < trials; i++ {
+				p := secp256k1.RandomPoint()
+				q, err := ParsePointSEC1(PointSEC1(&p))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(q.Eq(&p)).To(BeTrue())
+			}
+		})
+
+		It("should handle both encodings of the point at infinity", func() {
+			inf := secp256k1.NewPointInfinity()
+			Expect(PointSEC1(&inf)).To(Equal([]byte{0}))
+			for _, bs := range [][]byte{{0}, make([]byte, SEC1PointSize)} {
+				p, err := ParsePointSEC1(bs)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(p.IsInfinity()).To(BeTrue())
+			}
+		})
+
+		It("should return an error for invalid encodings", func() {
+			bs := mustHex(g)
+			bs[0] = 1
+			_, err := ParsePointSEC1(bs)
+			Expect(err).To(HaveOccurred())
+			_, err = ParsePointSEC1(mustHex(g)[:32])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("shares", func() {
+		It("should match the binary format of kryptology", func() {
+			value := secp256k1.NewFnFromU16(0x1234)
+			share := shamir.NewShare(secp256k1.NewFnFromU16(5), value)
+			ks, err := ToKryptologyShare(&share)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ks.Id).To(Equal(uint32(5)))
+			Expect(hex.EncodeToString(ks.Bytes())).To(Equal(
+				"00000005" + "0000000000000000000000000000000000000000000000000000000000001234",
+			))
+		})
+
+		It("should convert shares to kryptology and back", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, 20)
+				k := shamirutil.RandRange(1, n)
+				secret := secp256k1.RandomFn()
+				shares := make(shamir.Shares, n)
+				Expect(shamir.ShareSecret(&shares, sequentialIndices(n), secret, k)).To(Succeed())
+
+				converted := make(shamir.Shares, n)
+				for j := range shares {
+					ks, err := ToKryptologyShare(&shares[j])
+					Expect(err).ToNot(HaveOccurred())
+					ks, err = ParseKryptologyShare(ks.Bytes())
+					Expect(err).ToNot(HaveOccurred())
+					converted[j], err = FromKryptologyShare(ks)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(converted[j].Eq(&shares[j])).To(BeTrue())
+				}
+				opened := shamir.Open(converted[:k])
+				Expect(opened.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an error for indices that kryptology can not represent", func() {
+			value := secp256k1.RandomFn()
+			for _, index := range []secp256k1.Fn{secp256k1.NewFnFromU16(0), secp256k1.RandomFn()} {
+				share := shamir.NewShare(index, value)
+				_, err := ToKryptologyShare(&share)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should return an error for shares with id zero", func() {
+			_, err := ParseKryptologyShare(make([]byte, 36))
+			Expect(err).To(HaveOccurred())
+			_, err = FromKryptologyShare(KryptologyShare{Id: 0, Value: make([]byte, 32)})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("pedersen verifiable sharing", func() {
+		It("should give shares that are valid for the converted commitment", func() {
+			h := shamir.PedersenH()
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, 20)
+				k := shamirutil.RandRange(1, n)
+				vshares := make(shamir.VerifiableShares, n)
+				c := shamir.NewCommitmentWithCapacity(k)
+				Expect(shamir.VShareSecret(&vshares, &c, sequentialIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+
+				points := ToKryptologyCommitment(c)
+				Expect(points).To(HaveLen(k))
+				converted, err := FromKryptologyCommitment(points)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(converted.Eq(c)).To(BeTrue())
+
+				for j := range vshares {
+					secret, blinding, err := ToKryptologyPedersen(&vshares[j])
+					Expect(err).ToNot(HaveOccurred())
+					Expect(blinding.Id).To(Equal(secret.Id))
+					vshare, err := FromKryptologyPedersen(secret, blinding)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(vshare.Eq(&vshares[j])).To(BeTrue())
+					Expect(shamir.IsValid(h, &converted, &vshare)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should return an error for secret and blinding shares with different ids", func() {
+			value := make([]byte, 32)
+			_, err := FromKryptologyPedersen(KryptologyShare{Id: 1, Value: value}, KryptologyShare{Id: 2, Value: value})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,154 @@
+// Package jointrand generates a verifiable sharing of a random value that no
+// party chooses, from random verifiable sharings dealt by each of the parties.
+// This is the joint random secret sharing that underlies distributed key
+// generation, threshold signatures and other protocols built on the
+// verifiable secret sharing of the shamir package.
+//
+// Each of the n parties deals a sharing of a fresh random value with Deal,
+// and sends each recipient its share together with the commitment. After the
+// complaint round, for example with the complaint package, the parties agree
+// on the set of qualified dealers whose dealings are valid. Each recipient
+// then adds the shares that it received from the qualified dealers with
+// Combine, which gives it a share of the sum of their values, and anyone can
+// compute the commitment to that sharing with CombineCommitments.
+//
+// If at most t of the parties are faulty, combining at least t+1 dealings
+// means that at least one of them is from an honest party, whose value is
+// uniformly random and, since Pedersen commitments are hiding, unknown to the
+// others when they fix their own dealings. The joint value is therefore
+// uniformly random and can not be biased. The combining functions check that
+// enough dealings are given, and that they are from distinct dealers and have
+// the same threshold. The sharing is only secret if t < k, so this is also
+// checked.
+package jointrand
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// ErrTooFewDealings is wrapped by the errors returned when fewer than t+1
+// dealings are combined.
+var ErrTooFewDealings = errors.New("too few qualified dealings")
+
+// A Dealing is the view of one dealing of a recipient: the index of the
+// dealer, the commitment of the sharing, and the share of the recipient.
+type Dealing struct {
+	Dealer     secp256k1.Fn
+	Commitment shamir.Commitment
+	Share      shamir.VerifiableShare
+}
+
+// Deal creates the contribution of one party: a verifiable sharing with
+// threshold k of a fresh random value, for the given indices. The value is
+// not returned, and is not needed again, since only the joint value is ever
+// reconstructed.
+func Deal(indices []secp256k1.Fn, h secp256k1.Point, k int) (shamir.Dealing, error) {
+	secret, err := shamir.RandomFn()
+	if err != nil {
+		return shamir.Dealing{}, err
+	}
+	defer secret.Clear()
+	return shamir.Deal(indices, h, secret, k)
+}
+
+// Combine checks the given dealings of the qualified dealers, of which there
+// must be at least t+1 for at most t faulty parties, and returns the share of
+// the recipient of the joint random value together with the commitment to the
+// joint sharing. Every share must be valid with regard to the commitment of
+// its dealing, and have the same index. Invalid shares should be resolved in
+// the complaint round, before the set of qualified dealers is fixed, so an
+// error is returned if one is given.
+func Combine(h secp256k1.Point, k, t int, dealings []Dealing) (shamir.VerifiableShare, shamir.Commitment, error) {
+	dealers := make([]secp256k1.Fn, len(dealings))
+	commitments := make([]shamir.Commitment, len(dealings))
+	for i := range dealings {
+		dealers[i] = dealings[i].Dealer
+		commitments[i] = dealings[i].Commitment
+	}
+	c, err := CombineCommitments(k, t, dealers, commitments)
+	if err != nil {
+		return shamir.VerifiableShare{}, nil, err
+	}
+
+	index := dealings[0].Share.Share.Index
+	var share shamir.VerifiableShare
+	for i := range dealings {
+		if !dealings[i].Share.Share.IndexEq(&index) {
+			share.Zero()
+			return shamir.VerifiableShare{}, nil, fmt.Errorf("dealing %v: share has a different index", i)
+		}
+		if !shamir.IsValid(h, &dealings[i].Commitment, &dealings[i].Share) {
+			share.Zero()
+			return shamir.VerifiableShare{}, nil, fmt.Errorf("dealing %v: share is not valid", i)
+		}
+		if i == 0 {
+			share = dealings[i].Share
+		} else {
+			share.Add(&share, &dealings[i].Share)
+		}
+	}
+	return share, c, nil
+}
+
+// CombineCommitments checks that the commitments of the given qualified
+// dealers, of which there must be at least t+1 for at most t faulty parties,
+// are from distinct dealers and for sharings with threshold k, and returns
+// their sum, which is the commitment to the joint sharing. The first point of
+// the sum commits to the joint random value.
+func CombineCommitments(k, t int, dealers []secp256k1.Fn, commitments []shamir.Commitment) (shamir.Commitment, error) {
+	if t < 0 || t >= k {
+		return nil, fmt.Errorf("invalid number of faulty parties: expected 0 <= t < k = %v, got t = %v", k, t)
+	}
+	if len(dealers) != len(commitments) {
+		return nil, fmt.Errorf("expected a commitment for each of the %v dealers, got %v", len(dealers), len(commitments))
+	}
+	if len(dealers) < t+1 {
+		return nil, fmt.Errorf("%w: expected at least t+1 = %v, got %v", ErrTooFewDealings, t+1, len(dealers))
+	}
+	c := shamir.NewCommitmentWithCapacity(k)
+	for i := range dealers {
+		for j := 0; j < i; j++ {
+			if dealers[i].Eq(&dealers[j]) {
+				return nil, fmt.Errorf("dealings %v and %v are from the same dealer", j, i)
+			}
+		}
+		if commitments[i].Len() != k {
+			return nil, fmt.Errorf("dealing %v: expected a commitment of length %v, got %v", i, k, commitments[i].Len())
+		}
+		c.Add(c, commitments[i])
+	}
+	return c, nil
+}
+
+// Open reconstructs the joint random value from the shares that are revealed
+// by the recipients, using only the shares that are valid with regard to the
+// commitment to the joint sharing. An error is returned if fewer than k of
+// the shares are valid, where k is the length of the commitment, or if two
+// valid shares have the same index.
+func Open(h secp256k1.Point, c shamir.Commitment, vshares shamir.VerifiableShares) (secp256k1.Fn, error) {
+	k := c.Len()
+	shares := make(shamir.Shares, 0, k)
+	defer shares.Zero()
+	for i := range vshares {
+		if len(shares) == k {
+			break
+		}
+		if !shamir.IsValid(h, &c, &vshares[i]) {
+			continue
+		}
+		for j := range shares {
+			if shares[j].IndexEq(&vshares[i].Share.Index) {
+				return secp256k1.Fn{}, fmt.Errorf("share %v has the same index as an earlier share", i)
+			}
+		}
+		shares = append(shares, vshares[i].Share)
+	}
+	if k == 0 || len(shares) < k {
+		return secp256k1.Fn{}, fmt.Errorf("too few valid shares: expected at least %v, got %v", k, len(shares))
+	}
+	return shamir.Open(shares), nil
+}
@@ -0,0 +1,13 @@
+package jointrand_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestJointrand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jointrand Suite")
+}
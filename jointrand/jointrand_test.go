@@ -0,0 +1,123 @@
+package jointrand_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/jointrand"
+)
+
+var _ = Describe("Joint random sharing", func() {
+	n, k, t := 7, 3, 2
+	h := shamir.PedersenH()
+	indices := shamirutil.SequentialIndices(n)
+
+	deal := func() []shamir.Dealing {
+		dealings := make([]shamir.Dealing, n)
+		for i := range dealings {
+			d, err := Deal(indices, h, k)
+			Expect(err).ToNot(HaveOccurred())
+			dealings[i] = d
+		}
+		return dealings
+	}
+
+	// Returns the view of the recipient with the given position of the
+	// dealings of the given dealers.
+	view := func(dealings []shamir.Dealing, qualified []int, recipient int) []Dealing {
+		views := make([]Dealing, len(qualified))
+		for i, d := range qualified {
+			views[i] = Dealing{
+				Dealer:     indices[d],
+				Commitment: dealings[d].Commitment,
+				Share:      dealings[d].Shares[recipient],
+			}
+		}
+		return views
+	}
+
+	It("should share the sum of the values of the qualified dealings", func() {
+		dealings := deal()
+		qualified := []int{0, 2, 3, 6}
+
+		var expected secp256k1.Fn
+		dealers := make([]secp256k1.Fn, len(qualified))
+		commitments := make([]shamir.Commitment, len(qualified))
+		for i, d := range qualified {
+			value := shamir.Open(dealings[d].Shares.Shares())
+			expected.Add(&expected, &value)
+			dealers[i] = indices[d]
+			commitments[i] = dealings[d].Commitment
+		}
+		c, err := CombineCommitments(k, t, dealers, commitments)
+		Expect(err).ToNot(HaveOccurred())
+
+		vshares := make(shamir.VerifiableShares, n)
+		for r := range vshares {
+			share, com, err := Combine(h, k, t, view(dealings, qualified, r))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(com.Eq(c)).To(BeTrue())
+			Expect(share.Share.Index.Eq(&indices[r])).To(BeTrue())
+			vshares[r] = share
+		}
+
+		value, err := Open(h, c, vshares)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value.Eq(&expected)).To(BeTrue())
+
+		// Invalid shares are skipped when opening.
+		shamirutil.PerturbValue(&vshares[0])
+		shamirutil.PerturbDecommitment(&vshares[1])
+		value, err = Open(h, c, vshares)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value.Eq(&expected)).To(BeTrue())
+		_, err = Open(h, c, vshares[:k+1])
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should require at least t+1 dealings", func() {
+		dealings := deal()
+		_, _, err := Combine(h, k, t, view(dealings, []int{1, 4}, 0))
+		Expect(errors.Is(err, ErrTooFewDealings)).To(BeTrue())
+		_, _, err = Combine(h, k, t, view(dealings, []int{1, 4, 5}, 0))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should reject invalid parameters and dealings", func() {
+		dealings := deal()
+		views := view(dealings, []int{0, 1, 2}, 0)
+		_, _, err := Combine(h, k, k, views)
+		Expect(err).To(HaveOccurred())
+
+		// The same dealer twice.
+		dup := append([]Dealing{}, views...)
+		dup[1].Dealer = dup[0].Dealer
+		_, _, err = Combine(h, k, t, dup)
+		Expect(err).To(HaveOccurred())
+
+		// A dealing with a different threshold.
+		other, err := shamir.Deal(indices, h, secp256k1.RandomFn(), k+1)
+		Expect(err).ToNot(HaveOccurred())
+		mixed := append([]Dealing{}, views...)
+		mixed[2].Commitment, mixed[2].Share = other.Commitment, other.Shares[0]
+		_, _, err = Combine(h, k, t, mixed)
+		Expect(err).To(HaveOccurred())
+
+		// An invalid share.
+		bad := append([]Dealing{}, views...)
+		shamirutil.PerturbValue(&bad[1].Share)
+		_, _, err = Combine(h, k, t, bad)
+		Expect(err).To(HaveOccurred())
+
+		// A share for a different recipient.
+		wrong := append([]Dealing{}, views...)
+		wrong[1].Share = dealings[1].Shares[1]
+		_, _, err = Combine(h, k, t, wrong)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,178 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// LagrangeCoefficient computes the Lagrange coefficient for the given index
+// for interpolation at zero over the given set of indices, which must contain
+// the index. That is, it computes the product of x_j / (x_j - x_i) over all
+// indices x_j that are not equal to x_i. If the indices are the indices of a
+// qualified set of shares, the secret is the sum of the share values
+// multiplied by their coefficients. An error is returned if the index is not
+// in the set, or if the set contains duplicate indices.
+func LagrangeCoefficient(index *secp256k1.Fn, indices []secp256k1.Fn) (secp256k1.Fn, error) {
+	var num, denom, tmp secp256k1.Fn
+	num.SetU16(1)
+	denom.SetU16(1)
+	found := false
+	for j := range indices {
+		if indices[j].Eq(index) {
+			if found {
+				return secp256k1.Fn{}, fmt.Errorf("duplicate index at position %v", j)
+			}
+			found = true
+			continue
+		}
+		tmp.Negate(index)
+		tmp.Add(&tmp, &indices[j])
+		denom.Mul(&denom, &tmp)
+		num.Mul(&num, &indices[j])
+	}
+	if !found {
+		return secp256k1.Fn{}, fmt.Errorf("index is not in the set of indices")
+	}
+	denom.Inverse(&denom)
+	num.Mul(&num, &denom)
+	return num, nil
+}
+
+// LagrangeCoefficients computes the Lagrange coefficients for interpolation
+// at zero for all of the given indices, so that the i-th coefficient is
+// LagrangeCoefficient(&indices[i], indices), but with a single inversion for
+// all of the denominators. An error is returned if the indices are not
+// distinct.
+func LagrangeCoefficients(indices []secp256k1.Fn) ([]secp256k1.Fn, error) {
+	return lagrangeAtZero(indices)
+}
+
+// InterpolateInExponent computes the sum of the given points multiplied by the
+// Lagrange coefficients of the corresponding indices. If the points are the
+// points value*P for the values of a qualified set of shares with the given
+// indices, the result is secret*P. An error is returned if the number of
+// points does not match the number of indices, or if the indices are not
+// distinct.
+func InterpolateInExponent(indices []secp256k1.Fn, points []secp256k1.Point) (secp256k1.Point, error) {
+	if len(indices) != len(points) {
+		return secp256k1.Point{}, fmt.Errorf("expected %v points, got %v", len(indices), len(points))
+	}
+	coeffs, err := LagrangeCoefficients(indices)
+	if err != nil {
+		return secp256k1.Point{}, err
+	}
+	var res secp256k1.Point
+	msmPoints(&res, points, coeffs)
+	return res, nil
+}
+
+// InterpolateCommitment returns the commitment to the polynomial of degree
+// less than len(indices) whose evaluations in the exponent at the given
+// indices are the given points. That is, if the points are f(x_i)*G for a
+// polynomial f with coefficients a_j, the result is the commitment whose j-th
+// point is a_j*G, as for a Feldman commitment. This derives, for example, the
+// commitment to a jointly generated key, and so every partial public key,
+// from the partial public keys of any k parties. The first point of the
+// commitment is the result of InterpolateInExponent. If f has degree less
+// than k-1 for k < len(indices), the trailing points are the point at
+// infinity, so the indices of exactly k parties should be given when the
+// threshold is known. Each point of the commitment is computed with a
+// multi-scalar multiplication. An error is returned if the number of points
+// does not match the number of indices, or if the indices are not distinct or
+// empty.
+func InterpolateCommitment(indices []secp256k1.Fn, points []secp256k1.Point) (Commitment, error) {
+	n := len(indices)
+	if n != len(points) {
+		return nil, fmt.Errorf("expected %v points, got %v", n, len(points))
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no points to interpolate")
+	}
+
+	// The Lagrange basis polynomial for x_i is N_i(x)/N_i(x_i), where N_i is
+	// the product of x - x_m over all m != i. Each N_i is the quotient of the
+	// product N of all of the factors by x - x_i.
+	denoms := make([]secp256k1.Fn, n)
+	var tmp secp256k1.Fn
+	for i := range indices {
+		denoms[i].SetU16(1)
+		for m := range indices {
+			if m == i {
+				continue
+			}
+			tmp.Negate(&indices[m])
+			tmp.Add(&tmp, &indices[i])
+			if tmp.IsZero() {
+				return nil, fmt.Errorf("duplicate index at positions %v and %v", m, i)
+			}
+			denoms[i].Mul(&denoms[i], &tmp)
+		}
+	}
+	BatchInvert(denoms)
+
+	master := make([]secp256k1.Fn, n+1)
+	master[0].SetU16(1)
+	for m := range indices {
+		for j := m + 1; j > 0; j-- {
+			tmp.Mul(&indices[m], &master[j])
+			master[j].Negate(&tmp)
+			master[j].Add(&master[j], &master[j-1])
+		}
+		master[0].Mul(&master[0], &indices[m])
+		master[0].Negate(&master[0])
+	}
+
+	// The scalars for the j-th point of the commitment are the j-th
+	// coefficients of the basis polynomials.
+	scalars := make([]secp256k1.Fn, n*n)
+	quotient := make([]secp256k1.Fn, n)
+	for i := range indices {
+		quotient[n-1] = master[n]
+		for j := n - 1; j > 0; j-- {
+			quotient[j-1].Mul(&indices[i], &quotient[j])
+			quotient[j-1].Add(&quotient[j-1], &master[j])
+		}
+		for j := range quotient {
+			scalars[j*n+i].Mul(&quotient[j], &denoms[i])
+		}
+	}
+
+	c := make(Commitment, n)
+	for j := range c {
+		msmPoints(&c[j], points, scalars[j*n:(j+1)*n])
+	}
+	return c, nil
+}
+
+// BatchInvert replaces every element of the slice with its inverse, using
+// Montgomery's trick: the product of all of the elements is inverted, and the
+// inverse of each element is recovered from the prefix products, so that n
+// inversions are replaced by one inversion and 3(n-1) multiplications. The
+// elements must be non-zero; if any of them is zero, every element is set to
+// zero. The Scalar types of the p256, bn254 and ristretto255 packages have
+// functions of the same name.
+func BatchInvert(xs []secp256k1.Fn) {
+	var inv, tmp secp256k1.Fn
+	batchInvertWith(xs, make([]secp256k1.Fn, len(xs)), &inv, &tmp)
+}
+
+// Same as BatchInvert, but uses the given slice, which must be at least as
+// long as xs, for the prefix products, and the given temporaries, so that it
+// does not allocate.
+func batchInvertWith(xs, prefix []secp256k1.Fn, inv, tmp *secp256k1.Fn) {
+	if len(xs) == 0 {
+		return
+	}
+	prefix[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		prefix[i].Mul(&prefix[i-1], &xs[i])
+	}
+	inv.Inverse(&prefix[len(xs)-1])
+	for i := len(xs) - 1; i > 0; i-- {
+		tmp.Mul(inv, &prefix[i-1])
+		inv.Mul(inv, &xs[i])
+		xs[i] = *tmp
+	}
+	xs[0] = *inv
+}
@@ -0,0 +1,146 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Lagrange interpolation", func() {
+	trials := 20
+	n := 10
+
+	It("should reconstruct the secret from any qualified subset", func() {
+		indices := RandomIndices(n)
+		shares := make(Shares, n)
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n)
+			secret := secp256k1.RandomFn()
+			Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+
+			subset := make(Shares, RandRange(k, n))
+			subsetIndices := make([]secp256k1.Fn, len(subset))
+			for j, p := range rand.Perm(n)[:len(subset)] {
+				subset[j] = shares[p]
+				subsetIndices[j] = shares[p].Index
+			}
+			coeffs, err := LagrangeCoefficients(subsetIndices)
+			Expect(err).ToNot(HaveOccurred())
+
+			var recon, term secp256k1.Fn
+			for j := range subset {
+				term.Mul(&coeffs[j], &subset[j].Value)
+				recon.Add(&recon, &term)
+			}
+			Expect(recon.Eq(&secret)).To(BeTrue())
+
+			points := make([]secp256k1.Point, len(subset))
+			for j := range subset {
+				points[j].BaseExp(&subset[j].Value)
+			}
+			var expected secp256k1.Point
+			expected.BaseExp(&secret)
+			pk, err := InterpolateInExponent(subsetIndices, points)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pk.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should interpolate commitments in the exponent", func() {
+		h := secp256k1.RandomPoint()
+		indices := RandomIndices(n)
+		vshares := make(VerifiableShares, n)
+		for i := 0; i < trials; i++ {
+			k := RandRange(1, n)
+			c := NewCommitmentWithCapacity(k)
+			Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+
+			// The evaluations of the commitment are commitments to the
+			// shares, so interpolating k of them gives back the commitment.
+			subset := rand.Perm(n)
+			subsetIndices := make([]secp256k1.Fn, k)
+			points := make([]secp256k1.Point, k)
+			for j, p := range subset[:k] {
+				subsetIndices[j] = indices[p]
+				points[j] = c.Evaluate(&indices[p])
+			}
+			interpolated, err := InterpolateCommitment(subsetIndices, points)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(interpolated.Eq(c)).To(BeTrue())
+
+			// With more than k points, the trailing points are infinity.
+			if k < n {
+				subsetIndices = append(subsetIndices, indices[subset[k]])
+				points = append(points, c.Evaluate(&indices[subset[k]]))
+				interpolated, err = InterpolateCommitment(subsetIndices, points)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(interpolated[:k].Eq(c)).To(BeTrue())
+				Expect(interpolated[k].IsInfinity()).To(BeTrue())
+			}
+		}
+
+		_, err := InterpolateCommitment(nil, nil)
+		Expect(err).To(HaveOccurred())
+		dup := []secp256k1.Fn{indices[0], indices[0]}
+		_, err = InterpolateCommitment(dup, make([]secp256k1.Point, 2))
+		Expect(err).To(HaveOccurred())
+		_, err = InterpolateCommitment(indices[:2], make([]secp256k1.Point, 1))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return errors for invalid sets of indices", func() {
+		indices := RandomIndices(n)
+		other := secp256k1.RandomFn()
+		_, err := LagrangeCoefficient(&other, indices)
+		Expect(err).To(HaveOccurred())
+
+		indices[1] = indices[0]
+		_, err = LagrangeCoefficients(indices)
+		Expect(err).To(HaveOccurred())
+
+		_, err = InterpolateInExponent(indices[:2], []secp256k1.Point{secp256k1.RandomPoint()})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should evaluate commitments in the exponent", func() {
+		h := secp256k1.RandomPoint()
+		indices := RandomIndices(n)
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(n)
+		Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), RandRange(1, n))).To(Succeed())
+		for i := range vshares {
+			var expected, hPow secp256k1.Point
+			expected.BaseExp(&vshares[i].Share.Value)
+			hPow.Scale(&h, &vshares[i].Decommitment)
+			expected.Add(&expected, &hPow)
+			eval := c.Evaluate(&vshares[i].Share.Index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should invert every element of a batch", func() {
+		for i := 0; i < trials; i++ {
+			xs := RandomIndices(rand.Intn(n + 1))
+			invs := make([]secp256k1.Fn, len(xs))
+			copy(invs, xs)
+			BatchInvert(invs)
+			for j := range xs {
+				var expected secp256k1.Fn
+				expected.Inverse(&xs[j])
+				Expect(invs[j].Eq(&expected)).To(BeTrue())
+			}
+		}
+
+		xs := RandomIndices(n)
+		xs[rand.Intn(n)] = secp256k1.Fn{}
+		BatchInvert(xs)
+		for j := range xs {
+			Expect(xs[j].IsZero()).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,291 @@
+package shamir
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Large sharings
+//
+// The functions in this file support sharings with tens of thousands of
+// shares, such as those needed by large staking committees, where the
+// functions that are tuned for small sharings use too much time or memory:
+//	- ShareSecret holds every share in memory at once. ShareSecretChunked
+//		produces the shares a chunk at a time, evaluating each chunk in
+//		parallel.
+//	- Open takes time quadratic in the number of shares, with an inversion
+//		for every share, and needs every share in memory at once. An Opener
+//		computes the Lagrange coefficients once, with a single inversion, and
+//		then consumes the shares one at a time, for example as they are read
+//		from a stream with Shares.ReadFrom. For the sequential indices 1, 2,
+//		..., n, the coefficients are computed in linear time.
+//	- Commitment.EvaluateMSM, and so IsValid, use Pippenger's method for long
+//		commitments.
+//
+// FFT based interpolation is not provided: the order of the secp256k1 group
+// minus one is divisible by 2^6 but no higher power of two, so the field only
+// has radix 2 FFTs of up to 64 points, which is not enough to be worthwhile.
+
+// The number of shares produced at a time by ShareSecretChunked when it is
+// given a non-positive chunk length.
+const defaultChunkLen = 1024
+
+// ShareSecretChunked creates Shamir shares for the given secret at the given
+// threshold in the same way as ShareSecret, but rather than storing all of
+// them it passes them to emit in order, at most chunkLen at a time, so that
+// only one chunk is held in memory. A default chunk length is used if chunkLen
+// is not positive. The shares within a chunk are evaluated in parallel. The
+// slice passed to emit is reused for the next chunk and zeroed once sharing
+// is done, so emit must copy any shares that it needs to keep. If emit returns
+// an error, sharing stops and the error is returned.
+//
+// Panics: This function will panic under the same conditions as ShareSecret,
+// except that there is no destination slice.
+func ShareSecretChunked(
+	indices []secp256k1.Fn,
+	secret secp256k1.Fn,
+	k, chunkLen int,
+	emit func(Shares) error,
+	opts ...ShareOption,
+) error {
+	options := newShareOptions(opts)
+	if err := validateIndices(indices, options); err != nil {
+		return err
+	}
+	if err := checkIndices(indices, k); err != nil {
+		return err
+	}
+	if chunkLen <= 0 {
+		chunkLen = defaultChunkLen
+	}
+
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	s.reserve(k)
+	defer s.wipe()
+	coeffs := s.coeffs[:k]
+	coeffs[0] = secret
+	if err := s.sampleFns(&options, coeffs[1:]); err != nil {
+		return err
+	}
+
+	chunk := make(Shares, 0, minInt(chunkLen, len(indices)))
+	defer chunk.Zero()
+	for start := 0; start < len(indices); start += chunkLen {
+		end := minInt(start+chunkLen, len(indices))
+		chunk = chunk[:end-start]
+		parallelFor(len(chunk), func(i int) {
+			chunk[i].Index = indices[start+i]
+			polyEval(&chunk[i].Value, &chunk[i].Index, coeffs)
+		})
+		if err := emit(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// An Opener reconstructs a secret from the shares with a fixed set of
+// indices, consuming the shares one at a time so that they need not all be
+// held in memory. After construction, it holds the Lagrange coefficients of
+// the indices and the running sum, and nothing else that grows with the
+// number of shares that have been added. An Opener must not be used by more
+// than one goroutine at a time.
+type Opener struct {
+	coeffs    []secp256k1.Fn
+	positions map[[32]byte]int
+	received  []bool
+	remaining int
+	acc       secp256k1.Fn
+}
+
+// NewOpener constructs an opener for the shares with the given indices. As
+// for Open, the secret is only reconstructed if the indices are those of at
+// least k valid shares of a sharing with threshold k. An error is returned if
+// the indices are not distinct.
+func NewOpener(indices []secp256k1.Fn) (*Opener, error) {
+	coeffs, err := lagrangeAtZero(indices)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[[32]byte]int, len(indices))
+	var key [32]byte
+	for i := range indices {
+		indices[i].PutB32(key[:])
+		positions[key] = i
+	}
+	return &Opener{
+		coeffs:    coeffs,
+		positions: positions,
+		received:  make([]bool, len(indices)),
+		remaining: len(indices),
+	}, nil
+}
+
+// Add adds the share to the reconstruction. An error is returned, and the
+// share is ignored, if its index is not one of the indices of the opener or
+// if a share with the same index has already been added.
+func (o *Opener) Add(share *Share) error {
+	var key [32]byte
+	share.Index.PutB32(key[:])
+	i, ok := o.positions[key]
+	if !ok {
+		return fmt.Errorf("share index is not one of the indices of the opener")
+	}
+	if o.received[i] {
+		return fmt.Errorf("share with index at position %v has already been added", i)
+	}
+	o.received[i] = true
+	o.remaining--
+
+	var term secp256k1.Fn
+	term.Mul(&o.coeffs[i], &share.Value)
+	o.acc.Add(&o.acc, &term)
+	term.Clear()
+	return nil
+}
+
+// Remaining returns the number of shares that are yet to be added.
+func (o *Opener) Remaining() int {
+	return o.remaining
+}
+
+// Secret returns the reconstructed secret. An error is returned if not every
+// share has been added.
+func (o *Opener) Secret() (secp256k1.Fn, error) {
+	if o.remaining != 0 {
+		return secp256k1.Fn{}, fmt.Errorf("%v shares are yet to be added", o.remaining)
+	}
+	return o.acc, nil
+}
+
+// Wipe zeroes the running sum of the opener, which is derived from the
+// secret, and the opener can not be used afterwards.
+func (o *Opener) Wipe() {
+	o.acc.Clear()
+	o.remaining = -1
+}
+
+// Computes the Lagrange coefficients for interpolation at zero, as for
+// LagrangeCoefficients, but with a single inversion. This takes time linear in
+// the number of indices if they are 1, 2, ..., n, and quadratic otherwise.
+func lagrangeAtZero(indices []secp256k1.Fn) ([]secp256k1.Fn, error) {
+	n := len(indices)
+	if isSequential(indices) {
+		return lagrangeSequential(n), nil
+	}
+
+	// The i-th coefficient is the product of x_j over j != i, divided by the
+	// product of x_j - x_i over j != i.
+	nums := make([]secp256k1.Fn, n)
+	denoms := make([]secp256k1.Fn, n)
+	var diff secp256k1.Fn
+	for i := range indices {
+		nums[i].SetU16(1)
+		denoms[i].SetU16(1)
+		for j := range indices {
+			if i == j {
+				continue
+			}
+			diff.Negate(&indices[i])
+			diff.Add(&diff, &indices[j])
+			if diff.IsZero() {
+				return nil, fmt.Errorf("duplicate index at positions %v and %v", i, j)
+			}
+			denoms[i].Mul(&denoms[i], &diff)
+			nums[i].Mul(&nums[i], &indices[j])
+		}
+	}
+	BatchInvert(denoms)
+	for i := range nums {
+		nums[i].Mul(&nums[i], &denoms[i])
+	}
+	return nums, nil
+}
+
+// For the indices 1, 2, ..., n, the i-th Lagrange coefficient for
+// interpolation at zero is (-1)^(i-1) times the binomial coefficient
+// C(n, i), which is computed from the previous one as C(n, i-1)(n-i+1)/i.
+func lagrangeSequential(n int) []secp256k1.Fn {
+	invs := make([]secp256k1.Fn, n)
+	var one secp256k1.Fn
+	one.SetU16(1)
+	for i := range invs {
+		if i == 0 {
+			invs[i] = one
+		} else {
+			invs[i].Add(&invs[i-1], &one)
+		}
+	}
+	coeffs := make([]secp256k1.Fn, n)
+	if n == 0 {
+		return coeffs
+	}
+	factor := invs[n-1]
+	// invs[i-1] holds i, and after inversion holds 1/i.
+	BatchInvert(invs)
+
+	binom := one
+	var minusOne secp256k1.Fn
+	minusOne.Negate(&one)
+	for i := 1; i <= n; i++ {
+		// factor holds n - i + 1.
+		binom.Mul(&binom, &factor)
+		binom.Mul(&binom, &invs[i-1])
+		if i%2 == 1 {
+			coeffs[i-1] = binom
+		} else {
+			coeffs[i-1].Negate(&binom)
+		}
+		factor.Add(&factor, &minusOne)
+	}
+	return coeffs
+}
+
+// Returns true if the indices are 1, 2, ..., n.
+func isSequential(indices []secp256k1.Fn) bool {
+	var expected, one secp256k1.Fn
+	one.SetU16(1)
+	for i := range indices {
+		expected.Add(&expected, &one)
+		if !indices[i].Eq(&expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// Calls f(i) for every i in [0, n), splitting the range between as many
+// goroutines as there are processors.
+func parallelFor(n int, f func(i int)) {
+	workers := minInt(runtime.GOMAXPROCS(0), n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	per := (n + workers - 1) / workers
+	for start := 0; start < n; start += per {
+		end := minInt(start+per, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				f(i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
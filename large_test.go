@@ -0,0 +1,169 @@
+package shamir_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Large sharings", func() {
+	trials := 10
+
+	Context("chunked sharing", func() {
+		It("should emit shares that open to the secret", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 100)
+				k := RandRange(1, n)
+				chunkLen := RandRange(0, n+5)
+				indices := RandomIndices(n)
+				secret := secp256k1.RandomFn()
+
+				shares := make(Shares, 0, n)
+				err := ShareSecretChunked(indices, secret, k, chunkLen, func(chunk Shares) error {
+					if chunkLen > 0 {
+						Expect(len(chunk)).To(BeNumerically("<=", chunkLen))
+					}
+					shares = append(shares, chunk...)
+					return nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(shares)).To(Equal(n))
+				for j := range shares {
+					Expect(shares[j].Index.Eq(&indices[j])).To(BeTrue())
+				}
+				Shuffle(shares)
+				opened := Open(shares[:k])
+				Expect(opened.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should stop and return the error from emit", func() {
+			calls := 0
+			failure := errors.New("disk full")
+			err := ShareSecretChunked(RandomIndices(50), secp256k1.RandomFn(), 10, 10, func(Shares) error {
+				calls++
+				return failure
+			})
+			Expect(err).To(Equal(failure))
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should return an error when the threshold is larger than the number of indices", func() {
+			err := ShareSecretChunked(RandomIndices(5), secp256k1.RandomFn(), 6, 2, func(Shares) error { return nil })
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("streaming opener", func() {
+		openStream := func(indices []secp256k1.Fn, shares Shares) secp256k1.Fn {
+			opener, err := NewOpener(indices)
+			Expect(err).ToNot(HaveOccurred())
+			for j := range shares {
+				Expect(opener.Remaining()).To(Equal(len(shares) - j))
+				Expect(opener.Add(&shares[j])).To(Succeed())
+			}
+			secret, err := opener.Secret()
+			Expect(err).ToNot(HaveOccurred())
+			return secret
+		}
+
+		It("should open the secret for random and sequential indices", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 100)
+				k := RandRange(1, n)
+				for _, indices := range [][]secp256k1.Fn{RandomIndices(n), SequentialIndices(n)} {
+					secret := secp256k1.RandomFn()
+					shares := make(Shares, n)
+					Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+					Shuffle(shares)
+
+					opened := openStream(indices, shares)
+					Expect(opened.Eq(&secret)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should agree with Open for a subset of the shares", func() {
+			n, k := 30, 10
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, SequentialIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+			Shuffle(shares)
+			subset := shares[:k+2]
+			indices := make([]secp256k1.Fn, len(subset))
+			for j := range subset {
+				indices[j] = subset[j].Index
+			}
+
+			expected := Open(subset)
+			opened := openStream(indices, subset)
+			Expect(opened.Eq(&expected)).To(BeTrue())
+		})
+
+		It("should reject unknown and repeated indices and incomplete input", func() {
+			n, k := 10, 4
+			indices := RandomIndices(n)
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, indices, secp256k1.RandomFn(), k)).To(Succeed())
+
+			opener, err := NewOpener(indices)
+			Expect(err).ToNot(HaveOccurred())
+			unknown := NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			Expect(opener.Add(&unknown)).ToNot(Succeed())
+			Expect(opener.Add(&shares[0])).To(Succeed())
+			Expect(opener.Add(&shares[0])).ToNot(Succeed())
+			Expect(opener.Remaining()).To(Equal(n - 1))
+			_, err = opener.Secret()
+			Expect(err).To(HaveOccurred())
+
+			opener.Wipe()
+			_, err = opener.Secret()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for duplicate indices", func() {
+			indices := RandomIndices(5)
+			indices[3] = indices[1]
+			_, err := NewOpener(indices)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func BenchmarkShareSecretChunked10k(b *testing.B) {
+	n := 10000
+	k := 3334
+
+	indices := SequentialIndices(n)
+	secret := secp256k1.RandomFn()
+	emit := func(Shares) error { return nil }
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ShareSecretChunked(indices, secret, k, 0, emit)
+	}
+}
+
+func BenchmarkOpener10k(b *testing.B) {
+	n := 10000
+	k := 3334
+
+	indices := SequentialIndices(n)
+	shares := make(Shares, n)
+	secret := secp256k1.RandomFn()
+	_ = ShareSecret(&shares, indices, secret, k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		opener, _ := NewOpener(indices)
+		for j := range shares {
+			_ = opener.Add(&shares[j])
+		}
+		_, _ = opener.Secret()
+	}
+}
@@ -0,0 +1,36 @@
+//go:build go1.21
+// +build go1.21
+
+package shamir
+
+import (
+	"log/slog"
+)
+
+// LogValue implements the slog.LogValuer interface. Only the index of the
+// share is logged.
+func (s Share) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("index", formatFn(&s.Index)),
+		slog.String("value", redacted),
+	)
+}
+
+// LogValue implements the slog.LogValuer interface. Only the index of the
+// share is logged.
+func (vs VerifiableShare) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("index", formatFn(&vs.Share.Index)),
+		slog.String("value", redacted),
+		slog.String("decommitment", redacted),
+	)
+}
+
+// LogValue implements the slog.LogValuer interface. The commitment is logged
+// as its length and a digest of its points.
+func (c Commitment) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("len", len(c)),
+		slog.String("digest", c.shortDigest()),
+	)
+}
@@ -0,0 +1,34 @@
+//go:build go1.21
+// +build go1.21
+
+package shamir_test
+
+import (
+	"bytes"
+	"log/slog"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Structured logging", func() {
+	value := secp256k1.RandomFn()
+	decom := secp256k1.RandomFn()
+	vshare := NewVerifiableShare(NewShare(secp256k1.NewFnFromU16(42), value), decom)
+	hexOf := func(x secp256k1.Fn) string { return x.Int().Text(16) }
+
+	It("should not log secret values", func() {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		logger.Info("received", "share", vshare, "commitment", RandomCommitment(2))
+
+		Expect(buf.String()).To(ContainSubstring("share.index=0x2a"))
+		Expect(buf.String()).To(ContainSubstring("commitment.len=2"))
+		Expect(buf.String()).ToNot(ContainSubstring(hexOf(value)))
+		Expect(buf.String()).ToNot(ContainSubstring(hexOf(decom)))
+	})
+})
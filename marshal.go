@@ -1,6 +1,8 @@
 package shamir
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
 	"reflect"
 
@@ -8,6 +10,11 @@ import (
 	"github.com/renproject/surge"
 )
 
+// ErrCapacity is wrapped by the errors returned when decoding
+// into a caller provided slice whose capacity is less than the declared
+// length.
+var ErrCapacity = errors.New("insufficient capacity")
+
 // Generate implements the quick.Generator interface.
 func (s Share) Generate(_ *rand.Rand, _ int) reflect.Value {
 	return reflect.ValueOf(NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()))
@@ -64,14 +71,38 @@ func (shares *Shares) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
 		return buf, rem, err
 	}
 
-	if *shares == nil {
+	if *shares == nil || uint32(cap(*shares)) < l {
 		*shares = make(Shares, 0, l)
 	}
 
-	*shares = (*shares)[:0]
-	for i := uint32(0); i < l; i++ {
-		*shares = append(*shares, Share{})
-		buf, rem, err = (*shares)[i].Unmarshal(buf, rem)
+	*shares = (*shares)[:l]
+	return unmarshalShares(*shares, buf, rem)
+}
+
+// UnmarshalSharesInto is the same as Shares.Unmarshal, but decodes the shares
+// into the backing array of dst instead of allocating a slice, and returns dst
+// resliced to the decoded length. An error wrapping ErrCapacity is
+// returned if the declared length is greater than the capacity of dst, so the
+// memory used to decode is bounded by the caller regardless of the input.
+func UnmarshalSharesInto(dst Shares, buf []byte, rem int) (Shares, []byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, ShareSize, buf, rem)
+	if err != nil {
+		return dst[:0], buf, rem, err
+	}
+	if uint32(cap(dst)) < l {
+		return dst[:0], buf, rem, fmt.Errorf("%w: length %v, capacity %v", ErrCapacity, l, cap(dst))
+	}
+
+	dst = dst[:l]
+	buf, rem, err = unmarshalShares(dst, buf, rem)
+	return dst, buf, rem, err
+}
+
+func unmarshalShares(dst Shares, buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	for i := range dst {
+		buf, rem, err = dst[i].Unmarshal(buf, rem)
 		if err != nil {
 			return buf, rem, err
 		}
@@ -1,14 +1,19 @@
 package shamir_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"testing"
 
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
 	"github.com/renproject/surge/surgeutil"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
 )
 
 var _ = Describe("Surge marshalling", func() {
@@ -19,6 +24,7 @@ var _ = Describe("Surge marshalling", func() {
 		reflect.TypeOf(Commitment{}),
 		reflect.TypeOf(VerifiableShare{}),
 		reflect.TypeOf(VerifiableShares{}),
+		reflect.TypeOf(Dealing{}),
 	}
 
 	for _, t := range types {
@@ -66,4 +72,70 @@ var _ = Describe("Surge marshalling", func() {
 			})
 		})
 	}
+
+	Context("unmarshalling into a provided slice", func() {
+		n, k := 10, 4
+		indices := RandomIndices(n)
+		h := PedersenH()
+
+		It("should decode shares into the backing array", func() {
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, indices, secp256k1.RandomFn(), k)).To(Succeed())
+			buf, err := surge.ToBinary(shares)
+			Expect(err).ToNot(HaveOccurred())
+
+			dst := make(Shares, 0, n)
+			decoded, tail, rem, err := UnmarshalSharesInto(dst, buf, len(buf))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tail).To(BeEmpty())
+			Expect(rem).To(BeZero())
+			Expect(decoded).To(HaveLen(n))
+			for i := range shares {
+				Expect(decoded[i].Eq(&shares[i])).To(BeTrue())
+			}
+			Expect(&decoded[0]).To(BeIdenticalTo(&dst[:1][0]))
+
+			// The slice is not allocated, and the only allocations are made
+			// by the decoding of the scalars.
+			allocs := testing.AllocsPerRun(10, func() {
+				_, _, _, _ = UnmarshalSharesInto(dst, buf, len(buf))
+			})
+			Expect(allocs).To(BeNumerically("<=", 2*n))
+		})
+
+		It("should decode verifiable shares into the backing array", func() {
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+			buf, err := surge.ToBinary(vshares)
+			Expect(err).ToNot(HaveOccurred())
+
+			dst := make(VerifiableShares, 0, n+1)
+			decoded, _, _, err := UnmarshalVSharesInto(dst, buf, len(buf))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded).To(HaveLen(n))
+			for i := range vshares {
+				Expect(decoded[i].Eq(&vshares[i])).To(BeTrue())
+			}
+			Expect(&decoded[0]).To(BeIdenticalTo(&dst[:1][0]))
+		})
+
+		It("should fail if the length is greater than the capacity", func() {
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, indices, secp256k1.RandomFn(), k)).To(Succeed())
+			buf, err := surge.ToBinary(shares)
+			Expect(err).ToNot(HaveOccurred())
+			decoded, _, _, err := UnmarshalSharesInto(make(Shares, 0, n-1), buf, len(buf))
+			Expect(errors.Is(err, ErrCapacity)).To(BeTrue())
+			Expect(decoded).To(BeEmpty())
+
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+			buf, err = surge.ToBinary(vshares)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, err = UnmarshalVSharesInto(nil, buf, len(buf))
+			Expect(errors.Is(err, ErrCapacity)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,234 @@
+// Package matrix implements matrices over the scalar field of secp256k1, with a
+// focus on the Vandermonde matrices that relate the coefficients of a
+// polynomial to its evaluations, and hence relate Shamir shares at different
+// sets of indices.
+package matrix
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// Matrix is a matrix over the scalar field of secp256k1, stored as a slice of
+// rows. All of the rows of a valid matrix have the same length.
+type Matrix [][]secp256k1.Fn
+
+// New constructs a zero matrix with the given number of rows and columns.
+func New(rows, cols int) Matrix {
+	m := make(Matrix, rows)
+	for i := range m {
+		m[i] = make([]secp256k1.Fn, cols)
+	}
+	return m
+}
+
+// Identity constructs the n x n identity matrix.
+func Identity(n int) Matrix {
+	m := New(n, n)
+	for i := range m {
+		m[i][i].SetU16(1)
+	}
+	return m
+}
+
+// Rows returns the number of rows of the matrix.
+func (m Matrix) Rows() int { return len(m) }
+
+// Cols returns the number of columns of the matrix.
+func (m Matrix) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// Eq returns true if the two matrices have the same dimensions and entries, and
+// false otherwise.
+func (m Matrix) Eq(other Matrix) bool {
+	if m.Rows() != other.Rows() || m.Cols() != other.Cols() {
+		return false
+	}
+	for i := range m {
+		for j := range m[i] {
+			if !m[i][j].Eq(&other[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Mul returns the product of the two matrices.
+//
+// Panics: This function will panic if the number of columns of m is not equal
+// to the number of rows of other.
+func (m Matrix) Mul(other Matrix) Matrix {
+	if m.Cols() != other.Rows() {
+		panic(fmt.Sprintf("cannot multiply %vx%v matrix by %vx%v matrix", m.Rows(), m.Cols(), other.Rows(), other.Cols()))
+	}
+	var term secp256k1.Fn
+	prod := New(m.Rows(), other.Cols())
+	for i := range prod {
+		for j := range prod[i] {
+			for k := range other {
+				term.Mul(&m[i][k], &other[k][j])
+				prod[i][j].Add(&prod[i][j], &term)
+			}
+		}
+	}
+	return prod
+}
+
+// MulVec returns the product of the matrix with the given column vector.
+//
+// Panics: This function will panic if the length of the vector is not equal to
+// the number of columns of the matrix.
+func (m Matrix) MulVec(v []secp256k1.Fn) []secp256k1.Fn {
+	if m.Cols() != len(v) {
+		panic(fmt.Sprintf("cannot multiply %vx%v matrix by vector of length %v", m.Rows(), m.Cols(), len(v)))
+	}
+	var term secp256k1.Fn
+	prod := make([]secp256k1.Fn, m.Rows())
+	for i := range prod {
+		for j := range v {
+			term.Mul(&m[i][j], &v[j])
+			prod[i].Add(&prod[i], &term)
+		}
+	}
+	return prod
+}
+
+// Vandermonde constructs the Vandermonde matrix for the given indices with the
+// given number of columns. That is, the entry in row i and column j is x_i^j,
+// where x_i is the i-th index. Multiplying this matrix by the coefficients of
+// a polynomial of degree less than cols gives the evaluations of the
+// polynomial at the indices.
+func Vandermonde(indices []secp256k1.Fn, cols int) Matrix {
+	m := New(len(indices), cols)
+	for i := range m {
+		for j := range m[i] {
+			if j == 0 {
+				m[i][j].SetU16(1)
+			} else {
+				m[i][j].Mul(&m[i][j-1], &indices[i])
+			}
+		}
+	}
+	return m
+}
+
+// VandermondeInverse constructs the inverse of the square Vandermonde matrix
+// for the given indices. Multiplying this matrix by the evaluations of a
+// polynomial of degree less than the number of indices at the indices gives
+// the coefficients of the polynomial. The j-th column of the inverse is the
+// coefficients of the j-th Lagrange basis polynomial, so this takes time
+// quadratic in the number of indices. An error is returned if the indices are
+// not distinct, in which case the matrix is singular.
+func VandermondeInverse(indices []secp256k1.Fn) (Matrix, error) {
+	n := len(indices)
+	if err := checkDistinct(indices); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return Matrix{}, nil
+	}
+
+	// The coefficients of the product of (x - x_i) over all indices.
+	var neg, tmp secp256k1.Fn
+	master := make([]secp256k1.Fn, n+1)
+	master[0].SetU16(1)
+	for i := range indices {
+		neg.Negate(&indices[i])
+		for j := i + 1; j > 0; j-- {
+			tmp.Mul(&master[j], &neg)
+			master[j].Add(&master[j-1], &tmp)
+		}
+		master[0].Mul(&master[0], &neg)
+	}
+
+	inv := New(n, n)
+	quot := make([]secp256k1.Fn, n)
+	var denom secp256k1.Fn
+	for i := range indices {
+		// Divide the master polynomial by (x - x_i), which leaves the
+		// product of (x - x_j) over all j != i, and evaluate this quotient at
+		// x_i to get the normalising denominator.
+		quot[n-1] = master[n]
+		for j := n - 1; j > 0; j-- {
+			tmp.Mul(&quot[j], &indices[i])
+			quot[j-1].Add(&master[j], &tmp)
+		}
+		denom = quot[n-1]
+		for j := n - 2; j >= 0; j-- {
+			denom.Mul(&denom, &indices[i])
+			denom.Add(&denom, &quot[j])
+		}
+		denom.Inverse(&denom)
+		for j := range quot {
+			inv[j][i].Mul(&quot[j], &denom)
+		}
+	}
+	return inv, nil
+}
+
+// ConversionMatrix constructs the matrix that maps the evaluations of a
+// polynomial of degree less than len(oldIndices) at the old indices to its
+// evaluations at the new indices. The entry in row j and column i is the i-th
+// Lagrange basis polynomial for the old indices evaluated at the j-th new
+// index. Since the i-th column only multiplies the i-th old share, each old
+// share holder can compute their contribution to every new share locally, and
+// the new shares are sums of these contributions, so that the underlying
+// secret is never reconstructed in one place. An error is returned if the old
+// indices are not distinct.
+func ConversionMatrix(oldIndices, newIndices []secp256k1.Fn) (Matrix, error) {
+	inv, err := VandermondeInverse(oldIndices)
+	if err != nil {
+		return nil, err
+	}
+	return Vandermonde(newIndices, len(oldIndices)).Mul(inv), nil
+}
+
+// ConvertShares re-encodes the given shares, which must have the old indices
+// in the same order, as shares with the new indices of the polynomial of
+// degree less than len(oldIndices) that they define. When the shares are from
+// a sharing with threshold k <= len(oldIndices), the new shares are shares of
+// the same secret with the same threshold. An error is returned if the shares
+// do not match the old indices or if the old indices are not distinct.
+//
+// This function computes all of the new shares at once; see ConversionMatrix
+// for converting shares that are held by different parties.
+func ConvertShares(oldIndices, newIndices []secp256k1.Fn, shares shamir.Shares) (shamir.Shares, error) {
+	if len(shares) != len(oldIndices) {
+		return nil, fmt.Errorf("expected %v shares, got %v", len(oldIndices), len(shares))
+	}
+	values := make([]secp256k1.Fn, len(shares))
+	for i := range shares {
+		if !shares[i].IndexEq(&oldIndices[i]) {
+			return nil, fmt.Errorf("share %v does not have the corresponding old index", i)
+		}
+		values[i] = shares[i].Value
+	}
+	conv, err := ConversionMatrix(oldIndices, newIndices)
+	if err != nil {
+		return nil, err
+	}
+	newValues := conv.MulVec(values)
+	newShares := make(shamir.Shares, len(newIndices))
+	for j := range newShares {
+		newShares[j] = shamir.NewShare(newIndices[j], newValues[j])
+	}
+	return newShares, nil
+}
+
+func checkDistinct(indices []secp256k1.Fn) error {
+	for i := range indices {
+		for j := 0; j < i; j++ {
+			if indices[i].Eq(&indices[j]) {
+				return fmt.Errorf("duplicate index at positions %v and %v", j, i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package matrix_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMatrix(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Matrix Suite")
+}
@@ -0,0 +1,164 @@
+package matrix_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/matrix"
+)
+
+var _ = Describe("Matrices", func() {
+	trials := 20
+	maxN := 15
+
+	randomVec := func(n int) []secp256k1.Fn {
+		v := make([]secp256k1.Fn, n)
+		for i := range v {
+			v[i] = secp256k1.RandomFn()
+		}
+		return v
+	}
+
+	Context("Vandermonde matrices", func() {
+		It("should evaluate polynomials at the indices", func() {
+			for i := 0; i < trials; i++ {
+				n, k := shamirutil.RandRange(1, maxN), shamirutil.RandRange(1, maxN)
+				indices := shamirutil.RandomIndices(n)
+				coeffs := randomVec(k)
+
+				evals := Vandermonde(indices, k).MulVec(coeffs)
+				for j := range indices {
+					var expected, pow, term secp256k1.Fn
+					pow.SetU16(1)
+					for l := range coeffs {
+						term.Mul(&coeffs[l], &pow)
+						expected.Add(&expected, &term)
+						pow.Mul(&pow, &indices[j])
+					}
+					Expect(evals[j].Eq(&expected)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should be inverted by the Vandermonde inverse", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, maxN)
+				indices := shamirutil.RandomIndices(n)
+				v := Vandermonde(indices, n)
+				inv, err := VandermondeInverse(indices)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v.Mul(inv).Eq(Identity(n))).To(BeTrue())
+				Expect(inv.Mul(v).Eq(Identity(n))).To(BeTrue())
+			}
+		})
+
+		It("should return an error for duplicate indices", func() {
+			indices := shamirutil.RandomIndices(5)
+			indices[3] = indices[1]
+			_, err := VandermondeInverse(indices)
+			Expect(err).To(HaveOccurred())
+			_, err = ConversionMatrix(indices, shamirutil.RandomIndices(5))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should invert the empty matrix", func() {
+			inv, err := VandermondeInverse(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv.Rows()).To(Equal(0))
+		})
+	})
+
+	Context("matrix arithmetic", func() {
+		It("should panic for mismatched dimensions", func() {
+			Expect(func() { New(2, 3).Mul(New(2, 3)) }).To(Panic())
+			Expect(func() { New(2, 3).MulVec(randomVec(2)) }).To(Panic())
+		})
+
+		It("should leave matrices unchanged when multiplying by the identity", func() {
+			m := Vandermonde(shamirutil.RandomIndices(4), 3)
+			Expect(Identity(4).Mul(m).Eq(m)).To(BeTrue())
+			Expect(m.Mul(Identity(3)).Eq(m)).To(BeTrue())
+		})
+	})
+
+	Context("share conversion", func() {
+		It("should give shares of the same secret at the new indices", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, maxN)
+				k := shamirutil.RandRange(1, n)
+				m := shamirutil.RandRange(k, maxN)
+				indices := shamirutil.RandomIndices(n + m)
+				oldIndices, newIndices := indices[:n], indices[n:]
+
+				secret := secp256k1.RandomFn()
+				coeffs := make([]secp256k1.Fn, k)
+				shares := make(shamir.Shares, n)
+				err := shamir.ShareAndGetCoeffs(&shares, coeffs, oldIndices, secret, k)
+				Expect(err).ToNot(HaveOccurred())
+
+				newShares, err := ConvertShares(oldIndices, newIndices, shares)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(newShares).To(HaveLen(m))
+
+				// The new shares lie on the original sharing polynomial.
+				evals := Vandermonde(newIndices, k).MulVec(coeffs)
+				for j := range newShares {
+					Expect(newShares[j].Index.Eq(&newIndices[j])).To(BeTrue())
+					Expect(newShares[j].Value.Eq(&evals[j])).To(BeTrue())
+				}
+				rand.Shuffle(len(newShares), func(a, b int) {
+					newShares[a], newShares[b] = newShares[b], newShares[a]
+				})
+				opened := shamir.Open(newShares[:k])
+				Expect(opened.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should allow each old share to be converted separately", func() {
+			n, m := 5, 7
+			indices := shamirutil.RandomIndices(n + m)
+			oldIndices, newIndices := indices[:n], indices[n:]
+			shares := make(shamir.Shares, n)
+			Expect(shamir.ShareSecret(&shares, oldIndices, secp256k1.RandomFn(), 3)).To(Succeed())
+
+			conv, err := ConversionMatrix(oldIndices, newIndices)
+			Expect(err).ToNot(HaveOccurred())
+
+			// Each old share holder computes their contributions, and each
+			// new share holder sums the contributions they receive.
+			sums := make([]secp256k1.Fn, m)
+			var contribution secp256k1.Fn
+			for i := range shares {
+				for j := range sums {
+					contribution.Mul(&conv[j][i], &shares[i].Value)
+					sums[j].Add(&sums[j], &contribution)
+				}
+			}
+
+			newShares, err := ConvertShares(oldIndices, newIndices, shares)
+			Expect(err).ToNot(HaveOccurred())
+			for j := range sums {
+				Expect(sums[j].Eq(&newShares[j].Value)).To(BeTrue())
+			}
+		})
+
+		It("should return an error when the shares do not match the old indices", func() {
+			indices := shamirutil.RandomIndices(8)
+			oldIndices, newIndices := indices[:4], indices[4:]
+			shares := make(shamir.Shares, 4)
+			Expect(shamir.ShareSecret(&shares, oldIndices, secp256k1.RandomFn(), 2)).To(Succeed())
+
+			_, err := ConvertShares(oldIndices, newIndices, shares[:3])
+			Expect(err).To(HaveOccurred())
+
+			shares[0], shares[1] = shares[1], shares[0]
+			_, err = ConvertShares(oldIndices, newIndices, shares)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,316 @@
+// Package merkle implements a Merkleized sharing mode, an alternative to the
+// Pedersen commitments of the shamir package for when the dealer is semi
+// trusted and checking a share must be extremely cheap. Instead of committing
+// to the coefficients of the sharing polynomial, which costs k point
+// operations per check, the dealer commits to the shares themselves as the
+// leaves of a Merkle tree, and gives each recipient an inclusion proof of
+// O(log n) hashes.
+//
+// An inclusion proof only shows that a share is the one that the dealer
+// committed to; it does not show that the committed shares lie on a
+// polynomial of degree less than k. That is checked by Audit, which verifies
+// the proofs of a set of opened shares and their consistency with each other,
+// for example when the shares are revealed at reconstruction or by an
+// auditor that is given all of them.
+//
+// The tree is the Merkle tree of RFC 9162 over SHA-256, so leaves and interior
+// nodes are hashed with different prefixes and any number of leaves is
+// supported. Each leaf is the hash of the index and value of the share and a
+// random salt, so that the root does not reveal anything about shares with
+// low entropy.
+package merkle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/surge"
+)
+
+// SaltSize is the number of bytes in the salt of a leaf.
+const SaltSize = 32
+
+// The prefixes that separate leaf and interior node hashes.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// CommitmentSize is the number of bytes in a marshalled Commitment.
+const CommitmentSize = sha256.Size + 2*surge.SizeHintU32
+
+// A Commitment is the root of the Merkle tree of a sharing, together with the
+// number of shares and the reconstruction threshold.
+type Commitment struct {
+	Root [sha256.Size]byte
+	N, K uint32
+}
+
+// A Proof shows that a share is a leaf of the Merkle tree of a sharing. It is
+// given to the recipient of the share together with the share.
+type Proof struct {
+	// Position is the position of the share in the dealing, which is the
+	// position of its leaf in the tree.
+	Position uint32
+	Salt     [SaltSize]byte
+	Path     [][sha256.Size]byte
+}
+
+// Deal commits to the given shares, which should be a sharing with threshold
+// k, and returns the commitment and the inclusion proof for each share. The
+// salts are read from shamir.RandomSource, and an error is returned if reading
+// from it fails.
+//
+// Panics: This function will panic if there are no shares or k is not between
+// 1 and the number of shares.
+func Deal(shares shamir.Shares, k int) (Commitment, []Proof, error) {
+	if len(shares) == 0 {
+		panic("cannot commit to an empty sharing")
+	}
+	if k < 1 || k > len(shares) {
+		panic(fmt.Sprintf("invalid threshold: expected 1 <= k <= %v, got k = %v", len(shares), k))
+	}
+	proofs := make([]Proof, len(shares))
+	leaves := make([][sha256.Size]byte, len(shares))
+	for i := range shares {
+		proofs[i].Position = uint32(i)
+		if _, err := io.ReadFull(shamir.RandomSource(), proofs[i].Salt[:]); err != nil {
+			return Commitment{}, nil, fmt.Errorf("could not generate salt: %w", err)
+		}
+		leaves[i] = leafHash(&shares[i], &proofs[i].Salt)
+	}
+	root := build(leaves, proofs)
+	return Commitment{Root: root, N: uint32(len(shares)), K: uint32(k)}, proofs, nil
+}
+
+// Verify returns true if the proof shows that the share is a leaf of the tree
+// with the given commitment, and false otherwise.
+func Verify(c *Commitment, share *shamir.Share, proof *Proof) bool {
+	if proof.Position >= c.N {
+		return false
+	}
+	fn, sn := proof.Position, c.N-1
+	r := leafHash(share, &proof.Salt)
+	for i := range proof.Path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(&proof.Path[i], &r)
+			if fn&1 == 0 {
+				for fn&1 == 0 && fn != 0 {
+					fn >>= 1
+					sn >>= 1
+				}
+			}
+		} else {
+			r = nodeHash(&r, &proof.Path[i])
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && r == c.Root
+}
+
+// Audit checks a set of opened shares against the commitment: every proof
+// must verify, no position may be opened twice, and the shares must lie on a
+// single polynomial of degree less than k. At least k shares must be given,
+// and the consistency check is only meaningful for more than k; when all n
+// shares are given, a successful audit shows that the dealing was a valid
+// sharing. An error describing the first problem found is returned if the
+// audit fails.
+func Audit(c *Commitment, shares shamir.Shares, proofs []Proof) error {
+	if len(shares) != len(proofs) {
+		return fmt.Errorf("expected %v proofs, got %v", len(shares), len(proofs))
+	}
+	k := int(c.K)
+	if k < 1 {
+		return fmt.Errorf("invalid threshold %v", k)
+	}
+	if len(shares) < k {
+		return fmt.Errorf("expected at least %v shares, got %v", k, len(shares))
+	}
+	opened := make(map[uint32]bool, len(shares))
+	for i := range shares {
+		if opened[proofs[i].Position] {
+			return fmt.Errorf("position %v opened more than once", proofs[i].Position)
+		}
+		opened[proofs[i].Position] = true
+		if !Verify(c, &shares[i], &proofs[i]) {
+			return fmt.Errorf("invalid proof for share at position %v", proofs[i].Position)
+		}
+	}
+
+	// Interpolate the first k shares and check that the rest agree. The
+	// indices are distinct, since the dealer must have committed to them.
+	indices := make([]secp256k1.Fn, k)
+	values := make([]secp256k1.Fn, k)
+	for i := 0; i < k; i++ {
+		indices[i], values[i] = shares[i].Index, shares[i].Value
+	}
+	for i := range indices {
+		for j := 0; j < i; j++ {
+			if indices[i].Eq(&indices[j]) {
+				return fmt.Errorf("duplicate index at positions %v and %v", proofs[j].Position, proofs[i].Position)
+			}
+		}
+	}
+	interp := poly.NewInterpolator(indices)
+	p := poly.NewWithCapacity(k)
+	interp.Interpolate(values, &p)
+	for i := k; i < len(shares); i++ {
+		eval := p.Evaluate(shares[i].Index)
+		if !eval.Eq(&shares[i].Value) {
+			return fmt.Errorf("share at position %v is not consistent with a sharing of threshold %v", proofs[i].Position, k)
+		}
+	}
+	return nil
+}
+
+func leafHash(share *shamir.Share, salt *[SaltSize]byte) [sha256.Size]byte {
+	var bs [1 + 2*secp256k1.FnSizeMarshalled + SaltSize]byte
+	bs[0] = leafPrefix
+	share.Index.PutB32(bs[1:])
+	share.Value.PutB32(bs[1+secp256k1.FnSizeMarshalled:])
+	copy(bs[1+2*secp256k1.FnSizeMarshalled:], salt[:])
+	return sha256.Sum256(bs[:])
+}
+
+func nodeHash(left, right *[sha256.Size]byte) [sha256.Size]byte {
+	var bs [1 + 2*sha256.Size]byte
+	bs[0] = nodePrefix
+	copy(bs[1:], left[:])
+	copy(bs[1+sha256.Size:], right[:])
+	return sha256.Sum256(bs[:])
+}
+
+// Returns the largest power of two that is less than n, for n > 1.
+func split(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// Computes the root of the tree with the given leaves, and appends the
+// siblings of each node on the way up to the paths of the leaves below it, so
+// that each path ends up ordered from the leaf up.
+func build(leaves [][sha256.Size]byte, proofs []Proof) [sha256.Size]byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	left, right := build(leaves[:k], proofs[:k]), build(leaves[k:], proofs[k:])
+	for i := range proofs[:k] {
+		proofs[i].Path = append(proofs[i].Path, right)
+	}
+	for i := range proofs[k:] {
+		proofs[k+i].Path = append(proofs[k+i].Path, left)
+	}
+	return nodeHash(&left, &right)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (c Commitment) SizeHint() int { return CommitmentSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (c Commitment) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < len(c.Root) || rem < len(c.Root) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(buf, c.Root[:])
+	buf, rem = buf[len(c.Root):], rem-len(c.Root)
+	buf, rem, err := surge.MarshalU32(c.N, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.MarshalU32(c.K, buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (c *Commitment) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < len(c.Root) || rem < len(c.Root) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(c.Root[:], buf)
+	buf, rem = buf[len(c.Root):], rem-len(c.Root)
+	buf, rem, err := surge.UnmarshalU32(&c.N, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.UnmarshalU32(&c.K, buf, rem)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (p Proof) SizeHint() int {
+	return surge.SizeHintU32 + SaltSize + surge.SizeHintU32 + sha256.Size*len(p.Path)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (p Proof) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(p.Position, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = putBytes(p.Salt[:], buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalLen(uint32(len(p.Path)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range p.Path {
+		buf, rem, err = putBytes(p.Path[i][:], buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (p *Proof) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.UnmarshalU32(&p.Position, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = getBytes(p.Salt[:], buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	buf, rem, err = surge.UnmarshalLen(&l, sha256.Size, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	p.Path = make([][sha256.Size]byte, l)
+	for i := range p.Path {
+		buf, rem, err = getBytes(p.Path[i][:], buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+func putBytes(src, buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < len(src) || rem < len(src) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(buf, src)
+	return buf[len(src):], rem - len(src), nil
+}
+
+func getBytes(dst, buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < len(dst) || rem < len(dst) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(dst, buf)
+	return buf[len(dst):], rem - len(dst), nil
+}
@@ -0,0 +1,165 @@
+package merkle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/merkle"
+)
+
+var _ = Describe("Merkleized sharing", func() {
+	trials := 20
+
+	deal := func(n, k int) (shamir.Shares, Commitment, []Proof) {
+		shares := make(shamir.Shares, n)
+		Expect(shamir.ShareSecret(&shares, shamirutil.RandomIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+		c, proofs, err := Deal(shares, k)
+		Expect(err).ToNot(HaveOccurred())
+		return shares, c, proofs
+	}
+
+	Context("inclusion proofs", func() {
+		It("should verify for every share and every size of tree", func() {
+			for n := 1; n <= 40; n++ {
+				shares, c, proofs := deal(n, shamirutil.RandRange(1, n))
+				Expect(c.N).To(Equal(uint32(n)))
+				for i := range shares {
+					Expect(Verify(&c, &shares[i], &proofs[i])).To(BeTrue())
+				}
+			}
+		})
+
+		It("should not verify for modified shares or proofs", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(2, 40)
+				shares, c, proofs := deal(n, shamirutil.RandRange(1, n))
+				j := shamirutil.RandRange(0, n-1)
+
+				share := shares[j]
+				share.Value = secp256k1.RandomFn()
+				Expect(Verify(&c, &share, &proofs[j])).To(BeFalse())
+				Expect(Verify(&c, &shares[(j+1)%n], &proofs[j])).To(BeFalse())
+
+				proof := proofs[j]
+				proof.Salt[0] ^= 1
+				Expect(Verify(&c, &shares[j], &proof)).To(BeFalse())
+
+				proof = proofs[j]
+				proof.Position = uint32((j + 1) % n)
+				Expect(Verify(&c, &shares[j], &proof)).To(BeFalse())
+				proof.Position = uint32(n)
+				Expect(Verify(&c, &shares[j], &proof)).To(BeFalse())
+
+				proof = proofs[j]
+				proof.Path = append([][32]byte{}, proofs[j].Path...)
+				proof.Path[0][0] ^= 1
+				Expect(Verify(&c, &shares[j], &proof)).To(BeFalse())
+				proof.Path = proofs[j].Path[:len(proofs[j].Path)-1]
+				Expect(Verify(&c, &shares[j], &proof)).To(BeFalse())
+			}
+		})
+
+		It("should compute the RFC 9162 tree root", func() {
+			shamir.SetRandomSource(bytes.NewReader(make([]byte, 5*SaltSize)))
+			defer shamir.SetRandomSource(nil)
+
+			shares := make(shamir.Shares, 5)
+			for i := range shares {
+				shares[i] = shamir.NewShare(secp256k1.NewFnFromU16(uint16(i+1)), secp256k1.NewFnFromU16(uint16(101+i)))
+			}
+			c, _, err := Deal(shares, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hex.EncodeToString(c.Root[:])).To(Equal("21d83e04caf9ac1b6ff0224305c2694459afdb58b233ebd264f7c3b695ed3c56"))
+		})
+	})
+
+	Context("auditing", func() {
+		It("should accept the opened shares of a valid dealing", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(1, 30)
+				k := shamirutil.RandRange(1, n)
+				shares, c, proofs := deal(n, k)
+				Expect(Audit(&c, shares, proofs)).To(Succeed())
+				Expect(Audit(&c, shares[:k], proofs[:k])).To(Succeed())
+			}
+		})
+
+		It("should reject a dealing with an inconsistent share", func() {
+			for i := 0; i < trials; i++ {
+				n := shamirutil.RandRange(3, 30)
+				k := shamirutil.RandRange(1, n-1)
+				shares := make(shamir.Shares, n)
+				Expect(shamir.ShareSecret(&shares, shamirutil.RandomIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+				shares[shamirutil.RandRange(0, n-1)].Value = secp256k1.RandomFn()
+				c, proofs, err := Deal(shares, k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(Audit(&c, shares, proofs)).ToNot(Succeed())
+			}
+		})
+
+		It("should reject invalid proofs, repeated positions and too few shares", func() {
+			n, k := 10, 4
+			shares, c, proofs := deal(n, k)
+
+			modified := append(shamir.Shares{}, shares...)
+			modified[5].Value = secp256k1.RandomFn()
+			Expect(Audit(&c, modified, proofs)).ToNot(Succeed())
+
+			repeated := append(shamir.Shares{}, shares[:k]...)
+			repeatedProofs := append([]Proof{}, proofs[:k]...)
+			repeated = append(repeated, shares[0])
+			repeatedProofs = append(repeatedProofs, proofs[0])
+			Expect(Audit(&c, repeated, repeatedProofs)).ToNot(Succeed())
+
+			Expect(Audit(&c, shares[:k-1], proofs[:k-1])).ToNot(Succeed())
+			Expect(Audit(&c, shares, proofs[1:])).ToNot(Succeed())
+		})
+	})
+
+	It("should marshal and unmarshal commitments and proofs", func() {
+		_, c, proofs := deal(13, 5)
+		bs, err := surge.ToBinary(c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(bs)).To(Equal(CommitmentSize))
+		var decodedC Commitment
+		Expect(surge.FromBinary(&decodedC, bs)).To(Succeed())
+		Expect(decodedC).To(Equal(c))
+
+		for i := range proofs {
+			bs, err := surge.ToBinary(proofs[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(bs)).To(Equal(proofs[i].SizeHint()))
+			var decoded Proof
+			Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+			Expect(decoded).To(Equal(proofs[i]))
+			for j := 0; j < len(bs); j++ {
+				Expect(surge.FromBinary(&decoded, bs[:j])).ToNot(Succeed())
+			}
+		}
+	})
+
+	It("should return an error when the source of randomness fails", func() {
+		shares, _, _ := deal(5, 2)
+		shamir.SetRandomSource(bytes.NewReader(make([]byte, SaltSize)))
+		defer shamir.SetRandomSource(nil)
+
+		_, _, err := Deal(shares, 2)
+		Expect(errors.Is(err, io.EOF)).To(BeTrue())
+	})
+
+	It("should panic for invalid thresholds", func() {
+		shares, _, _ := deal(5, 2)
+		Expect(func() { Deal(shares, 0) }).To(Panic())
+		Expect(func() { Deal(shares, 6) }).To(Panic())
+		Expect(func() { Deal(nil, 1) }).To(Panic())
+	})
+})
@@ -0,0 +1,168 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// A CurveID identifies the group that a sharing is over.
+type CurveID uint8
+
+// The groups that have an identifier. CurveSecp256k1 identifies the secp256k1
+// group, which is the group used by this package, and the others identify the
+// groups of the p256, bn254 and ristretto255 packages. Only secp256k1 can be
+// used in a SharingMetadata.
+const (
+	CurveSecp256k1    CurveID = 1
+	CurveP256         CurveID = 2
+	CurveBN254        CurveID = 3
+	CurveRistretto255 CurveID = 4
+)
+
+// SharingMetadataSize is the number of bytes in the surge encoding of a
+// SharingMetadata.
+const SharingMetadataSize = 2*surge.SizeHintU32 + surge.SizeHintU8 + 32
+
+// The tag that separates digests of Pedersen parameters from other digests.
+const pedersenHDigestTag = "renproject/shamir/pedersen h"
+
+// SharingMetadata describes the parameters of a verifiable sharing: the
+// number of shares N, the reconstruction threshold K, the group and a digest
+// of the Pedersen parameter h. Sending it alongside a commitment or shares
+// makes messages self-describing, so that a recipient using different
+// parameters gets an error instead of shares that silently fail to open.
+type SharingMetadata struct {
+	N       uint32
+	K       uint32
+	Curve   CurveID
+	HDigest [32]byte
+}
+
+// NewSharingMetadata returns the metadata for a sharing of n shares with
+// threshold k over secp256k1 with Pedersen parameter h.
+//
+// Panics: This function will panic if n or k is negative.
+func NewSharingMetadata(n, k int, h *secp256k1.Point) SharingMetadata {
+	if n < 0 || k < 0 {
+		panic(fmt.Sprintf("invalid parameters: n = %v, k = %v", n, k))
+	}
+	return SharingMetadata{
+		N:       uint32(n),
+		K:       uint32(k),
+		Curve:   CurveSecp256k1,
+		HDigest: PedersenHDigest(h),
+	}
+}
+
+// PedersenHDigest returns a canonical digest of the Pedersen parameter h. The
+// digest is the SHA-256 hash of tag || version || h, where tag is the string
+// "renproject/shamir/pedersen h" prefixed by its length as a 4 byte big endian
+// integer, version is DigestVersion as a single byte, and h is in its 33 byte
+// compressed form.
+func PedersenHDigest(h *secp256k1.Point) [32]byte {
+	d := newDigest(pedersenHDigestTag)
+	var bs [secp256k1.PointSizeMarshalled]byte
+	h.PutBytes(bs[:])
+	d.Write(bs[:])
+	return sum(d)
+}
+
+// Validate returns an error if the metadata does not describe a valid sharing,
+// which is the case if the threshold is not in the range 1 to N, or if the
+// curve is not one that this package supports.
+func (m SharingMetadata) Validate() error {
+	if m.K < 1 || m.K > m.N {
+		return fmt.Errorf("%w: expected 1 <= k <= %v, got k = %v", ErrInvalidThreshold, m.N, m.K)
+	}
+	if m.Curve != CurveSecp256k1 {
+		return fmt.Errorf("unsupported curve %v", m.Curve)
+	}
+	return nil
+}
+
+// CheckCommitment returns an error if the metadata is not valid, if the
+// commitment does not have K points, or if h is not the Pedersen parameter
+// that the metadata describes.
+func (m SharingMetadata) CheckCommitment(h *secp256k1.Point, c Commitment) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if uint32(c.Len()) != m.K {
+		return fmt.Errorf("commitment has %v points, expected k = %v", c.Len(), m.K)
+	}
+	if PedersenHDigest(h) != m.HDigest {
+		return fmt.Errorf("pedersen parameter does not match the metadata")
+	}
+	return nil
+}
+
+// CheckDealing returns an error under the same conditions as CheckCommitment,
+// and also if there are not N verifiable shares. It is intended for checking
+// a complete set of shares, for example by a dealer before sending them or by
+// a party that has collected all of them; a recipient of a single share
+// should use CheckCommitment.
+func (m SharingMetadata) CheckDealing(h *secp256k1.Point, c Commitment, vshares VerifiableShares) error {
+	if err := m.CheckCommitment(h, c); err != nil {
+		return err
+	}
+	if uint32(len(vshares)) != m.N {
+		return fmt.Errorf("got %v shares, expected n = %v", len(vshares), m.N)
+	}
+	return nil
+}
+
+// Eq returns true if the two metadata are equal, and false otherwise.
+func (m *SharingMetadata) Eq(other *SharingMetadata) bool {
+	return *m == *other
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (m SharingMetadata) SizeHint() int { return SharingMetadataSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (m SharingMetadata) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(m.N, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalU32(m.K, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalU8(uint8(m.Curve), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if len(buf) < len(m.HDigest) || rem < len(m.HDigest) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(buf, m.HDigest[:])
+	return buf[len(m.HDigest):], rem - len(m.HDigest), nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface. An error is returned
+// if the decoded metadata is not valid, as for Validate.
+func (m *SharingMetadata) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.UnmarshalU32(&m.N, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.UnmarshalU32(&m.K, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var curve uint8
+	buf, rem, err = surge.UnmarshalU8(&curve, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	m.Curve = CurveID(curve)
+	if len(buf) < len(m.HDigest) || rem < len(m.HDigest) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(m.HDigest[:], buf)
+	buf, rem = buf[len(m.HDigest):], rem-len(m.HDigest)
+	return buf, rem, m.Validate()
+}
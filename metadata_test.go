@@ -0,0 +1,87 @@
+package shamir_test
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Sharing metadata", func() {
+	trials := 20
+	h := PedersenH()
+
+	deal := func(n, k int) (VerifiableShares, Commitment) {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, SequentialIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares, c
+	}
+
+	It("should accept a dealing with matching parameters", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(1, 20)
+			k := RandRange(1, n)
+			vshares, c := deal(n, k)
+			m := NewSharingMetadata(n, k, &h)
+			Expect(m.Validate()).To(Succeed())
+			Expect(m.CheckCommitment(&h, c)).To(Succeed())
+			Expect(m.CheckDealing(&h, c, vshares)).To(Succeed())
+		}
+	})
+
+	It("should return an error for mismatched parameters", func() {
+		n, k := 10, 4
+		vshares, c := deal(n, k)
+		m := NewSharingMetadata(n, k, &h)
+
+		Expect(m.CheckCommitment(&h, c[:k-1])).ToNot(Succeed())
+		Expect(m.CheckDealing(&h, c, vshares[:n-1])).ToNot(Succeed())
+		other := PedersenHFromSeed([]byte("other"))
+		Expect(m.CheckCommitment(&other, c)).ToNot(Succeed())
+
+		for _, invalid := range []SharingMetadata{
+			NewSharingMetadata(n, 0, &h),
+			NewSharingMetadata(n, n+1, &h),
+			{N: uint32(n), K: uint32(k), Curve: CurveSecp256k1 + 1},
+		} {
+			Expect(invalid.Validate()).ToNot(Succeed())
+			Expect(invalid.CheckCommitment(&h, c)).ToNot(Succeed())
+		}
+	})
+
+	It("should unmarshal what was marshalled", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(1, 20)
+			m := NewSharingMetadata(n, RandRange(1, n), &h)
+			bs, err := surge.ToBinary(m)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bs).To(HaveLen(SharingMetadataSize))
+
+			var decoded SharingMetadata
+			Expect(DecodeLegacy(bs, &decoded)).To(Succeed())
+			Expect(decoded.Eq(&m)).To(BeTrue())
+
+			bs, err = EncodeV1(&m)
+			Expect(err).ToNot(HaveOccurred())
+			v, err := DecodeAny(bs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v).To(Equal(m))
+		}
+	})
+
+	It("should return an error when unmarshalling invalid metadata", func() {
+		m := NewSharingMetadata(3, 2, &h)
+		bs, err := surge.ToBinary(m)
+		Expect(err).ToNot(HaveOccurred())
+
+		var decoded SharingMetadata
+		Expect(DecodeLegacy(bs[:len(bs)-1], &decoded)).ToNot(Succeed())
+		invalid := append([]byte{}, bs...)
+		invalid[2*surge.SizeHintU32] = 0
+		Expect(DecodeLegacy(invalid, &decoded)).ToNot(Succeed())
+	})
+})
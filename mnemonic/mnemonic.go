@@ -0,0 +1,182 @@
+// Package mnemonic encodes shares as human transcribable word lists, so that
+// shares can be backed up on paper. The encoding is the one defined by BIP-39:
+// the data is followed by a checksum made of the first len(data)/4 bits of
+// its SHA-256 hash, and the result is split into 11 bit groups that each
+// select a word from the BIP-39 English word list. For 16 to 32 bytes of data
+// this is exactly a BIP-39 mnemonic, and the same rule is applied to the
+// longer encodings of shares.
+//
+// SLIP-0039 mnemonics are not supported, since that standard defines its own
+// sharing over GF(256) rather than encoding shares over the secp256k1 scalar
+// field.
+package mnemonic
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+const bitsPerWord = 11
+
+// MaxDataLen is the maximum number of bytes that can be encoded. This is
+// bounded by the number of checksum bits available in a SHA-256 hash.
+const MaxDataLen = 4 * 8 * sha256.Size
+
+var wordList = strings.Fields(englishWords)
+
+// ErrInvalidChecksum is returned when decoding a mnemonic whose checksum does
+// not match its data, which usually indicates a transcription error.
+var ErrInvalidChecksum = errors.New("invalid mnemonic checksum")
+
+// NumWords returns the number of words in the mnemonic for data with the
+// given number of bytes.
+func NumWords(dataLen int) int {
+	return (dataLen*8 + dataLen/4 + bitsPerWord - 1) / bitsPerWord
+}
+
+// Encode returns the mnemonic for the given data. The length of the data must
+// be a positive multiple of 4 bytes that is no greater than MaxDataLen.
+func Encode(data []byte) ([]string, error) {
+	if len(data) == 0 || len(data)%4 != 0 || len(data) > MaxDataLen {
+		return nil, fmt.Errorf(
+			"invalid data length: expected a positive multiple of 4 no greater than %v, got %v",
+			MaxDataLen, len(data),
+		)
+	}
+
+	hash := sha256.Sum256(data)
+	bits := append(append([]byte{}, data...), hash[:]...)
+	words := make([]string, NumWords(len(data)))
+	for i := range words {
+		words[i] = wordList[readBits(bits, i*bitsPerWord)]
+	}
+	return words, nil
+}
+
+// Decode returns the data encoded by the given mnemonic. Words are matched
+// case insensitively. An error is returned if a word is not in the word list,
+// the number of words does not correspond to a valid data length, or the
+// checksum does not match.
+func Decode(words []string) ([]byte, error) {
+	// The number of words determines the number of data bits d via
+	// 11w = d + d/32, so d = 32 * floor(11w / 33).
+	dataLen := 4 * (len(words) * bitsPerWord / 33)
+	if dataLen == 0 || dataLen > MaxDataLen || NumWords(dataLen) != len(words) {
+		return nil, fmt.Errorf("invalid number of words: %v", len(words))
+	}
+
+	bits := make([]byte, (len(words)*bitsPerWord+7)/8)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		j := sort.SearchStrings(wordList, word)
+		if j == len(wordList) || wordList[j] != word {
+			return nil, fmt.Errorf("invalid word %v: %q is not in the word list", i, word)
+		}
+		writeBits(bits, i*bitsPerWord, j)
+	}
+
+	data := bits[:dataLen]
+	hash := sha256.Sum256(data)
+	checksumBits := dataLen / 4
+	for i := 0; i < checksumBits; i++ {
+		if bit(bits, dataLen*8+i) != bit(hash[:], i) {
+			return nil, ErrInvalidChecksum
+		}
+	}
+	return append([]byte{}, data...), nil
+}
+
+// EncodeShare returns the mnemonic for the given share. The index is encoded
+// before the value, each as a 32 byte big endian integer, giving a mnemonic of
+// 48 words.
+func EncodeShare(share *shamir.Share) []string {
+	data := make([]byte, shamir.ShareSize)
+	share.Index.PutB32(data)
+	share.Value.PutB32(data[secp256k1.FnSizeMarshalled:])
+	words, err := Encode(data)
+	if err != nil {
+		panic(fmt.Sprintf("invariant violation: share encoding failed: %v", err))
+	}
+	return words
+}
+
+// DecodeShare returns the share encoded by the given mnemonic.
+func DecodeShare(words []string) (shamir.Share, error) {
+	data, err := Decode(words)
+	if err != nil {
+		return shamir.Share{}, err
+	}
+	if len(data) != shamir.ShareSize {
+		return shamir.Share{}, fmt.Errorf("invalid share length: expected %v bytes, got %v", shamir.ShareSize, len(data))
+	}
+
+	var share shamir.Share
+	if share.Index.SetB32(data) || share.Value.SetB32(data[secp256k1.FnSizeMarshalled:]) {
+		return shamir.Share{}, errors.New("invalid share: scalar overflows the field")
+	}
+	return share, nil
+}
+
+// EncodeVerifiableShare returns the mnemonic for the given verifiable share.
+// The share is encoded as for EncodeShare and is followed by the
+// decommitment, giving a mnemonic of 72 words.
+func EncodeVerifiableShare(vshare *shamir.VerifiableShare) []string {
+	data := make([]byte, shamir.VShareSize)
+	vshare.Share.Index.PutB32(data)
+	vshare.Share.Value.PutB32(data[secp256k1.FnSizeMarshalled:])
+	vshare.Decommitment.PutB32(data[shamir.ShareSize:])
+	words, err := Encode(data)
+	if err != nil {
+		panic(fmt.Sprintf("invariant violation: verifiable share encoding failed: %v", err))
+	}
+	return words
+}
+
+// DecodeVerifiableShare returns the verifiable share encoded by the given
+// mnemonic.
+func DecodeVerifiableShare(words []string) (shamir.VerifiableShare, error) {
+	data, err := Decode(words)
+	if err != nil {
+		return shamir.VerifiableShare{}, err
+	}
+	if len(data) != shamir.VShareSize {
+		return shamir.VerifiableShare{}, fmt.Errorf("invalid verifiable share length: expected %v bytes, got %v", shamir.VShareSize, len(data))
+	}
+
+	var vshare shamir.VerifiableShare
+	if vshare.Share.Index.SetB32(data) ||
+		vshare.Share.Value.SetB32(data[secp256k1.FnSizeMarshalled:]) ||
+		vshare.Decommitment.SetB32(data[shamir.ShareSize:]) {
+		return shamir.VerifiableShare{}, errors.New("invalid verifiable share: scalar overflows the field")
+	}
+	return vshare, nil
+}
+
+// Reads the 11 bit big endian integer starting at the given bit offset.
+func readBits(bs []byte, offset int) int {
+	v := 0
+	for i := 0; i < bitsPerWord; i++ {
+		v = v<<1 | int(bit(bs, offset+i))
+	}
+	return v
+}
+
+// Writes the 11 bit big endian integer starting at the given bit offset.
+func writeBits(bs []byte, offset, v int) {
+	for i := 0; i < bitsPerWord; i++ {
+		if v&(1<<(bitsPerWord-1-i)) != 0 {
+			pos := offset + i
+			bs[pos/8] |= 0x80 >> (pos % 8)
+		}
+	}
+}
+
+func bit(bs []byte, pos int) byte {
+	return (bs[pos/8] >> (7 - pos%8)) & 1
+}
@@ -0,0 +1,13 @@
+package mnemonic_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMnemonic(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Mnemonic Suite")
+}
@@ -0,0 +1,169 @@
+package mnemonic_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/mnemonic"
+)
+
+var _ = Describe("Mnemonic encoding", func() {
+	trials := 100
+
+	Context("BIP-39 compatibility", func() {
+		vectors := []struct {
+			data     []byte
+			mnemonic string
+		}{
+			{
+				bytes.Repeat([]byte{0x00}, 16),
+				"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			},
+			{
+				bytes.Repeat([]byte{0x7f}, 16),
+				"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			},
+			{
+				bytes.Repeat([]byte{0x00}, 32),
+				"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon " +
+					"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			},
+			{
+				bytes.Repeat([]byte{0xff}, 32),
+				"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			},
+		}
+
+		It("should match the BIP-39 test vectors", func() {
+			for _, vector := range vectors {
+				words, err := Encode(vector.data)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.Join(words, " ")).To(Equal(vector.mnemonic))
+
+				data, err := Decode(strings.Fields(vector.mnemonic))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(Equal(vector.data))
+			}
+		})
+	})
+
+	Context("encoding and decoding", func() {
+		It("should round trip arbitrary data", func() {
+			for i := 0; i < trials; i++ {
+				data := make([]byte, 4*(rand.Intn(MaxDataLen/4)+1))
+				rand.Read(data)
+
+				words, err := Encode(data)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(words).To(HaveLen(NumWords(len(data))))
+
+				decoded, err := Decode(words)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decoded).To(Equal(data))
+			}
+		})
+
+		It("should round trip shares", func() {
+			for i := 0; i < trials; i++ {
+				share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+				words := EncodeShare(&share)
+				Expect(words).To(HaveLen(48))
+
+				decoded, err := DecodeShare(words)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decoded.Eq(&share)).To(BeTrue())
+			}
+		})
+
+		It("should round trip verifiable shares", func() {
+			for i := 0; i < trials; i++ {
+				vshare := shamir.NewVerifiableShare(
+					shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+					secp256k1.RandomFn(),
+				)
+				words := EncodeVerifiableShare(&vshare)
+				Expect(words).To(HaveLen(72))
+
+				decoded, err := DecodeVerifiableShare(words)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(decoded.Eq(&vshare)).To(BeTrue())
+			}
+		})
+
+		It("should accept words in any case", func() {
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			words := EncodeShare(&share)
+			for i := range words {
+				words[i] = strings.ToUpper(words[i])
+			}
+
+			decoded, err := DecodeShare(words)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.Eq(&share)).To(BeTrue())
+		})
+	})
+
+	Context("invalid input", func() {
+		It("should reject data with an invalid length", func() {
+			for _, l := range []int{0, 1, 15, 17, MaxDataLen + 4} {
+				_, err := Encode(make([]byte, l))
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should reject an invalid number of words", func() {
+			for _, l := range []int{0, 1, 2, 4, 13} {
+				words := make([]string, l)
+				for i := range words {
+					words[i] = "abandon"
+				}
+				_, err := Decode(words)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should reject unknown words", func() {
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			words := EncodeShare(&share)
+			words[rand.Intn(len(words))] = "notaword"
+
+			_, err := DecodeShare(words)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should detect a swapped word with the checksum", func() {
+			detected, attempts := 0, 0
+			for i := 0; i < trials; i++ {
+				share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+				words := EncodeShare(&share)
+
+				// Swap two distinct adjacent words.
+				j := rand.Intn(len(words) - 1)
+				if words[j] == words[j+1] {
+					continue
+				}
+				words[j], words[j+1] = words[j+1], words[j]
+				attempts++
+
+				if _, err := DecodeShare(words); err == ErrInvalidChecksum {
+					detected++
+				}
+			}
+
+			// A 16 bit checksum misses a random error with probability 2^-16.
+			Expect(detected).To(BeNumerically(">=", attempts-2))
+		})
+
+		It("should reject the wrong kind of share", func() {
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			_, err := DecodeVerifiableShare(EncodeShare(&share))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,150 @@
+// Package mobile is a small facade over the sharing functions of this module
+// that can be bound with gomobile for use from iOS and Android. The API of the
+// other packages is built from slices of structs and pointers to them, which
+// gomobile can not bind, so every function here instead takes and returns
+// flat byte slices and integers.
+//
+// A curve is chosen with one of the constants Secp256k1 and P256. For both
+// curves, a scalar is encoded in 32 bytes big endian and must be less than the
+// order of the group, and a point is encoded in 33 bytes, as for the Marshal
+// methods of the shamir and p256 packages. The encodings are:
+//
+//	secret      a scalar
+//	share       index || value
+//	vshare      index || value || decommitment
+//	commitment  point || ... || point, one point for each of the k coefficients
+//
+// A list of shares, as returned by Split and accepted by Combine, is the
+// concatenation of the shares, so the i-th share of a list is
+// shares[i*ShareSize : (i+1)*ShareSize]; a list of verifiable shares is laid
+// out in the same way. The shares created by this package are indexed by 1,
+// 2, ..., n. Since a verifiable share starts with its share, the first
+// ShareSize bytes of each verifiable share can be given to Combine.
+package mobile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/shamir"
+)
+
+// The curves that are supported, which have the same values as the
+// corresponding shamir.CurveID.
+const (
+	Secp256k1 = int(shamir.CurveSecp256k1)
+	P256      = int(shamir.CurveP256)
+)
+
+// The sizes in bytes of the encodings, which are the same for both curves.
+const (
+	ScalarSize = 32
+	PointSize  = 33
+	ShareSize  = 2 * ScalarSize
+	VShareSize = 3 * ScalarSize
+)
+
+// MaxShares is the largest number of shares that Split and VSplit create.
+const MaxShares = 1<<16 - 1
+
+// A VerifiableSharing is the result of VSplit: a list of verifiable shares and
+// the commitment that they can be verified against.
+type VerifiableSharing struct {
+	Shares     []byte
+	Commitment []byte
+}
+
+// Split creates n shares of the secret over the given curve, any k of which
+// can be combined to recover the secret, and returns them as a list.
+func Split(curve int, secret []byte, n, k int) ([]byte, error) {
+	s, err := schemeFor(curve)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParams(secret, n, k); err != nil {
+		return nil, err
+	}
+	return s.split(secret, n, k)
+}
+
+// Combine recovers the secret from a list of at least k shares of a sharing
+// over the given curve with threshold k. The shares must not have been
+// modified, since an invalid share silently gives a different secret; use
+// Verify on verifiable shares to check them first. An error is returned if
+// the list is empty or can not be decoded, or if two shares have the same
+// index.
+func Combine(curve int, shares []byte) ([]byte, error) {
+	s, err := schemeFor(curve)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 || len(shares)%ShareSize != 0 {
+		return nil, fmt.Errorf("invalid length of shares: expected a non-zero multiple of %v bytes, got %v", ShareSize, len(shares))
+	}
+	return s.combine(shares)
+}
+
+// VSplit creates n Pedersen verifiable shares of the secret over the given
+// curve, any k of which can be combined to recover the secret, together with
+// the commitment to the sharing. The Pedersen parameter is the PedersenH of
+// the package for the curve.
+func VSplit(curve int, secret []byte, n, k int) (*VerifiableSharing, error) {
+	s, err := schemeFor(curve)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParams(secret, n, k); err != nil {
+		return nil, err
+	}
+	return s.vsplit(secret, n, k)
+}
+
+// Verify returns whether the verifiable share is valid with respect to the
+// commitment, for a sharing over the given curve created by VSplit. An error
+// is returned, rather than false, if the share or the commitment can not be
+// decoded.
+func Verify(curve int, vshare, commitment []byte) (bool, error) {
+	s, err := schemeFor(curve)
+	if err != nil {
+		return false, err
+	}
+	if len(vshare) != VShareSize {
+		return false, fmt.Errorf("invalid length of verifiable share: expected %v bytes, got %v", VShareSize, len(vshare))
+	}
+	if len(commitment) == 0 || len(commitment)%PointSize != 0 {
+		return false, fmt.Errorf("invalid length of commitment: expected a non-zero multiple of %v bytes, got %v", PointSize, len(commitment))
+	}
+	return s.verify(vshare, commitment)
+}
+
+// A scheme implements the exported functions for one curve. The lengths of
+// the arguments and the parameters have already been checked.
+type scheme interface {
+	split(secret []byte, n, k int) ([]byte, error)
+	combine(shares []byte) ([]byte, error)
+	vsplit(secret []byte, n, k int) (*VerifiableSharing, error)
+	verify(vshare, commitment []byte) (bool, error)
+}
+
+func schemeFor(curve int) (scheme, error) {
+	switch curve {
+	case Secp256k1:
+		return secp256k1Scheme{}, nil
+	case P256:
+		return p256Scheme{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %v", curve)
+	}
+}
+
+func checkParams(secret []byte, n, k int) error {
+	if len(secret) != ScalarSize {
+		return fmt.Errorf("invalid length of secret: expected %v bytes, got %v", ScalarSize, len(secret))
+	}
+	if k < 1 || k > n || n > MaxShares {
+		return fmt.Errorf("invalid parameters: n = %v, k = %v", n, k)
+	}
+	return nil
+}
+
+var errDuplicateIndex = errors.New("two shares have the same index")
@@ -0,0 +1,159 @@
+package mobile_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/p256"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/mobile"
+)
+
+var _ = Describe("Mobile API", func() {
+	trials := 10
+
+	randomSecret := func(curve int) []byte {
+		secret := make([]byte, ScalarSize)
+		if curve == Secp256k1 {
+			s := secp256k1.RandomFn()
+			s.PutB32(secret)
+		} else {
+			s := p256.RandomScalar()
+			s.PutBytes(secret)
+		}
+		return secret
+	}
+
+	// Returns a random subset of m of the n shares in the list, with the
+	// given size of each share.
+	subset := func(shares []byte, size, n, m int) []byte {
+		buf := make([]byte, 0, m*size)
+		for _, i := range rand.Perm(n)[:m] {
+			buf = append(buf, shares[i*size:(i+1)*size]...)
+		}
+		return buf
+	}
+
+	for _, curve := range []int{Secp256k1, P256} {
+		curve := curve
+
+		Context(map[int]string{Secp256k1: "secp256k1", P256: "P-256"}[curve], func() {
+			It("should recover the secret from any k shares", func() {
+				for i := 0; i < trials; i++ {
+					n := shamirutil.RandRange(1, 20)
+					k := shamirutil.RandRange(1, n)
+					secret := randomSecret(curve)
+
+					shares, err := Split(curve, secret, n, k)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(shares).To(HaveLen(n * ShareSize))
+
+					recon, err := Combine(curve, subset(shares, ShareSize, n, shamirutil.RandRange(k, n)))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recon).To(Equal(secret))
+				}
+			})
+
+			It("should create verifiable shares that verify and combine", func() {
+				for i := 0; i < trials; i++ {
+					n := shamirutil.RandRange(1, 20)
+					k := shamirutil.RandRange(1, n)
+					secret := randomSecret(curve)
+
+					sharing, err := VSplit(curve, secret, n, k)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sharing.Shares).To(HaveLen(n * VShareSize))
+					Expect(sharing.Commitment).To(HaveLen(k * PointSize))
+
+					shares := make([]byte, 0, n*ShareSize)
+					for j := 0; j < n; j++ {
+						vshare := sharing.Shares[j*VShareSize : (j+1)*VShareSize]
+						ok, err := Verify(curve, vshare, sharing.Commitment)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(ok).To(BeTrue())
+						shares = append(shares, vshare[:ShareSize]...)
+					}
+
+					recon, err := Combine(curve, subset(shares, ShareSize, n, k))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(recon).To(Equal(secret))
+				}
+			})
+
+			It("should not verify a modified share", func() {
+				sharing, err := VSplit(curve, randomSecret(curve), 5, 3)
+				Expect(err).ToNot(HaveOccurred())
+				vshare := append([]byte{}, sharing.Shares[:VShareSize]...)
+				vshare[ShareSize-1] ^= 1
+				ok, err := Verify(curve, vshare, sharing.Commitment)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should return errors for invalid arguments", func() {
+				secret := randomSecret(curve)
+				_, err := Split(curve, secret[1:], 5, 3)
+				Expect(err).To(HaveOccurred())
+				_, err = Split(curve, secret, 3, 5)
+				Expect(err).To(HaveOccurred())
+				_, err = VSplit(curve, secret, 5, 0)
+				Expect(err).To(HaveOccurred())
+
+				overflow := make([]byte, ScalarSize)
+				for i := range overflow {
+					overflow[i] = 0xFF
+				}
+				_, err = Split(curve, overflow, 5, 3)
+				Expect(err).To(HaveOccurred())
+
+				shares, err := Split(curve, secret, 5, 3)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = Combine(curve, nil)
+				Expect(err).To(HaveOccurred())
+				_, err = Combine(curve, shares[1:])
+				Expect(err).To(HaveOccurred())
+				_, err = Combine(curve, append(shares[:ShareSize:ShareSize], shares[:ShareSize]...))
+				Expect(err).To(HaveOccurred())
+				zeroIndex := append([]byte{}, shares[:ShareSize]...)
+				copy(zeroIndex, make([]byte, ScalarSize))
+				_, err = Combine(curve, zeroIndex)
+				Expect(err).To(HaveOccurred())
+
+				sharing, err := VSplit(curve, secret, 5, 3)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = Verify(curve, sharing.Shares[:ShareSize], sharing.Commitment)
+				Expect(err).To(HaveOccurred())
+				_, err = Verify(curve, sharing.Shares[:VShareSize], sharing.Commitment[1:])
+				Expect(err).To(HaveOccurred())
+				// There is no point with x coordinate 7 on either curve.
+				badPoint := append([]byte{}, sharing.Commitment...)
+				copy(badPoint, make([]byte, PointSize))
+				badPoint[0], badPoint[PointSize-1] = 0x02, 7
+				_, err = Verify(curve, sharing.Shares[:VShareSize], badPoint)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	}
+
+	It("should agree with the shamir package for secp256k1", func() {
+		sharing, err := VSplit(Secp256k1, randomSecret(Secp256k1), 5, 3)
+		Expect(err).ToNot(HaveOccurred())
+		var vshare shamir.VerifiableShare
+		_, _, err = vshare.Unmarshal(sharing.Shares[:VShareSize], VShareSize)
+		Expect(err).ToNot(HaveOccurred())
+		c := make(shamir.Commitment, 3)
+		for i := range c {
+			Expect(c[i].SetBytes(sharing.Commitment[i*PointSize:])).To(Succeed())
+		}
+		Expect(shamir.IsValid(shamir.PedersenH(), &c, &vshare)).To(BeTrue())
+	})
+
+	It("should return an error for an unsupported curve", func() {
+		_, err := Split(3, make([]byte, ScalarSize), 5, 3)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,136 @@
+package mobile
+
+import (
+	"errors"
+
+	"github.com/renproject/shamir/p256"
+)
+
+type p256Scheme struct{}
+
+func (p256Scheme) split(secret []byte, n, k int) ([]byte, error) {
+	var s p256.Scalar
+	defer s.Clear()
+	if err := s.SetBytes(secret); err != nil {
+		return nil, err
+	}
+	shares := make(p256.Shares, n)
+	defer wipeP256Shares(shares)
+	if err := p256.ShareSecret(&shares, p256Indices(n), s, k); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, n*ShareSize)
+	for i := range shares {
+		putP256Share(buf[i*ShareSize:], &shares[i])
+	}
+	return buf, nil
+}
+
+func (p256Scheme) combine(buf []byte) ([]byte, error) {
+	shares := make(p256.Shares, len(buf)/ShareSize)
+	defer wipeP256Shares(shares)
+	for i := range shares {
+		if err := setP256Share(&shares[i], buf[i*ShareSize:]); err != nil {
+			return nil, err
+		}
+		for j := 0; j < i; j++ {
+			if shares[i].IndexEq(&shares[j].Index) {
+				return nil, errDuplicateIndex
+			}
+		}
+	}
+
+	secret := p256.Open(shares)
+	defer secret.Clear()
+	res := make([]byte, ScalarSize)
+	secret.PutBytes(res)
+	return res, nil
+}
+
+func (p256Scheme) vsplit(secret []byte, n, k int) (*VerifiableSharing, error) {
+	var s p256.Scalar
+	defer s.Clear()
+	if err := s.SetBytes(secret); err != nil {
+		return nil, err
+	}
+	vshares := make(p256.VerifiableShares, n)
+	defer func() {
+		for i := range vshares {
+			vshares[i].Share.Index.Clear()
+			vshares[i].Share.Value.Clear()
+			vshares[i].Decommitment.Clear()
+		}
+	}()
+	c := p256.NewCommitmentWithCapacity(k)
+	if err := p256.VShareSecret(&vshares, &c, p256Indices(n), p256.PedersenH(), s, k); err != nil {
+		return nil, err
+	}
+
+	sharing := &VerifiableSharing{
+		Shares:     make([]byte, n*VShareSize),
+		Commitment: make([]byte, k*PointSize),
+	}
+	for i := range vshares {
+		dst := sharing.Shares[i*VShareSize:]
+		putP256Share(dst, &vshares[i].Share)
+		vshares[i].Decommitment.PutBytes(dst[ShareSize:])
+	}
+	for i := range c {
+		c[i].PutBytes(sharing.Commitment[i*PointSize:])
+	}
+	return sharing, nil
+}
+
+func (p256Scheme) verify(buf, commitment []byte) (bool, error) {
+	var vshare p256.VerifiableShare
+	defer func() {
+		vshare.Share.Index.Clear()
+		vshare.Share.Value.Clear()
+		vshare.Decommitment.Clear()
+	}()
+	if err := setP256Share(&vshare.Share, buf); err != nil {
+		return false, err
+	}
+	if err := vshare.Decommitment.SetBytes(buf[ShareSize:VShareSize]); err != nil {
+		return false, err
+	}
+	c := make(p256.Commitment, len(commitment)/PointSize)
+	for i := range c {
+		if err := c[i].SetBytes(commitment[i*PointSize : (i+1)*PointSize]); err != nil {
+			return false, err
+		}
+	}
+	return p256.IsValid(p256.PedersenH(), c, &vshare), nil
+}
+
+// Returns the indices 1, 2, ..., n.
+func p256Indices(n int) []p256.Scalar {
+	indices := make([]p256.Scalar, n)
+	for i := range indices {
+		indices[i].SetU16(uint16(i + 1))
+	}
+	return indices
+}
+
+func setP256Share(s *p256.Share, bs []byte) error {
+	if err := s.Index.SetBytes(bs[:ScalarSize]); err != nil {
+		return err
+	}
+	if s.Index.IsZero() {
+		return errors.New("share has index zero")
+	}
+	return s.Value.SetBytes(bs[ScalarSize:ShareSize])
+}
+
+func putP256Share(dst []byte, s *p256.Share) {
+	s.Index.PutBytes(dst)
+	s.Value.PutBytes(dst[ScalarSize:])
+}
+
+func wipeP256Shares(shares p256.Shares) {
+	for i := range shares {
+		shares[i].Index.Clear()
+		shares[i].Value.Clear()
+	}
+}
@@ -0,0 +1,127 @@
+package mobile
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+type secp256k1Scheme struct{}
+
+func (secp256k1Scheme) split(secret []byte, n, k int) ([]byte, error) {
+	var s secp256k1.Fn
+	defer s.Clear()
+	if err := setFn(&s, secret); err != nil {
+		return nil, err
+	}
+	shares := make(shamir.Shares, n)
+	defer shares.Zero()
+	if err := shamir.ShareSecret(&shares, fnIndices(n), s, k); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, n*ShareSize)
+	for i := range shares {
+		putShare(buf[i*ShareSize:], &shares[i])
+	}
+	return buf, nil
+}
+
+func (secp256k1Scheme) combine(buf []byte) ([]byte, error) {
+	shares := make(shamir.Shares, len(buf)/ShareSize)
+	defer shares.Zero()
+	for i := range shares {
+		if err := setShare(&shares[i], buf[i*ShareSize:]); err != nil {
+			return nil, err
+		}
+		for j := 0; j < i; j++ {
+			if shares[i].IndexEq(&shares[j].Index) {
+				return nil, errDuplicateIndex
+			}
+		}
+	}
+
+	secret := shamir.Open(shares)
+	defer secret.Clear()
+	res := make([]byte, ScalarSize)
+	secret.PutB32(res)
+	return res, nil
+}
+
+func (secp256k1Scheme) vsplit(secret []byte, n, k int) (*VerifiableSharing, error) {
+	var s secp256k1.Fn
+	defer s.Clear()
+	if err := setFn(&s, secret); err != nil {
+		return nil, err
+	}
+	vshares := make(shamir.VerifiableShares, n)
+	defer vshares.Zero()
+	c := shamir.NewCommitmentWithCapacity(k)
+	if err := shamir.VShareSecret(&vshares, &c, fnIndices(n), shamir.PedersenH(), s, k); err != nil {
+		return nil, err
+	}
+
+	sharing := &VerifiableSharing{
+		Shares:     make([]byte, n*VShareSize),
+		Commitment: make([]byte, k*PointSize),
+	}
+	for i := range vshares {
+		dst := sharing.Shares[i*VShareSize:]
+		putShare(dst, &vshares[i].Share)
+		vshares[i].Decommitment.PutB32(dst[ShareSize:])
+	}
+	for i := range c {
+		c[i].PutBytes(sharing.Commitment[i*PointSize:])
+	}
+	return sharing, nil
+}
+
+func (secp256k1Scheme) verify(buf, commitment []byte) (bool, error) {
+	var vshare shamir.VerifiableShare
+	defer vshare.Zero()
+	if err := setShare(&vshare.Share, buf); err != nil {
+		return false, err
+	}
+	if err := setFn(&vshare.Decommitment, buf[ShareSize:]); err != nil {
+		return false, err
+	}
+	c := make(shamir.Commitment, len(commitment)/PointSize)
+	for i := range c {
+		if err := c[i].SetBytes(commitment[i*PointSize : (i+1)*PointSize]); err != nil {
+			return false, err
+		}
+	}
+	return shamir.IsValid(shamir.PedersenH(), &c, &vshare), nil
+}
+
+// Returns the indices 1, 2, ..., n.
+func fnIndices(n int) []secp256k1.Fn {
+	indices := make([]secp256k1.Fn, n)
+	for i := range indices {
+		indices[i].SetU16(uint16(i + 1))
+	}
+	return indices
+}
+
+func setFn(x *secp256k1.Fn, bs []byte) error {
+	if x.SetB32(bs[:ScalarSize]) {
+		return errors.New("scalar is not less than the order")
+	}
+	return nil
+}
+
+func setShare(s *shamir.Share, bs []byte) error {
+	if err := setFn(&s.Index, bs); err != nil {
+		return err
+	}
+	if s.Index.IsZero() {
+		return errors.New("share has index zero")
+	}
+	return setFn(&s.Value, bs[ScalarSize:])
+}
+
+func putShare(dst []byte, s *shamir.Share) {
+	s.Index.PutB32(dst)
+	s.Value.PutB32(dst[ScalarSize:])
+}
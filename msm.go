@@ -0,0 +1,216 @@
+package shamir
+
+import (
+	"math/bits"
+
+	"github.com/renproject/secp256k1"
+)
+
+// The number of bits of a scalar that are processed at a time in a
+// multi-scalar multiplication.
+const msmWindow = 4
+
+// The minimum length of a commitment for which evaluation uses a multi-scalar
+// multiplication instead of Horner's method. Below this, the cost of the
+// doublings and tables outweighs the saved scalar multiplications.
+const msmThreshold = 5
+
+// The minimum length of a commitment for which EvaluateMSM uses Pippenger's
+// method instead of Straus' method. Pippenger's method needs no tables, and
+// its cost per point falls as the number of points grows, so it wins for long
+// commitments.
+const pippengerThreshold = 256
+
+// A pointTable holds the multiples 0*P, 1*P, ..., 15*P of a point P, which
+// are the values that are added in a single window of a multi-scalar
+// multiplication.
+type pointTable [1 << msmWindow]secp256k1.Point
+
+func newPointTable(p *secp256k1.Point) pointTable {
+	var table pointTable
+	table[0] = secp256k1.NewPointInfinity()
+	table[1] = *p
+	for i := 2; i < len(table); i++ {
+		table[i].Add(&table[i-1], p)
+	}
+	return table
+}
+
+// Computes the sum of scalars[i]*P_i, where tables[i] is the table for P_i,
+// using Straus' method: the windows of all of the scalars are processed
+// together from most to least significant, so that the doublings are shared.
+// This is not constant time with respect to the scalars.
+func msm(dst *secp256k1.Point, tables []pointTable, scalars []secp256k1.Fn) {
+	bs := make([][32]byte, len(scalars))
+	for i := range scalars {
+		scalars[i].PutB32(bs[i][:])
+	}
+
+	acc := secp256k1.NewPointInfinity()
+	for w := 256/msmWindow - 1; w >= 0; w-- {
+		if !acc.IsInfinity() {
+			for j := 0; j < msmWindow; j++ {
+				acc.Add(&acc, &acc)
+			}
+		}
+		for i := range tables {
+			if digit := window(&bs[i], w); digit != 0 {
+				acc.Add(&acc, &tables[i][digit])
+			}
+		}
+	}
+	*dst = acc
+}
+
+// Returns the value of the w-th window of the big endian scalar, where the
+// 0-th window is the least significant.
+func window(bs *[32]byte, w int) byte {
+	b := bs[31-w/2]
+	if w%2 == 1 {
+		return b >> 4
+	}
+	return b & 0x0F
+}
+
+// Computes the sum of scalars[i]*points[i] using Pippenger's bucket method:
+// for each window of c bits, from most to least significant, every point is
+// added to the bucket for its digit, and the buckets are combined with a
+// running sum so that bucket d is counted d times. This takes about
+// (256/c)(n + 2^(c+1)) additions for n points, compared to 64n for Straus'
+// method with 4 bit windows, and is not constant time with respect to the
+// scalars.
+func msmPippenger(dst *secp256k1.Point, points []secp256k1.Point, scalars []secp256k1.Fn) {
+	bs := make([][32]byte, len(scalars))
+	for i := range scalars {
+		scalars[i].PutB32(bs[i][:])
+	}
+
+	// The window size that roughly minimises the number of additions.
+	c := bits.Len(uint(len(points))) - 3
+	if c < 2 {
+		c = 2
+	}
+	buckets := make([]secp256k1.Point, 1<<c)
+	acc := secp256k1.NewPointInfinity()
+	var sum, windowSum secp256k1.Point
+	for w := (256+c-1)/c - 1; w >= 0; w-- {
+		if !acc.IsInfinity() {
+			for j := 0; j < c; j++ {
+				acc.Add(&acc, &acc)
+			}
+		}
+		for d := range buckets {
+			buckets[d] = secp256k1.NewPointInfinity()
+		}
+		for i := range points {
+			if digit := bitsAt(&bs[i], w*c, c); digit != 0 {
+				buckets[digit].Add(&buckets[digit], &points[i])
+			}
+		}
+		sum, windowSum = secp256k1.NewPointInfinity(), secp256k1.NewPointInfinity()
+		for d := len(buckets) - 1; d > 0; d-- {
+			sum.Add(&sum, &buckets[d])
+			windowSum.Add(&windowSum, &sum)
+		}
+		acc.Add(&acc, &windowSum)
+	}
+	*dst = acc
+}
+
+// Returns the c bits of the big endian scalar starting at the given bit
+// offset, where bit 0 is the least significant. Bits beyond the most
+// significant are zero.
+func bitsAt(bs *[32]byte, offset, c int) int {
+	digit := 0
+	for i := c - 1; i >= 0; i-- {
+		bit := offset + i
+		digit <<= 1
+		if bit < 256 {
+			digit |= int(bs[31-bit/8]>>(bit%8)) & 1
+		}
+	}
+	return digit
+}
+
+// Computes the sum of scalars[i]*points[i], using Pippenger's method for many
+// points and Straus' method, with tables built for this call, otherwise.
+func msmPoints(dst *secp256k1.Point, points []secp256k1.Point, scalars []secp256k1.Fn) {
+	if len(points) >= pippengerThreshold {
+		msmPippenger(dst, points, scalars)
+		return
+	}
+	tables := make([]pointTable, len(points))
+	for i := range points {
+		tables[i] = newPointTable(&points[i])
+	}
+	msm(dst, tables, scalars)
+}
+
+// Sets the given slice to the powers 1, x, x^2, ... of x.
+func powers(dst []secp256k1.Fn, x *secp256k1.Fn) {
+	if len(dst) == 0 {
+		return
+	}
+	dst[0].SetU16(1)
+	for i := 1; i < len(dst); i++ {
+		dst[i].Mul(&dst[i-1], x)
+	}
+}
+
+// EvaluateMSM returns the same result as Evaluate, but evaluates the
+// commitment as a single multi-scalar multiplication of the commitment points
+// by the powers of the index, which is faster than Evaluate for all but the
+// shortest commitments. Long commitments, such as those of sharings with
+// thresholds in the thousands, are evaluated with Pippenger's method. When the
+// same commitment is evaluated many times, a CommitmentTable avoids
+// recomputing the tables for each evaluation.
+//
+// Panics: This function will panic if the commitment is empty.
+func (c Commitment) EvaluateMSM(index *secp256k1.Fn) secp256k1.Point {
+	if len(c) == 0 {
+		panic("cannot evaluate an empty commitment")
+	}
+	scalars := make([]secp256k1.Fn, len(c))
+	powers(scalars, index)
+	var eval secp256k1.Point
+	msmPoints(&eval, c, scalars)
+	return eval
+}
+
+// A CommitmentTable holds precomputed multiples of the points of a
+// commitment, so that the commitment can be evaluated repeatedly without the
+// setup cost of EvaluateMSM.
+type CommitmentTable struct {
+	tables []pointTable
+}
+
+// NewCommitmentTable precomputes the tables for the given commitment. The
+// commitment is not referenced after this function returns, and so is safe to
+// modify.
+func NewCommitmentTable(c Commitment) CommitmentTable {
+	tables := make([]pointTable, len(c))
+	for i := range c {
+		tables[i] = newPointTable(&c[i])
+	}
+	return CommitmentTable{tables: tables}
+}
+
+// Len returns the number of points in the commitment from which the table
+// was constructed.
+func (t *CommitmentTable) Len() int { return len(t.tables) }
+
+// Evaluate returns the commitment evaluated at the given index "in the
+// exponent", as for Commitment.Evaluate.
+//
+// Panics: This function will panic if the table was constructed from an empty
+// commitment.
+func (t *CommitmentTable) Evaluate(index *secp256k1.Fn) secp256k1.Point {
+	if len(t.tables) == 0 {
+		panic("cannot evaluate an empty commitment")
+	}
+	scalars := make([]secp256k1.Fn, len(t.tables))
+	powers(scalars, index)
+	var eval secp256k1.Point
+	msm(&eval, t.tables, scalars)
+	return eval
+}
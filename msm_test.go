@@ -0,0 +1,122 @@
+package shamir_test
+
+import (
+	"testing"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+// Evaluates the commitment directly as the sum of c[i]*index^i.
+func evaluateNaive(c Commitment, index *secp256k1.Fn) secp256k1.Point {
+	eval := secp256k1.NewPointInfinity()
+	var pow secp256k1.Fn
+	pow.SetU16(1)
+	for i := range c {
+		var term secp256k1.Point
+		term.ScaleExt(&c[i], &pow)
+		eval.Add(&eval, &term)
+		pow.Mul(&pow, index)
+	}
+	return eval
+}
+
+var _ = Describe("Multi-scalar multiplication", func() {
+	trials := 20
+	maxK := 40
+
+	It("should agree with direct evaluation", func() {
+		for i := 0; i < trials; i++ {
+			c := RandomCommitment(RandRange(1, maxK))
+			index := secp256k1.RandomFn()
+			expected := evaluateNaive(c, &index)
+
+			eval := c.EvaluateMSM(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+			eval = c.Evaluate(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+			table := NewCommitmentTable(c)
+			Expect(table.Len()).To(Equal(c.Len()))
+			eval = table.Evaluate(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should handle small indices and points at infinity", func() {
+		for i := 0; i < trials; i++ {
+			c := RandomCommitment(RandRange(1, maxK))
+			c[RandRange(0, c.Len()-1)] = secp256k1.NewPointInfinity()
+			index := secp256k1.NewFnFromU16(uint16(RandRange(0, 3)))
+			expected := evaluateNaive(c, &index)
+
+			eval := c.EvaluateMSM(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should evaluate the same table many times", func() {
+		c := RandomCommitment(RandRange(1, maxK))
+		table := NewCommitmentTable(c)
+		for i := 0; i < trials; i++ {
+			index := secp256k1.RandomFn()
+			expected := evaluateNaive(c, &index)
+			eval := table.Evaluate(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should agree with the tables for long commitments", func() {
+		for i := 0; i < 3; i++ {
+			c := RandomCommitment(RandRange(256, 600))
+			c[RandRange(0, c.Len()-1)] = secp256k1.NewPointInfinity()
+			index := secp256k1.RandomFn()
+			table := NewCommitmentTable(c)
+			expected := table.Evaluate(&index)
+
+			eval := c.EvaluateMSM(&index)
+			Expect(eval.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should panic for an empty commitment", func() {
+		index := secp256k1.RandomFn()
+		Expect(func() { Commitment{}.EvaluateMSM(&index) }).To(Panic())
+		table := NewCommitmentTable(Commitment{})
+		Expect(func() { table.Evaluate(&index) }).To(Panic())
+	})
+})
+
+func BenchmarkEvaluateMSM(b *testing.B) {
+	c := RandomCommitment(33)
+	index := secp256k1.RandomFn()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.EvaluateMSM(&index)
+	}
+}
+
+func BenchmarkEvaluateTable(b *testing.B) {
+	c := RandomCommitment(33)
+	table := NewCommitmentTable(c)
+	index := secp256k1.RandomFn()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = table.Evaluate(&index)
+	}
+}
+
+func BenchmarkEvaluateMSM10k(b *testing.B) {
+	c := RandomCommitment(10000)
+	index := secp256k1.RandomFn()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.EvaluateMSM(&index)
+	}
+}
@@ -0,0 +1,262 @@
+// Package p256 implements Shamir secret sharing and Pedersen verifiable secret
+// sharing over the NIST P-256 curve (also known as secp256r1 or prime256v1).
+// Secrets and shares are elements of the P-256 scalar field, so that keys
+// shared with this package can be used directly by HSMs, smart cards and
+// WebAuthn/PIV authenticators, which commonly only support P-256.
+//
+// Scalar multiplication and point addition use filippo.io/nistec, whose P-256
+// implementation uses complete formulas over fiat-crypto field arithmetic and
+// runs in constant time. Points are stored in their affine encoding, and the
+// conversions to and from it around every operation, like Eq, IsIdentity,
+// Negate and the decoding of points, are not guaranteed to run in constant
+// time. Scalar field arithmetic uses the fixed size Montgomery arithmetic in
+// scalar.go, which runs in constant time, so secrets and shares are never
+// handled by math/big.
+//
+// The API mirrors the secp256k1 based API of the top level shamir package.
+package p256
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+
+	"filippo.io/nistec"
+	"github.com/renproject/surge"
+)
+
+// ScalarSize is the number of bytes in the encoding of a Scalar.
+const ScalarSize = 32
+
+// PointSize is the number of bytes in the encoding of a Point. Points are
+// encoded in SEC1 compressed form, with the identity encoded as 33 zero bytes.
+const PointSize = 33
+
+// CommitmentPointSize is the number of bytes in the encoding of each point of
+// a Commitment.
+const CommitmentPointSize = PointSize
+
+// CurveName returns the name of the group, "P-256".
+func CurveName() string {
+	return "P-256"
+}
+
+// FieldOrder returns the order of the scalar field, which is the order of the
+// group. The caller may modify the returned integer.
+func FieldOrder() *big.Int {
+	var bs [ScalarSize]byte
+	for i := range order {
+		binary.BigEndian.PutUint64(bs[ScalarSize-8*(i+1):], order[i])
+	}
+	return new(big.Int).SetBytes(bs[:])
+}
+
+// The length of the SEC 1 uncompressed encoding of a point.
+const uncompressedSize = 65
+
+// A Point is a point on the P-256 curve. The zero value is the point at
+// infinity, which is the identity element. Operations store their result in
+// the receiver, and are safe for aliasing.
+type Point struct {
+	// The SEC 1 uncompressed encoding of the point, which is unique since it
+	// holds the affine coordinates. The point at infinity is represented by
+	// all zeros, so that it is the zero value.
+	enc [uncompressedSize]byte
+}
+
+// Returns the point as a nistec point. Every operation starts from a new
+// nistec point, so values of type Point never share state.
+func (p *Point) point() *nistec.P256Point {
+	if p.IsIdentity() {
+		return nistec.NewP256Point()
+	}
+	q, err := nistec.NewP256Point().SetBytes(p.enc[:])
+	if err != nil {
+		panic(fmt.Sprintf("invalid point representation: %v", err))
+	}
+	return q
+}
+
+// Sets the point to the given nistec point.
+func (p *Point) set(q *nistec.P256Point) {
+	bs := q.Bytes()
+	if len(bs) != uncompressedSize {
+		// The encoding of the point at infinity is a single zero byte.
+		*p = Point{}
+		return
+	}
+	copy(p.enc[:], bs)
+}
+
+// NewIdentity returns the identity element of the group.
+func NewIdentity() Point {
+	return Point{}
+}
+
+// RandomPoint returns a uniformly random point. It panics if the system source
+// of randomness fails.
+func RandomPoint() Point {
+	var p Point
+	s := RandomScalar()
+	p.BaseExp(&s)
+	return p
+}
+
+// BaseExp computes the scalar multiple of the base point and stores the
+// result in the receiver.
+func (p *Point) BaseExp(s *Scalar) {
+	var bs [ScalarSize]byte
+	s.PutBytes(bs[:])
+	q, err := nistec.NewP256Point().ScalarBaseMult(bs[:])
+	bs = [ScalarSize]byte{}
+	if err != nil {
+		panic(fmt.Sprintf("invalid scalar: %v", err))
+	}
+	p.set(q)
+}
+
+// Scale computes the scalar multiple of a and stores the result in the
+// receiver.
+func (p *Point) Scale(a *Point, s *Scalar) {
+	var bs [ScalarSize]byte
+	s.PutBytes(bs[:])
+	q, err := nistec.NewP256Point().ScalarMult(a.point(), bs[:])
+	bs = [ScalarSize]byte{}
+	if err != nil {
+		panic(fmt.Sprintf("invalid scalar: %v", err))
+	}
+	p.set(q)
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (p *Point) Add(a, b *Point) {
+	p.set(nistec.NewP256Point().Add(a.point(), b.point()))
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (p *Point) Sub(a, b *Point) {
+	var neg Point
+	neg.Negate(b)
+	p.Add(a, &neg)
+}
+
+// Negate computes -a and stores the result in the receiver.
+func (p *Point) Negate(a *Point) {
+	if a.IsIdentity() {
+		*p = Point{}
+		return
+	}
+	// The negation has the same x coordinate and the y coordinate of the
+	// other parity, so it is decoded from the compressed encoding of a with
+	// the parity flipped.
+	var bs [PointSize]byte
+	a.PutBytes(bs[:])
+	bs[0] ^= 1
+	q, err := nistec.NewP256Point().SetBytes(bs[:])
+	if err != nil {
+		panic(fmt.Sprintf("invalid point representation: %v", err))
+	}
+	p.set(q)
+}
+
+// Eq returns true if the two points are equal.
+func (p *Point) Eq(other *Point) bool {
+	return *p == *other
+}
+
+// IsIdentity returns true if the point is the identity element.
+func (p *Point) IsIdentity() bool {
+	return *p == Point{}
+}
+
+// PutBytes writes the 33 byte compressed encoding of the point into the
+// destination slice, which must have length at least PointSize.
+func (p *Point) PutBytes(dst []byte) {
+	if p.IsIdentity() {
+		for i := range dst[:PointSize] {
+			dst[i] = 0
+		}
+		return
+	}
+	dst[0] = 2 | p.enc[uncompressedSize-1]&1
+	copy(dst[1:PointSize], p.enc[1:PointSize])
+}
+
+// SetBytes sets the point from its 33 byte compressed encoding. An error is
+// returned if the encoding is not valid.
+func (p *Point) SetBytes(bs []byte) error {
+	if len(bs) != PointSize {
+		return fmt.Errorf("invalid point length: expected %v bytes, got %v", PointSize, len(bs))
+	}
+	switch bs[0] {
+	case 0:
+		for _, b := range bs[1:] {
+			if b != 0 {
+				return errors.New("invalid point encoding")
+			}
+		}
+		*p = Point{}
+		return nil
+	case 2, 3:
+		q, err := nistec.NewP256Point().SetBytes(bs)
+		if err != nil {
+			return fmt.Errorf("invalid point encoding: %v", err)
+		}
+		p.set(q)
+		return nil
+	default:
+		return errors.New("invalid point encoding")
+	}
+}
+
+// Generate implements the quick.Generator interface.
+func (p Point) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomPoint())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (p Point) SizeHint() int { return PointSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (p Point) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	p.PutBytes(buf)
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (p *Point) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := p.SetBytes(buf[:PointSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// PedersenHFromSeed derives a Pedersen generator from the given domain
+// separation tag by try and increment: for a one byte counter starting at
+// zero, the candidate x coordinate is the SHA-256 hash of the domain followed
+// by the counter, and the first candidate that is on the curve is used
+// together with its even y coordinate. Nobody knows the discrete logarithm of
+// the result with respect to the base point.
+func PedersenHFromSeed(domain []byte) Point {
+	input := make([]byte, len(domain)+1)
+	copy(input, domain)
+	for ctr := 0; ctr < 256; ctr++ {
+		input[len(domain)] = byte(ctr)
+		hash := sha256.Sum256(input)
+		var h Point
+		if h.SetBytes(append([]byte{2}, hash[:]...)) == nil {
+			return h
+		}
+	}
+	panic("could not derive pedersen generator")
+}
@@ -0,0 +1,13 @@
+package p256_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestP256(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "P256 Suite")
+}
@@ -0,0 +1,352 @@
+package p256_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/p256"
+)
+
+var _ = Describe("P-256 backend", func() {
+	trials := 20
+	n := 10
+
+	randomIndices := func(n int) []Scalar {
+		indices := make([]Scalar, n)
+		for i := range indices {
+			indices[i] = RandomScalar()
+		}
+		return indices
+	}
+
+	Context("group operations", func() {
+		It("should treat the zero value point as the identity", func() {
+			var zero Point
+			p := RandomPoint()
+			identity := NewIdentity()
+			Expect(zero.IsIdentity()).To(BeTrue())
+			Expect(zero.Eq(&identity)).To(BeTrue())
+			Expect(zero.Eq(&p)).To(BeFalse())
+
+			var sum Point
+			sum.Add(&p, &zero)
+			Expect(sum.Eq(&p)).To(BeTrue())
+		})
+
+		It("should satisfy the distributive law for scalar multiplication", func() {
+			for i := 0; i < trials; i++ {
+				a, b := RandomScalar(), RandomScalar()
+				var sum Scalar
+				sum.Add(&a, &b)
+
+				var ga, gb, gsum, expected Point
+				ga.BaseExp(&a)
+				gb.BaseExp(&b)
+				gsum.BaseExp(&sum)
+				expected.Add(&ga, &gb)
+				Expect(gsum.Eq(&expected)).To(BeTrue())
+
+				var diff Point
+				diff.Sub(&gsum, &gb)
+				Expect(diff.Eq(&ga)).To(BeTrue())
+			}
+		})
+
+		It("should compute inverses", func() {
+			for i := 0; i < trials; i++ {
+				a := RandomScalar()
+				var inv, prod Scalar
+				inv.Inverse(&a)
+				prod.Mul(&a, &inv)
+				one := NewScalarFromU16(1)
+				Expect(prod.Eq(&one)).To(BeTrue())
+			}
+		})
+
+		It("should agree with math/big for scalar arithmetic", func() {
+			m := FieldOrder()
+			toInt := func(s *Scalar) *big.Int {
+				bs := make([]byte, ScalarSize)
+				s.PutBytes(bs)
+				return new(big.Int).SetBytes(bs)
+			}
+			fromInt := func(x *big.Int) Scalar {
+				bs := make([]byte, ScalarSize)
+				copy(bs[ScalarSize-len(x.Bytes()):], x.Bytes())
+				var s Scalar
+				Expect(s.SetBytes(bs)).To(Succeed())
+				return s
+			}
+			expectInt := func(s *Scalar, expected *big.Int) {
+				Expect(toInt(s).Cmp(expected.Mod(expected, m))).To(Equal(0))
+			}
+
+			// Include the edge cases next to zero and the order.
+			edges := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Sub(m, big.NewInt(1))}
+			for i := 0; i < trials+len(edges)*len(edges); i++ {
+				var a, b Scalar
+				if i < len(edges)*len(edges) {
+					a, b = fromInt(edges[i%len(edges)]), fromInt(edges[i/len(edges)])
+				} else {
+					a, b = RandomScalar(), RandomScalar()
+				}
+				x, y := toInt(&a), toInt(&b)
+				Expect(x.Cmp(m)).To(Equal(-1))
+
+				var res Scalar
+				res.Add(&a, &b)
+				expectInt(&res, new(big.Int).Add(x, y))
+				res.Sub(&a, &b)
+				expectInt(&res, new(big.Int).Sub(x, y))
+				res.Mul(&a, &b)
+				expectInt(&res, new(big.Int).Mul(x, y))
+				res.Negate(&a)
+				expectInt(&res, new(big.Int).Neg(x))
+				if !a.IsZero() {
+					res.Inverse(&a)
+					expectInt(&res, new(big.Int).ModInverse(x, m))
+				}
+			}
+		})
+
+		It("should reject non canonical encodings", func() {
+			bs := make([]byte, PointSize)
+			for i := range bs {
+				bs[i] = 0xff
+			}
+			var s Scalar
+			Expect(s.SetBytes(bs[:ScalarSize])).ToNot(Succeed())
+			var p Point
+			Expect(p.SetBytes(bs)).ToNot(Succeed())
+			bs[0] = 2
+			Expect(p.SetBytes(bs)).ToNot(Succeed())
+		})
+
+		It("should encode points in SEC1 compressed form", func() {
+			one := NewScalarFromU16(1)
+			var g Point
+			g.BaseExp(&one)
+			bs := make([]byte, PointSize)
+			g.PutBytes(bs)
+			Expect(hex.EncodeToString(bs)).To(Equal("036b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296"))
+
+			for i := 0; i < trials; i++ {
+				p := RandomPoint()
+				p.PutBytes(bs)
+				var decoded Point
+				Expect(decoded.SetBytes(bs)).To(Succeed())
+				Expect(decoded.Eq(&p)).To(BeTrue())
+			}
+		})
+
+		It("should agree with known multiples of the base point", func() {
+			two := NewScalarFromU16(2)
+			var g2 Point
+			g2.BaseExp(&two)
+			bs := make([]byte, PointSize)
+			g2.PutBytes(bs)
+			Expect(hex.EncodeToString(bs)).To(Equal("037cf27b188d034f7e8a52380304b51ac3c08969e277f21b35a60b48fc47669978"))
+
+			// The order minus one times the base point is its negation.
+			one := NewScalarFromU16(1)
+			var minusOne Scalar
+			minusOne.Negate(&one)
+			var g, negG, expected Point
+			g.BaseExp(&one)
+			negG.BaseExp(&minusOne)
+			expected.Negate(&g)
+			Expect(negG.Eq(&expected)).To(BeTrue())
+
+			var sum Point
+			sum.Add(&g, &negG)
+			Expect(sum.IsIdentity()).To(BeTrue())
+			var zero Scalar
+			sum.Scale(&g, &zero)
+			Expect(sum.IsIdentity()).To(BeTrue())
+		})
+
+		It("should report the order of the scalar field", func() {
+			// The order minus one is the negation of one.
+			m := FieldOrder()
+			m.Sub(m, big.NewInt(1))
+			bs := make([]byte, ScalarSize)
+			copy(bs[ScalarSize-len(m.Bytes()):], m.Bytes())
+			var x Scalar
+			Expect(x.SetBytes(bs)).To(Succeed())
+			one := NewScalarFromU16(1)
+			x.Add(&x, &one)
+			Expect(x.IsZero()).To(BeTrue())
+			Expect(CommitmentPointSize).To(Equal(PointSize))
+			Expect(CurveName()).ToNot(BeEmpty())
+		})
+	})
+
+	Context("sharing", func() {
+		It("should reconstruct the secret from any k shares", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+
+				rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+				recon := Open(shares[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an ErrInvalidThreshold when k is not between 1 and n", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for _, k := range []int{-1, 0, n + 1} {
+				err := ShareSecret(&shares, indices, RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+				err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+			}
+
+			err := ShareSecret(&shares, indices, RandomScalar(), n+1)
+			var kErr *shamir.ErrKTooLarge
+			Expect(errors.As(err, &kErr)).To(BeTrue())
+			Expect(*kErr).To(Equal(shamir.ErrKTooLarge{K: n + 1, N: n}))
+		})
+
+		It("should return an error when the source of randomness fails", func() {
+			shamir.SetRandomSource(bytes.NewReader(nil))
+			defer shamir.SetRandomSource(nil)
+
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			err := ShareSecret(&shares, indices, RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+
+			// With k = 1 only the decommitment is random.
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 1)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		})
+	})
+
+	Context("verifiable sharing", func() {
+		h := PedersenH()
+
+		It("should produce valid shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+				for j := range vshares {
+					Expect(IsValid(h, c, &vshares[j])).To(BeTrue())
+				}
+
+				recon := Open(vshares.Shares()[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should detect perturbed shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				// When k = 1 the index does not affect validity, so k is at
+				// least 2.
+				Expect(VShareSecret(&vshares, &c, indices, h, RandomScalar(), shamirutil.RandRange(2, n))).To(Succeed())
+				j := rand.Intn(n)
+				switch rand.Intn(3) {
+				case 0:
+					vshares[j].Share.Index = RandomScalar()
+				case 1:
+					vshares[j].Share.Value = RandomScalar()
+				default:
+					vshares[j].Decommitment = RandomScalar()
+				}
+				Expect(IsValid(h, c, &vshares[j])).To(BeFalse())
+			}
+		})
+
+		It("should be homomorphic under addition and scaling", func() {
+			indices := randomIndices(n)
+			vshares1 := make(VerifiableShares, n)
+			vshares2 := make(VerifiableShares, n)
+			c1 := NewCommitmentWithCapacity(n)
+			c2 := NewCommitmentWithCapacity(n)
+			sum := NewCommitmentWithCapacity(n)
+			scaled := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				Expect(VShareSecret(&vshares1, &c1, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				Expect(VShareSecret(&vshares2, &c2, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				scale := RandomScalar()
+				sum.Add(c1, c2)
+				scaled.Scale(c1, &scale)
+				for j := range indices {
+					var s VerifiableShare
+					s.Add(&vshares1[j], &vshares2[j])
+					Expect(IsValid(h, sum, &s)).To(BeTrue())
+					s.Scale(&vshares1[j], &scale)
+					Expect(IsValid(h, scaled, &s)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should derive a fixed Pedersen generator", func() {
+			h1, h2 := PedersenH(), PedersenH()
+			Expect(h1.Eq(&h2)).To(BeTrue())
+			Expect(h1.IsIdentity()).To(BeFalse())
+
+			h3 := PedersenHFromSeed([]byte(PedersenDomain))
+			Expect(h3.Eq(&h1)).To(BeTrue())
+			h4 := PedersenHFromSeed([]byte("another domain"))
+			Expect(h4.Eq(&h1)).To(BeFalse())
+			Expect(h4.IsIdentity()).To(BeFalse())
+		})
+	})
+
+	Context("surge marshalling", func() {
+		types := []reflect.Type{
+			reflect.TypeOf(Scalar{}),
+			reflect.TypeOf(Point{}),
+			reflect.TypeOf(Share{}),
+			reflect.TypeOf(Shares{}),
+			reflect.TypeOf(VerifiableShare{}),
+			reflect.TypeOf(VerifiableShares{}),
+			reflect.TypeOf(Commitment{}),
+		}
+
+		for _, t := range types {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
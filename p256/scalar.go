@@ -0,0 +1,325 @@
+package p256
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	mrand "math/rand"
+	"reflect"
+
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// The order of the group as little endian 64 bit limbs.
+var order = [4]uint64{
+	0xf3b9cac2fc632551, 0xbce6faada7179e84, 0xffffffffffffffff, 0xffffffff00000000,
+}
+
+// The negation of the inverse of the order modulo 2^64.
+const orderInv = 0xccd1c8aaee00bc4f
+
+// R^2 modulo the order, where R = 2^256, as little endian 64 bit limbs.
+var rSquared = [4]uint64{
+	0x83244c95be79eea2, 0x4699799c49bd6fa6, 0x2845b2392b6bec59, 0x66e12d94f3d95620,
+}
+
+// A Scalar is an element of the P-256 scalar field, that is, an integer
+// modulo the order of the base point. The zero value is the zero scalar. Like
+// secp256k1.Fn, operations store their result in the receiver, and all
+// operations are safe for aliasing.
+//
+// Arithmetic is done in the Montgomery domain with fixed size limbs, in the
+// style of fiat-crypto, and does not branch on or index memory by the values
+// of the scalars, so it runs in constant time. The exceptions are IsZero and
+// Eq, whose results are not secret, and the check that an encoding is
+// canonical in SetBytes.
+type Scalar struct {
+	// The Montgomery form of the scalar, x*R modulo the order, as little
+	// endian 64 bit limbs. It is always fully reduced.
+	limbs [4]uint64
+}
+
+// NewScalarFromU16 returns a scalar equal to the given value.
+func NewScalarFromU16(v uint16) Scalar {
+	var s Scalar
+	s.SetU16(v)
+	return s
+}
+
+// RandomScalar returns a uniformly random scalar. It panics if the system
+// source of randomness fails.
+func RandomScalar() Scalar {
+	s, err := randomScalar(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Returns a uniformly random scalar using bytes from the given source.
+func randomScalar(r io.Reader) (Scalar, error) {
+	// Reducing 384 bits modulo the 256 bit order gives a negligible bias.
+	var bs [48]byte
+	if err := sharing.ReadRandom(r, bs[:]); err != nil {
+		return Scalar{}, err
+	}
+	var lo, hi [4]uint64
+	lo = limbsFromBytes(bs[16:])
+	hi[0] = binary.BigEndian.Uint64(bs[8:16])
+	hi[1] = binary.BigEndian.Uint64(bs[:8])
+	for i := range bs {
+		bs[i] = 0
+	}
+
+	// Since the order is greater than 2^255, lo is less than twice the order,
+	// and a single subtraction reduces it. The result is lo + hi*2^256, and
+	// since 2^256 = R, hi*R is the Montgomery multiplication of hi and R^2,
+	// which must then be converted to the Montgomery form itself.
+	var s, t Scalar
+	subOrder(&lo, &lo, 0)
+	montMul(&s.limbs, &lo, &rSquared)
+	montMul(&t.limbs, &hi, &rSquared)
+	montMul(&t.limbs, &t.limbs, &rSquared)
+	s.Add(&s, &t)
+	return s, nil
+}
+
+// SetU16 sets the scalar to the given value.
+func (s *Scalar) SetU16(v uint16) {
+	x := [4]uint64{uint64(v)}
+	montMul(&s.limbs, &x, &rSquared)
+}
+
+// Clear sets the scalar to zero.
+func (s *Scalar) Clear() {
+	s.limbs = [4]uint64{}
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (s *Scalar) Add(a, b *Scalar) {
+	var sum [4]uint64
+	var carry uint64
+	sum[0], carry = bits.Add64(a.limbs[0], b.limbs[0], 0)
+	sum[1], carry = bits.Add64(a.limbs[1], b.limbs[1], carry)
+	sum[2], carry = bits.Add64(a.limbs[2], b.limbs[2], carry)
+	sum[3], carry = bits.Add64(a.limbs[3], b.limbs[3], carry)
+	subOrder(&s.limbs, &sum, carry)
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (s *Scalar) Sub(a, b *Scalar) {
+	var diff [4]uint64
+	var borrow, carry uint64
+	diff[0], borrow = bits.Sub64(a.limbs[0], b.limbs[0], 0)
+	diff[1], borrow = bits.Sub64(a.limbs[1], b.limbs[1], borrow)
+	diff[2], borrow = bits.Sub64(a.limbs[2], b.limbs[2], borrow)
+	diff[3], borrow = bits.Sub64(a.limbs[3], b.limbs[3], borrow)
+
+	// Add the order back if the subtraction wrapped around.
+	mask := -borrow
+	s.limbs[0], carry = bits.Add64(diff[0], order[0]&mask, 0)
+	s.limbs[1], carry = bits.Add64(diff[1], order[1]&mask, carry)
+	s.limbs[2], carry = bits.Add64(diff[2], order[2]&mask, carry)
+	s.limbs[3], _ = bits.Add64(diff[3], order[3]&mask, carry)
+}
+
+// Mul computes a * b and stores the result in the receiver.
+func (s *Scalar) Mul(a, b *Scalar) {
+	montMul(&s.limbs, &a.limbs, &b.limbs)
+}
+
+// Negate computes -a and stores the result in the receiver.
+func (s *Scalar) Negate(a *Scalar) {
+	var zero Scalar
+	s.Sub(&zero, a)
+}
+
+// Inverse computes the multiplicative inverse of a and stores the result in
+// the receiver. The inverse of zero is zero.
+func (s *Scalar) Inverse(a *Scalar) {
+	// By Fermat's little theorem, the inverse is a^(order-2). The exponent is
+	// public, so branching on its bits does not leak anything about a.
+	exp := order
+	exp[0] -= 2
+	x := *a
+	res := NewScalarFromU16(1)
+	for i := 3; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			res.Mul(&res, &res)
+			if exp[i]>>uint(j)&1 == 1 {
+				res.Mul(&res, &x)
+			}
+		}
+	}
+	x.Clear()
+	*s = res
+}
+
+// BatchInvert replaces every element of the slice with its inverse, using a
+// single inversion, as for shamir.BatchInvert. The elements must be non-zero;
+// if any of them is zero, every element is set to zero.
+func BatchInvert(xs []Scalar) {
+	if len(xs) == 0 {
+		return
+	}
+	prefix := make([]Scalar, len(xs))
+	prefix[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		prefix[i].Mul(&prefix[i-1], &xs[i])
+	}
+	var inv, tmp Scalar
+	inv.Inverse(&prefix[len(xs)-1])
+	for i := len(xs) - 1; i > 0; i-- {
+		tmp.Mul(&inv, &prefix[i-1])
+		inv.Mul(&inv, &xs[i])
+		xs[i] = tmp
+	}
+	xs[0] = inv
+}
+
+// IsZero returns true if the scalar is zero.
+func (s *Scalar) IsZero() bool {
+	return s.limbs[0]|s.limbs[1]|s.limbs[2]|s.limbs[3] == 0
+}
+
+// Eq returns true if the two scalars are equal.
+func (s *Scalar) Eq(other *Scalar) bool {
+	var acc uint64
+	for i := range s.limbs {
+		acc |= s.limbs[i] ^ other.limbs[i]
+	}
+	return acc == 0
+}
+
+// PutBytes writes the 32 byte big endian encoding of the scalar into the
+// destination slice, which must have length at least ScalarSize.
+func (s *Scalar) PutBytes(dst []byte) {
+	var x [4]uint64
+	montMul(&x, &s.limbs, &[4]uint64{1})
+	binary.BigEndian.PutUint64(dst[0:8], x[3])
+	binary.BigEndian.PutUint64(dst[8:16], x[2])
+	binary.BigEndian.PutUint64(dst[16:24], x[1])
+	binary.BigEndian.PutUint64(dst[24:32], x[0])
+}
+
+// SetBytes sets the scalar from its 32 byte big endian encoding. An error is
+// returned if the encoded integer is not less than the order.
+func (s *Scalar) SetBytes(bs []byte) error {
+	if len(bs) != ScalarSize {
+		return fmt.Errorf("invalid scalar length: expected %v bytes, got %v", ScalarSize, len(bs))
+	}
+	x := limbsFromBytes(bs)
+	var borrow uint64
+	_, borrow = bits.Sub64(x[0], order[0], 0)
+	_, borrow = bits.Sub64(x[1], order[1], borrow)
+	_, borrow = bits.Sub64(x[2], order[2], borrow)
+	_, borrow = bits.Sub64(x[3], order[3], borrow)
+	if borrow == 0 {
+		return errors.New("scalar is not less than the order")
+	}
+	montMul(&s.limbs, &x, &rSquared)
+	return nil
+}
+
+// Generate implements the quick.Generator interface.
+func (s Scalar) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomScalar())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (s Scalar) SizeHint() int { return ScalarSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (s Scalar) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	s.PutBytes(buf)
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (s *Scalar) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := s.SetBytes(buf[:ScalarSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// Reads 32 big endian bytes as little endian 64 bit limbs.
+func limbsFromBytes(bs []byte) [4]uint64 {
+	return [4]uint64{
+		binary.BigEndian.Uint64(bs[24:32]),
+		binary.BigEndian.Uint64(bs[16:24]),
+		binary.BigEndian.Uint64(bs[8:16]),
+		binary.BigEndian.Uint64(bs[0:8]),
+	}
+}
+
+// Sets dst to x - order if the 257 bit integer x + hi*2^256 is at least the
+// order, and to x otherwise, without branching. The integer must be less than
+// twice the order.
+func subOrder(dst, x *[4]uint64, hi uint64) {
+	var t [4]uint64
+	var borrow uint64
+	t[0], borrow = bits.Sub64(x[0], order[0], 0)
+	t[1], borrow = bits.Sub64(x[1], order[1], borrow)
+	t[2], borrow = bits.Sub64(x[2], order[2], borrow)
+	t[3], borrow = bits.Sub64(x[3], order[3], borrow)
+	_, borrow = bits.Sub64(hi, 0, borrow)
+
+	// The mask is all ones if the subtraction did not wrap around, in which
+	// case the difference is kept.
+	mask := borrow - 1
+	for i := range dst {
+		dst[i] = t[i]&mask | x[i]&^mask
+	}
+}
+
+// Sets dst to the Montgomery product a*b/R modulo the order, using the
+// coarsely integrated operand scanning method. The inputs must be less than
+// the order. The output may alias either input.
+func montMul(dst, a, b *[4]uint64) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		// t += a * b[i]
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		// t = (t + m*order) / 2^64, where m is chosen so that the division is
+		// exact.
+		m := t[0] * orderInv
+		hi, lo := bits.Mul64(m, order[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, order[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+	subOrder(dst, &[4]uint64{t[0], t[1], t[2], t[3]}, t[4])
+}
@@ -0,0 +1,54 @@
+package shamir
+
+import (
+	"crypto/sha256"
+
+	"github.com/renproject/secp256k1"
+)
+
+// PedersenDomain is the domain separation tag from which PedersenH derives
+// the second Pedersen generator.
+const PedersenDomain = "renproject/shamir/secp256k1 pedersen h"
+
+// PedersenH returns the standard second Pedersen generator for use with
+// VShareSecret and IsValid. It is equal to PedersenHFromSeed applied to
+// PedersenDomain.
+func PedersenH() secp256k1.Point {
+	return PedersenHFromSeed([]byte(PedersenDomain))
+}
+
+// PedersenHFromSeed derives a Pedersen generator from the given domain
+// separation tag, so that applications that need several independent
+// generators can derive them in a way that anyone can verify. The derivation
+// is by try and increment: for a one byte counter starting at zero, the
+// candidate x coordinate is the SHA-256 hash of the domain followed by the
+// counter, and the first candidate that is less than the field modulus and is
+// the x coordinate of a curve point is used together with its even y
+// coordinate. Since the point is the output of a hash function, nobody knows
+// its discrete logarithm with respect to the base point.
+func PedersenHFromSeed(domain []byte) secp256k1.Point {
+	input := make([]byte, len(domain)+1)
+	copy(input, domain)
+	for ctr := 0; ctr < 256; ctr++ {
+		input[len(domain)] = byte(ctr)
+		hash := sha256.Sum256(input)
+
+		// Reject candidates that are not canonical field elements.
+		var x secp256k1.Fp
+		if x.SetB32(hash[:]) {
+			continue
+		}
+
+		// The point encoding is the parity of the y coordinate followed by
+		// the x coordinate.
+		var bs [secp256k1.PointSizeMarshalled]byte
+		copy(bs[1:], hash[:])
+		var h secp256k1.Point
+		if err := h.SetBytes(bs[:]); err == nil {
+			return h
+		}
+	}
+	// Each candidate succeeds with probability about one half, so this can
+	// not happen in practice.
+	panic("could not derive pedersen generator")
+}
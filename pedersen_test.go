@@ -0,0 +1,59 @@
+package shamir_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Pedersen generator derivation", func() {
+	It("should be deterministic", func() {
+		h1, h2 := PedersenH(), PedersenH()
+		Expect(h1.Eq(&h2)).To(BeTrue())
+		Expect(h1.IsInfinity()).To(BeFalse())
+
+		h3 := PedersenHFromSeed([]byte(PedersenDomain))
+		Expect(h3.Eq(&h1)).To(BeTrue())
+	})
+
+	It("should give different generators for different domains", func() {
+		h1 := PedersenHFromSeed([]byte("domain a"))
+		h2 := PedersenHFromSeed([]byte("domain b"))
+		Expect(h1.Eq(&h2)).To(BeFalse())
+	})
+
+	It("should be verifiable from the domain", func() {
+		for _, domain := range []string{PedersenDomain, "", "domain a", "domain b"} {
+			h := PedersenHFromSeed([]byte(domain))
+			x, y, err := h.XY()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(y.IsEven()).To(BeTrue())
+
+			var xBytes [32]byte
+			x.PutB32(xBytes[:])
+			found := false
+			for ctr := 0; ctr < 256 && !found; ctr++ {
+				hash := sha256.Sum256(append([]byte(domain), byte(ctr)))
+				found = bytes.Equal(hash[:], xBytes[:])
+			}
+			Expect(found).To(BeTrue())
+		}
+	})
+
+	It("should produce a generator that can be used for verifiable sharing", func() {
+		n, k := 10, 4
+		h := PedersenH()
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+		for i := range vshares {
+			Expect(IsValid(h, &c, &vshares[i])).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,113 @@
+package shamir
+
+import (
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Persistence
+//
+// Share, VerifiableShare and Commitment implement the gob.GobEncoder and
+// gob.GobDecoder interfaces, and are registered with encoding/gob so that they
+// can also be sent as interface values. VerifiableShare and Commitment
+// implement the driver.Valuer and sql.Scanner interfaces, so that they can be
+// stored directly in a database column; Share has a field named Value, so it
+// can not have a method of the same name, and SQLShare adapts it instead.
+//
+// Every encoding is version 1 of the enveloped wire format, as produced by
+// EncodeV1, so it records the type of the value and can be read back with
+// DecodeAny. Database values are stored as bytes, which suits a Postgres bytea
+// column, and can be scanned from bytes or from a hex string, with or without
+// the \x prefix that Postgres uses for bytea in text form.
+//
+// Shares are stored as they are, not encrypted. Columns holding them should be
+// protected accordingly; verifiable shares can instead be encrypted with
+// SealShare before they are stored.
+
+func init() {
+	gob.Register(Share{})
+	gob.Register(VerifiableShare{})
+	gob.Register(Commitment{})
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (s Share) GobEncode() ([]byte, error) { return EncodeV1(s) }
+
+// GobDecode implements the gob.GobDecoder interface.
+func (s *Share) GobDecode(buf []byte) error { return decodeEnvelope(buf, s) }
+
+// GobEncode implements the gob.GobEncoder interface.
+func (vs VerifiableShare) GobEncode() ([]byte, error) { return EncodeV1(vs) }
+
+// GobDecode implements the gob.GobDecoder interface.
+func (vs *VerifiableShare) GobDecode(buf []byte) error { return decodeEnvelope(buf, vs) }
+
+// GobEncode implements the gob.GobEncoder interface.
+func (c Commitment) GobEncode() ([]byte, error) { return EncodeV1(c) }
+
+// GobDecode implements the gob.GobDecoder interface.
+func (c *Commitment) GobDecode(buf []byte) error { return decodeEnvelope(buf, c) }
+
+// Value implements the driver.Valuer interface.
+func (vs VerifiableShare) Value() (driver.Value, error) { return EncodeV1(vs) }
+
+// Scan implements the sql.Scanner interface.
+func (vs *VerifiableShare) Scan(src interface{}) error { return scanEnvelope(src, vs) }
+
+// Value implements the driver.Valuer interface.
+func (c Commitment) Value() (driver.Value, error) { return EncodeV1(c) }
+
+// Scan implements the sql.Scanner interface.
+func (c *Commitment) Scan(src interface{}) error { return scanEnvelope(src, c) }
+
+// SQLShare adapts a Share to the driver.Valuer and sql.Scanner interfaces,
+// which Share can not implement itself because it has a field named Value.
+// A share is stored by passing SQLShare{share} as a query argument, and read
+// back by scanning into a *SQLShare.
+type SQLShare struct {
+	Share Share
+}
+
+// Value implements the driver.Valuer interface.
+func (s SQLShare) Value() (driver.Value, error) { return EncodeV1(s.Share) }
+
+// Scan implements the sql.Scanner interface.
+func (s *SQLShare) Scan(src interface{}) error { return scanEnvelope(src, &s.Share) }
+
+// Decodes the value from the database, which must be the bytes of an envelope
+// or their hex encoding, into the given destination.
+func scanEnvelope(src interface{}, dst interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return decodeEnvelope(src, dst)
+	case string:
+		buf, err := hex.DecodeString(strings.TrimPrefix(src, `\x`))
+		if err != nil {
+			return fmt.Errorf("cannot decode hex: %v", err)
+		}
+		return decodeEnvelope(buf, dst)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into %T", dst)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dst)
+	}
+}
+
+// Decodes the envelope into the given destination, which must be a pointer to
+// a value of the type that the envelope holds.
+func decodeEnvelope(buf []byte, dst interface{}) error {
+	v, err := DecodeAny(buf)
+	if err != nil {
+		return err
+	}
+	dstValue := reflect.ValueOf(dst).Elem()
+	if reflect.TypeOf(v) != dstValue.Type() {
+		return fmt.Errorf("cannot decode %T into %T", v, dst)
+	}
+	dstValue.Set(reflect.ValueOf(v))
+	return nil
+}
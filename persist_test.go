@@ -0,0 +1,125 @@
+package shamir_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Persistence", func() {
+	trials := 20
+
+	randomValues := func() (Share, VerifiableShare, Commitment) {
+		share := NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+		vshare := NewVerifiableShare(share, secp256k1.RandomFn())
+		return share, vshare, RandomCommitment(RandRange(1, 10))
+	}
+
+	Context("when using gob", func() {
+		It("should decode what was encoded", func() {
+			for i := 0; i < trials; i++ {
+				share, vshare, c := randomValues()
+
+				var buf bytes.Buffer
+				enc := gob.NewEncoder(&buf)
+				Expect(enc.Encode(share)).To(Succeed())
+				Expect(enc.Encode(vshare)).To(Succeed())
+				Expect(enc.Encode(c)).To(Succeed())
+
+				var decodedShare Share
+				var decodedVShare VerifiableShare
+				var decodedC Commitment
+				dec := gob.NewDecoder(&buf)
+				Expect(dec.Decode(&decodedShare)).To(Succeed())
+				Expect(dec.Decode(&decodedVShare)).To(Succeed())
+				Expect(dec.Decode(&decodedC)).To(Succeed())
+				Expect(decodedShare.Eq(&share)).To(BeTrue())
+				Expect(decodedVShare.Eq(&vshare)).To(BeTrue())
+				Expect(decodedC.Eq(c)).To(BeTrue())
+			}
+		})
+
+		It("should decode values sent as interfaces", func() {
+			share, vshare, c := randomValues()
+			values := []interface{}{share, vshare, c}
+
+			var buf bytes.Buffer
+			Expect(gob.NewEncoder(&buf).Encode(values)).To(Succeed())
+			var decoded []interface{}
+			Expect(gob.NewDecoder(&buf).Decode(&decoded)).To(Succeed())
+
+			Expect(decoded).To(HaveLen(3))
+			decodedShare := decoded[0].(Share)
+			decodedVShare := decoded[1].(VerifiableShare)
+			Expect(decodedShare.Eq(&share)).To(BeTrue())
+			Expect(decodedVShare.Eq(&vshare)).To(BeTrue())
+			Expect(decoded[2].(Commitment).Eq(c)).To(BeTrue())
+		})
+
+		It("should not decode a value of a different type", func() {
+			_, vshare, _ := randomValues()
+			bs, err := vshare.GobEncode()
+			Expect(err).ToNot(HaveOccurred())
+
+			var c Commitment
+			Expect(c.GobDecode(bs)).ToNot(Succeed())
+		})
+	})
+
+	Context("when using database/sql", func() {
+		It("should scan what was valued", func() {
+			for i := 0; i < trials; i++ {
+				share, vshare, c := randomValues()
+
+				shareValue, err := SQLShare{share}.Value()
+				Expect(err).ToNot(HaveOccurred())
+				vshareValue, err := vshare.Value()
+				Expect(err).ToNot(HaveOccurred())
+				cValue, err := c.Value()
+				Expect(err).ToNot(HaveOccurred())
+
+				var decodedShare SQLShare
+				var decodedVShare VerifiableShare
+				var decodedC Commitment
+				Expect(decodedShare.Scan(shareValue)).To(Succeed())
+				Expect(decodedVShare.Scan(vshareValue)).To(Succeed())
+				Expect(decodedC.Scan(cValue)).To(Succeed())
+				Expect(decodedShare.Share.Eq(&share)).To(BeTrue())
+				Expect(decodedVShare.Eq(&vshare)).To(BeTrue())
+				Expect(decodedC.Eq(c)).To(BeTrue())
+			}
+		})
+
+		It("should scan hex strings", func() {
+			_, vshare, _ := randomValues()
+			value, err := vshare.Value()
+			Expect(err).ToNot(HaveOccurred())
+			str := hex.EncodeToString(value.([]byte))
+
+			for _, src := range []string{str, `\x` + str} {
+				var decoded VerifiableShare
+				Expect(decoded.Scan(src)).To(Succeed())
+				Expect(decoded.Eq(&vshare)).To(BeTrue())
+			}
+		})
+
+		It("should return an error for invalid sources", func() {
+			_, vshare, c := randomValues()
+			value, err := c.Value()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(vshare.Scan(value)).ToNot(Succeed())
+			Expect(vshare.Scan(nil)).ToNot(Succeed())
+			Expect(vshare.Scan(42)).ToNot(Succeed())
+			Expect(vshare.Scan("not hex")).ToNot(Succeed())
+			Expect(vshare.Scan([]byte{})).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,173 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// PointShareSize is the number of bytes in a point share.
+const PointShareSize = secp256k1.FnSizeMarshalled + secp256k1.PointSizeMarshalled
+
+// PointShares is a slice of point shares.
+type PointShares []PointShare
+
+// A PointShare is a share of a secret curve point, shared in the exponent: the
+// value is F(index) for a polynomial F whose coefficients are curve points and
+// whose constant term is the secret point.
+type PointShare struct {
+	Index secp256k1.Fn
+	Value secp256k1.Point
+}
+
+// NewPointShare constructs a new point share from an index and a value.
+func NewPointShare(index secp256k1.Fn, value secp256k1.Point) PointShare {
+	return PointShare{Index: index, Value: value}
+}
+
+// ShareInExponent returns the point share value*P of the given share. If the
+// shares of a sharing of s are each mapped in this way, the point shares are a
+// sharing of s*P, so that, for example, the holders of a threshold ElGamal key
+// can jointly compute s*C for a ciphertext component C without revealing s.
+func ShareInExponent(s *Share, p *secp256k1.Point) PointShare {
+	var value secp256k1.Point
+	value.ScaleExt(p, &s.Value)
+	return PointShare{Index: s.Index, Value: value}
+}
+
+// Eq returns true if the two point shares are equal, and false otherwise.
+func (ps *PointShare) Eq(other *PointShare) bool {
+	return ps.Index.Eq(&other.Index) && ps.Value.Eq(&other.Value)
+}
+
+// IndexEq returns true if the index of the point share is equal to the given
+// index, and false otherwise.
+func (ps *PointShare) IndexEq(other *secp256k1.Fn) bool {
+	return ps.Index.Eq(other)
+}
+
+// SharePoint creates shares in the exponent of the given secret point at the
+// given threshold, and stores them in the given destination. The sharing
+// polynomial is secret + a_1 x G + ... + a_{k-1} x^{k-1} G for random scalars
+// a_i, so that any k of the shares recover the secret with OpenPoint, and
+// fewer than k reveal nothing about it. The checks that are performed on the
+// indices can be configured with the given options, as for ShareSecret.
+//
+// Panics: This function will panic if the destination shares slice has a
+// capacity less than n (the number of indices), or if any of the indices is
+// zero and the WithAllowZeroIndexOff option is not given.
+func SharePoint(dst *PointShares, indices []secp256k1.Fn, secret secp256k1.Point, k int, opts ...ShareOption) error {
+	options := newShareOptions(opts)
+	if err := validateIndices(indices, options); err != nil {
+		return err
+	}
+	if err := checkIndices(indices, k); err != nil {
+		return err
+	}
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	s.reserve(k)
+	defer s.wipe()
+
+	// The constant term of the scalar polynomial is zero, so its evaluations
+	// are the discrete logarithms of the offsets of the shares from the
+	// secret.
+	coeffs := s.coeffs[:k]
+	coeffs[0].Clear()
+	if err := s.sampleFns(&options, coeffs[1:]); err != nil {
+		return err
+	}
+
+	*dst = (*dst)[:len(indices)]
+	for i := range indices {
+		(*dst)[i].Index = indices[i]
+		polyEval(&s.tmp, &indices[i], coeffs)
+		(*dst)[i].Value.BaseExp(&s.tmp)
+		(*dst)[i].Value.Add(&(*dst)[i].Value, &secret)
+	}
+	return nil
+}
+
+// OpenPoint computes the secret point corresponding to the given point shares,
+// by Lagrange interpolation at zero in the exponent. As for Open, the result is
+// only the secret if there are at least k valid shares. An error is returned if
+// the shares do not have distinct indices.
+func OpenPoint(shares PointShares) (secp256k1.Point, error) {
+	if len(shares) == 0 {
+		return secp256k1.Point{}, fmt.Errorf("no shares given")
+	}
+	indices := make([]secp256k1.Fn, len(shares))
+	points := make([]secp256k1.Point, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+		points[i] = shares[i].Value
+	}
+	return InterpolateInExponent(indices, points)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (ps PointShare) SizeHint() int { return ps.Index.SizeHint() + ps.Value.SizeHint() }
+
+// Marshal implements the surge.Marshaler interface.
+func (ps PointShare) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ps.Index.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	return ps.Value.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (ps *PointShare) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ps.Index.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	return ps.Value.Unmarshal(buf, rem)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (shares PointShares) SizeHint() int { return surge.SizeHintU32 + PointShareSize*len(shares) }
+
+// Marshal implements the surge.Marshaler interface.
+func (shares PointShares) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU32(uint32(len(shares)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	for i := range shares {
+		buf, rem, err = shares[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (shares *PointShares) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, PointShareSize, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+
+	if *shares == nil {
+		*shares = make(PointShares, 0, l)
+	}
+
+	*shares = (*shares)[:0]
+	for i := uint32(0); i < l; i++ {
+		*shares = append(*shares, PointShare{})
+		buf, rem, err = (*shares)[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
@@ -0,0 +1,94 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Point sharing", func() {
+	trials := 20
+
+	It("should open to the secret point from any k shares", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(1, 20)
+			k := RandRange(1, n)
+			secret := secp256k1.RandomPoint()
+			shares := make(PointShares, n)
+			Expect(SharePoint(&shares, RandomIndices(n), secret, k)).To(Succeed())
+
+			rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+			opened, err := OpenPoint(shares[:RandRange(k, n)])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened.Eq(&secret)).To(BeTrue())
+		}
+	})
+
+	It("should not open to the secret point from fewer than k shares", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(2, 20)
+			k := RandRange(2, n)
+			secret := secp256k1.RandomPoint()
+			shares := make(PointShares, n)
+			Expect(SharePoint(&shares, RandomIndices(n), secret, k)).To(Succeed())
+
+			opened, err := OpenPoint(shares[:k-1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened.Eq(&secret)).To(BeFalse())
+		}
+	})
+
+	It("should map a sharing of a scalar to a sharing in the exponent", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(1, 20)
+			k := RandRange(1, n)
+			secret := secp256k1.RandomFn()
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, RandomIndices(n), secret, k)).To(Succeed())
+
+			p := secp256k1.RandomPoint()
+			pointShares := make(PointShares, n)
+			for j := range shares {
+				pointShares[j] = ShareInExponent(&shares[j], &p)
+			}
+			var expected secp256k1.Point
+			expected.Scale(&p, &secret)
+			opened, err := OpenPoint(pointShares[:k])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened.Eq(&expected)).To(BeTrue())
+		}
+	})
+
+	It("should return an error for too large a threshold or no shares", func() {
+		n := 5
+		shares := make(PointShares, n)
+		Expect(SharePoint(&shares, RandomIndices(n), secp256k1.RandomPoint(), n+1)).ToNot(Succeed())
+		_, err := OpenPoint(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should unmarshal what was marshalled", func() {
+		for i := 0; i < trials; i++ {
+			n := RandRange(1, 20)
+			shares := make(PointShares, n)
+			Expect(SharePoint(&shares, RandomIndices(n), secp256k1.RandomPoint(), RandRange(1, n))).To(Succeed())
+
+			bs, err := surge.ToBinary(shares)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bs).To(HaveLen(shares.SizeHint()))
+
+			var decoded PointShares
+			Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+			Expect(decoded).To(HaveLen(n))
+			for j := range shares {
+				Expect(decoded[j].Eq(&shares[j])).To(BeTrue())
+			}
+		}
+	})
+})
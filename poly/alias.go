@@ -0,0 +1,13 @@
+//go:build !polydebug
+// +build !polydebug
+
+package poly
+
+import (
+	"github.com/renproject/secp256k1"
+)
+
+// Aliasing checks are only performed in builds with the polydebug tag; see
+// alias_polydebug.go. In other builds they compile to nothing.
+
+func checkNoAlias(op, dstName string, dst []secp256k1.Fn, srcName string, src []secp256k1.Fn) {}
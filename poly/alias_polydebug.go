@@ -0,0 +1,29 @@
+//go:build polydebug
+// +build polydebug
+
+package poly
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Panics if the backing arrays of the two slices overlap, up to their
+// capacities. This is used by the functions that produce incorrect results,
+// rather than panicking, when a destination is an alias of another argument,
+// so that builds with the polydebug tag catch the misuse during development.
+func checkNoAlias(op, dstName string, dst []secp256k1.Fn, srcName string, src []secp256k1.Fn) {
+	if cap(dst) == 0 || cap(src) == 0 {
+		return
+	}
+	size := unsafe.Sizeof(secp256k1.Fn{})
+	dstStart := uintptr(unsafe.Pointer(&dst[:1][0]))
+	srcStart := uintptr(unsafe.Pointer(&src[:1][0]))
+	dstEnd := dstStart + uintptr(cap(dst))*size
+	srcEnd := srcStart + uintptr(cap(src))*size
+	if dstStart < srcEnd && srcStart < dstEnd {
+		panic(fmt.Sprintf("poly: illegal aliasing in %v: %v shares memory with %v", op, dstName, srcName))
+	}
+}
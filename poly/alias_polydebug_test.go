@@ -0,0 +1,53 @@
+//go:build polydebug
+// +build polydebug
+
+package poly_test
+
+import (
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/poly/polyutil"
+)
+
+var _ = Describe("Aliasing checks", func() {
+	maxDegree := 10
+
+	It("should panic when the destinations of a division are aliased", func() {
+		a := NewWithCapacity(maxDegree + 1)
+		b := NewWithCapacity(maxDegree + 1)
+		q := NewWithCapacity(maxDegree + 1)
+		r := NewWithCapacity(maxDegree + 1)
+		polyutil.SetRandomPolynomial(&a, maxDegree)
+		polyutil.SetRandomPolynomial(&b, maxDegree/2)
+
+		Expect(func() { Divide(a, b, &q, &r) }).ToNot(Panic())
+		Expect(func() { Divide(a, b, &a, &r) }).To(Panic())
+		Expect(func() { Divide(a, b, &q, &b) }).To(Panic())
+		Expect(func() { Divide(a, b, &q, &q) }).To(Panic())
+
+		// Polynomials that share a backing array overlap even if their
+		// lengths do not.
+		shared := NewWithCapacity(2 * (maxDegree + 1))
+		lower, upper := shared[:1:maxDegree+1], shared[maxDegree+1:maxDegree+2]
+		Expect(func() { Divide(a, b, &lower, &upper) }).ToNot(Panic())
+		lower = shared[:1]
+		Expect(func() { Divide(a, b, &lower, &upper) }).To(Panic())
+	})
+
+	It("should panic when interpolating into the values", func() {
+		indices := make([]secp256k1.Fn, maxDegree)
+		values := make([]secp256k1.Fn, maxDegree)
+		for i := range indices {
+			indices[i] = secp256k1.RandomFn()
+			values[i] = secp256k1.RandomFn()
+		}
+		interp := NewInterpolator(indices)
+		p := NewWithCapacity(maxDegree)
+		Expect(func() { interp.Interpolate(values, &p) }).ToNot(Panic())
+		aliased := Poly(values)
+		Expect(func() { interp.Interpolate(values, &aliased) }).To(Panic())
+	})
+})
@@ -0,0 +1,157 @@
+package poly
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Evaluations represents a polynomial in point-value form: by its values at a
+// fixed set of distinct indices. Addition, subtraction and scaling take time
+// linear in the number of indices, as does multiplication as long as the
+// degree of the product is less than the number of indices, so computations on
+// shares can stay in this form and only be converted to coefficient form with
+// Interpolate when needed.
+//
+// The indices are shared between all of the evaluations that are derived from
+// each other, and must not be modified.
+type Evaluations struct {
+	indices []secp256k1.Fn
+	values  []secp256k1.Fn
+}
+
+// NewEvaluations constructs the zero polynomial in point-value form over the
+// given indices, which must be distinct.
+func NewEvaluations(indices []secp256k1.Fn) Evaluations {
+	return Evaluations{indices: indices, values: make([]secp256k1.Fn, len(indices))}
+}
+
+// EvaluationsOf evaluates the polynomial at each of the given indices, which
+// must be distinct. The polynomial is only determined by the result if its
+// degree is less than the number of indices.
+func EvaluationsOf(p Poly, indices []secp256k1.Fn) Evaluations {
+	e := NewEvaluations(indices)
+	for i := range indices {
+		e.values[i] = p.Evaluate(indices[i])
+	}
+	return e
+}
+
+// EvaluationsFromValues constructs a polynomial in point-value form from its
+// values at the given indices, which must be distinct. The values are copied.
+//
+// Panics: This function will panic if the number of values is not equal to
+// the number of indices.
+func EvaluationsFromValues(indices, values []secp256k1.Fn) Evaluations {
+	if len(values) != len(indices) {
+		panic(fmt.Sprintf("expected %v values, got %v", len(indices), len(values)))
+	}
+	e := NewEvaluations(indices)
+	copy(e.values, values)
+	return e
+}
+
+// Indices returns the indices at which the polynomial is evaluated. The
+// returned slice must not be modified.
+func (e *Evaluations) Indices() []secp256k1.Fn {
+	return e.indices
+}
+
+// Values returns the values of the polynomial at the indices, in the same
+// order. The returned slice refers to the values of the caller, so modifying
+// it modifies the polynomial.
+func (e *Evaluations) Values() []secp256k1.Fn {
+	return e.values
+}
+
+// Len returns the number of indices.
+func (e *Evaluations) Len() int {
+	return len(e.indices)
+}
+
+// Add computes the sum of the two polynomials and stores the result in the
+// caller. This function is safe for aliasing.
+//
+// Panics: This function will panic if the caller and the arguments are not
+// over the same indices.
+func (e *Evaluations) Add(a, b Evaluations) {
+	e.checkSameIndices(a, b)
+	for i := range e.values {
+		e.values[i].Add(&a.values[i], &b.values[i])
+	}
+}
+
+// Sub computes the difference of the two polynomials and stores the result in
+// the caller. This function is safe for aliasing.
+//
+// Panics: This function will panic if the caller and the arguments are not
+// over the same indices.
+func (e *Evaluations) Sub(a, b Evaluations) {
+	e.checkSameIndices(a, b)
+	var neg secp256k1.Fn
+	for i := range e.values {
+		neg.Negate(&b.values[i])
+		e.values[i].Add(&a.values[i], &neg)
+	}
+}
+
+// ScalarMul computes the multiplication of the polynomial by the given scalar
+// and stores the result in the caller. This function is safe for aliasing.
+//
+// Panics: This function will panic if the caller and the argument are not
+// over the same indices.
+func (e *Evaluations) ScalarMul(a Evaluations, s secp256k1.Fn) {
+	e.checkSameIndices(a, a)
+	for i := range e.values {
+		e.values[i].Mul(&a.values[i], &s)
+	}
+}
+
+// Mul computes the product of the two polynomials and stores the result in
+// the caller. The result only represents the product if the sum of the
+// degrees of the arguments is less than the number of indices; otherwise the
+// values are those of the product reduced modulo the polynomial that vanishes
+// at every index. This function is safe for aliasing.
+//
+// Panics: This function will panic if the caller and the arguments are not
+// over the same indices.
+func (e *Evaluations) Mul(a, b Evaluations) {
+	e.checkSameIndices(a, b)
+	for i := range e.values {
+		e.values[i].Mul(&a.values[i], &b.values[i])
+	}
+}
+
+// Interpolate converts the polynomial to coefficient form using the given
+// interpolator, which must have been constructed for the same indices in the
+// same order, and stores the result in p. The result is the unique
+// polynomial of degree less than the number of indices with these values.
+//
+// NOTE: If the destination polynomial doesn't have sufficient capacity to
+// store the result, this function will panic. It is enough for the capacity
+// to be the number of indices.
+func (e *Evaluations) Interpolate(interp *Interpolator, p *Poly) {
+	if len(interp.basis) != len(e.values) {
+		panic(fmt.Sprintf("interpolator is for %v indices, expected %v", len(interp.basis), len(e.values)))
+	}
+	interp.Interpolate(e.values, p)
+}
+
+// Panics unless the caller and the given evaluations have the same indices.
+// Evaluations derived from each other share the slice of indices, so
+// comparing the elements is usually avoided.
+func (e *Evaluations) checkSameIndices(a, b Evaluations) {
+	for _, other := range [...][]secp256k1.Fn{a.indices, b.indices} {
+		if len(other) != len(e.indices) {
+			panic(fmt.Sprintf("evaluations are over %v and %v indices", len(e.indices), len(other)))
+		}
+		if len(other) == 0 || &other[0] == &e.indices[0] {
+			continue
+		}
+		for i := range other {
+			if !other[i].Eq(&e.indices[i]) {
+				panic("evaluations are over different indices")
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package poly_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/poly/polyutil"
+	"github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Point-value representation", func() {
+	trials := 50
+	const maxPoints = 15
+
+	randomPoly := func(degree int) Poly {
+		p := NewWithCapacity(degree + 1)
+		polyutil.SetRandomPolynomial(&p, degree)
+		return p
+	}
+
+	// Checks that the evaluations are the given polynomial, by converting
+	// them to coefficient form.
+	expectPoly := func(e Evaluations, p Poly) {
+		interp := NewInterpolator(e.Indices())
+		res := NewWithCapacity(e.Len())
+		e.Interpolate(&interp, &res)
+		Expect(res.Eq(p)).To(BeTrue())
+	}
+
+	It("should convert to and from coefficient form", func() {
+		for i := 0; i < trials; i++ {
+			n := rand.Intn(maxPoints) + 1
+			indices := shamirutil.RandomIndices(n)
+			p := randomPoly(rand.Intn(n))
+			e := EvaluationsOf(p, indices)
+			for j := range indices {
+				Expect(e.Values()[j]).To(Equal(p.Evaluate(indices[j])))
+			}
+			expectPoly(e, p)
+			expectPoly(EvaluationsFromValues(indices, e.Values()), p)
+		}
+	})
+
+	It("should add, subtract and scale in point-value form", func() {
+		for i := 0; i < trials; i++ {
+			n := rand.Intn(maxPoints) + 1
+			indices := shamirutil.RandomIndices(n)
+			a, b := randomPoly(rand.Intn(n)), randomPoly(rand.Intn(n))
+			ea, eb := EvaluationsOf(a, indices), EvaluationsOf(b, indices)
+			s := secp256k1.RandomFn()
+
+			expected := NewWithCapacity(n)
+			e := NewEvaluations(indices)
+
+			e.Add(ea, eb)
+			expected.Add(a, b)
+			expectPoly(e, expected)
+
+			e.Sub(ea, eb)
+			expected.Sub(a, b)
+			expectPoly(e, expected)
+
+			// Aliasing.
+			ea.ScalarMul(ea, s)
+			expected.ScalarMul(a, s)
+			expectPoly(ea, expected)
+		}
+	})
+
+	It("should multiply when the degree of the product is small enough", func() {
+		for i := 0; i < trials; i++ {
+			n := rand.Intn(maxPoints) + 1
+			indices := shamirutil.RandomIndices(n)
+			da := rand.Intn(n)
+			a, b := randomPoly(da), randomPoly(rand.Intn(n-da))
+
+			e := EvaluationsOf(a, indices)
+			e.Mul(e, EvaluationsOf(b, indices))
+			expected := NewWithCapacity(n)
+			expected.Mul(a, b)
+			expectPoly(e, expected)
+		}
+	})
+
+	It("should accept evaluations over equal but distinct index slices", func() {
+		indices := shamirutil.RandomIndices(5)
+		copied := append([]secp256k1.Fn{}, indices...)
+		e := NewEvaluations(indices)
+		Expect(func() { e.Add(NewEvaluations(copied), NewEvaluations(indices)) }).ToNot(Panic())
+	})
+
+	It("should panic for evaluations over different indices", func() {
+		e := NewEvaluations(shamirutil.RandomIndices(5))
+		Expect(func() { e.Add(e, NewEvaluations(shamirutil.RandomIndices(5))) }).To(Panic())
+		Expect(func() { e.Mul(e, NewEvaluations(shamirutil.RandomIndices(4))) }).To(Panic())
+		Expect(func() { EvaluationsFromValues(e.Indices(), make([]secp256k1.Fn, 4)) }).To(Panic())
+
+		interp := NewInterpolator(shamirutil.RandomIndices(4))
+		p := NewWithCapacity(5)
+		Expect(func() { e.Interpolate(&interp, &p) }).To(Panic())
+	})
+})
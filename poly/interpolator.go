@@ -10,6 +10,11 @@ import (
 // interpolating multiple sets of points, all of which have the same set of
 // corresponding x coordinates, each interpolation can use the same setup,
 // improving efficiency.
+//
+// The setup is the only state of an interpolator, and it is not modified by
+// Interpolate, so a single interpolator can be used by multiple goroutines
+// concurrently as long as they interpolate into different polynomials. It
+// must not be unmarshalled into while it is in use.
 type Interpolator struct {
 	basis []Poly
 }
@@ -19,6 +24,8 @@ type Interpolator struct {
 // be interpolated. That is, if the set of indices is `{x0, x1, ..., xn}`, then
 // the constructed interpolator will be able to interpolate any set of points
 // of the form `{(x0, y0), (x1, y1), ..., (xn, yn)}` for any `y0, y1, ..., yn`.
+// The denominators of the basis polynomials are inverted together, so that
+// construction needs a single field inversion.
 func NewInterpolator(indices []secp256k1.Fn) Interpolator {
 	// Interpolation will use Lagrange polynomial interpolation
 
@@ -31,12 +38,14 @@ func NewInterpolator(indices []secp256k1.Fn) Interpolator {
 	}
 
 	numerator := NewWithCapacity(2)
-	var denominator secp256k1.Fn
+	denominators := make([]secp256k1.Fn, len(indices))
+	var diff secp256k1.Fn
 
-	// Compute basis polynomials
+	// Compute the numerators and denominators of the basis polynomials
 	numerator = numerator[:2]
 	for i := range basis {
 		basis[i][0].SetU16(1)
+		denominators[i].SetU16(1)
 
 		for j := range indices {
 			if i == j {
@@ -46,22 +55,45 @@ func NewInterpolator(indices []secp256k1.Fn) Interpolator {
 			// Numerator x - xj
 			numerator[0].Negate(&indices[j])
 			numerator[1].SetU16(1)
+			basis[i].Mul(basis[i], numerator)
 
 			// Denominator xi - xj
-			denominator.Negate(&indices[j])
-			denominator.Add(&denominator, &indices[i])
-
-			// (x - xj)/(xi - xj)
-			denominator.Inverse(&denominator)
-			numerator.ScalarMul(numerator, denominator)
-
-			basis[i].Mul(basis[i], numerator)
+			diff.Negate(&indices[j])
+			diff.Add(&diff, &indices[i])
+			denominators[i].Mul(&denominators[i], &diff)
 		}
 	}
 
+	batchInvert(denominators)
+	for i := range basis {
+		basis[i].ScalarMul(basis[i], denominators[i])
+	}
+
 	return Interpolator{basis}
 }
 
+// Inverts every element of the slice with a single inversion, using
+// Montgomery's trick, as for shamir.BatchInvert. The elements must be
+// non-zero.
+func batchInvert(xs []secp256k1.Fn) {
+	if len(xs) == 0 {
+		return
+	}
+	prefix := make([]secp256k1.Fn, len(xs))
+	prefix[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		prefix[i].Mul(&prefix[i-1], &xs[i])
+	}
+	var inv, tmp secp256k1.Fn
+	inv.Inverse(&prefix[len(xs)-1])
+	for i := len(xs) - 1; i > 0; i-- {
+		tmp.Mul(&inv, &prefix[i-1])
+		inv.Mul(&inv, &xs[i])
+		xs[i] = tmp
+	}
+	xs[0] = inv
+}
+
 // Interpolate takes a set of values representing polynomial evaluations, and
 // computes a polynomial that interpolates these values, storing the result in
 // `poly`. It is assumed that the values are in corresponding order to the
@@ -69,8 +101,11 @@ func NewInterpolator(indices []secp256k1.Fn) Interpolator {
 // interpolator was constructed using the set of indices `{x0, x1, ..., xn}`,
 // then calling this function with the values `{y0, y1, ..., yn}` will find the
 // interpolating polynomial for the set of points `{(x0, y0), (x1, y1), ...,
-// (xn, yn)}`.
+// (xn, yn)}`. The polynomial must not share memory with the values, and builds
+// with the polydebug tag panic if it does.
 func (interp *Interpolator) Interpolate(values []secp256k1.Fn, poly *Poly) {
+	checkNoAlias("Interpolate", "poly", *poly, "values", values)
+
 	// Polynomial is a linear combination of the Lagrange basis
 
 	// In the first iteration we set the polynomial in case it was non-zero
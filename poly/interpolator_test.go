@@ -2,6 +2,7 @@ package poly_test
 
 import (
 	"math/rand"
+	"sync"
 
 	"github.com/renproject/secp256k1"
 
@@ -43,5 +44,56 @@ var _ = Describe("Polynomial interpolation", func() {
 				Expect(interpPoly.Eq(poly)).To(BeTrue())
 			}
 		})
+
+		It("should compute the correct interpolating polynomial for sequential indices", func() {
+			const maxPoints int = 15
+			poly := NewWithCapacity(maxPoints + 1)
+			interpPoly := NewWithCapacity(maxPoints + 1)
+			values := make([]secp256k1.Fn, maxPoints)
+
+			for numPoints := 1; numPoints <= maxPoints; numPoints++ {
+				indices := shamirutil.SequentialIndices(numPoints)
+				interpolator := NewInterpolator(indices)
+
+				values = values[:numPoints]
+				polyutil.SetRandomPolynomial(&poly, rand.Intn(numPoints))
+				for j, index := range indices {
+					values[j] = poly.Evaluate(index)
+				}
+
+				interpolator.Interpolate(values, &interpPoly)
+				Expect(interpPoly.Eq(poly)).To(BeTrue())
+			}
+		})
+
+		It("should be usable by multiple goroutines at once", func() {
+			goroutines := 8
+			trials := 20
+			const numPoints int = 15
+
+			indices := shamirutil.RandomIndices(numPoints)
+			interpolator := NewInterpolator(indices)
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					poly := NewWithCapacity(numPoints)
+					interpPoly := NewWithCapacity(numPoints)
+					values := make([]secp256k1.Fn, numPoints)
+					for i := 0; i < trials; i++ {
+						polyutil.SetRandomPolynomial(&poly, rand.Intn(numPoints))
+						for j, index := range indices {
+							values[j] = poly.Evaluate(index)
+						}
+						interpolator.Interpolate(values, &interpPoly)
+						Expect(interpPoly.Eq(poly)).To(BeTrue())
+					}
+				}()
+			}
+			wg.Wait()
+		})
 	})
 })
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/renproject/secp256k1"
-	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/shamir/fnbatch"
 )
 
 // Poly represents a polynomial in the field defined by the elliptic curve
@@ -140,6 +140,22 @@ func (p *Poly) Zero() {
 	p.Coefficient(0).Clear()
 }
 
+// Clobber overwrites every coefficient in the underlying memory of the
+// polynomial with zero, including memory beyond the current degree, and then
+// sets the polynomial to the zero polynomial. This should be used to scrub
+// polynomials that hold secret values, such as sharing polynomials, once they
+// are no longer needed. As with any zeroization in Go, copies of the
+// polynomial that were made previously are not affected.
+func (p *Poly) Clobber() {
+	*p = (*p)[:cap(*p)]
+	for i := range *p {
+		p.Coefficient(i).Clear()
+	}
+	if cap(*p) > 0 {
+		p.Zero()
+	}
+}
+
 // Sets the length of the underlying slice to be such that it can hold a
 // polynomial of the given degree.
 func (p *Poly) setLenByDegree(degree int) {
@@ -156,16 +172,7 @@ func (p *Poly) removeLeadingZeros() {
 
 // Evaluate computes the value of the polynomial at the given point.
 func (p *Poly) Evaluate(x secp256k1.Fn) secp256k1.Fn {
-	var res secp256k1.Fn
-
-	res = *p.Coefficient(p.Degree())
-
-	for i := p.Degree() - 1; i >= 0; i-- {
-		res.Mul(&res, &x)
-		res.Add(&res, p.Coefficient(i))
-	}
-
-	return res
+	return fnbatch.Eval(*p, &x)
 }
 
 // ScalarMul computes the multiplication of the input polynomial by the input
@@ -188,9 +195,7 @@ func (p *Poly) ScalarMul(a Poly, s secp256k1.Fn) {
 	}
 
 	p.setLenByDegree(a.Degree())
-	for i := range *p {
-		p.Coefficient(i).Mul(a.Coefficient(i), &s)
-	}
+	fnbatch.Scale(*p, a, &s)
 }
 
 // Add computes the addition of the two input polynomials and stores the result
@@ -206,15 +211,11 @@ func (p *Poly) ScalarMul(a Poly, s secp256k1.Fn) {
 func (p *Poly) Add(a, b Poly) {
 	if a.Degree() > b.Degree() {
 		p.setLenByDegree(a.Degree())
-		for i := range b {
-			p.Coefficient(i).Add(a.Coefficient(i), b.Coefficient(i))
-		}
+		fnbatch.Add((*p)[:len(b)], a[:len(b)], b)
 		copy((*p)[b.Degree()+1:], a[b.Degree()+1:])
 	} else {
 		p.setLenByDegree(b.Degree())
-		for i := range a {
-			p.Coefficient(i).Add(a.Coefficient(i), b.Coefficient(i))
-		}
+		fnbatch.Add((*p)[:len(a)], a, b[:len(a)])
 		copy((*p)[a.Degree()+1:], b[a.Degree()+1:])
 	}
 
@@ -238,24 +239,14 @@ func (p *Poly) Add(a, b Poly) {
 // degree smaller than this, but this will only happen in the case that some of
 // the leading terms cancel.
 func (p *Poly) AddScaled(a, b Poly, s secp256k1.Fn) {
-	var scaled secp256k1.Fn
-
 	if a.Degree() > b.Degree() {
 		p.setLenByDegree(a.Degree())
-		for i := range b {
-			scaled.Mul(&s, b.Coefficient(i))
-			p.Coefficient(i).Add(a.Coefficient(i), &scaled)
-		}
+		fnbatch.AddScaled((*p)[:len(b)], a[:len(b)], b, &s)
 		copy((*p)[b.Degree()+1:], a[b.Degree()+1:])
 	} else {
 		p.setLenByDegree(b.Degree())
-		for i := range a {
-			scaled.Mul(&s, b.Coefficient(i))
-			p.Coefficient(i).Add(a.Coefficient(i), &scaled)
-		}
-		for i := a.Degree() + 1; i <= b.Degree(); i++ {
-			p.Coefficient(i).Mul(b.Coefficient(i), &s)
-		}
+		fnbatch.AddScaled((*p)[:len(a)], a, b[:len(a)], &s)
+		fnbatch.Scale((*p)[len(a):], b[len(a):], &s)
 	}
 
 	if a.Degree() == b.Degree() {
@@ -327,12 +318,9 @@ func (p *Poly) Neg(a Poly) {
 }
 
 // Mul copmutes the product of the two polynomials and stores the result in the
-// destination polynomial. This function is not safe when the two input
-// polynomials are aliases of eachother as well as the destination; in this
-// case the multiplication will give an incorrect result. The exception to this
-// is when the polynomial has degree 0. Otherwise, either input polynomial may
-// individually be an alias of the destination polynomial or be aliases of
-// eachother (but not the destination) and still be safe.
+// destination polynomial. This function is safe for aliasing: either (and
+// possibly both) of the input polynomials may be an alias of the caller, and
+// the input polynomials may be aliases of eachother.
 //
 // NOTE: If the destination polynomial doesn't have sufficient capacity to
 // store the result, this function will panic. To ensure that the destination
@@ -345,65 +333,28 @@ func (p *Poly) Mul(a, b Poly) {
 		return
 	}
 
-	// In order to allow for the case that p == a or p == b, we need to make
-	// sure that we do not clobber coefficients before we have finished using
-	// them. To do this, we populate the higher Degree() coefficients first.
-	// However, we need to consider that, for instance, the coefficient for the
-	// x^1 term is equal to a0b1 + a1b0, which clearly uses the x^1 coefficient
-	// of both a and b. We therefore need to check which of a and b are aliased
-	// by p, and make sure to use the higher Degree() coefficient of the
-	// aliased polynomial first in our sum, before it gets clobbered.
-	//
-	// We need to check that the slices point to the same memory. Go doesn't
-	// allow comparison of slices other than to the nil value, so we use the
-	// following workaround wherein we compare the addresses of the first
-	// elements of the slices instead.
-	aliasedA := p.Coefficient(0) == a.Coefficient(0)
-
+	// In order to allow for the case that p aliases a, b or both, we need to
+	// make sure that we do not clobber coefficients before we have finished
+	// using them. The coefficient for the x^i term only depends on the
+	// coefficients of a and b for terms of degree at most i, so it is enough
+	// to populate the higher degree coefficients first, and to accumulate
+	// each sum separately before writing it to the destination.
 	p.setLenByDegree(a.Degree() + b.Degree())
 	var aStart, bStart, numTerms int
-	var ab secp256k1.Fn
-
-	// If p aliases a, then we need to count down in the coefficients of a to
-	// avoid clobbering values that we will need to use
-	if aliasedA {
-		for i := a.Degree() + b.Degree(); i >= 0; i-- {
-			aStart = shamirutil.Min(a.Degree(), i)
-			bStart = shamirutil.Max(0, i-a.Degree())
-			numTerms = shamirutil.Min(aStart, b.Degree()-bStart)
-
-			// Account for the fact that initially the memory might not be
-			// zeroed
-			ab.Mul(a.Coefficient(aStart), b.Coefficient(bStart))
-			*p.Coefficient(i) = ab
-
-			for j := 1; j <= numTerms; j++ {
-				// Count down in a and up in b
-				ab.Mul(a.Coefficient(aStart-j), b.Coefficient(bStart+j))
-				p.Coefficient(i).Add(p.Coefficient(i), &ab)
-			}
-		}
-	} else {
-		// It is possible that p does not aliase either a or b here, but in
-		// this case either of the branches would work so we don't need to
-		// consider this case separately
-
-		for i := a.Degree() + b.Degree(); i >= 0; i-- {
-			aStart = shamirutil.Max(0, i-b.Degree())
-			bStart = shamirutil.Min(b.Degree(), i)
-			numTerms = shamirutil.Min(a.Degree()-aStart, bStart)
-
-			// Account for the fact that initially the memory might not be
-			// zeroed
-			ab.Mul(a.Coefficient(aStart), b.Coefficient(bStart))
-			*p.Coefficient(i) = ab
-
-			for j := 1; j <= numTerms; j++ {
-				// Count up in a and down in b
-				ab.Mul(a.Coefficient(aStart+j), b.Coefficient(bStart-j))
-				p.Coefficient(i).Add(p.Coefficient(i), &ab)
-			}
+	var ab, sum secp256k1.Fn
+
+	for i := a.Degree() + b.Degree(); i >= 0; i-- {
+		aStart = maxInt(0, i-b.Degree())
+		bStart = minInt(b.Degree(), i)
+		numTerms = minInt(a.Degree()-aStart, bStart)
+
+		sum.Mul(a.Coefficient(aStart), b.Coefficient(bStart))
+		for j := 1; j <= numTerms; j++ {
+			// Count up in a and down in b
+			ab.Mul(a.Coefficient(aStart+j), b.Coefficient(bStart-j))
+			sum.Add(&sum, &ab)
 		}
+		*p.Coefficient(i) = sum
 	}
 }
 
@@ -412,7 +363,8 @@ func (p *Poly) Mul(a, b Poly) {
 // should satisfy `a = bq + r`. Note that if either `q` or `r` are aliased by
 // either `a` or `b`, the result will be incorrect. This is also true if `q` is
 // an alias of `r`. The inputs `a` and `b` are not modified and can therefore
-// also be aliases of eachother.
+// also be aliases of eachother. Builds with the polydebug tag panic when the
+// destinations are aliased.
 //
 // NOTE: If the destination polynomials (i.e. `q` and `r`) don't have
 // sufficient capacity to store the result, this function will panic. To ensure
@@ -420,6 +372,12 @@ func (p *Poly) Mul(a, b Poly) {
 // `q` has a capacity of at least `deg(a) - deg(b) + 1`, and that `r` has a
 // capacity of at least `deg(a) + 1`.
 func Divide(a, b Poly, q, r *Poly) {
+	checkNoAlias("Divide", "q", *q, "a", a)
+	checkNoAlias("Divide", "q", *q, "b", b)
+	checkNoAlias("Divide", "r", *r, "a", a)
+	checkNoAlias("Divide", "r", *r, "b", b)
+	checkNoAlias("Divide", "q", *q, "r", *r)
+
 	// Short circuit when the division is trivial
 	if b.Degree() > a.Degree() {
 		q.Zero()
@@ -436,6 +394,13 @@ func Divide(a, b Poly, q, r *Poly) {
 	q.setLenByDegree(r.Degree() - d)
 	cInv.Inverse(&c)
 
+	// The remainder can drop by more than one degree in a single step, in
+	// which case some coefficients of the quotient are never written, so
+	// they need to be zeroed first.
+	for i := range *q {
+		q.Coefficient(i).Clear()
+	}
+
 	for r.Degree() >= d {
 		s.Mul(&cInv, r.Coefficient(r.Degree()))
 
@@ -458,3 +423,17 @@ func Divide(a, b Poly, q, r *Poly) {
 		r.Zero()
 	}
 }
+
+func minInt(a, b int) int {
+	if a >= b {
+		return b
+	}
+	return a
+}
+
+func maxInt(a, b int) int {
+	if a <= b {
+		return b
+	}
+	return a
+}
@@ -265,6 +265,31 @@ var _ = Describe("Polynomials", func() {
 		})
 	})
 
+	Context("when clobbering a polynomial", func() {
+		It("should zero the entire underlying memory", func() {
+			trials := 100
+			maxDegree := 20
+
+			poly := NewWithCapacity(maxDegree + 1)
+
+			for i := 0; i < trials; i++ {
+				polyutil.SetRandomPolynomial(&poly, rand.Intn(maxDegree+1))
+
+				poly.Clobber()
+
+				Expect(poly.IsZero()).To(BeTrue())
+				for _, coeff := range poly[:cap(poly)] {
+					Expect(coeff.IsZero()).To(BeTrue())
+				}
+			}
+		})
+
+		It("should not panic for a polynomial with no capacity", func() {
+			poly := Poly{}
+			Expect(func() { poly.Clobber() }).ToNot(Panic())
+		})
+	})
+
 	Context("when evaluating a polynomial at a point", func() {
 		It("should perform the computation correctly", func() {
 			trials := 1000
@@ -976,7 +1001,7 @@ var _ = Describe("Polynomials", func() {
 			}
 		})
 
-		It("should give an incorrect result when the caller and both arguments are aliases of each other", func() {
+		It("should work when the caller and both arguments are aliases of each other", func() {
 			trials := 1000
 			maxDegree := 20
 
@@ -993,11 +1018,7 @@ var _ = Describe("Polynomials", func() {
 				c.Mul(a, b)
 				a.Mul(a, a)
 
-				if degree == 0 {
-					Expect(a.Eq(c)).To(BeTrue())
-				} else {
-					Expect(a.Eq(c)).To(BeFalse())
-				}
+				Expect(a.Eq(c)).To(BeTrue())
 			}
 		})
 	})
@@ -1032,6 +1053,29 @@ var _ = Describe("Polynomials", func() {
 			}
 		})
 
+		It("should give the correct quotient when the remainder drops by more than one degree", func() {
+			// x^4 = (x^2 + 1)(x^2 - 1) + 1, and the x^3 coefficient of the
+			// intermediate remainder is zero.
+			var one, minusOne secp256k1.Fn
+			one.SetU16(1)
+			minusOne.Negate(&one)
+			a := NewFromSlice([]secp256k1.Fn{{}, {}, {}, {}, one})
+			b := NewFromSlice([]secp256k1.Fn{one, {}, one})
+			expectedQ := NewFromSlice([]secp256k1.Fn{minusOne, {}, one})
+
+			// Fill the quotient with non zero values so that any
+			// coefficients that are not written are detected.
+			q := NewWithCapacity(3)
+			polyutil.SetRandomPolynomial(&q, 2)
+			r := NewWithCapacity(5)
+
+			Divide(a, b, &q, &r)
+
+			Expect(q.Eq(expectedQ)).To(BeTrue())
+			Expect(r.Degree()).To(Equal(0))
+			Expect(r.Coefficient(0).IsOne()).To(BeTrue())
+		})
+
 		It("should give the trivial result when deg(a) < deb(b)", func() {
 			trials := 1000
 			maxDegree := 20
@@ -1134,16 +1178,3 @@ func BenchmarkPolyMul(b *testing.B) {
 		polyProd.Mul(poly1, poly2)
 	}
 }
-
-func BenchmarkPolyInterpolate(b *testing.B) {
-	n := 100
-	indices := shamirutil.RandomIndices(n)
-	values := shamirutil.RandomIndices(n)
-	poly := NewWithCapacity(n)
-	interp := NewInterpolator(indices)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		interp.Interpolate(values, &poly)
-	}
-}
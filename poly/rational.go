@@ -0,0 +1,60 @@
+package poly
+
+import (
+	"github.com/renproject/secp256k1"
+)
+
+// RationalReconstruct finds polynomials num and den, with deg(num) <= dNum,
+// deg(den) <= dDen and den not zero, such that den a = num mod m. When den is
+// coprime to m, num/den is the rational function that is equal to a modulo m.
+// The returned den is monic, and the returned boolean is false if no such
+// polynomials exist. When dNum + dDen < deg(m), any other solution (num',
+// den') satisfies num den' = num' den, so the rational function is unique.
+//
+// The polynomials are found by running the Extended Euclidean Algorithm on m
+// and a, and stopping at the first remainder r with deg(r) <= dNum. Then r = t
+// a mod m for the t term of that step, and there is a solution if and only if
+// deg(t) <= dDen. Decoding a Reed-Solomon code is the special case in which m
+// is the product of (x - x_i) over the indices x_i of the shares, a
+// interpolates the shares, den is the error locator, and num is the message
+// polynomial times the error locator. In this case den is not coprime to m,
+// and the message polynomial is num/den.
+//
+// Panics: This function will panic if either dNum or dDen is negative, or if
+// dNum + dDen >= deg(m).
+func RationalReconstruct(a, m Poly, dNum, dDen int) (num, den Poly, ok bool) {
+	if dNum < 0 || dDen < 0 || dNum+dDen >= m.Degree() {
+		panic("invalid degree bounds for rational reconstruction")
+	}
+
+	c := maxInt(len(a), len(m)) + 1
+	rPrev, rNext := NewWithCapacity(c), NewWithCapacity(c)
+	tPrev, tNext := NewWithCapacity(c), NewWithCapacity(c)
+	q, r := NewWithCapacity(c), NewWithCapacity(c)
+
+	// r0 = m, r1 = a mod m, t0 = 0, t1 = 1.
+	rPrev.Set(m)
+	Divide(a, m, &q, &rNext)
+	tNext.Coefficient(0).SetU16(1)
+
+	// The zero polynomial has degree 0, so the loop always terminates.
+	for rNext.Degree() > dNum {
+		Divide(rPrev, rNext, &q, &r)
+		rPrev, rNext, r = rNext, r, rPrev
+
+		// tNext, tPrev = tPrev - q * tNext, tNext
+		r.Mul(q, tNext)
+		r.Sub(tPrev, r)
+		tPrev, tNext, r = tNext, r, tPrev
+	}
+
+	if tNext.Degree() > dDen {
+		return nil, nil, false
+	}
+	var lcInv secp256k1.Fn
+	lcInv.Inverse(tNext.Coefficient(tNext.Degree()))
+	num, den = NewWithCapacity(len(rNext)), NewWithCapacity(len(tNext))
+	num.ScalarMul(rNext, lcInv)
+	den.ScalarMul(tNext, lcInv)
+	return num, den, true
+}
@@ -0,0 +1,119 @@
+package poly_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/poly/polyutil"
+)
+
+var _ = Describe("Rational reconstruction", func() {
+	trials := 50
+	n := 12
+
+	randomPoly := func(degree int) Poly {
+		p := NewWithCapacity(degree + 1)
+		polyutil.SetRandomPolynomial(&p, degree)
+		return p
+	}
+
+	// Returns the product of (x - x_i) over the given indices, and the
+	// polynomial that interpolates the given values at them.
+	setup := func(indices, values []secp256k1.Fn) (Poly, Poly) {
+		m := NewWithCapacity(len(indices) + 1)
+		m.Coefficient(0).SetU16(1)
+		for i := range indices {
+			var neg secp256k1.Fn
+			neg.Negate(&indices[i])
+			m.Mul(m, NewFromSlice([]secp256k1.Fn{neg, secp256k1.NewFnFromU16(1)}))
+		}
+		interp := NewInterpolator(indices)
+		a := NewWithCapacity(len(indices))
+		interp.Interpolate(values, &a)
+		return m, a
+	}
+
+	randomIndices := func() []secp256k1.Fn {
+		indices := make([]secp256k1.Fn, n)
+		for i := range indices {
+			indices[i] = secp256k1.RandomFn()
+		}
+		return indices
+	}
+
+	It("should recover a rational function from its values", func() {
+		for i := 0; i < trials; i++ {
+			dNum := rand.Intn(n)
+			dDen := rand.Intn(n - dNum)
+			num, den := randomPoly(rand.Intn(dNum+1)), randomPoly(rand.Intn(dDen+1))
+			if den.IsZero() {
+				continue
+			}
+
+			indices := randomIndices()
+			values := make([]secp256k1.Fn, n)
+			for j := range indices {
+				x, y := num.Evaluate(indices[j]), den.Evaluate(indices[j])
+				y.Inverse(&y)
+				values[j].Mul(&x, &y)
+			}
+			m, a := setup(indices, values)
+
+			gotNum, gotDen, ok := RationalReconstruct(a, m, dNum, dDen)
+			Expect(ok).To(BeTrue())
+			Expect(gotDen.Coefficient(gotDen.Degree()).IsOne()).To(BeTrue())
+			lhs := NewWithCapacity(2 * n)
+			rhs := NewWithCapacity(2 * n)
+			lhs.Mul(gotNum, den)
+			rhs.Mul(num, gotDen)
+			Expect(lhs.Eq(rhs)).To(BeTrue())
+		}
+	})
+
+	It("should decode a Reed-Solomon codeword with errors", func() {
+		k, e := 4, 3
+		for i := 0; i < trials; i++ {
+			f := randomPoly(k - 1)
+			indices := randomIndices()
+			values := make([]secp256k1.Fn, n)
+			for j := range indices {
+				values[j] = f.Evaluate(indices[j])
+			}
+			for j := 0; j < e; j++ {
+				values[j] = secp256k1.RandomFn()
+			}
+			m, a := setup(indices, values)
+
+			// The message times the error locator has degree less than
+			// k + e, and the error locator has degree e.
+			num, den, ok := RationalReconstruct(a, m, k+e-1, e)
+			Expect(ok).To(BeTrue())
+			q, r := NewWithCapacity(n), NewWithCapacity(n)
+			Divide(num, den, &q, &r)
+			Expect(r.IsZero()).To(BeTrue())
+			Expect(q.Eq(f)).To(BeTrue())
+		}
+	})
+
+	It("should fail when there is no rational function with the given degrees", func() {
+		for i := 0; i < trials; i++ {
+			values := make([]secp256k1.Fn, n)
+			for j := range values {
+				values[j] = secp256k1.RandomFn()
+			}
+			m, a := setup(randomIndices(), values)
+			_, _, ok := RationalReconstruct(a, m, n/2-1, n/2-2)
+			Expect(ok).To(BeFalse())
+		}
+	})
+
+	It("should panic if the degree bounds are too large", func() {
+		m, a := randomPoly(n), randomPoly(n-1)
+		Expect(func() { RationalReconstruct(a, m, n/2, n/2) }).To(Panic())
+		Expect(func() { RationalReconstruct(a, m, -1, 0) }).To(Panic())
+	})
+})
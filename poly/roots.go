@@ -0,0 +1,173 @@
+package poly
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// RootsAmong returns the elements of the given candidates that are roots of
+// the polynomial, in the order in which they appear in the candidates. This is
+// the analogue of a Chien search: every candidate is evaluated, so it is
+// suitable when the possible roots are known in advance, as is the case for
+// the roots of an error locator polynomial, which must be indices of shares.
+// If the polynomial is zero, every candidate is returned.
+func RootsAmong(p Poly, candidates []secp256k1.Fn) []secp256k1.Fn {
+	var roots []secp256k1.Fn
+	for i := range candidates {
+		if y := p.Evaluate(candidates[i]); y.IsZero() {
+			roots = append(roots, candidates[i])
+		}
+	}
+	return roots
+}
+
+// Roots returns the distinct roots of the polynomial in the field, in no
+// particular order. Each root is returned once, regardless of its
+// multiplicity. Unlike RootsAmong, the roots do not need to be known in
+// advance, but finding them is much more expensive: it takes a number of
+// polynomial multiplications modulo p that is proportional to the bit length
+// of the field order, and so should only be used for polynomials of small
+// degree. The roots are found by first computing the gcd of p with x^q - x,
+// where q is the order of the field, which is the product of the distinct
+// linear factors of p, and then splitting this product using the
+// Cantor-Zassenhaus algorithm.
+//
+// The splitting is randomised, using elements read from the source set with
+// shamir.SetRandomSource, and an error is returned if reading from it fails.
+//
+// Panics: This function will panic if the polynomial is zero, since every
+// element of the field is then a root.
+func Roots(p Poly) ([]secp256k1.Fn, error) {
+	if p.IsZero() {
+		panic("cannot find the roots of the zero polynomial")
+	}
+	if p.Degree() == 0 {
+		return nil, nil
+	}
+
+	// Exponents are big endian byte representations. Since the order q is
+	// odd, (q - 1)/2 is q - 1 shifted right by one bit.
+	var minusOne secp256k1.Fn
+	minusOne.SetU16(1)
+	minusOne.Negate(&minusOne)
+	var halfOrder [32]byte
+	minusOne.PutB32(halfOrder[:])
+	for i := len(halfOrder) - 1; i > 0; i-- {
+		halfOrder[i] = halfOrder[i]>>1 | halfOrder[i-1]<<7
+	}
+	halfOrder[0] >>= 1
+
+	f := monic(p)
+	x := NewWithCapacity(2)
+	x.setLenByDegree(1)
+	x.Coefficient(1).SetU16(1)
+
+	// x^q = (x^((q-1)/2))^2 x.
+	h := powMod(x, &halfOrder, f)
+	h = mulMod(h, h, f)
+	h = mulMod(h, x, f)
+	h = sub(h, x)
+	g := gcd(h, f)
+
+	roots := make([]secp256k1.Fn, 0, g.Degree())
+	return splitLinear(roots, g, &halfOrder)
+}
+
+// Appends the roots of the given monic polynomial, which must be a product of
+// distinct linear factors, to dst. For a random a, (x + a)^((q-1)/2) - 1 has
+// each of the roots of g as a root with probability about 1/2, independently,
+// so its gcd with g is a non trivial factor of g with probability at least
+// 1/2 when g has degree at least 2.
+func splitLinear(dst []secp256k1.Fn, g Poly, halfOrder *[32]byte) ([]secp256k1.Fn, error) {
+	switch g.Degree() {
+	case 0:
+		return dst, nil
+	case 1:
+		var root secp256k1.Fn
+		root.Negate(g.Coefficient(0))
+		return append(dst, root), nil
+	}
+
+	var minusOne secp256k1.Fn
+	minusOne.SetU16(1)
+	minusOne.Negate(&minusOne)
+	base := NewWithCapacity(2)
+	base.setLenByDegree(1)
+	base.Coefficient(1).SetU16(1)
+	for {
+		c, err := shamir.RandomFn()
+		if err != nil {
+			return nil, err
+		}
+		*base.Coefficient(0) = c
+		u := powMod(base, halfOrder, g)
+		u.Coefficient(0).Add(u.Coefficient(0), &minusOne)
+		u.removeLeadingZeros()
+		d := gcd(u, g)
+		if d.Degree() == 0 || d.Degree() == g.Degree() {
+			continue
+		}
+		q, r := NewWithCapacity(g.Degree()+1), NewWithCapacity(g.Degree()+1)
+		Divide(g, d, &q, &r)
+		dst, err = splitLinear(dst, d, halfOrder)
+		if err != nil {
+			return nil, err
+		}
+		return splitLinear(dst, q, halfOrder)
+	}
+}
+
+// Returns base^e mod m, where e is a big endian exponent.
+func powMod(base Poly, e *[32]byte, m Poly) Poly {
+	res := NewWithCapacity(m.Degree() + 1)
+	res.Coefficient(0).SetU16(1)
+	for _, b := range e {
+		for j := 7; j >= 0; j-- {
+			res = mulMod(res, res, m)
+			if (b>>uint(j))&1 == 1 {
+				res = mulMod(res, base, m)
+			}
+		}
+	}
+	return res
+}
+
+// Returns a b mod m.
+func mulMod(a, b, m Poly) Poly {
+	prod := NewWithCapacity(a.Degree() + b.Degree() + 1)
+	prod.Mul(a, b)
+	q, r := NewWithCapacity(prod.Degree()+1), NewWithCapacity(prod.Degree()+1)
+	Divide(prod, m, &q, &r)
+	return r
+}
+
+// Returns a - b.
+func sub(a, b Poly) Poly {
+	diff := NewWithCapacity(maxInt(len(a), len(b)))
+	diff.Sub(a, b)
+	return diff
+}
+
+// Returns the monic greatest common divisor of the two polynomials.
+func gcd(a, b Poly) Poly {
+	c := maxInt(len(a), len(b))
+	x, y := NewWithCapacity(c), NewWithCapacity(c)
+	q, r := NewWithCapacity(c), NewWithCapacity(c)
+	x.Set(a)
+	y.Set(b)
+	for !y.IsZero() {
+		Divide(x, y, &q, &r)
+		x, y, r = y, r, x
+	}
+	return monic(x)
+}
+
+// Returns a copy of the polynomial scaled so that its leading coefficient is
+// one. The polynomial must not be zero.
+func monic(a Poly) Poly {
+	var lcInv secp256k1.Fn
+	lcInv.Inverse(a.Coefficient(a.Degree()))
+	p := NewWithCapacity(len(a))
+	p.ScalarMul(a, lcInv)
+	return p
+}
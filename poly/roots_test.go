@@ -0,0 +1,144 @@
+package poly_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/poly/polyutil"
+)
+
+var _ = Describe("Roots", func() {
+	maxDegree := 8
+
+	// Returns the product of (x - r) over the given roots, scaled by a random
+	// non zero constant.
+	fromRoots := func(roots []secp256k1.Fn) Poly {
+		p := NewWithCapacity(len(roots) + 1)
+		*p.Coefficient(0) = secp256k1.RandomFn()
+		for i := range roots {
+			var neg secp256k1.Fn
+			neg.Negate(&roots[i])
+			p.Mul(p, NewFromSlice([]secp256k1.Fn{neg, secp256k1.NewFnFromU16(1)}))
+		}
+		return p
+	}
+
+	findRoots := func(p Poly) []secp256k1.Fn {
+		roots, err := Roots(p)
+		Expect(err).ToNot(HaveOccurred())
+		return roots
+	}
+
+	containsAll := func(xs, ys []secp256k1.Fn) bool {
+		for i := range ys {
+			found := false
+			for j := range xs {
+				if xs[j].Eq(&ys[i]) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	Context("when the candidates are known", func() {
+		It("should return the candidates that are roots in order", func() {
+			for i := 0; i < 100; i++ {
+				roots := make([]secp256k1.Fn, rand.Intn(maxDegree)+1)
+				for j := range roots {
+					roots[j] = secp256k1.RandomFn()
+				}
+				p := fromRoots(roots)
+
+				candidates := make([]secp256k1.Fn, 0, 2*len(roots))
+				var expected []secp256k1.Fn
+				for j := range roots {
+					candidates = append(candidates, secp256k1.RandomFn())
+					if rand.Intn(2) == 0 {
+						candidates = append(candidates, roots[j])
+						expected = append(expected, roots[j])
+					}
+				}
+
+				found := RootsAmong(p, candidates)
+				Expect(len(found)).To(Equal(len(expected)))
+				for j := range found {
+					Expect(found[j].Eq(&expected[j])).To(BeTrue())
+				}
+			}
+		})
+
+		It("should return every candidate for the zero polynomial", func() {
+			candidates := []secp256k1.Fn{secp256k1.RandomFn(), secp256k1.RandomFn()}
+			Expect(RootsAmong(NewWithCapacity(1), candidates)).To(HaveLen(2))
+		})
+	})
+
+	Context("when the candidates are not known", func() {
+		It("should find all of the roots of a product of linear factors", func() {
+			for i := 0; i < 10; i++ {
+				roots := make([]secp256k1.Fn, rand.Intn(maxDegree)+1)
+				for j := range roots {
+					roots[j] = secp256k1.RandomFn()
+				}
+				found := findRoots(fromRoots(roots))
+				Expect(found).To(HaveLen(len(roots)))
+				Expect(containsAll(found, roots)).To(BeTrue())
+			}
+		})
+
+		It("should return repeated roots once", func() {
+			root := secp256k1.RandomFn()
+			other := secp256k1.RandomFn()
+			found := findRoots(fromRoots([]secp256k1.Fn{root, other, root, root}))
+			Expect(found).To(HaveLen(2))
+			Expect(containsAll(found, []secp256k1.Fn{root, other})).To(BeTrue())
+		})
+
+		It("should find zero as a root", func() {
+			roots := []secp256k1.Fn{secp256k1.NewFnFromU16(0), secp256k1.RandomFn()}
+			found := findRoots(fromRoots(roots))
+			Expect(found).To(HaveLen(2))
+			Expect(containsAll(found, roots)).To(BeTrue())
+		})
+
+		It("should only find the roots of the linear factors", func() {
+			for i := 0; i < 10; i++ {
+				// A random polynomial has no roots with probability about
+				// 1/e, so multiplying by one gives factors without roots.
+				roots := make([]secp256k1.Fn, rand.Intn(3)+1)
+				for j := range roots {
+					roots[j] = secp256k1.RandomFn()
+				}
+				linear := fromRoots(roots)
+				other := NewWithCapacity(maxDegree + 1)
+				polyutil.SetRandomPolynomial(&other, rand.Intn(maxDegree-len(roots))+1)
+				p := NewWithCapacity(linear.Degree() + other.Degree() + 1)
+				p.Mul(linear, other)
+
+				found := findRoots(p)
+				Expect(containsAll(found, roots)).To(BeTrue())
+				for j := range found {
+					y := p.Evaluate(found[j])
+					Expect(y.IsZero()).To(BeTrue())
+				}
+			}
+		})
+
+		It("should return no roots for a non zero constant", func() {
+			Expect(findRoots(NewFromSlice([]secp256k1.Fn{secp256k1.NewFnFromU16(3)}))).To(BeEmpty())
+		})
+
+		It("should panic for the zero polynomial", func() {
+			Expect(func() { Roots(NewWithCapacity(1)) }).To(Panic())
+		})
+	})
+})
@@ -0,0 +1,313 @@
+// Package proactive tracks the epochs of a proactively refreshed verifiable
+// sharing. In each refresh, one or more parties deal a sharing of zero to the
+// holders, and each holder adds the zero shares that it receives to its share.
+// The secret is unchanged, but shares from different epochs can not be
+// combined, so an adversary must corrupt k holders within a single epoch.
+//
+// A Scheduler keeps the commitment of every epoch together with the transcript
+// of the refresh that produced it. It checks that each contribution to a
+// refresh is a sharing of zero of the right threshold before accepting it, so
+// that the commitment that it exposes is always a valid commitment to the
+// original secret.
+package proactive
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/surge"
+)
+
+// A Contribution is the public part of a sharing of zero that is dealt to
+// refresh the holders' shares. It consists of the commitment of the sharing,
+// and the value of the decommitment polynomial at zero, which shows that the
+// committed secret is zero: the first point of the commitment is then
+// Decommitment*h.
+type Contribution struct {
+	Commitment   shamir.Commitment
+	Decommitment secp256k1.Fn
+}
+
+// NewContribution deals a sharing of zero with threshold k for the given
+// indices, stores the shares in the destination slice, and returns the public
+// contribution for the sharing. Each share should be sent to the holder with
+// the corresponding index.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func NewContribution(
+	vshares *shamir.VerifiableShares,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	k int,
+) (Contribution, error) {
+	c := shamir.NewCommitmentWithCapacity(k)
+	if err := shamir.VShareSecret(vshares, &c, indices, h, secp256k1.Fn{}, k); err != nil {
+		return Contribution{}, err
+	}
+
+	// The decommitment polynomial has degree at most k - 1, so its value at
+	// zero is determined by the decommitments of any k shares.
+	decommitments := make(shamir.Shares, k)
+	for i := range decommitments {
+		decommitments[i] = shamir.NewShare((*vshares)[i].Share.Index, (*vshares)[i].Decommitment)
+	}
+	return Contribution{Commitment: c, Decommitment: shamir.Open(decommitments)}, nil
+}
+
+// Verify checks that the contribution is a sharing of zero with threshold k
+// with regard to the Pedersen parameter h, and returns an error if it is not.
+func (contrib *Contribution) Verify(h secp256k1.Point, k int) error {
+	if contrib.Commitment.Len() != k {
+		return fmt.Errorf("expected a commitment of length %v, got %v", k, contrib.Commitment.Len())
+	}
+	var expected secp256k1.Point
+	expected.ScaleExt(&h, &contrib.Decommitment)
+	if !expected.Eq(&contrib.Commitment[0]) {
+		return fmt.Errorf("commitment is not a commitment to zero")
+	}
+	return nil
+}
+
+// A Transcript records the refresh that moves a sharing into the given epoch,
+// as the contributions of the parties that dealt sharings of zero.
+type Transcript struct {
+	Epoch         uint64
+	Contributions []Contribution
+}
+
+// A Scheduler tracks the commitment of a proactively refreshed sharing across
+// epochs. The sharing starts in epoch 0, and each accepted transcript moves it
+// into the next epoch. A Scheduler is safe for concurrent use.
+type Scheduler struct {
+	mu          sync.RWMutex
+	h           secp256k1.Point
+	k           int
+	commitments []shamir.Commitment
+	transcripts []Transcript
+}
+
+// NewScheduler constructs a scheduler for the sharing with the given
+// commitment, which is taken to be the commitment of epoch 0, and Pedersen
+// parameter h. The threshold of the sharing is the length of the commitment.
+// The commitment is copied, and so is safe to modify after this function
+// returns.
+//
+// Panics: This function will panic if the commitment is empty.
+func NewScheduler(h secp256k1.Point, c shamir.Commitment) *Scheduler {
+	if c.Len() == 0 {
+		panic("cannot schedule refreshes for an empty commitment")
+	}
+	initial := shamir.NewCommitmentWithCapacity(c.Len())
+	initial.Set(c)
+	return &Scheduler{
+		h:           h,
+		k:           c.Len(),
+		commitments: []shamir.Commitment{initial},
+		transcripts: []Transcript{{}},
+	}
+}
+
+// Epoch returns the current epoch.
+func (s *Scheduler) Epoch() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return uint64(len(s.commitments) - 1)
+}
+
+// Threshold returns the threshold of the sharing.
+func (s *Scheduler) Threshold() int { return s.k }
+
+// Commitment returns a copy of the commitment of the current epoch.
+func (s *Scheduler) Commitment() shamir.Commitment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyCommitment(s.commitments[len(s.commitments)-1])
+}
+
+// CommitmentAt returns a copy of the commitment of the given epoch, and false
+// if the sharing has not reached that epoch.
+func (s *Scheduler) CommitmentAt(epoch uint64) (shamir.Commitment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if epoch >= uint64(len(s.commitments)) {
+		return nil, false
+	}
+	return copyCommitment(s.commitments[epoch]), true
+}
+
+// TranscriptAt returns the transcript of the refresh that moved the sharing
+// into the given epoch, and false if the sharing has not reached that epoch or
+// the epoch is 0, which has no refresh.
+func (s *Scheduler) TranscriptAt(epoch uint64) (Transcript, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if epoch == 0 || epoch >= uint64(len(s.transcripts)) {
+		return Transcript{}, false
+	}
+	return s.transcripts[epoch], true
+}
+
+// Apply checks the given transcript and, if it is valid, moves the sharing
+// into the next epoch, whose commitment is the sum of the current commitment
+// and the commitments of the contributions. A transcript is valid if it is for
+// the epoch after the current one and has at least one contribution, each of
+// which is a sharing of zero with the threshold of the sharing. An error is
+// returned, and the state of the scheduler is unchanged, if it is not. The
+// transcript is copied, and so is safe to modify after this function returns.
+func (s *Scheduler) Apply(t *Transcript) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if next := uint64(len(s.commitments)); t.Epoch != next {
+		return fmt.Errorf("expected a transcript for epoch %v, got %v", next, t.Epoch)
+	}
+	if len(t.Contributions) == 0 {
+		return fmt.Errorf("transcript has no contributions")
+	}
+	next := copyCommitment(s.commitments[len(s.commitments)-1])
+	contribs := make([]Contribution, len(t.Contributions))
+	for i := range t.Contributions {
+		if err := t.Contributions[i].Verify(s.h, s.k); err != nil {
+			return fmt.Errorf("contribution %v: %v", i, err)
+		}
+		next.Add(next, t.Contributions[i].Commitment)
+		contribs[i] = Contribution{
+			Commitment:   copyCommitment(t.Contributions[i].Commitment),
+			Decommitment: t.Contributions[i].Decommitment,
+		}
+	}
+	s.commitments = append(s.commitments, next)
+	s.transcripts = append(s.transcripts, Transcript{Epoch: t.Epoch, Contributions: contribs})
+	return nil
+}
+
+// IsValid returns true if the given share is valid with regard to the
+// commitment of the current epoch, and false otherwise.
+func (s *Scheduler) IsValid(vshare *shamir.VerifiableShare) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return shamir.IsValid(s.h, &s.commitments[len(s.commitments)-1], vshare)
+}
+
+// Refresh stores in dst the share of the holder in the epoch after that of the
+// given share, which is the sum of the share and the given zero shares, one
+// from each contribution to the refresh. The zero shares must all have the
+// same index as the share; an error is returned, and dst is unchanged, if they
+// do not. The old share should be zeroed once the new one has been checked.
+func Refresh(dst, vshare *shamir.VerifiableShare, zeroShares shamir.VerifiableShares) error {
+	sum := *vshare
+	for i := range zeroShares {
+		if !zeroShares[i].Share.IndexEq(&vshare.Share.Index) {
+			sum.Zero()
+			return fmt.Errorf("zero share %v has a different index", i)
+		}
+		sum.Add(&sum, &zeroShares[i])
+	}
+	*dst = sum
+	sum.Zero()
+	return nil
+}
+
+func copyCommitment(c shamir.Commitment) shamir.Commitment {
+	dst := shamir.NewCommitmentWithCapacity(c.Len())
+	dst.Set(c)
+	return dst
+}
+
+// Generate implements the quick.Generator interface.
+func (contrib Contribution) Generate(rand *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Contribution{
+		Commitment:   shamir.Commitment{}.Generate(rand, size).Interface().(shamir.Commitment),
+		Decommitment: secp256k1.RandomFn(),
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (contrib Contribution) SizeHint() int {
+	return contrib.Commitment.SizeHint() + contrib.Decommitment.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (contrib Contribution) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := contrib.Commitment.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return contrib.Decommitment.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (contrib *Contribution) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := contrib.Commitment.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return contrib.Decommitment.Unmarshal(buf, rem)
+}
+
+// Generate implements the quick.Generator interface.
+func (t Transcript) Generate(rand *rand.Rand, size int) reflect.Value {
+	// Both the number of contributions and the lengths of their commitments
+	// are bounded by the size, so it is scaled down to keep the number of
+	// points small.
+	size = size/8 + 1
+	contribs := make([]Contribution, rand.Intn(size))
+	for i := range contribs {
+		contribs[i] = Contribution{}.Generate(rand, size).Interface().(Contribution)
+	}
+	return reflect.ValueOf(Transcript{Epoch: rand.Uint64(), Contributions: contribs})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (t Transcript) SizeHint() int {
+	size := surge.SizeHintU64 + surge.SizeHintU32
+	for i := range t.Contributions {
+		size += t.Contributions[i].SizeHint()
+	}
+	return size
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (t Transcript) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU64(t.Epoch, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalLen(uint32(len(t.Contributions)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range t.Contributions {
+		buf, rem, err = t.Contributions[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (t *Transcript) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.UnmarshalU64(&t.Epoch, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	buf, rem, err = surge.UnmarshalLen(&l, Contribution{}.SizeHint(), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	t.Contributions = make([]Contribution, l)
+	for i := range t.Contributions {
+		buf, rem, err = t.Contributions[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
@@ -0,0 +1,13 @@
+package proactive_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestProactive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Proactive Suite")
+}
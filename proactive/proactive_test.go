@@ -0,0 +1,144 @@
+package proactive_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/proactive"
+)
+
+var _ = Describe("Proactive refresh", func() {
+	trials := 10
+	n, k := 7, 3
+	h := shamir.PedersenH()
+
+	setup := func() ([]secp256k1.Fn, secp256k1.Fn, shamir.VerifiableShares, *Scheduler) {
+		indices := shamirutil.RandomIndices(n)
+		secret := secp256k1.RandomFn()
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		Expect(shamir.VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+		return indices, secret, vshares, NewScheduler(h, c)
+	}
+
+	// Deals the given number of contributions, and returns the transcript for
+	// the given epoch together with the zero shares of each holder.
+	refresh := func(indices []secp256k1.Fn, epoch uint64, contributors int) (Transcript, []shamir.VerifiableShares) {
+		t := Transcript{Epoch: epoch, Contributions: make([]Contribution, contributors)}
+		zeroShares := make([]shamir.VerifiableShares, n)
+		vshares := make(shamir.VerifiableShares, n)
+		for i := range t.Contributions {
+			var err error
+			t.Contributions[i], err = NewContribution(&vshares, indices, h, k)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(t.Contributions[i].Verify(h, k)).To(Succeed())
+			for j := range vshares {
+				zeroShares[j] = append(zeroShares[j], vshares[j])
+			}
+		}
+		return t, zeroShares
+	}
+
+	It("should track valid refreshes across epochs", func() {
+		indices, secret, vshares, s := setup()
+		initial := s.Commitment()
+		for epoch := uint64(1); epoch <= uint64(trials); epoch++ {
+			t, zeroShares := refresh(indices, epoch, shamirutil.RandRange(1, n))
+			Expect(s.Apply(&t)).To(Succeed())
+			Expect(s.Epoch()).To(Equal(epoch))
+
+			for i := range vshares {
+				Expect(Refresh(&vshares[i], &vshares[i], zeroShares[i])).To(Succeed())
+				Expect(s.IsValid(&vshares[i])).To(BeTrue())
+			}
+			Expect(shamir.Open(vshares.Shares()[:k])).To(Equal(secret))
+
+			recorded, ok := s.TranscriptAt(epoch)
+			Expect(ok).To(BeTrue())
+			Expect(recorded.Epoch).To(Equal(epoch))
+			Expect(recorded.Contributions).To(HaveLen(len(t.Contributions)))
+		}
+
+		c, ok := s.CommitmentAt(0)
+		Expect(ok).To(BeTrue())
+		Expect(c.Eq(initial)).To(BeTrue())
+		c, ok = s.CommitmentAt(uint64(trials))
+		Expect(ok).To(BeTrue())
+		Expect(c.Eq(s.Commitment())).To(BeTrue())
+		_, ok = s.CommitmentAt(uint64(trials) + 1)
+		Expect(ok).To(BeFalse())
+		_, ok = s.TranscriptAt(0)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not accept shares from a previous epoch", func() {
+		indices, _, vshares, s := setup()
+		t, _ := refresh(indices, 1, 2)
+		Expect(s.Apply(&t)).To(Succeed())
+		for i := range vshares {
+			Expect(s.IsValid(&vshares[i])).To(BeFalse())
+		}
+	})
+
+	It("should reject invalid transcripts without changing state", func() {
+		for i := 0; i < trials; i++ {
+			indices, _, _, s := setup()
+			before := s.Commitment()
+
+			t, _ := refresh(indices, 2, 1)
+			Expect(s.Apply(&t)).ToNot(Succeed())
+
+			t, _ = refresh(indices, 1, 0)
+			Expect(s.Apply(&t)).ToNot(Succeed())
+
+			// A contribution that is a sharing of a non-zero secret.
+			t, _ = refresh(indices, 1, 2)
+			vshares := make(shamir.VerifiableShares, n)
+			c := shamir.NewCommitmentWithCapacity(k)
+			Expect(shamir.VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+			t.Contributions[1].Commitment = c
+			Expect(s.Apply(&t)).ToNot(Succeed())
+
+			// A contribution with the wrong threshold.
+			t, _ = refresh(indices, 1, 2)
+			vshares = make(shamir.VerifiableShares, n)
+			t.Contributions[0], _ = NewContribution(&vshares, indices, h, k+1)
+			Expect(s.Apply(&t)).ToNot(Succeed())
+
+			Expect(s.Epoch()).To(Equal(uint64(0)))
+			Expect(s.Commitment().Eq(before)).To(BeTrue())
+		}
+	})
+
+	It("should not refresh with zero shares for a different index", func() {
+		indices, _, vshares, _ := setup()
+		_, zeroShares := refresh(indices, 1, 2)
+		before := vshares[0]
+		Expect(Refresh(&vshares[0], &vshares[0], zeroShares[1])).ToNot(Succeed())
+		Expect(vshares[0].Eq(&before)).To(BeTrue())
+	})
+
+	Context("surge marshalling", func() {
+		for _, t := range []reflect.Type{reflect.TypeOf(Contribution{}), reflect.TypeOf(Transcript{})} {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
@@ -0,0 +1,99 @@
+package shamir
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// A SecretProvider supplies a secret scalar that is held elsewhere, for
+// example wrapped by a key in a PKCS#11 token or a cloud KMS, so that it can be
+// shared with ShareSecretFrom without the caller ever holding it.
+type SecretProvider interface {
+	// ReadSecret writes the secret as a 32 byte big endian integer to dst,
+	// which has length 32. The provider should not keep any copies of the
+	// secret once it returns.
+	ReadSecret(dst []byte) error
+}
+
+// SecretProviderFunc is an adapter that allows ordinary functions to be used
+// as secret providers.
+type SecretProviderFunc func(dst []byte) error
+
+// ReadSecret calls f(dst).
+func (f SecretProviderFunc) ReadSecret(dst []byte) error {
+	return f(dst)
+}
+
+// DecrypterProvider is a secret provider for a secret that is encrypted to a
+// key held by a crypto.Decrypter. The PKCS#11 and KMS wrappers for Go, such as
+// those for HSMs and the key management services of cloud providers,
+// generally expose their asymmetric keys as crypto.Decrypters, so this works
+// with any of them without this package depending on them.
+type DecrypterProvider struct {
+	Decrypter  crypto.Decrypter
+	Ciphertext []byte
+	// Opts is passed to Decrypt, for example *rsa.OAEPOptions; it may be nil
+	// if the decrypter does not need any.
+	Opts crypto.DecrypterOpts
+}
+
+// ReadSecret decrypts the ciphertext and writes the resulting secret to dst.
+// The plaintext is zeroed once it has been copied. An error is returned if
+// decryption fails or the plaintext is not 32 bytes long.
+func (p DecrypterProvider) ReadSecret(dst []byte) error {
+	plaintext, err := p.Decrypter.Decrypt(RandomSource(), p.Ciphertext, p.Opts)
+	if err != nil {
+		return fmt.Errorf("could not decrypt secret: %v", err)
+	}
+	defer wipeBytes(plaintext)
+	if len(plaintext) != secp256k1.FnSizeMarshalled {
+		return fmt.Errorf("expected a %v byte secret, got %v bytes", secp256k1.FnSizeMarshalled, len(plaintext))
+	}
+	copy(dst, plaintext)
+	return nil
+}
+
+// ShareSecretFrom is the same as ShareSecret, but reads the secret from the
+// given provider. The secret is only held in memory for the duration of the
+// call, and is zeroed before returning. An error is returned if the provider
+// returns an error or if the secret is not less than the order of the
+// secp256k1 group.
+//
+// Panics: This function will panic under the same conditions as ShareSecret.
+func ShareSecretFrom(dst *Shares, indices []secp256k1.Fn, p SecretProvider, k int, opts ...ShareOption) error {
+	bs := make([]byte, secp256k1.FnSizeMarshalled)
+	defer wipeBytes(bs)
+	if err := p.ReadSecret(bs); err != nil {
+		return err
+	}
+	secret := new(secp256k1.Fn)
+	defer secret.Clear()
+	if secret.SetB32(bs) {
+		return fmt.Errorf("secret is not less than the order of the secp256k1 group")
+	}
+	return ShareSecret(dst, indices, *secret, k, opts...)
+}
+
+// OpenInto is the same as Open, but writes the secret as a 32 byte big endian
+// integer to the given buffer instead of returning it, so that it can be
+// exported directly into memory that the caller controls, such as a locked
+// buffer that is never swapped to disk. The temporary values used to compute
+// the secret are zeroed before returning.
+//
+// Panics: This function will panic if dst is shorter than 32 bytes.
+func OpenInto(dst []byte, shares Shares) {
+	dst = dst[:secp256k1.FnSizeMarshalled]
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	secret := OpenWithScratch(shares, s)
+	secret.PutB32(dst)
+	secret.Clear()
+}
+
+func wipeBytes(bs []byte) {
+	for i := range bs {
+		bs[i] = 0
+	}
+}
@@ -0,0 +1,82 @@
+package shamir_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Secret providers", func() {
+	n, k := 10, 4
+
+	secretBytes := func(secret *secp256k1.Fn) []byte {
+		bs := make([]byte, 32)
+		secret.PutB32(bs)
+		return bs
+	}
+
+	It("should share the secret read from the provider", func() {
+		secret := secp256k1.RandomFn()
+		p := SecretProviderFunc(func(dst []byte) error {
+			copy(dst, secretBytes(&secret))
+			return nil
+		})
+		shares := make(Shares, n)
+		Expect(ShareSecretFrom(&shares, RandomIndices(n), p, k)).To(Succeed())
+		opened := Open(shares[:k])
+		Expect(opened.Eq(&secret)).To(BeTrue())
+	})
+
+	It("should share a secret that is decrypted by a crypto.Decrypter", func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		secret := secp256k1.RandomFn()
+		ciphertext, err := rsa.EncryptOAEP(crypto.SHA256.New(), rand.Reader, &key.PublicKey, secretBytes(&secret), nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		p := DecrypterProvider{
+			Decrypter:  key,
+			Ciphertext: ciphertext,
+			Opts:       &rsa.OAEPOptions{Hash: crypto.SHA256},
+		}
+		shares := make(Shares, n)
+		Expect(ShareSecretFrom(&shares, RandomIndices(n), p, k)).To(Succeed())
+		opened := Open(shares[:k])
+		Expect(opened.Eq(&secret)).To(BeTrue())
+
+		p.Ciphertext = ciphertext[1:]
+		Expect(ShareSecretFrom(&shares, RandomIndices(n), p, k)).ToNot(Succeed())
+	})
+
+	It("should return an error for failing providers and invalid secrets", func() {
+		shares := make(Shares, n)
+		failing := SecretProviderFunc(func([]byte) error { return errors.New("token removed") })
+		Expect(ShareSecretFrom(&shares, RandomIndices(n), failing, k)).ToNot(Succeed())
+
+		tooLarge := SecretProviderFunc(func(dst []byte) error {
+			copy(dst, bytes.Repeat([]byte{0xff}, 32))
+			return nil
+		})
+		Expect(ShareSecretFrom(&shares, RandomIndices(n), tooLarge, k)).ToNot(Succeed())
+	})
+
+	It("should open the secret into the given buffer", func() {
+		secret := secp256k1.RandomFn()
+		shares := make(Shares, n)
+		Expect(ShareSecret(&shares, RandomIndices(n), secret, k)).To(Succeed())
+
+		buf := make([]byte, 32)
+		OpenInto(buf, shares[:k])
+		Expect(buf).To(Equal(secretBytes(&secret)))
+		Expect(func() { OpenInto(make([]byte, 31), shares[:k]) }).To(Panic())
+	})
+})
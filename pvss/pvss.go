@@ -0,0 +1,339 @@
+// Package pvss implements publicly verifiable secret sharing over secp256k1,
+// following Schoenmakers' scheme, so that a dealing can be checked by anyone
+// in a single round, without a complaint phase.
+//
+// Each recipient has a key pair (y, Y = y*G). The dealer shares a secret s
+// with a polynomial f of degree k-1, and publishes:
+//   - a commitment to the coefficients of f multiplied by the second generator
+//     H = shamir.PedersenH(), so that the commitment evaluated at the index of
+//     a recipient is X = f(i)*H;
+//   - the encrypted share E = f(i)*Y of each recipient;
+//   - a single Fiat-Shamir proof, batched over all recipients, that
+//     log_H(X) = log_Y(E) for each of them.
+//
+// Anyone can check the dealing with Verify. A recipient decrypts its share as
+// S = y^-1 * E = f(i)*G, which it publishes with a DLEQ proof of correct
+// decryption, and any k valid decrypted shares can be combined with
+// shamir.OpenPoint into the shared secret s*G.
+//
+// Recipients therefore only learn their shares "in the exponent", and the
+// shared secret is the point s*G rather than the scalar s. This suits
+// applications such as randomness beacons and key encapsulation, where the
+// secret is hashed before use. Since nobody knows the discrete logarithm of H
+// with respect to G, the commitment, which reveals s*H, does not reveal s*G.
+package pvss
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/dleq"
+	"github.com/renproject/shamir/transcript"
+	"github.com/renproject/surge"
+)
+
+// The name of the protocol for the transcripts of dealing proofs, which
+// separates their challenges from other challenges with the same domain.
+const dealingTag = "renproject/shamir/pvss dealing v1"
+
+// The tag that separates the DLEQ proofs of decryption.
+const decryptionTag = "renproject/shamir/pvss decryption"
+
+// A Dealing is the public output of the dealer. It can be broadcast, and
+// verified by anyone that knows the indices and public keys of the
+// recipients.
+type Dealing struct {
+	// Commitment is the commitment to the coefficients of the sharing
+	// polynomial multiplied by shamir.PedersenH().
+	Commitment shamir.Commitment
+	// Encrypted holds the encrypted share of each recipient, in the order of
+	// the recipients given to Deal.
+	Encrypted []secp256k1.Point
+	Proof     Proof
+}
+
+// A Proof is a batched proof that the encrypted shares of a dealing are
+// consistent with its commitment. It has one challenge and one response for
+// each recipient.
+type Proof struct {
+	Challenge secp256k1.Fn
+	Responses []secp256k1.Fn
+}
+
+// A DecryptedShare is a share of the secret s*G, decrypted by its recipient,
+// together with a proof that it was decrypted correctly.
+type DecryptedShare struct {
+	Share shamir.PointShare
+	Proof dleq.Proof
+}
+
+// Deal shares a random secret among the recipients with the given indices and
+// public keys with threshold k, and returns the dealing and the shared secret
+// s*G. The domain separation tag binds the proof to the context of the
+// dealing, such as a protocol name and session identifier.
+//
+// Panics: This function will panic if any of the given indices is the zero
+// element.
+func Deal(indices []secp256k1.Fn, pubKeys []secp256k1.Point, k int, domain []byte) (Dealing, secp256k1.Point, error) {
+	if len(pubKeys) != len(indices) {
+		return Dealing{}, secp256k1.Point{}, fmt.Errorf("expected %v public keys, got %v", len(indices), len(pubKeys))
+	}
+	s, err := shamir.RandomFn()
+	if err != nil {
+		return Dealing{}, secp256k1.Point{}, err
+	}
+	defer s.Clear()
+	shares := make(shamir.Shares, len(indices))
+	defer shares.Zero()
+	coeffs := make([]secp256k1.Fn, k)
+	defer shamir.WipeFns(coeffs)
+	if err := shamir.ShareAndGetCoeffs(&shares, coeffs, indices, s, k); err != nil {
+		return Dealing{}, secp256k1.Point{}, err
+	}
+
+	h := shamir.PedersenH()
+	d := Dealing{
+		Commitment: make(shamir.Commitment, k),
+		Encrypted:  make([]secp256k1.Point, len(indices)),
+		Proof:      Proof{Responses: make([]secp256k1.Fn, len(indices))},
+	}
+	for i := range coeffs {
+		d.Commitment[i].ScaleExt(&h, &coeffs[i])
+	}
+
+	ws := make([]secp256k1.Fn, len(indices))
+	defer shamir.WipeFns(ws)
+	xs := make([]secp256k1.Point, len(indices))
+	a1s := make([]secp256k1.Point, len(indices))
+	a2s := make([]secp256k1.Point, len(indices))
+	for i := range indices {
+		xs[i].ScaleExt(&h, &shares[i].Value)
+		d.Encrypted[i].ScaleExt(&pubKeys[i], &shares[i].Value)
+		if ws[i], err = shamir.RandomFn(); err != nil {
+			return Dealing{}, secp256k1.Point{}, err
+		}
+		a1s[i].ScaleExt(&h, &ws[i])
+		a2s[i].ScaleExt(&pubKeys[i], &ws[i])
+	}
+	d.Proof.Challenge = challenge(domain, d.Commitment, pubKeys, xs, d.Encrypted, a1s, a2s)
+
+	// r = w - c*s_i.
+	for i := range indices {
+		r := &d.Proof.Responses[i]
+		r.Mul(&d.Proof.Challenge, &shares[i].Value)
+		r.Negate(r)
+		r.Add(r, &ws[i])
+	}
+
+	var secret secp256k1.Point
+	secret.BaseExp(&s)
+	return d, secret, nil
+}
+
+// Verify checks that the dealing is a valid sharing with threshold k among
+// the recipients with the given indices and public keys, that is, that the
+// commitment has k points and that every encrypted share is consistent with
+// it. An error is returned if it is not, or if k is less than one.
+func (d *Dealing) Verify(indices []secp256k1.Fn, pubKeys []secp256k1.Point, k int, domain []byte) error {
+	if k < 1 {
+		return fmt.Errorf("%w: expected k >= 1, got k = %v", shamir.ErrInvalidThreshold, k)
+	}
+	n := len(indices)
+	if len(pubKeys) != n || len(d.Encrypted) != n || len(d.Proof.Responses) != n {
+		return fmt.Errorf("expected %v public keys, encrypted shares and responses, got %v, %v and %v",
+			n, len(pubKeys), len(d.Encrypted), len(d.Proof.Responses))
+	}
+	if d.Commitment.Len() != k {
+		return fmt.Errorf("expected a commitment of length %v, got %v", k, d.Commitment.Len())
+	}
+	for i := range pubKeys {
+		if pubKeys[i].IsInfinity() {
+			return fmt.Errorf("public key at position %v is the point at infinity", i)
+		}
+	}
+
+	// a1 = r*H + c*X and a2 = r*Y + c*E.
+	h := shamir.PedersenH()
+	table := shamir.NewCommitmentTable(d.Commitment)
+	xs := make([]secp256k1.Point, n)
+	a1s := make([]secp256k1.Point, n)
+	a2s := make([]secp256k1.Point, n)
+	var tmp secp256k1.Point
+	for i := range indices {
+		xs[i] = table.Evaluate(&indices[i])
+		a1s[i].ScaleExt(&h, &d.Proof.Responses[i])
+		tmp.ScaleExt(&xs[i], &d.Proof.Challenge)
+		a1s[i].Add(&a1s[i], &tmp)
+		a2s[i].ScaleExt(&pubKeys[i], &d.Proof.Responses[i])
+		tmp.ScaleExt(&d.Encrypted[i], &d.Proof.Challenge)
+		a2s[i].Add(&a2s[i], &tmp)
+	}
+	c := challenge(domain, d.Commitment, pubKeys, xs, d.Encrypted, a1s, a2s)
+	if !c.Eq(&d.Proof.Challenge) {
+		return fmt.Errorf("invalid dealing proof")
+	}
+	return nil
+}
+
+// DecryptShare decrypts the encrypted share at the given position of the
+// dealing, for the recipient with the given index and private key, and proves
+// that the decryption is correct. The dealing should have been verified
+// first.
+//
+// Panics: This function will panic if the position is out of range.
+func (d *Dealing) DecryptShare(position int, index *secp256k1.Fn, priv *secp256k1.Fn, domain []byte) (DecryptedShare, error) {
+	if priv.IsZero() {
+		return DecryptedShare{}, fmt.Errorf("private key is zero")
+	}
+	var inv secp256k1.Fn
+	inv.Inverse(priv)
+	defer inv.Clear()
+
+	var s secp256k1.Point
+	s.ScaleExt(&d.Encrypted[position], &inv)
+
+	// Y = y*G and E = y*S.
+	g := secp256k1.NewPointInfinity()
+	one := secp256k1.NewFnFromU16(1)
+	g.BaseExp(&one)
+	st := dleq.NewStatement(priv, &g, &s)
+	proof, err := dleq.Prove(&st, priv, decryptionDomain(domain))
+	if err != nil {
+		return DecryptedShare{}, err
+	}
+	return DecryptedShare{Share: shamir.NewPointShare(*index, s), Proof: proof}, nil
+}
+
+// VerifyDecryptedShare returns true if the decrypted share is the correct
+// decryption of the encrypted share at the given position of the dealing by
+// the recipient with the given public key, and false otherwise.
+//
+// Panics: This function will panic if the position is out of range.
+func (d *Dealing) VerifyDecryptedShare(position int, pubKey *secp256k1.Point, ds *DecryptedShare, domain []byte) bool {
+	g := secp256k1.NewPointInfinity()
+	one := secp256k1.NewFnFromU16(1)
+	g.BaseExp(&one)
+	st := dleq.Statement{G1: g, H1: *pubKey, G2: ds.Share.Value, H2: d.Encrypted[position]}
+	return dleq.Verify(&st, &ds.Proof, decryptionDomain(domain))
+}
+
+func decryptionDomain(domain []byte) []byte {
+	out := make([]byte, 0, len(decryptionTag)+len(domain))
+	out = append(out, decryptionTag...)
+	return append(out, domain...)
+}
+
+// Computes the batched Fiat-Shamir challenge from a transcript of the domain,
+// the commitment, and the public key, commitment evaluation, encrypted share
+// and prover's commitments of every recipient.
+func challenge(
+	domain []byte,
+	c shamir.Commitment,
+	pubKeys, xs, es, a1s, a2s []secp256k1.Point,
+) secp256k1.Fn {
+	t := transcript.New(dealingTag)
+	t.AppendMessage("domain", domain)
+	t.AppendPoints("commitment", c)
+	t.AppendU64("recipients", uint64(len(pubKeys)))
+	for i := range pubKeys {
+		t.AppendPoint("Y", &pubKeys[i])
+		t.AppendPoint("X", &xs[i])
+		t.AppendPoint("E", &es[i])
+		t.AppendPoint("A1", &a1s[i])
+		t.AppendPoint("A2", &a2s[i])
+	}
+	return t.ChallengeScalar("challenge")
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (proof Proof) SizeHint() int {
+	return proof.Challenge.SizeHint() + surge.SizeHintU32 + secp256k1.FnSizeMarshalled*len(proof.Responses)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (proof Proof) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := proof.Challenge.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalLen(uint32(len(proof.Responses)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range proof.Responses {
+		buf, rem, err = proof.Responses[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (proof *Proof) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := proof.Challenge.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	buf, rem, err = surge.UnmarshalLen(&l, secp256k1.FnSizeMarshalled, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	proof.Responses = make([]secp256k1.Fn, l)
+	for i := range proof.Responses {
+		buf, rem, err = proof.Responses[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (d Dealing) SizeHint() int {
+	return d.Commitment.SizeHint() +
+		surge.SizeHintU32 + secp256k1.PointSizeMarshalled*len(d.Encrypted) +
+		d.Proof.SizeHint()
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (d Dealing) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Commitment.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalLen(uint32(len(d.Encrypted)), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	for i := range d.Encrypted {
+		buf, rem, err = d.Encrypted[i].Marshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return d.Proof.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (d *Dealing) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := d.Commitment.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	buf, rem, err = surge.UnmarshalLen(&l, secp256k1.PointSizeMarshalled, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	d.Encrypted = make([]secp256k1.Point, l)
+	for i := range d.Encrypted {
+		buf, rem, err = d.Encrypted[i].Unmarshal(buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return d.Proof.Unmarshal(buf, rem)
+}
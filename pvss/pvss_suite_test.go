@@ -0,0 +1,13 @@
+package pvss_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPVSS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PVSS Suite")
+}
@@ -0,0 +1,144 @@
+package pvss_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/pvss"
+)
+
+var _ = Describe("Publicly verifiable secret sharing", func() {
+	trials := 5
+	n := 10
+	domain := []byte("pvss test")
+
+	setup := func() ([]secp256k1.Fn, []secp256k1.Fn, []secp256k1.Point) {
+		indices := shamirutil.RandomIndices(n)
+		privs := make([]secp256k1.Fn, n)
+		pubs := make([]secp256k1.Point, n)
+		for i := range privs {
+			privs[i] = secp256k1.RandomFn()
+			pubs[i].BaseExp(&privs[i])
+		}
+		return indices, privs, pubs
+	}
+
+	It("should verify honest dealings and recover the secret from k decrypted shares", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(1, n)
+			indices, privs, pubs := setup()
+			d, secret, err := Deal(indices, pubs, k, domain)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.Verify(indices, pubs, k, domain)).To(Succeed())
+
+			perm := rand.Perm(n)[:k]
+			shares := make(shamir.PointShares, k)
+			for j, p := range perm {
+				ds, err := d.DecryptShare(p, &indices[p], &privs[p], domain)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(d.VerifyDecryptedShare(p, &pubs[p], &ds, domain)).To(BeTrue())
+				shares[j] = ds.Share
+			}
+			opened, err := shamir.OpenPoint(shares)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened.Eq(&secret)).To(BeTrue())
+		}
+	})
+
+	It("should reject dealings that have been tampered with", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(2, n)
+			indices, _, pubs := setup()
+			d, _, err := Deal(indices, pubs, k, domain)
+			Expect(err).ToNot(HaveOccurred())
+			j := rand.Intn(n)
+
+			tampered := d
+			tampered.Encrypted = append([]secp256k1.Point{}, d.Encrypted...)
+			tampered.Encrypted[j] = secp256k1.RandomPoint()
+			Expect(tampered.Verify(indices, pubs, k, domain)).ToNot(Succeed())
+
+			tampered = d
+			tampered.Commitment = append(shamir.Commitment{}, d.Commitment...)
+			tampered.Commitment[rand.Intn(k)] = secp256k1.RandomPoint()
+			Expect(tampered.Verify(indices, pubs, k, domain)).ToNot(Succeed())
+
+			tampered = d
+			tampered.Proof.Responses = append([]secp256k1.Fn{}, d.Proof.Responses...)
+			tampered.Proof.Responses[j] = secp256k1.RandomFn()
+			Expect(tampered.Verify(indices, pubs, k, domain)).ToNot(Succeed())
+
+			tampered = d
+			tampered.Proof.Challenge = secp256k1.RandomFn()
+			Expect(tampered.Verify(indices, pubs, k, domain)).ToNot(Succeed())
+
+			otherPubs := append([]secp256k1.Point{}, pubs...)
+			otherPubs[j] = secp256k1.RandomPoint()
+			Expect(d.Verify(indices, otherPubs, k, domain)).ToNot(Succeed())
+			Expect(d.Verify(indices, pubs, k, []byte("other domain"))).ToNot(Succeed())
+			Expect(d.Verify(indices, pubs, k-1, domain)).ToNot(Succeed())
+			Expect(d.Verify(indices[1:], pubs[1:], k, domain)).ToNot(Succeed())
+		}
+	})
+
+	It("should reject incorrectly decrypted shares", func() {
+		indices, privs, pubs := setup()
+		d, _, err := Deal(indices, pubs, 3, domain)
+		Expect(err).ToNot(HaveOccurred())
+		ds, err := d.DecryptShare(0, &indices[0], &privs[0], domain)
+		Expect(err).ToNot(HaveOccurred())
+
+		wrong := ds
+		wrong.Share.Value = secp256k1.RandomPoint()
+		Expect(d.VerifyDecryptedShare(0, &pubs[0], &wrong, domain)).To(BeFalse())
+		Expect(d.VerifyDecryptedShare(1, &pubs[1], &ds, domain)).To(BeFalse())
+		Expect(d.VerifyDecryptedShare(0, &pubs[0], &ds, []byte("other domain"))).To(BeFalse())
+
+		// A share decrypted with the wrong key does not verify.
+		ds, err = d.DecryptShare(0, &indices[0], &privs[1], domain)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.VerifyDecryptedShare(0, &pubs[0], &ds, domain)).To(BeFalse())
+	})
+
+	It("should reject non-positive thresholds when verifying", func() {
+		indices, _, pubs := setup()
+		d, _, err := Deal(indices, pubs, 3, domain)
+		Expect(err).ToNot(HaveOccurred())
+		for _, k := range []int{0, -1} {
+			err = d.Verify(indices, pubs, k, domain)
+			Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+		}
+
+		// A dealing with an empty commitment must not verify for k = 0.
+		d.Commitment = shamir.Commitment{}
+		Expect(func() { _ = d.Verify(indices, pubs, 0, domain) }).ToNot(Panic())
+		Expect(d.Verify(indices, pubs, 0, domain)).ToNot(Succeed())
+	})
+
+	It("should return an error when the number of public keys is wrong", func() {
+		indices, _, pubs := setup()
+		_, _, err := Deal(indices, pubs[1:], 3, domain)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should be the same after marshalling and unmarshalling", func() {
+		indices, _, pubs := setup()
+		d, _, err := Deal(indices, pubs, 4, domain)
+		Expect(err).ToNot(HaveOccurred())
+		bs, err := surge.ToBinary(d)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(bs)).To(Equal(d.SizeHint()))
+
+		var unmarshalled Dealing
+		Expect(surge.FromBinary(&unmarshalled, bs)).To(Succeed())
+		Expect(unmarshalled.Verify(indices, pubs, 4, domain)).To(Succeed())
+		Expect(surge.FromBinary(&unmarshalled, bs[:len(bs)-1])).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,83 @@
+package shamir
+
+import (
+	"errors"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/internal/sharing"
+)
+
+// ErrWeakRandomness is wrapped by the errors returned by sharing when the
+// checks enabled by WithCoefficientCheck or WithEntropyCheck fail.
+var ErrWeakRandomness = errors.New("weak randomness")
+
+// The number of times that coefficients are sampled again when they fail the
+// coefficient check.
+const maxResamples = 3
+
+// SetRandomSource sets the source of randomness that is used by this package
+// to generate the coefficients of sharing polynomials and the decommitments of
+// verifiable sharings, for example so that it can be backed by a hardware
+// security module or a DRBG. Passing nil restores the default, which is
+// crypto/rand.Reader. The source can be overridden for a single call to
+// ShareSecret or VShareSecret with the WithRandomSource option.
+//
+// The source must be safe for concurrent use if sharing is performed
+// concurrently, and must return uniformly random bytes: the security of the
+// sharings depends entirely on it. If reading from the source fails, the
+// sharing functions return an error that wraps the error of the source. This
+// function is safe to call concurrently with the sharing functions, but
+// sharings that are in progress may use either source.
+//
+// The source is also used by the other packages of this module that sample
+// secrets, nonces or random challenges, through RandomFn, and for sharing in
+// the p256, bn254 and ristretto255 packages.
+func SetRandomSource(r io.Reader) {
+	sharing.SetRandomSource(r)
+}
+
+// RandomSource returns the source of randomness that was set with
+// SetRandomSource, or crypto/rand.Reader if none was set.
+func RandomSource() io.Reader {
+	return sharing.RandomSource()
+}
+
+// RandomFn returns a random field element using bytes from the source set
+// with SetRandomSource. As for secp256k1.RandomFn, 32 bytes are reduced modulo
+// the order. An error is returned if reading from the source fails.
+func RandomFn() (secp256k1.Fn, error) {
+	return randomFn(RandomSource())
+}
+
+// Returns a random field element using bytes from the given source.
+func randomFn(r io.Reader) (secp256k1.Fn, error) {
+	var bs [32]byte
+	defer func() {
+		for i := range bs {
+			bs[i] = 0
+		}
+	}()
+	var x secp256k1.Fn
+	if err := sharing.ReadRandom(r, bs[:]); err != nil {
+		return x, err
+	}
+	x.SetB32(bs[:])
+	return x, nil
+}
+
+// Returns true if none of the given field elements is zero and no two are
+// equal.
+func distinctNonZero(xs []secp256k1.Fn) bool {
+	for i := range xs {
+		if xs[i].IsZero() {
+			return false
+		}
+		for j := 0; j < i; j++ {
+			if xs[i].Eq(&xs[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+package shamir_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	mrand "math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var errSourceFailed = errors.New("source failed")
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errSourceFailed }
+
+// A repeatingReader returns the same 32 bytes over and over.
+type repeatingReader [32]byte
+
+func (r *repeatingReader) Read(bs []byte) (int, error) {
+	for i := range bs {
+		bs[i] = r[i%32]
+	}
+	return len(bs), nil
+}
+
+var _ = Describe("Random source", func() {
+	n, k := 10, 4
+	h := secp256k1.RandomPoint()
+
+	AfterEach(func() {
+		SetRandomSource(nil)
+	})
+
+	vshare := func(indices []secp256k1.Fn, secret secp256k1.Fn, opts ...ShareOption) (VerifiableShares, Commitment) {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secret, k, opts...)).To(Succeed())
+		return vshares, c
+	}
+
+	It("should default to crypto/rand", func() {
+		Expect(RandomSource()).To(Equal(rand.Reader))
+		SetRandomSource(mrand.New(mrand.NewSource(0)))
+		Expect(RandomSource()).ToNot(Equal(rand.Reader))
+		SetRandomSource(nil)
+		Expect(RandomSource()).To(Equal(rand.Reader))
+	})
+
+	It("should make sharing deterministic for a deterministic source", func() {
+		indices := RandomIndices(n)
+		secret := secp256k1.RandomFn()
+
+		shares1, shares2 := make(Shares, n), make(Shares, n)
+		SetRandomSource(mrand.New(mrand.NewSource(1)))
+		Expect(ShareSecret(&shares1, indices, secret, k)).To(Succeed())
+		SetRandomSource(mrand.New(mrand.NewSource(1)))
+		Expect(ShareSecret(&shares2, indices, secret, k)).To(Succeed())
+		for i := range shares1 {
+			Expect(shares1[i].Eq(&shares2[i])).To(BeTrue())
+		}
+
+		SetRandomSource(mrand.New(mrand.NewSource(1)))
+		vshares1, c1 := vshare(indices, secret)
+		SetRandomSource(mrand.New(mrand.NewSource(1)))
+		vshares2, c2 := vshare(indices, secret)
+		Expect(c1.Eq(c2)).To(BeTrue())
+		for i := range vshares1 {
+			Expect(vshares1[i].Eq(&vshares2[i])).To(BeTrue())
+			Expect(IsValid(h, &c1, &vshares1[i])).To(BeTrue())
+		}
+		opened := Open(vshares1.Shares()[:k])
+		Expect(opened.Eq(&secret)).To(BeTrue())
+	})
+
+	It("should use the source for every sharing function", func() {
+		indices := RandomIndices(n)
+		secret := secp256k1.RandomFn()
+		SetRandomSource(failingReader{})
+
+		shares := make(Shares, n)
+		coeffs := make([]secp256k1.Fn, k)
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		_, batchErr := VShareSecretBatch(indices, h, []secp256k1.Fn{secret}, k)
+		_, fnErr := RandomFn()
+		_, keyErr := NewAuthKey()
+		for _, err := range []error{
+			ShareSecret(&shares, indices, secret, k),
+			ShareAndGetCoeffs(&shares, coeffs, indices, secret, k),
+			VShareSecret(&vshares, &c, indices, h, secret, k),
+			batchErr,
+			fnErr,
+			keyErr,
+		} {
+			Expect(errors.Is(err, errSourceFailed)).To(BeTrue())
+		}
+	})
+
+	It("should prefer the source given as an option", func() {
+		indices := RandomIndices(n)
+		secret := secp256k1.RandomFn()
+		SetRandomSource(failingReader{})
+
+		vshares1, c1 := vshare(indices, secret, WithRandomSource(mrand.New(mrand.NewSource(2))))
+		vshares2, c2 := vshare(indices, secret, WithRandomSource(mrand.New(mrand.NewSource(2))))
+		Expect(c1.Eq(c2)).To(BeTrue())
+		for i := range vshares1 {
+			Expect(vshares1[i].Eq(&vshares2[i])).To(BeTrue())
+		}
+
+		shares := make(Shares, n)
+		Expect(ShareSecret(&shares, indices, secret, k, WithRandomSource(rand.Reader))).To(Succeed())
+		opened := Open(shares[:k])
+		Expect(opened.Eq(&secret)).To(BeTrue())
+	})
+
+	Context("when checking the coefficients", func() {
+		It("should sample again when a coefficient is zero", func() {
+			indices := RandomIndices(n)
+			secret := secp256k1.RandomFn()
+			zeros := bytes.NewReader(make([]byte, 32*(k-1)))
+			r := io.MultiReader(zeros, rand.Reader)
+
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, indices, secret, k, WithRandomSource(r), WithCoefficientCheck())).To(Succeed())
+			Expect(zeros.Len()).To(Equal(0))
+			opened := Open(shares[:k])
+			Expect(opened.Eq(&secret)).To(BeTrue())
+		})
+
+		It("should return an error when the coefficients keep repeating", func() {
+			indices := RandomIndices(n)
+			secret := secp256k1.RandomFn()
+			r := new(repeatingReader)
+			rand.Read(r[:])
+			opts := []ShareOption{WithRandomSource(r), WithCoefficientCheck()}
+
+			shares := make(Shares, n)
+			err := ShareSecret(&shares, indices, secret, k, opts...)
+			Expect(errors.Is(err, ErrWeakRandomness)).To(BeTrue())
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			err = VShareSecret(&vshares, &c, indices, h, secret, k, opts...)
+			Expect(errors.Is(err, ErrWeakRandomness)).To(BeTrue())
+			pshares := make(PointShares, n)
+			err = SharePoint(&pshares, indices, secp256k1.RandomPoint(), k, opts...)
+			Expect(errors.Is(err, ErrWeakRandomness)).To(BeTrue())
+			err = ShareSecretChunked(indices, secret, k, 0, func(Shares) error { return nil }, opts...)
+			Expect(errors.Is(err, ErrWeakRandomness)).To(BeTrue())
+
+			// Without the check, the weak source is used as it is.
+			Expect(ShareSecret(&shares, indices, secret, k, WithRandomSource(r))).To(Succeed())
+		})
+	})
+
+	Context("when checking the entropy", func() {
+		It("should pass the random bytes to the check", func() {
+			indices := RandomIndices(n)
+			secret := secp256k1.RandomFn()
+			var samples [][]byte
+			check := func(sample []byte) error {
+				samples = append(samples, append([]byte{}, sample...))
+				return nil
+			}
+
+			vshare(indices, secret, WithEntropyCheck(check))
+			Expect(samples).To(HaveLen(2))
+			Expect(samples[0]).To(HaveLen(32 * (k - 1)))
+			Expect(samples[1]).To(HaveLen(32 * k))
+		})
+
+		It("should return an error when the check fails", func() {
+			indices := RandomIndices(n)
+			secret := secp256k1.RandomFn()
+			errUnhealthy := errors.New("unhealthy")
+			check := func([]byte) error { return errUnhealthy }
+
+			shares := make(Shares, n)
+			err := ShareSecret(&shares, indices, secret, k, WithEntropyCheck(check))
+			Expect(errors.Is(err, ErrWeakRandomness)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("unhealthy"))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// OpenAt evaluates the polynomial that passes through the given shares at x,
+// so that Open(shares) is the same as OpenAt(shares, 0). The same assumptions
+// as for Open apply: the shares must have distinct indices and, for the
+// result to be the value of the sharing polynomial, there must be at least k
+// valid shares.
+func OpenAt(shares Shares, x *secp256k1.Fn) secp256k1.Fn {
+	return openAt(len(shares), func(i int) *Share { return &shares[i] }, x)
+}
+
+// Reveal computes the values of the sharing polynomial at the given auxiliary
+// points from a qualified set of shares, for the gradual release of a secret.
+// Each reveal is a share at an auxiliary point, so once r of them have been
+// published, any k - r of the original shares, together with the reveals, open
+// the secret; see RemainingShares. Publishing the reveals one at a time thus
+// lowers the number of holders that need to cooperate one step at a time,
+// until with k reveals the secret is public.
+//
+// The points must be non-zero, distinct and different from the index of every
+// share in the sharing, not just those passed to this function, since a reveal
+// at the index of a share discloses that share. An error is returned if any
+// of the points is zero, repeated or the index of one of the given shares.
+func Reveal(shares Shares, points []secp256k1.Fn) (Shares, error) {
+	if err := checkRevealPoints(points, len(shares), func(i int) *Share { return &shares[i] }); err != nil {
+		return nil, err
+	}
+	reveals := make(Shares, len(points))
+	for i := range points {
+		reveals[i] = NewShare(points[i], OpenAt(shares, &points[i]))
+	}
+	return reveals, nil
+}
+
+// RevealVerifiable is the same as Reveal, but for a verifiable sharing. The
+// reveals include the values of the decommitment polynomial, so anyone can
+// check them against the commitment of the sharing with IsValid before
+// relying on them.
+func RevealVerifiable(vshares VerifiableShares, points []secp256k1.Fn) (VerifiableShares, error) {
+	share := func(i int) *Share { return &vshares[i].Share }
+	if err := checkRevealPoints(points, len(vshares), share); err != nil {
+		return nil, err
+	}
+	decom := func(i int) *Share {
+		return &Share{Index: vshares[i].Share.Index, Value: vshares[i].Decommitment}
+	}
+	reveals := make(VerifiableShares, len(points))
+	for i := range points {
+		reveals[i] = NewVerifiableShare(
+			NewShare(points[i], openAt(len(vshares), share, &points[i])),
+			openAt(len(vshares), decom, &points[i]),
+		)
+	}
+	return reveals, nil
+}
+
+// RemainingShares returns the number of shares of a sharing with threshold k
+// that are needed to open the secret once the given number of reveals have
+// been published. This is zero once there are k reveals.
+func RemainingShares(k, revealed int) int {
+	if revealed >= k {
+		return 0
+	}
+	return k - revealed
+}
+
+func checkRevealPoints(points []secp256k1.Fn, n int, share func(int) *Share) error {
+	for i := range points {
+		if points[i].IsZero() {
+			return fmt.Errorf("reveal point %v is zero", i)
+		}
+		for j := 0; j < i; j++ {
+			if points[i].Eq(&points[j]) {
+				return fmt.Errorf("reveal points %v and %v are equal", j, i)
+			}
+		}
+		for j := 0; j < n; j++ {
+			if share(j).IndexEq(&points[i]) {
+				return fmt.Errorf("reveal point %v is the index of share %v", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluates the polynomial through the n shares at x using the Lagrange basis
+// polynomials, the i-th of which at x is the product of (x_j - x) / (x_j - x_i)
+// over j != i. The denominators are inverted together with BatchInvert.
+func openAt(n int, share func(int) *Share, x *secp256k1.Fn) secp256k1.Fn {
+	nums := make([]secp256k1.Fn, n)
+	denoms := make([]secp256k1.Fn, n)
+	var acc, tmp secp256k1.Fn
+	defer Wipe(&tmp)
+	for i := 0; i < n; i++ {
+		si := share(i)
+		nums[i].SetU16(1)
+		denoms[i].SetU16(1)
+		for j := 0; j < n; j++ {
+			sj := share(j)
+			if si.Index.Eq(&sj.Index) {
+				continue
+			}
+			tmp.Negate(&si.Index)
+			tmp.Add(&tmp, &sj.Index)
+			denoms[i].Mul(&denoms[i], &tmp)
+			tmp.Negate(x)
+			tmp.Add(&tmp, &sj.Index)
+			nums[i].Mul(&nums[i], &tmp)
+		}
+	}
+	BatchInvert(denoms)
+	for i := 0; i < n; i++ {
+		tmp.Mul(&nums[i], &denoms[i])
+		tmp.Mul(&tmp, &share(i).Value)
+		acc.Add(&acc, &tmp)
+	}
+	return acc
+}
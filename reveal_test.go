@@ -0,0 +1,106 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Gradual release", func() {
+	trials := 20
+
+	// Auxiliary points that can not collide with sequential share indices.
+	auxPoints := func(n, r int) []secp256k1.Fn {
+		points := make([]secp256k1.Fn, r)
+		for i := range points {
+			points[i].SetU16(uint16(n + 1 + i))
+		}
+		return points
+	}
+
+	Context("opening at a point", func() {
+		It("should agree with Open at zero and with the shares at their indices", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				secret := secp256k1.RandomFn()
+				shares := make(Shares, n)
+				Expect(ShareSecret(&shares, SequentialIndices(n), secret, k)).To(Succeed())
+
+				zero := secp256k1.NewFnFromU16(0)
+				opened := OpenAt(shares[:k], &zero)
+				Expect(opened.Eq(&secret)).To(BeTrue())
+
+				for j := range shares {
+					y := OpenAt(shares[n-k:], &shares[j].Index)
+					Expect(y.Eq(&shares[j].Value)).To(BeTrue())
+				}
+			}
+		})
+	})
+
+	Context("revealing", func() {
+		It("should reduce the number of shares needed to open by one per reveal", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				secret := secp256k1.RandomFn()
+				shares := make(Shares, n)
+				Expect(ShareSecret(&shares, SequentialIndices(n), secret, k)).To(Succeed())
+
+				reveals, err := Reveal(shares[:k], auxPoints(n, k))
+				Expect(err).ToNot(HaveOccurred())
+				for r := 0; r <= k; r++ {
+					remaining := RemainingShares(k, r)
+					Expect(remaining).To(Equal(k - r))
+
+					rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+					subset := append(append(Shares{}, shares[:remaining]...), reveals[:r]...)
+					opened := Open(subset)
+					Expect(opened.Eq(&secret)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should give verifiable reveals that are valid for the commitment", func() {
+			h := PedersenH()
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				secret := secp256k1.RandomFn()
+				vshares := make(VerifiableShares, n)
+				c := NewCommitmentWithCapacity(k)
+				Expect(VShareSecret(&vshares, &c, SequentialIndices(n), h, secret, k)).To(Succeed())
+
+				reveals, err := RevealVerifiable(vshares[:k], auxPoints(n, k))
+				Expect(err).ToNot(HaveOccurred())
+				for j := range reveals {
+					Expect(IsValid(h, &c, &reveals[j])).To(BeTrue())
+				}
+				opened := Open(reveals.Shares())
+				Expect(opened.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an error for invalid points", func() {
+			n, k := 5, 3
+			shares := make(Shares, n)
+			Expect(ShareSecret(&shares, SequentialIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+
+			valid := auxPoints(n, 1)[0]
+			for _, points := range [][]secp256k1.Fn{
+				{secp256k1.NewFnFromU16(0)},
+				{valid, valid},
+				{shares[0].Index},
+			} {
+				_, err := Reveal(shares[:k], points)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+	})
+})
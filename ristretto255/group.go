@@ -0,0 +1,342 @@
+// Package ristretto255 implements Shamir secret sharing and Pedersen
+// verifiable secret sharing over the ristretto255 group. Ristretto255 is a
+// prime order group built from Curve25519, so unlike raw edwards25519 points
+// it has no cofactor, and Pedersen commitments over it do not admit small
+// subgroup malleability. It is the recommended choice when an Edwards curve is
+// required.
+//
+// The API mirrors the secp256k1 based API of the top level shamir package.
+package ristretto255
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+
+	"github.com/gtank/ristretto255"
+	"github.com/renproject/shamir/internal/sharing"
+	"github.com/renproject/surge"
+)
+
+// ScalarSize is the number of bytes in the encoding of a Scalar.
+const ScalarSize = 32
+
+// PointSize is the number of bytes in the encoding of a Point.
+const PointSize = 32
+
+// CommitmentPointSize is the number of bytes in the encoding of each point of
+// a Commitment.
+const CommitmentPointSize = PointSize
+
+// The prime order of the group, which is the modulus of the scalar field.
+var order, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+// CurveName returns the name of the group, "ristretto255".
+func CurveName() string {
+	return "ristretto255"
+}
+
+// FieldOrder returns the order of the scalar field, which is the order of the
+// group. The caller may modify the returned integer.
+func FieldOrder() *big.Int {
+	return new(big.Int).Set(order)
+}
+
+// A Scalar is an element of the ristretto255 scalar field, that is, an
+// integer modulo the prime order of the group. The zero value is the zero
+// scalar. Like secp256k1.Fn, operations store their result in the receiver,
+// and all operations are safe for aliasing.
+type Scalar struct {
+	inner ristretto255.Scalar
+}
+
+// NewScalarFromU16 returns a scalar equal to the given value.
+func NewScalarFromU16(v uint16) Scalar {
+	var s Scalar
+	s.SetU16(v)
+	return s
+}
+
+// RandomScalar returns a uniformly random scalar. It panics if the system
+// source of randomness fails.
+func RandomScalar() Scalar {
+	s, err := randomScalar(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Returns a uniformly random scalar using bytes from the given source.
+func randomScalar(r io.Reader) (Scalar, error) {
+	var bs [64]byte
+	if err := sharing.ReadRandom(r, bs[:]); err != nil {
+		return Scalar{}, err
+	}
+	var s Scalar
+	s.inner.FromUniformBytes(bs[:])
+	for i := range bs {
+		bs[i] = 0
+	}
+	return s, nil
+}
+
+// SetU16 sets the scalar to the given value.
+func (s *Scalar) SetU16(v uint16) {
+	var bs [ScalarSize]byte
+	binary.LittleEndian.PutUint16(bs[:], v)
+	if err := s.inner.Decode(bs[:]); err != nil {
+		panic(fmt.Sprintf("invariant violation: small scalar not canonical: %v", err))
+	}
+}
+
+// Clear sets the scalar to zero.
+func (s *Scalar) Clear() {
+	s.inner.Zero()
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (s *Scalar) Add(a, b *Scalar) {
+	s.inner.Add(&a.inner, &b.inner)
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (s *Scalar) Sub(a, b *Scalar) {
+	s.inner.Subtract(&a.inner, &b.inner)
+}
+
+// Mul computes a * b and stores the result in the receiver.
+func (s *Scalar) Mul(a, b *Scalar) {
+	s.inner.Multiply(&a.inner, &b.inner)
+}
+
+// Negate computes -a and stores the result in the receiver.
+func (s *Scalar) Negate(a *Scalar) {
+	s.inner.Negate(&a.inner)
+}
+
+// Inverse computes the multiplicative inverse of a and stores the result in
+// the receiver. The inverse of zero is zero.
+func (s *Scalar) Inverse(a *Scalar) {
+	s.inner.Invert(&a.inner)
+}
+
+// BatchInvert replaces every element of the slice with its inverse, using a
+// single inversion, as for shamir.BatchInvert. The elements must be non-zero;
+// if any of them is zero, every element is set to zero.
+func BatchInvert(xs []Scalar) {
+	if len(xs) == 0 {
+		return
+	}
+	prefix := make([]Scalar, len(xs))
+	prefix[0] = xs[0]
+	for i := 1; i < len(xs); i++ {
+		prefix[i].Mul(&prefix[i-1], &xs[i])
+	}
+	var inv, tmp Scalar
+	inv.Inverse(&prefix[len(xs)-1])
+	for i := len(xs) - 1; i > 0; i-- {
+		tmp.Mul(&inv, &prefix[i-1])
+		inv.Mul(&inv, &xs[i])
+		xs[i] = tmp
+	}
+	xs[0] = inv
+}
+
+// IsZero returns true if the scalar is zero.
+func (s *Scalar) IsZero() bool {
+	var zero ristretto255.Scalar
+	return s.inner.Equal(zero.Zero()) == 1
+}
+
+// Eq returns true if the two scalars are equal.
+func (s *Scalar) Eq(other *Scalar) bool {
+	return s.inner.Equal(&other.inner) == 1
+}
+
+// PutBytes writes the 32 byte little endian encoding of the scalar into the
+// destination slice, which must have length at least ScalarSize.
+func (s *Scalar) PutBytes(dst []byte) {
+	s.inner.Encode(dst[:0])
+}
+
+// SetBytes sets the scalar from its 32 byte little endian encoding. An error
+// is returned if the encoding is not canonical.
+func (s *Scalar) SetBytes(bs []byte) error {
+	if len(bs) != ScalarSize {
+		return fmt.Errorf("invalid scalar length: expected %v bytes, got %v", ScalarSize, len(bs))
+	}
+	return s.inner.Decode(bs)
+}
+
+// Generate implements the quick.Generator interface.
+func (s Scalar) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomScalar())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (s Scalar) SizeHint() int { return ScalarSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (s Scalar) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	s.PutBytes(buf)
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (s *Scalar) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < ScalarSize || rem < ScalarSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := s.SetBytes(buf[:ScalarSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[ScalarSize:], rem - ScalarSize, nil
+}
+
+// A Point is an element of the prime order ristretto255 group. The zero value
+// is the identity element. Operations store their result in the receiver, and
+// are safe for aliasing.
+type Point struct {
+	inner ristretto255.Element
+}
+
+var zeroElement ristretto255.Element
+
+// Returns the underlying element, treating the zero value of the struct as
+// the identity. The receiver is not modified, so that reading a point is safe
+// for concurrent use.
+func (p *Point) elem() *ristretto255.Element {
+	if p.inner == zeroElement {
+		return ristretto255.NewElement()
+	}
+	return &p.inner
+}
+
+// NewIdentity returns the identity element of the group.
+func NewIdentity() Point {
+	var p Point
+	p.inner.Zero()
+	return p
+}
+
+// RandomPoint returns a uniformly random group element. It panics if the
+// system source of randomness fails.
+func RandomPoint() Point {
+	var bs [64]byte
+	if _, err := rand.Read(bs[:]); err != nil {
+		panic(fmt.Sprintf("could not generate random bytes: %v", err))
+	}
+	var p Point
+	p.inner.FromUniformBytes(bs[:])
+	return p
+}
+
+// SetUniformBytes maps 64 uniformly random bytes to a group element using the
+// ristretto255 hash to group map. When the bytes are the output of a hash
+// function, nobody knows the discrete logarithm of the resulting element with
+// respect to the base point.
+func (p *Point) SetUniformBytes(bs []byte) {
+	if len(bs) != 64 {
+		panic(fmt.Sprintf("invalid slice length: expected 64, got %v", len(bs)))
+	}
+	p.inner.FromUniformBytes(bs)
+}
+
+// BaseExp computes the scalar multiple of the base point and stores the
+// result in the receiver.
+func (p *Point) BaseExp(s *Scalar) {
+	p.inner.ScalarBaseMult(&s.inner)
+}
+
+// Scale computes the scalar multiple of a and stores the result in the
+// receiver.
+func (p *Point) Scale(a *Point, s *Scalar) {
+	p.inner.ScalarMult(&s.inner, a.elem())
+}
+
+// Add computes a + b and stores the result in the receiver.
+func (p *Point) Add(a, b *Point) {
+	p.inner.Add(a.elem(), b.elem())
+}
+
+// Sub computes a - b and stores the result in the receiver.
+func (p *Point) Sub(a, b *Point) {
+	p.inner.Subtract(a.elem(), b.elem())
+}
+
+// Eq returns true if the two points are equal.
+func (p *Point) Eq(other *Point) bool {
+	return p.elem().Equal(other.elem()) == 1
+}
+
+// IsIdentity returns true if the point is the identity element.
+func (p *Point) IsIdentity() bool {
+	return p.elem().Equal(ristretto255.NewElement()) == 1
+}
+
+// PutBytes writes the 32 byte canonical encoding of the point into the
+// destination slice, which must have length at least PointSize.
+func (p *Point) PutBytes(dst []byte) {
+	p.elem().Encode(dst[:0])
+}
+
+// SetBytes sets the point from its 32 byte canonical encoding. An error is
+// returned if the encoding is not valid.
+func (p *Point) SetBytes(bs []byte) error {
+	if len(bs) != PointSize {
+		return fmt.Errorf("invalid point length: expected %v bytes, got %v", PointSize, len(bs))
+	}
+	if err := p.inner.Decode(bs); err != nil {
+		return errors.New("invalid point encoding")
+	}
+	return nil
+}
+
+// Generate implements the quick.Generator interface.
+func (p Point) Generate(_ *mrand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(RandomPoint())
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (p Point) SizeHint() int { return PointSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (p Point) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	p.PutBytes(buf)
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (p *Point) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	if len(buf) < PointSize || rem < PointSize {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	if err := p.SetBytes(buf[:PointSize]); err != nil {
+		return buf, rem, err
+	}
+	return buf[PointSize:], rem - PointSize, nil
+}
+
+// PedersenHFromSeed derives a Pedersen generator from the given domain
+// separation tag by hashing it with SHA-512 and mapping the result to the
+// group, so nobody knows its discrete logarithm with respect to the base
+// point.
+func PedersenHFromSeed(domain []byte) Point {
+	hash := sha512.Sum512(domain)
+	var h Point
+	h.SetUniformBytes(hash[:])
+	return h
+}
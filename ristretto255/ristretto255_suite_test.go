@@ -0,0 +1,13 @@
+package ristretto255_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRistretto255(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ristretto255 Suite")
+}
@@ -0,0 +1,281 @@
+package ristretto255_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/ristretto255"
+)
+
+var _ = Describe("Ristretto255 backend", func() {
+	trials := 20
+	n := 10
+
+	randomIndices := func(n int) []Scalar {
+		indices := make([]Scalar, n)
+		for i := range indices {
+			indices[i] = RandomScalar()
+		}
+		return indices
+	}
+
+	Context("group operations", func() {
+		It("should treat the zero value point as the identity", func() {
+			var zero Point
+			p := RandomPoint()
+			identity := NewIdentity()
+			Expect(zero.IsIdentity()).To(BeTrue())
+			Expect(zero.Eq(&identity)).To(BeTrue())
+			Expect(zero.Eq(&p)).To(BeFalse())
+
+			var sum Point
+			sum.Add(&p, &zero)
+			Expect(sum.Eq(&p)).To(BeTrue())
+		})
+
+		It("should satisfy the distributive law for scalar multiplication", func() {
+			for i := 0; i < trials; i++ {
+				a, b := RandomScalar(), RandomScalar()
+				var sum Scalar
+				sum.Add(&a, &b)
+
+				var ga, gb, gsum, expected Point
+				ga.BaseExp(&a)
+				gb.BaseExp(&b)
+				gsum.BaseExp(&sum)
+				expected.Add(&ga, &gb)
+				Expect(gsum.Eq(&expected)).To(BeTrue())
+
+				var diff Point
+				diff.Sub(&gsum, &gb)
+				Expect(diff.Eq(&ga)).To(BeTrue())
+			}
+		})
+
+		It("should compute inverses", func() {
+			for i := 0; i < trials; i++ {
+				a := RandomScalar()
+				var inv, prod Scalar
+				inv.Inverse(&a)
+				prod.Mul(&a, &inv)
+				one := NewScalarFromU16(1)
+				Expect(prod.Eq(&one)).To(BeTrue())
+			}
+		})
+
+		It("should reject non canonical encodings", func() {
+			bs := make([]byte, ScalarSize)
+			for i := range bs {
+				bs[i] = 0xff
+			}
+			var s Scalar
+			Expect(s.SetBytes(bs)).ToNot(Succeed())
+			var p Point
+			Expect(p.SetBytes(bs)).ToNot(Succeed())
+		})
+
+		It("should report the order of the scalar field", func() {
+			// The order minus one is the negation of one.
+			m := FieldOrder()
+			m.Sub(m, big.NewInt(1))
+			bs := make([]byte, ScalarSize)
+			for i, b := range m.Bytes() {
+				bs[len(m.Bytes())-1-i] = b
+			}
+			var x Scalar
+			Expect(x.SetBytes(bs)).To(Succeed())
+			one := NewScalarFromU16(1)
+			x.Add(&x, &one)
+			Expect(x.IsZero()).To(BeTrue())
+			Expect(CommitmentPointSize).To(Equal(PointSize))
+			Expect(CurveName()).ToNot(BeEmpty())
+		})
+	})
+
+	Context("sharing", func() {
+		It("should reconstruct the secret from any k shares", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(ShareSecret(&shares, indices, secret, k)).To(Succeed())
+
+				rand.Shuffle(n, func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+				recon := Open(shares[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should return an ErrInvalidThreshold when k is not between 1 and n", func() {
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for _, k := range []int{-1, 0, n + 1} {
+				err := ShareSecret(&shares, indices, RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+				err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), k)
+				Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+			}
+
+			err := ShareSecret(&shares, indices, RandomScalar(), n+1)
+			var kErr *shamir.ErrKTooLarge
+			Expect(errors.As(err, &kErr)).To(BeTrue())
+			Expect(*kErr).To(Equal(shamir.ErrKTooLarge{K: n + 1, N: n}))
+		})
+
+		It("should return an error when the source of randomness fails", func() {
+			shamir.SetRandomSource(bytes.NewReader(nil))
+			defer shamir.SetRandomSource(nil)
+
+			indices := randomIndices(n)
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			err := ShareSecret(&shares, indices, RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 2)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+
+			// With k = 1 only the decommitment is random.
+			err = VShareSecret(&vshares, &c, indices, PedersenH(), RandomScalar(), 1)
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		})
+	})
+
+	Context("verifiable sharing", func() {
+		h := PedersenH()
+
+		It("should produce valid shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				k := shamirutil.RandRange(1, n)
+				secret := RandomScalar()
+				Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+				for j := range vshares {
+					Expect(IsValid(h, c, &vshares[j])).To(BeTrue())
+				}
+
+				recon := Open(vshares.Shares()[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+			}
+		})
+
+		It("should detect perturbed shares", func() {
+			indices := randomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				// When k = 1 the index does not affect validity, so k is at
+				// least 2.
+				Expect(VShareSecret(&vshares, &c, indices, h, RandomScalar(), shamirutil.RandRange(2, n))).To(Succeed())
+				j := rand.Intn(n)
+				switch rand.Intn(3) {
+				case 0:
+					vshares[j].Share.Index = RandomScalar()
+				case 1:
+					vshares[j].Share.Value = RandomScalar()
+				default:
+					vshares[j].Decommitment = RandomScalar()
+				}
+				Expect(IsValid(h, c, &vshares[j])).To(BeFalse())
+			}
+		})
+
+		It("should be homomorphic under addition and scaling", func() {
+			indices := randomIndices(n)
+			vshares1 := make(VerifiableShares, n)
+			vshares2 := make(VerifiableShares, n)
+			c1 := NewCommitmentWithCapacity(n)
+			c2 := NewCommitmentWithCapacity(n)
+			sum := NewCommitmentWithCapacity(n)
+			scaled := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				Expect(VShareSecret(&vshares1, &c1, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				Expect(VShareSecret(&vshares2, &c2, indices, h, RandomScalar(), shamirutil.RandRange(1, n))).To(Succeed())
+				scale := RandomScalar()
+				sum.Add(c1, c2)
+				scaled.Scale(c1, &scale)
+				for j := range indices {
+					var s VerifiableShare
+					s.Add(&vshares1[j], &vshares2[j])
+					Expect(IsValid(h, sum, &s)).To(BeTrue())
+					s.Scale(&vshares1[j], &scale)
+					Expect(IsValid(h, scaled, &s)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should derive a fixed Pedersen generator", func() {
+			h1, h2 := PedersenH(), PedersenH()
+			Expect(h1.Eq(&h2)).To(BeTrue())
+			Expect(h1.IsIdentity()).To(BeFalse())
+
+			h3 := PedersenHFromSeed([]byte(PedersenDomain))
+			Expect(h3.Eq(&h1)).To(BeTrue())
+			h4 := PedersenHFromSeed([]byte("another domain"))
+			Expect(h4.Eq(&h1)).To(BeFalse())
+			Expect(h4.IsIdentity()).To(BeFalse())
+		})
+	})
+
+	Context("surge marshalling", func() {
+		types := []reflect.Type{
+			reflect.TypeOf(Scalar{}),
+			reflect.TypeOf(Point{}),
+			reflect.TypeOf(Share{}),
+			reflect.TypeOf(Shares{}),
+			reflect.TypeOf(VerifiableShare{}),
+			reflect.TypeOf(VerifiableShares{}),
+			reflect.TypeOf(Commitment{}),
+		}
+
+		// Points have many internal representations of the same group
+		// element, so they are compared using Eq rather than deep equality.
+		It("should marshal and unmarshal commitments", func() {
+			for i := 0; i < trials; i++ {
+				c := make(Commitment, rand.Intn(10))
+				for j := range c {
+					c[j] = RandomPoint()
+				}
+				bs, err := surge.ToBinary(c)
+				Expect(err).ToNot(HaveOccurred())
+				var decoded Commitment
+				Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+				Expect(decoded.Eq(c)).To(BeTrue())
+			}
+		})
+
+		for _, t := range types {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					if t != reflect.TypeOf(Point{}) && t != reflect.TypeOf(Commitment{}) {
+						Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					}
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package rs_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/renproject/shamir/shamirutil"
+)
+
+func FuzzDecoderDecode(f *testing.F) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 8; i++ {
+		seed := make([]byte, 3+64*32)
+		r.Read(seed)
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := shamirutil.CheckDecode(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package rs
 
 import (
+	"runtime"
+	"sync"
+
 	"github.com/renproject/secp256k1"
 	"github.com/renproject/shamir/eea"
 	"github.com/renproject/shamir/poly"
@@ -11,13 +14,23 @@ import (
 // called the indices. Each instance of a decoder corresponds to a specific set
 // of indices; this allows multiple decodings to use the same relatively
 // expensive setup.
+//
+// A decoder holds scratch space that is modified by Decode, and so is not safe
+// for concurrent use. To decode from multiple goroutines, either give each
+// goroutine its own decoder with Clone, which shares the setup but not the
+// scratch space, or use DecodeBatch.
 type Decoder struct {
+	// The setup, which depends only on the indices and threshold, and is not
+	// modified by decoding. It is shared by clones.
 	n, k         int
 	indices      []secp256k1.Fn
 	interpolator poly.Interpolator
-	eea          eea.Stepper
+	g0           poly.Poly
 
-	g0             poly.Poly
+	// The scratch space, which is overwritten by each call to Decode. After a
+	// decoding, it holds the result and the error locator, which is the t
+	// term of the EEA.
+	eea            eea.Stepper
 	interpPoly     poly.Poly
 	f1, r          poly.Poly
 	errors         []secp256k1.Fn
@@ -71,12 +84,32 @@ func NewDecoder(inds []secp256k1.Fn, k int) Decoder {
 	}
 }
 
+// Clone returns a decoder for the same indices and threshold that shares the
+// immutable setup of this decoder, but has its own scratch space. The clone
+// and the original can be used concurrently with each other, and cloning is
+// much cheaper than constructing a new decoder. Neither should be unmarshalled
+// into while the other is in use, since that would overwrite the shared setup.
+func (dec *Decoder) Clone() Decoder {
+	return Decoder{
+		n: dec.n, k: dec.k,
+		indices:      dec.indices,
+		interpolator: dec.interpolator,
+		eea:          eea.NewStepperWithCapacity(dec.n + 1),
+
+		g0:         dec.g0,
+		interpPoly: poly.NewWithCapacity(dec.n),
+		f1:         poly.NewWithCapacity(dec.n), r: poly.NewWithCapacity(dec.n),
+		errors: make([]secp256k1.Fn, dec.k),
+	}
+}
+
 // Decode executes the RS decoding algorithm to try to recover the encoded
 // polynomial. If decoding was successful, the polynomial is returned and the
 // returned boolean is true. Otherwise, the polynomial is nil and the boolean
 // is false. Decoding will fail if there are more than (n - k)/2 errors, but
 // less than n - k. If there are more than n - k errors, the output behaviour
-// is undefined.
+// is undefined. The returned polynomial is the decoder's scratch space, and so
+// is overwritten by the next call to Decode.
 func (dec *Decoder) Decode(values []secp256k1.Fn) (*poly.Poly, bool) {
 	threshold := (dec.n + dec.k) / 2
 	dec.errorsComputed = false
@@ -84,6 +117,15 @@ func (dec *Decoder) Decode(values []secp256k1.Fn) (*poly.Poly, bool) {
 	// Interpolate
 	dec.interpolator.Interpolate(values, &dec.interpPoly)
 
+	// The partial GCD below does not handle the zero codeword, but it always
+	// decodes to the zero polynomial with no errors.
+	if dec.interpPoly.IsZero() {
+		dec.f1.Zero()
+		dec.errors = dec.errors[:0]
+		dec.errorsComputed = true
+		return &dec.f1, true
+	}
+
 	// Partial GCD
 	dec.eea.Init(dec.g0, dec.interpPoly)
 	for dec.eea.Rem().Degree() >= threshold {
@@ -99,6 +141,50 @@ func (dec *Decoder) Decode(values []secp256k1.Fn) (*poly.Poly, bool) {
 	return nil, false
 }
 
+// DecodeBatch decodes each of the given codewords, which all have the indices
+// of the decoder, as for Decode. The i-th returned polynomial and boolean are
+// the result of decoding values[i]; if decoding failed, the polynomial is nil.
+// The returned polynomials do not share memory with the decoder. The codewords
+// are decoded in parallel by goroutines that each decode with a clone of the
+// decoder. DecodeBatch does not
+// modify the decoder, and so does not affect the result of ErrorIndices.
+func (dec *Decoder) DecodeBatch(values [][]secp256k1.Fn) ([]poly.Poly, []bool) {
+	polys := make([]poly.Poly, len(values))
+	oks := make([]bool, len(values))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(values) {
+		workers = len(values)
+	}
+	var wg sync.WaitGroup
+	var next int
+	var mu sync.Mutex
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := dec.Clone()
+			for {
+				mu.Lock()
+				i := next
+				next++
+				mu.Unlock()
+				if i >= len(values) {
+					return
+				}
+				if f1, ok := worker.Decode(values[i]); ok {
+					polys[i] = poly.NewWithCapacity(len(*f1))
+					polys[i].Set(*f1)
+					oks[i] = true
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return polys, oks
+}
+
 // ErrorIndices returns a slice of indices that correspond to the error
 // locations for the most recent execution of the decoding algorithm. If the
 // decoding algorithm has not been run yet or there are no errors, a nil slice
@@ -107,6 +193,9 @@ func (dec *Decoder) ErrorIndices() []secp256k1.Fn {
 	// If we have already computed the errors for this decoding, short ciruit
 	// and yield the cached result.
 	if dec.errorsComputed {
+		if len(dec.errors) == 0 {
+			return nil
+		}
 		return dec.errors
 	}
 
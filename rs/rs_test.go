@@ -2,6 +2,7 @@ package rs_test
 
 import (
 	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/renproject/secp256k1"
@@ -55,6 +56,22 @@ var _ = Describe("Reed-Solomon Decoding", func() {
 			}
 		})
 
+		It("should recover the zero polynomial", func() {
+			trials := 20
+			maxN := 20
+
+			for i := 0; i < trials; i++ {
+				n := rand.Intn(maxN) + 1
+				k := rand.Intn(n) + 1
+				decoder := NewDecoder(shamirutil.RandomIndices(n), k)
+
+				reconstructed, ok := decoder.Decode(make([]secp256k1.Fn, n))
+				Expect(ok).To(BeTrue())
+				Expect(reconstructed.IsZero()).To(BeTrue())
+				Expect(decoder.ErrorIndices()).To(BeNil())
+			}
+		})
+
 		It("should recover the polynomial when there are fewer than t errors", func() {
 			trials := 100
 			maxN := 20
@@ -174,6 +191,162 @@ var _ = Describe("Reed-Solomon Decoding", func() {
 			Expect(decoder.ErrorIndices()).To(BeNil())
 		})
 	})
+
+	Context("when taking snapshots of a decoder", func() {
+		n, k := 15, 5
+		indices := shamirutil.RandomIndices(n)
+		p := poly.NewWithCapacity(k)
+		values := make([]secp256k1.Fn, n)
+
+		codeword := func(errors []int) {
+			polyutil.SetRandomPolynomial(&p, k-1)
+			for j, index := range indices {
+				values[j] = p.Evaluate(index)
+			}
+			addErrors(values, errors)
+		}
+
+		It("should restore the result of the last decoding", func() {
+			decoder := NewDecoder(indices, k)
+			codeword([]int{1, 4, 9})
+			_, ok := decoder.Decode(values)
+			Expect(ok).To(BeTrue())
+			snapshot, err := decoder.Snapshot()
+			Expect(err).ToNot(HaveOccurred())
+
+			restored := NewDecoder(indices, k)
+			Expect(restored.Restore(snapshot)).To(Succeed())
+			Expect(restored.ErrorIndices()).To(HaveLen(3))
+			for i, index := range decoder.ErrorIndices() {
+				Expect(restored.ErrorIndices()[i].Eq(&index)).To(BeTrue())
+			}
+
+			// The restored decoder can continue decoding.
+			codeword([]int{0, 2})
+			reconstructed, ok := restored.Decode(values)
+			Expect(ok).To(BeTrue())
+			Expect(reconstructed.Eq(p)).To(BeTrue())
+			Expect(restored.ErrorIndices()).To(HaveLen(2))
+		})
+
+		It("should reject snapshots of other decoders", func() {
+			decoder := NewDecoder(indices, k)
+			snapshot, err := decoder.Snapshot()
+			Expect(err).ToNot(HaveOccurred())
+
+			other := NewDecoder(indices, k+1)
+			Expect(other.Restore(snapshot)).To(Equal(ErrIncompatibleSnapshot))
+			other = NewDecoder(shamirutil.RandomIndices(n), k)
+			Expect(other.Restore(snapshot)).To(Equal(ErrIncompatibleSnapshot))
+			Expect(decoder.Restore(snapshot[:len(snapshot)-1])).ToNot(Succeed())
+		})
+	})
+
+	Context("when decoding batches of messages", func() {
+		It("should agree with decoding the messages one at a time", func() {
+			trials := 20
+			maxN := 20
+			batchLen := 30
+			var n, k, t int
+
+			l := make([]int, maxN)
+
+			for i := 0; i < trials; i++ {
+				n = rand.Intn(maxN-2) + 3
+				k = rand.Intn(n-2) + 1
+				t = (n - k) / 2
+				indices := shamirutil.RandomIndices(n)
+				decoder := NewDecoder(indices, k)
+
+				// Each codeword has either a recoverable or an unrecoverable
+				// number of errors.
+				polys := make([]poly.Poly, batchLen)
+				values := make([][]secp256k1.Fn, batchLen)
+				for j := range values {
+					polys[j] = poly.NewWithCapacity(k)
+					polyutil.SetRandomPolynomial(&polys[j], k-1)
+					values[j] = make([]secp256k1.Fn, n)
+					for m, index := range indices {
+						values[j][m] = polys[j].Evaluate(index)
+					}
+					e := rand.Intn(n + 1)
+					eeautil.RandomSubset(&l, e, n)
+					addErrors(values[j], l)
+				}
+
+				decoded, oks := decoder.DecodeBatch(values)
+				Expect(len(decoded)).To(Equal(batchLen))
+				Expect(len(oks)).To(Equal(batchLen))
+				for j := range values {
+					reconstructed, ok := decoder.Decode(values[j])
+					Expect(oks[j]).To(Equal(ok))
+					if ok {
+						Expect(decoded[j].Eq(*reconstructed)).To(BeTrue())
+						if len(l) <= t {
+							Expect(decoded[j].Eq(polys[j])).To(BeTrue())
+						}
+					} else {
+						Expect(decoded[j]).To(BeNil())
+					}
+				}
+			}
+		})
+
+		It("should not modify the decoder", func() {
+			n, k := 10, 4
+			indices := shamirutil.RandomIndices(n)
+			decoder := NewDecoder(indices, k)
+			values := make([]secp256k1.Fn, n)
+			for j := range values {
+				values[j] = secp256k1.RandomFn()
+			}
+			decoder.Decode(values)
+			expected := decoder.ErrorIndices()
+
+			decoder.DecodeBatch([][]secp256k1.Fn{values, make([]secp256k1.Fn, n)})
+			Expect(decoder.ErrorIndices()).To(Equal(expected))
+		})
+
+		It("should decode concurrently with clones of the decoder", func() {
+			n, k, goroutines := 20, 7, 8
+			indices := shamirutil.RandomIndices(n)
+			decoder := NewDecoder(indices, k)
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				clone := decoder.Clone()
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					p := poly.NewWithCapacity(k)
+					values := make([]secp256k1.Fn, n)
+					l := make([]int, n)
+					for i := 0; i < 20; i++ {
+						polyutil.SetRandomPolynomial(&p, k-1)
+						for j, index := range indices {
+							values[j] = p.Evaluate(index)
+						}
+						eeautil.RandomSubset(&l, (n-k)/2, n)
+						addErrors(values, l)
+
+						reconstructed, ok := clone.Decode(values)
+						Expect(ok).To(BeTrue())
+						Expect(reconstructed.Eq(p)).To(BeTrue())
+						Expect(len(clone.ErrorIndices())).To(Equal(len(l)))
+					}
+				}()
+			}
+			wg.Wait()
+		})
+
+		It("should handle empty batches", func() {
+			decoder := NewDecoder(shamirutil.RandomIndices(5), 2)
+			decoded, oks := decoder.DecodeBatch(nil)
+			Expect(decoded).To(BeEmpty())
+			Expect(oks).To(BeEmpty())
+		})
+	})
 })
 
 func BenchmarkDecodeNoErrors(b *testing.B) {
@@ -240,3 +413,32 @@ func addErrors(values []secp256k1.Fn, subset []int) {
 		values[i] = secp256k1.RandomFn()
 	}
 }
+
+func BenchmarkDecodeBatch(b *testing.B) {
+	const n int = 100
+	const k int = 34
+	const batchLen int = 64
+	degree := k - 1
+
+	poly := poly.NewWithCapacity(degree + 1)
+	values := make([][]secp256k1.Fn, batchLen)
+	indices := [n]secp256k1.Fn{}
+
+	for i := range indices {
+		indices[i].SetU16(uint16(i + 1))
+	}
+	decoder := NewDecoder(indices[:], k)
+
+	for j := range values {
+		polyutil.SetRandomPolynomial(&poly, degree)
+		values[j] = make([]secp256k1.Fn, n)
+		for m, index := range indices {
+			values[j][m] = poly.Evaluate(index)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = decoder.DecodeBatch(values)
+	}
+}
@@ -0,0 +1,163 @@
+package rs
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/eea"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/surge"
+)
+
+// ErrIncompatibleSnapshot is returned by Restore when the snapshot was taken
+// from a decoder for different indices or a different threshold.
+var ErrIncompatibleSnapshot = errors.New("snapshot is for a different decoder")
+
+// Snapshot returns the state of the scratch space of the decoder, which holds
+// the result of the most recent call to Decode, together with the indices and
+// threshold that identify its setup. The setup itself is not included, since
+// it is much larger and can be recomputed with NewDecoder, so a snapshot is
+// suitable for checkpointing a long running decoding job. Decode runs to
+// completion in a single call, so a snapshot is always of the state between
+// two decodings; a job that decodes many codewords must record its own
+// progress through them alongside the snapshot.
+func (dec *Decoder) Snapshot() ([]byte, error) {
+	return surge.ToBinary(decoderState{
+		n: dec.n, k: dec.k,
+		indices:        dec.indices,
+		eea:            dec.eea,
+		interpPoly:     dec.interpPoly,
+		f1:             dec.f1,
+		r:              dec.r,
+		errors:         dec.errors,
+		errorsComputed: dec.errorsComputed,
+	})
+}
+
+// Restore replaces the scratch space of the decoder with the one in the given
+// snapshot, so that the decoder behaves as the one the snapshot was taken
+// from did, for example in the result of ErrorIndices. ErrIncompatibleSnapshot
+// is returned if the snapshot was taken from a decoder with different indices
+// or threshold, and the decoder is not modified if an error is returned.
+func (dec *Decoder) Restore(snapshot []byte) error {
+	var state decoderState
+	if err := surge.FromBinary(&state, snapshot); err != nil {
+		return err
+	}
+	if state.n != dec.n || state.k != dec.k || len(state.indices) != len(dec.indices) {
+		return ErrIncompatibleSnapshot
+	}
+	for i := range dec.indices {
+		if !state.indices[i].Eq(&dec.indices[i]) {
+			return ErrIncompatibleSnapshot
+		}
+	}
+	dec.eea = state.eea
+	dec.interpPoly = state.interpPoly
+	dec.f1, dec.r = state.f1, state.r
+	dec.errors = state.errors
+	dec.errorsComputed = state.errorsComputed
+	return nil
+}
+
+// The contents of a snapshot. The scratch space is unmarshalled into new
+// memory, so that a restored decoder does not share it with any other.
+type decoderState struct {
+	n, k           int
+	indices        []secp256k1.Fn
+	eea            eea.Stepper
+	interpPoly     poly.Poly
+	f1, r          poly.Poly
+	errors         []secp256k1.Fn
+	errorsComputed bool
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (state decoderState) SizeHint() int {
+	return surge.SizeHintI32 +
+		surge.SizeHintI32 +
+		surge.SizeHint(state.indices) +
+		state.eea.SizeHint() +
+		state.interpPoly.SizeHint() +
+		state.f1.SizeHint() +
+		state.r.SizeHint() +
+		surge.SizeHint(state.errors) +
+		surge.SizeHint(state.errorsComputed)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (state decoderState) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalI32(int32(state.n), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalI32(int32(state.k), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.Marshal(state.indices, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.eea.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.interpPoly.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.f1.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.r.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.Marshal(state.errors, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.Marshal(state.errorsComputed, buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (state *decoderState) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var tmp int32
+	buf, rem, err := surge.UnmarshalI32(&tmp, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	state.n = int(tmp)
+	buf, rem, err = surge.UnmarshalI32(&tmp, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	state.k = int(tmp)
+	buf, rem, err = surge.Unmarshal(&state.indices, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.eea.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.interpPoly.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.f1.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = state.r.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.Unmarshal(&state.errors, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.Unmarshal(&state.errorsComputed, buf, rem)
+}
@@ -0,0 +1,232 @@
+// Package safe exposes the functions of the shamir package that panic on
+// invalid arguments with the panics converted into errors. The shamir package
+// panics when the caller has broken a documented precondition, such as the
+// capacity of a destination or the indices of shares that are added, since
+// that is a programming error and checking for it would slow down every call.
+// A service that handles input from peers that it does not trust may instead
+// want every such failure to be an error that it can report, and can use this
+// package at its boundary while keeping the shamir package for its own
+// computations.
+//
+// The documented preconditions are checked before calling the shamir package,
+// so that they are reported with the errors of the shamir package where they
+// exist: a zero index gives an error wrapping shamir.ErrZeroIndex, and a
+// destination that is too small an error wrapping shamir.ErrCapacity. Any
+// other panic is recovered and returned as an error wrapping ErrPanic.
+package safe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// ErrPanic is wrapped by the errors returned when the shamir package panicked
+// for a reason that was not checked in advance.
+var ErrPanic = errors.New("recovered from panic")
+
+// ErrIndexMismatch is returned when adding shares that have different indices.
+var ErrIndexMismatch = errors.New("shares have different indices")
+
+// ErrEmptyCommitment is returned when evaluating a commitment with no points.
+var ErrEmptyCommitment = errors.New("empty commitment")
+
+// Sets the error to one wrapping ErrPanic if the calling function is
+// panicking. It must be called directly by a deferred statement.
+func catch(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrPanic, r)
+	}
+}
+
+func checkCapacity(what string, capacity, required int) error {
+	if capacity < required {
+		return fmt.Errorf("%w: %v has capacity %v, expected at least %v", shamir.ErrCapacity, what, capacity, required)
+	}
+	return nil
+}
+
+// ShareSecret is the same as shamir.ShareSecret, but returns an error instead
+// of panicking. Zero indices are always rejected, as for the
+// shamir.WithAllowZeroIndexOff option.
+func ShareSecret(dst *shamir.Shares, indices []secp256k1.Fn, secret secp256k1.Fn, k int, opts ...shamir.ShareOption) (err error) {
+	defer catch(&err)
+	if err := checkCapacity("destination shares", cap(*dst), len(indices)); err != nil {
+		return err
+	}
+	opts = append([]shamir.ShareOption{shamir.WithAllowZeroIndexOff()}, opts...)
+	return shamir.ShareSecret(dst, indices, secret, k, opts...)
+}
+
+// ShareAndGetCoeffs is the same as shamir.ShareAndGetCoeffs, but returns an
+// error instead of panicking.
+func ShareAndGetCoeffs(dst *shamir.Shares, coeffs, indices []secp256k1.Fn, secret secp256k1.Fn, k int) (err error) {
+	defer catch(&err)
+	if err := checkCapacity("destination shares", cap(*dst), len(indices)); err != nil {
+		return err
+	}
+	if err := checkCapacity("coefficients", len(coeffs), k); err != nil {
+		return err
+	}
+	if err := checkNonZero(indices); err != nil {
+		return err
+	}
+	return shamir.ShareAndGetCoeffs(dst, coeffs, indices, secret, k)
+}
+
+// VShareSecret is the same as shamir.VShareSecret, but returns an error
+// instead of panicking. Zero indices are always rejected, as for the
+// shamir.WithAllowZeroIndexOff option.
+func VShareSecret(
+	vshares *shamir.VerifiableShares,
+	c *shamir.Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	opts ...shamir.ShareOption,
+) (err error) {
+	defer catch(&err)
+	if err := checkVShareCapacity(vshares, c, indices, k); err != nil {
+		return err
+	}
+	opts = append([]shamir.ShareOption{shamir.WithAllowZeroIndexOff()}, opts...)
+	return shamir.VShareSecret(vshares, c, indices, h, secret, k, opts...)
+}
+
+// VShareSecretAndGetCoeffs is the same as shamir.VShareSecretAndGetCoeffs,
+// but returns an error instead of panicking. Zero indices are always
+// rejected, as for the shamir.WithAllowZeroIndexOff option.
+func VShareSecretAndGetCoeffs(
+	vshares *shamir.VerifiableShares,
+	c *shamir.Commitment,
+	fcoeffs, gcoeffs []secp256k1.Fn,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	opts ...shamir.ShareOption,
+) (err error) {
+	defer catch(&err)
+	if err := checkVShareCapacity(vshares, c, indices, k); err != nil {
+		return err
+	}
+	if err := checkCapacity("coefficients", len(fcoeffs), k); err != nil {
+		return err
+	}
+	if err := checkCapacity("blinding coefficients", len(gcoeffs), k); err != nil {
+		return err
+	}
+	opts = append([]shamir.ShareOption{shamir.WithAllowZeroIndexOff()}, opts...)
+	return shamir.VShareSecretAndGetCoeffs(vshares, c, fcoeffs, gcoeffs, indices, h, secret, k, opts...)
+}
+
+// VShareSecretBatch is the same as shamir.VShareSecretBatch, but returns an
+// error instead of panicking.
+func VShareSecretBatch(indices []secp256k1.Fn, h secp256k1.Point, secrets []secp256k1.Fn, k int) (dealings []shamir.Dealing, err error) {
+	defer catch(&err)
+	if err := checkNonZero(indices); err != nil {
+		return nil, err
+	}
+	return shamir.VShareSecretBatch(indices, h, secrets, k)
+}
+
+// AddShares sets dst to the sum of the two shares, as for shamir.Share.Add,
+// but returns ErrIndexMismatch instead of panicking if they have different
+// indices.
+func AddShares(dst, a, b *shamir.Share) error {
+	if !a.Index.Eq(&b.Index) {
+		return ErrIndexMismatch
+	}
+	dst.Add(a, b)
+	return nil
+}
+
+// AddVShares sets dst to the sum of the two verifiable shares, as for
+// shamir.VerifiableShare.Add, but returns ErrIndexMismatch instead of
+// panicking if they have different indices.
+func AddVShares(dst, a, b *shamir.VerifiableShare) error {
+	if !a.Share.Index.Eq(&b.Share.Index) {
+		return ErrIndexMismatch
+	}
+	dst.Add(a, b)
+	return nil
+}
+
+// AddCommitments sets dst to the sum of the two commitments, as for
+// shamir.Commitment.Add, but returns an error instead of panicking.
+func AddCommitments(dst *shamir.Commitment, a, b shamir.Commitment) (err error) {
+	defer catch(&err)
+	required := len(a)
+	if len(b) > required {
+		required = len(b)
+	}
+	if err := checkCapacity("destination commitment", cap(*dst), required); err != nil {
+		return err
+	}
+	dst.Add(a, b)
+	return nil
+}
+
+// ScaleCommitment sets dst to the commitment scaled by the given scalar, as
+// for shamir.Commitment.Scale, but returns an error instead of panicking.
+func ScaleCommitment(dst *shamir.Commitment, c shamir.Commitment, scale *secp256k1.Fn) (err error) {
+	defer catch(&err)
+	if err := checkCapacity("destination commitment", cap(*dst), len(c)); err != nil {
+		return err
+	}
+	dst.Scale(c, scale)
+	return nil
+}
+
+// TruncateCommitment is the same as shamir.Commitment.Truncate, but returns
+// an error instead of panicking if k is negative or greater than the length
+// of the commitment.
+func TruncateCommitment(c *shamir.Commitment, k int) (ok bool, err error) {
+	defer catch(&err)
+	if k < 0 || k > c.Len() {
+		return false, fmt.Errorf("%w: cannot truncate commitment of length %v to length %v", shamir.ErrInvalidThreshold, c.Len(), k)
+	}
+	return c.Truncate(k), nil
+}
+
+// Evaluate is the same as shamir.Commitment.Evaluate, but returns
+// ErrEmptyCommitment instead of panicking if the commitment is empty.
+func Evaluate(c shamir.Commitment, index *secp256k1.Fn) (eval secp256k1.Point, err error) {
+	defer catch(&err)
+	if c.Len() == 0 {
+		return secp256k1.Point{}, ErrEmptyCommitment
+	}
+	return c.Evaluate(index), nil
+}
+
+// IsValid is the same as shamir.IsValid, but returns ErrEmptyCommitment
+// instead of panicking if the commitment is empty.
+func IsValid(h secp256k1.Point, c *shamir.Commitment, vshare *shamir.VerifiableShare) (valid bool, err error) {
+	defer catch(&err)
+	if c.Len() == 0 {
+		return false, ErrEmptyCommitment
+	}
+	return shamir.IsValid(h, c, vshare), nil
+}
+
+func checkVShareCapacity(vshares *shamir.VerifiableShares, c *shamir.Commitment, indices []secp256k1.Fn, k int) error {
+	if err := checkCapacity("destination shares", cap(*vshares), len(indices)); err != nil {
+		return err
+	}
+	if k > 0 && k <= len(indices) {
+		return checkCapacity("destination commitment", cap(*c), k)
+	}
+	return nil
+}
+
+func checkNonZero(indices []secp256k1.Fn) error {
+	for i := range indices {
+		if indices[i].IsZero() {
+			return fmt.Errorf("%w: index %v", shamir.ErrZeroIndex, i)
+		}
+	}
+	return nil
+}
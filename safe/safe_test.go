@@ -0,0 +1,99 @@
+package safe_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/safe"
+)
+
+var _ = Describe("Safe wrappers", func() {
+	n, k := 10, 4
+	h := shamir.PedersenH()
+
+	It("should behave as the shamir package for valid arguments", func() {
+		indices := shamirutil.RandomIndices(n)
+		secret := secp256k1.RandomFn()
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+		for i := range vshares {
+			valid, err := IsValid(h, &c, &vshares[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		}
+		opened := shamir.Open(vshares.Shares()[:k])
+		Expect(opened.Eq(&secret)).To(BeTrue())
+
+		var sum shamir.VerifiableShare
+		Expect(AddVShares(&sum, &vshares[0], &vshares[0])).To(Succeed())
+		sumC := shamir.NewCommitmentWithCapacity(k)
+		Expect(AddCommitments(&sumC, c, c)).To(Succeed())
+		valid, err := IsValid(h, &sumC, &sum)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	It("should return errors for zero indices", func() {
+		indices := shamirutil.RandomIndices(n)
+		indices[3].Clear()
+		shares := make(shamir.Shares, n)
+		err := ShareSecret(&shares, indices, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrZeroIndex)).To(BeTrue())
+		coeffs := make([]secp256k1.Fn, k)
+		err = ShareAndGetCoeffs(&shares, coeffs, indices, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrZeroIndex)).To(BeTrue())
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		err = VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrZeroIndex)).To(BeTrue())
+		_, err = VShareSecretBatch(indices, h, []secp256k1.Fn{secp256k1.RandomFn()}, k)
+		Expect(errors.Is(err, shamir.ErrZeroIndex)).To(BeTrue())
+	})
+
+	It("should return errors for destinations that are too small", func() {
+		indices := shamirutil.RandomIndices(n)
+		shares := make(shamir.Shares, n-1)
+		err := ShareSecret(&shares, indices, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrCapacity)).To(BeTrue())
+
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k - 1)
+		err = VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrCapacity)).To(BeTrue())
+
+		fcoeffs, gcoeffs := make([]secp256k1.Fn, k), make([]secp256k1.Fn, k-1)
+		c = shamir.NewCommitmentWithCapacity(k)
+		err = VShareSecretAndGetCoeffs(&vshares, &c, fcoeffs, gcoeffs, indices, h, secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, shamir.ErrCapacity)).To(BeTrue())
+
+		Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+		small := shamir.NewCommitmentWithCapacity(k - 1)
+		Expect(errors.Is(AddCommitments(&small, c, c), shamir.ErrCapacity)).To(BeTrue())
+		Expect(errors.Is(ScaleCommitment(&small, c, &fcoeffs[0]), shamir.ErrCapacity)).To(BeTrue())
+	})
+
+	It("should return errors for mismatched indices and empty commitments", func() {
+		a := shamir.NewShare(secp256k1.NewFnFromU16(1), secp256k1.RandomFn())
+		b := shamir.NewShare(secp256k1.NewFnFromU16(2), secp256k1.RandomFn())
+		var sum shamir.Share
+		Expect(AddShares(&sum, &a, &b)).To(Equal(ErrIndexMismatch))
+
+		var c shamir.Commitment
+		index := secp256k1.NewFnFromU16(1)
+		_, err := Evaluate(c, &index)
+		Expect(err).To(Equal(ErrEmptyCommitment))
+		_, err = TruncateCommitment(&c, 1)
+		Expect(errors.Is(err, shamir.ErrInvalidThreshold)).To(BeTrue())
+	})
+
+	It("should recover from other panics", func() {
+		err := ShareSecret(nil, shamirutil.RandomIndices(n), secp256k1.RandomFn(), k)
+		Expect(errors.Is(err, ErrPanic)).To(BeTrue())
+	})
+})
@@ -0,0 +1,160 @@
+package shamir
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/bn254"
+	"github.com/renproject/shamir/p256"
+	"github.com/renproject/shamir/ristretto255"
+	"github.com/renproject/surge"
+)
+
+// CommitmentPointSize is the number of bytes in the encoding of each point of
+// a Commitment.
+const CommitmentPointSize = secp256k1.PointSizeMarshalled
+
+// The order of the secp256k1 group.
+var secp256k1Order, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// CurveName returns the name of the group that this package uses,
+// "secp256k1".
+func CurveName() string {
+	return "secp256k1"
+}
+
+// FieldOrder returns the order of the scalar field of this package, which is
+// the order of the secp256k1 group. The caller may modify the returned
+// integer.
+func FieldOrder() *big.Int {
+	return new(big.Int).Set(secp256k1Order)
+}
+
+// A SchemeDescriptor describes the group and encodings of a sharing scheme,
+// so that two peers can check during a handshake that they use compatible
+// implementations before exchanging shares. The field order is encoded in 32
+// bytes big endian.
+type SchemeDescriptor struct {
+	Curve               CurveID
+	CurveName           string
+	FieldOrder          [32]byte
+	ScalarSize          uint32
+	ShareSize           uint32
+	CommitmentPointSize uint32
+}
+
+// DescribeScheme returns the descriptor of the sharing scheme of this package.
+func DescribeScheme() SchemeDescriptor {
+	d, _ := DescribeCurve(CurveSecp256k1)
+	return d
+}
+
+//go:generate go run gen_backends.go
+
+// DescribeCurve returns the descriptor of the sharing scheme over the given
+// group, which is implemented by this package for secp256k1 and by the p256,
+// bn254 and ristretto255 packages for the other groups. An error is returned
+// if the group is not known.
+func DescribeCurve(curve CurveID) (SchemeDescriptor, error) {
+	d := SchemeDescriptor{Curve: curve}
+	var order *big.Int
+	switch curve {
+	case CurveSecp256k1:
+		d.CurveName, order = CurveName(), FieldOrder()
+		d.ScalarSize, d.ShareSize = uint32(secp256k1.FnSizeMarshalled), uint32(ShareSize)
+		d.CommitmentPointSize = uint32(CommitmentPointSize)
+	case CurveP256:
+		d.CurveName, order = p256.CurveName(), p256.FieldOrder()
+		d.ScalarSize, d.ShareSize = p256.ScalarSize, p256.ShareSize
+		d.CommitmentPointSize = p256.CommitmentPointSize
+	case CurveBN254:
+		d.CurveName, order = bn254.CurveName(), bn254.FieldOrder()
+		d.ScalarSize, d.ShareSize = bn254.ScalarSize, bn254.ShareSize
+		d.CommitmentPointSize = bn254.CommitmentPointSize
+	case CurveRistretto255:
+		d.CurveName, order = ristretto255.CurveName(), ristretto255.FieldOrder()
+		d.ScalarSize, d.ShareSize = ristretto255.ScalarSize, ristretto255.ShareSize
+		d.CommitmentPointSize = ristretto255.CommitmentPointSize
+	default:
+		return SchemeDescriptor{}, fmt.Errorf("unsupported curve %v", curve)
+	}
+	bs := order.Bytes()
+	copy(d.FieldOrder[len(d.FieldOrder)-len(bs):], bs)
+	return d, nil
+}
+
+// CheckCompatible returns an error if the descriptor of a peer is not the
+// same as this one, naming the first field that differs.
+func (d SchemeDescriptor) CheckCompatible(peer SchemeDescriptor) error {
+	switch {
+	case d.Curve != peer.Curve:
+		return fmt.Errorf("incompatible curve: expected %v, got %v", d.Curve, peer.Curve)
+	case d.CurveName != peer.CurveName:
+		return fmt.Errorf("incompatible curve name: expected %q, got %q", d.CurveName, peer.CurveName)
+	case d.FieldOrder != peer.FieldOrder:
+		return fmt.Errorf("incompatible field order: expected %x, got %x", d.FieldOrder, peer.FieldOrder)
+	case d.ScalarSize != peer.ScalarSize:
+		return fmt.Errorf("incompatible scalar size: expected %v, got %v", d.ScalarSize, peer.ScalarSize)
+	case d.ShareSize != peer.ShareSize:
+		return fmt.Errorf("incompatible share size: expected %v, got %v", d.ShareSize, peer.ShareSize)
+	case d.CommitmentPointSize != peer.CommitmentPointSize:
+		return fmt.Errorf("incompatible commitment point size: expected %v, got %v", d.CommitmentPointSize, peer.CommitmentPointSize)
+	}
+	return nil
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (d SchemeDescriptor) SizeHint() int {
+	return surge.SizeHintU8 + surge.SizeHintString(d.CurveName) + len(d.FieldOrder) + 3*surge.SizeHintU32
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (d SchemeDescriptor) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := surge.MarshalU8(uint8(d.Curve), buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = surge.MarshalString(d.CurveName, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if len(buf) < len(d.FieldOrder) || rem < len(d.FieldOrder) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(buf, d.FieldOrder[:])
+	buf, rem = buf[len(d.FieldOrder):], rem-len(d.FieldOrder)
+	for _, x := range [...]uint32{d.ScalarSize, d.ShareSize, d.CommitmentPointSize} {
+		buf, rem, err = surge.MarshalU32(x, buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (d *SchemeDescriptor) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	var curve uint8
+	buf, rem, err := surge.UnmarshalU8(&curve, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	d.Curve = CurveID(curve)
+	buf, rem, err = surge.UnmarshalString(&d.CurveName, buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	if len(buf) < len(d.FieldOrder) || rem < len(d.FieldOrder) {
+		return buf, rem, surge.ErrUnexpectedEndOfBuffer
+	}
+	copy(d.FieldOrder[:], buf)
+	buf, rem = buf[len(d.FieldOrder):], rem-len(d.FieldOrder)
+	for _, x := range [...]*uint32{&d.ScalarSize, &d.ShareSize, &d.CommitmentPointSize} {
+		buf, rem, err = surge.UnmarshalU32(x, buf, rem)
+		if err != nil {
+			return buf, rem, err
+		}
+	}
+	return buf, rem, nil
+}
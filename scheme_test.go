@@ -0,0 +1,80 @@
+package shamir_test
+
+import (
+	"math/big"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+)
+
+var _ = Describe("Scheme descriptors", func() {
+	curves := []CurveID{CurveSecp256k1, CurveP256, CurveBN254, CurveRistretto255}
+
+	It("should describe the order of the scalar field of this package", func() {
+		// The order minus one is the negation of one.
+		m := FieldOrder()
+		m.Sub(m, big.NewInt(1))
+		var bs [32]byte
+		copy(bs[32-len(m.Bytes()):], m.Bytes())
+		var x secp256k1.Fn
+		Expect(x.SetB32(bs[:])).To(BeFalse())
+		one := secp256k1.NewFnFromU16(1)
+		x.Add(&x, &one)
+		Expect(x.IsZero()).To(BeTrue())
+
+		d := DescribeScheme()
+		Expect(d.Curve).To(Equal(CurveSecp256k1))
+		Expect(d.CurveName).To(Equal(CurveName()))
+		Expect(new(big.Int).SetBytes(d.FieldOrder[:])).To(Equal(FieldOrder()))
+		Expect(d.ShareSize).To(BeEquivalentTo(ShareSize))
+		Expect(d.CommitmentPointSize).To(BeEquivalentTo(CommitmentPointSize))
+	})
+
+	It("should describe every known group differently", func() {
+		for i := range curves {
+			d, err := DescribeCurve(curves[i])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.CheckCompatible(d)).To(Succeed())
+			for j := 0; j < i; j++ {
+				other, err := DescribeCurve(curves[j])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(d.CheckCompatible(other)).ToNot(Succeed())
+			}
+		}
+		_, err := DescribeCurve(0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should name the field that differs", func() {
+		d := DescribeScheme()
+		peer := d
+		peer.FieldOrder[31]++
+		Expect(d.CheckCompatible(peer)).To(MatchError(ContainSubstring("field order")))
+		peer = d
+		peer.ShareSize++
+		Expect(d.CheckCompatible(peer)).To(MatchError(ContainSubstring("share size")))
+	})
+
+	It("should be the same after marshalling and unmarshalling", func() {
+		for _, curve := range curves {
+			d, err := DescribeCurve(curve)
+			Expect(err).ToNot(HaveOccurred())
+			buf, err := surge.ToBinary(d)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf).To(HaveLen(d.SizeHint()))
+
+			var decoded SchemeDescriptor
+			Expect(surge.FromBinary(&decoded, buf)).To(Succeed())
+			Expect(decoded).To(Equal(d))
+
+			for i := 0; i < len(buf); i++ {
+				_, _, err = decoded.Unmarshal(buf[:i], len(buf))
+				Expect(err).To(HaveOccurred())
+			}
+		}
+	})
+})
@@ -0,0 +1,343 @@
+package shamir
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/fnbatch"
+	"github.com/renproject/shamir/internal/sharing"
+)
+
+// A Scratch holds the temporary values used by the functions that accept one,
+// so that repeated calls with the same Scratch do not allocate. Values of the
+// secp256k1 types escape to the heap when they are passed to the underlying C
+// library, so even temporaries that look like they live on the stack would
+// otherwise be allocated on every call.
+//
+// The zero value is ready to use. A Scratch must not be used by more than one
+// goroutine at a time. Open, ShareSecret and VShareSecret take a Scratch from
+// an internal pool, so they also avoid most allocations. Every function that
+// uses a Scratch zeroes any secret values left in it before returning.
+type Scratch struct {
+	num, denom, acc, tmp secp256k1.Fn
+	radix                secp256k1.Fn
+	h, hPow              secp256k1.Point
+	coeffs, decomCoeffs  []secp256k1.Fn
+	randBytes            []byte
+	// The indices and values of the shares given to Open, and the
+	// numerators, denominators, index differences and prefix products of the
+	// Lagrange coefficients that it computes.
+	xs, ys                      []secp256k1.Fn
+	nums, denoms, diffs, prefix []secp256k1.Fn
+}
+
+// Grows the coefficient buffers of the scratch to hold at least k elements.
+func (s *Scratch) reserve(k int) {
+	if cap(s.coeffs) < k {
+		WipeFns(s.coeffs)
+		WipeFns(s.decomCoeffs)
+		s.coeffs = make([]secp256k1.Fn, k)
+		s.decomCoeffs = make([]secp256k1.Fn, k)
+		s.randBytes = make([]byte, 32*k)
+	}
+}
+
+// Grows the Lagrange coefficient buffers of the scratch to hold at least n
+// elements. Apart from the values of the shares, these only depend on the
+// indices, so they are not wiped.
+func (s *Scratch) reserveLagrange(n int) {
+	if cap(s.nums) < n {
+		WipeFns(s.ys)
+		s.xs = make([]secp256k1.Fn, n)
+		s.ys = make([]secp256k1.Fn, n)
+		s.nums = make([]secp256k1.Fn, n)
+		s.diffs = make([]secp256k1.Fn, n)
+		s.denoms = make([]secp256k1.Fn, n)
+		s.prefix = make([]secp256k1.Fn, n)
+	}
+}
+
+// Sets each of the given field elements to a random value using bytes from
+// the given source. Like secp256k1.RandomFn, 32 random bytes are reduced
+// modulo the order, but the value is built up 16 bits at a time because
+// Fn.SetB32 allocates. If check is not nil, it is given the random bytes
+// before they are used, and an error that it returns is returned.
+func (s *Scratch) randomFns(r io.Reader, xs []secp256k1.Fn, check func([]byte) error) error {
+	bs := s.randBytes[:32*len(xs)]
+	defer func() {
+		for i := range bs {
+			bs[i] = 0
+		}
+	}()
+	if err := sharing.ReadRandom(r, bs); err != nil {
+		return err
+	}
+	if check != nil {
+		if err := check(bs); err != nil {
+			return fmt.Errorf("%w: entropy check failed: %v", ErrWeakRandomness, err)
+		}
+	}
+	s.radix.SetU16(1 << 8)
+	s.radix.Mul(&s.radix, &s.radix)
+	for i := range xs {
+		xs[i].SetU16(0)
+		for j := 32 * i; j < 32*(i+1); j += 2 {
+			s.tmp.SetU16(uint16(bs[j])<<8 | uint16(bs[j+1]))
+			xs[i].Mul(&xs[i], &s.radix)
+			xs[i].Add(&xs[i], &s.tmp)
+		}
+	}
+	s.tmp.Clear()
+	return nil
+}
+
+// Sets each of the given field elements to a random value from the source
+// given by the options, with the checks that the options enable. If the
+// coefficient check fails, the elements are sampled again, up to
+// maxResamples times.
+func (s *Scratch) sampleFns(opts *ShareOptions, xs []secp256k1.Fn) error {
+	r := opts.random()
+	for attempt := 0; ; attempt++ {
+		if err := s.randomFns(r, xs, opts.EntropyCheck); err != nil {
+			return err
+		}
+		if !opts.CheckCoefficients || distinctNonZero(xs) {
+			return nil
+		}
+		if attempt == maxResamples {
+			return fmt.Errorf("%w: sampled a zero or repeated coefficient %v times", ErrWeakRandomness, attempt+1)
+		}
+	}
+}
+
+func (s *Scratch) wipe() {
+	Wipe(&s.num, &s.denom, &s.acc, &s.tmp)
+	WipeFns(s.coeffs)
+	WipeFns(s.decomCoeffs)
+}
+
+var scratchPool = sync.Pool{
+	New: func() interface{} { return new(Scratch) },
+}
+
+// OpenWithScratch is the same as Open, but uses the given scratch space for
+// its temporary values. Once the scratch space has grown to hold the Lagrange
+// coefficients of n shares, it does not allocate.
+func OpenWithScratch(shares Shares, s *Scratch) secp256k1.Fn {
+	return open(s, len(shares), func(i int) *Share { return &shares[i] })
+}
+
+// OpenVSharesWithScratch is the same as Open for the shares of the given
+// verifiable shares, but does not need to copy them with
+// VerifiableShares.Shares, and uses the given scratch space for its temporary
+// values. As for OpenWithScratch, it does not allocate once the scratch space
+// has grown.
+func OpenVSharesWithScratch(vshares VerifiableShares, s *Scratch) secp256k1.Fn {
+	return open(s, len(vshares), func(i int) *Share { return &vshares[i].Share })
+}
+
+// OpenVerifiable computes the secret corresponding to the given verifiable
+// shares as for Open, and also the decommitment at zero, which is the
+// constant term of the decommitment polynomial. Together they are the opening
+// of the first point of the commitment, secret*G + decommitment*h, as needed
+// to later prove knowledge of it. The same conditions as for Open must hold
+// for the results to be correct.
+func OpenVerifiable(vshares VerifiableShares) (secret, decommitment secp256k1.Fn) {
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	defer s.wipe()
+	n := len(vshares)
+	s.reserveLagrange(n)
+	xs, ys := s.xs[:n], s.ys[:n]
+	for i := range vshares {
+		xs[i], ys[i] = vshares[i].Share.Index, vshares[i].Share.Value
+	}
+	coeffs := s.lagrangeCoeffs(n)
+	secret = fnbatch.InnerProduct(coeffs, ys)
+	for i := range vshares {
+		ys[i] = vshares[i].Decommitment
+	}
+	decommitment = fnbatch.InnerProduct(coeffs, ys)
+	WipeFns(ys)
+	return secret, decommitment
+}
+
+func open(s *Scratch, n int, share func(int) *Share) secp256k1.Fn {
+	defer s.wipe()
+	s.reserveLagrange(n)
+	xs, ys := s.xs[:n], s.ys[:n]
+	for i := 0; i < n; i++ {
+		si := share(i)
+		xs[i], ys[i] = si.Index, si.Value
+	}
+	coeffs := s.lagrangeCoeffs(n)
+	s.acc = fnbatch.InnerProduct(coeffs, ys)
+	WipeFns(ys)
+	return s.acc
+}
+
+// Computes the Lagrange coefficients for interpolation at zero of the first n
+// indices in s.xs, which must have been reserved with reserveLagrange. The
+// returned slice is s.nums[:n].
+func (s *Scratch) lagrangeCoeffs(n int) []secp256k1.Fn {
+	xs := s.xs[:n]
+	nums, denoms, diffs := s.nums[:n], s.denoms[:n], s.diffs[:n]
+
+	// The numerator for share i is the product of every index except its
+	// own, which is the product of the prefix before it and the suffix after
+	// it.
+	s.acc.SetU16(1)
+	for i := 0; i < n; i++ {
+		nums[i] = s.acc
+		s.acc.Mul(&s.acc, &xs[i])
+	}
+	s.acc.SetU16(1)
+	for i := n - 1; i >= 0; i-- {
+		nums[i].Mul(&nums[i], &s.acc)
+		s.acc.Mul(&s.acc, &xs[i])
+	}
+
+	// The denominator for share i is the product of the differences between
+	// every other index and its own.
+	s.tmp.SetU16(1)
+	for i := 0; i < n; i++ {
+		fnbatch.SubScalar(diffs, xs, &xs[i])
+		diffs[i] = s.tmp
+		denoms[i] = fnbatch.Product(diffs)
+	}
+	batchInvertWith(denoms, s.prefix[:n], &s.num, &s.denom)
+
+	fnbatch.Mul(nums, nums, denoms)
+	return nums
+}
+
+// ShareSecretWithScratch is the same as ShareSecret, but uses the given
+// scratch space for its temporary values. Once the scratch space has grown to
+// hold k coefficients, it does not allocate unless it returns an error.
+//
+// Panics: This function will panic under the same conditions as ShareSecret.
+func ShareSecretWithScratch(dst *Shares, indices []secp256k1.Fn, secret secp256k1.Fn, k int, s *Scratch) error {
+	return shareSecret(dst, indices, secret, k, s, &ShareOptions{})
+}
+
+func shareSecret(dst *Shares, indices []secp256k1.Fn, secret secp256k1.Fn, k int, s *Scratch, options *ShareOptions) error {
+	if err := checkIndices(indices, k); err != nil {
+		return err
+	}
+	s.reserve(k)
+	defer s.wipe()
+
+	coeffs := s.coeffs[:k]
+	coeffs[0] = secret
+	if err := s.sampleFns(options, coeffs[1:]); err != nil {
+		return err
+	}
+
+	*dst = (*dst)[:len(indices)]
+	for i := range indices {
+		(*dst)[i].Index = indices[i]
+		polyEval(&(*dst)[i].Value, &indices[i], coeffs)
+	}
+	return nil
+}
+
+// VShareSecretWithScratch is the same as VShareSecret, but uses the given
+// scratch space for its temporary values. Once the scratch space has grown to
+// hold k coefficients, the only allocations are the k made internally by
+// secp256k1.Point.Scale when computing the commitment, unless it returns an
+// error.
+//
+// Panics: This function will panic under the same conditions as VShareSecret.
+func VShareSecretWithScratch(
+	vshares *VerifiableShares,
+	c *Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	s *Scratch,
+) error {
+	return vshareSecret(vshares, c, indices, h, secret, k, s, &ShareOptions{})
+}
+
+func vshareSecret(
+	vshares *VerifiableShares,
+	c *Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	s *Scratch,
+	options *ShareOptions,
+) error {
+	if err := checkIndices(indices, k); err != nil {
+		return err
+	}
+	s.reserve(k)
+	defer s.wipe()
+	return vshareSecretWithCoeffs(vshares, c, indices, h, secret, s.coeffs[:k], s.decomCoeffs[:k], s, options)
+}
+
+// Creates the verifiable sharing using the given slices, whose length is the
+// threshold, for the coefficients of the sharing and decommitment
+// polynomials, with the source of randomness and the optional destination for
+// the blinding commitment given by the options. The indices and threshold must
+// already have been checked. An error is only returned if a check of the
+// random values that is enabled by the options fails, in which case nothing
+// is written to the destinations.
+func vshareSecretWithCoeffs(
+	vshares *VerifiableShares,
+	c *Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	coeffs, decomCoeffs []secp256k1.Fn,
+	s *Scratch,
+	options *ShareOptions,
+) error {
+	k := len(coeffs)
+	coeffs[0] = secret
+	if err := s.sampleFns(options, coeffs[1:]); err != nil {
+		return err
+	}
+	if err := s.sampleFns(options, decomCoeffs); err != nil {
+		return err
+	}
+
+	// Copying h into the scratch space stops the parameter from escaping.
+	s.h = h
+	*c = (*c)[:k]
+	blind := options.BlindingCommitment
+	if blind != nil {
+		*blind = (*blind)[:k]
+	}
+	for i := range coeffs {
+		(*c)[i].BaseExp(&coeffs[i])
+		s.hPow.Scale(&s.h, &decomCoeffs[i])
+		(*c)[i].Add(&(*c)[i], &s.hPow)
+		if blind != nil {
+			(*blind)[i] = s.hPow
+		}
+	}
+
+	*vshares = (*vshares)[:len(indices)]
+	for i := range indices {
+		(*vshares)[i].Share.Index = indices[i]
+		polyEval(&(*vshares)[i].Share.Value, &indices[i], coeffs)
+		polyEval(&(*vshares)[i].Decommitment, &indices[i], decomCoeffs)
+	}
+	return nil
+}
+
+// Checks the preconditions on the indices and threshold that are shared by
+// the sharing functions.
+func checkIndices(indices []secp256k1.Fn, k int) error {
+	for i := range indices {
+		if indices[i].IsZero() {
+			panic("cannot create share for index zero")
+		}
+	}
+	return sharing.CheckThreshold(k, len(indices))
+}
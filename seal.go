@@ -0,0 +1,157 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/renproject/surge"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// A sealed share is a verifiable share encrypted under a key derived from a
+// passphrase, for storing shares at rest. The sealed encoding is
+//
+//	version || time || memory || threads || salt || nonce || ciphertext
+//
+// where version is SealV1 as a single byte, time and memory are the Argon2id
+// time and memory (in KiB) parameters as 4 byte big endian integers, threads
+// is the Argon2id parallelism as a single byte, salt is 16 random bytes and
+// nonce is 24 random bytes. The ciphertext is the XChaCha20-Poly1305
+// encryption of the surge encoding of the share, under the 32 byte Argon2id
+// key for the passphrase and salt, with the header before it as the
+// associated data, so the parameters can not be changed without detection.
+
+// SealV1 is the first version of the sealed share format.
+const SealV1 = 1
+
+// The lengths of the parts of a sealed share.
+const (
+	sealSaltSize   = 16
+	sealHeaderSize = 1 + 4 + 4 + 1 + sealSaltSize + chacha20poly1305.NonceSizeX
+	sealKeySize    = chacha20poly1305.KeySize
+)
+
+// SealedShareSize is the size in bytes of a sealed share.
+const SealedShareSize = sealHeaderSize + VShareSize + chacha20poly1305.Overhead
+
+// SealParams are the Argon2id parameters used to derive the key for sealing a
+// share from a passphrase.
+type SealParams struct {
+	Time      uint32
+	MemoryKiB uint32
+	Threads   uint8
+}
+
+// DefaultSealParams are the parameters used by SealShare. They are the second
+// recommended option of RFC 9106, which uses 64 MiB of memory.
+var DefaultSealParams = SealParams{Time: 3, MemoryKiB: 64 * 1024, Threads: 4}
+
+// The largest parameters that OpenSealedShare will accept, so that a tampered
+// header can not make it use an unbounded amount of time or memory.
+const (
+	maxSealTime      = 64
+	maxSealMemoryKiB = 4 * 1024 * 1024
+)
+
+// ErrSealedShareAuth is returned when a sealed share can not be opened,
+// because either the passphrase is wrong or the sealed share has been
+// modified.
+var ErrSealedShareAuth = errors.New("sealed share authentication failed")
+
+func (params SealParams) validate() error {
+	if params.Time == 0 || params.Time > maxSealTime {
+		return fmt.Errorf("invalid argon2id time parameter %v", params.Time)
+	}
+	if params.MemoryKiB < 8*uint32(params.Threads) || params.MemoryKiB > maxSealMemoryKiB {
+		return fmt.Errorf("invalid argon2id memory parameter %v KiB", params.MemoryKiB)
+	}
+	if params.Threads == 0 {
+		return fmt.Errorf("invalid argon2id threads parameter %v", params.Threads)
+	}
+	return nil
+}
+
+// SealShare encrypts the share under the given passphrase with the default
+// parameters, as for SealShareWithParams.
+func SealShare(vs VerifiableShare, passphrase []byte) ([]byte, error) {
+	return SealShareWithParams(vs, passphrase, DefaultSealParams)
+}
+
+// SealShareWithParams encrypts the share under a key that is derived from the
+// passphrase using Argon2id with the given parameters, and returns the sealed
+// share in the format described above. The salt and nonce are read from
+// RandomSource. The plaintext and key are zeroed before returning.
+func SealShareWithParams(vs VerifiableShare, passphrase []byte, params SealParams) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	sealed := make([]byte, sealHeaderSize, SealedShareSize)
+	sealed[0] = SealV1
+	binary.BigEndian.PutUint32(sealed[1:], params.Time)
+	binary.BigEndian.PutUint32(sealed[5:], params.MemoryKiB)
+	sealed[9] = params.Threads
+	if _, err := io.ReadFull(RandomSource(), sealed[10:sealHeaderSize]); err != nil {
+		return nil, fmt.Errorf("could not generate salt and nonce: %w", err)
+	}
+	salt := sealed[10 : 10+sealSaltSize]
+	nonce := sealed[10+sealSaltSize : sealHeaderSize]
+
+	plaintext := make([]byte, VShareSize)
+	defer wipeBytes(plaintext)
+	if _, _, err := vs.Marshal(plaintext, surge.MaxBytes); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(passphrase, salt, params.Time, params.MemoryKiB, params.Threads, sealKeySize)
+	defer wipeBytes(key)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(sealed, nonce, plaintext, sealed[:sealHeaderSize]), nil
+}
+
+// OpenSealedShare decrypts a share that was sealed with SealShare or
+// SealShareWithParams. ErrSealedShareAuth is returned if the passphrase is
+// wrong or the sealed share has been modified, and other errors are returned
+// if the sealed share is malformed, has an unsupported version, or has
+// Argon2id parameters that are out of range.
+func OpenSealedShare(sealed, passphrase []byte) (VerifiableShare, error) {
+	if len(sealed) != SealedShareSize {
+		return VerifiableShare{}, fmt.Errorf("expected %v bytes, got %v bytes", SealedShareSize, len(sealed))
+	}
+	if sealed[0] != SealV1 {
+		return VerifiableShare{}, fmt.Errorf("unsupported sealed share version %v", sealed[0])
+	}
+	params := SealParams{
+		Time:      binary.BigEndian.Uint32(sealed[1:]),
+		MemoryKiB: binary.BigEndian.Uint32(sealed[5:]),
+		Threads:   sealed[9],
+	}
+	if err := params.validate(); err != nil {
+		return VerifiableShare{}, err
+	}
+	salt := sealed[10 : 10+sealSaltSize]
+	nonce := sealed[10+sealSaltSize : sealHeaderSize]
+
+	key := argon2.IDKey(passphrase, salt, params.Time, params.MemoryKiB, params.Threads, sealKeySize)
+	defer wipeBytes(key)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return VerifiableShare{}, err
+	}
+	plaintext, err := aead.Open(nil, nonce, sealed[sealHeaderSize:], sealed[:sealHeaderSize])
+	if err != nil {
+		return VerifiableShare{}, ErrSealedShareAuth
+	}
+	defer wipeBytes(plaintext)
+
+	var vs VerifiableShare
+	if err := DecodeLegacy(plaintext, &vs); err != nil {
+		return VerifiableShare{}, err
+	}
+	return vs, nil
+}
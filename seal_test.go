@@ -0,0 +1,117 @@
+package shamir_test
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+)
+
+var _ = Describe("Sealed shares", func() {
+	// Cheap parameters, so that the tests run quickly.
+	params := SealParams{Time: 1, MemoryKiB: 64, Threads: 1}
+	passphrase := []byte("correct horse battery staple")
+
+	randomVShare := func() VerifiableShare {
+		return NewVerifiableShare(
+			NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+			secp256k1.RandomFn(),
+		)
+	}
+
+	It("should open what was sealed", func() {
+		vshare := randomVShare()
+		sealed, err := SealShareWithParams(vshare, passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(sealed)).To(Equal(SealedShareSize))
+		Expect(sealed[0]).To(Equal(byte(SealV1)))
+
+		opened, err := OpenSealedShare(sealed, passphrase)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opened.Eq(&vshare)).To(BeTrue())
+	})
+
+	It("should open what was sealed with the default parameters", func() {
+		vshare := randomVShare()
+		sealed, err := SealShare(vshare, passphrase)
+		Expect(err).ToNot(HaveOccurred())
+		opened, err := OpenSealedShare(sealed, passphrase)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(opened.Eq(&vshare)).To(BeTrue())
+	})
+
+	It("should use a fresh salt and nonce each time", func() {
+		vshare := randomVShare()
+		a, err := SealShareWithParams(vshare, passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+		b, err := SealShareWithParams(vshare, passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(a).ToNot(Equal(b))
+	})
+
+	It("should fail to open with the wrong passphrase", func() {
+		sealed, err := SealShareWithParams(randomVShare(), passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = OpenSealedShare(sealed, []byte("Tr0ub4dor&3"))
+		Expect(err).To(Equal(ErrSealedShareAuth))
+	})
+
+	It("should detect any modification", func() {
+		sealed, err := SealShareWithParams(randomVShare(), passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+		// The version and parameter bytes are checked before decryption, so
+		// only flip bits that leave them in range.
+		for i := 10; i < len(sealed); i++ {
+			modified := append([]byte{}, sealed...)
+			modified[i] ^= 0x01
+			_, err = OpenSealedShare(modified, passphrase)
+			Expect(err).To(Equal(ErrSealedShareAuth))
+		}
+		modified := append([]byte{}, sealed...)
+		modified[4] ^= 0x02
+		_, err = OpenSealedShare(modified, passphrase)
+		Expect(err).To(Equal(ErrSealedShareAuth))
+	})
+
+	It("should reject malformed sealed shares", func() {
+		sealed, err := SealShareWithParams(randomVShare(), passphrase, params)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = OpenSealedShare(sealed[:len(sealed)-1], passphrase)
+		Expect(err).To(HaveOccurred())
+
+		modified := append([]byte{}, sealed...)
+		modified[0] = SealV1 + 1
+		_, err = OpenSealedShare(modified, passphrase)
+		Expect(err).To(HaveOccurred())
+
+		// Memory parameter far beyond the limit.
+		modified = append([]byte{}, sealed...)
+		modified[5] = 0xff
+		_, err = OpenSealedShare(modified, passphrase)
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(Equal(ErrSealedShareAuth))
+	})
+
+	It("should reject invalid parameters", func() {
+		for _, p := range []SealParams{
+			{Time: 0, MemoryKiB: 64, Threads: 1},
+			{Time: 1, MemoryKiB: 64, Threads: 0},
+			{Time: 1, MemoryKiB: 4, Threads: 1},
+		} {
+			_, err := SealShareWithParams(randomVShare(), passphrase, p)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should wrap the error of the source of randomness", func() {
+		SetRandomSource(failingReader{})
+		defer SetRandomSource(nil)
+
+		_, err := SealShareWithParams(randomVShare(), passphrase, params)
+		Expect(errors.Is(err, errSourceFailed)).To(BeTrue())
+	})
+})
@@ -0,0 +1,82 @@
+// Package securemem stores secret shares in memory that is locked into RAM,
+// so that it is never written to swap, and that is surrounded by inaccessible
+// guard pages, so that overflows from neighbouring allocations can not read or
+// overwrite it. This is similar to what memguard provides, and is intended for
+// custody providers that must keep shares out of swap and core dumps.
+//
+// Locked memory is allocated directly from the operating system rather than
+// from the Go heap, so it is not moved or copied by the runtime, but it is
+// also not garbage collected: every Buffer must be explicitly destroyed, which
+// zeroes it and returns it to the operating system. The amount of memory that
+// can be locked is usually limited (see RLIMIT_MEMLOCK on Unix), so buffers
+// should be kept small and destroyed as soon as they are no longer needed.
+//
+// Locking memory is only supported on Linux, macOS and FreeBSD. On other
+// platforms, NewBuffer returns ErrUnsupported.
+//
+// Note that values read out of a buffer, such as the shares returned by
+// Shares.Load, are ordinary Go values and are not protected; they should be
+// used briefly and zeroed with Share.Zero.
+package securemem
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned by NewBuffer on platforms where memory can not be
+// locked.
+var ErrUnsupported = errors.New("locked memory is not supported on this platform")
+
+// ErrDestroyed is returned when using a buffer that has been destroyed.
+var ErrDestroyed = errors.New("buffer has been destroyed")
+
+// A Buffer is a fixed size region of locked memory between two guard pages.
+// A Buffer must not be used by more than one goroutine at a time.
+type Buffer struct {
+	// The whole mapping, including the guard pages.
+	mem []byte
+	// The usable part of the mapping, which ends at the start of the second
+	// guard page so that writes past its end fault.
+	data []byte
+}
+
+// NewBuffer allocates a locked buffer of the given size. The buffer is
+// initially zero.
+func NewBuffer(size int) (*Buffer, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid buffer size %v", size)
+	}
+	return newBuffer(size)
+}
+
+// Bytes returns the contents of the buffer. The returned slice refers to the
+// locked memory, and must not be used after the buffer is destroyed, since the
+// memory is then unmapped. It returns nil if the buffer has been destroyed.
+func (b *Buffer) Bytes() []byte {
+	return b.data
+}
+
+// Len returns the size of the buffer, or zero if it has been destroyed.
+func (b *Buffer) Len() int {
+	return len(b.data)
+}
+
+// Destroyed returns true if the buffer has been destroyed.
+func (b *Buffer) Destroyed() bool {
+	return b.mem == nil
+}
+
+// Destroy zeroes the buffer, unlocks it and returns it to the operating
+// system. Destroying a buffer more than once has no effect.
+func (b *Buffer) Destroy() error {
+	if b.mem == nil {
+		return nil
+	}
+	for i := range b.data {
+		b.data[i] = 0
+	}
+	err := freeBuffer(b)
+	b.mem, b.data = nil, nil
+	return err
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package securemem
+
+func newBuffer(int) (*Buffer, error) {
+	return nil, ErrUnsupported
+}
+
+func freeBuffer(*Buffer) error {
+	return nil
+}
@@ -0,0 +1,13 @@
+package securemem_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSecuremem(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Securemem Suite")
+}
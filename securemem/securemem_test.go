@@ -0,0 +1,88 @@
+package securemem_test
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/securemem"
+)
+
+var _ = Describe("Locked memory", func() {
+	Context("buffers", func() {
+		It("should be zero, writable and zeroed when destroyed", func() {
+			b, err := NewBuffer(100)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.Len()).To(Equal(100))
+			Expect(b.Bytes()).To(Equal(make([]byte, 100)))
+
+			for i := range b.Bytes() {
+				b.Bytes()[i] = byte(i)
+			}
+			Expect(b.Bytes()[99]).To(Equal(byte(99)))
+
+			Expect(b.Destroy()).To(Succeed())
+			Expect(b.Destroyed()).To(BeTrue())
+			Expect(b.Bytes()).To(BeNil())
+			Expect(b.Destroy()).To(Succeed())
+		})
+
+		It("should return an error for invalid sizes", func() {
+			_, err := NewBuffer(0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("shares", func() {
+		n, k := 10, 4
+
+		It("should store and load shares", func() {
+			secret := secp256k1.RandomFn()
+			shares := make(shamir.Shares, n)
+			Expect(shamir.ShareSecret(&shares, shamirutil.RandomIndices(n), secret, k)).To(Succeed())
+			expected := append(shamir.Shares{}, shares...)
+
+			s, err := NewSharesFrom(shares)
+			Expect(err).ToNot(HaveOccurred())
+			defer s.Destroy()
+			Expect(s.Len()).To(Equal(n))
+			for i := range shares {
+				Expect(shares[i].Index.IsZero() && shares[i].Value.IsZero()).To(BeTrue())
+			}
+
+			for i := range expected {
+				share, err := s.Load(i)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(share.Eq(&expected[i])).To(BeTrue())
+			}
+
+			buf := make([]byte, 32)
+			Expect(s.Open(buf)).To(Succeed())
+			var opened secp256k1.Fn
+			opened.SetB32(buf)
+			Expect(opened.Eq(&secret)).To(BeTrue())
+		})
+
+		It("should return an error once destroyed", func() {
+			s, err := NewShares(n)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Destroy()).To(Succeed())
+
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			Expect(s.Store(0, &share)).To(Equal(ErrDestroyed))
+			_, err = s.Load(0)
+			Expect(err).To(Equal(ErrDestroyed))
+			Expect(s.Open(make([]byte, 32))).To(Equal(ErrDestroyed))
+		})
+
+		It("should panic for positions out of range", func() {
+			s, err := NewShares(n)
+			Expect(err).ToNot(HaveOccurred())
+			defer s.Destroy()
+			Expect(func() { s.Load(n) }).To(Panic())
+			Expect(func() { s.Load(-1) }).To(Panic())
+		})
+	})
+})
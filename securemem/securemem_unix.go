@@ -0,0 +1,50 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package securemem
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func newBuffer(size int) (*Buffer, error) {
+	pageSize := os.Getpagesize()
+	dataPages := (size + pageSize - 1) / pageSize
+	mem, err := syscall.Mmap(-1, 0, (dataPages+2)*pageSize,
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("could not allocate memory: %v", err)
+	}
+
+	// The data is placed at the end of the pages between the guard pages, so
+	// that an overflow runs into the second guard page immediately.
+	first, last := mem[:pageSize], mem[len(mem)-pageSize:]
+	inner := mem[pageSize : len(mem)-pageSize]
+	b := &Buffer{mem: mem, data: inner[len(inner)-size:]}
+	for _, guard := range [][]byte{first, last} {
+		if err := syscall.Mprotect(guard, syscall.PROT_NONE); err != nil {
+			syscall.Munmap(mem)
+			return nil, fmt.Errorf("could not protect guard page: %v", err)
+		}
+	}
+	if err := syscall.Mlock(inner); err != nil {
+		syscall.Munmap(mem)
+		return nil, fmt.Errorf("could not lock memory: %v", err)
+	}
+	return b, nil
+}
+
+func freeBuffer(b *Buffer) error {
+	pageSize := os.Getpagesize()
+	inner := b.mem[pageSize : len(b.mem)-pageSize]
+	if err := syscall.Munlock(inner); err != nil {
+		syscall.Munmap(b.mem)
+		return fmt.Errorf("could not unlock memory: %v", err)
+	}
+	if err := syscall.Munmap(b.mem); err != nil {
+		return fmt.Errorf("could not free memory: %v", err)
+	}
+	return nil
+}
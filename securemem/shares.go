@@ -0,0 +1,115 @@
+package securemem
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// Shares is a fixed number of shares stored in a locked buffer. Each share is
+// stored as the 32 byte big endian encodings of its index and value.
+type Shares struct {
+	buf *Buffer
+	n   int
+}
+
+// NewShares allocates locked storage for n shares, which are initially zero.
+func NewShares(n int) (*Shares, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of shares %v", n)
+	}
+	buf, err := NewBuffer(n * shamir.ShareSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Shares{buf: buf, n: n}, nil
+}
+
+// NewSharesFrom allocates locked storage for the given shares and copies them
+// into it. The given shares are zeroed once they have been copied, so that the
+// locked copy is the only one.
+func NewSharesFrom(shares shamir.Shares) (*Shares, error) {
+	s, err := NewShares(len(shares))
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		if err := s.Store(i, &shares[i]); err != nil {
+			s.Destroy()
+			return nil, err
+		}
+	}
+	shares.Zero()
+	return s, nil
+}
+
+// Len returns the number of shares.
+func (s *Shares) Len() int {
+	return s.n
+}
+
+// Store copies the share into position i.
+//
+// Panics: This function will panic if i is out of range.
+func (s *Shares) Store(i int, share *shamir.Share) error {
+	bs, err := s.slot(i)
+	if err != nil {
+		return err
+	}
+	share.Index.PutB32(bs[:secp256k1.FnSizeMarshalled])
+	share.Value.PutB32(bs[secp256k1.FnSizeMarshalled:])
+	return nil
+}
+
+// Load returns a copy of the share at position i. The copy is not protected,
+// so it should be zeroed with Share.Zero once it is no longer needed.
+//
+// Panics: This function will panic if i is out of range.
+func (s *Shares) Load(i int) (shamir.Share, error) {
+	var share shamir.Share
+	bs, err := s.slot(i)
+	if err != nil {
+		return share, err
+	}
+	// The stored values were canonical when they were stored, so they can
+	// not overflow.
+	share.Index.SetB32(bs[:secp256k1.FnSizeMarshalled])
+	share.Value.SetB32(bs[secp256k1.FnSizeMarshalled:])
+	return share, nil
+}
+
+// Open reconstructs the secret from the stored shares and writes it as a 32
+// byte big endian integer to dst, as for shamir.OpenInto. The shares are only
+// copied out of the locked buffer for the duration of the call, and the copies
+// are zeroed before returning.
+//
+// Panics: This function will panic if dst is shorter than 32 bytes.
+func (s *Shares) Open(dst []byte) error {
+	shares := make(shamir.Shares, s.n)
+	defer shares.Zero()
+	for i := range shares {
+		var err error
+		if shares[i], err = s.Load(i); err != nil {
+			return err
+		}
+	}
+	shamir.OpenInto(dst, shares)
+	return nil
+}
+
+// Destroy zeroes the shares and releases the locked memory that holds them.
+// Destroying the shares more than once has no effect.
+func (s *Shares) Destroy() error {
+	return s.buf.Destroy()
+}
+
+func (s *Shares) slot(i int) ([]byte, error) {
+	if s.buf.Destroyed() {
+		return nil, ErrDestroyed
+	}
+	if i < 0 || i >= s.n {
+		panic(fmt.Sprintf("share index out of range: expected 0 <= i < %v, got %v", s.n, i))
+	}
+	return s.buf.Bytes()[i*shamir.ShareSize : (i+1)*shamir.ShareSize], nil
+}
@@ -0,0 +1,151 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// ErrSelfTest is wrapped by the errors returned by SelfTest.
+var ErrSelfTest = errors.New("self test failed")
+
+// The known answers of the self tests. The random values of the sharings are
+// read from a katReader, so that the shares and commitment are fixed. The
+// commitment is checked by its digest, as given by Commitment.Hash.
+const (
+	katSecret     = "6b5f5e1a8d0e3c2a4f4b9d7c1e2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c"
+	katN, katK    = 5, 3
+	katCommitment = "47f44d4492b1a977c63299687c8a78545616cd32e79c8da81a2807ae4393c4ad"
+)
+
+// The values of the shares of the secret for the indices 1, 2, ..., katN.
+var katShares = [katN]string{
+	"ac43df1e9b8526f7f97d25b9da08046556c612b65f6beecb21213e8690aa6534",
+	"2a04ea141ac0e484411d1e53b99716080e405da87e4f2a9aea9599175cd148a6",
+	"e4a27efb0ac174cf262b8749bcd77031f93b1a244cdda7abb1e8c3d41e0b6824",
+	"dc1c9dd36b86d7d8a8a8609be3c912e5a2588e5c6c862585f77601a333ec412c",
+	"1073469d3d110da0c893aa4a2e6bfe230998ba50dd48a429bb3d52849e73d3be",
+}
+
+// A katReader is a deterministic source of random bytes for the self tests,
+// whose i-th block of 32 bytes is the SHA-256 hash of a tag and i.
+type katReader struct {
+	counter uint64
+	buf     []byte
+}
+
+func (r *katReader) Read(bs []byte) (int, error) {
+	n := 0
+	for n < len(bs) {
+		if len(r.buf) == 0 {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], r.counter)
+			r.counter++
+			h := sha256.New()
+			h.Write([]byte("renproject/shamir/self test"))
+			h.Write(block[:])
+			r.buf = h.Sum(nil)
+		}
+		m := copy(bs[n:], r.buf)
+		r.buf = r.buf[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// SelfTest runs known-answer tests of sharing, opening and verifiable
+// sharing, and returns an error wrapping ErrSelfTest if any result differs
+// from the expected one. It is meant to be called once at startup by
+// deployments that must not run if the cryptographic implementation, or the
+// platform that it runs on, is faulty. It does not use the source of
+// randomness set with SetRandomSource, and is safe to call concurrently with
+// the other functions of this package.
+func SelfTest() error {
+	if err := selfTestSharing(); err != nil {
+		return fmt.Errorf("%w: sharing: %v", ErrSelfTest, err)
+	}
+	if err := selfTestVSS(); err != nil {
+		return fmt.Errorf("%w: verifiable sharing: %v", ErrSelfTest, err)
+	}
+	return nil
+}
+
+func katIndices() []secp256k1.Fn {
+	indices := make([]secp256k1.Fn, katN)
+	for i := range indices {
+		indices[i].SetU16(uint16(i + 1))
+	}
+	return indices
+}
+
+func katSecretFn() secp256k1.Fn {
+	bs, _ := hex.DecodeString(katSecret)
+	var secret secp256k1.Fn
+	secret.SetB32(bs)
+	return secret
+}
+
+func selfTestSharing() error {
+	secret := katSecretFn()
+	shares := make(Shares, katN)
+	if err := ShareSecret(&shares, katIndices(), secret, katK, WithRandomSource(&katReader{})); err != nil {
+		return err
+	}
+	var bs [32]byte
+	for i := range shares {
+		shares[i].Value.PutB32(bs[:])
+		if got := hex.EncodeToString(bs[:]); got != katShares[i] {
+			return fmt.Errorf("unexpected value %v of share %v", got, i)
+		}
+	}
+
+	// Every qualified subset opens to the secret, and a subset that is too
+	// small does not.
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		qualified := make(Shares, len(subset))
+		for i, j := range subset {
+			qualified[i] = shares[j]
+		}
+		if opened := Open(qualified); !opened.Eq(&secret) {
+			return fmt.Errorf("shares %v did not open to the secret", subset)
+		}
+	}
+	if opened := Open(shares[:katK-1]); opened.Eq(&secret) {
+		return errors.New("too few shares opened to the secret")
+	}
+	return nil
+}
+
+func selfTestVSS() error {
+	secret := katSecretFn()
+	h := PedersenH()
+	vshares := make(VerifiableShares, katN)
+	c := NewCommitmentWithCapacity(katK)
+	if err := VShareSecret(&vshares, &c, katIndices(), h, secret, katK, WithRandomSource(&katReader{})); err != nil {
+		return err
+	}
+	digest := c.Hash()
+	if got := hex.EncodeToString(digest[:]); got != katCommitment {
+		return fmt.Errorf("unexpected commitment digest %v", got)
+	}
+	for i := range vshares {
+		if !IsValid(h, &c, &vshares[i]) {
+			return fmt.Errorf("share %v is not valid", i)
+		}
+	}
+	bad := vshares[0]
+	var one secp256k1.Fn
+	one.SetU16(1)
+	bad.Share.Value.Add(&bad.Share.Value, &one)
+	if IsValid(h, &c, &bad) {
+		return errors.New("modified share is valid")
+	}
+	if opened := Open(vshares.Shares()[:katK]); !opened.Eq(&secret) {
+		return errors.New("shares did not open to the secret")
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package shamir_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+)
+
+var _ = Describe("Self test", func() {
+	It("should pass", func() {
+		Expect(SelfTest()).To(Succeed())
+	})
+
+	It("should not use the global source of randomness", func() {
+		SetRandomSource(failingReader{})
+		defer SetRandomSource(nil)
+		Expect(SelfTest()).To(Succeed())
+	})
+})
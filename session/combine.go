@@ -0,0 +1,293 @@
+package session
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// The stream of one recipient that is being combined.
+type source struct {
+	r     io.Reader
+	index secp256k1.Fn
+
+	// The last record that was read, which is valid if have is true.
+	have      bool
+	seq       uint64
+	recHeader [recordSize]byte
+	length    int
+	body      []byte
+}
+
+// Reads records from the source until it has the one with the given
+// sequence number.
+func (src *source) advance(seq uint64, chunkSize int) error {
+	if src.r == nil {
+		return errors.New("stream failed and must be resumed")
+	}
+	for !src.have || src.seq < seq {
+		if err := src.read(chunkSize); err != nil {
+			src.r = nil
+			src.have = false
+			return err
+		}
+	}
+	if src.seq > seq {
+		src.r = nil
+		src.have = false
+		return fmt.Errorf("%w: expected chunk %v, got chunk %v", ErrInvalidRecord, seq, src.seq)
+	}
+	return nil
+}
+
+// Reads the next record.
+func (src *source) read(chunkSize int) error {
+	src.have = false
+	if _, err := io.ReadFull(src.r, src.recHeader[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%w: %v", ErrTruncated, err)
+		}
+		return fmt.Errorf("could not read record: %w", err)
+	}
+	src.seq = binary.BigEndian.Uint64(src.recHeader[0:8])
+	if src.recHeader[8]&^flagFinal != 0 {
+		return fmt.Errorf("%w: unknown flags %v", ErrInvalidRecord, src.recHeader[8])
+	}
+	length := binary.BigEndian.Uint32(src.recHeader[9:13])
+	if length > uint32(chunkSize) {
+		return fmt.Errorf("%w: chunk length %v greater than chunk size %v", ErrInvalidRecord, length, chunkSize)
+	}
+	src.length = int(length)
+	if src.body == nil {
+		src.body = make([]byte, recordLen(chunkSize)-recordSize)
+	}
+	body := src.body[:recordLen(src.length)-recordSize]
+	if _, err := io.ReadFull(src.r, body); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%w: %v", ErrTruncated, err)
+		}
+		return fmt.Errorf("could not read record: %w", err)
+	}
+	src.have = true
+	return nil
+}
+
+// Returns the j-th share value of the current record.
+func (src *source) value(j int) []byte {
+	return src.body[32*j : 32*(j+1)]
+}
+
+// Returns the tag of the current record.
+func (src *source) tag() []byte {
+	n := numScalars(src.length)
+	return src.body[32*n : 32*n+tagSize]
+}
+
+// A CombineSession reconstructs a secret that was shared by a SplitSession
+// from the streams of some of the recipients, and writes it to a stream. A
+// CombineSession must not be used by more than one goroutine at a time.
+type CombineSession struct {
+	sources   []source
+	lagrange  []secp256k1.Fn
+	id        [16]byte
+	k         int
+	chunkSize int
+	mac       hash.Hash
+
+	next   uint64
+	done   bool
+	offset int64
+
+	plain []byte
+	out   []byte
+}
+
+// NewCombineSession reads the headers of the given streams, which must be
+// from the same session and from different recipients, and returns a session
+// that reconstructs the secret from them. The secret can only be
+// reconstructed if there are at least k streams. Fewer are accepted, since
+// that can not be detected from the headers, but Combine will then fail with
+// an error wrapping ErrIntegrity.
+func NewCombineSession(streams []io.Reader) (*CombineSession, error) {
+	if len(streams) == 0 {
+		return nil, errors.New("no streams given")
+	}
+	c := &CombineSession{sources: make([]source, len(streams))}
+	keyShares := make(shamir.Shares, len(streams))
+	defer keyShares.Zero()
+	indices := make([]secp256k1.Fn, len(streams))
+	for i, r := range streams {
+		h, err := readHeader(r)
+		if err != nil {
+			return nil, fmt.Errorf("stream %v: %w", i, err)
+		}
+		if i == 0 {
+			c.id, c.k, c.chunkSize = h.id, int(h.k), int(h.chunkSize)
+		} else if err := c.checkHeader(&h); err != nil {
+			return nil, fmt.Errorf("stream %v: %w", i, err)
+		}
+		for j := 0; j < i; j++ {
+			if h.index.Eq(&indices[j]) {
+				return nil, fmt.Errorf("%w: streams %v and %v are from the same recipient", ErrInvalidHeader, j, i)
+			}
+		}
+		c.sources[i] = source{r: r, index: h.index}
+		indices[i] = h.index
+		keyShares[i] = shamir.NewShare(h.index, h.keyShare)
+		h.keyShare.Clear()
+	}
+
+	lagrange, err := shamir.LagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	c.lagrange = lagrange
+	key := shamir.Open(keyShares)
+	c.mac = newMAC(&key)
+	key.Clear()
+	c.plain = make([]byte, c.chunkSize)
+	return c, nil
+}
+
+// Combine reads chunks from the streams, and writes each one to dst once its
+// tag has been checked, until the final chunk has been written. It returns
+// the number of bytes written to dst. If reading one of the streams fails,
+// the error is returned, and Combine can be called again to continue once the
+// stream has been replaced with Resume. If writing to dst fails, the error is
+// returned, and the rest of the chunk is written first when Combine is called
+// again. Calling Combine once the final chunk has been written does nothing.
+func (c *CombineSession) Combine(dst io.Writer) (int64, error) {
+	var written int64
+	for {
+		if len(c.out) > 0 {
+			n, err := dst.Write(c.out)
+			written += int64(n)
+			c.offset += int64(n)
+			c.out = c.out[n:]
+			if err != nil {
+				return written, err
+			}
+		}
+		wipe(c.plain)
+		if c.done {
+			return written, nil
+		}
+		if err := c.open(); err != nil {
+			return written, err
+		}
+	}
+}
+
+// Resume replaces the stream at the given position, which is the position
+// in the slice given to NewCombineSession, with a new stream of the same
+// recipient. The header of the new stream is read and checked, and any
+// records that it repeats are skipped.
+func (c *CombineSession) Resume(i int, r io.Reader) error {
+	if i < 0 || i >= len(c.sources) {
+		return fmt.Errorf("invalid stream position %v", i)
+	}
+	h, err := readHeader(r)
+	if err != nil {
+		return err
+	}
+	defer h.keyShare.Clear()
+	if err := c.checkHeader(&h); err != nil {
+		return err
+	}
+	if !h.index.Eq(&c.sources[i].index) {
+		return fmt.Errorf("%w: stream is from a different recipient", ErrInvalidHeader)
+	}
+	c.sources[i].r = r
+	return nil
+}
+
+// Offset returns the number of bytes of the secret that have been written.
+func (c *CombineSession) Offset() int64 {
+	return c.offset
+}
+
+// Done returns true once the final chunk has been written.
+func (c *CombineSession) Done() bool {
+	return c.done && len(c.out) == 0
+}
+
+// Wipe zeroes the buffers of the session, after which it can no longer be
+// used.
+func (c *CombineSession) Wipe() {
+	c.mac = nil
+	c.out = nil
+	wipe(c.plain)
+	for i := range c.sources {
+		wipe(c.sources[i].body)
+	}
+}
+
+func (c *CombineSession) checkHeader(h *header) error {
+	switch {
+	case h.id != c.id:
+		return fmt.Errorf("%w: different session", ErrInvalidHeader)
+	case int(h.k) != c.k:
+		return fmt.Errorf("%w: different threshold", ErrInvalidHeader)
+	case int(h.chunkSize) != c.chunkSize:
+		return fmt.Errorf("%w: different chunk size", ErrInvalidHeader)
+	}
+	return nil
+}
+
+// Reconstructs and checks the next chunk, and sets it as the output.
+func (c *CombineSession) open() error {
+	for i := range c.sources {
+		if err := c.sources[i].advance(c.next, c.chunkSize); err != nil {
+			return fmt.Errorf("stream %v: %w", i, err)
+		}
+	}
+	first := &c.sources[0]
+	for i := 1; i < len(c.sources); i++ {
+		if c.sources[i].recHeader != first.recHeader {
+			return fmt.Errorf("%w: streams 0 and %v disagree on chunk %v", ErrInvalidRecord, i, c.next)
+		}
+	}
+
+	chunk := c.plain[:first.length]
+	var x, term, value secp256k1.Fn
+	defer x.Clear()
+	defer term.Clear()
+	defer value.Clear()
+	valid := true
+	for j := 0; j < numScalars(first.length); j++ {
+		x.Clear()
+		for i := range c.sources {
+			value.SetB32(c.sources[i].value(j))
+			term.Mul(&c.lagrange[i], &value)
+			x.Add(&x, &term)
+		}
+		end := (j + 1) * ScalarBytes
+		if end > len(chunk) {
+			end = len(chunk)
+		}
+		valid = decodeScalar(chunk[j*ScalarBytes:end], &x) && valid
+	}
+
+	var tag [tagSize]byte
+	computeTag(tag[:], c.mac, &c.id, first.recHeader[:], chunk)
+	for i := range c.sources {
+		valid = hmac.Equal(tag[:], c.sources[i].tag()) && valid
+	}
+	if !valid {
+		wipe(chunk)
+		return fmt.Errorf("%w: chunk %v", ErrIntegrity, c.next)
+	}
+	for i := range c.sources {
+		c.sources[i].have = false
+	}
+	c.done = first.recHeader[8]&flagFinal != 0
+	c.next++
+	c.out = chunk
+	return nil
+}
@@ -0,0 +1,196 @@
+// Package session shares byte strings that are too large to hold in memory,
+// such as backups or model weights, by streaming them through chunked sharing
+// sessions. A SplitSession reads the secret from an io.Reader and writes a
+// stream of shares to each recipient, and a CombineSession reads the streams
+// of at least k recipients and writes the secret to an io.Writer.
+//
+// The secret is split into chunks, and each chunk into scalars of 31 bytes,
+// which are shared independently over secp256k1 with shamir.ShareSecret. The
+// recipients are given the indices 1, 2, ..., n, in the order of the writers
+// passed to NewSplitSession. Each stream starts with a header, which holds
+// the share of the recipient of a random MAC key, followed by one record for
+// each chunk:
+//
+//	header  magic || version || session ID || k || chunk size || index || key share
+//	record  sequence number || flags || length || values || tag
+//
+// where the integers are big endian, of 4 bytes except for the 8 byte
+// sequence number and the 1 byte version and flags. The values are the shares
+// of the scalars of the chunk, 32 bytes each, and the tag is an HMAC-SHA256,
+// keyed by the MAC key, of the session ID, sequence number, flags, length and
+// the chunk itself, which is the same in the stream of every recipient. The
+// last record has the final flag set, and may be empty. The tags let the
+// combiner detect, before it writes a chunk, that a share was modified, that
+// chunks were reordered, or that streams from different sessions were mixed;
+// together with the final flag they also detect truncation.
+//
+// Sessions can be resumed when a stream fails part way through. Every stream
+// can be replaced by a new one, which starts with a header and may repeat
+// records that were already received, which are skipped. See
+// SplitSession.Resume and CombineSession.Resume.
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/renproject/secp256k1"
+)
+
+// DefaultChunkSize is the number of bytes in each chunk, apart from the last,
+// when no chunk size is given. It is a multiple of ScalarBytes, so that the
+// scalars of full chunks are filled.
+const DefaultChunkSize = 1024 * ScalarBytes
+
+// MaxChunkSize is the largest chunk size that a session accepts, which bounds
+// the memory used by a CombineSession for streams that it does not trust.
+const MaxChunkSize = 1 << 24
+
+// MaxRecipients is the largest number of recipients of a SplitSession.
+const MaxRecipients = 1<<16 - 1
+
+// ScalarBytes is the number of bytes of the secret held by each scalar, so
+// that its big endian value is always less than the order of the group.
+const ScalarBytes = 31
+
+const (
+	version    = 1
+	flagFinal  = 1
+	headerSize = 4 + 1 + 16 + 4 + 4 + 32 + 32
+	recordSize = 8 + 1 + 4
+	tagSize    = sha256.Size
+)
+
+var magic = [4]byte{'S', 'H', 'S', 'S'}
+
+var (
+	// ErrInvalidHeader is wrapped by the errors for headers that can not be
+	// decoded or do not belong to the same session as the other streams.
+	ErrInvalidHeader = errors.New("invalid stream header")
+	// ErrInvalidRecord is wrapped by the errors for records that can not be
+	// decoded or are not in sequence.
+	ErrInvalidRecord = errors.New("invalid chunk record")
+	// ErrIntegrity is wrapped by the errors for chunks whose tag does not
+	// match the reconstructed chunk, which happens when a share was modified
+	// or fewer than k streams were given.
+	ErrIntegrity = errors.New("chunk failed integrity check")
+	// ErrTruncated is wrapped by the errors for streams that end before the
+	// final chunk.
+	ErrTruncated = errors.New("stream ended before the final chunk")
+)
+
+// The header of the stream of one recipient.
+type header struct {
+	id        [16]byte
+	k         uint32
+	chunkSize uint32
+	index     secp256k1.Fn
+	keyShare  secp256k1.Fn
+}
+
+func (h *header) marshal(buf []byte) {
+	copy(buf, magic[:])
+	buf[4] = version
+	copy(buf[5:21], h.id[:])
+	binary.BigEndian.PutUint32(buf[21:25], h.k)
+	binary.BigEndian.PutUint32(buf[25:29], h.chunkSize)
+	h.index.PutB32(buf[29:61])
+	h.keyShare.PutB32(buf[61:93])
+}
+
+func readHeader(r io.Reader) (header, error) {
+	var buf [headerSize]byte
+	defer wipe(buf[:])
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return header{}, fmt.Errorf("could not read header: %w", err)
+	}
+	if buf[0] != magic[0] || buf[1] != magic[1] || buf[2] != magic[2] || buf[3] != magic[3] {
+		return header{}, fmt.Errorf("%w: bad magic %x", ErrInvalidHeader, buf[:4])
+	}
+	if buf[4] != version {
+		return header{}, fmt.Errorf("%w: unsupported version %v", ErrInvalidHeader, buf[4])
+	}
+	var h header
+	copy(h.id[:], buf[5:21])
+	h.k = binary.BigEndian.Uint32(buf[21:25])
+	h.chunkSize = binary.BigEndian.Uint32(buf[25:29])
+	if h.k == 0 {
+		return header{}, fmt.Errorf("%w: threshold is zero", ErrInvalidHeader)
+	}
+	if h.chunkSize == 0 || h.chunkSize > MaxChunkSize {
+		return header{}, fmt.Errorf("%w: chunk size %v out of range", ErrInvalidHeader, h.chunkSize)
+	}
+	if h.index.SetB32(buf[29:61]) || h.index.IsZero() {
+		return header{}, fmt.Errorf("%w: invalid index", ErrInvalidHeader)
+	}
+	if h.keyShare.SetB32(buf[61:93]) {
+		return header{}, fmt.Errorf("%w: invalid key share", ErrInvalidHeader)
+	}
+	return h, nil
+}
+
+// Returns the number of scalars that hold a chunk of the given length.
+func numScalars(length int) int {
+	return (length + ScalarBytes - 1) / ScalarBytes
+}
+
+// Returns the number of bytes in a record for a chunk of the given length.
+func recordLen(length int) int {
+	return recordSize + 32*numScalars(length) + tagSize
+}
+
+// Returns the MAC keyed by the given key.
+func newMAC(key *secp256k1.Fn) hash.Hash {
+	var bs [32]byte
+	key.PutB32(bs[:])
+	defer wipe(bs[:])
+	return hmac.New(sha256.New, bs[:])
+}
+
+// Computes the tag of a chunk with the given record header into tag.
+func computeTag(tag []byte, mac hash.Hash, id *[16]byte, recHeader, chunk []byte) {
+	mac.Reset()
+	mac.Write(id[:])
+	mac.Write(recHeader)
+	mac.Write(chunk)
+	mac.Sum(tag[:0])
+}
+
+// Sets x to the scalar that holds the given bytes, of which there must be at
+// most ScalarBytes.
+func encodeScalar(x *secp256k1.Fn, bs []byte) {
+	var buf [32]byte
+	copy(buf[1:], bs)
+	x.SetB32(buf[:])
+	wipe(buf[:])
+}
+
+// Copies the bytes held by the scalar into bs, of which there must be at most
+// ScalarBytes, and returns false if the scalar does not hold a chunk of that
+// length.
+func decodeScalar(bs []byte, x *secp256k1.Fn) bool {
+	var buf [32]byte
+	defer wipe(buf[:])
+	x.PutB32(buf[:])
+	copy(bs, buf[1:])
+	if buf[0] != 0 {
+		return false
+	}
+	for _, b := range buf[1+len(bs):] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func wipe(bs []byte) {
+	for i := range bs {
+		bs[i] = 0
+	}
+}
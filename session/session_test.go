@@ -0,0 +1,223 @@
+package session_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/renproject/shamir"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/session"
+)
+
+// A failingWriter accepts the given number of bytes and then fails.
+type failingWriter struct {
+	w    io.Writer
+	left int
+}
+
+func (w *failingWriter) Write(bs []byte) (int, error) {
+	if len(bs) > w.left {
+		n, _ := w.w.Write(bs[:w.left])
+		w.left = 0
+		return n, errors.New("write failed")
+	}
+	w.left -= len(bs)
+	return w.w.Write(bs)
+}
+
+var _ = Describe("Sessions", func() {
+	n, k := 5, 3
+	chunkSize := 4 * ScalarBytes
+
+	randomSecret := func(l int) []byte {
+		secret := make([]byte, l)
+		rand.Read(secret)
+		return secret
+	}
+
+	split := func(secret []byte, opts ...shamir.ShareOption) []*bytes.Buffer {
+		bufs := make([]*bytes.Buffer, n)
+		writers := make([]io.Writer, n)
+		for i := range bufs {
+			bufs[i] = new(bytes.Buffer)
+			writers[i] = bufs[i]
+		}
+		s, err := NewSplitSession(writers, k, chunkSize, opts...)
+		Expect(err).ToNot(HaveOccurred())
+		defer s.Wipe()
+		Expect(s.Split(bytes.NewReader(secret))).To(Succeed())
+		Expect(s.Done()).To(BeTrue())
+		Expect(s.Offset()).To(Equal(int64(len(secret))))
+		return bufs
+	}
+
+	combine := func(streams ...[]byte) ([]byte, error) {
+		readers := make([]io.Reader, len(streams))
+		for i := range streams {
+			readers[i] = bytes.NewReader(streams[i])
+		}
+		c, err := NewCombineSession(readers)
+		if err != nil {
+			return nil, err
+		}
+		defer c.Wipe()
+		var out bytes.Buffer
+		if _, err := c.Combine(&out); err != nil {
+			return nil, err
+		}
+		Expect(c.Done()).To(BeTrue())
+		return out.Bytes(), nil
+	}
+
+	It("should reconstruct secrets of any length from k streams", func() {
+		for _, l := range []int{0, 1, ScalarBytes, chunkSize - 1, chunkSize, 3*chunkSize + 7} {
+			secret := randomSecret(l)
+			bufs := split(secret)
+			for _, set := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 2, 3, 4}} {
+				streams := make([][]byte, len(set))
+				for i, j := range set {
+					streams[i] = bufs[j].Bytes()
+				}
+				out, err := combine(streams...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(HaveLen(l))
+				Expect(bytes.Equal(out, secret)).To(BeTrue())
+			}
+		}
+	})
+
+	It("should fail the integrity check with fewer than k streams", func() {
+		bufs := split(randomSecret(chunkSize))
+		_, err := combine(bufs[0].Bytes(), bufs[1].Bytes())
+		Expect(errors.Is(err, ErrIntegrity)).To(BeTrue())
+	})
+
+	It("should detect a modified share", func() {
+		bufs := split(randomSecret(2 * chunkSize))
+		stream := append([]byte{}, bufs[1].Bytes()...)
+		stream[len(stream)/2] ^= 1
+		_, err := combine(bufs[0].Bytes(), stream, bufs[2].Bytes())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should detect truncated streams", func() {
+		bufs := split(randomSecret(2 * chunkSize))
+		stream := bufs[1].Bytes()
+		_, err := combine(bufs[0].Bytes(), stream[:len(stream)-1], bufs[2].Bytes())
+		Expect(errors.Is(err, ErrTruncated)).To(BeTrue())
+	})
+
+	It("should reject streams from different sessions", func() {
+		bufs1 := split(randomSecret(chunkSize))
+		bufs2 := split(randomSecret(chunkSize))
+		_, err := combine(bufs1[0].Bytes(), bufs1[1].Bytes(), bufs2[2].Bytes())
+		Expect(errors.Is(err, ErrInvalidHeader)).To(BeTrue())
+		_, err = combine(bufs1[0].Bytes(), bufs1[0].Bytes(), bufs1[1].Bytes())
+		Expect(errors.Is(err, ErrInvalidHeader)).To(BeTrue())
+	})
+
+	It("should pass the share options to the sharing of each scalar", func() {
+		check := func([]byte) error { return errors.New("unhealthy") }
+		writers := make([]io.Writer, n)
+		for i := range writers {
+			writers[i] = new(bytes.Buffer)
+		}
+		_, err := NewSplitSession(writers, k, chunkSize, shamir.WithEntropyCheck(check))
+		Expect(errors.Is(err, shamir.ErrWeakRandomness)).To(BeTrue())
+	})
+
+	It("should resume splitting with a new writer", func() {
+		secret := randomSecret(3*chunkSize + 5)
+		bufs := make([]*bytes.Buffer, n)
+		writers := make([]io.Writer, n)
+		for i := range bufs {
+			bufs[i] = new(bytes.Buffer)
+			writers[i] = bufs[i]
+		}
+		failed := new(bytes.Buffer)
+		writers[1] = &failingWriter{w: failed, left: 200}
+		s, err := NewSplitSession(writers, k, chunkSize)
+		Expect(err).ToNot(HaveOccurred())
+
+		src := bytes.NewReader(secret)
+		err = s.Split(src)
+		var werr *WriteError
+		Expect(errors.As(err, &werr)).To(BeTrue())
+		Expect(werr.Recipients).To(Equal([]int{1}))
+		Expect(s.Done()).To(BeFalse())
+
+		resumed := new(bytes.Buffer)
+		resumeWriters := make([]io.Writer, n)
+		resumeWriters[1] = resumed
+		Expect(s.Resume(resumeWriters)).To(Succeed())
+		Expect(s.Split(src)).To(Succeed())
+		Expect(s.Done()).To(BeTrue())
+
+		// The failed stream holds the header and a partial record, so the
+		// combiner reads it up to the failure and then resumes with the new
+		// stream.
+		c, err := NewCombineSession([]io.Reader{
+			bytes.NewReader(bufs[0].Bytes()),
+			bytes.NewReader(failed.Bytes()),
+			bytes.NewReader(bufs[2].Bytes()),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		var out bytes.Buffer
+		_, err = c.Combine(&out)
+		Expect(errors.Is(err, ErrTruncated)).To(BeTrue())
+		Expect(c.Resume(1, bytes.NewReader(resumed.Bytes()))).To(Succeed())
+		_, err = c.Combine(&out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c.Done()).To(BeTrue())
+		Expect(bytes.Equal(out.Bytes(), secret)).To(BeTrue())
+	})
+
+	It("should resume combining with a stream that repeats records", func() {
+		secret := randomSecret(4 * chunkSize)
+		bufs := split(secret)
+		stream := bufs[2].Bytes()
+		headerAndOne := 93 + 13 + 32*4 + 32
+
+		c, err := NewCombineSession([]io.Reader{
+			bytes.NewReader(bufs[0].Bytes()),
+			bytes.NewReader(bufs[1].Bytes()),
+			bytes.NewReader(stream[:headerAndOne+50]),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		var out bytes.Buffer
+		_, err = c.Combine(&out)
+		Expect(errors.Is(err, ErrTruncated)).To(BeTrue())
+		Expect(c.Offset()).To(Equal(int64(chunkSize)))
+
+		// Resuming with the whole stream repeats the first chunk.
+		Expect(c.Resume(2, bytes.NewReader(stream))).To(Succeed())
+		_, err = c.Combine(&out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(out.Bytes(), secret)).To(BeTrue())
+
+		// A stream of a different recipient is rejected.
+		Expect(errors.Is(c.Resume(2, bytes.NewReader(bufs[3].Bytes())), ErrInvalidHeader)).To(BeTrue())
+	})
+
+	It("should continue writing the chunk after the destination fails", func() {
+		secret := randomSecret(2*chunkSize + 3)
+		bufs := split(secret)
+		c, err := NewCombineSession([]io.Reader{
+			bytes.NewReader(bufs[0].Bytes()),
+			bytes.NewReader(bufs[1].Bytes()),
+			bytes.NewReader(bufs[2].Bytes()),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		var out bytes.Buffer
+		written, err := c.Combine(&failingWriter{w: &out, left: chunkSize + 10})
+		Expect(err).To(HaveOccurred())
+		Expect(written).To(Equal(int64(chunkSize + 10)))
+		_, err = c.Combine(&out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytes.Equal(out.Bytes(), secret)).To(BeTrue())
+	})
+})
@@ -0,0 +1,300 @@
+package session
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// A WriteError is returned by SplitSession.Split when writing to some of the
+// recipients fails. The chunk is still written to the other recipients.
+type WriteError struct {
+	// Recipients are the positions of the recipients that failed, in
+	// increasing order.
+	Recipients []int
+	// Err is the error of the first recipient that failed.
+	Err error
+}
+
+func (err *WriteError) Error() string {
+	return fmt.Sprintf("could not write to recipients %v: %v", err.Recipients, err.Err)
+}
+
+// Unwrap returns the error of the first recipient that failed.
+func (err *WriteError) Unwrap() error {
+	return err.Err
+}
+
+// A SplitSession shares a secret that is read from a stream, writing the
+// shares of each recipient to its own stream. A SplitSession must not be used
+// by more than one goroutine at a time.
+type SplitSession struct {
+	recipients []io.Writer
+	indices    []secp256k1.Fn
+	headers    [][headerSize]byte
+	k          int
+	opts       []shamir.ShareOption
+
+	id  [16]byte
+	key secp256k1.Fn
+	mac hash.Hash
+
+	buf    []byte
+	shares shamir.Shares
+	seq    uint64
+	offset int64
+	done   bool
+
+	// The records of the last chunk, which are kept until every recipient
+	// has been sent its record.
+	records    [][]byte
+	sent       []bool
+	needHeader []bool
+}
+
+// NewSplitSession returns a session that shares a secret between the given
+// recipients, any k of which can reconstruct it, in chunks of the given
+// number of bytes. DefaultChunkSize is used if chunkSize is not positive. The
+// options are passed to shamir.ShareSecret for every scalar, and the session
+// ID and MAC key are also read from the source of randomness given by the
+// options. Nothing is written until Split is called.
+func NewSplitSession(recipients []io.Writer, k, chunkSize int, opts ...shamir.ShareOption) (*SplitSession, error) {
+	n := len(recipients)
+	if n > MaxRecipients {
+		return nil, fmt.Errorf("too many recipients: expected at most %v, got %v", MaxRecipients, n)
+	}
+	if k < 1 || k > n {
+		return nil, fmt.Errorf("%w: expected 1 <= k <= %v, got k = %v", shamir.ErrInvalidThreshold, n, k)
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if chunkSize > MaxChunkSize {
+		return nil, fmt.Errorf("chunk size too large: expected at most %v, got %v", MaxChunkSize, chunkSize)
+	}
+	var options shamir.ShareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	r := options.Random
+	if r == nil {
+		r = shamir.RandomSource()
+	}
+
+	s := &SplitSession{
+		recipients: append([]io.Writer{}, recipients...),
+		indices:    make([]secp256k1.Fn, n),
+		headers:    make([][headerSize]byte, n),
+		k:          k,
+		opts:       opts,
+		buf:        make([]byte, chunkSize),
+		shares:     make(shamir.Shares, n),
+		records:    make([][]byte, n),
+		sent:       make([]bool, n),
+		needHeader: make([]bool, n),
+	}
+	var keyBytes [32]byte
+	defer wipe(keyBytes[:])
+	if _, err := io.ReadFull(r, s.id[:]); err != nil {
+		return nil, fmt.Errorf("could not generate session ID: %w", err)
+	}
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return nil, fmt.Errorf("could not generate MAC key: %w", err)
+	}
+	s.key.SetB32(keyBytes[:])
+	s.mac = newMAC(&s.key)
+
+	for i := range recipients {
+		s.indices[i].SetU16(uint16(i + 1))
+	}
+	if err := shamir.ShareSecret(&s.shares, s.indices, s.key, k, opts...); err != nil {
+		return nil, err
+	}
+	defer s.shares.Zero()
+	for i := range recipients {
+		h := header{
+			id:        s.id,
+			k:         uint32(k),
+			chunkSize: uint32(chunkSize),
+			index:     s.indices[i],
+			keyShare:  s.shares[i].Value,
+		}
+		h.marshal(s.headers[i][:])
+		h.keyShare.Clear()
+		s.records[i] = make([]byte, 0, recordLen(chunkSize))
+		s.sent[i] = true
+		s.needHeader[i] = true
+	}
+	return s, nil
+}
+
+// Split reads the secret from src until it returns io.EOF, and writes the
+// shares of each chunk to the recipients, finishing with the final chunk. If
+// reading from src fails, the error is returned, and Split can be called
+// again to continue with a source that starts at Offset. If writing to some
+// of the recipients fails, a *WriteError is returned; once their writers have
+// been replaced with Resume, Split can be called again with the same source
+// to continue. Calling Split once the final chunk has been written does
+// nothing.
+func (s *SplitSession) Split(src io.Reader) error {
+	if err := s.flush(); err != nil {
+		return err
+	}
+	for !s.done {
+		n, err := io.ReadFull(src, s.buf)
+		final := false
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			final = true
+		default:
+			wipe(s.buf[:n])
+			return fmt.Errorf("could not read secret: %w", err)
+		}
+		err = s.seal(s.buf[:n], final)
+		wipe(s.buf[:n])
+		if err != nil {
+			return err
+		}
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resume replaces the writers of the recipients with the non-nil writers of
+// the given slice, which must have one entry for each recipient. Each new
+// writer is sent the header again before any record, followed by the record
+// of the last chunk if the recipient had not yet been sent all of it.
+func (s *SplitSession) Resume(recipients []io.Writer) error {
+	if len(recipients) != len(s.recipients) {
+		return fmt.Errorf("invalid number of recipients: expected %v, got %v", len(s.recipients), len(recipients))
+	}
+	for i, w := range recipients {
+		if w != nil {
+			s.recipients[i] = w
+			s.needHeader[i] = true
+		}
+	}
+	return nil
+}
+
+// Offset returns the number of bytes of the secret that have been read and
+// shared. It is where a new source should start when Split is called again
+// after reading failed.
+func (s *SplitSession) Offset() int64 {
+	return s.offset
+}
+
+// Done returns true once the final chunk has been written to every
+// recipient.
+func (s *SplitSession) Done() bool {
+	return s.done && s.flushed()
+}
+
+// Wipe zeroes the MAC key and the buffers of the session, after which it can
+// no longer be used.
+func (s *SplitSession) Wipe() {
+	s.key.Clear()
+	s.mac = nil
+	for i := range s.records {
+		wipe(s.records[i][:cap(s.records[i])])
+	}
+	wipe(s.buf)
+}
+
+// Builds the record of each recipient for the given chunk.
+func (s *SplitSession) seal(chunk []byte, final bool) error {
+	var recHeader [recordSize]byte
+	binary.BigEndian.PutUint64(recHeader[0:8], s.seq)
+	if final {
+		recHeader[8] = flagFinal
+	}
+	binary.BigEndian.PutUint32(recHeader[9:13], uint32(len(chunk)))
+	for i := range s.records {
+		s.records[i] = append(s.records[i][:0], recHeader[:]...)
+	}
+
+	var x secp256k1.Fn
+	defer x.Clear()
+	defer s.shares.Zero()
+	for j := 0; j < len(chunk); j += ScalarBytes {
+		end := j + ScalarBytes
+		if end > len(chunk) {
+			end = len(chunk)
+		}
+		encodeScalar(&x, chunk[j:end])
+		if err := shamir.ShareSecret(&s.shares, s.indices, x, s.k, s.opts...); err != nil {
+			return err
+		}
+		for i := range s.records {
+			rec := s.records[i]
+			s.records[i] = rec[:len(rec)+32]
+			s.shares[i].Value.PutB32(s.records[i][len(rec):])
+		}
+	}
+
+	var tag [tagSize]byte
+	computeTag(tag[:], s.mac, &s.id, recHeader[:], chunk)
+	for i := range s.records {
+		s.records[i] = append(s.records[i], tag[:]...)
+		s.sent[i] = false
+	}
+	s.seq++
+	s.offset += int64(len(chunk))
+	s.done = final
+	return nil
+}
+
+// Writes the record of the last chunk to the recipients that have not been
+// sent it.
+func (s *SplitSession) flush() error {
+	var failed []int
+	var first error
+	for i, w := range s.recipients {
+		if s.sent[i] && !s.needHeader[i] {
+			continue
+		}
+		if s.needHeader[i] {
+			if _, err := w.Write(s.headers[i][:]); err != nil {
+				failed = append(failed, i)
+				if first == nil {
+					first = err
+				}
+				continue
+			}
+			s.needHeader[i] = false
+		}
+		if !s.sent[i] {
+			if _, err := w.Write(s.records[i]); err != nil {
+				// The recipient may have received part of the record, so
+				// the stream can only continue with a new writer.
+				s.needHeader[i] = true
+				failed = append(failed, i)
+				if first == nil {
+					first = err
+				}
+				continue
+			}
+			s.sent[i] = true
+		}
+	}
+	if first != nil {
+		return &WriteError{Recipients: failed, Err: first}
+	}
+	return nil
+}
+
+func (s *SplitSession) flushed() bool {
+	for i := range s.sent {
+		if !s.sent[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/fnbatch"
 )
 
 // ShareSize is the number of bytes in a share.
@@ -72,36 +73,41 @@ func (s *Share) Scale(other *Share, scale *secp256k1.Fn) {
 // threshold, and stores them in the given destination slice. In the returned
 // Shares, there will be one share for each index in the indices that were used
 // to construct the Sharer. If k is larger than the number of indices, in which
-// case it would be impossible to reconstruct the secret, an error is returned.
+// case it would be impossible to reconstruct the secret, or is less than one,
+// an error wrapping ErrInvalidThreshold is returned.
+// The checks that are performed on the indices can be configured with the
+// given options, as described for ShareOptions.
 //
 // Panics: This function will panic if the destination shares slice has a
-// capacity less than n (the number of indices).
-func ShareSecret(dst *Shares, indices []secp256k1.Fn, secret secp256k1.Fn, k int) error {
-	coeffs := make([]secp256k1.Fn, k)
-	return ShareAndGetCoeffs(dst, coeffs, indices, secret, k)
+// capacity less than n (the number of indices), or if any of the indices is
+// zero and the WithAllowZeroIndexOff option is not given.
+func ShareSecret(dst *Shares, indices []secp256k1.Fn, secret secp256k1.Fn, k int, opts ...ShareOption) error {
+	options := newShareOptions(opts)
+	if err := validateIndices(indices, options); err != nil {
+		return err
+	}
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	return shareSecret(dst, indices, secret, k, s, &options)
 }
 
 // ShareAndGetCoeffs is the same as ShareSecret, but uses the provided slice to
 // store the generated coefficients of the sharing polynomial. If this function
 // successfully returns, this slice will contain the coefficients of the
-// sharing polynomial, where index 0 is the constant term.
+// sharing polynomial, where index 0 is the constant term. Since these
+// coefficients determine the secret, the caller should zero them with WipeFns
+// once they are no longer needed.
 //
 // Panics: This function will panic if the destination shares slice has a
 // capacity less than n (the number of indices) or the coefficients slice has
 // length less than k, or any of the given indices is the zero element.
 func ShareAndGetCoeffs(dst *Shares, coeffs, indices []secp256k1.Fn, secret secp256k1.Fn, k int) error {
-	for _, index := range indices {
-		if index.IsZero() {
-			panic("cannot create share for index zero")
-		}
+	if err := checkIndices(indices, k); err != nil {
+		return err
 	}
-	if k > len(indices) {
-		return fmt.Errorf(
-			"reconstruction threshold too large: expected k <= %v, got k = %v",
-			len(indices), k,
-		)
+	if err := setRandomCoeffs(coeffs, secret, k); err != nil {
+		return err
 	}
-	setRandomCoeffs(coeffs, secret, k)
 
 	// Set shares
 	// NOTE: This panics if the destination slice does not have the required
@@ -118,17 +124,24 @@ func ShareAndGetCoeffs(dst *Shares, coeffs, indices []secp256k1.Fn, secret secp2
 }
 
 // Sets the coefficients of the Sharer to represent a random degree k-1
-// polynomial with constant term equal to the given secret.
+// polynomial with constant term equal to the given secret. The random
+// coefficients are read from the source set with SetRandomSource, and an
+// error is returned if reading from it fails.
 //
 // Panics: This function will panic if k is greater than len(coeffs).
-func setRandomCoeffs(coeffs []secp256k1.Fn, secret secp256k1.Fn, k int) {
+func setRandomCoeffs(coeffs []secp256k1.Fn, secret secp256k1.Fn, k int) error {
 	coeffs = coeffs[:k]
 	coeffs[0] = secret
 
 	// NOTE: If k > len(coeffs), then this will panic when i > len(coeffs).
+	r := RandomSource()
 	for i := 1; i < k; i++ {
-		coeffs[i] = secp256k1.RandomFn()
+		var err error
+		if coeffs[i], err = randomFn(r); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // Evaluates the polynomial defined by the given coefficients at the point x
@@ -141,11 +154,8 @@ func setRandomCoeffs(coeffs []secp256k1.Fn, secret secp256k1.Fn, k int) {
 // coeffs is the empty (or nil) slice.
 func polyEval(y, x *secp256k1.Fn, coeffs []secp256k1.Fn) {
 	// NOTE: This will panic if len(coeffs) is less than 1 or if coeffs is nil.
-	*y = coeffs[len(coeffs)-1]
-	for i := len(coeffs) - 2; i >= 0; i-- {
-		y.Mul(y, x)
-		y.Add(y, &coeffs[i])
-	}
+	_ = coeffs[len(coeffs)-1]
+	*y = fnbatch.Eval(coeffs, x)
 }
 
 // Open computes the secret corresponding to the given shares. This is
@@ -157,24 +167,7 @@ func polyEval(y, x *secp256k1.Fn, coeffs []secp256k1.Fn) {
 //	- All shares are valid, in the sense that they have not been maliciously
 //		modified.
 func Open(shares Shares) secp256k1.Fn {
-	var num, denom, res, tmp secp256k1.Fn
-	res.SetU16(0)
-	for i := range shares {
-		num.SetU16(1)
-		denom.SetU16(1)
-		for j := range shares {
-			if shares[i].Index.Eq(&shares[j].Index) {
-				continue
-			}
-			tmp.Negate(&shares[i].Index)
-			tmp.Add(&tmp, &shares[j].Index)
-			denom.Mul(&denom, &tmp)
-			num.Mul(&num, &shares[j].Index)
-		}
-		denom.Inverse(&denom)
-		tmp.Mul(&num, &denom)
-		tmp.Mul(&tmp, &shares[i].Value)
-		res.Add(&res, &tmp)
-	}
-	return res
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	return OpenWithScratch(shares, s)
 }
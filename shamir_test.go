@@ -1,8 +1,8 @@
 package shamir_test
 
 import (
+	"errors"
 	"math/rand"
-	"testing"
 	"time"
 
 	"github.com/renproject/secp256k1"
@@ -318,6 +318,37 @@ var _ = Describe("Shamir Secret Sharing", func() {
 				err := ShareSecret(&shares, indices, secret, k)
 
 				Expect(err).To(HaveOccurred())
+				var kErr *ErrKTooLarge
+				Expect(errors.As(err, &kErr)).To(BeTrue())
+				Expect(*kErr).To(Equal(ErrKTooLarge{K: k, N: n}))
+				Expect(errors.Is(err, ErrInvalidThreshold)).To(BeTrue())
+			}
+		})
+
+		It("should return an error when k is not positive", func() {
+			shares := make(Shares, n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			h := secp256k1.RandomPoint()
+			gc := NewGroupCommitment(Secp256k1Group)
+			var scratch Scratch
+
+			for _, k := range []int{0, -1} {
+				secret := secp256k1.RandomFn()
+				errs := []error{
+					ShareSecret(&shares, indices, secret, k),
+					ShareSecretWithScratch(&shares, indices, secret, k, &scratch),
+					ShareAndGetCoeffs(&shares, nil, indices, secret, k),
+					VShareSecret(&vshares, &c, indices, h, secret, k),
+					VShareSecretWithScratch(&vshares, &c, indices, h, secret, k, &scratch),
+					VShareSecretInGroup(&vshares, &gc, indices, Secp256k1Group.NewElement(), secret, k),
+					ShareSecretChunked(indices, secret, k, n, func(Shares) error { return nil }),
+				}
+				for _, err := range errs {
+					Expect(errors.Is(err, ErrInvalidThreshold)).To(BeTrue())
+					var kErr *ErrKTooLarge
+					Expect(errors.As(err, &kErr)).To(BeFalse())
+				}
 			}
 		})
 
@@ -354,33 +385,3 @@ var _ = Describe("Shamir Secret Sharing", func() {
 		})
 	})
 })
-
-func BenchmarkShare(b *testing.B) {
-	n := 100
-	k := 33
-
-	indices := RandomIndices(n)
-	shares := make(Shares, n)
-	secret := secp256k1.RandomFn()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = ShareSecret(&shares, indices, secret, k)
-	}
-}
-
-func BenchmarkOpen(b *testing.B) {
-	n := 100
-	k := 33
-
-	indices := RandomIndices(n)
-	shares := make(Shares, n)
-	secret := secp256k1.RandomFn()
-	_ = ShareSecret(&shares, indices, secret, k)
-	Shuffle(shares)
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = Open(shares[:k])
-	}
-}
@@ -0,0 +1,427 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        (unknown)
+// source: shamir.proto
+
+package shamirpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Share is a single Shamir share. Both fields are 32 byte big endian encodings
+// of elements of the secp256k1 scalar field.
+type Share struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index []byte `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Share) Reset() {
+	*x = Share{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shamir_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Share) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Share) ProtoMessage() {}
+
+func (x *Share) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Share.ProtoReflect.Descriptor instead.
+func (*Share) Descriptor() ([]byte, []int) {
+	return file_shamir_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Share) GetIndex() []byte {
+	if x != nil {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *Share) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// VerifiableShare is a Share together with its Pedersen decommitment value,
+// which is also a 32 byte big endian scalar.
+type VerifiableShare struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Share        *Share `protobuf:"bytes,1,opt,name=share,proto3" json:"share,omitempty"`
+	Decommitment []byte `protobuf:"bytes,2,opt,name=decommitment,proto3" json:"decommitment,omitempty"`
+}
+
+func (x *VerifiableShare) Reset() {
+	*x = VerifiableShare{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shamir_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifiableShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifiableShare) ProtoMessage() {}
+
+func (x *VerifiableShare) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifiableShare.ProtoReflect.Descriptor instead.
+func (*VerifiableShare) Descriptor() ([]byte, []int) {
+	return file_shamir_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifiableShare) GetShare() *Share {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+func (x *VerifiableShare) GetDecommitment() []byte {
+	if x != nil {
+		return x.Decommitment
+	}
+	return nil
+}
+
+// Commitment is a Pedersen commitment to a sharing. Each point is a 33 byte
+// encoding of a secp256k1 curve point, in the same format used by surge.
+type Commitment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Points [][]byte `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *Commitment) Reset() {
+	*x = Commitment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shamir_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Commitment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Commitment) ProtoMessage() {}
+
+func (x *Commitment) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Commitment.ProtoReflect.Descriptor instead.
+func (*Commitment) Descriptor() ([]byte, []int) {
+	return file_shamir_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Commitment) GetPoints() [][]byte {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// Shares is a list of Shamir shares.
+type Shares struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shares []*Share `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
+}
+
+func (x *Shares) Reset() {
+	*x = Shares{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shamir_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Shares) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shares) ProtoMessage() {}
+
+func (x *Shares) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shares.ProtoReflect.Descriptor instead.
+func (*Shares) Descriptor() ([]byte, []int) {
+	return file_shamir_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Shares) GetShares() []*Share {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+// VerifiableShares is a list of verifiable shares.
+type VerifiableShares struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shares []*VerifiableShare `protobuf:"bytes,1,rep,name=shares,proto3" json:"shares,omitempty"`
+}
+
+func (x *VerifiableShares) Reset() {
+	*x = VerifiableShares{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shamir_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifiableShares) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifiableShares) ProtoMessage() {}
+
+func (x *VerifiableShares) ProtoReflect() protoreflect.Message {
+	mi := &file_shamir_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifiableShares.ProtoReflect.Descriptor instead.
+func (*VerifiableShares) Descriptor() ([]byte, []int) {
+	return file_shamir_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifiableShares) GetShares() []*VerifiableShare {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+var File_shamir_proto protoreflect.FileDescriptor
+
+var file_shamir_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x68, 0x61, 0x6d, 0x69, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x73, 0x68, 0x61, 0x6d, 0x69, 0x72, 0x22, 0x33, 0x0a, 0x05, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x5a, 0x0a, 0x0f, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x23,
+	0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x73, 0x68, 0x61, 0x6d, 0x69, 0x72, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x05, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x24, 0x0a, 0x0a, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x22, 0x2f, 0x0a,
+	0x06, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x68, 0x61, 0x6d, 0x69, 0x72,
+	0x2e, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22, 0x43,
+	0x0a, 0x10, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x68, 0x61, 0x6d, 0x69, 0x72, 0x2e, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x06, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x73, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x72, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x73, 0x68, 0x61,
+	0x6d, 0x69, 0x72, 0x2f, 0x73, 0x68, 0x61, 0x6d, 0x69, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_shamir_proto_rawDescOnce sync.Once
+	file_shamir_proto_rawDescData = file_shamir_proto_rawDesc
+)
+
+func file_shamir_proto_rawDescGZIP() []byte {
+	file_shamir_proto_rawDescOnce.Do(func() {
+		file_shamir_proto_rawDescData = protoimpl.X.CompressGZIP(file_shamir_proto_rawDescData)
+	})
+	return file_shamir_proto_rawDescData
+}
+
+var file_shamir_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_shamir_proto_goTypes = []interface{}{
+	(*Share)(nil),            // 0: shamir.Share
+	(*VerifiableShare)(nil),  // 1: shamir.VerifiableShare
+	(*Commitment)(nil),       // 2: shamir.Commitment
+	(*Shares)(nil),           // 3: shamir.Shares
+	(*VerifiableShares)(nil), // 4: shamir.VerifiableShares
+}
+var file_shamir_proto_depIdxs = []int32{
+	0, // 0: shamir.VerifiableShare.share:type_name -> shamir.Share
+	0, // 1: shamir.Shares.shares:type_name -> shamir.Share
+	1, // 2: shamir.VerifiableShares.shares:type_name -> shamir.VerifiableShare
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_shamir_proto_init() }
+func file_shamir_proto_init() {
+	if File_shamir_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_shamir_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Share); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shamir_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifiableShare); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shamir_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Commitment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shamir_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Shares); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shamir_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifiableShares); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_shamir_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_shamir_proto_goTypes,
+		DependencyIndexes: file_shamir_proto_depIdxs,
+		MessageInfos:      file_shamir_proto_msgTypes,
+	}.Build()
+	File_shamir_proto = out.File
+	file_shamir_proto_rawDesc = nil
+	file_shamir_proto_goTypes = nil
+	file_shamir_proto_depIdxs = nil
+}
@@ -0,0 +1,168 @@
+// Package shamirpb provides protobuf messages for the types in the shamir
+// package, along with converters to and from the native Go types. The schema
+// is defined in shamir.proto, and the messages are generated from it with
+// protoc-gen-go, so they implement proto.Message and can be used directly in
+// gRPC services and in other messages that import the schema.
+//
+// The String methods of the generated messages print every field, including
+// the values and decommitments of shares, so messages that hold shares must
+// not be logged.
+package shamirpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative shamir.proto
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// FromShare converts the given share into its protobuf representation.
+func FromShare(share *shamir.Share) *Share {
+	return &Share{
+		Index: fnBytes(&share.Index),
+		Value: fnBytes(&share.Value),
+	}
+}
+
+// ToShare converts the protobuf message into a shamir.Share. An error is
+// returned if either field is not a valid scalar encoding.
+func (s *Share) ToShare() (shamir.Share, error) {
+	var share shamir.Share
+	if err := setFn(&share.Index, s.Index); err != nil {
+		return shamir.Share{}, fmt.Errorf("invalid index: %v", err)
+	}
+	if err := setFn(&share.Value, s.Value); err != nil {
+		return shamir.Share{}, fmt.Errorf("invalid value: %v", err)
+	}
+	return share, nil
+}
+
+// FromVerifiableShare converts the given verifiable share into its protobuf
+// representation.
+func FromVerifiableShare(vshare *shamir.VerifiableShare) *VerifiableShare {
+	return &VerifiableShare{
+		Share:        FromShare(&vshare.Share),
+		Decommitment: fnBytes(&vshare.Decommitment),
+	}
+}
+
+// ToVerifiableShare converts the protobuf message into a
+// shamir.VerifiableShare. An error is returned if the share is missing or any
+// field is not a valid scalar encoding.
+func (vs *VerifiableShare) ToVerifiableShare() (shamir.VerifiableShare, error) {
+	if vs.Share == nil {
+		return shamir.VerifiableShare{}, fmt.Errorf("missing share")
+	}
+	share, err := vs.Share.ToShare()
+	if err != nil {
+		return shamir.VerifiableShare{}, err
+	}
+	var decom secp256k1.Fn
+	if err := setFn(&decom, vs.Decommitment); err != nil {
+		return shamir.VerifiableShare{}, fmt.Errorf("invalid decommitment: %v", err)
+	}
+	return shamir.NewVerifiableShare(share, decom), nil
+}
+
+// FromCommitment converts the given commitment into its protobuf
+// representation.
+func FromCommitment(c shamir.Commitment) *Commitment {
+	points := make([][]byte, len(c))
+	for i := range c {
+		points[i] = make([]byte, secp256k1.PointSizeMarshalled)
+		c[i].PutBytes(points[i])
+	}
+	return &Commitment{Points: points}
+}
+
+// ToCommitment converts the protobuf message into a shamir.Commitment. An
+// error is returned if any of the points is not a valid curve point encoding.
+func (c *Commitment) ToCommitment() (shamir.Commitment, error) {
+	com := make(shamir.Commitment, len(c.Points))
+	for i, bs := range c.Points {
+		if len(bs) != secp256k1.PointSizeMarshalled {
+			return nil, fmt.Errorf(
+				"invalid point %v: expected %v bytes, got %v",
+				i, secp256k1.PointSizeMarshalled, len(bs),
+			)
+		}
+		if err := com[i].SetBytes(bs); err != nil {
+			return nil, fmt.Errorf("invalid point %v: %v", i, err)
+		}
+	}
+	return com, nil
+}
+
+// FromShares converts the given shares into their protobuf representation.
+func FromShares(shares shamir.Shares) *Shares {
+	msg := &Shares{Shares: make([]*Share, len(shares))}
+	for i := range shares {
+		msg.Shares[i] = FromShare(&shares[i])
+	}
+	return msg
+}
+
+// ToShares converts the protobuf message into shamir.Shares.
+func (s *Shares) ToShares() (shamir.Shares, error) {
+	shares := make(shamir.Shares, len(s.Shares))
+	for i, share := range s.Shares {
+		if share == nil {
+			return nil, fmt.Errorf("missing share %v", i)
+		}
+		var err error
+		if shares[i], err = share.ToShare(); err != nil {
+			return nil, fmt.Errorf("share %v: %v", i, err)
+		}
+	}
+	return shares, nil
+}
+
+// FromVerifiableShares converts the given verifiable shares into their
+// protobuf representation.
+func FromVerifiableShares(vshares shamir.VerifiableShares) *VerifiableShares {
+	msg := &VerifiableShares{Shares: make([]*VerifiableShare, len(vshares))}
+	for i := range vshares {
+		msg.Shares[i] = FromVerifiableShare(&vshares[i])
+	}
+	return msg
+}
+
+// ToVerifiableShares converts the protobuf message into
+// shamir.VerifiableShares.
+func (vs *VerifiableShares) ToVerifiableShares() (shamir.VerifiableShares, error) {
+	vshares := make(shamir.VerifiableShares, len(vs.Shares))
+	for i, vshare := range vs.Shares {
+		if vshare == nil {
+			return nil, fmt.Errorf("missing share %v", i)
+		}
+		var err error
+		if vshares[i], err = vshare.ToVerifiableShare(); err != nil {
+			return nil, fmt.Errorf("share %v: %v", i, err)
+		}
+	}
+	return vshares, nil
+}
+
+func fnBytes(x *secp256k1.Fn) []byte {
+	bs := make([]byte, secp256k1.FnSizeMarshalled)
+	x.PutB32(bs)
+	return bs
+}
+
+// Sets the scalar from its 32 byte encoding. Proto3 omits empty fields, so a
+// missing field is interpreted as the zero scalar.
+func setFn(x *secp256k1.Fn, bs []byte) error {
+	if len(bs) == 0 {
+		x.Clear()
+		return nil
+	}
+	if len(bs) != secp256k1.FnSizeMarshalled {
+		return fmt.Errorf("expected %v bytes, got %v", secp256k1.FnSizeMarshalled, len(bs))
+	}
+	if x.SetB32(bs) {
+		return fmt.Errorf("scalar overflows the field")
+	}
+	return nil
+}
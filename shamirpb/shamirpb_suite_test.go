@@ -0,0 +1,13 @@
+package shamirpb_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestShamirpb(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shamirpb Suite")
+}
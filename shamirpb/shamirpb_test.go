@@ -0,0 +1,175 @@
+package shamirpb_test
+
+import (
+	"bytes"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/shamirpb"
+)
+
+var _ = Describe("Protobuf messages", func() {
+	trials := 20
+	n, k := 10, 4
+	h := secp256k1.RandomPoint()
+
+	randomDealing := func() (shamir.VerifiableShares, shamir.Commitment) {
+		indices := shamirutil.RandomIndices(n)
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		Expect(shamir.VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares, c
+	}
+
+	Context("converting to and from the native types", func() {
+		It("should round trip shares through the wire format", func() {
+			for i := 0; i < trials; i++ {
+				vshares, _ := randomDealing()
+				shares := vshares.Shares()
+
+				bs, err := proto.Marshal(FromShares(shares))
+				Expect(err).ToNot(HaveOccurred())
+				var msg Shares
+				Expect(proto.Unmarshal(bs, &msg)).To(Succeed())
+				decoded, err := msg.ToShares()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(decoded).To(HaveLen(len(shares)))
+				for j := range shares {
+					Expect(decoded[j].Eq(&shares[j])).To(BeTrue())
+				}
+			}
+		})
+
+		It("should round trip verifiable shares through the wire format", func() {
+			for i := 0; i < trials; i++ {
+				vshares, _ := randomDealing()
+
+				bs, err := proto.Marshal(FromVerifiableShares(vshares))
+				Expect(err).ToNot(HaveOccurred())
+				var msg VerifiableShares
+				Expect(proto.Unmarshal(bs, &msg)).To(Succeed())
+				decoded, err := msg.ToVerifiableShares()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(decoded).To(HaveLen(len(vshares)))
+				for j := range vshares {
+					Expect(decoded[j].Eq(&vshares[j])).To(BeTrue())
+				}
+			}
+		})
+
+		It("should round trip commitments through the wire format", func() {
+			for i := 0; i < trials; i++ {
+				vshares, c := randomDealing()
+
+				bs, err := proto.Marshal(FromCommitment(c))
+				Expect(err).ToNot(HaveOccurred())
+				var msg Commitment
+				Expect(proto.Unmarshal(bs, &msg)).To(Succeed())
+				decoded, err := msg.ToCommitment()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(decoded.Eq(c)).To(BeTrue())
+				for _, vshare := range vshares {
+					Expect(shamir.IsValid(h, &decoded, &vshare)).To(BeTrue())
+				}
+			}
+		})
+	})
+
+	Context("generated code", func() {
+		It("should implement proto.Message with the names from the schema", func() {
+			msgs := map[string]proto.Message{
+				"shamir.Share":            &Share{},
+				"shamir.VerifiableShare":  &VerifiableShare{},
+				"shamir.Commitment":       &Commitment{},
+				"shamir.Shares":           &Shares{},
+				"shamir.VerifiableShares": &VerifiableShares{},
+			}
+			for name, msg := range msgs {
+				desc := msg.ProtoReflect().Descriptor()
+				Expect(string(desc.FullName())).To(Equal(name))
+				Expect(desc.ParentFile().Path()).To(Equal("shamir.proto"))
+			}
+		})
+
+		It("should round trip messages that embed shares", func() {
+			vshares, _ := randomDealing()
+			msg := FromVerifiableShare(&vshares[0])
+			cloned := proto.Clone(msg).(*VerifiableShare)
+			Expect(proto.Equal(msg, cloned)).To(BeTrue())
+
+			decoded, err := cloned.ToVerifiableShare()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.Eq(&vshares[0])).To(BeTrue())
+		})
+	})
+
+	Context("wire compatibility", func() {
+		It("should produce the canonical protobuf encoding of a share", func() {
+			index := secp256k1.NewFnFromU16(1)
+			value := secp256k1.NewFnFromU16(2)
+			bs, err := proto.Marshal(FromShare(&shamir.Share{Index: index, Value: value}))
+			Expect(err).ToNot(HaveOccurred())
+
+			expected := []byte{0x0a, 0x20}
+			expected = append(expected, make([]byte, 31)...)
+			expected = append(expected, 0x01, 0x12, 0x20)
+			expected = append(expected, make([]byte, 31)...)
+			expected = append(expected, 0x02)
+			Expect(bytes.Equal(bs, expected)).To(BeTrue())
+		})
+
+		It("should skip unknown fields", func() {
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			bs, err := proto.Marshal(FromShare(&share))
+			Expect(err).ToNot(HaveOccurred())
+
+			// Field 3 as a varint, field 4 as a fixed32 and field 5 as bytes.
+			bs = append(bs, 0x18, 0x96, 0x01)
+			bs = append(bs, 0x25, 0x01, 0x02, 0x03, 0x04)
+			bs = append(bs, 0x2a, 0x02, 0xff, 0xff)
+
+			var msg Share
+			Expect(proto.Unmarshal(bs, &msg)).To(Succeed())
+			decoded, err := msg.ToShare()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.Eq(&share)).To(BeTrue())
+		})
+
+		It("should return an error for truncated messages", func() {
+			share := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+			bs, err := proto.Marshal(FromShare(&share))
+			Expect(err).ToNot(HaveOccurred())
+
+			for i := 1; i < len(bs); i++ {
+				if i == 34 {
+					// The first field is complete at this length.
+					continue
+				}
+				var msg Share
+				Expect(proto.Unmarshal(bs[:i], &msg)).ToNot(Succeed())
+			}
+		})
+
+		It("should return an error for invalid field lengths", func() {
+			msg := Share{Index: []byte{1, 2, 3}}
+			_, err := msg.ToShare()
+			Expect(err).To(HaveOccurred())
+
+			com := Commitment{Points: [][]byte{{1, 2, 3}}}
+			_, err = com.ToCommitment()
+			Expect(err).To(HaveOccurred())
+
+			vmsg := VerifiableShare{}
+			_, err = vmsg.ToVerifiableShare()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,162 @@
+package shamirutil
+
+import (
+	"errors"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/rs"
+)
+
+// ErrTooManyInconsistent is returned by InconsistentIndices when the shares
+// are not consistent, but there are too many inconsistent shares to determine
+// which ones they are.
+var ErrTooManyInconsistent = errors.New("too many inconsistent shares to locate")
+
+// An InconsistencyReport is evidence that some of the shares given to
+// OpenRobust or InconsistentIndices do not lie on the polynomial that the
+// others agree on. It is meant for higher layers that need to blame or slash
+// the parties that contributed those shares: anyone holding the same shares
+// can check the report by interpolating the polynomial from the shares that
+// are not listed, and evaluating it at the listed indices.
+type InconsistencyReport struct {
+	// Threshold is the threshold k that the shares were decoded with.
+	Threshold int
+	// Decoded is true if Reed-Solomon decoding found the polynomial of degree
+	// less than k that agrees with all but at most (n - k)/2 of the n shares.
+	// Otherwise there were too many inconsistent shares to locate them, and
+	// the remaining fields are empty.
+	Decoded bool
+	// Positions are the positions in the given slice of the shares that
+	// conflict with the polynomial, in increasing order.
+	Positions []int
+	// Indices, Received and Expected are, for each conflicting share, its
+	// index, its value, and the value of the polynomial at its index.
+	Indices  []secp256k1.Fn
+	Received []secp256k1.Fn
+	Expected []secp256k1.Fn
+}
+
+// OpenRobust reconstructs the secret from shares of which some may be wrong.
+// If the shares all lie on a single polynomial of degree less than k, the
+// secret is returned with a nil report. Otherwise the shares are Reed-Solomon
+// decoded, and if that succeeds the secret is the constant term of the
+// decoded polynomial and the report identifies the shares that conflict with
+// it. If decoding fails, ErrTooManyInconsistent is returned, together with a
+// report whose Decoded field is false. Up to (n - k)/2 wrong shares can be
+// corrected, and with n <= k shares nothing can be detected, so the secret is
+// then the result of Open. An error is also returned if k is not positive or
+// if two of the shares have the same index.
+func OpenRobust(shares shamir.Shares, k int) (secp256k1.Fn, *InconsistencyReport, error) {
+	var secret secp256k1.Fn
+	report, err := checkConsistency(shares, k, &secret)
+	return secret, report, err
+}
+
+// InconsistentIndices checks whether all of the given shares lie on a single
+// polynomial of degree less than k, and returns the indices of the shares that
+// do not. If the shares are consistent, the returned slice is nil. Otherwise
+// the shares are Reed-Solomon decoded, which finds the polynomial that agrees
+// with all but at most (n - k)/2 of the n shares, and the indices of the shares
+// that disagree with it are returned. If there is no such polynomial,
+// ErrTooManyInconsistent is returned. OpenRobust returns more detailed
+// evidence.
+//
+// Unlike SharesAreConsistent, every share is checked against the same
+// polynomial, so this function is suitable for deciding which parties to
+// blame, for example in complaint rounds. An error is also returned if k is
+// not positive or if two of the shares have the same index.
+func InconsistentIndices(shares shamir.Shares, k int) ([]secp256k1.Fn, error) {
+	report, err := checkConsistency(shares, k, nil)
+	if err != nil || report == nil {
+		return nil, err
+	}
+	return report.Indices, nil
+}
+
+// Checks the consistency of the shares as for OpenRobust, and stores the
+// secret in secret if it is not nil.
+func checkConsistency(shares shamir.Shares, k int, secret *secp256k1.Fn) (*InconsistencyReport, error) {
+	if k < 1 {
+		return nil, errors.New("threshold must be positive")
+	}
+	indices := make([]secp256k1.Fn, len(shares))
+	values := make([]secp256k1.Fn, len(shares))
+	defer shamir.WipeFns(values)
+	for i := range shares {
+		for j := 0; j < i; j++ {
+			if shares[i].IndexEq(&shares[j].Index) {
+				return nil, errors.New("shares have duplicate indices")
+			}
+		}
+		indices[i] = shares[i].Index
+		values[i] = shares[i].Value
+	}
+	if len(shares) <= k {
+		if secret != nil && len(shares) > 0 {
+			*secret = shamir.Open(shares)
+		}
+		return nil, nil
+	}
+
+	// Interpolate the first k shares and check the residuals of the rest,
+	// which is cheaper than decoding when the shares are consistent.
+	interpolator := poly.NewInterpolator(indices[:k])
+	p := poly.NewWithCapacity(k)
+	defer p.Zero()
+	interpolator.Interpolate(values[:k], &p)
+	consistent := true
+	for i := k; i < len(shares); i++ {
+		if y := p.Evaluate(indices[i]); !y.Eq(&values[i]) {
+			consistent = false
+			break
+		}
+	}
+	if consistent {
+		if secret != nil {
+			*secret = *p.Coefficient(0)
+		}
+		return nil, nil
+	}
+
+	report := &InconsistencyReport{Threshold: k}
+	decoder := rs.NewDecoder(indices, k)
+	f, ok := decoder.Decode(values)
+	if !ok {
+		return report, ErrTooManyInconsistent
+	}
+	defer f.Zero()
+	errs := decoder.ErrorIndices()
+	if len(errs) == 0 {
+		return report, ErrTooManyInconsistent
+	}
+	report.Decoded = true
+	for i := range indices {
+		for j := range errs {
+			if indices[i].Eq(&errs[j]) {
+				report.Positions = append(report.Positions, i)
+				report.Indices = append(report.Indices, indices[i])
+				report.Received = append(report.Received, values[i])
+				report.Expected = append(report.Expected, f.Evaluate(indices[i]))
+				break
+			}
+		}
+	}
+	if secret != nil {
+		*secret = *f.Coefficient(0)
+	}
+	return report, nil
+}
+
+// OpenRobustVShares is a wrapper around OpenRobust for the VerifiableShares
+// type.
+func OpenRobustVShares(vshares shamir.VerifiableShares, k int) (secp256k1.Fn, *InconsistencyReport, error) {
+	return OpenRobust(vshares.Shares(), k)
+}
+
+// InconsistentVShareIndices is a wrapper around InconsistentIndices for the
+// VerifiableShares type.
+func InconsistentVShareIndices(vshares shamir.VerifiableShares, k int) ([]secp256k1.Fn, error) {
+	return InconsistentIndices(vshares.Shares(), k)
+}
@@ -0,0 +1,119 @@
+package shamirutil
+
+import (
+	"io"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// Malicious dealers
+//
+// The functions in this file construct dealings that deviate from the
+// protocol in specific ways, so that higher level protocols such as DKGs can
+// test their complaint and disqualification paths deterministically. Each
+// returns a shamir.Dealing that is honest in every respect except the one that
+// is described.
+
+// DealInconsistentShare returns a dealing of the given secret in which the
+// share at the given position is not valid with regard to the commitment,
+// and every other share is valid. This is the dealer that a justified
+// complaint is raised against.
+//
+// Panics: This function will panic if the position is out of range, or under
+// the same conditions as shamir.VShareSecret.
+func DealInconsistentShare(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k, position int,
+) (shamir.Dealing, error) {
+	d, err := shamir.Deal(indices, h, secret, k)
+	if err != nil {
+		return shamir.Dealing{}, err
+	}
+	// Adding one keeps the share invalid, where a random value could be
+	// valid with negligible probability.
+	one := secp256k1.NewFnFromU16(1)
+	d.Shares[position].Share.Value.Add(&d.Shares[position].Share.Value, &one)
+	return d, nil
+}
+
+// DealWrongDegree returns a dealing of the given secret that is a valid
+// sharing with threshold actualK rather than the agreed threshold k, so that
+// every share is valid but the commitment has the wrong length. If actualK is
+// greater than k, any k shares are consistent with more than one secret; if it
+// is less, fewer than k shares are enough to open the secret.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret with threshold actualK.
+func DealWrongDegree(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	actualK int,
+) (shamir.Dealing, error) {
+	return shamir.Deal(indices, h, secret, actualK)
+}
+
+// DealBiased returns a dealing of the given secret whose polynomials are
+// drawn from a deterministic source seeded with the given seed, rather than
+// from a secure source. Dealings with the same seed, indices and secret are
+// identical, which models a dealer that reuses or predicts its randomness.
+// All of the shares are valid.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func DealBiased(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	seed int64,
+) (shamir.Dealing, error) {
+	return dealFromSource(indices, h, secret, k, rand.New(rand.NewSource(seed)))
+}
+
+// DealDegenerate returns a dealing of the given secret in which every random
+// coefficient is zero, as for a dealer whose source of randomness returns only
+// zeros. Every share is valid and has the secret as its value, and every point
+// of the commitment but the first is the point at infinity.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func DealDegenerate(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+) (shamir.Dealing, error) {
+	return dealFromSource(indices, h, secret, k, zeroReader{})
+}
+
+func dealFromSource(
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	r io.Reader,
+) (shamir.Dealing, error) {
+	d := shamir.Dealing{
+		Commitment: shamir.NewCommitmentWithCapacity(k),
+		Shares:     make(shamir.VerifiableShares, len(indices)),
+	}
+	err := shamir.VShareSecret(&d.Shares, &d.Commitment, indices, h, secret, k, shamir.WithRandomSource(r))
+	if err != nil {
+		return shamir.Dealing{}, err
+	}
+	return d, nil
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(bs []byte) (int, error) {
+	for i := range bs {
+		bs[i] = 0
+	}
+	return len(bs), nil
+}
@@ -0,0 +1,220 @@
+package shamirutil
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/rs"
+	"github.com/renproject/surge"
+)
+
+// The maximum number of shares that the fuzzing helpers will construct.
+const fuzzMaxN = 16
+
+// OpenDifferential reconstructs the secret from the given shares using
+// shamir.Open, a poly.Interpolator and an rs.Decoder, and returns an error if
+// the three results do not agree. The shares must have distinct indices and lie
+// on a polynomial of degree less than k, where k is at most the number of
+// shares.
+func OpenDifferential(shares shamir.Shares, k int) error {
+	indices := make([]secp256k1.Fn, len(shares))
+	values := make([]secp256k1.Fn, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+		values[i] = shares[i].Value
+	}
+
+	secret := shamir.Open(shares)
+
+	interpolator := poly.NewInterpolator(indices)
+	interpolated := poly.NewWithCapacity(len(shares))
+	interpolator.Interpolate(values, &interpolated)
+	if !interpolated.Coefficient(0).Eq(&secret) {
+		return fmt.Errorf("open gave %v but interpolation gave %v", secret.Int(), interpolated.Coefficient(0).Int())
+	}
+
+	decoder := rs.NewDecoder(indices, k)
+	decoded, ok := decoder.Decode(values)
+	if !ok {
+		return fmt.Errorf("decoding failed for %v shares with threshold %v", len(shares), k)
+	}
+	if !decoded.Coefficient(0).Eq(&secret) {
+		return fmt.Errorf("open gave %v but decoding gave %v", secret.Int(), decoded.Coefficient(0).Int())
+	}
+	return nil
+}
+
+// CheckOpen uses the given bytes to construct a polynomial and a set of shares
+// of it, and checks that the secret is reconstructed correctly by
+// OpenDifferential. It returns an error if the check fails, and nil if it
+// passes or if the bytes do not describe a valid sharing. It is intended to be
+// called from a fuzzing entry point.
+func CheckOpen(data []byte) error {
+	r := fuzzReader(data)
+	n, k := r.threshold()
+	indices, ok := r.indices(n)
+	if !ok {
+		return nil
+	}
+	p := r.poly(k)
+
+	shares := make(shamir.Shares, n)
+	for i := range shares {
+		shares[i] = shamir.NewShare(indices[i], p.Evaluate(indices[i]))
+	}
+	if secret := shamir.Open(shares); !secret.Eq(p.Coefficient(0)) {
+		return fmt.Errorf("open gave %v but the secret is %v", secret.Int(), p.Coefficient(0).Int())
+	}
+	return OpenDifferential(shares, k)
+}
+
+// CheckDecode uses the given bytes to construct a codeword for an
+// rs.Decoder with a number of errors that the decoder can correct, and checks
+// that decoding recovers the polynomial and locates the errors. It returns an
+// error if the check fails, and nil if it passes or if the bytes do not
+// describe a valid codeword. It is intended to be called from a fuzzing entry
+// point.
+func CheckDecode(data []byte) error {
+	r := fuzzReader(data)
+	n, k := r.threshold()
+	indices, ok := r.indices(n)
+	if !ok {
+		return nil
+	}
+	p := r.poly(k)
+
+	values := make([]secp256k1.Fn, n)
+	for i := range values {
+		values[i] = p.Evaluate(indices[i])
+	}
+
+	corrupted := make([]bool, n)
+	numErrors := 0
+	for e := int(r.byte()) % ((n-k)/2 + 1); e > 0; e-- {
+		i := int(r.byte()) % n
+		if corrupted[i] {
+			continue
+		}
+		delta := r.fn()
+		if delta.IsZero() {
+			delta.SetU16(1)
+		}
+		values[i].Add(&values[i], &delta)
+		corrupted[i] = true
+		numErrors++
+	}
+
+	decoder := rs.NewDecoder(indices, k)
+	decoded, ok := decoder.Decode(values)
+	if !ok {
+		return fmt.Errorf("decoding failed with %v errors for n = %v and k = %v", numErrors, n, k)
+	}
+	if !decoded.Eq(p) {
+		return fmt.Errorf("decoding gave %v but the polynomial is %v", decoded, p)
+	}
+	errs := decoder.ErrorIndices()
+	if len(errs) != numErrors {
+		return fmt.Errorf("decoder located %v errors but there were %v", len(errs), numErrors)
+	}
+	for _, index := range errs {
+		located := false
+		for i := range indices {
+			if corrupted[i] && indices[i].Eq(&index) {
+				located = true
+			}
+		}
+		if !located {
+			return fmt.Errorf("decoder located an error at %v which is not corrupted", index.Int())
+		}
+	}
+	return nil
+}
+
+// CheckUnmarshalVerifiableShares unmarshals the given bytes as
+// shamir.VerifiableShares, and checks that if unmarshalling succeeds then
+// marshalling and unmarshalling again gives the same shares. It returns an
+// error if the check fails. It is intended to be called from a fuzzing entry
+// point, which will also catch any panics.
+func CheckUnmarshalVerifiableShares(data []byte) error {
+	var vshares shamir.VerifiableShares
+	if _, _, err := vshares.Unmarshal(data, surge.MaxBytes); err != nil {
+		return nil
+	}
+	bs, err := surge.ToBinary(vshares)
+	if err != nil {
+		return fmt.Errorf("marshalling unmarshalled shares: %v", err)
+	}
+	if !bytes.Equal(bs[:surge.SizeHintU32], data[:surge.SizeHintU32]) {
+		return fmt.Errorf("marshalled length prefix %x differs from %x", bs[:surge.SizeHintU32], data[:surge.SizeHintU32])
+	}
+	var again shamir.VerifiableShares
+	if err := surge.FromBinary(&again, bs); err != nil {
+		return fmt.Errorf("unmarshalling marshalled shares: %v", err)
+	}
+	if len(again) != len(vshares) {
+		return fmt.Errorf("unmarshalled %v shares but expected %v", len(again), len(vshares))
+	}
+	for i := range vshares {
+		if !again[i].Eq(&vshares[i]) {
+			return fmt.Errorf("share %v changed after marshalling and unmarshalling", i)
+		}
+	}
+	return nil
+}
+
+// A fuzzReader consumes fuzzing input, and gives zero bytes once the input
+// has been exhausted.
+type fuzzReader []byte
+
+func (r *fuzzReader) byte() byte {
+	if len(*r) == 0 {
+		return 0
+	}
+	b := (*r)[0]
+	*r = (*r)[1:]
+	return b
+}
+
+func (r *fuzzReader) fn() secp256k1.Fn {
+	var bs [secp256k1.FnSizeMarshalled]byte
+	*r = (*r)[copy(bs[:], *r):]
+	var x secp256k1.Fn
+	x.SetB32(bs[:])
+	return x
+}
+
+// Returns a number of shares n and a threshold k with 1 <= k <= n.
+func (r *fuzzReader) threshold() (int, int) {
+	n := 1 + int(r.byte())%fuzzMaxN
+	k := 1 + int(r.byte())%n
+	return n, k
+}
+
+// Returns n indices, and false if they are not distinct and non zero.
+func (r *fuzzReader) indices(n int) ([]secp256k1.Fn, bool) {
+	indices := make([]secp256k1.Fn, n)
+	for i := range indices {
+		indices[i] = r.fn()
+		if indices[i].IsZero() {
+			return nil, false
+		}
+		for j := 0; j < i; j++ {
+			if indices[i].Eq(&indices[j]) {
+				return nil, false
+			}
+		}
+	}
+	return indices, true
+}
+
+// Returns a polynomial of degree less than k.
+func (r *fuzzReader) poly(k int) poly.Poly {
+	coeffs := make([]secp256k1.Fn, k)
+	for i := range coeffs {
+		coeffs[i] = r.fn()
+	}
+	return poly.NewFromSlice(coeffs)
+}
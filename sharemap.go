@@ -0,0 +1,169 @@
+package shamir
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/renproject/secp256k1"
+)
+
+// An IndexKey is the canonical 32 byte big endian encoding of a share index,
+// which can be used as a map key. Comparing keys bytewise gives the same
+// order as comparing the indices as integers.
+type IndexKey [32]byte
+
+// KeyOf returns the key for the given index.
+func KeyOf(index *secp256k1.Fn) IndexKey {
+	var key IndexKey
+	index.PutB32(key[:])
+	return key
+}
+
+// A ShareMap is a set of shares keyed by index, which is convenient when
+// shares arrive one at a time from different parties. Iteration using Keys or
+// Shares is in ascending order of index, and so is deterministic.
+type ShareMap map[IndexKey]Share
+
+// NewShareMap constructs a share map from the given shares. False is returned
+// if two of the shares have the same index.
+func NewShareMap(shares Shares) (ShareMap, bool) {
+	m := make(ShareMap, len(shares))
+	for i := range shares {
+		if !m.Insert(shares[i]) {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// Insert adds the share to the map. If the map already contains a share with
+// the same index, the map is left unchanged and false is returned.
+func (m ShareMap) Insert(share Share) bool {
+	key := KeyOf(&share.Index)
+	if _, ok := m[key]; ok {
+		return false
+	}
+	m[key] = share
+	return true
+}
+
+// Lookup returns the share with the given index, and false if there is no
+// such share.
+func (m ShareMap) Lookup(index *secp256k1.Fn) (Share, bool) {
+	share, ok := m[KeyOf(index)]
+	return share, ok
+}
+
+// Delete removes the share with the given index, if there is one.
+func (m ShareMap) Delete(index *secp256k1.Fn) {
+	delete(m, KeyOf(index))
+}
+
+// MergeAdd merges the other map into the caller. Shares with an index that is
+// in both maps are added together, as for Share.Add, and shares with an index
+// that is only in the other map are inserted.
+func (m ShareMap) MergeAdd(other ShareMap) {
+	for key, share := range other {
+		if existing, ok := m[key]; ok {
+			share.Add(&existing, &share)
+		}
+		m[key] = share
+	}
+}
+
+// Keys returns the keys of the map in ascending order.
+func (m ShareMap) Keys() []IndexKey {
+	keys := make([]IndexKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// Shares returns the shares in the map in ascending order of index.
+func (m ShareMap) Shares() Shares {
+	keys := m.Keys()
+	shares := make(Shares, len(keys))
+	for i, key := range keys {
+		shares[i] = m[key]
+	}
+	return shares
+}
+
+// A VShareMap is a set of verifiable shares keyed by index. It behaves in the
+// same way as a ShareMap.
+type VShareMap map[IndexKey]VerifiableShare
+
+// NewVShareMap constructs a verifiable share map from the given shares. False
+// is returned if two of the shares have the same index.
+func NewVShareMap(vshares VerifiableShares) (VShareMap, bool) {
+	m := make(VShareMap, len(vshares))
+	for i := range vshares {
+		if !m.Insert(vshares[i]) {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// Insert adds the share to the map. If the map already contains a share with
+// the same index, the map is left unchanged and false is returned.
+func (m VShareMap) Insert(vshare VerifiableShare) bool {
+	key := KeyOf(&vshare.Share.Index)
+	if _, ok := m[key]; ok {
+		return false
+	}
+	m[key] = vshare
+	return true
+}
+
+// Lookup returns the share with the given index, and false if there is no
+// such share.
+func (m VShareMap) Lookup(index *secp256k1.Fn) (VerifiableShare, bool) {
+	vshare, ok := m[KeyOf(index)]
+	return vshare, ok
+}
+
+// Delete removes the share with the given index, if there is one.
+func (m VShareMap) Delete(index *secp256k1.Fn) {
+	delete(m, KeyOf(index))
+}
+
+// MergeAdd merges the other map into the caller. Shares with an index that is
+// in both maps are added together, as for VerifiableShare.Add, and shares with
+// an index that is only in the other map are inserted.
+func (m VShareMap) MergeAdd(other VShareMap) {
+	for key, vshare := range other {
+		if existing, ok := m[key]; ok {
+			vshare.Add(&existing, &vshare)
+		}
+		m[key] = vshare
+	}
+}
+
+// Keys returns the keys of the map in ascending order.
+func (m VShareMap) Keys() []IndexKey {
+	keys := make([]IndexKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return keys
+}
+
+// Shares returns the shares in the map in ascending order of index.
+func (m VShareMap) Shares() VerifiableShares {
+	keys := m.Keys()
+	vshares := make(VerifiableShares, len(keys))
+	for i, key := range keys {
+		vshares[i] = m[key]
+	}
+	return vshares
+}
+
+func sortKeys(keys []IndexKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+}
@@ -0,0 +1,161 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Share maps", func() {
+	trials := 20
+	n := 20
+
+	Context("shares", func() {
+		It("should insert, look up and delete shares", func() {
+			for i := 0; i < trials; i++ {
+				shares := randomShares(n)
+				m := make(ShareMap)
+				for _, j := range rand.Perm(n) {
+					Expect(m.Insert(shares[j])).To(BeTrue())
+				}
+				Expect(m).To(HaveLen(n))
+
+				dup := NewShare(shares[0].Index, secp256k1.RandomFn())
+				Expect(m.Insert(dup)).To(BeFalse())
+				share, ok := m.Lookup(&shares[0].Index)
+				Expect(ok).To(BeTrue())
+				Expect(share.Eq(&shares[0])).To(BeTrue())
+
+				m.Delete(&shares[0].Index)
+				_, ok = m.Lookup(&shares[0].Index)
+				Expect(ok).To(BeFalse())
+				Expect(m).To(HaveLen(n - 1))
+			}
+		})
+
+		It("should iterate in ascending order of index", func() {
+			for i := 0; i < trials; i++ {
+				shares := randomShares(n)
+				m, ok := NewShareMap(shares)
+				Expect(ok).To(BeTrue())
+				sorted := m.Shares()
+				Expect(sorted).To(HaveLen(n))
+				for j := 1; j < n; j++ {
+					prev, cur := sorted[j-1].Index.Int(), sorted[j].Index.Int()
+					Expect(prev.Cmp(cur)).To(Equal(-1))
+				}
+				Expect(m.Shares()).To(Equal(sorted))
+			}
+		})
+
+		It("should not construct a map from shares with duplicate indices", func() {
+			shares := randomShares(n)
+			shares[n-1].Index = shares[0].Index
+			_, ok := NewShareMap(shares)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should add shares with the same index when merging", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				secret1, secret2 := secp256k1.RandomFn(), secp256k1.RandomFn()
+				shares1 := make(Shares, n)
+				shares2 := make(Shares, n)
+				Expect(ShareSecret(&shares1, indices, secret1, n)).To(Succeed())
+				Expect(ShareSecret(&shares2, indices, secret2, n)).To(Succeed())
+
+				// Leave one share out of the caller so that it is inserted.
+				m1, _ := NewShareMap(shares1[1:])
+				m2, _ := NewShareMap(shares2)
+				m1.MergeAdd(m2)
+				Expect(m1).To(HaveLen(n))
+
+				share, _ := m1.Lookup(&indices[0])
+				Expect(share.Eq(&shares2[0])).To(BeTrue())
+
+				m1.Delete(&indices[0])
+				m1.Insert(shares1[0])
+				m1.MergeAdd(ShareMap{KeyOf(&indices[0]): shares2[0]})
+				var expected secp256k1.Fn
+				expected.Add(&secret1, &secret2)
+				recon := Open(m1.Shares())
+				Expect(recon.Eq(&expected)).To(BeTrue())
+			}
+		})
+	})
+
+	Context("verifiable shares", func() {
+		h := PedersenH()
+
+		It("should insert, look up and delete shares", func() {
+			for i := 0; i < trials; i++ {
+				d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+				Expect(err).ToNot(HaveOccurred())
+				m := make(VShareMap)
+				for _, j := range rand.Perm(n) {
+					Expect(m.Insert(d.Shares[j])).To(BeTrue())
+				}
+				Expect(m.Insert(d.Shares[0])).To(BeFalse())
+
+				vshare, ok := m.Lookup(&d.Shares[0].Share.Index)
+				Expect(ok).To(BeTrue())
+				Expect(vshare.Eq(&d.Shares[0])).To(BeTrue())
+
+				m.Delete(&d.Shares[0].Share.Index)
+				_, ok = m.Lookup(&d.Shares[0].Share.Index)
+				Expect(ok).To(BeFalse())
+			}
+		})
+
+		It("should give valid shares in ascending order after merging", func() {
+			for i := 0; i < trials; i++ {
+				indices := RandomIndices(n)
+				d1, err := Deal(indices, h, secp256k1.RandomFn(), RandRange(1, n))
+				Expect(err).ToNot(HaveOccurred())
+				d2, err := Deal(indices, h, secp256k1.RandomFn(), RandRange(1, n))
+				Expect(err).ToNot(HaveOccurred())
+
+				m1, ok := NewVShareMap(d1.Shares)
+				Expect(ok).To(BeTrue())
+				m2, ok := NewVShareMap(d2.Shares)
+				Expect(ok).To(BeTrue())
+				m1.MergeAdd(m2)
+
+				com := NewCommitmentWithCapacity(n)
+				com.Add(d1.Commitment, d2.Commitment)
+				keys := m1.Keys()
+				vshares := m1.Shares()
+				Expect(vshares).To(HaveLen(n))
+				for j := range vshares {
+					Expect(KeyOf(&vshares[j].Share.Index)).To(Equal(keys[j]))
+					Expect(IsValid(h, &com, &vshares[j])).To(BeTrue())
+					if j > 0 {
+						prev, cur := vshares[j-1].Share.Index.Int(), vshares[j].Share.Index.Int()
+						Expect(prev.Cmp(cur)).To(Equal(-1))
+					}
+				}
+			}
+		})
+
+		It("should not construct a map from shares with duplicate indices", func() {
+			d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+			Expect(err).ToNot(HaveOccurred())
+			d.Shares[1].Share.Index = d.Shares[0].Share.Index
+			_, ok := NewVShareMap(d.Shares)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
+
+func randomShares(n int) Shares {
+	shares := make(Shares, n)
+	if err := ShareSecret(&shares, RandomIndices(n), secp256k1.RandomFn(), n); err != nil {
+		panic(err)
+	}
+	return shares
+}
@@ -0,0 +1,159 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// The number of elements that are encoded or decoded at a time when streaming.
+const streamChunkLen = 256
+
+// WriteTo implements the io.WriterTo interface. It writes the same encoding as
+// Marshal, but encodes the shares in fixed size chunks so that the whole
+// encoding never needs to be held in memory.
+func (shares Shares) WriteTo(w io.Writer) (int64, error) {
+	return writeChunked(w, len(shares), ShareSize, func(i int, buf []byte) error {
+		_, _, err := shares[i].Marshal(buf, ShareSize)
+		return err
+	})
+}
+
+// ReadFrom implements the io.ReaderFrom interface. It reads a single encoding
+// as written by WriteTo or Marshal and stores the result in the caller.
+// Unlike most implementations of io.ReaderFrom, it does not read until EOF,
+// so further values can be read from the same reader. An error is returned if
+// the encoded length would exceed surge.MaxBytes.
+func (shares *Shares) ReadFrom(r io.Reader) (int64, error) {
+	*shares = (*shares)[:0]
+	return readChunked(r, ShareSize, func(buf []byte) error {
+		var share Share
+		if _, _, err := share.Unmarshal(buf, ShareSize); err != nil {
+			return err
+		}
+		*shares = append(*shares, share)
+		return nil
+	})
+}
+
+// WriteTo implements the io.WriterTo interface. It writes the same encoding as
+// Marshal, but encodes the shares in fixed size chunks so that the whole
+// encoding never needs to be held in memory.
+func (vshares VerifiableShares) WriteTo(w io.Writer) (int64, error) {
+	return writeChunked(w, len(vshares), VShareSize, func(i int, buf []byte) error {
+		_, _, err := vshares[i].Marshal(buf, VShareSize)
+		return err
+	})
+}
+
+// ReadFrom implements the io.ReaderFrom interface. It reads a single encoding
+// as written by WriteTo or Marshal and stores the result in the caller. It
+// does not read until EOF, and returns an error if the encoded length would
+// exceed surge.MaxBytes.
+func (vshares *VerifiableShares) ReadFrom(r io.Reader) (int64, error) {
+	*vshares = (*vshares)[:0]
+	return readChunked(r, VShareSize, func(buf []byte) error {
+		var vshare VerifiableShare
+		if _, _, err := vshare.Unmarshal(buf, VShareSize); err != nil {
+			return err
+		}
+		*vshares = append(*vshares, vshare)
+		return nil
+	})
+}
+
+// WriteTo implements the io.WriterTo interface. It writes the same encoding as
+// Marshal, but encodes the points in fixed size chunks so that the whole
+// encoding never needs to be held in memory.
+func (c Commitment) WriteTo(w io.Writer) (int64, error) {
+	return writeChunked(w, len(c), secp256k1.PointSizeMarshalled, func(i int, buf []byte) error {
+		_, _, err := c[i].Marshal(buf, secp256k1.PointSizeMarshalled)
+		return err
+	})
+}
+
+// ReadFrom implements the io.ReaderFrom interface. It reads a single encoding
+// as written by WriteTo or Marshal and stores the result in the caller. It
+// does not read until EOF, and returns an error if the encoded length would
+// exceed surge.MaxBytes.
+func (c *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	*c = (*c)[:0]
+	return readChunked(r, secp256k1.PointSizeMarshalled, func(buf []byte) error {
+		var p secp256k1.Point
+		if _, _, err := p.Unmarshal(buf, secp256k1.PointSize); err != nil {
+			return err
+		}
+		*c = append(*c, p)
+		return nil
+	})
+}
+
+// Writes the length prefix followed by n elements of the given size, which
+// are encoded into a buffer by put a chunk at a time.
+func writeChunked(w io.Writer, n, size int, put func(i int, buf []byte) error) (int64, error) {
+	var prefix [surge.SizeHintU32]byte
+	binary.BigEndian.PutUint32(prefix[:], uint32(n))
+	written, err := w.Write(prefix[:])
+	total := int64(written)
+	if err != nil {
+		return total, err
+	}
+
+	chunk := make([]byte, streamChunkLen*size)
+	for i := 0; i < n; {
+		j := 0
+		for ; j < streamChunkLen && i < n; i, j = i+1, j+1 {
+			if err := put(i, chunk[j*size:(j+1)*size]); err != nil {
+				return total, err
+			}
+		}
+		written, err = w.Write(chunk[:j*size])
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Reads a length prefix followed by that many elements of the given size,
+// passing the encoding of each element to get in order.
+func readChunked(r io.Reader, size int, get func(buf []byte) error) (int64, error) {
+	var prefix [surge.SizeHintU32]byte
+	read, err := io.ReadFull(r, prefix[:])
+	total := int64(read)
+	if err != nil {
+		return total, err
+	}
+	n := int(binary.BigEndian.Uint32(prefix[:]))
+	if n > (surge.MaxBytes-surge.SizeHintU32)/size {
+		return total, fmt.Errorf("encoded length %v exceeds the maximum of %v bytes", n, surge.MaxBytes)
+	}
+
+	chunkLen := streamChunkLen
+	if n < chunkLen {
+		chunkLen = n
+	}
+	chunk := make([]byte, chunkLen*size)
+	for n > 0 {
+		m := chunkLen
+		if n < m {
+			m = n
+		}
+		read, err = io.ReadFull(r, chunk[:m*size])
+		total += int64(read)
+		if err != nil {
+			return total, err
+		}
+		for j := 0; j < m; j++ {
+			if err := get(chunk[j*size : (j+1)*size]); err != nil {
+				return total, err
+			}
+		}
+		n -= m
+	}
+	return total, nil
+}
@@ -0,0 +1,152 @@
+package shamir_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Streaming", func() {
+	trials := 10
+
+	// Lengths both smaller and larger than the size of a chunk.
+	randomLen := func() int { return rand.Intn(600) }
+
+	randomVShares := func(n int) VerifiableShares {
+		vshares := make(VerifiableShares, n)
+		for i := range vshares {
+			vshares[i] = NewVerifiableShare(
+				NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()),
+				secp256k1.RandomFn(),
+			)
+		}
+		return vshares
+	}
+
+	type streamer interface {
+		surge.Marshaler
+		io.WriterTo
+	}
+
+	expectWireFormat := func(v streamer) []byte {
+		var buf bytes.Buffer
+		n, err := v.WriteTo(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(int64(buf.Len())))
+		bs, err := surge.ToBinary(v)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.Bytes()).To(Equal(bs))
+		return bs
+	}
+
+	It("should stream shares in the surge wire format", func() {
+		for i := 0; i < trials; i++ {
+			shares := randomVShares(randomLen()).Shares()
+			bs := expectWireFormat(shares)
+
+			var decoded Shares
+			n, err := decoded.ReadFrom(bytes.NewReader(bs))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(int64(len(bs))))
+			Expect(decoded).To(HaveLen(len(shares)))
+			for j := range shares {
+				Expect(decoded[j].Eq(&shares[j])).To(BeTrue())
+			}
+		}
+	})
+
+	It("should stream verifiable shares in the surge wire format", func() {
+		for i := 0; i < trials; i++ {
+			vshares := randomVShares(randomLen())
+			bs := expectWireFormat(vshares)
+
+			var decoded VerifiableShares
+			n, err := decoded.ReadFrom(bytes.NewReader(bs))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(int64(len(bs))))
+			Expect(decoded).To(HaveLen(len(vshares)))
+			for j := range vshares {
+				Expect(decoded[j].Eq(&vshares[j])).To(BeTrue())
+			}
+		}
+	})
+
+	It("should stream commitments in the surge wire format", func() {
+		for i := 0; i < trials; i++ {
+			c := RandomCommitment(randomLen())
+			bs := expectWireFormat(c)
+
+			var decoded Commitment
+			n, err := decoded.ReadFrom(bytes.NewReader(bs))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(int64(len(bs))))
+			Expect(decoded.Eq(c)).To(BeTrue())
+		}
+	})
+
+	It("should read consecutive values from the same reader", func() {
+		vshares := randomVShares(randomLen())
+		c := RandomCommitment(randomLen())
+		var buf bytes.Buffer
+		_, err := vshares.WriteTo(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = c.WriteTo(&buf)
+		Expect(err).ToNot(HaveOccurred())
+
+		decodedVShares := randomVShares(3)
+		_, err = decodedVShares.ReadFrom(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedVShares).To(HaveLen(len(vshares)))
+		var decodedCom Commitment
+		_, err = decodedCom.ReadFrom(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodedCom.Eq(c)).To(BeTrue())
+		Expect(buf.Len()).To(Equal(0))
+	})
+
+	It("should return an error when the stream is truncated", func() {
+		for i := 0; i < trials; i++ {
+			vshares := randomVShares(1 + randomLen())
+			bs, err := surge.ToBinary(vshares)
+			Expect(err).ToNot(HaveOccurred())
+
+			var decoded VerifiableShares
+			_, err = decoded.ReadFrom(bytes.NewReader(bs[:rand.Intn(len(bs))]))
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should return an error when the length is too large", func() {
+		var decoded Shares
+		_, err := decoded.ReadFrom(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error when the writer fails", func() {
+		c := RandomCommitment(RandRange(1, 600))
+		_, err := c.WriteTo(&failingWriter{limit: rand.Intn(c.SizeHint())})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, io.ErrShortWrite
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
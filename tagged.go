@@ -0,0 +1,126 @@
+package shamir
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+)
+
+// MaxShareTagLen is the maximum length in bytes of the tag of a TaggedShare.
+const MaxShareTagLen = 1024
+
+// The tag that separates the digests used to label decommitments with tags.
+const shareTagDigestTag = "renproject/shamir/share tag"
+
+// A TaggedShare is a verifiable share together with a label, such as an epoch
+// number or session ID. The stored decommitment is the decommitment of the
+// share minus a hash of the tag and the index of the share, so the share only
+// verifies once it has been untagged with the same tag. This catches a share
+// that was labelled for one session being used by mistake in another session
+// that happens to use the same commitment.
+//
+// The tag is only a label, not an integrity binding. The hash is unkeyed and
+// its inputs are public, so anyone that holds a tagged share can untag it and
+// tag it again with any other tag, and the result is indistinguishable from a
+// share that the dealer tagged. Protocols that need a share to be bound to a
+// session must commit to the session in the dealing itself, for example by
+// signing the commitment together with the session ID.
+type TaggedShare struct {
+	// Tagged is the share with the labelled decommitment. It is not valid for
+	// the commitment of the sharing until it has been untagged.
+	Tagged VerifiableShare
+	Tag    []byte
+}
+
+// NewTaggedShare labels the given share with the given tag. The tag is copied.
+//
+// Panics: This function will panic if the tag is longer than MaxShareTagLen.
+func NewTaggedShare(vshare *VerifiableShare, tag []byte) TaggedShare {
+	if len(tag) > MaxShareTagLen {
+		panic(fmt.Sprintf("tag too long: expected at most %v bytes, got %v", MaxShareTagLen, len(tag)))
+	}
+	var offset secp256k1.Fn
+	tagLabelOffset(&offset, tag, &vshare.Share.Index)
+	offset.Negate(&offset)
+
+	ts := TaggedShare{Tagged: *vshare, Tag: append([]byte{}, tag...)}
+	ts.Tagged.Decommitment.Add(&ts.Tagged.Decommitment, &offset)
+	return ts
+}
+
+// Untag returns the underlying verifiable share, which is valid for the
+// commitment of the sharing. An error is returned if the tag of the share is
+// not the expected tag.
+func (ts *TaggedShare) Untag(expected []byte) (VerifiableShare, error) {
+	if !bytes.Equal(ts.Tag, expected) {
+		return VerifiableShare{}, fmt.Errorf("share tag does not match the expected tag")
+	}
+	var offset secp256k1.Fn
+	tagLabelOffset(&offset, ts.Tag, &ts.Tagged.Share.Index)
+
+	vshare := ts.Tagged
+	vshare.Decommitment.Add(&vshare.Decommitment, &offset)
+	return vshare, nil
+}
+
+// IsValidTagged returns true if the tagged share has the expected tag and the
+// untagged share is valid for the given commitment, as for IsValid.
+func IsValidTagged(h secp256k1.Point, c *Commitment, ts *TaggedShare, expected []byte) bool {
+	vshare, err := ts.Untag(expected)
+	if err != nil {
+		return false
+	}
+	return IsValid(h, c, &vshare)
+}
+
+// Eq returns true if the two tagged shares are equal, and false otherwise.
+func (ts *TaggedShare) Eq(other *TaggedShare) bool {
+	return ts.Tagged.Eq(&other.Tagged) && bytes.Equal(ts.Tag, other.Tag)
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (ts TaggedShare) SizeHint() int {
+	return ts.Tagged.SizeHint() + surge.SizeHintU32 + len(ts.Tag)
+}
+
+// Marshal implements the surge.Marshaler interface.
+func (ts TaggedShare) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ts.Tagged.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return surge.MarshalBytes(ts.Tag, buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface. An error is returned
+// if the tag is longer than MaxShareTagLen.
+func (ts *TaggedShare) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ts.Tagged.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	var l uint32
+	if _, _, err := surge.UnmarshalLen(&l, 1, buf, rem); err != nil {
+		return buf, rem, err
+	}
+	if l > MaxShareTagLen {
+		return buf, rem, fmt.Errorf("tag too long: expected at most %v bytes, got %v", MaxShareTagLen, l)
+	}
+	return surge.UnmarshalBytes(&ts.Tag, buf, rem)
+}
+
+// Sets dst to the hash of the tag and index, reduced modulo the group order.
+// The hash is unkeyed, so the offset only labels the decommitment with the
+// tag; it does not authenticate it.
+func tagLabelOffset(dst *secp256k1.Fn, tag []byte, index *secp256k1.Fn) {
+	h := newDigest(shareTagDigestTag)
+	writeU32(h, uint32(len(tag)))
+	h.Write(tag)
+	var bs [secp256k1.FnSizeMarshalled]byte
+	index.PutB32(bs[:])
+	h.Write(bs[:])
+	digest := sum(h)
+	dst.SetB32(digest[:])
+}
@@ -0,0 +1,114 @@
+package shamir_test
+
+import (
+	"bytes"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/surge"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Tagged shares", func() {
+	n, k := 10, 4
+	h := PedersenH()
+	epoch1, epoch2 := []byte("epoch 1"), []byte("epoch 2")
+
+	deal := func() (VerifiableShares, Commitment) {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+		return vshares, c
+	}
+
+	It("should only be valid for the tag that it was labelled with", func() {
+		vshares, c := deal()
+		for i := range vshares {
+			ts := NewTaggedShare(&vshares[i], epoch1)
+			Expect(IsValidTagged(h, &c, &ts, epoch1)).To(BeTrue())
+			Expect(IsValidTagged(h, &c, &ts, epoch2)).To(BeFalse())
+			Expect(IsValid(h, &c, &ts.Tagged)).To(BeFalse())
+
+			untagged, err := ts.Untag(epoch1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(untagged.Eq(&vshares[i])).To(BeTrue())
+			_, err = ts.Untag(epoch2)
+			Expect(err).To(HaveOccurred())
+
+			// Changing the tag without adjusting the decommitment gives an
+			// invalid share.
+			ts.Tag = epoch2
+			Expect(IsValidTagged(h, &c, &ts, epoch2)).To(BeFalse())
+		}
+	})
+
+	It("should let any holder relabel a share", func() {
+		// The tag is only a label: untagging and tagging again gives a share
+		// that is valid for the new tag.
+		vshares, c := deal()
+		ts := NewTaggedShare(&vshares[0], epoch1)
+		untagged, err := ts.Untag(epoch1)
+		Expect(err).ToNot(HaveOccurred())
+		relabelled := NewTaggedShare(&untagged, epoch2)
+		Expect(IsValidTagged(h, &c, &relabelled, epoch2)).To(BeTrue())
+	})
+
+	It("should work with empty tags", func() {
+		vshares, c := deal()
+		ts := NewTaggedShare(&vshares[0], nil)
+		Expect(IsValidTagged(h, &c, &ts, []byte{})).To(BeTrue())
+		Expect(IsValidTagged(h, &c, &ts, epoch1)).To(BeFalse())
+	})
+
+	It("should copy the tag", func() {
+		vshares, _ := deal()
+		tag := []byte("session")
+		ts := NewTaggedShare(&vshares[0], tag)
+		tag[0] = 'S'
+		Expect(ts.Tag).To(Equal([]byte("session")))
+	})
+
+	It("should panic for tags that are too long", func() {
+		vshares, _ := deal()
+		Expect(func() { NewTaggedShare(&vshares[0], make([]byte, MaxShareTagLen+1)) }).To(Panic())
+	})
+
+	It("should marshal and unmarshal", func() {
+		vshares, _ := deal()
+		ts := NewTaggedShare(&vshares[0], epoch1)
+		bs, err := surge.ToBinary(ts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(bs)).To(Equal(ts.SizeHint()))
+
+		var decoded TaggedShare
+		Expect(surge.FromBinary(&decoded, bs)).To(Succeed())
+		Expect(decoded.Eq(&ts)).To(BeTrue())
+
+		for i := range bs {
+			Expect(surge.FromBinary(&decoded, bs[:i])).ToNot(Succeed())
+		}
+
+		long := ts
+		long.Tag = bytes.Repeat([]byte{1}, MaxShareTagLen+1)
+		bs, err = surge.ToBinary(long)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(surge.FromBinary(&decoded, bs)).ToNot(Succeed())
+	})
+
+	It("should be encoded in the versioned wire format", func() {
+		vshares, _ := deal()
+		ts := NewTaggedShare(&vshares[0], epoch1)
+		for _, v := range []interface{}{ts, &ts} {
+			bs, err := EncodeV1(v)
+			Expect(err).ToNot(HaveOccurred())
+			decoded, err := DecodeAny(bs)
+			Expect(err).ToNot(HaveOccurred())
+			decodedTS, ok := decoded.(TaggedShare)
+			Expect(ok).To(BeTrue())
+			Expect(decodedTS.Eq(&ts)).To(BeTrue())
+		}
+	})
+})
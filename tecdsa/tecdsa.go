@@ -0,0 +1,213 @@
+// Package tecdsa provides the sharing utilities that threshold ECDSA
+// protocols over secp256k1 are built from, on top of the verifiable sharing in
+// the shamir package. It does not implement a complete signing protocol.
+//
+// An ECDSA signature on the hash m with nonce k and key x is (r, s), where r is
+// the x coordinate of k*G reduced modulo the group order, and
+// s = k^-1 (m + r x). The utilities here compute s from sharings of k^-1 and
+// x with the same indices:
+//	- ShareKeyAndNonce shares the key and a nonce with matching indices.
+//	- ProductShare multiplies two shares locally. The products are shares of
+//		the product of the secrets, but on a polynomial of degree 2(k-1), so
+//		2k-1 of them are needed to reconstruct it.
+//	- ReshareProduct and ReduceDegree turn the products back into a sharing
+//		with threshold k: each party verifiably reshares its product, and each
+//		party combines the subshares that it receives from 2k-1 parties with
+//		their Lagrange coefficients. ReduceCommitments combines the
+//		commitments in the same way, so the reduced shares can be checked
+//		with shamir.IsValid.
+//	- InvertWithMask computes shares of k^-1 from shares of a random mask and
+//		the opened product of the nonce and the mask.
+//	- PartialSignature and AssembleSignature compute and combine the
+//		Lagrange weighted shares of s.
+//
+// The verifiable sharings only show that each resharing is consistent; they do
+// not show that a party reshared the correct product of its shares. Protocols
+// that need to tolerate malicious parties must add a proof of this, or check
+// the final signature and identify the cheater by other means.
+package tecdsa
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// ShareKeyAndNonce verifiably shares the key and the nonce among the parties
+// with the given indices, with reconstruction threshold k, so that each party
+// holds a share of both with the same index. The shares and commitments are
+// stored in the given destinations, as for shamir.VShareSecret.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func ShareKeyAndNonce(
+	keyShares, nonceShares *shamir.VerifiableShares,
+	keyCommitment, nonceCommitment *shamir.Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	key, nonce secp256k1.Fn,
+	k int,
+) error {
+	if err := shamir.VShareSecret(keyShares, keyCommitment, indices, h, key, k); err != nil {
+		return err
+	}
+	return shamir.VShareSecret(nonceShares, nonceCommitment, indices, h, nonce, k)
+}
+
+// ProductShare returns the share with the same index as the given shares
+// whose value is the product of their values. If the shares are from sharings
+// of a and b with threshold k, the product is a share of ab with threshold
+// 2k-1. An error is returned if the shares have different indices.
+func ProductShare(a, b *shamir.Share) (shamir.Share, error) {
+	if !a.IndexEq(&b.Index) {
+		return shamir.Share{}, fmt.Errorf("shares have different indices")
+	}
+	var product shamir.Share
+	product.Index = a.Index
+	product.Value.Mul(&a.Value, &b.Value)
+	return product, nil
+}
+
+// ReshareProduct verifiably shares the value of the product share among the
+// parties with the given indices with threshold k, as the first step of
+// degree reduction. The shares and commitment are stored in the given
+// destinations, as for shamir.VShareSecret.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func ReshareProduct(
+	vshares *shamir.VerifiableShares,
+	c *shamir.Commitment,
+	product *shamir.Share,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	k int,
+) error {
+	return shamir.VShareSecret(vshares, c, indices, h, product.Value, k)
+}
+
+// ReduceDegree combines the subshares that a party received from the parties
+// with the given indices, which each reshared their product share with
+// ReshareProduct, into the party's share of the product with threshold k. The
+// i-th subshare must be from the party with the i-th sender index, and all
+// subshares must have the index of the receiving party. An error is returned
+// if there are fewer than 2k-1 senders, if the senders are not distinct, or if
+// the subshares do not all have the same index.
+func ReduceDegree(dst *shamir.VerifiableShare, senders []secp256k1.Fn, subshares shamir.VerifiableShares, k int) error {
+	if len(subshares) != len(senders) {
+		return fmt.Errorf("expected %v subshares, got %v", len(senders), len(subshares))
+	}
+	coeffs, err := reductionCoefficients(senders, k)
+	if err != nil {
+		return err
+	}
+	index := subshares[0].Share.Index
+	var reduced, term shamir.VerifiableShare
+	for i := range subshares {
+		if !subshares[i].Share.IndexEq(&index) {
+			return fmt.Errorf("subshare at position %v has a different index", i)
+		}
+		term.Scale(&subshares[i], &coeffs[i])
+		if i == 0 {
+			reduced = term
+		} else {
+			reduced.Add(&reduced, &term)
+		}
+	}
+	*dst = reduced
+	return nil
+}
+
+// ReduceCommitments combines the commitments of the resharings of the parties
+// with the given indices, in the same way as ReduceDegree combines the
+// subshares, so that the reduced shares are valid for the resulting
+// commitment. An error is returned under the same conditions as for
+// ReduceDegree.
+//
+// Panics: This function will panic if the destination commitment has a
+// capacity less than the length of the longest commitment.
+func ReduceCommitments(dst *shamir.Commitment, senders []secp256k1.Fn, commitments []shamir.Commitment, k int) error {
+	if len(commitments) != len(senders) {
+		return fmt.Errorf("expected %v commitments, got %v", len(senders), len(commitments))
+	}
+	coeffs, err := reductionCoefficients(senders, k)
+	if err != nil {
+		return err
+	}
+	maxLen := 0
+	for i := range commitments {
+		if commitments[i].Len() > maxLen {
+			maxLen = commitments[i].Len()
+		}
+	}
+	term := shamir.NewCommitmentWithCapacity(maxLen)
+	*dst = (*dst)[:0]
+	for i := range commitments {
+		term.Scale(commitments[i], &coeffs[i])
+		dst.Add(*dst, term)
+	}
+	return nil
+}
+
+// InvertWithMask computes a share of k^-1 from a share of a random mask and
+// the opened product u of k and the mask, which is the share of the mask
+// multiplied by u^-1. The product is opened by reconstructing it from the
+// reduced shares of the product. An error is returned if u is zero.
+func InvertWithMask(mask *shamir.Share, u *secp256k1.Fn) (shamir.Share, error) {
+	if u.IsZero() {
+		return shamir.Share{}, fmt.Errorf("opened product is zero")
+	}
+	var inv secp256k1.Fn
+	inv.Inverse(u)
+	var share shamir.Share
+	share.Scale(mask, &inv)
+	return share, nil
+}
+
+// PartialSignature returns the share of s = k^-1 (m + r x) for the given
+// shares of k^-1 and x, which must have the same index, and the message hash m
+// and signature component r. Since s is a product of two shared values, the
+// partial signatures are shares with threshold 2k-1.
+func PartialSignature(nonceInv, key *shamir.Share, m, r *secp256k1.Fn) (shamir.Share, error) {
+	if !nonceInv.IndexEq(&key.Index) {
+		return shamir.Share{}, fmt.Errorf("shares have different indices")
+	}
+	var partial shamir.Share
+	partial.Index = key.Index
+	partial.Value.Mul(r, &key.Value)
+	partial.Value.Add(&partial.Value, m)
+	partial.Value.Mul(&partial.Value, &nonceInv.Value)
+	return partial, nil
+}
+
+// AssembleSignature combines partial signatures from at least 2k-1 parties,
+// weighting each by its Lagrange coefficient, into the signature component s.
+// An error is returned if there are too few partial signatures or their
+// indices are not distinct. The signature is not normalised to low s.
+func AssembleSignature(partials shamir.Shares, k int) (secp256k1.Fn, error) {
+	indices := make([]secp256k1.Fn, len(partials))
+	for i := range partials {
+		indices[i] = partials[i].Index
+	}
+	coeffs, err := reductionCoefficients(indices, k)
+	if err != nil {
+		return secp256k1.Fn{}, err
+	}
+	var s, term secp256k1.Fn
+	for i := range partials {
+		term.Mul(&coeffs[i], &partials[i].Value)
+		s.Add(&s, &term)
+	}
+	return s, nil
+}
+
+// Returns the Lagrange coefficients of the given indices, checking that there
+// are enough of them to reconstruct a product of two sharings with threshold
+// k.
+func reductionCoefficients(indices []secp256k1.Fn, k int) ([]secp256k1.Fn, error) {
+	if len(indices) < 2*k-1 {
+		return nil, fmt.Errorf("expected at least %v shares, got %v", 2*k-1, len(indices))
+	}
+	return shamir.LagrangeCoefficients(indices)
+}
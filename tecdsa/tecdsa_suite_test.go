@@ -0,0 +1,13 @@
+package tecdsa_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTECDSA(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Threshold ECDSA Suite")
+}
@@ -0,0 +1,157 @@
+package tecdsa_test
+
+import (
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/tecdsa"
+)
+
+// Returns the x coordinate of the point reduced modulo the group order.
+func xModN(p *secp256k1.Point) secp256k1.Fn {
+	x, _, err := p.XY()
+	Expect(err).ToNot(HaveOccurred())
+	var bs [32]byte
+	x.PutB32(bs[:])
+	var r secp256k1.Fn
+	r.SetB32(bs[:])
+	return r
+}
+
+// Checks an ECDSA signature (r, s) on the hash m for the public key pk.
+func verifyECDSA(pk *secp256k1.Point, m, r, s *secp256k1.Fn) bool {
+	var sInv, u1, u2 secp256k1.Fn
+	sInv.Inverse(s)
+	u1.Mul(m, &sInv)
+	u2.Mul(r, &sInv)
+	var a, b secp256k1.Point
+	a.BaseExp(&u1)
+	b.ScaleExt(pk, &u2)
+	a.Add(&a, &b)
+	if a.IsInfinity() {
+		return false
+	}
+	x := xModN(&a)
+	return x.Eq(r)
+}
+
+var _ = Describe("Threshold ECDSA utilities", func() {
+	n, k := 7, 3
+	h := shamir.PedersenH()
+
+	// Performs a verifiable multiplication of the sharings a and b, where
+	// the first 2k-1 parties act as senders, and returns the reduced shares
+	// and commitment.
+	multiply := func(indices []secp256k1.Fn, a, b shamir.VerifiableShares) (shamir.VerifiableShares, shamir.Commitment) {
+		senders := indices[:2*k-1]
+		subshares := make([]shamir.VerifiableShares, len(senders))
+		commitments := make([]shamir.Commitment, len(senders))
+		for j := range senders {
+			product, err := ProductShare(&a[j].Share, &b[j].Share)
+			Expect(err).ToNot(HaveOccurred())
+			subshares[j] = make(shamir.VerifiableShares, n)
+			commitments[j] = shamir.NewCommitmentWithCapacity(k)
+			Expect(ReshareProduct(&subshares[j], &commitments[j], &product, indices, h, k)).To(Succeed())
+		}
+
+		c := shamir.NewCommitmentWithCapacity(k)
+		Expect(ReduceCommitments(&c, senders, commitments, k)).To(Succeed())
+
+		reduced := make(shamir.VerifiableShares, n)
+		received := make(shamir.VerifiableShares, len(senders))
+		for i := range indices {
+			for j := range senders {
+				Expect(shamir.IsValid(h, &commitments[j], &subshares[j][i])).To(BeTrue())
+				received[j] = subshares[j][i]
+			}
+			Expect(ReduceDegree(&reduced[i], senders, received, k)).To(Succeed())
+			Expect(shamir.IsValid(h, &c, &reduced[i])).To(BeTrue())
+		}
+		return reduced, c
+	}
+
+	It("should reduce the degree of a product of sharings", func() {
+		indices := shamirutil.RandomIndices(n)
+		x, y := secp256k1.RandomFn(), secp256k1.RandomFn()
+		xShares, yShares := make(shamir.VerifiableShares, n), make(shamir.VerifiableShares, n)
+		xc, yc := shamir.NewCommitmentWithCapacity(k), shamir.NewCommitmentWithCapacity(k)
+		Expect(ShareKeyAndNonce(&xShares, &yShares, &xc, &yc, indices, h, x, y, k)).To(Succeed())
+
+		reduced, c := multiply(indices, xShares, yShares)
+		Expect(c.Len()).To(Equal(k))
+		var expected secp256k1.Fn
+		expected.Mul(&x, &y)
+		shares := reduced.Shares()
+		shamirutil.Shuffle(shares)
+		opened := shamir.Open(shares[:k])
+		Expect(opened.Eq(&expected)).To(BeTrue())
+	})
+
+	It("should produce a valid ECDSA signature", func() {
+		indices := shamirutil.RandomIndices(n)
+		key, nonce, mask := secp256k1.RandomFn(), secp256k1.RandomFn(), secp256k1.RandomFn()
+		var pk, bigR secp256k1.Point
+		pk.BaseExp(&key)
+		bigR.BaseExp(&nonce)
+
+		keyShares, nonceShares := make(shamir.VerifiableShares, n), make(shamir.VerifiableShares, n)
+		keyC, nonceC := shamir.NewCommitmentWithCapacity(k), shamir.NewCommitmentWithCapacity(k)
+		Expect(ShareKeyAndNonce(&keyShares, &nonceShares, &keyC, &nonceC, indices, h, key, nonce, k)).To(Succeed())
+		maskShares := make(shamir.VerifiableShares, n)
+		maskC := shamir.NewCommitmentWithCapacity(k)
+		Expect(shamir.VShareSecret(&maskShares, &maskC, indices, h, mask, k)).To(Succeed())
+
+		// Open u = nonce * mask and invert the nonce.
+		reduced, _ := multiply(indices, nonceShares, maskShares)
+		u := shamir.Open(reduced.Shares()[:k])
+		nonceInvShares := make(shamir.Shares, n)
+		for i := range indices {
+			var err error
+			nonceInvShares[i], err = InvertWithMask(&maskShares[i].Share, &u)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		m := secp256k1.RandomFn()
+		r := xModN(&bigR)
+		partials := make(shamir.Shares, n)
+		for i := range indices {
+			var err error
+			partials[i], err = PartialSignature(&nonceInvShares[i], &keyShares[i].Share, &m, &r)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		shamirutil.Shuffle(partials)
+		s, err := AssembleSignature(partials[:2*k-1], k)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(verifyECDSA(&pk, &m, &r, &s)).To(BeTrue())
+
+		_, err = AssembleSignature(partials[:2*k-2], k)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return errors for mismatched inputs", func() {
+		a := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+		b := shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn())
+		_, err := ProductShare(&a, &b)
+		Expect(err).To(HaveOccurred())
+		m, r := secp256k1.RandomFn(), secp256k1.RandomFn()
+		_, err = PartialSignature(&a, &b, &m, &r)
+		Expect(err).To(HaveOccurred())
+
+		zero := secp256k1.NewFnFromU16(0)
+		_, err = InvertWithMask(&a, &zero)
+		Expect(err).To(HaveOccurred())
+
+		senders := shamirutil.RandomIndices(2*k - 1)
+		subshares := make(shamir.VerifiableShares, len(senders))
+		for i := range subshares {
+			subshares[i] = shamir.NewVerifiableShare(shamir.NewShare(secp256k1.RandomFn(), secp256k1.RandomFn()), secp256k1.RandomFn())
+		}
+		var dst shamir.VerifiableShare
+		Expect(ReduceDegree(&dst, senders, subshares, k)).ToNot(Succeed())
+		Expect(ReduceDegree(&dst, senders[:2*k-2], subshares[:2*k-2], k)).ToNot(Succeed())
+		Expect(ReduceDegree(&dst, senders, subshares[1:], k)).ToNot(Succeed())
+	})
+})
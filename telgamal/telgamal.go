@@ -0,0 +1,232 @@
+// Package telgamal implements threshold ElGamal encryption over secp256k1 on
+// top of the verifiable sharing in the shamir package.
+//
+// A dealer shares a random private key x using VShareSecret, and publishes the
+// public key x*G. Anyone can encrypt a message, which is a curve point, to the
+// public key. To decrypt, each of at least k parties computes a decryption
+// share from its share of the private key, together with a DLEQ proof that
+// the decryption share is consistent with the party's verification key. Any k
+// valid decryption shares can then be combined to recover the message by
+// Lagrange interpolation in the exponent.
+//
+// The verification key of a party is s*G, where s is the value of its share of
+// the private key. Parties publish their verification keys, and the public key
+// can be recomputed from any k of them with PublicKeyFromVerificationKeys.
+package telgamal
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/dleq"
+)
+
+// ProofDomain is the domain separation tag used for the DLEQ proofs attached
+// to decryption shares.
+const ProofDomain = "renproject/shamir/telgamal decryption share"
+
+// CiphertextSize is the number of bytes in a marshalled Ciphertext.
+const CiphertextSize = 2 * secp256k1.PointSizeMarshalled
+
+// DecryptionShareSize is the number of bytes in a marshalled DecryptionShare.
+const DecryptionShareSize = secp256k1.FnSizeMarshalled + secp256k1.PointSizeMarshalled + dleq.ProofSize
+
+// ShareKey generates a random private key, verifiably shares it among the
+// parties with the given indices with reconstruction threshold k, and returns
+// the corresponding public key. The shares and commitment are stored in the
+// given destinations, as for shamir.VShareSecret.
+//
+// Panics: This function will panic under the same conditions as
+// shamir.VShareSecret.
+func ShareKey(
+	vshares *shamir.VerifiableShares,
+	c *shamir.Commitment,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	k int,
+) (secp256k1.Point, error) {
+	x, err := shamir.RandomFn()
+	if err != nil {
+		return secp256k1.Point{}, err
+	}
+	defer x.Clear()
+	if err := shamir.VShareSecret(vshares, c, indices, h, x, k); err != nil {
+		return secp256k1.Point{}, err
+	}
+	var pk secp256k1.Point
+	pk.BaseExp(&x)
+	return pk, nil
+}
+
+// VerificationKey returns the verification key for the given share of the
+// private key, which is value*G.
+func VerificationKey(share *shamir.Share) secp256k1.Point {
+	var vk secp256k1.Point
+	vk.BaseExp(&share.Value)
+	return vk
+}
+
+// PublicKeyFromVerificationKeys computes the public key from the verification
+// keys of at least k parties with the given indices, by Lagrange
+// interpolation in the exponent.
+func PublicKeyFromVerificationKeys(indices []secp256k1.Fn, vks []secp256k1.Point) (secp256k1.Point, error) {
+	return shamir.InterpolateInExponent(indices, vks)
+}
+
+// A Ciphertext is an ElGamal encryption (r*G, M + r*PK) of a message M to the
+// public key PK.
+type Ciphertext struct {
+	C1, C2 secp256k1.Point
+}
+
+// Encrypt encrypts the given message to the public key. The randomness of the
+// encryption is read from the source set with shamir.SetRandomSource, and an
+// error is returned if reading from it fails.
+func Encrypt(pk *secp256k1.Point, msg *secp256k1.Point) (Ciphertext, error) {
+	r, err := shamir.RandomFn()
+	if err != nil {
+		return Ciphertext{}, err
+	}
+	defer r.Clear()
+
+	var ct Ciphertext
+	ct.C1.BaseExp(&r)
+	ct.C2.Scale(pk, &r)
+	ct.C2.Add(&ct.C2, msg)
+	return ct, nil
+}
+
+// Generate implements the quick.Generator interface.
+func (ct Ciphertext) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(Ciphertext{C1: secp256k1.RandomPoint(), C2: secp256k1.RandomPoint()})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (ct Ciphertext) SizeHint() int { return CiphertextSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (ct Ciphertext) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ct.C1.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return ct.C2.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (ct *Ciphertext) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ct.C1.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return ct.C2.Unmarshal(buf, rem)
+}
+
+// A DecryptionShare is the contribution of a single party to the decryption
+// of a ciphertext. It consists of the index of the party, the point s*C1,
+// where s is the party's share of the private key, and a proof that the point
+// was computed correctly.
+type DecryptionShare struct {
+	Index secp256k1.Fn
+	D     secp256k1.Point
+	Proof dleq.Proof
+}
+
+// PartialDecrypt computes the decryption share of the given ciphertext for
+// the given share of the private key. An error is returned if the nonce of
+// the proof can not be generated.
+func PartialDecrypt(ct *Ciphertext, share *shamir.Share) (DecryptionShare, error) {
+	g := generator()
+	st := dleq.NewStatement(&share.Value, &g, &ct.C1)
+	proof, err := dleq.Prove(&st, &share.Value, []byte(ProofDomain))
+	if err != nil {
+		return DecryptionShare{}, err
+	}
+	return DecryptionShare{Index: share.Index, D: st.H2, Proof: proof}, nil
+}
+
+// VerifyDecryptionShare returns true if the decryption share was correctly
+// computed for the given ciphertext by the party with the given verification
+// key, and false otherwise.
+func VerifyDecryptionShare(ct *Ciphertext, ds *DecryptionShare, vk *secp256k1.Point) bool {
+	st := dleq.Statement{G1: generator(), H1: *vk, G2: ct.C1, H2: ds.D}
+	return dleq.Verify(&st, &ds.Proof, []byte(ProofDomain))
+}
+
+// Combine recovers the message from at least k decryption shares of the
+// ciphertext with distinct indices. The decryption shares are not verified;
+// this should be done with VerifyDecryptionShare before combining them, since
+// a single invalid decryption share will give an incorrect message.
+func Combine(ct *Ciphertext, dss []DecryptionShare) (secp256k1.Point, error) {
+	if len(dss) == 0 {
+		return secp256k1.Point{}, errors.New("no decryption shares given")
+	}
+	indices := make([]secp256k1.Fn, len(dss))
+	ds := make([]secp256k1.Point, len(dss))
+	for i := range dss {
+		indices[i] = dss[i].Index
+		ds[i] = dss[i].D
+	}
+
+	// The interpolation gives x*C1 = r*PK.
+	xC1, err := shamir.InterpolateInExponent(indices, ds)
+	if err != nil {
+		return secp256k1.Point{}, err
+	}
+	var minusOne secp256k1.Fn
+	minusOne.SetU16(1)
+	minusOne.Negate(&minusOne)
+	xC1.ScaleExt(&xC1, &minusOne)
+
+	var msg secp256k1.Point
+	msg.Add(&ct.C2, &xC1)
+	return msg, nil
+}
+
+// Generate implements the quick.Generator interface.
+func (ds DecryptionShare) Generate(_ *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(DecryptionShare{
+		Index: secp256k1.RandomFn(),
+		D:     secp256k1.RandomPoint(),
+		Proof: dleq.Proof{Challenge: secp256k1.RandomFn(), Response: secp256k1.RandomFn()},
+	})
+}
+
+// SizeHint implements the surge.SizeHinter interface.
+func (ds DecryptionShare) SizeHint() int { return DecryptionShareSize }
+
+// Marshal implements the surge.Marshaler interface.
+func (ds DecryptionShare) Marshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ds.Index.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = ds.D.Marshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return ds.Proof.Marshal(buf, rem)
+}
+
+// Unmarshal implements the surge.Unmarshaler interface.
+func (ds *DecryptionShare) Unmarshal(buf []byte, rem int) ([]byte, int, error) {
+	buf, rem, err := ds.Index.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	buf, rem, err = ds.D.Unmarshal(buf, rem)
+	if err != nil {
+		return buf, rem, err
+	}
+	return ds.Proof.Unmarshal(buf, rem)
+}
+
+func generator() secp256k1.Point {
+	var g secp256k1.Point
+	one := secp256k1.NewFnFromU16(1)
+	g.BaseExp(&one)
+	return g
+}
@@ -0,0 +1,13 @@
+package telgamal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTElGamal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Threshold ElGamal Suite")
+}
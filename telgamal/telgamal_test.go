@@ -0,0 +1,150 @@
+package telgamal_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/shamirutil"
+	"github.com/renproject/surge/surgeutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/telgamal"
+)
+
+var _ = Describe("Threshold ElGamal", func() {
+	trials := 10
+	n := 10
+	h := shamir.PedersenH()
+
+	encrypt := func(pk, msg *secp256k1.Point) Ciphertext {
+		ct, err := Encrypt(pk, msg)
+		Expect(err).ToNot(HaveOccurred())
+		return ct
+	}
+
+	partialDecrypt := func(ct *Ciphertext, share *shamir.Share) DecryptionShare {
+		ds, err := PartialDecrypt(ct, share)
+		Expect(err).ToNot(HaveOccurred())
+		return ds
+	}
+
+	setup := func(k int) (secp256k1.Point, shamir.VerifiableShares, []secp256k1.Point) {
+		indices := shamirutil.RandomIndices(n)
+		vshares := make(shamir.VerifiableShares, n)
+		c := shamir.NewCommitmentWithCapacity(k)
+		pk, err := ShareKey(&vshares, &c, indices, h, k)
+		Expect(err).ToNot(HaveOccurred())
+		for i := range vshares {
+			Expect(shamir.IsValid(h, &c, &vshares[i])).To(BeTrue())
+		}
+		vks := make([]secp256k1.Point, n)
+		for i := range vshares {
+			vks[i] = VerificationKey(&vshares[i].Share)
+		}
+		return pk, vshares, vks
+	}
+
+	It("should decrypt with any k valid decryption shares", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(1, n)
+			pk, vshares, vks := setup(k)
+			msg := secp256k1.RandomPoint()
+			ct := encrypt(&pk, &msg)
+
+			perm := rand.Perm(n)[:k]
+			dss := make([]DecryptionShare, k)
+			for j, p := range perm {
+				dss[j] = partialDecrypt(&ct, &vshares[p].Share)
+				Expect(VerifyDecryptionShare(&ct, &dss[j], &vks[p])).To(BeTrue())
+			}
+			decrypted, err := Combine(&ct, dss)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decrypted.Eq(&msg)).To(BeTrue())
+		}
+	})
+
+	It("should not decrypt with fewer than k decryption shares", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(2, n)
+			pk, vshares, _ := setup(k)
+			msg := secp256k1.RandomPoint()
+			ct := encrypt(&pk, &msg)
+
+			dss := make([]DecryptionShare, k-1)
+			for j := range dss {
+				dss[j] = partialDecrypt(&ct, &vshares[j].Share)
+			}
+			decrypted, err := Combine(&ct, dss)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decrypted.Eq(&msg)).To(BeFalse())
+		}
+	})
+
+	It("should detect invalid decryption shares", func() {
+		for i := 0; i < trials; i++ {
+			// When k = 1 every party has the same share, so k is at least 2.
+			pk, vshares, vks := setup(shamirutil.RandRange(2, n))
+			msg := secp256k1.RandomPoint()
+			ct := encrypt(&pk, &msg)
+
+			j := rand.Intn(n)
+			ds := partialDecrypt(&ct, &vshares[j].Share)
+			other := (j + 1) % n
+			Expect(VerifyDecryptionShare(&ct, &ds, &vks[other])).To(BeFalse())
+
+			ds.D = secp256k1.RandomPoint()
+			Expect(VerifyDecryptionShare(&ct, &ds, &vks[j])).To(BeFalse())
+
+			otherCt := encrypt(&pk, &msg)
+			ds = partialDecrypt(&ct, &vshares[j].Share)
+			Expect(VerifyDecryptionShare(&otherCt, &ds, &vks[j])).To(BeFalse())
+		}
+	})
+
+	It("should recompute the public key from verification keys", func() {
+		for i := 0; i < trials; i++ {
+			k := shamirutil.RandRange(1, n)
+			pk, vshares, vks := setup(k)
+			indices := make([]secp256k1.Fn, k)
+			for j := range indices {
+				indices[j] = vshares[j].Share.Index
+			}
+			recomputed, err := PublicKeyFromVerificationKeys(indices, vks[:k])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recomputed.Eq(&pk)).To(BeTrue())
+		}
+	})
+
+	It("should return errors for invalid inputs", func() {
+		_, err := Combine(&Ciphertext{}, nil)
+		Expect(err).To(HaveOccurred())
+
+		index := secp256k1.RandomFn()
+		vks := []secp256k1.Point{secp256k1.RandomPoint(), secp256k1.RandomPoint()}
+		_, err = PublicKeyFromVerificationKeys([]secp256k1.Fn{index, index}, vks)
+		Expect(err).To(HaveOccurred())
+		_, err = PublicKeyFromVerificationKeys([]secp256k1.Fn{index}, vks)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("surge marshalling", func() {
+		for _, t := range []reflect.Type{reflect.TypeOf(Ciphertext{}), reflect.TypeOf(DecryptionShare{})} {
+			t := t
+
+			It(fmt.Sprintf("should marshal and unmarshal %v", t), func() {
+				for i := 0; i < trials; i++ {
+					Expect(surgeutil.MarshalUnmarshalCheck(t)).To(Succeed())
+					Expect(func() { surgeutil.Fuzz(t) }).ToNot(Panic())
+					Expect(surgeutil.MarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.MarshalRemTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalBufTooSmall(t)).To(Succeed())
+					Expect(surgeutil.UnmarshalRemTooSmall(t)).To(Succeed())
+				}
+			})
+		}
+	})
+})
@@ -0,0 +1,296 @@
+//go:build ignore
+// +build ignore
+
+// This program generates the implementations of the group interface for the
+// packages of the groups other than secp256k1, which all share the same API.
+// It is run by go generate.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+// The packages for which a group is generated.
+var packages = []string{"bn254", "p256", "ristretto255"}
+
+var groupTmpl = template.Must(template.New("group").Parse(`// Code generated by gen_groups.go; DO NOT EDIT.
+
+package testvectors
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+
+	"github.com/renproject/shamir/{{.}}"
+)
+
+type {{.}}Group struct{}
+
+func (g {{.}}Group) randomScalar(rng *rand.Rand) string {
+	// Sampling encodings until one is canonical gives uniform scalars without
+	// depending on how the package reduces random bytes.
+	bs := make([]byte, {{.}}.ScalarSize)
+	var s {{.}}.Scalar
+	for {
+		rng.Read(bs)
+		if s.SetBytes(bs) == nil {
+			return g.encodeScalar(&s)
+		}
+	}
+}
+
+func (g {{.}}Group) smallScalar(v uint16) string {
+	s := {{.}}.NewScalarFromU16(v)
+	return g.encodeScalar(&s)
+}
+
+func (g {{.}}Group) pedersenH() string {
+	h := {{.}}.PedersenH()
+	return g.encodePoint(&h)
+}
+
+func (g {{.}}Group) share(coeffs, indices []string) ([]Share, error) {
+	cs, err := g.decodeScalars(coeffs)
+	if err != nil {
+		return nil, err
+	}
+	xs, err := g.decodeScalars(indices)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]Share, len(xs))
+	for i := range xs {
+		y := g.eval(cs, &xs[i])
+		shares[i] = Share{Index: g.encodeScalar(&xs[i]), Value: g.encodeScalar(&y)}
+	}
+	return shares, nil
+}
+
+func (g {{.}}Group) vshare(h string, coeffs, decomCoeffs, indices []string) ([]string, []VShare, error) {
+	hp, err := g.decodePoint(h)
+	if err != nil {
+		return nil, nil, err
+	}
+	cs, err := g.decodeScalars(coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	ds, err := g.decodeScalars(decomCoeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ds) != len(cs) {
+		return nil, nil, fmt.Errorf("expected %v decommitment coefficients, got %v", len(cs), len(ds))
+	}
+	xs, err := g.decodeScalars(indices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := make({{.}}.Commitment, len(cs))
+	var hPow {{.}}.Point
+	for i := range c {
+		c[i].BaseExp(&cs[i])
+		hPow.Scale(&hp, &ds[i])
+		c[i].Add(&c[i], &hPow)
+	}
+	vshares := make({{.}}.VerifiableShares, len(xs))
+	for i := range xs {
+		vshares[i] = {{.}}.NewVerifiableShare({{.}}.NewShare(xs[i], g.eval(cs, &xs[i])), g.eval(ds, &xs[i]))
+	}
+	return g.encodePoints(c), g.encodeVShares(vshares), nil
+}
+
+func (g {{.}}Group) checkValid(h string, c []string, vshares []VShare) error {
+	hp, err := g.decodePoint(h)
+	if err != nil {
+		return err
+	}
+	com, err := g.decodePoints(c)
+	if err != nil {
+		return err
+	}
+	vs, err := g.decodeVShares(vshares)
+	if err != nil {
+		return err
+	}
+	for i := range vs {
+		if !{{.}}.IsValid(hp, com, &vs[i]) {
+			return fmt.Errorf("share %v is not valid for the commitment", i)
+		}
+	}
+	return nil
+}
+
+func (g {{.}}Group) open(shares []Share) (string, error) {
+	ss := make({{.}}.Shares, len(shares))
+	for i := range shares {
+		var err error
+		if ss[i].Index, err = g.decodeScalar(shares[i].Index); err != nil {
+			return "", err
+		}
+		if ss[i].Value, err = g.decodeScalar(shares[i].Value); err != nil {
+			return "", err
+		}
+	}
+	secret := {{.}}.Open(ss)
+	return g.encodeScalar(&secret), nil
+}
+
+func (g {{.}}Group) add(c1 []string, vshares1 []VShare, c2 []string, vshares2 []VShare) ([]string, []VShare, error) {
+	com1, err := g.decodePoints(c1)
+	if err != nil {
+		return nil, nil, err
+	}
+	com2, err := g.decodePoints(c2)
+	if err != nil {
+		return nil, nil, err
+	}
+	vs1, err := g.decodeVShares(vshares1)
+	if err != nil {
+		return nil, nil, err
+	}
+	vs2, err := g.decodeVShares(vshares2)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(com1) != len(com2) || len(vs1) != len(vs2) {
+		return nil, nil, fmt.Errorf("sharings have different sizes")
+	}
+
+	sum := {{.}}.NewCommitmentWithCapacity(len(com1))
+	sum.Add(com1, com2)
+	vshares := make({{.}}.VerifiableShares, len(vs1))
+	for i := range vshares {
+		vshares[i].Add(&vs1[i], &vs2[i])
+	}
+	return g.encodePoints(sum), g.encodeVShares(vshares), nil
+}
+
+// Evaluates the polynomial with the given coefficients at x.
+func (g {{.}}Group) eval(coeffs []{{.}}.Scalar, x *{{.}}.Scalar) {{.}}.Scalar {
+	y := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		y.Mul(&y, x)
+		y.Add(&y, &coeffs[i])
+	}
+	return y
+}
+
+func (g {{.}}Group) encodeScalar(x *{{.}}.Scalar) string {
+	var bs [{{.}}.ScalarSize]byte
+	x.PutBytes(bs[:])
+	return hex.EncodeToString(bs[:])
+}
+
+func (g {{.}}Group) decodeScalar(str string) ({{.}}.Scalar, error) {
+	var x {{.}}.Scalar
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return x, err
+	}
+	if err := x.SetBytes(bs); err != nil {
+		return x, fmt.Errorf("scalar %v: %v", str, err)
+	}
+	return x, nil
+}
+
+func (g {{.}}Group) decodeScalars(strs []string) ([]{{.}}.Scalar, error) {
+	xs := make([]{{.}}.Scalar, len(strs))
+	for i := range strs {
+		var err error
+		if xs[i], err = g.decodeScalar(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return xs, nil
+}
+
+func (g {{.}}Group) encodePoint(p *{{.}}.Point) string {
+	var bs [{{.}}.PointSize]byte
+	p.PutBytes(bs[:])
+	return hex.EncodeToString(bs[:])
+}
+
+func (g {{.}}Group) encodePoints(ps {{.}}.Commitment) []string {
+	strs := make([]string, len(ps))
+	for i := range ps {
+		strs[i] = g.encodePoint(&ps[i])
+	}
+	return strs
+}
+
+func (g {{.}}Group) decodePoint(str string) ({{.}}.Point, error) {
+	var p {{.}}.Point
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return p, err
+	}
+	if len(bs) != {{.}}.PointSize {
+		return p, fmt.Errorf("expected %v bytes for a point, got %v", {{.}}.PointSize, len(bs))
+	}
+	err = p.SetBytes(bs)
+	return p, err
+}
+
+func (g {{.}}Group) decodePoints(strs []string) ({{.}}.Commitment, error) {
+	ps := make({{.}}.Commitment, len(strs))
+	for i := range strs {
+		var err error
+		if ps[i], err = g.decodePoint(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+func (g {{.}}Group) encodeVShares(vshares {{.}}.VerifiableShares) []VShare {
+	vs := make([]VShare, len(vshares))
+	for i := range vshares {
+		vs[i] = VShare{
+			Index:        g.encodeScalar(&vshares[i].Share.Index),
+			Value:        g.encodeScalar(&vshares[i].Share.Value),
+			Decommitment: g.encodeScalar(&vshares[i].Decommitment),
+		}
+	}
+	return vs
+}
+
+func (g {{.}}Group) decodeVShares(vs []VShare) ({{.}}.VerifiableShares, error) {
+	vshares := make({{.}}.VerifiableShares, len(vs))
+	for i := range vs {
+		var err error
+		if vshares[i].Share.Index, err = g.decodeScalar(vs[i].Index); err != nil {
+			return nil, err
+		}
+		if vshares[i].Share.Value, err = g.decodeScalar(vs[i].Value); err != nil {
+			return nil, err
+		}
+		if vshares[i].Decommitment, err = g.decodeScalar(vs[i].Decommitment); err != nil {
+			return nil, err
+		}
+	}
+	return vshares, nil
+}
+`))
+
+func main() {
+	for _, pkg := range packages {
+		var buf bytes.Buffer
+		if err := groupTmpl.Execute(&buf, pkg); err != nil {
+			log.Fatal(err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile("group_"+pkg+".go", src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package testvectors
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+)
+
+// The (n, k) parameters for which vectors are generated.
+var params = [][2]int{{1, 1}, {3, 2}, {5, 3}, {10, 4}, {7, 7}}
+
+// Generate deterministically generates a set of vectors for secp256k1 from the
+// given seed. The same seed always gives the same vectors, so the vectors are
+// not suitable for anything other than testing.
+func Generate(seed int64) *Vectors {
+	g := generator{rng: rand.New(rand.NewSource(seed))}
+	h := shamir.PedersenH()
+	v := &Vectors{Version: Version, Curve: Curve}
+	for _, p := range params {
+		n, k := p[0], p[1]
+		v.Sharing = append(v.Sharing, g.sharing(n, k))
+		v.VSS = append(v.VSS, g.vss(&h, n, k))
+		v.Refresh = append(v.Refresh, g.refresh(&h, n, k))
+		// Decoding needs redundancy to correct any errors.
+		if n > k {
+			v.Decoding = append(v.Decoding, g.decoding(n, k))
+		}
+	}
+	return v
+}
+
+// GenerateForCurve is the same as Generate, but generates the vectors for the
+// curve with the given identifier, which must be one of Curves.
+func GenerateForCurve(curve string, seed int64) (*Vectors, error) {
+	if curve == Curve {
+		return Generate(seed), nil
+	}
+	g, ok := groups[curve]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+	return generateGroup(g, curve, seed)
+}
+
+type generator struct {
+	rng *rand.Rand
+}
+
+func (g *generator) fn() secp256k1.Fn {
+	var bs [32]byte
+	g.rng.Read(bs[:])
+	var x secp256k1.Fn
+	x.SetB32(bs[:])
+	return x
+}
+
+func (g *generator) fns(n int) []secp256k1.Fn {
+	xs := make([]secp256k1.Fn, n)
+	for i := range xs {
+		xs[i] = g.fn()
+	}
+	return xs
+}
+
+// Chooses at random between the sequential indices 1, 2, ..., n and random
+// indices, so that both common cases are covered.
+func (g *generator) indices(n int) []secp256k1.Fn {
+	if g.rng.Intn(2) == 0 {
+		indices := make([]secp256k1.Fn, n)
+		for i := range indices {
+			indices[i].SetU16(uint16(i + 1))
+		}
+		return indices
+	}
+	return g.fns(n)
+}
+
+func (g *generator) sharing(n, k int) SharingVector {
+	coeffs := g.fns(k)
+	indices := g.indices(n)
+	shares := make([]Share, n)
+	for i := range indices {
+		y := eval(coeffs, &indices[i])
+		shares[i] = Share{Index: encodeFn(&indices[i]), Value: encodeFn(&y)}
+	}
+	return SharingVector{
+		K:            k,
+		Secret:       encodeFn(&coeffs[0]),
+		Coefficients: encodeFns(coeffs),
+		Shares:       shares,
+	}
+}
+
+func (g *generator) vss(h *secp256k1.Point, n, k int) VSSVector {
+	coeffs, decomCoeffs := g.fns(k), g.fns(k)
+	indices := g.indices(n)
+	return VSSVector{
+		K:                        k,
+		H:                        encodePoint(h),
+		Coefficients:             encodeFns(coeffs),
+		DecommitmentCoefficients: encodeFns(decomCoeffs),
+		Commitment:               encodePoints(commit(h, coeffs, decomCoeffs)),
+		Shares:                   encodeVShares(evalVShares(indices, coeffs, decomCoeffs)),
+	}
+}
+
+func (g *generator) refresh(h *secp256k1.Point, n, k int) RefreshVector {
+	indices := g.indices(n)
+	coeffs, decomCoeffs := g.fns(k), g.fns(k)
+	zeroCoeffs, zeroDecomCoeffs := g.fns(k), g.fns(k)
+	zeroCoeffs[0].Clear()
+
+	c := shamir.Commitment(commit(h, coeffs, decomCoeffs))
+	zeroC := shamir.Commitment(commit(h, zeroCoeffs, zeroDecomCoeffs))
+	refreshedC := shamir.NewCommitmentWithCapacity(k)
+	refreshedC.Add(c, zeroC)
+
+	shares := evalVShares(indices, coeffs, decomCoeffs)
+	zeroShares := evalVShares(indices, zeroCoeffs, zeroDecomCoeffs)
+	refreshedShares := make(shamir.VerifiableShares, n)
+	for i := range refreshedShares {
+		refreshedShares[i].Add(&shares[i], &zeroShares[i])
+	}
+
+	return RefreshVector{
+		K:                   k,
+		H:                   encodePoint(h),
+		Commitment:          encodePoints(c),
+		Shares:              encodeVShares(shares),
+		ZeroCommitment:      encodePoints(zeroC),
+		ZeroShares:          encodeVShares(zeroShares),
+		RefreshedCommitment: encodePoints(refreshedC),
+		RefreshedShares:     encodeVShares(refreshedShares),
+	}
+}
+
+func (g *generator) decoding(n, k int) DecodingVector {
+	coeffs := g.fns(k)
+	indices := g.indices(n)
+	values := make([]secp256k1.Fn, n)
+	for i := range indices {
+		values[i] = eval(coeffs, &indices[i])
+	}
+
+	// Introduce the maximum number of errors that can be corrected.
+	var errorIndices []secp256k1.Fn
+	for _, i := range g.rng.Perm(n)[:(n-k)/2] {
+		offset := g.fn()
+		for offset.IsZero() {
+			offset = g.fn()
+		}
+		values[i].Add(&values[i], &offset)
+		errorIndices = append(errorIndices, indices[i])
+	}
+
+	return DecodingVector{
+		K:            k,
+		Indices:      encodeFns(indices),
+		Values:       encodeFns(values),
+		Coefficients: encodeFns(coeffs),
+		ErrorIndices: encodeFns(errorIndices),
+	}
+}
+
+// Evaluates the polynomial with the given coefficients at x.
+func eval(coeffs []secp256k1.Fn, x *secp256k1.Fn) secp256k1.Fn {
+	y := coeffs[len(coeffs)-1]
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		y.Mul(&y, x)
+		y.Add(&y, &coeffs[i])
+	}
+	return y
+}
+
+// Computes the Pedersen commitment to the given coefficients.
+func commit(h *secp256k1.Point, coeffs, decomCoeffs []secp256k1.Fn) []secp256k1.Point {
+	c := make([]secp256k1.Point, len(coeffs))
+	var hPow secp256k1.Point
+	for i := range c {
+		c[i].BaseExp(&coeffs[i])
+		hPow.Scale(h, &decomCoeffs[i])
+		c[i].Add(&c[i], &hPow)
+	}
+	return c
+}
+
+func evalVShares(indices, coeffs, decomCoeffs []secp256k1.Fn) shamir.VerifiableShares {
+	vshares := make(shamir.VerifiableShares, len(indices))
+	for i := range indices {
+		vshares[i] = shamir.NewVerifiableShare(
+			shamir.NewShare(indices[i], eval(coeffs, &indices[i])),
+			eval(decomCoeffs, &indices[i]),
+		)
+	}
+	return vshares
+}
+
+func encodeVShares(vshares shamir.VerifiableShares) []VShare {
+	vs := make([]VShare, len(vshares))
+	for i := range vshares {
+		vs[i] = VShare{
+			Index:        encodeFn(&vshares[i].Share.Index),
+			Value:        encodeFn(&vshares[i].Share.Value),
+			Decommitment: encodeFn(&vshares[i].Decommitment),
+		}
+	}
+	return vs
+}
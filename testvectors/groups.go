@@ -0,0 +1,313 @@
+package testvectors
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+//go:generate go run gen_groups.go
+
+// A group does the arithmetic that the vectors for one of the curves other
+// than secp256k1 need, on hex encoded scalars and points. The implementations
+// for the packages of those curves are generated by gen_groups.go.
+type group interface {
+	// randomScalar returns a scalar sampled with the given generator.
+	randomScalar(rng *rand.Rand) string
+	// smallScalar returns the scalar equal to the given value.
+	smallScalar(v uint16) string
+	// pedersenH returns the Pedersen parameter of the package.
+	pedersenH() string
+
+	// share evaluates the polynomial with the given coefficients at each of
+	// the indices.
+	share(coeffs, indices []string) ([]Share, error)
+	// vshare returns the commitment and the verifiable shares of the sharing
+	// with the given polynomials.
+	vshare(h string, coeffs, decomCoeffs, indices []string) ([]string, []VShare, error)
+	// checkValid returns an error if any of the shares is not valid for the
+	// commitment.
+	checkValid(h string, c []string, vshares []VShare) error
+	// open reconstructs the secret from the given shares.
+	open(shares []Share) (string, error)
+	// add returns the sum of two verifiable sharings.
+	add(c1 []string, vshares1 []VShare, c2 []string, vshares2 []VShare) ([]string, []VShare, error)
+}
+
+// The groups of the curves other than secp256k1, by curve identifier.
+var groups = map[string]group{
+	"p256":         p256Group{},
+	"bn254":        bn254Group{},
+	"ristretto255": ristretto255Group{},
+}
+
+func generateGroup(g group, curve string, seed int64) (*Vectors, error) {
+	gg := groupGenerator{g: g, rng: rand.New(rand.NewSource(seed))}
+	h := g.pedersenH()
+	v := &Vectors{Version: Version, Curve: curve}
+	for _, p := range params {
+		n, k := p[0], p[1]
+		sharing, err := gg.sharing(n, k)
+		if err != nil {
+			return nil, err
+		}
+		vss, err := gg.vss(h, n, k)
+		if err != nil {
+			return nil, err
+		}
+		refresh, err := gg.refresh(h, n, k)
+		if err != nil {
+			return nil, err
+		}
+		v.Sharing = append(v.Sharing, sharing)
+		v.VSS = append(v.VSS, vss)
+		v.Refresh = append(v.Refresh, refresh)
+	}
+	return v, nil
+}
+
+type groupGenerator struct {
+	g   group
+	rng *rand.Rand
+}
+
+func (gg *groupGenerator) scalars(n int) []string {
+	xs := make([]string, n)
+	for i := range xs {
+		xs[i] = gg.g.randomScalar(gg.rng)
+	}
+	return xs
+}
+
+// Chooses at random between the sequential indices 1, 2, ..., n and random
+// indices, as for the secp256k1 vectors.
+func (gg *groupGenerator) indices(n int) []string {
+	if gg.rng.Intn(2) == 0 {
+		indices := make([]string, n)
+		for i := range indices {
+			indices[i] = gg.g.smallScalar(uint16(i + 1))
+		}
+		return indices
+	}
+	return gg.scalars(n)
+}
+
+func (gg *groupGenerator) sharing(n, k int) (SharingVector, error) {
+	coeffs := gg.scalars(k)
+	shares, err := gg.g.share(coeffs, gg.indices(n))
+	if err != nil {
+		return SharingVector{}, err
+	}
+	return SharingVector{K: k, Secret: coeffs[0], Coefficients: coeffs, Shares: shares}, nil
+}
+
+func (gg *groupGenerator) vss(h string, n, k int) (VSSVector, error) {
+	coeffs, decomCoeffs := gg.scalars(k), gg.scalars(k)
+	c, vshares, err := gg.g.vshare(h, coeffs, decomCoeffs, gg.indices(n))
+	if err != nil {
+		return VSSVector{}, err
+	}
+	return VSSVector{
+		K:                        k,
+		H:                        h,
+		Coefficients:             coeffs,
+		DecommitmentCoefficients: decomCoeffs,
+		Commitment:               c,
+		Shares:                   vshares,
+	}, nil
+}
+
+func (gg *groupGenerator) refresh(h string, n, k int) (RefreshVector, error) {
+	indices := gg.indices(n)
+	coeffs, decomCoeffs := gg.scalars(k), gg.scalars(k)
+	zeroCoeffs, zeroDecomCoeffs := gg.scalars(k), gg.scalars(k)
+	zeroCoeffs[0] = gg.g.smallScalar(0)
+
+	c, shares, err := gg.g.vshare(h, coeffs, decomCoeffs, indices)
+	if err != nil {
+		return RefreshVector{}, err
+	}
+	zeroC, zeroShares, err := gg.g.vshare(h, zeroCoeffs, zeroDecomCoeffs, indices)
+	if err != nil {
+		return RefreshVector{}, err
+	}
+	refreshedC, refreshedShares, err := gg.g.add(c, shares, zeroC, zeroShares)
+	if err != nil {
+		return RefreshVector{}, err
+	}
+	return RefreshVector{
+		K:                   k,
+		H:                   h,
+		Commitment:          c,
+		Shares:              shares,
+		ZeroCommitment:      zeroC,
+		ZeroShares:          zeroShares,
+		RefreshedCommitment: refreshedC,
+		RefreshedShares:     refreshedShares,
+	}, nil
+}
+
+func verifyGroup(g group, v *Vectors) error {
+	if len(v.Decoding) != 0 {
+		return fmt.Errorf("decoding vectors are only defined for %v", Curve)
+	}
+	for i := range v.Sharing {
+		if err := verifyGroupSharing(g, &v.Sharing[i]); err != nil {
+			return fmt.Errorf("sharing %v: %v", i, err)
+		}
+	}
+	for i := range v.VSS {
+		if err := verifyGroupVSS(g, &v.VSS[i]); err != nil {
+			return fmt.Errorf("vss %v: %v", i, err)
+		}
+	}
+	for i := range v.Refresh {
+		if err := verifyGroupRefresh(g, &v.Refresh[i]); err != nil {
+			return fmt.Errorf("refresh %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func verifyGroupSharing(g group, v *SharingVector) error {
+	if err := checkThreshold(v.K, len(v.Coefficients), len(v.Shares)); err != nil {
+		return err
+	}
+	indices := make([]string, len(v.Shares))
+	for i := range v.Shares {
+		indices[i] = v.Shares[i].Index
+	}
+	expected, err := g.share(v.Coefficients, indices)
+	if err != nil {
+		return err
+	}
+	for i := range v.Shares {
+		if !strings.EqualFold(v.Shares[i].Value, expected[i].Value) {
+			return fmt.Errorf("share %v is not an evaluation of the polynomial", i)
+		}
+	}
+	if !strings.EqualFold(v.Coefficients[0], v.Secret) {
+		return fmt.Errorf("secret is not the constant coefficient")
+	}
+	opened, err := g.open(v.Shares[:v.K])
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(opened, v.Secret) {
+		return fmt.Errorf("shares do not open to the secret")
+	}
+	return nil
+}
+
+func verifyGroupVSS(g group, v *VSSVector) error {
+	if err := checkThreshold(v.K, len(v.Coefficients), len(v.Shares)); err != nil {
+		return err
+	}
+	c, vshares, err := g.vshare(v.H, v.Coefficients, v.DecommitmentCoefficients, vsharesIndexStrings(v.Shares))
+	if err != nil {
+		return err
+	}
+	if !equalFolds(c, v.Commitment) {
+		return fmt.Errorf("commitment does not match the coefficients")
+	}
+	for i := range vshares {
+		if !equalVShares(&vshares[i], &v.Shares[i]) {
+			return fmt.Errorf("share %v is not an evaluation of the polynomials", i)
+		}
+	}
+	return g.checkValid(v.H, v.Commitment, v.Shares)
+}
+
+func verifyGroupRefresh(g group, v *RefreshVector) error {
+	for _, s := range []struct {
+		c       []string
+		vshares []VShare
+	}{
+		{v.Commitment, v.Shares},
+		{v.ZeroCommitment, v.ZeroShares},
+		{v.RefreshedCommitment, v.RefreshedShares},
+	} {
+		if err := checkThreshold(v.K, len(s.c), len(s.vshares)); err != nil {
+			return err
+		}
+		if err := g.checkValid(v.H, s.c, s.vshares); err != nil {
+			return err
+		}
+	}
+	if len(v.ZeroShares) != len(v.Shares) || len(v.RefreshedShares) != len(v.Shares) {
+		return fmt.Errorf("sharings have different numbers of shares")
+	}
+	for i := range v.Shares {
+		if !strings.EqualFold(v.Shares[i].Index, v.ZeroShares[i].Index) {
+			return fmt.Errorf("share %v of the zero sharing has a different index", i)
+		}
+	}
+
+	sum, sumShares, err := g.add(v.Commitment, v.Shares, v.ZeroCommitment, v.ZeroShares)
+	if err != nil {
+		return err
+	}
+	if !equalFolds(sum, v.RefreshedCommitment) {
+		return fmt.Errorf("refreshed commitment is not the sum of the commitments")
+	}
+	for i := range sumShares {
+		if !equalVShares(&sumShares[i], &v.RefreshedShares[i]) {
+			return fmt.Errorf("refreshed share %v is not the sum of the shares", i)
+		}
+	}
+
+	zero, err := g.open(vsharesShares(v.ZeroShares[:v.K]))
+	if err != nil {
+		return err
+	}
+	if zero != g.smallScalar(0) {
+		return fmt.Errorf("zero sharing does not open to zero")
+	}
+	secret, err := g.open(vsharesShares(v.Shares[:v.K]))
+	if err != nil {
+		return err
+	}
+	refreshed, err := g.open(vsharesShares(v.RefreshedShares[:v.K]))
+	if err != nil {
+		return err
+	}
+	if refreshed != secret {
+		return fmt.Errorf("refreshed sharing does not open to the original secret")
+	}
+	return nil
+}
+
+// Returns true if the two slices of hex strings are equal, ignoring case.
+func equalFolds(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalVShares(a, b *VShare) bool {
+	return strings.EqualFold(a.Index, b.Index) &&
+		strings.EqualFold(a.Value, b.Value) &&
+		strings.EqualFold(a.Decommitment, b.Decommitment)
+}
+
+func vsharesIndexStrings(vs []VShare) []string {
+	indices := make([]string, len(vs))
+	for i := range vs {
+		indices[i] = vs[i].Index
+	}
+	return indices
+}
+
+func vsharesShares(vs []VShare) []Share {
+	shares := make([]Share, len(vs))
+	for i := range vs {
+		shares[i] = Share{Index: vs[i].Index, Value: vs[i].Value}
+	}
+	return shares
+}
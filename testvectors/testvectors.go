@@ -0,0 +1,231 @@
+// Package testvectors produces and checks canonical test vectors for the
+// sharing, verifiable sharing, share refresh and Reed-Solomon decoding
+// functionality of this module, so that implementations in other languages can
+// test that they interoperate with it.
+//
+// The vectors are serialised as JSON. Every scalar is a hex encoded 32 byte
+// big endian integer that is less than the order of the secp256k1 group, and
+// every curve point is a hex encoded 33 byte string: a byte that is 0 or 1
+// according to the parity of the y coordinate, followed by the 32 byte big
+// endian x coordinate, or 0xff followed by 32 zero bytes for the point at
+// infinity. This is the encoding used by the Marshal methods of this module.
+// Note that the first byte differs from the SEC 1 compressed encoding, which
+// uses 2 and 3.
+//
+// Sharing, verifiable sharing and refresh vectors are also produced for the
+// p256, bn254 and ristretto255 packages, with the curve identifier set to the
+// name of the package. Their scalars and points are hex encoded with the
+// PutBytes methods of those packages: 32 byte big endian scalars and 33 byte
+// SEC 1 compressed points for p256, 32 byte big endian scalars and 64 byte
+// EIP-196 points for bn254, and 32 byte little endian scalars and 32 byte
+// canonical ristretto255 encodings for ristretto255. Reed-Solomon decoding is
+// only implemented over secp256k1, so there are no decoding vectors for these
+// curves.
+//
+// The vectors are versioned. Any change to the format or to the vectors that
+// are generated for a given seed is accompanied by a new version.
+package testvectors
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Version is the version of the format of the vectors produced by this
+// package.
+const Version = 1
+
+// Curve is the identifier of secp256k1, the curve of the vectors produced by
+// Generate.
+const Curve = "secp256k1"
+
+// Curves are the identifiers of all curves that vectors can be produced for.
+var Curves = []string{Curve, "p256", "bn254", "ristretto255"}
+
+// DefaultSeed is the seed used to generate the published vectors in
+// testdata/v1.json for secp256k1, and testdata/v1-<curve>.json for the other
+// curves, which can be regenerated by running the tests of this package with
+// the -update flag.
+const DefaultSeed = 1
+
+// Vectors is a versioned set of test vectors.
+type Vectors struct {
+	Version  int              `json:"version"`
+	Curve    string           `json:"curve"`
+	Sharing  []SharingVector  `json:"sharing"`
+	VSS      []VSSVector      `json:"vss"`
+	Refresh  []RefreshVector  `json:"refresh"`
+	Decoding []DecodingVector `json:"decoding,omitempty"`
+}
+
+// Share is a share with hex encoded fields.
+type Share struct {
+	Index string `json:"index"`
+	Value string `json:"value"`
+}
+
+// VShare is a verifiable share with hex encoded fields.
+type VShare struct {
+	Index        string `json:"index"`
+	Value        string `json:"value"`
+	Decommitment string `json:"decommitment"`
+}
+
+// SharingVector is a Shamir sharing of a secret with threshold K. The shares
+// are the evaluations of the polynomial with the given coefficients, the first
+// of which is the secret, and any K of them open to the secret.
+type SharingVector struct {
+	K            int      `json:"k"`
+	Secret       string   `json:"secret"`
+	Coefficients []string `json:"coefficients"`
+	Shares       []Share  `json:"shares"`
+}
+
+// VSSVector is a Pedersen verifiable sharing with threshold K and Pedersen
+// parameter H. The i-th point of the commitment is c_i G + d_i H, where c_i
+// and d_i are the i-th coefficients of the sharing and decommitment
+// polynomials, and the values and decommitments of the shares are the
+// evaluations of these polynomials.
+type VSSVector struct {
+	K                        int      `json:"k"`
+	H                        string   `json:"h"`
+	Coefficients             []string `json:"coefficients"`
+	DecommitmentCoefficients []string `json:"decommitmentCoefficients"`
+	Commitment               []string `json:"commitment"`
+	Shares                   []VShare `json:"shares"`
+}
+
+// RefreshVector is a proactive refresh of a verifiable sharing with threshold
+// K and Pedersen parameter H: a verifiable sharing of zero is added to the
+// original sharing, share by share and point by point, which gives new shares
+// of the same secret.
+type RefreshVector struct {
+	K                   int      `json:"k"`
+	H                   string   `json:"h"`
+	Commitment          []string `json:"commitment"`
+	Shares              []VShare `json:"shares"`
+	ZeroCommitment      []string `json:"zeroCommitment"`
+	ZeroShares          []VShare `json:"zeroShares"`
+	RefreshedCommitment []string `json:"refreshedCommitment"`
+	RefreshedShares     []VShare `json:"refreshedShares"`
+}
+
+// DecodingVector is a Reed-Solomon codeword with errors. The values are the
+// evaluations at the indices of the polynomial with the given coefficients,
+// which has degree less than K, except at the error indices. Decoding the
+// values gives the polynomial and the error indices.
+type DecodingVector struct {
+	K            int      `json:"k"`
+	Indices      []string `json:"indices"`
+	Values       []string `json:"values"`
+	Coefficients []string `json:"coefficients"`
+	ErrorIndices []string `json:"errorIndices"`
+}
+
+// Encode writes the vectors to the given writer as indented JSON. The output
+// is canonical: equal vectors are always encoded to the same bytes.
+func Encode(w io.Writer, v *Vectors) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Decode reads vectors from the given reader, and returns an error if they are
+// not valid JSON or are for a version or curve that is not supported.
+func Decode(r io.Reader) (*Vectors, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	v := new(Vectors)
+	if err := dec.Decode(v); err != nil {
+		return nil, err
+	}
+	if v.Version != Version {
+		return nil, fmt.Errorf("unsupported version %v", v.Version)
+	}
+	if _, ok := groups[v.Curve]; !ok && v.Curve != Curve {
+		return nil, fmt.Errorf("unsupported curve %q", v.Curve)
+	}
+	return v, nil
+}
+
+func encodeFn(x *secp256k1.Fn) string {
+	var bs [32]byte
+	x.PutB32(bs[:])
+	return hex.EncodeToString(bs[:])
+}
+
+func encodeFns(xs []secp256k1.Fn) []string {
+	strs := make([]string, len(xs))
+	for i := range xs {
+		strs[i] = encodeFn(&xs[i])
+	}
+	return strs
+}
+
+func encodePoint(p *secp256k1.Point) string {
+	var bs [secp256k1.PointSizeMarshalled]byte
+	p.PutBytes(bs[:])
+	return hex.EncodeToString(bs[:])
+}
+
+func encodePoints(ps []secp256k1.Point) []string {
+	strs := make([]string, len(ps))
+	for i := range ps {
+		strs[i] = encodePoint(&ps[i])
+	}
+	return strs
+}
+
+func decodeFn(str string) (secp256k1.Fn, error) {
+	var x secp256k1.Fn
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return x, err
+	}
+	if len(bs) != 32 {
+		return x, fmt.Errorf("expected 32 bytes for a scalar, got %v", len(bs))
+	}
+	if x.SetB32(bs) {
+		return x, fmt.Errorf("scalar %v is not less than the group order", str)
+	}
+	return x, nil
+}
+
+func decodeFns(strs []string) ([]secp256k1.Fn, error) {
+	xs := make([]secp256k1.Fn, len(strs))
+	for i := range strs {
+		var err error
+		if xs[i], err = decodeFn(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return xs, nil
+}
+
+func decodePoint(str string) (secp256k1.Point, error) {
+	var p secp256k1.Point
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return p, err
+	}
+	if len(bs) != secp256k1.PointSizeMarshalled {
+		return p, fmt.Errorf("expected %v bytes for a point, got %v", secp256k1.PointSizeMarshalled, len(bs))
+	}
+	err = p.SetBytes(bs)
+	return p, err
+}
+
+func decodePoints(strs []string) ([]secp256k1.Point, error) {
+	ps := make([]secp256k1.Point, len(strs))
+	for i := range strs {
+		var err error
+		if ps[i], err = decodePoint(strs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
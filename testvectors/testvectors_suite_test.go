@@ -0,0 +1,13 @@
+package testvectors_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTestvectors(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Testvectors Suite")
+}
@@ -0,0 +1,195 @@
+package testvectors_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/testvectors"
+)
+
+var update = flag.Bool("update", false, "regenerate the published test vectors")
+
+var _ = Describe("Test vectors", func() {
+	path := filepath.Join("testdata", "v1.json")
+
+	encode := func(v *Vectors) []byte {
+		var buf bytes.Buffer
+		Expect(Encode(&buf, v)).To(Succeed())
+		return buf.Bytes()
+	}
+
+	// Replaces the last hex digit of the given string.
+	tamper := func(str *string) {
+		last := (*str)[len(*str)-1]
+		replacement := "0"
+		if last == '0' {
+			replacement = "1"
+		}
+		*str = (*str)[:len(*str)-1] + replacement
+	}
+
+	It("should generate the published vectors", func() {
+		generated := encode(Generate(DefaultSeed))
+		if *update {
+			Expect(ioutil.WriteFile(path, generated, 0644)).To(Succeed())
+		}
+		published, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(generated)).To(Equal(string(published)))
+	})
+
+	It("should verify the published vectors", func() {
+		published, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		v, err := Decode(bytes.NewReader(published))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(Verify(v)).To(Succeed())
+		Expect(v.Sharing).ToNot(BeEmpty())
+		Expect(v.VSS).ToNot(BeEmpty())
+		Expect(v.Refresh).ToNot(BeEmpty())
+		Expect(v.Decoding).ToNot(BeEmpty())
+	})
+
+	It("should generate vectors that verify for other seeds", func() {
+		for seed := int64(2); seed < 5; seed++ {
+			v := Generate(seed)
+			Expect(Verify(v)).To(Succeed())
+			roundTripped, err := Decode(bytes.NewReader(encode(v)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(roundTripped).To(Equal(v))
+		}
+	})
+
+	for _, curve := range Curves[1:] {
+		curve := curve
+
+		Context(fmt.Sprintf("for %v", curve), func() {
+			path := filepath.Join("testdata", fmt.Sprintf("v1-%v.json", curve))
+
+			generate := func(seed int64) *Vectors {
+				v, err := GenerateForCurve(curve, seed)
+				Expect(err).ToNot(HaveOccurred())
+				return v
+			}
+
+			It("should generate the published vectors", func() {
+				generated := encode(generate(DefaultSeed))
+				if *update {
+					Expect(ioutil.WriteFile(path, generated, 0644)).To(Succeed())
+				}
+				published, err := ioutil.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(generated)).To(Equal(string(published)))
+			})
+
+			It("should verify the published vectors", func() {
+				published, err := ioutil.ReadFile(path)
+				Expect(err).ToNot(HaveOccurred())
+				v, err := Decode(bytes.NewReader(published))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v.Curve).To(Equal(curve))
+				Expect(Verify(v)).To(Succeed())
+				Expect(v.Sharing).ToNot(BeEmpty())
+				Expect(v.VSS).ToNot(BeEmpty())
+				Expect(v.Refresh).ToNot(BeEmpty())
+				Expect(v.Decoding).To(BeEmpty())
+			})
+
+			It("should generate vectors that verify for other seeds", func() {
+				for seed := int64(2); seed < 5; seed++ {
+					v := generate(seed)
+					Expect(Verify(v)).To(Succeed())
+					roundTripped, err := Decode(bytes.NewReader(encode(v)))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(roundTripped).To(Equal(v))
+				}
+			})
+
+			It("should detect modified vectors", func() {
+				v := generate(DefaultSeed)
+				tamper(&v.Sharing[1].Shares[0].Value)
+				Expect(Verify(v)).ToNot(Succeed())
+
+				v = generate(DefaultSeed)
+				v.VSS[2].Commitment[0] = v.VSS[2].Commitment[1]
+				Expect(Verify(v)).ToNot(Succeed())
+
+				v = generate(DefaultSeed)
+				tamper(&v.Refresh[2].RefreshedShares[1].Decommitment)
+				Expect(Verify(v)).ToNot(Succeed())
+
+				v = generate(DefaultSeed)
+				v.Decoding = Generate(DefaultSeed).Decoding
+				Expect(Verify(v)).ToNot(Succeed())
+			})
+
+			It("should not verify as vectors for another curve", func() {
+				v := generate(DefaultSeed)
+				v.Curve = Curve
+				Expect(Verify(v)).ToNot(Succeed())
+			})
+		})
+	}
+
+	It("should return an error when generating for an unsupported curve", func() {
+		_, err := GenerateForCurve("ed25519", DefaultSeed)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("when the vectors are modified", func() {
+		It("should detect a modified share", func() {
+			v := Generate(DefaultSeed)
+			tamper(&v.Sharing[1].Shares[0].Value)
+			Expect(Verify(v)).ToNot(Succeed())
+		})
+
+		It("should detect a modified commitment", func() {
+			v := Generate(DefaultSeed)
+			v.VSS[2].Commitment[0] = v.VSS[2].Commitment[1]
+			Expect(Verify(v)).ToNot(Succeed())
+		})
+
+		It("should detect a modified refresh", func() {
+			v := Generate(DefaultSeed)
+			tamper(&v.Refresh[2].RefreshedShares[1].Decommitment)
+			Expect(Verify(v)).ToNot(Succeed())
+		})
+
+		It("should detect modified error indices", func() {
+			v := Generate(DefaultSeed)
+			for i := range v.Decoding {
+				if len(v.Decoding[i].ErrorIndices) > 0 {
+					v.Decoding[i].ErrorIndices = v.Decoding[i].ErrorIndices[1:]
+					break
+				}
+			}
+			Expect(Verify(v)).ToNot(Succeed())
+		})
+
+		It("should reject unsupported versions and curves", func() {
+			v := Generate(DefaultSeed)
+			v.Version++
+			Expect(Verify(v)).ToNot(Succeed())
+			_, err := Decode(bytes.NewReader(encode(v)))
+			Expect(err).To(HaveOccurred())
+
+			v = Generate(DefaultSeed)
+			v.Curve = "ed25519"
+			Expect(Verify(v)).ToNot(Succeed())
+			_, err = Decode(bytes.NewReader(encode(v)))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject scalars that are not canonical", func() {
+			v := Generate(DefaultSeed)
+			v.Sharing[0].Secret = strings.Repeat("ff", 32)
+			Expect(Verify(v)).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,275 @@
+package testvectors
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir"
+	"github.com/renproject/shamir/poly"
+	"github.com/renproject/shamir/rs"
+)
+
+// Verify replays the given vectors against this module, and returns an error
+// describing the first vector that does not hold.
+func Verify(v *Vectors) error {
+	if v.Version != Version {
+		return fmt.Errorf("unsupported version %v", v.Version)
+	}
+	if g, ok := groups[v.Curve]; ok {
+		return verifyGroup(g, v)
+	}
+	if v.Curve != Curve {
+		return fmt.Errorf("unsupported curve %q", v.Curve)
+	}
+	for i := range v.Sharing {
+		if err := verifySharing(&v.Sharing[i]); err != nil {
+			return fmt.Errorf("sharing %v: %v", i, err)
+		}
+	}
+	for i := range v.VSS {
+		if err := verifyVSS(&v.VSS[i]); err != nil {
+			return fmt.Errorf("vss %v: %v", i, err)
+		}
+	}
+	for i := range v.Refresh {
+		if err := verifyRefresh(&v.Refresh[i]); err != nil {
+			return fmt.Errorf("refresh %v: %v", i, err)
+		}
+	}
+	for i := range v.Decoding {
+		if err := verifyDecoding(&v.Decoding[i]); err != nil {
+			return fmt.Errorf("decoding %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func verifySharing(v *SharingVector) error {
+	secret, err := decodeFn(v.Secret)
+	if err != nil {
+		return err
+	}
+	coeffs, err := decodeFns(v.Coefficients)
+	if err != nil {
+		return err
+	}
+	if err := checkThreshold(v.K, len(coeffs), len(v.Shares)); err != nil {
+		return err
+	}
+	if !coeffs[0].Eq(&secret) {
+		return fmt.Errorf("secret is not the constant coefficient")
+	}
+	shares := make(shamir.Shares, len(v.Shares))
+	for i := range v.Shares {
+		if shares[i].Index, err = decodeFn(v.Shares[i].Index); err != nil {
+			return err
+		}
+		if shares[i].Value, err = decodeFn(v.Shares[i].Value); err != nil {
+			return err
+		}
+		if y := eval(coeffs, &shares[i].Index); !y.Eq(&shares[i].Value) {
+			return fmt.Errorf("share %v is not an evaluation of the polynomial", i)
+		}
+	}
+	if opened := shamir.Open(shares[:v.K]); !opened.Eq(&secret) {
+		return fmt.Errorf("shares do not open to the secret")
+	}
+	return nil
+}
+
+func verifyVSS(v *VSSVector) error {
+	h, err := decodePoint(v.H)
+	if err != nil {
+		return err
+	}
+	coeffs, err := decodeFns(v.Coefficients)
+	if err != nil {
+		return err
+	}
+	decomCoeffs, err := decodeFns(v.DecommitmentCoefficients)
+	if err != nil {
+		return err
+	}
+	if len(decomCoeffs) != len(coeffs) {
+		return fmt.Errorf("expected %v decommitment coefficients, got %v", len(coeffs), len(decomCoeffs))
+	}
+	c, err := decodePoints(v.Commitment)
+	if err != nil {
+		return err
+	}
+	if !shamir.Commitment(c).Eq(commit(&h, coeffs, decomCoeffs)) {
+		return fmt.Errorf("commitment does not match the coefficients")
+	}
+	vshares, err := decodeVShares(v.Shares)
+	if err != nil {
+		return err
+	}
+	if err := checkThreshold(v.K, len(coeffs), len(vshares)); err != nil {
+		return err
+	}
+	expected := evalVShares(vsharesIndices(vshares), coeffs, decomCoeffs)
+	for i := range vshares {
+		if !vshares[i].Eq(&expected[i]) {
+			return fmt.Errorf("share %v is not an evaluation of the polynomials", i)
+		}
+	}
+	return checkValid(&h, c, vshares)
+}
+
+func verifyRefresh(v *RefreshVector) error {
+	h, err := decodePoint(v.H)
+	if err != nil {
+		return err
+	}
+	type sharing struct {
+		c       shamir.Commitment
+		vshares shamir.VerifiableShares
+	}
+	var sharings [3]sharing
+	for i, s := range []struct {
+		c       []string
+		vshares []VShare
+	}{
+		{v.Commitment, v.Shares},
+		{v.ZeroCommitment, v.ZeroShares},
+		{v.RefreshedCommitment, v.RefreshedShares},
+	} {
+		if sharings[i].c, err = decodePoints(s.c); err != nil {
+			return err
+		}
+		if sharings[i].vshares, err = decodeVShares(s.vshares); err != nil {
+			return err
+		}
+		if err := checkThreshold(v.K, len(sharings[i].c), len(sharings[i].vshares)); err != nil {
+			return err
+		}
+		if err := checkValid(&h, sharings[i].c, sharings[i].vshares); err != nil {
+			return err
+		}
+	}
+	original, zero, refreshed := sharings[0], sharings[1], sharings[2]
+
+	sum := shamir.NewCommitmentWithCapacity(v.K)
+	sum.Add(original.c, zero.c)
+	if !sum.Eq(refreshed.c) {
+		return fmt.Errorf("refreshed commitment is not the sum of the commitments")
+	}
+	if len(zero.vshares) != len(original.vshares) || len(refreshed.vshares) != len(original.vshares) {
+		return fmt.Errorf("sharings have different numbers of shares")
+	}
+	for i := range original.vshares {
+		if !original.vshares[i].Share.IndexEq(&zero.vshares[i].Share.Index) {
+			return fmt.Errorf("share %v of the zero sharing has a different index", i)
+		}
+		var expected shamir.VerifiableShare
+		expected.Add(&original.vshares[i], &zero.vshares[i])
+		if !expected.Eq(&refreshed.vshares[i]) {
+			return fmt.Errorf("refreshed share %v is not the sum of the shares", i)
+		}
+	}
+	if opened := shamir.Open(zero.vshares.Shares()[:v.K]); !opened.IsZero() {
+		return fmt.Errorf("zero sharing does not open to zero")
+	}
+	secret := shamir.Open(original.vshares.Shares()[:v.K])
+	if opened := shamir.Open(refreshed.vshares.Shares()[:v.K]); !opened.Eq(&secret) {
+		return fmt.Errorf("refreshed sharing does not open to the original secret")
+	}
+	return nil
+}
+
+func verifyDecoding(v *DecodingVector) error {
+	indices, err := decodeFns(v.Indices)
+	if err != nil {
+		return err
+	}
+	values, err := decodeFns(v.Values)
+	if err != nil {
+		return err
+	}
+	coeffs, err := decodeFns(v.Coefficients)
+	if err != nil {
+		return err
+	}
+	errorIndices, err := decodeFns(v.ErrorIndices)
+	if err != nil {
+		return err
+	}
+	if len(values) != len(indices) {
+		return fmt.Errorf("expected %v values, got %v", len(indices), len(values))
+	}
+	if err := checkThreshold(v.K, len(coeffs), len(indices)); err != nil {
+		return err
+	}
+
+	decoder := rs.NewDecoder(indices, v.K)
+	p, ok := decoder.Decode(values)
+	if !ok {
+		return fmt.Errorf("codeword could not be decoded")
+	}
+	if !p.Eq(poly.NewFromSlice(coeffs)) {
+		return fmt.Errorf("decoded polynomial does not match the coefficients")
+	}
+	found := decoder.ErrorIndices()
+	if len(found) != len(errorIndices) {
+		return fmt.Errorf("expected %v errors, found %v", len(errorIndices), len(found))
+	}
+	for i := range errorIndices {
+		if !contains(found, &errorIndices[i]) {
+			return fmt.Errorf("error at index %v was not found", v.ErrorIndices[i])
+		}
+	}
+	return nil
+}
+
+func checkThreshold(k, coeffs, shares int) error {
+	if k < 1 || k != coeffs {
+		return fmt.Errorf("threshold %v does not match the %v coefficients", k, coeffs)
+	}
+	if shares < k {
+		return fmt.Errorf("threshold %v is larger than the %v shares", k, shares)
+	}
+	return nil
+}
+
+func checkValid(h *secp256k1.Point, c shamir.Commitment, vshares shamir.VerifiableShares) error {
+	for i := range vshares {
+		if !shamir.IsValid(*h, &c, &vshares[i]) {
+			return fmt.Errorf("share %v is not valid for the commitment", i)
+		}
+	}
+	return nil
+}
+
+func decodeVShares(vs []VShare) (shamir.VerifiableShares, error) {
+	vshares := make(shamir.VerifiableShares, len(vs))
+	for i := range vs {
+		var err error
+		if vshares[i].Share.Index, err = decodeFn(vs[i].Index); err != nil {
+			return nil, err
+		}
+		if vshares[i].Share.Value, err = decodeFn(vs[i].Value); err != nil {
+			return nil, err
+		}
+		if vshares[i].Decommitment, err = decodeFn(vs[i].Decommitment); err != nil {
+			return nil, err
+		}
+	}
+	return vshares, nil
+}
+
+func vsharesIndices(vshares shamir.VerifiableShares) []secp256k1.Fn {
+	indices := make([]secp256k1.Fn, len(vshares))
+	for i := range vshares {
+		indices[i] = vshares[i].Share.Index
+	}
+	return indices
+}
+
+func contains(xs []secp256k1.Fn, x *secp256k1.Fn) bool {
+	for i := range xs {
+		if xs[i].Eq(x) {
+			return true
+		}
+	}
+	return false
+}
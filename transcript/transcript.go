@@ -0,0 +1,154 @@
+// Package transcript implements Fiat-Shamir transcripts based on SHA-256. A
+// transcript absorbs the messages of a public coin protocol, each with a
+// label, and derives challenges that depend on every message absorbed before
+// them, so that a non-interactive proof built with it is bound to its whole
+// context. The design follows Merlin: the protocol is named when the
+// transcript is created, every message and challenge is labelled, and
+// deriving a challenge also absorbs it, so later challenges depend on earlier
+// ones.
+//
+// The state is a 32 byte chaining value h. Absorbing a message m with label l
+// sets
+//
+//	h = SHA-256(h || op || len(l) || l || len(m) || m)
+//
+// where op is a byte that identifies the operation and the lengths are big
+// endian 32 bit integers, so that no two different sequences of operations
+// give the same encoding. The initial value of h is the hash of the name of
+// the protocol, absorbed in the same way into the all zero value. The i-th
+// 32 byte block of a challenge of n bytes with label l is
+//
+//	SHA-256(h || op || len(l) || l || n || i)
+//
+// and the challenge is then absorbed as a message with label l.
+//
+// Scalars and points are absorbed in the canonical encodings of the
+// secp256k1 package, that is, 32 byte big endian scalars and 33 byte
+// compressed points.
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+
+	"github.com/renproject/secp256k1"
+)
+
+// Identifies the operation that is absorbed into the state.
+const (
+	opInit byte = iota
+	opMessage
+	opChallenge
+	opChallengeBlock
+)
+
+// A Transcript is the state of a Fiat-Shamir transcript. The zero value is
+// not valid; transcripts are created with New. A Transcript is a value, so
+// copying it forks the transcript: the copy and the original can absorb
+// different messages independently. It must not be used by more than one
+// goroutine at a time.
+type Transcript struct {
+	state [sha256.Size]byte
+}
+
+// New returns a transcript for the protocol with the given name, which
+// separates its challenges from those of any other protocol. The name should
+// be unique to the protocol and its version, for example
+// "renproject/shamir/dealer proof v1".
+func New(protocol string) Transcript {
+	var t Transcript
+	t.absorb(opInit, []byte(protocol), nil)
+	return t
+}
+
+// AppendMessage absorbs the message with the given label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.absorb(opMessage, []byte(label), msg)
+}
+
+// AppendU64 absorbs the integer, encoded as 8 big endian bytes, with the
+// given label.
+func (t *Transcript) AppendU64(label string, x uint64) {
+	var bs [8]byte
+	binary.BigEndian.PutUint64(bs[:], x)
+	t.AppendMessage(label, bs[:])
+}
+
+// AppendScalar absorbs the scalar with the given label.
+func (t *Transcript) AppendScalar(label string, x *secp256k1.Fn) {
+	var bs [secp256k1.FnSizeMarshalled]byte
+	x.PutB32(bs[:])
+	t.AppendMessage(label, bs[:])
+}
+
+// AppendPoint absorbs the point with the given label.
+func (t *Transcript) AppendPoint(label string, p *secp256k1.Point) {
+	var bs [secp256k1.PointSizeMarshalled]byte
+	p.PutBytes(bs[:])
+	t.AppendMessage(label, bs[:])
+}
+
+// AppendPoints absorbs the points with the given label, as a single message
+// that also encodes their number, so that a commitment is bound as a whole.
+func (t *Transcript) AppendPoints(label string, ps []secp256k1.Point) {
+	msg := make([]byte, 4+len(ps)*secp256k1.PointSizeMarshalled)
+	binary.BigEndian.PutUint32(msg, uint32(len(ps)))
+	for i := range ps {
+		ps[i].PutBytes(msg[4+i*secp256k1.PointSizeMarshalled:])
+	}
+	t.AppendMessage(label, msg)
+}
+
+// ChallengeBytes fills dst with challenge bytes derived from the transcript
+// and the given label, and then absorbs them, so that later challenges
+// depend on them.
+func (t *Transcript) ChallengeBytes(label string, dst []byte) {
+	h := sha256.New()
+	var block [sha256.Size]byte
+	for i, n := uint32(0), 0; n < len(dst); i++ {
+		h.Reset()
+		h.Write(t.state[:])
+		h.Write([]byte{opChallengeBlock})
+		writeBytes(h, []byte(label))
+		writeU32(h, uint32(len(dst)))
+		writeU32(h, i)
+		h.Sum(block[:0])
+		n += copy(dst[n:], block[:])
+	}
+	t.absorb(opChallenge, []byte(label), dst)
+}
+
+// ChallengeScalar returns a challenge scalar derived from the transcript and
+// the given label, and then absorbs it, as for ChallengeBytes. The scalar is
+// 32 challenge bytes reduced modulo the order of the group, which is
+// indistinguishable from uniform since the order is within 2^-128 of 2^256.
+func (t *Transcript) ChallengeScalar(label string) secp256k1.Fn {
+	var bs [32]byte
+	t.ChallengeBytes(label, bs[:])
+	var c secp256k1.Fn
+	c.SetB32(bs[:])
+	return c
+}
+
+func (t *Transcript) absorb(op byte, label, msg []byte) {
+	h := sha256.New()
+	h.Write(t.state[:])
+	h.Write([]byte{op})
+	writeBytes(h, label)
+	writeBytes(h, msg)
+	h.Sum(t.state[:0])
+}
+
+// Writes the length of the bytes as a big endian 32 bit integer, followed by
+// the bytes.
+func writeBytes(h hash.Hash, bs []byte) {
+	writeU32(h, uint32(len(bs)))
+	h.Write(bs)
+}
+
+func writeU32(h hash.Hash, x uint32) {
+	var bs [4]byte
+	binary.BigEndian.PutUint32(bs[:], x)
+	h.Write(bs[:])
+}
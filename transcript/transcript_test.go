@@ -0,0 +1,103 @@
+package transcript_test
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir/transcript"
+)
+
+var _ = Describe("Transcript", func() {
+	challenge := func(t Transcript) secp256k1.Fn {
+		return t.ChallengeScalar("challenge")
+	}
+
+	It("should match the known answer", func() {
+		t := New("test protocol")
+		t.AppendMessage("msg", []byte("hello"))
+		t.AppendU64("n", 5)
+		x := secp256k1.NewFnFromU16(7)
+		t.AppendScalar("x", &x)
+		var bs [48]byte
+		t.ChallengeBytes("c", bs[:])
+		Expect(hex.EncodeToString(bs[:])).To(Equal(
+			"1abf382cc380e3a56fff16a9df759d5270cf6313ff04fdf68ccdc363e734c523" +
+				"07aa3cc0ed10c5a69008a96f697f1d44",
+		))
+	})
+
+	It("should derive the same challenges from the same messages", func() {
+		p := secp256k1.RandomPoint()
+		t1, t2 := New("protocol"), New("protocol")
+		t1.AppendPoint("p", &p)
+		t2.AppendPoint("p", &p)
+		c1, c2 := challenge(t1), challenge(t2)
+		Expect(c1.Eq(&c2)).To(BeTrue())
+	})
+
+	It("should separate protocols, labels and message boundaries", func() {
+		base := challenge(New("protocol"))
+		other := challenge(New("other protocol"))
+		Expect(base.Eq(&other)).To(BeFalse())
+
+		t1, t2, t3 := New("protocol"), New("protocol"), New("protocol")
+		t1.AppendMessage("a", []byte("bc"))
+		t2.AppendMessage("ab", []byte("c"))
+		t3.AppendMessage("b", []byte("bc"))
+		c1, c2, c3 := challenge(t1), challenge(t2), challenge(t3)
+		Expect(c1.Eq(&c2)).To(BeFalse())
+		Expect(c1.Eq(&c3)).To(BeFalse())
+
+		t4, t5 := New("protocol"), New("protocol")
+		t4.AppendMessage("a", []byte("b"))
+		t4.AppendMessage("a", []byte("c"))
+		t5.AppendMessage("a", []byte("bc"))
+		c4, c5 := challenge(t4), challenge(t5)
+		Expect(c4.Eq(&c5)).To(BeFalse())
+	})
+
+	It("should bind commitments as a whole", func() {
+		ps := []secp256k1.Point{secp256k1.RandomPoint(), secp256k1.RandomPoint()}
+		t1, t2 := New("protocol"), New("protocol")
+		t1.AppendPoints("c", ps)
+		t2.AppendPoints("c", ps[:1])
+		t2.AppendPoint("c", &ps[1])
+		c1, c2 := challenge(t1), challenge(t2)
+		Expect(c1.Eq(&c2)).To(BeFalse())
+	})
+
+	It("should absorb the challenges that it derives", func() {
+		t := New("protocol")
+		c1, c2 := challenge(t), t.ChallengeScalar("challenge")
+		c3 := t.ChallengeScalar("challenge")
+		Expect(c1.Eq(&c2)).To(BeTrue())
+		Expect(c2.Eq(&c3)).To(BeFalse())
+	})
+
+	It("should fork when copied", func() {
+		t := New("protocol")
+		t.AppendMessage("common", []byte("prefix"))
+		fork := t
+		fork.AppendMessage("branch", []byte("one"))
+		t.AppendMessage("branch", []byte("two"))
+		c1, c2 := challenge(fork), challenge(t)
+		Expect(c1.Eq(&c2)).To(BeFalse())
+	})
+
+	It("should derive challenges of any length", func() {
+		t := New("protocol")
+		long := make([]byte, 100)
+		fork := t
+		fork.ChallengeBytes("c", long)
+		Expect(bytes.Equal(long[:32], long[32:64])).To(BeFalse())
+
+		// The length of the challenge is part of its derivation.
+		short := make([]byte, 32)
+		t.ChallengeBytes("c", short)
+		Expect(bytes.Equal(short, long[:32])).To(BeFalse())
+	})
+})
@@ -0,0 +1,117 @@
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/renproject/secp256k1"
+)
+
+// A Verifier checks verifiable shares against a fixed commitment. It
+// precomputes tables of multiples of the commitment points, so that the
+// commitment is evaluated at the index of each share with a single
+// multi-scalar multiplication. This amortises the setup cost when many shares
+// are verified against the same commitment, for example during a DKG.
+type Verifier struct {
+	tables []pointTable
+	h      secp256k1.Point
+}
+
+// NewVerifier constructs a verifier for shares with the given commitment and
+// Pedersen parameter h. The commitment is not referenced after this function
+// returns, and so is safe to modify.
+func NewVerifier(h secp256k1.Point, c Commitment) Verifier {
+	tables := make([]pointTable, len(c))
+	for i := range c {
+		tables[i] = newPointTable(&c[i])
+	}
+	return Verifier{tables: tables, h: h}
+}
+
+// Verify returns true when the given verifiable share is valid with regard to
+// the commitment of the verifier, and false otherwise. It gives the same
+// result as IsValid, except that it returns false rather than panicking when
+// the commitment is empty.
+func (v *Verifier) Verify(vshare *VerifiableShare) bool {
+	k := len(v.tables)
+	if k == 0 {
+		return false
+	}
+
+	// The multi-scalar multiplication is not constant time, so it is only
+	// used for the powers of the index, which are public.
+	scalars := make([]secp256k1.Fn, k)
+	powers(scalars, &vshare.Share.Index)
+	var eval secp256k1.Point
+	msm(&eval, v.tables, scalars)
+
+	expected := pedersenPoint(&v.h, vshare)
+	return eval.Eq(&expected)
+}
+
+// An IndexVerifier checks verifiable shares whose indices belong to a fixed
+// IndexSet against any commitment. It precomputes the powers index^j of each
+// index, which IsValid and Verifier recompute for every share, so that the
+// commitment is evaluated at the index with a single multi-scalar
+// multiplication. This suits nodes that verify shares for the same parties
+// from many dealers, at the cost of storing k powers for each index.
+//
+// An IndexVerifier is not modified after construction, and so is safe for
+// concurrent use.
+type IndexVerifier struct {
+	set    IndexSet
+	k      int
+	h      secp256k1.Point
+	powers [][]secp256k1.Fn
+}
+
+// NewIndexVerifier constructs a verifier for shares with the indices of the
+// given set and Pedersen parameter h, for commitments with at most k points.
+//
+// Panics: This function will panic if k is less than 1.
+func NewIndexVerifier(h secp256k1.Point, set IndexSet, k int) IndexVerifier {
+	if k < 1 {
+		panic(fmt.Sprintf("invalid threshold: expected k >= 1, got %v", k))
+	}
+
+	ps := make([][]secp256k1.Fn, set.Len())
+	for i := range ps {
+		index := set.Index(i)
+		ps[i] = make([]secp256k1.Fn, k)
+		powers(ps[i], &index)
+	}
+	return IndexVerifier{set: set, k: k, h: h, powers: ps}
+}
+
+// Verify returns true when the given verifiable share is valid with regard to
+// the given commitment, and false otherwise. It gives the same result as
+// IsValid for shares whose index is in the set of the verifier and
+// commitments with at most k points. Shares with other indices, commitments
+// with more than k points and empty commitments are rejected.
+func (v *IndexVerifier) Verify(c *Commitment, vshare *VerifiableShare) bool {
+	k := len(*c)
+	if k == 0 || k > v.k {
+		return false
+	}
+	p, ok := v.set.Position(&vshare.Share.Index)
+	if !ok {
+		return false
+	}
+
+	// As for Verifier, the value and decommitment are secret and so are not
+	// part of the multi-scalar multiplication.
+	var eval secp256k1.Point
+	msmPoints(&eval, *c, v.powers[p][:k])
+
+	expected := pedersenPoint(&v.h, vshare)
+	return eval.Eq(&expected)
+}
+
+// Returns value*G + decommitment*h for the given share, using constant time
+// scalar multiplications since the value and decommitment are secret.
+func pedersenPoint(h *secp256k1.Point, vshare *VerifiableShare) secp256k1.Point {
+	var p, hPow secp256k1.Point
+	p.BaseExp(&vshare.Share.Value)
+	hPow.ScaleExt(h, &vshare.Decommitment)
+	p.Add(&p, &hPow)
+	return p
+}
@@ -0,0 +1,125 @@
+package shamir_test
+
+import (
+	"math/rand"
+
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Verifier", func() {
+	trials := 20
+	n := 20
+	h := PedersenH()
+
+	It("should accept valid shares", func() {
+		for i := 0; i < trials; i++ {
+			d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+			Expect(err).ToNot(HaveOccurred())
+			verifier := NewVerifier(h, d.Commitment)
+			for j := range d.Shares {
+				Expect(verifier.Verify(&d.Shares[j])).To(BeTrue())
+			}
+		}
+	})
+
+	It("should reject invalid shares", func() {
+		for i := 0; i < trials; i++ {
+			// When k = 1 the index does not affect validity, so k is at least 2.
+			d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(2, n))
+			Expect(err).ToNot(HaveOccurred())
+			verifier := NewVerifier(h, d.Commitment)
+
+			vshare := d.Shares[rand.Intn(n)]
+			switch rand.Intn(3) {
+			case 0:
+				PerturbIndex(&vshare)
+			case 1:
+				PerturbValue(&vshare)
+			default:
+				PerturbDecommitment(&vshare)
+			}
+			Expect(verifier.Verify(&vshare)).To(BeFalse())
+			Expect(IsValid(h, &d.Commitment, &vshare)).To(BeFalse())
+		}
+	})
+
+	It("should not be affected by later changes to the commitment", func() {
+		d, err := Deal(RandomIndices(n), h, secp256k1.RandomFn(), RandRange(1, n))
+		Expect(err).ToNot(HaveOccurred())
+		verifier := NewVerifier(h, d.Commitment)
+		d.Commitment[0] = secp256k1.RandomPoint()
+		Expect(verifier.Verify(&d.Shares[0])).To(BeTrue())
+	})
+
+	It("should reject shares when the commitment is empty", func() {
+		verifier := NewVerifier(h, Commitment{})
+		vshare := NewVerifiableShare(NewShare(secp256k1.RandomFn(), secp256k1.Fn{}), secp256k1.Fn{})
+		Expect(verifier.Verify(&vshare)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Index verifier", func() {
+	trials := 20
+	n := 20
+	h := PedersenH()
+
+	for _, sequential := range []bool{true, false} {
+		sequential := sequential
+		description := "with random indices"
+		if sequential {
+			description = "with sequential indices"
+		}
+
+		Context(description, func() {
+			var set IndexSet
+			BeforeEach(func() {
+				if sequential {
+					set = NewSequentialIndexSet(n)
+				} else {
+					var err error
+					set, err = NewIndexSet(RandomIndices(n))
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
+
+			It("should agree with IsValid for valid and invalid shares", func() {
+				verifier := NewIndexVerifier(h, set, n)
+				for i := 0; i < trials; i++ {
+					d, err := Deal(set.Indices(), h, secp256k1.RandomFn(), RandRange(2, n))
+					Expect(err).ToNot(HaveOccurred())
+					for j := range d.Shares {
+						Expect(verifier.Verify(&d.Commitment, &d.Shares[j])).To(BeTrue())
+					}
+
+					vshare := d.Shares[rand.Intn(n)]
+					if rand.Intn(2) == 0 {
+						PerturbValue(&vshare)
+					} else {
+						PerturbDecommitment(&vshare)
+					}
+					Expect(verifier.Verify(&d.Commitment, &vshare)).To(BeFalse())
+					Expect(IsValid(h, &d.Commitment, &vshare)).To(BeFalse())
+				}
+			})
+
+			It("should reject shares and commitments that it does not cover", func() {
+				k := n / 2
+				verifier := NewIndexVerifier(h, set, k)
+				d, err := Deal(set.Indices(), h, secp256k1.RandomFn(), k+1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(IsValid(h, &d.Commitment, &d.Shares[0])).To(BeTrue())
+				Expect(verifier.Verify(&d.Commitment, &d.Shares[0])).To(BeFalse())
+
+				d, err = Deal(append(set.Indices()[1:], secp256k1.RandomFn()), h, secp256k1.RandomFn(), k)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(verifier.Verify(&d.Commitment, &d.Shares[n-1])).To(BeFalse())
+				Expect(verifier.Verify(&Commitment{}, &d.Shares[0])).To(BeFalse())
+			})
+		})
+	}
+})
@@ -1,6 +1,7 @@
 package shamir
 
 import (
+	"fmt"
 	"math/rand"
 	"reflect"
 
@@ -42,19 +43,39 @@ func (vshares *VerifiableShares) Unmarshal(buf []byte, rem int) ([]byte, int, er
 		return buf, rem, err
 	}
 
-	if *vshares == nil {
+	if *vshares == nil || uint32(cap(*vshares)) < l {
 		*vshares = make(VerifiableShares, 0, l)
 	}
 
-	*vshares = (*vshares)[:0]
-	for i := uint32(0); i < l; i++ {
-		*vshares = append(*vshares, VerifiableShare{})
-		buf, rem, err = (*vshares)[i].Unmarshal(buf, rem)
+	*vshares = (*vshares)[:l]
+	return unmarshalVShares(*vshares, buf, rem)
+}
+
+// UnmarshalVSharesInto is the same as UnmarshalSharesInto, but for verifiable
+// shares.
+func UnmarshalVSharesInto(dst VerifiableShares, buf []byte, rem int) (VerifiableShares, []byte, int, error) {
+	var l uint32
+	buf, rem, err := surge.UnmarshalLen(&l, VShareSize, buf, rem)
+	if err != nil {
+		return dst[:0], buf, rem, err
+	}
+	if uint32(cap(dst)) < l {
+		return dst[:0], buf, rem, fmt.Errorf("%w: length %v, capacity %v", ErrCapacity, l, cap(dst))
+	}
+
+	dst = dst[:l]
+	buf, rem, err = unmarshalVShares(dst, buf, rem)
+	return dst, buf, rem, err
+}
+
+func unmarshalVShares(dst VerifiableShares, buf []byte, rem int) ([]byte, int, error) {
+	var err error
+	for i := range dst {
+		buf, rem, err = dst[i].Unmarshal(buf, rem)
 		if err != nil {
 			return buf, rem, err
 		}
 	}
-
 	return buf, rem, nil
 }
 
@@ -155,9 +176,29 @@ func (vs *VerifiableShare) Scale(other *VerifiableShare, scale *secp256k1.Fn) {
 	vs.Decommitment.Mul(&other.Decommitment, scale)
 }
 
+// Rerandomize adds to the share the share at its index of a sharing of zero,
+// so that a relay can forward it without it being linked to the share that it
+// received, while it stays valid for the commitment that is rerandomized with
+// the same delta by Commitment.Rerandomize. The sharing of zero is given by
+// the polynomial delta*x for both the value and the decommitment, so the share
+// can be updated knowing only delta and its own index. Every share of a
+// sharing must be rerandomized with the same delta for them to be valid for
+// the same commitment, and delta should be chosen at random for each session.
+func (vs *VerifiableShare) Rerandomize(delta *secp256k1.Fn) {
+	var blind secp256k1.Fn
+	blind.Mul(delta, &vs.Share.Index)
+	vs.Share.Value.Add(&vs.Share.Value, &blind)
+	vs.Decommitment.Add(&vs.Decommitment, &blind)
+	blind.Clear()
+}
+
 // A Commitment is used to verify that a sharing has been performed correctly.
+// For small thresholds that are known in advance, the fixed size types
+// Commitment2, Commitment3 and Commitment4 can be used instead.
 type Commitment []secp256k1.Point
 
+//go:generate go run gen_commitk.go
+
 // Generate implements the quick.Generator interface.
 func (c Commitment) Generate(rand *rand.Rand, size int) reflect.Value {
 	com := make(Commitment, rand.Intn(size))
@@ -194,6 +235,66 @@ func (c Commitment) Len() int {
 	return len(c)
 }
 
+// DegreeBound returns the number of points in the commitment up to and
+// including the last one that is not the point at infinity. The committed
+// polynomials have degree less than this bound, since the trailing points at
+// infinity are commitments to zero coefficients, so the commitment can be
+// truncated to this length without invalidating any shares. For a commitment
+// that was padded with PadToLen, this is the threshold of the original
+// sharing. It is zero if every point is the point at infinity.
+func (c Commitment) DegreeBound() int {
+	for i := len(c) - 1; i >= 0; i-- {
+		if !c[i].IsInfinity() {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Truncate shortens the commitment to its first k points, which are the
+// commitments to the coefficients of the terms of degree less than k. If all
+// of the removed points are the point at infinity, the commitment is to a
+// sharing polynomial of degree less than k, and so every share that was valid
+// for the commitment before truncation is still valid afterwards, and the
+// returned boolean is true. Otherwise the truncated commitment is to a
+// different polynomial, which agrees with the original at fewer non zero
+// indices than the number of removed points, so in general the shares of the
+// original sharing will not be valid for it, and the returned boolean is
+// false.
+// Truncation does not lower the degree of a sharing, so when resharing to a
+// lower threshold, the commitment can only be truncated if the new sharing
+// was created at the lower threshold and then padded.
+//
+// Panics: This function will panic if k is negative or greater than the
+// length of the commitment.
+func (c *Commitment) Truncate(k int) bool {
+	if k < 0 || k > len(*c) {
+		panic(fmt.Sprintf("cannot truncate commitment of length %v to length %v", len(*c), k))
+	}
+	ok := true
+	for i := k; i < len(*c); i++ {
+		if !(*c)[i].IsInfinity() {
+			ok = false
+		}
+	}
+	*c = (*c)[:k]
+	return ok
+}
+
+// PadToLen extends the commitment to length k by appending points at
+// infinity, which are the commitments to zero coefficients. The padded
+// commitment is to the same sharing polynomial, with zero coefficients for
+// the new terms, and so a share is valid for the padded commitment if and
+// only if it was valid for the original. This allows the commitment to be
+// combined, for example using Add, with commitments of a sharing at the
+// higher threshold k. If the commitment already has length at least k, it is
+// not modified.
+func (c *Commitment) PadToLen(k int) {
+	for len(*c) < k {
+		c.Append(secp256k1.NewPointInfinity())
+	}
+}
+
 // Set the calling commitment to be equal to the given commitment.
 func (c *Commitment) Set(other Commitment) {
 	if len(*c) < len(other) {
@@ -310,19 +411,59 @@ func (c *Commitment) AddConstant(other Commitment, constant *secp256k1.Fn) {
 func (c *Commitment) Scale(other Commitment, scale *secp256k1.Fn) {
 	*c = (*c)[:len(other)]
 	for i := range *c {
-		(*c)[i].Scale(&other[i], scale)
+		(*c)[i].ScaleExt(&other[i], scale)
 	}
 }
 
-// Evaluates the sharing polynomial at the given index "in the exponent".
+// Rerandomize modifies the commitment in place to commit to the sharing that
+// results from rerandomizing its shares with the same delta using
+// VerifiableShare.Rerandomize. The sharing of zero that is added commits to
+// delta*G + delta*h in the linear term, so the commitment to the secret in the
+// first point is unchanged.
+//
+// Panics: This function will panic if the commitment has fewer than two
+// points, since the linear term would then raise the threshold.
+func (c Commitment) Rerandomize(h secp256k1.Point, delta *secp256k1.Fn) {
+	if len(c) < 2 {
+		panic(fmt.Sprintf("cannot rerandomize a commitment of length %v", len(c)))
+	}
+	var gPow, hPow secp256k1.Point
+	gPow.BaseExp(delta)
+	hPow.ScaleExt(&h, delta)
+	gPow.Add(&gPow, &hPow)
+	c[1].Add(&c[1], &gPow)
+}
+
+// Evaluates the sharing polynomial at the given index "in the exponent". Long
+// commitments are evaluated with a multi-scalar multiplication, and short ones
+// with Horner's method.
 func (c *Commitment) evaluate(eval *secp256k1.Point, index *secp256k1.Fn) {
+	if len(*c) >= msmThreshold {
+		*eval = c.EvaluateMSM(index)
+		return
+	}
 	*eval = (*c)[len(*c)-1]
 	for i := len(*c) - 2; i >= 0; i-- {
-		eval.Scale(eval, index)
+		// Padded commitments end in points at infinity, which Scale does not
+		// handle.
+		eval.ScaleExt(eval, index)
 		eval.Add(eval, &(*c)[i])
 	}
 }
 
+// Evaluate returns the commitment evaluated at the given index "in the
+// exponent". For a sharing created by VShareSecret, this is value*G +
+// decommitment*H for the share with the given index. When the commitment is a
+// Feldman commitment, that is, the coefficients of the sharing polynomial
+// multiplied by G, this is the public share value*G.
+//
+// Panics: This function will panic if the commitment is empty.
+func (c Commitment) Evaluate(index *secp256k1.Fn) secp256k1.Point {
+	var eval secp256k1.Point
+	c.evaluate(&eval, index)
+	return eval
+}
+
 // IsValid returns true when the given verifiable share is valid with regard to
 // the given commitment, and false otherwise.
 func IsValid(h secp256k1.Point, c *Commitment, vshare *VerifiableShare) bool {
@@ -335,14 +476,30 @@ func IsValid(h secp256k1.Point, c *Commitment, vshare *VerifiableShare) bool {
 	return gPow.Eq(&eval)
 }
 
+// IsValidFeldman returns true when the given share is valid with regard to the
+// given Feldman commitment, that is, a commitment whose points are the
+// coefficients of the sharing polynomial multiplied by G, and false otherwise.
+// This allows recipients of plain shares, which have no decommitment, to check
+// them. A share can not be valid for a Pedersen commitment created by
+// VShareSecret with this function; use IsValid for those.
+func IsValidFeldman(c *Commitment, share *Share) bool {
+	var gPow, eval secp256k1.Point
+	gPow.BaseExp(&share.Value)
+	c.evaluate(&eval, &share.Index)
+	return gPow.Eq(&eval)
+}
+
 // VShareSecret creates verifiable Shamir shares for the given secret at the
 // given threshold, and stores the shares and the commitment in the given
 // destinations. In the returned Shares, there will be one share for each index
-// in the indices that were used to construct the Sharer.
+// in the indices that were used to construct the Sharer. The checks that are
+// performed on the indices can be configured with the given options, as for
+// ShareSecret.
 //
 // Panics: This function will panic if the destination shares slice has a
 // capacity less than n (the number of indices), or if the destination
-// commitment has a capacity less than k.
+// commitment has a capacity less than k, or if any of the indices is zero and
+// the WithAllowZeroIndexOff option is not given.
 func VShareSecret(
 	vshares *VerifiableShares,
 	c *Commitment,
@@ -350,34 +507,56 @@ func VShareSecret(
 	h secp256k1.Point,
 	secret secp256k1.Fn,
 	k int,
+	opts ...ShareOption,
 ) error {
-	n := len(indices)
-	shares := make(Shares, n)
-	coeffs := make([]secp256k1.Fn, k)
-	err := ShareAndGetCoeffs(&shares, coeffs, indices, secret, k)
-	if err != nil {
+	options := newShareOptions(opts)
+	if err := validateIndices(indices, options); err != nil {
 		return err
 	}
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	return vshareSecret(vshares, c, indices, h, secret, k, s, &options)
+}
 
-	// At this point, the sharer should still have the randomly picked
-	// coefficients in its cache, which we need to use for the commitment.
-	*c = (*c)[:k]
-	for i, coeff := range coeffs {
-		(*c)[i].BaseExp(&coeff)
+// VShareSecretAndGetCoeffs is the same as VShareSecret, but uses the provided
+// slices to store the coefficients of the sharing polynomial f and of the
+// blinding polynomial g, whose values at the indices are the decommitments of
+// the shares, for dealers that need them to create further proofs about the
+// sharing. Index 0 holds the constant term, so that fcoeffs[0] is the secret
+// and the i-th point of the commitment is fcoeffs[i]*G + gcoeffs[i]*h.
+//
+// Both slices must be zeroed with WipeFns once they are no longer needed. The
+// coefficients of f determine the secret, and since the commitment is public,
+// so do the coefficients of g: they give gcoeffs[0]*h, and so secret*G.
+//
+// Panics: This function will panic under the same conditions as VShareSecret,
+// or if either coefficient slice has length less than k.
+func VShareSecretAndGetCoeffs(
+	vshares *VerifiableShares,
+	c *Commitment,
+	fcoeffs, gcoeffs []secp256k1.Fn,
+	indices []secp256k1.Fn,
+	h secp256k1.Point,
+	secret secp256k1.Fn,
+	k int,
+	opts ...ShareOption,
+) error {
+	if len(fcoeffs) < k || len(gcoeffs) < k {
+		panic(fmt.Sprintf(
+			"coefficient slices too short: expected length at least k = %v, got %v and %v",
+			k, len(fcoeffs), len(gcoeffs),
+		))
 	}
-
-	setRandomCoeffs(coeffs, secp256k1.RandomFn(), k)
-	for i, ind := range indices {
-		(*vshares)[i].Share = shares[i]
-		polyEval(&(*vshares)[i].Decommitment, &ind, coeffs)
+	options := newShareOptions(opts)
+	if err := validateIndices(indices, options); err != nil {
+		return err
 	}
-
-	// Finish the computation of the commitments
-	var hPow secp256k1.Point
-	for i, coeff := range coeffs {
-		hPow.Scale(&h, &coeff)
-		(*c)[i].Add(&(*c)[i], &hPow)
+	if err := checkIndices(indices, k); err != nil {
+		return err
 	}
-
-	return nil
+	s := scratchPool.Get().(*Scratch)
+	defer scratchPool.Put(s)
+	s.reserve(k)
+	defer s.wipe()
+	return vshareSecretWithCoeffs(vshares, c, indices, h, secret, fcoeffs[:k], gcoeffs[:k], s, &options)
 }
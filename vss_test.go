@@ -1,11 +1,12 @@
 package shamir_test
 
 import (
+	"errors"
 	"math/rand"
-	"testing"
 	"time"
 
 	"github.com/renproject/secp256k1"
+	"github.com/renproject/shamir/poly"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -465,6 +466,44 @@ var _ = Describe("Verifiable secret sharing", func() {
 		})
 	})
 
+	Context("Rerandomization", func() {
+		trials := 20
+		n := 20
+
+		It("should keep the shares valid and the secret unchanged", func() {
+			indices := RandomIndices(n)
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(n)
+			for i := 0; i < trials; i++ {
+				k := RandRange(2, n)
+				secret := secp256k1.RandomFn()
+				Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+				original := append(VerifiableShares{}, vshares...)
+				first := c[0]
+
+				delta := secp256k1.RandomFn()
+				c.Rerandomize(h, &delta)
+				Expect(c[0].Eq(&first)).To(BeTrue())
+				for j := range vshares {
+					vshares[j].Rerandomize(&delta)
+					Expect(vshares[j].Share.Value.Eq(&original[j].Share.Value)).To(BeFalse())
+					Expect(vshares[j].Decommitment.Eq(&original[j].Decommitment)).To(BeFalse())
+					Expect(IsValid(h, &c, &vshares[j])).To(BeTrue())
+					Expect(IsValid(h, &c, &original[j])).To(BeFalse())
+				}
+				recon := Open(vshares.Shares()[:k])
+				Expect(recon.Eq(&secret)).To(BeTrue())
+				Expect(VsharesAreConsistent(vshares, k)).To(BeTrue())
+			}
+		})
+
+		It("should panic for a commitment with fewer than two points", func() {
+			delta := secp256k1.RandomFn()
+			c := Commitment{secp256k1.RandomPoint()}
+			Expect(func() { c.Rerandomize(h, &delta) }).To(Panic())
+		})
+	})
+
 	//
 	// Miscellaneous tests
 	//
@@ -475,7 +514,7 @@ var _ = Describe("Verifiable secret sharing", func() {
 		indices := RandomIndices(n)
 
 		err := VShareSecret(nil, nil, indices, h, secp256k1.Fn{}, n+1)
-		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrInvalidThreshold)).To(BeTrue())
 	})
 
 	Context("Commitments", func() {
@@ -542,6 +581,219 @@ var _ = Describe("Verifiable secret sharing", func() {
 				Expect(com.Len()).To(Equal(k))
 			}
 		})
+
+		Context("when resizing", func() {
+			n := 15
+			indices := RandomIndices(n)
+			vshares := make(VerifiableShares, n)
+
+			allValid := func(c Commitment) bool {
+				for i := range vshares {
+					if !IsValid(h, &c, &vshares[i]) {
+						return false
+					}
+				}
+				return true
+			}
+
+			It("should keep shares valid after padding", func() {
+				for i := 0; i < 10; i++ {
+					k := RandRange(1, maxK)
+					c := NewCommitmentWithCapacity(maxK)
+					Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+
+					c.PadToLen(RandRange(k, maxK+5))
+					Expect(allValid(c)).To(BeTrue())
+				}
+			})
+
+			It("should not change a commitment that is already long enough", func() {
+				c := RandomCommitment(maxK)
+				original := append(Commitment{}, c...)
+				c.PadToLen(maxK - 1)
+				Expect(c.Eq(original)).To(BeTrue())
+			})
+
+			It("should keep shares valid after truncating padding", func() {
+				for i := 0; i < 10; i++ {
+					k := RandRange(1, maxK)
+					c := NewCommitmentWithCapacity(maxK)
+					Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+
+					original := append(Commitment{}, c...)
+					c.PadToLen(maxK)
+					Expect(c.Truncate(RandRange(k, maxK))).To(BeTrue())
+					Expect(allValid(c)).To(BeTrue())
+					Expect(c.Truncate(k)).To(BeTrue())
+					Expect(c.Eq(original)).To(BeTrue())
+				}
+			})
+
+			It("should make shares invalid after truncating non identity points", func() {
+				for i := 0; i < 10; i++ {
+					k := RandRange(2, maxK)
+					c := NewCommitmentWithCapacity(maxK)
+					Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k)).To(Succeed())
+
+					Expect(c.Truncate(RandRange(1, k-1))).To(BeFalse())
+					Expect(allValid(c)).To(BeFalse())
+				}
+			})
+
+			It("should keep homomorphic addition consistent after padding", func() {
+				k1, k2 := 3, 7
+				vshares2 := make(VerifiableShares, n)
+				c1 := NewCommitmentWithCapacity(k2)
+				c2 := NewCommitmentWithCapacity(k2)
+				Expect(VShareSecret(&vshares, &c1, indices, h, secp256k1.RandomFn(), k1)).To(Succeed())
+				Expect(VShareSecret(&vshares2, &c2, indices, h, secp256k1.RandomFn(), k2)).To(Succeed())
+
+				padded := append(Commitment{}, c1...)
+				padded.PadToLen(k2)
+				sum, paddedSum := NewCommitmentWithCapacity(k2), NewCommitmentWithCapacity(k2)
+				sum.Add(c1, c2)
+				paddedSum.Add(padded, c2)
+				Expect(paddedSum.Eq(sum)).To(BeTrue())
+			})
+
+			It("should panic when truncating to an invalid length", func() {
+				c := RandomCommitment(3)
+				Expect(func() { c.Truncate(4) }).To(Panic())
+				Expect(func() { c.Truncate(-1) }).To(Panic())
+			})
+		})
+	})
+
+	Context("Feldman commitments", func() {
+		trials := 20
+
+		feldmanShare := func(n, k int) (Shares, Commitment) {
+			shares := make(Shares, n)
+			coeffs := make([]secp256k1.Fn, k)
+			Expect(ShareAndGetCoeffs(&shares, coeffs, RandomIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+			c := NewCommitmentWithCapacity(k)
+			for i := range coeffs {
+				var p secp256k1.Point
+				p.BaseExp(&coeffs[i])
+				c.Append(p)
+			}
+			return shares, c
+		}
+
+		It("should find correctly constructed shares valid", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				shares, c := feldmanShare(n, RandRange(1, n))
+				for j := range shares {
+					Expect(IsValidFeldman(&c, &shares[j])).To(BeTrue())
+				}
+			}
+		})
+
+		It("should find modified shares invalid", func() {
+			for i := 0; i < trials; i++ {
+				// With k = 1 every index has the same value, so changing the
+				// index of a share does not make it invalid.
+				n := RandRange(2, 20)
+				shares, c := feldmanShare(n, RandRange(2, n))
+				j := rand.Intn(n)
+				if rand.Intn(2) == 0 {
+					shares[j].Index = secp256k1.RandomFn()
+				} else {
+					shares[j].Value = secp256k1.RandomFn()
+				}
+				Expect(IsValidFeldman(&c, &shares[j])).To(BeFalse())
+			}
+		})
+
+		It("should not accept shares against a Pedersen commitment", func() {
+			n, k := 10, 4
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			Expect(VShareSecret(&vshares, &c, RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+			for j := range vshares {
+				Expect(IsValidFeldman(&c, &vshares[j].Share)).To(BeFalse())
+			}
+		})
+	})
+
+	Context("Polynomial coefficients", func() {
+		trials := 20
+
+		It("should return the coefficients of both polynomials", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				indices := RandomIndices(n)
+				secret := secp256k1.RandomFn()
+				vshares := make(VerifiableShares, n)
+				c := NewCommitmentWithCapacity(k)
+				fcoeffs := make([]secp256k1.Fn, k)
+				gcoeffs := make([]secp256k1.Fn, k)
+				Expect(VShareSecretAndGetCoeffs(&vshares, &c, fcoeffs, gcoeffs, indices, h, secret, k)).To(Succeed())
+				Expect(fcoeffs[0].Eq(&secret)).To(BeTrue())
+
+				f, g := poly.Poly(fcoeffs), poly.Poly(gcoeffs)
+				for j := range vshares {
+					Expect(IsValid(h, &c, &vshares[j])).To(BeTrue())
+					value, decommitment := f.Evaluate(indices[j]), g.Evaluate(indices[j])
+					Expect(vshares[j].Share.Value.Eq(&value)).To(BeTrue())
+					Expect(vshares[j].Decommitment.Eq(&decommitment)).To(BeTrue())
+				}
+				for j := range c {
+					var expected, hPow secp256k1.Point
+					expected.BaseExp(&fcoeffs[j])
+					hPow.Scale(&h, &gcoeffs[j])
+					expected.Add(&expected, &hPow)
+					Expect(c[j].Eq(&expected)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should output the commitment to the blinding polynomial when asked", func() {
+			for i := 0; i < trials; i++ {
+				n := RandRange(1, 20)
+				k := RandRange(1, n)
+				indices := RandomIndices(n)
+				vshares := make(VerifiableShares, n)
+				c := NewCommitmentWithCapacity(k)
+				blind := NewCommitmentWithCapacity(k)
+				fcoeffs := make([]secp256k1.Fn, k)
+				gcoeffs := make([]secp256k1.Fn, k)
+				Expect(VShareSecretAndGetCoeffs(
+					&vshares, &c, fcoeffs, gcoeffs, indices, h, secp256k1.RandomFn(), k,
+					WithBlindingCommitment(&blind),
+				)).To(Succeed())
+				Expect(blind.Len()).To(Equal(k))
+				for j := range blind {
+					var expected secp256k1.Point
+					expected.Scale(&h, &gcoeffs[j])
+					Expect(blind[j].Eq(&expected)).To(BeTrue())
+				}
+
+				// The blinding commitment evaluates to decommitment*h.
+				Expect(VShareSecret(&vshares, &c, indices, h, secp256k1.RandomFn(), k, WithBlindingCommitment(&blind))).To(Succeed())
+				for j := range vshares {
+					var expected secp256k1.Point
+					expected.Scale(&h, &vshares[j].Decommitment)
+					eval := blind.Evaluate(&indices[j])
+					Expect(eval.Eq(&expected)).To(BeTrue())
+				}
+			}
+		})
+
+		It("should panic if a coefficient slice is too short", func() {
+			n, k := 10, 4
+			vshares := make(VerifiableShares, n)
+			c := NewCommitmentWithCapacity(k)
+			long, short := make([]secp256k1.Fn, k), make([]secp256k1.Fn, k-1)
+			Expect(func() {
+				_ = VShareSecretAndGetCoeffs(&vshares, &c, short, long, RandomIndices(n), h, secp256k1.RandomFn(), k)
+			}).To(Panic())
+			Expect(func() {
+				_ = VShareSecretAndGetCoeffs(&vshares, &c, long, short, RandomIndices(n), h, secp256k1.RandomFn(), k)
+			}).To(Panic())
+		})
 	})
 
 	Context("Verifiable shares", func() {
@@ -608,6 +860,26 @@ var _ = Describe("Verifiable secret sharing", func() {
 			Expect(m).To(Equal(0))
 			Expect(VerifiableSharesAreEq(shares1, shares2)).To(BeTrue())
 		})
+
+		It("should open to the secret and the decommitment at zero", func() {
+			n := maxN
+			indices := RandomIndices(n)
+			vshares := make(VerifiableShares, n)
+			for i := 0; i < 20; i++ {
+				k := RandRange(1, n)
+				c := NewCommitmentWithCapacity(k)
+				secret := secp256k1.RandomFn()
+				Expect(VShareSecret(&vshares, &c, indices, h, secret, k)).To(Succeed())
+
+				opened, decommitment := OpenVerifiable(vshares[n-k:])
+				Expect(opened.Eq(&secret)).To(BeTrue())
+				var expected, hPow secp256k1.Point
+				expected.BaseExp(&opened)
+				hPow.Scale(&h, &decommitment)
+				expected.Add(&expected, &hPow)
+				Expect(expected.Eq(&c[0])).To(BeTrue())
+			}
+		})
 	})
 
 	Context("Constants", func() {
@@ -617,38 +889,3 @@ var _ = Describe("Verifiable secret sharing", func() {
 		})
 	})
 })
-
-func BenchmarkVSShare(b *testing.B) {
-	n := 100
-	k := 33
-	h := secp256k1.RandomPoint()
-
-	indices := RandomIndices(n)
-	vshares := make(VerifiableShares, n)
-	c := NewCommitmentWithCapacity(n)
-	secret := secp256k1.RandomFn()
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = VShareSecret(&vshares, &c, indices, h, secret, k)
-	}
-}
-
-func BenchmarkVSSVerify(b *testing.B) {
-	n := 100
-	k := 33
-	h := secp256k1.RandomPoint()
-
-	indices := RandomIndices(n)
-	vshares := make(VerifiableShares, n)
-	c := NewCommitmentWithCapacity(n)
-	secret := secp256k1.RandomFn()
-	_ = VShareSecret(&vshares, &c, indices, h, secret, k)
-	ind := rand.Intn(n)
-	share := vshares[ind]
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		IsValid(h, &c, &share)
-	}
-}
@@ -0,0 +1,68 @@
+package shamir
+
+import (
+	"github.com/renproject/secp256k1"
+)
+
+// Zeroization
+//
+// The functions in this file overwrite the memory that holds secret values,
+// so that services managing key material can scrub shares once they are no
+// longer needed. Only the memory that is referenced at the time of the call
+// is overwritten. In particular:
+//	- Values that have been copied (e.g. by passing a Share by value,
+//		appending to a slice that was then reallocated, or marshalling) are
+//		distinct copies and need to be zeroed separately.
+//	- The Go runtime may copy goroutine stacks when they grow, which can leave
+//		stale copies of stack allocated values in memory that is no longer
+//		reachable. Keeping secrets in heap allocated slices avoids this, as
+//		the garbage collector does not move heap objects.
+//	- Memory that has been released to the garbage collector is not zeroed.
+//		Zero values before dropping the last reference to them.
+
+// Wipe overwrites each of the given field elements with zero.
+func Wipe(xs ...*secp256k1.Fn) {
+	for _, x := range xs {
+		x.Clear()
+	}
+}
+
+// WipeFns overwrites each element of the given slice with zero. The entire
+// capacity of the slice is overwritten, not just its length.
+func WipeFns(xs []secp256k1.Fn) {
+	xs = xs[:cap(xs)]
+	for i := range xs {
+		xs[i].Clear()
+	}
+}
+
+// Zero overwrites the index and value of the share with zero.
+func (s *Share) Zero() {
+	s.Index.Clear()
+	s.Value.Clear()
+}
+
+// Zero overwrites every share in the slice with zero. The entire capacity of
+// the slice is overwritten, not just its length.
+func (shares Shares) Zero() {
+	shares = shares[:cap(shares)]
+	for i := range shares {
+		shares[i].Zero()
+	}
+}
+
+// Zero overwrites the share and decommitment of the verifiable share with
+// zero.
+func (vs *VerifiableShare) Zero() {
+	vs.Share.Zero()
+	vs.Decommitment.Clear()
+}
+
+// Zero overwrites every verifiable share in the slice with zero. The entire
+// capacity of the slice is overwritten, not just its length.
+func (vshares VerifiableShares) Zero() {
+	vshares = vshares[:cap(vshares)]
+	for i := range vshares {
+		vshares[i].Zero()
+	}
+}
@@ -0,0 +1,60 @@
+package shamir_test
+
+import (
+	"github.com/renproject/secp256k1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/renproject/shamir"
+	. "github.com/renproject/shamir/shamirutil"
+)
+
+var _ = Describe("Zeroization", func() {
+	n, k := 10, 4
+	h := secp256k1.RandomPoint()
+
+	It("should zero field elements", func() {
+		x, y := secp256k1.RandomFn(), secp256k1.RandomFn()
+		Wipe(&x, &y)
+		Expect(x.IsZero()).To(BeTrue())
+		Expect(y.IsZero()).To(BeTrue())
+
+		xs := make([]secp256k1.Fn, 2, 5)
+		xs = xs[:5]
+		for i := range xs {
+			xs[i] = secp256k1.RandomFn()
+		}
+		WipeFns(xs[:2])
+		for _, x := range xs {
+			Expect(x.IsZero()).To(BeTrue())
+		}
+	})
+
+	It("should zero shares", func() {
+		shares := make(Shares, n)
+		Expect(ShareSecret(&shares, RandomIndices(n), secp256k1.RandomFn(), k)).To(Succeed())
+
+		shares[0].Zero()
+		Expect(shares[0].Index.IsZero()).To(BeTrue())
+		Expect(shares[0].Value.IsZero()).To(BeTrue())
+
+		shares[:1].Zero()
+		for _, share := range shares {
+			Expect(share.Index.IsZero()).To(BeTrue())
+			Expect(share.Value.IsZero()).To(BeTrue())
+		}
+	})
+
+	It("should zero verifiable shares", func() {
+		vshares := make(VerifiableShares, n)
+		c := NewCommitmentWithCapacity(k)
+		Expect(VShareSecret(&vshares, &c, RandomIndices(n), h, secp256k1.RandomFn(), k)).To(Succeed())
+
+		vshares.Zero()
+		for _, vshare := range vshares {
+			Expect(vshare.Share.Index.IsZero()).To(BeTrue())
+			Expect(vshare.Share.Value.IsZero()).To(BeTrue())
+			Expect(vshare.Decommitment.IsZero()).To(BeTrue())
+		}
+	})
+})